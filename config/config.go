@@ -58,6 +58,8 @@ const (
 	DefStatusPort = 10080
 	// DefHost is the default host of TiDB
 	DefHost = "0.0.0.0"
+	// DefListenerName is the name of the listener on host and port in metrics.
+	DefListenerName = "default"
 	// DefStatusHost is the default status host of TiDB
 	DefStatusHost = "0.0.0.0"
 	// DefTableColumnCountLimit is limit of the number of columns in a table
@@ -127,6 +129,9 @@ type Config struct {
 	IndexLimit                 int                `toml:"index-limit" json:"index-limit"`
 	TableColumnCountLimit      uint32             `toml:"table-column-count-limit" json:"table-column-count-limit"`
 	GracefulWaitBeforeShutdown int                `toml:"graceful-wait-before-shutdown" json:"graceful-wait-before-shutdown"`
+	// GracefulDrainTimeout is the number of seconds that a draining server waits for the transactions to finish,
+	// the remaining connections are killed after it. 0 means waiting until all the transactions finish.
+	GracefulDrainTimeout int `toml:"graceful-drain-timeout" json:"graceful-drain-timeout"`
 	// AlterPrimaryKey is used to control alter primary key feature.
 	AlterPrimaryKey bool `toml:"alter-primary-key" json:"alter-primary-key"`
 	// TreatOldVersionUTF8AsUTF8MB4 is use to treat old version table/column UTF8 charset as UTF8MB4. This is for compatibility.
@@ -146,6 +151,8 @@ type Config struct {
 	IsolationRead IsolationRead `toml:"isolation-read" json:"isolation-read"`
 	// MaxServerConnections is the maximum permitted number of simultaneous client connections.
 	MaxServerConnections uint32 `toml:"max-server-connections" json:"max-server-connections"`
+	// Listeners are the additional MySQL protocol listeners, e.g. a dedicated port for the administrators.
+	Listeners []Listener `toml:"listener" json:"listener"`
 	// NewCollationsEnabledOnFirstBootstrap indicates if the new collations are enabled, it effects only when a TiDB cluster bootstrapped on the first time.
 	NewCollationsEnabledOnFirstBootstrap bool `toml:"new_collations_enabled_on_first_bootstrap" json:"new_collations_enabled_on_first_bootstrap"`
 	// Experimental contains parameters for experimental features.
@@ -378,6 +385,10 @@ type Security struct {
 	MinTLSVersion   string `toml:"tls-version" json:"tls-version"`
 	RSAKeySize      int    `toml:"rsa-key-size" json:"rsa-key-size"`
 	SecureBootstrap bool   `toml:"secure-bootstrap" json:"secure-bootstrap"`
+	// SessionTokenSigningCert and SessionTokenSigningKey sign and verify the session states migrated
+	// between TiDB instances, all the instances of a cluster should use the same certificate.
+	SessionTokenSigningCert string `toml:"session-token-signing-cert" json:"session-token-signing-cert"`
+	SessionTokenSigningKey  string `toml:"session-token-signing-key" json:"session-token-signing-key"`
 }
 
 // The ErrConfigValidationFailed error is used so that external callers can do a type assertion
@@ -492,6 +503,29 @@ type ProxyProtocol struct {
 	HeaderTimeout uint `toml:"header-timeout" json:"header-timeout"`
 }
 
+// Listener is the config of an additional MySQL protocol listener. The connections of a listener
+// have their own connection and token budgets, which are not shared with `max-server-connections`
+// and `token-limit`, so that the listener can be reserved for some users, e.g. the administrators.
+type Listener struct {
+	// Name identifies the listener in logs and metrics.
+	Name string `toml:"name" json:"name"`
+	// Host is the bind address of the listener, the host of the server is used if it is empty.
+	Host string `toml:"host" json:"host"`
+	Port uint   `toml:"port" json:"port"`
+	// RequireSecureTransport requires the clients of the listener to connect with TLS.
+	RequireSecureTransport bool `toml:"require-secure-transport" json:"require-secure-transport"`
+	// AllowedUsers is the list of users allowed to connect to the listener, empty means all users.
+	AllowedUsers []string `toml:"allowed-users" json:"allowed-users"`
+	// RequiredPrivilege is the dynamic privilege a user must have to connect to the listener,
+	// e.g. SERVICE_CONNECTION_ADMIN. Empty means no privilege is required.
+	RequiredPrivilege string `toml:"required-privilege" json:"required-privilege"`
+	// TokenLimit is the number of sessions of the listener that can execute requests concurrently,
+	// 0 means the same as `token-limit`.
+	TokenLimit uint `toml:"token-limit" json:"token-limit"`
+	// MaxConnections is the maximum number of simultaneous connections of the listener, 0 means unlimited.
+	MaxConnections uint32 `toml:"max-connections" json:"max-connections"`
+}
+
 // Binlog is the config for binlog.
 type Binlog struct {
 	Enable bool `toml:"enable" json:"enable"`
@@ -604,6 +638,7 @@ var defaultConf = Config{
 	TxnLocalLatches:              defTiKVCfg.TxnLocalLatches,
 	LowerCaseTableNames:          2,
 	GracefulWaitBeforeShutdown:   0,
+	GracefulDrainTimeout:         60,
 	ServerVersion:                "",
 	Log: Log{
 		Level:               "info",
@@ -895,6 +930,22 @@ func (c *Config) Valid() error {
 		return fmt.Errorf("lower-case-table-names should be 0 or 1 or 2")
 	}
 
+	listenerNames := make(map[string]struct{}, len(c.Listeners))
+	for i := range c.Listeners {
+		l := &c.Listeners[i]
+		if l.Name == "" {
+			return fmt.Errorf("the name of listener %d should not be empty", i)
+		}
+		if _, ok := listenerNames[l.Name]; ok || l.Name == DefListenerName {
+			return fmt.Errorf("duplicate listener name %s", l.Name)
+		}
+		listenerNames[l.Name] = struct{}{}
+		if l.Port == 0 || l.Port == c.Port || l.Port == c.Status.StatusPort {
+			return fmt.Errorf("invalid port %d of listener %s", l.Port, l.Name)
+		}
+		l.RequiredPrivilege = strings.ToUpper(l.RequiredPrivilege)
+	}
+
 	// txn-local-latches
 	if err := c.TxnLocalLatches.Valid(); err != nil {
 		return err
@@ -545,6 +545,55 @@ func TestTableColumnCountLimit(t *testing.T) {
 	checkValid(DefMaxOfTableColumnCountLimit+1, false)
 }
 
+func TestListenerValid(t *testing.T) {
+	t.Parallel()
+
+	conf := NewConfig()
+	_, err := toml.Decode(`
+[[listener]]
+name = "admin"
+host = "127.0.0.1"
+port = 4001
+required-privilege = "service_connection_admin"
+max-connections = 10
+
+[[listener]]
+name = "app"
+port = 4002
+allowed-users = ["app"]
+require-secure-transport = true
+token-limit = 100
+`, conf)
+	require.NoError(t, err)
+	require.NoError(t, conf.Valid())
+	require.Len(t, conf.Listeners, 2)
+	require.Equal(t, Listener{
+		Name:              "admin",
+		Host:              "127.0.0.1",
+		Port:              4001,
+		RequiredPrivilege: "SERVICE_CONNECTION_ADMIN",
+		MaxConnections:    10,
+	}, conf.Listeners[0])
+	require.Equal(t, Listener{
+		Name:                   "app",
+		Port:                   4002,
+		AllowedUsers:           []string{"app"},
+		RequireSecureTransport: true,
+		TokenLimit:             100,
+	}, conf.Listeners[1])
+
+	checkInvalid := func(l Listener) {
+		conf.Listeners = []Listener{{Name: "admin", Port: 4001}, l}
+		require.Error(t, conf.Valid())
+	}
+	checkInvalid(Listener{Port: 4002})
+	checkInvalid(Listener{Name: "admin", Port: 4002})
+	checkInvalid(Listener{Name: DefListenerName, Port: 4002})
+	checkInvalid(Listener{Name: "app"})
+	checkInvalid(Listener{Name: "app", Port: DefPort})
+	checkInvalid(Listener{Name: "app", Port: DefStatusPort})
+}
+
 func TestEncodeDefTempStorageDir(t *testing.T) {
 	t.Parallel()
 
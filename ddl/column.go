@@ -81,6 +81,70 @@ func adjustColumnInfoInAddColumn(tblInfo *model.TableInfo, offset int) {
 	tblInfo.Columns = newCols
 }
 
+// locateOffsetToMove returns the offset that the column at currentOffset should be moved to according to pos.
+func locateOffsetToMove(currentOffset int, pos *ast.ColumnPosition, tblInfo *model.TableInfo) (int, error) {
+	if pos == nil {
+		return currentOffset, nil
+	}
+	switch pos.Tp {
+	case ast.ColumnPositionFirst:
+		return 0, nil
+	case ast.ColumnPositionAfter:
+		c := model.FindColumnInfo(tblInfo.Columns, pos.RelativeColumn.Name.L)
+		if c == nil || c.State != model.StatePublic {
+			return 0, infoschema.ErrColumnNotExists.GenWithStackByArgs(pos.RelativeColumn, tblInfo.Name)
+		}
+		if currentOffset <= c.Offset {
+			return c.Offset, nil
+		}
+		return c.Offset + 1, nil
+	default:
+		return currentOffset, nil
+	}
+}
+
+// moveColumnInfo moves the column at offset from to offset to, and updates the offsets of the
+// columns in between as well as the index columns and the changing columns referring to them.
+func moveColumnInfo(tblInfo *model.TableInfo, from, to int) {
+	if from == to {
+		return
+	}
+	cols := tblInfo.Columns
+	src := cols[from]
+	offsetChanged := make(map[int]int)
+	if from < to {
+		for i := from; i < to; i++ {
+			cols[i] = cols[i+1]
+			offsetChanged[cols[i].Offset] = i
+			cols[i].Offset = i
+		}
+	} else {
+		for i := from; i > to; i-- {
+			cols[i] = cols[i-1]
+			offsetChanged[cols[i].Offset] = i
+			cols[i].Offset = i
+		}
+	}
+	cols[to] = src
+	offsetChanged[from] = to
+	src.Offset = to
+	for _, idx := range tblInfo.Indices {
+		for _, col := range idx.Columns {
+			if newOffset, ok := offsetChanged[col.Offset]; ok {
+				col.Offset = newOffset
+			}
+		}
+	}
+	for _, col := range cols {
+		if col.ChangeStateInfo == nil {
+			continue
+		}
+		if newOffset, ok := offsetChanged[col.ChangeStateInfo.DependencyColumnOffset]; ok {
+			col.ChangeStateInfo.DependencyColumnOffset = newOffset
+		}
+	}
+}
+
 // adjustColumnInfoInDropColumn is used to set the correct position of column info when dropping column.
 // 1. The offset of column should to be set to the last of the columns.
 // 2. The dropped column is moved to the end of tblInfo.Columns, due to it was not public any more.
@@ -145,19 +209,20 @@ func createColumnInfo(tblInfo *model.TableInfo, colInfo *model.ColumnInfo, pos *
 	return colInfo, pos, offset, nil
 }
 
-func checkAddColumn(t *meta.Meta, job *model.Job) (*model.TableInfo, *model.ColumnInfo, *model.ColumnInfo, *ast.ColumnPosition, int, error) {
+func checkAddColumn(t *meta.Meta, job *model.Job) (*model.TableInfo, *model.ColumnInfo, *model.ColumnInfo, *ast.ColumnPosition, int, []*model.ConstraintInfo, error) {
 	schemaID := job.SchemaID
 	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, schemaID)
 	if err != nil {
-		return nil, nil, nil, nil, 0, errors.Trace(err)
+		return nil, nil, nil, nil, 0, nil, errors.Trace(err)
 	}
 	col := &model.ColumnInfo{}
 	pos := &ast.ColumnPosition{}
 	offset := 0
-	err = job.DecodeArgs(col, pos, &offset)
+	var constraintInfos []*model.ConstraintInfo
+	err = job.DecodeArgs(col, pos, &offset, &constraintInfos)
 	if err != nil {
 		job.State = model.JobStateCancelled
-		return nil, nil, nil, nil, 0, errors.Trace(err)
+		return nil, nil, nil, nil, 0, nil, errors.Trace(err)
 	}
 
 	columnInfo := model.FindColumnInfo(tblInfo.Columns, col.Name.L)
@@ -165,10 +230,10 @@ func checkAddColumn(t *meta.Meta, job *model.Job) (*model.TableInfo, *model.Colu
 		if columnInfo.State == model.StatePublic {
 			// We already have a column with the same column name.
 			job.State = model.JobStateCancelled
-			return nil, nil, nil, nil, 0, infoschema.ErrColumnExists.GenWithStackByArgs(col.Name)
+			return nil, nil, nil, nil, 0, nil, infoschema.ErrColumnExists.GenWithStackByArgs(col.Name)
 		}
 	}
-	return tblInfo, columnInfo, col, pos, offset, nil
+	return tblInfo, columnInfo, col, pos, offset, constraintInfos, nil
 }
 
 func onAddColumn(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error) {
@@ -187,11 +252,15 @@ func onAddColumn(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error)
 		}
 	})
 
-	tblInfo, columnInfo, col, pos, offset, err := checkAddColumn(t, job)
+	tblInfo, columnInfo, col, pos, offset, constraintInfos, err := checkAddColumn(t, job)
 	if err != nil {
 		return ver, errors.Trace(err)
 	}
 	if columnInfo == nil {
+		if err = checkAddColumnCheckConstraints(tblInfo, constraintInfos); err != nil {
+			job.State = model.JobStateCancelled
+			return ver, errors.Trace(err)
+		}
 		columnInfo, _, offset, err = createColumnInfo(tblInfo, col, pos)
 		if err != nil {
 			job.State = model.JobStateCancelled
@@ -200,7 +269,7 @@ func onAddColumn(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error)
 		logutil.BgLogger().Info("[ddl] run add column job", zap.String("job", job.String()), zap.Reflect("columnInfo", *columnInfo), zap.Int("offset", offset))
 		// Set offset arg to job.
 		if offset != 0 {
-			job.Args = []interface{}{columnInfo, pos, offset}
+			job.Args = []interface{}{columnInfo, pos, offset, constraintInfos}
 		}
 		if err = checkAddColumnTooManyColumns(len(tblInfo.Columns)); err != nil {
 			job.State = model.JobStateCancelled
@@ -236,11 +305,24 @@ func onAddColumn(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error)
 		}
 		// Update the job state when all affairs done.
 		job.SchemaState = model.StateWriteReorganization
+		job.MarkNonRevertible()
 	case model.StateWriteReorganization:
 		// reorganization -> public
 		// Adjust table column offset.
-		adjustColumnInfoInAddColumn(tblInfo, offset)
+		if job.MultiSchemaInfo != nil {
+			// The other sub-jobs of the multi-schema change may have appended columns after
+			// this one, so the offset is located again.
+			offset, err = locateOffsetToMove(columnInfo.Offset, pos, tblInfo)
+			if err != nil {
+				return ver, errors.Trace(err)
+			}
+			moveColumnInfo(tblInfo, columnInfo.Offset, offset)
+		} else {
+			adjustColumnInfoInAddColumn(tblInfo, offset)
+		}
 		columnInfo.State = model.StatePublic
+		// The check constraints only refer to the new column, they take effect with it.
+		addColumnCheckConstraints(tblInfo, constraintInfos)
 		ver, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != columnInfo.State)
 		if err != nil {
 			return ver, errors.Trace(err)
@@ -256,20 +338,21 @@ func onAddColumn(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error)
 	return ver, errors.Trace(err)
 }
 
-func checkAddColumns(t *meta.Meta, job *model.Job) (*model.TableInfo, []*model.ColumnInfo, []*model.ColumnInfo, []*ast.ColumnPosition, []int, []bool, error) {
+func checkAddColumns(t *meta.Meta, job *model.Job) (*model.TableInfo, []*model.ColumnInfo, []*model.ColumnInfo, []*ast.ColumnPosition, []int, []bool, []*model.ConstraintInfo, error) {
 	schemaID := job.SchemaID
 	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, schemaID)
 	if err != nil {
-		return nil, nil, nil, nil, nil, nil, errors.Trace(err)
+		return nil, nil, nil, nil, nil, nil, nil, errors.Trace(err)
 	}
 	columns := []*model.ColumnInfo{}
 	positions := []*ast.ColumnPosition{}
 	offsets := []int{}
 	ifNotExists := []bool{}
-	err = job.DecodeArgs(&columns, &positions, &offsets, &ifNotExists)
+	var constraintInfos []*model.ConstraintInfo
+	err = job.DecodeArgs(&columns, &positions, &offsets, &ifNotExists, &constraintInfos)
 	if err != nil {
 		job.State = model.JobStateCancelled
-		return nil, nil, nil, nil, nil, nil, errors.Trace(err)
+		return nil, nil, nil, nil, nil, nil, nil, errors.Trace(err)
 	}
 
 	columnInfos := make([]*model.ColumnInfo, 0, len(columns))
@@ -288,7 +371,7 @@ func checkAddColumns(t *meta.Meta, job *model.Job) (*model.TableInfo, []*model.C
 					continue
 				}
 				job.State = model.JobStateCancelled
-				return nil, nil, nil, nil, nil, nil, infoschema.ErrColumnExists.GenWithStackByArgs(col.Name)
+				return nil, nil, nil, nil, nil, nil, nil, infoschema.ErrColumnExists.GenWithStackByArgs(col.Name)
 			}
 			columnInfos = append(columnInfos, columnInfo)
 		}
@@ -297,7 +380,7 @@ func checkAddColumns(t *meta.Meta, job *model.Job) (*model.TableInfo, []*model.C
 		newOffsets = append(newOffsets, offsets[i])
 		newIfNotExists = append(newIfNotExists, ifNotExists[i])
 	}
-	return tblInfo, columnInfos, newColumns, newPositions, newOffsets, newIfNotExists, nil
+	return tblInfo, columnInfos, newColumns, newPositions, newOffsets, newIfNotExists, constraintInfos, nil
 }
 
 func setColumnsState(columnInfos []*model.ColumnInfo, state model.SchemaState) {
@@ -328,7 +411,7 @@ func onAddColumns(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error
 		}
 	})
 
-	tblInfo, columnInfos, columns, positions, offsets, ifNotExists, err := checkAddColumns(t, job)
+	tblInfo, columnInfos, columns, positions, offsets, ifNotExists, constraintInfos, err := checkAddColumns(t, job)
 	if err != nil {
 		return ver, errors.Trace(err)
 	}
@@ -337,6 +420,12 @@ func onAddColumns(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error
 			job.State = model.JobStateCancelled
 			return ver, nil
 		}
+		// The constraints of the columns which are skipped for existing are not added.
+		constraintInfos = constraintsOnColumns(constraintInfos, columns)
+		if err = checkAddColumnCheckConstraints(tblInfo, constraintInfos); err != nil {
+			job.State = model.JobStateCancelled
+			return ver, errors.Trace(err)
+		}
 		for i := range columns {
 			columnInfo, pos, offset, err := createColumnInfo(tblInfo, columns[i], positions[i])
 			if err != nil {
@@ -353,7 +442,7 @@ func onAddColumns(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error
 			columnInfos = append(columnInfos, columnInfo)
 		}
 		// Set arg to job.
-		job.Args = []interface{}{columnInfos, positions, offsets, ifNotExists}
+		job.Args = []interface{}{columnInfos, positions, offsets, ifNotExists, constraintInfos}
 	}
 
 	originalState := columnInfos[0].State
@@ -405,6 +494,7 @@ func onAddColumns(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error
 			adjustColumnInfoInAddColumn(tblInfo, offsets[i])
 		}
 		setColumnsState(columnInfos, model.StatePublic)
+		addColumnCheckConstraints(tblInfo, constraintInfos)
 		ver, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != columnInfos[0].State)
 		if err != nil {
 			return ver, errors.Trace(err)
@@ -545,6 +635,8 @@ func checkDropColumns(t *meta.Meta, job *model.Job) (*model.TableInfo, []*model.
 func checkDropColumnForStatePublic(tblInfo *model.TableInfo, colInfo *model.ColumnInfo) (err error) {
 	// Set this column's offset to the last and reset all following columns' offsets.
 	adjustColumnInfoInDropColumn(tblInfo, colInfo.Offset)
+	// The check constraints that only depend on this column are dropped with it.
+	removeCheckConstraintsOnColumn(tblInfo, colInfo.Name)
 	// When the dropping column has not-null flag and it hasn't the default value, we can backfill the column value like "add column".
 	// NOTE: If the state of StateWriteOnly can be rollbacked, we'd better reconsider the original default value.
 	// And we need consider the column without not-null flag.
@@ -573,6 +665,12 @@ func onDropColumn(t *meta.Meta, job *model.Job) (ver int64, _ error) {
 	originalState := colInfo.State
 	switch colInfo.State {
 	case model.StatePublic:
+		if job.MultiSchemaInfo != nil && job.MultiSchemaInfo.Revertible {
+			// Dropping a column can't be rolled back, start it after all the sub-jobs
+			// of the multi-schema change become non-revertible.
+			job.MarkNonRevertible()
+			return ver, nil
+		}
 		// public -> write only
 		colInfo.State = model.StateWriteOnly
 		setIndicesState(idxInfos, model.StateWriteOnly)
@@ -927,13 +1025,59 @@ func (w *worker) onModifyColumn(d *ddlCtx, t *meta.Meta, job *model.Job) (ver in
 		}
 		tblInfo.Indices = append(tblInfo.Indices, jobParam.changingIdxs...)
 	} else {
-		tblInfo.Columns[len(tblInfo.Columns)-1] = jobParam.changingCol
-		copy(tblInfo.Indices[len(tblInfo.Indices)-len(jobParam.changingIdxs):], jobParam.changingIdxs)
+		replaceChangingColumnAndIndexes(tblInfo, jobParam.changingCol, jobParam.changingIdxs)
 	}
 
 	return w.doModifyColumnTypeWithData(d, t, job, dbInfo, tblInfo, jobParam.changingCol, oldCol, jobParam.newCol.Name, jobParam.pos, jobParam.changingIdxs)
 }
 
+// replaceChangingColumnAndIndexes puts the changing column and indexes of the job arguments back to the table info.
+// They are located by IDs since the other sub-jobs of a multi-schema change may append or move columns and indexes,
+// and the offsets in the table info are kept.
+func replaceChangingColumnAndIndexes(tblInfo *model.TableInfo, changingCol *model.ColumnInfo, changingIdxs []*model.IndexInfo) {
+	for i, col := range tblInfo.Columns {
+		if col.ID == changingCol.ID {
+			changingCol.Offset = col.Offset
+			changingCol.ChangeStateInfo = col.ChangeStateInfo
+			tblInfo.Columns[i] = changingCol
+			break
+		}
+	}
+	for _, cIdx := range changingIdxs {
+		for i, idx := range tblInfo.Indices {
+			if idx.ID == cIdx.ID {
+				for j, idxCol := range idx.Columns {
+					cIdx.Columns[j].Offset = idxCol.Offset
+				}
+				tblInfo.Indices[i] = cIdx
+				break
+			}
+		}
+	}
+}
+
+// removeChangingColumnAndIndexes removes the changing column and indexes from the table info.
+func removeChangingColumnAndIndexes(tblInfo *model.TableInfo, changingCol *model.ColumnInfo, changingIdxs []*model.IndexInfo) {
+	for i, col := range tblInfo.Columns {
+		if col.ID == changingCol.ID {
+			adjustColumnInfoInDropColumn(tblInfo, i)
+			tblInfo.Columns = tblInfo.Columns[:len(tblInfo.Columns)-1]
+			break
+		}
+	}
+	changingIdxIDs := make(map[int64]struct{}, len(changingIdxs))
+	for _, idx := range changingIdxs {
+		changingIdxIDs[idx.ID] = struct{}{}
+	}
+	indices := make([]*model.IndexInfo, 0, len(tblInfo.Indices))
+	for _, idx := range tblInfo.Indices {
+		if _, ok := changingIdxIDs[idx.ID]; !ok {
+			indices = append(indices, idx)
+		}
+	}
+	tblInfo.Indices = indices
+}
+
 // rollbackModifyColumnJobWithData is used to rollback modify-column job which need to reorg the data.
 func rollbackModifyColumnJobWithData(t *meta.Meta, tblInfo *model.TableInfo, job *model.Job, oldCol *model.ColumnInfo, jobParam *modifyColumnJobParameter) (ver int64, err error) {
 	// If the not-null change is included, we should clean the flag info in oldCol.
@@ -946,8 +1090,7 @@ func rollbackModifyColumnJobWithData(t *meta.Meta, tblInfo *model.TableInfo, job
 	if jobParam.changingCol != nil {
 		// changingCol isn't nil means the job has been in the mid state. These appended changingCol and changingIndex should
 		// be removed from the tableInfo as well.
-		tblInfo.Columns = tblInfo.Columns[:len(tblInfo.Columns)-1]
-		tblInfo.Indices = tblInfo.Indices[:len(tblInfo.Indices)-len(jobParam.changingIdxs)]
+		removeChangingColumnAndIndexes(tblInfo, jobParam.changingCol, jobParam.changingIdxs)
 	}
 	ver, err = updateVersionAndTableInfoWithCheck(t, job, tblInfo, true)
 	if err != nil {
@@ -1044,59 +1187,25 @@ func (w *worker) doModifyColumnTypeWithData(
 		job.SnapshotVer = 0
 		job.SchemaState = model.StateWriteReorganization
 	case model.StateWriteReorganization:
-		tbl, err := getTable(d.store, dbInfo.ID, tblInfo)
-		if err != nil {
-			return ver, errors.Trace(err)
-		}
-
-		reorgInfo, err := getReorgInfo(d, t, job, tbl, BuildElements(changingCol, changingIdxs))
-		if err != nil || reorgInfo.first {
-			// If we run reorg firstly, we should update the job snapshot version
-			// and then run the reorg next time.
-			return ver, errors.Trace(err)
-		}
-
-		// Inject a failpoint so that we can pause here and do verification on other components.
-		// With a failpoint-enabled version of TiDB, you can trigger this failpoint by the following command:
-		// enable: curl -X PUT -d "pause" "http://127.0.0.1:10080/fail/github.com/pingcap/tidb/ddl/mockDelayInModifyColumnTypeWithData".
-		// disable: curl -X DELETE "http://127.0.0.1:10080/fail/github.com/pingcap/tidb/ddl/mockDelayInModifyColumnTypeWithData"
-		failpoint.Inject("mockDelayInModifyColumnTypeWithData", func() {})
-		err = w.runReorgJob(t, reorgInfo, tbl.Meta(), d.lease, func() (addIndexErr error) {
-			defer util.Recover(metrics.LabelDDL, "onModifyColumn",
-				func() {
-					addIndexErr = errCancelledDDLJob.GenWithStack("modify table `%v` column `%v` panic", tblInfo.Name, oldCol.Name)
-				}, false)
-			// Use old column name to generate less confusing error messages.
-			changingColCpy := changingCol.Clone()
-			changingColCpy.Name = oldCol.Name
-			return w.updateColumnAndIndexes(tbl, oldCol, changingColCpy, changingIdxs, reorgInfo)
-		})
-		if err != nil {
-			if errWaitReorgTimeout.Equal(err) {
-				// If timeout, we should return, check for the owner and re-wait job done.
-				return ver, nil
-			}
-			if kv.IsTxnRetryableError(err) {
-				// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
-				w.reorgCtx.cleanNotifyReorgCancel()
-				return ver, errors.Trace(err)
+		// The reorganization has been done if the sub-job of a multi-schema change is non-revertible.
+		if job.MultiSchemaInfo == nil || job.MultiSchemaInfo.Revertible {
+			var done bool
+			done, ver, err = w.doReorgWorkForModifyColumn(d, t, job, dbInfo, tblInfo, oldCol, changingCol, changingIdxs)
+			if !done {
+				return ver, err
 			}
-			if err1 := t.RemoveDDLReorgHandle(job, reorgInfo.elements); err1 != nil {
-				logutil.BgLogger().Warn("[ddl] run modify column job failed, RemoveDDLReorgHandle failed, can't convert job to rollback",
-					zap.String("job", job.String()), zap.Error(err1))
+			if job.MultiSchemaInfo != nil {
+				// Make the column public after all the sub-jobs of the multi-schema change become non-revertible.
+				job.MarkNonRevertible()
+				// Reset SnapshotVer so that the rollback won't wait for the finished reorganization.
+				job.SnapshotVer = 0
+				return ver, nil
 			}
-			logutil.BgLogger().Warn("[ddl] run modify column job failed, convert job to rollback", zap.String("job", job.String()), zap.Error(err))
-			job.State = model.JobStateRollingback
-			// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
-			w.reorgCtx.cleanNotifyReorgCancel()
-			return ver, errors.Trace(err)
 		}
-		// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
-		w.reorgCtx.cleanNotifyReorgCancel()
 
 		// Remove the old column and indexes. Update the relative column name and index names.
 		oldIdxIDs := make([]int64, 0, len(changingIdxs))
-		tblInfo.Columns = tblInfo.Columns[:len(tblInfo.Columns)-1]
+		removeChangingColumnAndIndexes(tblInfo, changingCol, changingIdxs)
 		for _, cIdx := range changingIdxs {
 			idxName := getChangingIndexOriginName(cIdx)
 			for i, idx := range tblInfo.Indices {
@@ -1118,7 +1227,6 @@ func (w *worker) doModifyColumnTypeWithData(
 		if err = changingCol.SetOriginDefaultValue(nil); err != nil {
 			return ver, errors.Trace(err)
 		}
-		tblInfo.Indices = tblInfo.Indices[:len(tblInfo.Indices)-len(changingIdxs)]
 		// Adjust table column offset.
 		if err = adjustColumnInfoInModifyColumn(job, tblInfo, changingCol, oldCol, pos, changingColumnUniqueName.L); err != nil {
 			// TODO: Do rollback.
@@ -1142,6 +1250,61 @@ func (w *worker) doModifyColumnTypeWithData(
 	return ver, errors.Trace(err)
 }
 
+// doReorgWorkForModifyColumn backfills the changing column and indexes, done is true if the reorganization is finished.
+func (w *worker) doReorgWorkForModifyColumn(d *ddlCtx, t *meta.Meta, job *model.Job, dbInfo *model.DBInfo, tblInfo *model.TableInfo,
+	oldCol, changingCol *model.ColumnInfo, changingIdxs []*model.IndexInfo) (done bool, ver int64, err error) {
+	tbl, err := getTable(d.store, dbInfo.ID, tblInfo)
+	if err != nil {
+		return false, ver, errors.Trace(err)
+	}
+
+	reorgInfo, err := getReorgInfo(d, t, job, tbl, BuildElements(changingCol, changingIdxs))
+	if err != nil || reorgInfo.first {
+		// If we run reorg firstly, we should update the job snapshot version
+		// and then run the reorg next time.
+		return false, ver, errors.Trace(err)
+	}
+
+	// Inject a failpoint so that we can pause here and do verification on other components.
+	// With a failpoint-enabled version of TiDB, you can trigger this failpoint by the following command:
+	// enable: curl -X PUT -d "pause" "http://127.0.0.1:10080/fail/github.com/pingcap/tidb/ddl/mockDelayInModifyColumnTypeWithData".
+	// disable: curl -X DELETE "http://127.0.0.1:10080/fail/github.com/pingcap/tidb/ddl/mockDelayInModifyColumnTypeWithData"
+	failpoint.Inject("mockDelayInModifyColumnTypeWithData", func() {})
+	err = w.runReorgJob(t, reorgInfo, tbl.Meta(), d.lease, func() (addIndexErr error) {
+		defer util.Recover(metrics.LabelDDL, "onModifyColumn",
+			func() {
+				addIndexErr = errCancelledDDLJob.GenWithStack("modify table `%v` column `%v` panic", tblInfo.Name, oldCol.Name)
+			}, false)
+		// Use old column name to generate less confusing error messages.
+		changingColCpy := changingCol.Clone()
+		changingColCpy.Name = oldCol.Name
+		return w.updateColumnAndIndexes(tbl, oldCol, changingColCpy, changingIdxs, reorgInfo)
+	})
+	if err != nil {
+		if errWaitReorgTimeout.Equal(err) {
+			// If timeout, we should return, check for the owner and re-wait job done.
+			return false, ver, nil
+		}
+		if kv.IsTxnRetryableError(err) {
+			// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+			w.reorgCtx.cleanNotifyReorgCancel()
+			return false, ver, errors.Trace(err)
+		}
+		if err1 := t.RemoveDDLReorgHandle(job, reorgInfo.elements); err1 != nil {
+			logutil.BgLogger().Warn("[ddl] run modify column job failed, RemoveDDLReorgHandle failed, can't convert job to rollback",
+				zap.String("job", job.String()), zap.Error(err1))
+		}
+		logutil.BgLogger().Warn("[ddl] run modify column job failed, convert job to rollback", zap.String("job", job.String()), zap.Error(err))
+		job.State = model.JobStateRollingback
+		// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+		w.reorgCtx.cleanNotifyReorgCancel()
+		return false, ver, errors.Trace(err)
+	}
+	// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+	w.reorgCtx.cleanNotifyReorgCancel()
+	return true, ver, nil
+}
+
 // BuildElements is exported for testing.
 func BuildElements(changingCol *model.ColumnInfo, changingIdxs []*model.IndexInfo) []*meta.Element {
 	elements := make([]*meta.Element, 0, len(changingIdxs)+1)
@@ -1496,6 +1659,12 @@ func (w *worker) doModifyColumn(
 		}
 	}
 
+	if job.MultiSchemaInfo != nil && job.MultiSchemaInfo.Revertible {
+		// Modify the column after all the sub-jobs of the multi-schema change become non-revertible.
+		job.MarkNonRevertible()
+		return ver, nil
+	}
+
 	if err := adjustColumnInfoInModifyColumn(job, tblInfo, newCol, oldCol, pos, ""); err != nil {
 		return ver, errors.Trace(err)
 	}
@@ -1709,6 +1878,11 @@ func updateColumnDefaultValue(t *meta.Meta, job *model.Job, newCol *model.Column
 		job.State = model.JobStateCancelled
 		return ver, infoschema.ErrColumnNotExists.GenWithStackByArgs(newCol.Name, tblInfo.Name)
 	}
+	if job.MultiSchemaInfo != nil && job.MultiSchemaInfo.Revertible {
+		// Set the default value after all the sub-jobs of the multi-schema change become non-revertible.
+		job.MarkNonRevertible()
+		return ver, nil
+	}
 	// The newCol's offset may be the value of the old schema version, so we can't use newCol directly.
 	oldCol.DefaultValue = newCol.DefaultValue
 	oldCol.DefaultValueBit = newCol.DefaultValueBit
@@ -0,0 +1,561 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/format"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/sqlexec"
+)
+
+// checkConstraintExprChecker collects the columns and the disallowed parts of a check constraint expression.
+type checkConstraintExprChecker struct {
+	cols            []*ast.ColumnName
+	disallowedFunc  string
+	hasSubqueryLike bool
+	hasVariable     bool
+	hasAggFunc      bool
+	hasRowVal       bool
+	hasWindowFunc   bool
+	otherErr        error
+}
+
+func (c *checkConstraintExprChecker) Enter(inNode ast.Node) (outNode ast.Node, skipChildren bool) {
+	switch node := inNode.(type) {
+	case *ast.ColumnName:
+		c.cols = append(c.cols, node)
+	case *ast.FuncCallExpr:
+		// Blocked functions & non-builtin functions is not allowed
+		_, isFunctionBlocked := expression.IllegalFunctions4GeneratedColumns[node.FnName.L]
+		if isFunctionBlocked || !expression.IsFunctionSupported(node.FnName.L) {
+			c.disallowedFunc = node.FnName.O
+			return inNode, true
+		}
+		if err := expression.VerifyArgsWrapper(node.FnName.L, len(node.Args)); err != nil {
+			c.otherErr = err
+			return inNode, true
+		}
+	case *ast.SubqueryExpr, *ast.ExistsSubqueryExpr, *ast.ValuesExpr, *ast.DefaultExpr:
+		c.hasSubqueryLike = true
+		return inNode, true
+	case *ast.VariableExpr:
+		c.hasVariable = true
+		return inNode, true
+	case *ast.AggregateFuncExpr:
+		c.hasAggFunc = true
+		return inNode, true
+	case *ast.RowExpr:
+		c.hasRowVal = true
+		return inNode, true
+	case *ast.WindowFuncExpr:
+		c.hasWindowFunc = true
+		return inNode, true
+	}
+	return inNode, false
+}
+
+func (c *checkConstraintExprChecker) Leave(inNode ast.Node) (node ast.Node, ok bool) {
+	return inNode, true
+}
+
+// checkTooLongConstraint checks the length of the check constraint name.
+func checkTooLongConstraint(constrName model.CIStr) error {
+	if utf8.RuneCountInString(constrName.L) > mysql.MaxConstraintIdentifierLen {
+		return ErrTooLongIdent.GenWithStackByArgs(constrName)
+	}
+	return nil
+}
+
+// genCheckConstraintName generates the name `<table>_chk_<n>` which is not used by the constraints in the table.
+func genCheckConstraintName(tblInfo *model.TableInfo, usedNames map[string]struct{}) model.CIStr {
+	for i := 1; ; i++ {
+		name := fmt.Sprintf("%s_chk_%d", tblInfo.Name.O, i)
+		if _, ok := usedNames[strings.ToLower(name)]; ok {
+			continue
+		}
+		if tblInfo.FindConstraintInfoByName(name) != nil {
+			continue
+		}
+		return model.NewCIStr(name)
+	}
+}
+
+// buildConstraintInfo validates the check constraint definition and builds the constraint info of it.
+func buildConstraintInfo(ctx sessionctx.Context, tblInfo *model.TableInfo, constrName model.CIStr, constr *ast.Constraint) (*model.ConstraintInfo, error) {
+	if err := checkTooLongConstraint(constrName); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var checker checkConstraintExprChecker
+	constr.Expr.Accept(&checker)
+	switch {
+	case checker.disallowedFunc != "":
+		return nil, ErrCheckConstraintNamedFunctionIsNotAllowed.GenWithStackByArgs(constrName.O, checker.disallowedFunc)
+	case checker.hasSubqueryLike:
+		return nil, ErrCheckConstraintFunctionIsNotAllowed.GenWithStackByArgs(constrName.O)
+	case checker.hasVariable:
+		return nil, ErrCheckConstraintVariables.GenWithStackByArgs(constrName.O)
+	case checker.hasAggFunc:
+		return nil, ErrInvalidGroupFuncUse
+	case checker.hasRowVal:
+		return nil, ErrCheckConstraintRowValueIsNotAllowed.GenWithStackByArgs(constrName.O)
+	case checker.hasWindowFunc:
+		return nil, errWindowInvalidWindowFuncUse.GenWithStackByArgs(constrName.O)
+	case checker.otherErr != nil:
+		return nil, checker.otherErr
+	}
+
+	dependedCols := make([]model.CIStr, 0, len(checker.cols))
+	for _, colName := range checker.cols {
+		// The qualifiers are removed so that the constraint is not affected by renaming the table.
+		if colName.Table.L != "" && colName.Table.L != tblInfo.Name.L {
+			return nil, ErrCheckConstraintRefersUnknownColumn.GenWithStackByArgs(constrName.O, colName.OrigColName())
+		}
+		colName.Schema, colName.Table = model.CIStr{}, model.CIStr{}
+
+		col := model.FindColumnInfo(tblInfo.Columns, colName.Name.L)
+		if col == nil || col.State != model.StatePublic {
+			return nil, ErrCheckConstraintRefersUnknownColumn.GenWithStackByArgs(constrName.O, colName.Name.O)
+		}
+		if mysql.HasAutoIncrementFlag(col.Flag) {
+			return nil, ErrCheckConstraintRefersAutoIncrementColumn.GenWithStackByArgs(constrName.O)
+		}
+		if constr.InColumn && colName.Name.L != strings.ToLower(constr.InColumnName) {
+			return nil, ErrColumnCheckConstraintReferencesOtherColumn.GenWithStackByArgs(constrName.O)
+		}
+		found := false
+		for _, depCol := range dependedCols {
+			if depCol.L == col.Name.L {
+				found = true
+				break
+			}
+		}
+		if !found {
+			dependedCols = append(dependedCols, col.Name)
+		}
+	}
+
+	expr, err := expression.RewriteSimpleExprWithTableInfo(ctx, tblInfo, constr.Expr)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !mysql.HasIsBooleanFlag(expr.GetType().Flag) {
+		return nil, ErrNonBooleanExprForCheckConstraint.GenWithStackByArgs(constrName.O)
+	}
+
+	var sb strings.Builder
+	restoreFlags := format.RestoreStringSingleQuotes | format.RestoreKeyWordLowercase | format.RestoreNameBackQuotes |
+		format.RestoreSpacesAroundBinaryOperation
+	if err := constr.Expr.Restore(format.NewRestoreCtx(restoreFlags, &sb)); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	return &model.ConstraintInfo{
+		Name:           constrName,
+		Table:          tblInfo.Name,
+		ConstraintCols: dependedCols,
+		Enforced:       constr.Enforced,
+		InColumn:       constr.InColumn,
+		ExprString:     sb.String(),
+		State:          model.StateNone,
+	}, nil
+}
+
+// buildCheckConstraints builds the check constraints of the table to create.
+func buildCheckConstraints(ctx sessionctx.Context, tblInfo *model.TableInfo, constrs []*ast.Constraint) error {
+	// The explicit names are reserved before generating the names of the anonymous constraints.
+	usedNames := make(map[string]struct{}, len(constrs))
+	for _, constr := range constrs {
+		if constr.Name == "" {
+			continue
+		}
+		name := strings.ToLower(constr.Name)
+		if _, ok := usedNames[name]; ok {
+			return ErrCheckConstraintDupName.GenWithStackByArgs(constr.Name)
+		}
+		usedNames[name] = struct{}{}
+	}
+	for _, constr := range constrs {
+		constrName := model.NewCIStr(constr.Name)
+		if constr.Name == "" {
+			constrName = genCheckConstraintName(tblInfo, usedNames)
+			usedNames[constrName.L] = struct{}{}
+		}
+		constraintInfo, err := buildConstraintInfo(ctx, tblInfo, constrName, constr)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		tblInfo.MaxConstraintID++
+		constraintInfo.ID = tblInfo.MaxConstraintID
+		constraintInfo.State = model.StatePublic
+		tblInfo.Constraints = append(tblInfo.Constraints, constraintInfo)
+	}
+	return nil
+}
+
+// buildAddColumnCheckConstraints builds the check constraints defined in the options of the columns to add.
+// A column check constraint only refers to its own column, and the existing rows read the origin default value
+// of the new column, so the existing rows are validated by checking the origin default value.
+func buildAddColumnCheckConstraints(ctx sessionctx.Context, tblInfo *model.TableInfo, cols []*table.Column, constrs []*ast.Constraint, usedNames map[string]struct{}) ([]*model.ConstraintInfo, error) {
+	if len(constrs) == 0 {
+		return nil, nil
+	}
+	// The constraints are built and checked as if the new columns were public.
+	newTblInfo := tblInfo.Clone()
+	for _, col := range cols {
+		colInfo := col.ToInfo().Clone()
+		colInfo.State = model.StatePublic
+		colInfo.Offset = len(newTblInfo.Columns)
+		newTblInfo.Columns = append(newTblInfo.Columns, colInfo)
+	}
+	row := make([]types.Datum, len(newTblInfo.Columns))
+	for _, colInfo := range newTblInfo.Columns[len(tblInfo.Columns):] {
+		val, err := table.GetColOriginDefaultValue(ctx, colInfo)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		row[colInfo.Offset] = val
+	}
+
+	for _, constr := range constrs {
+		if constr.Name == "" {
+			continue
+		}
+		name := strings.ToLower(constr.Name)
+		if _, ok := usedNames[name]; ok || tblInfo.FindConstraintInfoByName(name) != nil {
+			return nil, ErrCheckConstraintDupName.GenWithStackByArgs(constr.Name)
+		}
+		usedNames[name] = struct{}{}
+	}
+	constraintInfos := make([]*model.ConstraintInfo, 0, len(constrs))
+	for _, constr := range constrs {
+		constrName := model.NewCIStr(constr.Name)
+		if constr.Name == "" {
+			constrName = genCheckConstraintName(tblInfo, usedNames)
+			usedNames[constrName.L] = struct{}{}
+		}
+		constraintInfo, err := buildConstraintInfo(ctx, newTblInfo, constrName, constr)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if constraintInfo.Enforced {
+			constraint, err := table.ToConstraint(ctx, constraintInfo, newTblInfo)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if err = constraint.CheckRow(ctx, row); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		constraintInfos = append(constraintInfos, constraintInfo)
+	}
+	return constraintInfos, nil
+}
+
+// checkAddColumnCheckConstraints checks whether the names of the check constraints of the new columns are used.
+func checkAddColumnCheckConstraints(tblInfo *model.TableInfo, constraintInfos []*model.ConstraintInfo) error {
+	for _, constraintInfo := range constraintInfos {
+		if tblInfo.FindConstraintInfoByName(constraintInfo.Name.L) != nil {
+			return ErrCheckConstraintDupName.GenWithStackByArgs(constraintInfo.Name.O)
+		}
+	}
+	return nil
+}
+
+// constraintsOnColumns returns the column check constraints which are defined on the columns.
+func constraintsOnColumns(constraintInfos []*model.ConstraintInfo, cols []*model.ColumnInfo) []*model.ConstraintInfo {
+	result := make([]*model.ConstraintInfo, 0, len(constraintInfos))
+	for _, constraintInfo := range constraintInfos {
+		for _, col := range cols {
+			if len(constraintInfo.ConstraintCols) > 0 && constraintInfo.ConstraintCols[0].L == col.Name.L {
+				result = append(result, constraintInfo)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// addColumnCheckConstraints adds the check constraints of the new columns to the table when the columns become public.
+func addColumnCheckConstraints(tblInfo *model.TableInfo, constraintInfos []*model.ConstraintInfo) {
+	for _, constraintInfo := range constraintInfos {
+		constraintInfo = constraintInfo.Clone()
+		tblInfo.MaxConstraintID++
+		constraintInfo.ID = tblInfo.MaxConstraintID
+		constraintInfo.State = model.StatePublic
+		tblInfo.Constraints = append(tblInfo.Constraints, constraintInfo)
+	}
+}
+
+// checkColumnDependedByCheckConstraint checks whether the column can be dropped or renamed.
+// When the column is dropped, the constraints that only depend on it are dropped together.
+func checkColumnDependedByCheckConstraint(tblInfo *model.TableInfo, colName model.CIStr, isRename bool) error {
+	for _, constraintInfo := range tblInfo.Constraints {
+		for _, col := range constraintInfo.ConstraintCols {
+			if col.L != colName.L {
+				continue
+			}
+			if isRename || len(constraintInfo.ConstraintCols) > 1 {
+				return ErrDependentByCheckConstraint.GenWithStackByArgs(constraintInfo.Name.O, colName.O)
+			}
+		}
+	}
+	return nil
+}
+
+// removeCheckConstraintsOnColumn removes the check constraints that depend on the dropping column.
+func removeCheckConstraintsOnColumn(tblInfo *model.TableInfo, colName model.CIStr) {
+	constraints := tblInfo.Constraints[:0]
+	for _, constraintInfo := range tblInfo.Constraints {
+		depended := false
+		for _, col := range constraintInfo.ConstraintCols {
+			if col.L == colName.L {
+				depended = true
+				break
+			}
+		}
+		if !depended {
+			constraints = append(constraints, constraintInfo)
+		}
+	}
+	tblInfo.Constraints = constraints
+}
+
+func onAddCheckConstraint(w *worker, t *meta.Meta, job *model.Job) (ver int64, err error) {
+	dbInfo, err := checkSchemaExistAndCancelNotExistJob(t, job)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+
+	var constraintInfoInJob model.ConstraintInfo
+	if err = job.DecodeArgs(&constraintInfoInJob); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+
+	constraintInfo := tblInfo.FindConstraintInfoByName(constraintInfoInJob.Name.L)
+	if constraintInfo != nil && constraintInfo.State == model.StatePublic {
+		job.State = model.JobStateCancelled
+		return ver, ErrCheckConstraintDupName.GenWithStackByArgs(constraintInfo.Name.O)
+	}
+	if constraintInfo == nil {
+		constraintInfo = constraintInfoInJob.Clone()
+		tblInfo.MaxConstraintID++
+		constraintInfo.ID = tblInfo.MaxConstraintID
+		constraintInfo.State = model.StateNone
+		tblInfo.Constraints = append(tblInfo.Constraints, constraintInfo)
+	}
+
+	originalState := constraintInfo.State
+	switch constraintInfo.State {
+	case model.StateNone:
+		if !constraintInfo.Enforced {
+			// The existing rows are not validated for the constraint which is not enforced.
+			// none -> public
+			constraintInfo.State = model.StatePublic
+			ver, err = updateVersionAndTableInfoWithCheck(t, job, tblInfo, originalState != constraintInfo.State)
+			if err != nil {
+				return ver, errors.Trace(err)
+			}
+			job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+			return ver, nil
+		}
+		// none -> write only
+		constraintInfo.State = model.StateWriteOnly
+		ver, err = updateVersionAndTableInfoWithCheck(t, job, tblInfo, originalState != constraintInfo.State)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		job.SchemaState = model.StateWriteOnly
+	case model.StateWriteOnly:
+		// All the servers check the new rows with the constraint now, so the existing rows are validated.
+		err = w.verifyRemainRecordsForCheckConstraint(dbInfo, tblInfo, constraintInfo)
+		if err != nil {
+			if !table.ErrCheckConstraintViolated.Equal(err) {
+				return ver, errors.Trace(err)
+			}
+			removeCheckConstraint(tblInfo, constraintInfo.Name)
+			ver, err1 := updateVersionAndTableInfo(t, job, tblInfo, true)
+			if err1 != nil {
+				return ver, errors.Trace(err1)
+			}
+			job.FinishTableJob(model.JobStateRollbackDone, model.StateNone, ver, tblInfo)
+			return ver, errors.Trace(err)
+		}
+		// write only -> public
+		constraintInfo.State = model.StatePublic
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != constraintInfo.State)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+	default:
+		err = ErrInvalidDDLState.GenWithStackByArgs("constraint", constraintInfo.State)
+	}
+	return ver, errors.Trace(err)
+}
+
+func onDropCheckConstraint(t *meta.Meta, job *model.Job) (ver int64, _ error) {
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+
+	var constrName model.CIStr
+	if err = job.DecodeArgs(&constrName); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+
+	constraintInfo := tblInfo.FindConstraintInfoByName(constrName.L)
+	if constraintInfo == nil || constraintInfo.State != model.StatePublic {
+		job.State = model.JobStateCancelled
+		return ver, ErrCheckConstraintNotFound.GenWithStackByArgs(constrName.O)
+	}
+
+	// Checking the rows with fewer constraints on the servers with the old schema is harmless,
+	// so the constraint is removed in one step.
+	// public -> none
+	removeCheckConstraint(tblInfo, constrName)
+	ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.FinishTableJob(model.JobStateDone, model.StateNone, ver, tblInfo)
+	return ver, nil
+}
+
+func onAlterCheckConstraint(w *worker, t *meta.Meta, job *model.Job) (ver int64, err error) {
+	dbInfo, err := checkSchemaExistAndCancelNotExistJob(t, job)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+
+	var (
+		constrName model.CIStr
+		enforced   bool
+	)
+	if err = job.DecodeArgs(&constrName, &enforced); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+
+	constraintInfo := tblInfo.FindConstraintInfoByName(constrName.L)
+	if constraintInfo == nil || constraintInfo.State != model.StatePublic {
+		job.State = model.JobStateCancelled
+		return ver, ErrCheckConstraintNotFound.GenWithStackByArgs(constrName.O)
+	}
+
+	if !enforced {
+		// Checking fewer rows is harmless, so NOT ENFORCED takes effect in one step.
+		constraintInfo.Enforced = false
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+		return ver, nil
+	}
+
+	// The constraint keeps public, the job schema state records the progress of enforcing it.
+	switch job.SchemaState {
+	case model.StateNone:
+		// Start checking the new rows with the constraint.
+		constraintInfo.Enforced = true
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		job.SchemaState = model.StateWriteOnly
+	case model.StateWriteOnly:
+		err = w.verifyRemainRecordsForCheckConstraint(dbInfo, tblInfo, constraintInfo)
+		if err != nil {
+			if !table.ErrCheckConstraintViolated.Equal(err) {
+				return ver, errors.Trace(err)
+			}
+			constraintInfo.Enforced = false
+			ver, err1 := updateVersionAndTableInfo(t, job, tblInfo, true)
+			if err1 != nil {
+				return ver, errors.Trace(err1)
+			}
+			job.FinishTableJob(model.JobStateRollbackDone, model.StatePublic, ver, tblInfo)
+			return ver, errors.Trace(err)
+		}
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, false)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+	default:
+		err = ErrInvalidDDLState.GenWithStackByArgs("constraint", job.SchemaState)
+	}
+	return ver, errors.Trace(err)
+}
+
+func removeCheckConstraint(tblInfo *model.TableInfo, constrName model.CIStr) {
+	constraints := tblInfo.Constraints[:0]
+	for _, constraintInfo := range tblInfo.Constraints {
+		if constraintInfo.Name.L != constrName.L {
+			constraints = append(constraints, constraintInfo)
+		}
+	}
+	tblInfo.Constraints = constraints
+}
+
+// verifyRemainRecordsForCheckConstraint checks whether all the existing rows satisfy the check constraint.
+func (w *worker) verifyRemainRecordsForCheckConstraint(dbInfo *model.DBInfo, tblInfo *model.TableInfo, constraintInfo *model.ConstraintInfo) error {
+	// Get sessionctx from context resource pool.
+	sctx, err := w.sessPool.get()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer w.sessPool.put(sctx)
+
+	// The expression string may contain '%', which is escaped for the format of the SQL.
+	sql := "select 1 from %n.%n where not (" + strings.ReplaceAll(constraintInfo.ExprString, "%", "%%") + ") limit 1"
+	exec := sctx.(sqlexec.RestrictedSQLExecutor)
+	stmt, err := exec.ParseWithParams(w.ddlJobCtx, sql, dbInfo.Name.L, tblInfo.Name.L)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	rows, _, err := exec.ExecRestrictedStmt(w.ddlJobCtx, stmt)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(rows) > 0 {
+		return table.ErrCheckConstraintViolated.GenWithStackByArgs(constraintInfo.Name.O)
+	}
+	return nil
+}
@@ -19,6 +19,7 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"strconv"
 	"strings"
 	"sync/atomic"
 	"time"
@@ -50,6 +51,7 @@ import (
 	"github.com/pingcap/tidb/util/israce"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/mock"
+	"github.com/pingcap/tidb/util/sqlexec"
 	"github.com/pingcap/tidb/util/testkit"
 	"go.uber.org/zap"
 )
@@ -1615,6 +1617,119 @@ func (s *testIntegrationSuite5) TestMultiPartitionDropAndTruncate(c *C) {
 	result.Check(testkit.Rows(`2010`))
 }
 
+func (s *testIntegrationSuite5) TestAlterTableReorganizePartition(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t, tl, th")
+	tk.MustExec(`create table t (a int primary key, b varchar(20), key (b))
+	partition by range (a) (
+		partition p0 values less than (10),
+		partition p1 values less than (20),
+		partition p2 values less than (30)
+	);`)
+	tk.MustExec(`insert into t values (1, "a"), (5, "e"), (12, "l"), (15, "o"), (25, "y")`)
+
+	// Split a partition.
+	tk.MustExec(`alter table t reorganize partition p0 into (
+		partition p00 values less than (4),
+		partition p01 values less than (10))`)
+	tk.MustQuery("select partition_name, table_rows from information_schema.partitions where table_schema = 'test' and table_name = 't'").Check(
+		testkit.Rows("p00 0", "p01 0", "p1 0", "p2 0"))
+	tk.MustQuery("select * from t partition (p00)").Check(testkit.Rows("1 a"))
+	tk.MustQuery("select * from t partition (p01)").Check(testkit.Rows("5 e"))
+	tk.MustQuery("select * from t order by a").Check(testkit.Rows("1 a", "5 e", "12 l", "15 o", "25 y"))
+	tk.MustQuery("select a from t use index(b) where b = 'e'").Check(testkit.Rows("5"))
+	tk.MustExec("admin check table t")
+
+	// Merge partitions, the last partition can be extended.
+	tk.MustExec(`alter table t reorganize partition p1, p2 into (
+		partition p1 values less than (40))`)
+	tk.MustQuery("select * from t partition (p1) order by a").Check(testkit.Rows("12 l", "15 o", "25 y"))
+	tk.MustExec(`insert into t values (35, "i")`)
+	tk.MustGetErrCode(`insert into t values (45, "s")`, tmysql.ErrNoPartitionForGivenValue)
+	tk.MustExec("admin check table t")
+
+	// Errors.
+	tk.MustGetErrCode(`alter table t reorganize partition p00, p1 into (
+		partition p2 values less than (40))`, tmysql.ErrConsecutiveReorgPartitions)
+	tk.MustGetErrCode(`alter table t reorganize partition p00 into (
+		partition p2 values less than (5))`, tmysql.ErrReorgOutsideRange)
+	tk.MustGetErrCode(`alter table t reorganize partition p1 into (
+		partition p2 values less than (30))`, tmysql.ErrReorgOutsideRange)
+	tk.MustGetErrCode(`alter table t reorganize partition p3 into (
+		partition p2 values less than (40))`, tmysql.ErrDropPartitionNonExistent)
+	tk.MustGetErrCode(`alter table t reorganize partition p01 into (
+		partition p1 values less than (10))`, tmysql.ErrSameNamePartition)
+
+	// List partition.
+	tk.MustExec("set @@session.tidb_enable_list_partition = ON")
+	tk.MustExec(`create table tl (a int, b int)
+	partition by list (a) (
+		partition p0 values in (1, 2, 3),
+		partition p1 values in (4, 5, 6)
+	);`)
+	tk.MustExec("insert into tl values (1, 1), (2, 2), (4, 4), (6, 6)")
+	tk.MustExec(`alter table tl reorganize partition p0, p1 into (
+		partition p0 values in (1, 4),
+		partition p1 values in (2, 3, 5, 6))`)
+	tk.MustQuery("select * from tl partition (p0) order by a").Check(testkit.Rows("1 1", "4 4"))
+	tk.MustQuery("select * from tl partition (p1) order by a").Check(testkit.Rows("2 2", "6 6"))
+	tk.MustGetErrCode(`alter table tl reorganize partition p0 into (
+		partition p0 values in (1))`, tmysql.ErrNoPartitionForGivenValue)
+	tk.MustQuery("select * from tl order by a").Check(testkit.Rows("1 1", "2 2", "4 4", "6 6"))
+	tk.MustExec("admin check table tl")
+
+	// Hash partition.
+	tk.MustExec("create table th (a int) partition by hash(a) partitions 4")
+	tk.MustGetErrCode(`alter table th reorganize partition p0 into (
+		partition p0)`, tmysql.ErrUnsupportedDDLOperation)
+}
+
+func (s *testIntegrationSuite5) TestAlterTablePartitioning(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int primary key auto_increment, b varchar(20), key (b))")
+	tk.MustExec(`insert into t(b) values ("a"), ("b"), ("c"), ("d"), ("e")`)
+
+	// Partition a non-partitioned table.
+	tk.MustExec(`alter table t partition by range (a) (
+		partition p0 values less than (3),
+		partition p1 values less than (maxvalue))`)
+	tk.MustQuery("select partition_name, partition_method from information_schema.partitions where table_schema = 'test' and table_name = 't'").Check(
+		testkit.Rows("p0 RANGE", "p1 RANGE"))
+	tk.MustQuery("select * from t partition (p0) order by a").Check(testkit.Rows("1 a", "2 b"))
+	tk.MustQuery("select * from t partition (p1) order by a").Check(testkit.Rows("3 c", "4 d", "5 e"))
+	tk.MustQuery("select a from t use index(b) where b = 'd'").Check(testkit.Rows("4"))
+	tk.MustExec("admin check table t")
+
+	// Change the partitioning.
+	tk.MustExec("alter table t partition by hash (a) partitions 2")
+	tk.MustQuery("select * from t partition (p0) order by a").Check(testkit.Rows("2 b", "4 d"))
+	tk.MustQuery("select * from t partition (p1) order by a").Check(testkit.Rows("1 a", "3 c", "5 e"))
+	tk.MustExec("admin check table t")
+
+	// Remove the partitioning.
+	tk.MustExec("alter table t remove partitioning")
+	tk.MustQuery("select count(*) from information_schema.partitions where table_schema = 'test' and table_name = 't' and partition_name is not null").Check(
+		testkit.Rows("0"))
+	createSQL := tk.MustQuery("show create table t").Rows()[0][1].(string)
+	c.Assert(strings.Contains(createSQL, "PARTITION"), IsFalse, Commentf(createSQL))
+	tk.MustExec(`insert into t(b) values ("f")`)
+	tk.MustQuery("select * from t where a < 6 order by a").Check(testkit.Rows("1 a", "2 b", "3 c", "4 d", "5 e"))
+	tk.MustQuery("select count(*) from t where a > 5").Check(testkit.Rows("1"))
+	tk.MustExec("admin check table t")
+
+	// Errors.
+	tk.MustGetErrCode("alter table t remove partitioning", tmysql.ErrPartitionMgmtOnNonpartitioned)
+	tk.MustGetErrCode(`alter table t partition by range columns (b) (
+		partition p0 values less than (maxvalue))`, tmysql.ErrUniqueKeyNeedAllFieldsInPf)
+	tk.MustGetErrCode(`alter table t partition by range (a) (
+		partition p0 values less than (3))`, tmysql.ErrNoPartitionForGivenValue)
+	tk.MustQuery("select count(*) from t").Check(testkit.Rows("6"))
+	tk.MustExec("admin check table t")
+}
+
 func (s *testSerialDBSuite1) TestDropPartitionWithGlobalIndex(c *C) {
 	config.UpdateGlobal(func(conf *config.Config) {
 		conf.EnableGlobalIndex = true
@@ -3067,13 +3182,12 @@ func (s *testIntegrationSuite3) TestPartitionErrorCode(c *C) {
 	_, err = tk.Exec("alter table t_part coalesce partition 4;")
 	c.Assert(ddl.ErrCoalesceOnlyOnHashPartition.Equal(err), IsTrue)
 
-	tk.MustGetErrCode(`alter table t_part reorganize partition p0, p1 into (
-			partition p0 values less than (1980));`, tmysql.ErrUnsupportedDDLOperation)
+	tk.MustGetErrCode(`alter table t_part reorganize partition p0 into (
+			partition p0 values less than (1980));`, tmysql.ErrReorgOutsideRange)
 
 	tk.MustGetErrCode("alter table t_part check partition p0, p1;", tmysql.ErrUnsupportedDDLOperation)
 	tk.MustGetErrCode("alter table t_part optimize partition p0,p1;", tmysql.ErrUnsupportedDDLOperation)
 	tk.MustGetErrCode("alter table t_part rebuild partition p0,p1;", tmysql.ErrUnsupportedDDLOperation)
-	tk.MustGetErrCode("alter table t_part remove partitioning;", tmysql.ErrUnsupportedDDLOperation)
 	tk.MustGetErrCode("alter table t_part repair partition p1;", tmysql.ErrUnsupportedDDLOperation)
 
 	// Reduce the impact on DML when executing partition DDL
@@ -3224,8 +3338,8 @@ func (s *testIntegrationSuite3) TestUnsupportedPartitionManagementDDLs(c *C) {
 		);
 	`)
 
-	_, err := tk.Exec("alter table test_1465 partition by hash(a)")
-	c.Assert(err, ErrorMatches, ".*alter table partition is unsupported")
+	tk.MustGetErrCode("alter table test_1465 partition by linear hash(a) partitions 2", tmysql.ErrUnsupportedDDLOperation)
+	tk.MustGetErrCode("alter table test_1465 partition by key(a) partitions 2", tmysql.ErrUnsupportedDDLOperation)
 }
 
 func (s *testSerialDBSuite1) TestCommitWhenSchemaChange(c *C) {
@@ -3498,6 +3612,152 @@ func (s *testSerialDBSuite1) TestTruncatePartitionMultipleTimes(c *C) {
 	c.Assert(errCount, LessEqual, int32(1))
 }
 
+// runPartitioningChangeWithDML runs alterSQL on test.t, some DML is executed in every schema state of the job.
+func runPartitioningChangeWithDML(c *C, store kv.Storage, tk *testkit.TestKit, alterSQL string) {
+	tk.MustExec("insert into t values (1, 1), (50, 50), (99, 99), (150, 150)")
+
+	tk1 := testkit.NewTestKitWithInit(c, store)
+	dom := domain.GetDomain(tk.Se)
+	originHook := dom.DDL().GetHook()
+	defer dom.DDL().SetHook(originHook)
+	hook := &ddl.TestDDLCallback{}
+	var checkErr error
+	states := make(map[model.SchemaState]struct{})
+	hook.OnJobRunBeforeExported = func(job *model.Job) {
+		if !admin.MayNeedBackfill(job.Type) || checkErr != nil {
+			return
+		}
+		if _, ok := states[job.SchemaState]; ok {
+			return
+		}
+		states[job.SchemaState] = struct{}{}
+		var sqls []string
+		switch job.SchemaState {
+		case model.StateDeleteOnly:
+			sqls = []string{"insert into t values (10, 10)", "delete from t where a = 1"}
+		case model.StateWriteOnly:
+			sqls = []string{"insert into t values (20, 20)", "update t set b = 51 where a = 50"}
+		case model.StateWriteReorganization:
+			sqls = []string{"insert into t values (70, 70)", "update t set a = 30 where a = 10"}
+		case model.StateDeleteReorganization:
+			sqls = []string{"insert into t values (80, 80)", "delete from t where a = 99"}
+		}
+		for _, sql := range sqls {
+			if _, checkErr = tk1.Exec(sql); checkErr != nil {
+				return
+			}
+		}
+	}
+	dom.DDL().SetHook(hook)
+	tk.MustExec(alterSQL)
+	c.Assert(checkErr, IsNil)
+	c.Assert(states, HasLen, 5)
+
+	tk.MustExec("admin check table t")
+	tk.MustQuery("select * from t order by a").Check(testkit.Rows("20 20", "30 10", "50 51", "70 70", "80 80", "150 150"))
+	tk.MustQuery("select a from t use index(b) where b = 10").Check(testkit.Rows("30"))
+}
+
+func (s *testSerialDBSuite1) TestReorganizePartitionWithDML(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists t")
+	tk.MustExec(`create table t (a int primary key, b int, key (b))
+	partition by range (a) (
+		partition p0 values less than (100),
+		partition p1 values less than (200)
+	);`)
+	runPartitioningChangeWithDML(c, s.store, tk, `alter table t reorganize partition p0 into (
+		partition p00 values less than (50),
+		partition p01 values less than (100))`)
+	tk.MustQuery("select * from t partition (p00) order by a").Check(testkit.Rows("20 20", "30 10"))
+	tk.MustQuery("select * from t partition (p01) order by a").Check(testkit.Rows("50 51", "70 70", "80 80"))
+}
+
+func (s *testSerialDBSuite1) TestAlterTablePartitioningWithDML(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int primary key, b int, key (b))")
+	runPartitioningChangeWithDML(c, s.store, tk, "alter table t partition by hash (a) partitions 3")
+	tk.MustQuery("select * from t partition (p0) order by a").Check(testkit.Rows("30 10", "150 150"))
+
+	tk.MustExec("drop table t")
+	tk.MustExec(`create table t (a int primary key, b int, key (b))
+	partition by range (a) (
+		partition p0 values less than (100),
+		partition p1 values less than (200)
+	);`)
+	runPartitioningChangeWithDML(c, s.store, tk, "alter table t remove partitioning")
+	tbl := testGetTableByName(c, tk.Se, "test", "t")
+	c.Assert(tbl.Meta().Partition, IsNil)
+}
+
+func (s *testSerialDBSuite1) TestCancelPartitioningChange(c *C) {
+	tk := testkit.NewTestKitWithInit(c, s.store)
+	tk1 := testkit.NewTestKitWithInit(c, s.store)
+	dom := domain.GetDomain(tk.Se)
+	originHook := dom.DDL().GetHook()
+	defer dom.DDL().SetHook(originHook)
+
+	var checkErr error
+	var cancelState model.SchemaState
+	hook := &ddl.TestDDLCallback{}
+	hook.OnJobUpdatedExported = func(job *model.Job) {
+		if !admin.MayNeedBackfill(job.Type) || job.SchemaState != cancelState || checkErr != nil {
+			return
+		}
+		var res sqlexec.RecordSet
+		res, checkErr = tk1.Exec("admin cancel ddl jobs " + strconv.Itoa(int(job.ID)))
+		if checkErr != nil {
+			return
+		}
+		// drain the result set here, otherwise the cancel action won't take effect immediately.
+		chk := res.NewChunk(nil)
+		if checkErr = res.Next(context.Background(), chk); checkErr != nil {
+			return
+		}
+		checkErr = res.Close()
+	}
+	dom.DDL().SetHook(hook)
+
+	alterSQLs := []string{
+		"alter table t reorganize partition p0, p1 into (partition p0 values less than (200))",
+		"alter table t partition by hash (a) partitions 3",
+		"alter table t remove partitioning",
+	}
+	for _, alterSQL := range alterSQLs {
+		for _, state := range []model.SchemaState{model.StateDeleteOnly, model.StateWriteOnly, model.StateWriteReorganization} {
+			tk.MustExec("drop table if exists t")
+			tk.MustExec(`create table t (a int primary key, b int, key (b))
+			partition by range (a) (
+				partition p0 values less than (100),
+				partition p1 values less than (200)
+			);`)
+			tk.MustExec("insert into t values (1, 1), (50, 50), (150, 150)")
+			cancelState = state
+			tk.MustGetErrCode(alterSQL, errno.ErrCancelledDDLJob)
+			c.Assert(checkErr, IsNil)
+			tk.MustQuery("select partition_name from information_schema.partitions where table_schema = 'test' and table_name = 't'").Check(
+				testkit.Rows("p0", "p1"))
+			tk.MustQuery("select * from t partition (p1)").Check(testkit.Rows("150 150"))
+			tk.MustQuery("select * from t order by a").Check(testkit.Rows("1 1", "50 50", "150 150"))
+			tk.MustExec("admin check table t")
+		}
+	}
+
+	// Cancel partitioning a non-partitioned table.
+	tk.MustExec("drop table t")
+	tk.MustExec("create table t (a int primary key, b int, key (b))")
+	tk.MustExec("insert into t values (1, 1), (50, 50), (150, 150)")
+	cancelState = model.StateWriteReorganization
+	tk.MustGetErrCode("alter table t partition by hash (a) partitions 3", errno.ErrCancelledDDLJob)
+	c.Assert(checkErr, IsNil)
+	tbl := testGetTableByName(c, tk.Se, "test", "t")
+	c.Assert(tbl.Meta().Partition, IsNil)
+	tk.MustQuery("select * from t order by a").Check(testkit.Rows("1 1", "50 50", "150 150"))
+	tk.MustExec("admin check table t")
+	cancelState = model.StateNone
+}
+
 func (s *testSerialDBSuite1) TestAddPartitionReplicaBiggerThanTiFlashStores(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("create database if not exists test_partition2")
@@ -2689,7 +2689,7 @@ func (s *testDBSuite5) TestRenameColumn(c *C) {
 
 	// Test renaming the column with foreign key.
 	tk.MustExec("drop table test_rename_column")
-	tk.MustExec("create table test_rename_column_base (base int)")
+	tk.MustExec("create table test_rename_column_base (base int, key(base))")
 	tk.MustExec("create table test_rename_column (col int, foreign key (col) references test_rename_column_base(base))")
 
 	tk.MustGetErrCode("alter table test_rename_column rename column col to col1", errno.ErrFKIncompatibleColumns)
@@ -3278,7 +3278,7 @@ func (s *testDBSuite2) TestCreateTableWithEnumCol(c *C) {
 func (s *testDBSuite2) TestTableForeignKey(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use test")
-	tk.MustExec("create table t1 (a int, b int);")
+	tk.MustExec("create table t1 (a int, b int, key(b));")
 	// test create table with foreign key.
 	failSQL := "create table t2 (c int, foreign key (a) references t1(a));"
 	tk.MustGetErrCode(failSQL, errno.ErrKeyColumnDoesNotExits)
@@ -3362,14 +3362,14 @@ func (s *testDBSuite8) TestFKOnGeneratedColumns(c *C) {
 	// foreign key constraint can be defined on a stored generated column.
 	tk.MustExec("create table t2 (a int primary key);")
 	tk.MustExec("create table t1 (a int, b int as (a+1) stored, foreign key (b) references t2(a));")
-	tk.MustExec("create table t3 (a int, b int generated always as (a+1) stored);")
+	tk.MustExec("create table t3 (a int, b int generated always as (a+1) stored, key(b));")
 	tk.MustExec("alter table t3 add foreign key (b) references t2(a);")
 	tk.MustExec("drop table t1, t2, t3;")
 
 	// foreign key constraint can reference a stored generated column.
 	tk.MustExec("create table t1 (a int, b int generated always as (a+1) stored primary key);")
 	tk.MustExec("create table t2 (a int, foreign key (a) references t1(b));")
-	tk.MustExec("create table t3 (a int);")
+	tk.MustExec("create table t3 (a int, key(a));")
 	tk.MustExec("alter table t3 add foreign key (a) references t1(b);")
 	tk.MustExec("drop table t1, t2, t3;")
 
@@ -3409,15 +3409,15 @@ func (s *testDBSuite8) TestFKOnGeneratedColumns(c *C) {
 	tk.MustExec("create table t5 (a int, b int generated always as (a % 10) stored, foreign key (b) references t1(a) on delete cascade);")
 	tk.MustExec("create table t6 (a int, b int generated always as (a % 10) stored, foreign key (b) references t1(a) on delete no action);")
 	tk.MustExec("drop table t2,t3,t4,t5,t6;")
-	tk.MustExec("create table t2 (a int, b int generated always as (a % 10) stored);")
+	tk.MustExec("create table t2 (a int, b int generated always as (a % 10) stored, key(b));")
 	tk.MustExec("alter table t2 add foreign key (b) references t1(a) on update restrict;")
-	tk.MustExec("create table t3 (a int, b int generated always as (a % 10) stored);")
+	tk.MustExec("create table t3 (a int, b int generated always as (a % 10) stored, key(b));")
 	tk.MustExec("alter table t3 add foreign key (b) references t1(a) on update no action;")
-	tk.MustExec("create table t4 (a int, b int generated always as (a % 10) stored);")
+	tk.MustExec("create table t4 (a int, b int generated always as (a % 10) stored, key(b));")
 	tk.MustExec("alter table t4 add foreign key (b) references t1(a) on delete restrict;")
-	tk.MustExec("create table t5 (a int, b int generated always as (a % 10) stored);")
+	tk.MustExec("create table t5 (a int, b int generated always as (a % 10) stored, key(b));")
 	tk.MustExec("alter table t5 add foreign key (b) references t1(a) on delete cascade;")
-	tk.MustExec("create table t6 (a int, b int generated always as (a % 10) stored);")
+	tk.MustExec("create table t6 (a int, b int generated always as (a % 10) stored, key(b));")
 	tk.MustExec("alter table t6 add foreign key (b) references t1(a) on delete no action;")
 	tk.MustExec("drop table t1,t2,t3,t4,t5,t6;")
 
@@ -3445,13 +3445,13 @@ func (s *testDBSuite8) TestFKOnGeneratedColumns(c *C) {
 	tk.MustExec("create table t4 (a int, b int generated always as (a % 10) stored, foreign key (a) references t1(a) on delete restrict);")
 	tk.MustExec("create table t5 (a int, b int generated always as (a % 10) stored, foreign key (a) references t1(a) on delete no action);")
 	tk.MustExec("drop table t2,t3,t4,t5")
-	tk.MustExec("create table t2 (a int, b int generated always as (a % 10) stored);")
+	tk.MustExec("create table t2 (a int, b int generated always as (a % 10) stored, key(a));")
 	tk.MustExec("alter table t2 add foreign key (a) references t1(a) on update restrict;")
-	tk.MustExec("create table t3 (a int, b int generated always as (a % 10) stored);")
+	tk.MustExec("create table t3 (a int, b int generated always as (a % 10) stored, key(a));")
 	tk.MustExec("alter table t3 add foreign key (a) references t1(a) on update no action;")
-	tk.MustExec("create table t4 (a int, b int generated always as (a % 10) stored);")
+	tk.MustExec("create table t4 (a int, b int generated always as (a % 10) stored, key(a));")
 	tk.MustExec("alter table t4 add foreign key (a) references t1(a) on delete restrict;")
-	tk.MustExec("create table t5 (a int, b int generated always as (a % 10) stored);")
+	tk.MustExec("create table t5 (a int, b int generated always as (a % 10) stored, key(a));")
 	tk.MustExec("alter table t5 add foreign key (a) references t1(a) on delete no action;")
 	tk.MustExec("drop table t1,t2,t3,t4,t5;")
 }
@@ -6458,56 +6458,155 @@ func (s *testDBSuite4) TestColumnCheck(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use " + s.schemaName)
 	tk.MustExec("drop table if exists column_check")
-	tk.MustExec("create table column_check (pk int primary key, a int check (a > 1))")
+	tk.MustExec("create table column_check (pk int primary key, a int check (a > 1), b int constraint b_positive check (b > 0) not enforced)")
 	defer tk.MustExec("drop table if exists column_check")
-	c.Assert(tk.Se.GetSessionVars().StmtCtx.WarningCount(), Equals, uint16(1))
-	tk.MustQuery("show warnings").Check(testutil.RowsWithSep("|", "Warning|8231|CONSTRAINT CHECK is not supported"))
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.WarningCount(), Equals, uint16(0))
+	tk.MustQuery("show create table column_check").Check(testutil.RowsWithSep("|", ""+
+		"column_check CREATE TABLE `column_check` (\n"+
+		"  `pk` int(11) NOT NULL,\n"+
+		"  `a` int(11) DEFAULT NULL,\n"+
+		"  `b` int(11) DEFAULT NULL,\n"+
+		"  PRIMARY KEY (`pk`) /*T![clustered_index] CLUSTERED */,\n"+
+		"  CONSTRAINT `column_check_chk_1` CHECK ((`a` > 1)),\n"+
+		"  CONSTRAINT `b_positive` CHECK ((`b` > 0)) /*!80016 NOT ENFORCED */\n"+
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+
+	// The column check constraint can only refer to its own column.
+	tk.MustGetErrCode("create table t_other (a int check (b > 1), b int)", errno.ErrColumnCheckConstraintReferencesOtherColumn)
+	// The column check constraint is added with the column by ADD COLUMN.
+	tk.MustExec("alter table column_check add column c int check (c > 0)")
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.WarningCount(), Equals, uint16(0))
+	tk.MustGetErrMsg("insert into column_check (pk, a, c) values (1, 2, 0)", "[table:3819]Check constraint 'column_check_chk_2' is violated.")
 }
 
 func (s *testDBSuite5) TestAlterCheck(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use " + s.schemaName)
 	tk.MustExec("drop table if exists alter_check")
-	tk.MustExec("create table alter_check (pk int primary key)")
+	tk.MustExec("create table alter_check (pk int primary key, a int, constraint crcn check (a > 1) not enforced)")
 	defer tk.MustExec("drop table if exists alter_check")
-	tk.MustExec("alter table alter_check alter check crcn ENFORCED")
-	c.Assert(tk.Se.GetSessionVars().StmtCtx.WarningCount(), Equals, uint16(1))
-	tk.MustQuery("show warnings").Check(testutil.RowsWithSep("|", "Warning|8231|ALTER CHECK is not supported"))
+	tk.MustGetErrCode("alter table alter_check alter check unknown enforced", errno.ErrCheckConstraintNotFound)
+
+	tk.MustExec("insert into alter_check values (1, 0)")
+	// The existing rows are validated when the constraint becomes enforced.
+	tk.MustGetErrCode("alter table alter_check alter check crcn enforced", errno.ErrCheckConstraintViolated)
+	tk.MustQuery("select constraint_name from information_schema.table_constraints where table_name = 'alter_check' and constraint_type = 'CHECK'").Check(testkit.Rows("crcn"))
+	tk.MustExec("insert into alter_check values (2, 0)")
+
+	tk.MustExec("delete from alter_check")
+	tk.MustExec("alter table alter_check alter check crcn enforced")
+	tk.MustGetErrCode("insert into alter_check values (3, 0)", errno.ErrCheckConstraintViolated)
+	tk.MustExec("alter table alter_check alter check crcn not enforced")
+	tk.MustExec("insert into alter_check values (3, 0)")
 }
 
 func (s *testDBSuite6) TestDropCheck(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use " + s.schemaName)
 	tk.MustExec("drop table if exists drop_check")
-	tk.MustExec("create table drop_check (pk int primary key)")
+	tk.MustExec("create table drop_check (pk int primary key, a int, b int, constraint c1 check (a > 1), constraint c2 check (a < b))")
 	defer tk.MustExec("drop table if exists drop_check")
-	tk.MustExec("alter table drop_check drop check crcn")
-	c.Assert(tk.Se.GetSessionVars().StmtCtx.WarningCount(), Equals, uint16(1))
-	tk.MustQuery("show warnings").Check(testutil.RowsWithSep("|", "Warning|8231|DROP CHECK is not supported"))
+	tk.MustGetErrCode("alter table drop_check drop check crcn", errno.ErrCheckConstraintNotFound)
+	tk.MustGetErrCode("insert into drop_check values (1, 0, 10)", errno.ErrCheckConstraintViolated)
+	tk.MustExec("alter table drop_check drop check c1")
+	tk.MustExec("insert into drop_check values (1, 0, 10)")
+	tk.MustQuery("select constraint_name, check_clause from information_schema.check_constraints where constraint_schema = '" + s.schemaName + "'").
+		Check(testkit.Rows("c2 (`a` < `b`)"))
+
+	// The column used by a multi-column constraint can't be dropped or renamed.
+	tk.MustGetErrCode("alter table drop_check drop column b", errno.ErrDependentByCheckConstraint)
+	tk.MustGetErrCode("alter table drop_check rename column b to c", errno.ErrDependentByCheckConstraint)
+	tk.MustGetErrCode("alter table drop_check change column b c int", errno.ErrDependentByCheckConstraint)
+	// The single-column constraint is dropped with its column.
+	tk.MustExec("alter table drop_check add constraint c3 check (b > 0)")
+	tk.MustExec("alter table drop_check drop check c2")
+	tk.MustExec("alter table drop_check drop column b")
+	tk.MustQuery("select count(*) from information_schema.check_constraints where constraint_schema = '" + s.schemaName + "'").Check(testkit.Rows("0"))
 }
 
 func (s *testDBSuite7) TestAddConstraintCheck(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use " + s.schemaName)
 	tk.MustExec("drop table if exists add_constraint_check")
-	tk.MustExec("create table add_constraint_check (pk int primary key, a int)")
+	tk.MustExec("create table add_constraint_check (pk int primary key auto_increment, a int)")
 	defer tk.MustExec("drop table if exists add_constraint_check")
+	tk.MustExec("insert into add_constraint_check (a) values (0), (2)")
+	// The existing rows are validated.
+	tk.MustGetErrCode("alter table add_constraint_check add constraint crn check (a > 1)", errno.ErrCheckConstraintViolated)
+	tk.MustQuery("select count(*) from information_schema.check_constraints where constraint_name = 'crn'").Check(testkit.Rows("0"))
+	tk.MustExec("alter table add_constraint_check add constraint crn check (a > 1) not enforced")
+	tk.MustGetErrCode("alter table add_constraint_check add constraint crn check (a > 0)", errno.ErrCheckConstraintDupName)
+	tk.MustExec("alter table add_constraint_check drop check crn")
+
+	tk.MustExec("delete from add_constraint_check where a = 0")
 	tk.MustExec("alter table add_constraint_check add constraint crn check (a > 1)")
-	c.Assert(tk.Se.GetSessionVars().StmtCtx.WarningCount(), Equals, uint16(1))
-	tk.MustQuery("show warnings").Check(testutil.RowsWithSep("|", "Warning|8231|ADD CONSTRAINT CHECK is not supported"))
-}
-
-func (s *testDBSuite7) TestCreateTableIngoreCheckConstraint(c *C) {
+	tk.MustExec("alter table add_constraint_check add check (a < 100)")
+	tk.MustQuery("select constraint_name, check_clause from information_schema.check_constraints where constraint_schema = '" + s.schemaName + "' order by constraint_name").
+		Check(testkit.Rows("add_constraint_check_chk_1 (`a` < 100)", "crn (`a` > 1)"))
+	tk.MustGetErrCode("insert into add_constraint_check (a) values (1)", errno.ErrCheckConstraintViolated)
+	tk.MustGetErrCode("insert into add_constraint_check (a) values (100)", errno.ErrCheckConstraintViolated)
+
+	// The invalid expressions are rejected.
+	tk.MustGetErrCode("alter table add_constraint_check add check (a + 1)", errno.ErrNonBooleanExprForCheckConstraint)
+	tk.MustGetErrCode("alter table add_constraint_check add check (pk > 0)", errno.ErrCheckConstraintRefersAutoIncrementColumn)
+	tk.MustGetErrCode("alter table add_constraint_check add check (c > 0)", errno.ErrCheckConstraintRefersUnknownColumn)
+	tk.MustGetErrCode("alter table add_constraint_check add check (a > rand())", errno.ErrCheckConstraintNamedFunctionIsNotAllowed)
+	tk.MustGetErrCode("alter table add_constraint_check add check (a > @a)", errno.ErrCheckConstraintVariables)
+	tk.MustGetErrCode("alter table add_constraint_check add check (a > (select 1))", errno.ErrCheckConstraintFunctionIsNotAllowed)
+}
+
+func (s *testDBSuite7) TestCreateTableWithCheckConstraint(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	tk.MustExec("use " + s.schemaName)
-	tk.MustExec("drop table if exists table_constraint_check")
+	tk.MustExec("drop table if exists admin_user")
 	tk.MustExec("CREATE TABLE admin_user (enable bool, CHECK (enable IN (0, 1)));")
-	c.Assert(tk.Se.GetSessionVars().StmtCtx.WarningCount(), Equals, uint16(1))
-	tk.MustQuery("show warnings").Check(testutil.RowsWithSep("|", "Warning|8231|CONSTRAINT CHECK is not supported"))
+	defer tk.MustExec("drop table if exists admin_user")
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.WarningCount(), Equals, uint16(0))
 	tk.MustQuery("show create table admin_user").Check(testutil.RowsWithSep("|", ""+
 		"admin_user CREATE TABLE `admin_user` (\n"+
-		"  `enable` tinyint(1) DEFAULT NULL\n"+
+		"  `enable` tinyint(1) DEFAULT NULL,\n"+
+		"  CONSTRAINT `admin_user_chk_1` CHECK ((`enable` in (0,1)))\n"+
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+	tk.MustGetErrCode("create table t_dup (a int, constraint c check (a > 0), constraint c check (a < 10))", errno.ErrCheckConstraintDupName)
+	tk.MustGetErrCode("create table t_rand (a int, check (a > rand()))", errno.ErrCheckConstraintNamedFunctionIsNotAllowed)
+
+	// The rows are checked when they are written.
+	tk.MustExec("insert into admin_user values (1), (null)")
+	tk.MustGetErrMsg("insert into admin_user values (2)", "[table:3819]Check constraint 'admin_user_chk_1' is violated.")
+}
+
+func (s *testDBSuite7) TestAddColumnWithCheckConstraint(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use " + s.schemaName)
+	tk.MustExec("drop table if exists add_column_check")
+	tk.MustExec("create table add_column_check (a int)")
+	defer tk.MustExec("drop table if exists add_column_check")
+	tk.MustExec("insert into add_column_check values (1), (2)")
+
+	tk.MustExec("alter table add_column_check add column b int default 1 check (b > 0)")
+	c.Assert(tk.Se.GetSessionVars().StmtCtx.WarningCount(), Equals, uint16(0))
+	tk.MustExec("alter table add_column_check add column c int check (c < 10), add column d int constraint d_pos check (d > 0)")
+	tk.MustQuery("show create table add_column_check").Check(testutil.RowsWithSep("|", ""+
+		"add_column_check CREATE TABLE `add_column_check` (\n"+
+		"  `a` int(11) DEFAULT NULL,\n"+
+		"  `b` int(11) DEFAULT '1',\n"+
+		"  `c` int(11) DEFAULT NULL,\n"+
+		"  `d` int(11) DEFAULT NULL,\n"+
+		"  CONSTRAINT `add_column_check_chk_1` CHECK ((`b` > 0)),\n"+
+		"  CONSTRAINT `add_column_check_chk_2` CHECK ((`c` < 10)),\n"+
+		"  CONSTRAINT `d_pos` CHECK ((`d` > 0))\n"+
 		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"))
+	tk.MustGetErrMsg("insert into add_column_check values (3, 0, 1, 1)", "[table:3819]Check constraint 'add_column_check_chk_1' is violated.")
+	tk.MustGetErrMsg("update add_column_check set d = -1", "[table:3819]Check constraint 'd_pos' is violated.")
+
+	// The existing rows read the default value of the new column, which should satisfy the constraint.
+	tk.MustGetErrCode("alter table add_column_check add column e int default 0 check (e > 0)", errno.ErrCheckConstraintViolated)
+	tk.MustGetErrCode("alter table add_column_check add column e int not null check (e > 0)", errno.ErrCheckConstraintViolated)
+	tk.MustExec("alter table add_column_check add column e int default 0 check (e > 0) not enforced")
+	tk.MustGetErrCode("alter table add_column_check add column f int check (f > a)", errno.ErrColumnCheckConstraintReferencesOtherColumn)
+	tk.MustGetErrCode("alter table add_column_check add column f int constraint d_pos check (f > 0)", errno.ErrCheckConstraintDupName)
+	tk.MustGetErrCode("alter table add_column_check add column f int as (a + 1) check (f > 0)", errno.ErrUnsupportedConstraintCheck)
+	tk.MustQuery("select * from add_column_check order by a").Check(testkit.Rows("1 1 <nil> <nil> 0", "2 1 <nil> <nil> 0"))
 }
 
 func (s *testDBSuite6) TestAlterOrderBy(c *C) {
@@ -831,7 +831,16 @@ func columnDefToCol(ctx sessionctx.Context, offset int, colDef *ast.ColumnDef, o
 			case ast.ColumnOptionFulltext:
 				ctx.GetSessionVars().StmtCtx.AppendWarning(ErrTableCantHandleFt.GenWithStackByArgs())
 			case ast.ColumnOptionCheck:
-				ctx.GetSessionVars().StmtCtx.AppendWarning(ErrUnsupportedConstraintCheck.GenWithStackByArgs("CONSTRAINT CHECK"))
+				// The column check constraint is built with the table check constraints.
+				constraint := &ast.Constraint{
+					Tp:           ast.ConstraintCheck,
+					Name:         v.ConstraintName,
+					Expr:         v.Expr,
+					Enforced:     v.Enforced,
+					InColumn:     true,
+					InColumnName: colDef.Name.Name.O,
+				}
+				constraints = append(constraints, constraint)
 			}
 		}
 	}
@@ -1361,6 +1370,10 @@ func checkConstraintNames(constraints []*ast.Constraint) error {
 
 	// Check not empty constraint name whether is duplicated.
 	for _, constr := range constraints {
+		if constr.Tp == ast.ConstraintCheck {
+			// The check constraints have their own namespace, which is checked when building them.
+			continue
+		}
 		if constr.Tp == ast.ConstraintForeignKey {
 			err := checkDuplicateConstraint(fkNames, constr.Name, true)
 			if err != nil {
@@ -1527,6 +1540,7 @@ func buildTableInfo(
 		tbInfo.Columns = append(tbInfo.Columns, v.ToInfo())
 		tblColumns = append(tblColumns, table.ToColumn(v.ToInfo()))
 	}
+	var checkConstraints []*ast.Constraint
 	for _, constr := range constraints {
 		// Build hidden columns if necessary.
 		hiddenCols, err := buildHiddenColumnInfo(ctx, constr.Keys, model.NewCIStr(constr.Name), tbInfo, tblColumns)
@@ -1590,7 +1604,7 @@ func buildTableInfo(
 			continue
 		}
 		if constr.Tp == ast.ConstraintCheck {
-			ctx.GetSessionVars().StmtCtx.AppendWarning(ErrUnsupportedConstraintCheck.GenWithStackByArgs("CONSTRAINT CHECK"))
+			checkConstraints = append(checkConstraints, constr)
 			continue
 		}
 		// build index info.
@@ -1610,6 +1624,9 @@ func buildTableInfo(
 		case ast.ConstraintUniq, ast.ConstraintUniqKey, ast.ConstraintUniqIndex:
 			idxInfo.Unique = true
 		}
+		if idxInfo.Unique && idxInfo.MVIndex {
+			return nil, errUnsupportedIndexType.GenWithStack("unique multi-valued index is not supported")
+		}
 		// set index type.
 		if constr.Option != nil {
 			idxInfo.Comment, err = validateCommentLength(ctx.GetSessionVars(), idxInfo.Name.String(), constr.Option)
@@ -1632,6 +1649,12 @@ func buildTableInfo(
 		idxInfo.ID = allocateIndexID(tbInfo)
 		tbInfo.Indices = append(tbInfo.Indices, idxInfo)
 	}
+	if err = buildForeignKeyIndices(tbInfo); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err = buildCheckConstraints(ctx, tbInfo, checkConstraints); err != nil {
+		return nil, errors.Trace(err)
+	}
 	if tbInfo.IsCommonHandle {
 		// Ensure tblInfo's each non-unique secondary-index's len + primary-key's len <= MaxIndexLength for clustered index table.
 		var pkLen, idxLen int
@@ -2011,6 +2034,11 @@ func (d *ddl) CreateTable(ctx sessionctx.Context, s *ast.CreateTableStmt) (err e
 	if err = checkTableInfoValidWithStmt(ctx, tbInfo, s); err != nil {
 		return err
 	}
+	for _, fk := range tbInfo.ForeignKeys {
+		if err = checkForeignKeyParentIndex(is, schema.Name, tbInfo, fk); err != nil {
+			return err
+		}
+	}
 
 	onExist := OnExistError
 	if s.IfNotExists {
@@ -2654,28 +2682,38 @@ func (d *ddl) AlterTable(ctx context.Context, sctx sessionctx.Context, ident ast
 		if isSameTypeMultiSpecs(validSpecs) {
 			switch validSpecs[0].Tp {
 			case ast.AlterTableAddColumns:
-				err = d.AddColumns(sctx, ident, validSpecs)
+				return errors.Trace(d.AddColumns(sctx, ident, validSpecs))
 			case ast.AlterTableDropColumn:
-				err = d.DropColumns(sctx, ident, validSpecs)
+				return errors.Trace(d.DropColumns(sctx, ident, validSpecs))
 			case ast.AlterTableDropPrimaryKey, ast.AlterTableDropIndex:
-				err = d.DropIndexes(sctx, ident, validSpecs)
-			default:
-				return errRunMultiSchemaChanges
+				return errors.Trace(d.DropIndexes(sctx, ident, validSpecs))
 			}
-			if err != nil {
-				return errors.Trace(err)
-			}
-			return nil
 		}
 
-		return errRunMultiSchemaChanges
+		if err = checkMultiSchemaSpecs(validSpecs); err != nil {
+			return errors.Trace(err)
+		}
+		// Collect the jobs of the specs as the sub-jobs of one multi-schema change job.
+		sctx.GetSessionVars().StmtCtx.MultiSchemaInfo = model.NewMultiSchemaInfo()
+		defer func() {
+			sctx.GetSessionVars().StmtCtx.MultiSchemaInfo = nil
+		}()
 	}
 
 	for _, spec := range validSpecs {
 		var handledCharsetOrCollate bool
 		switch spec.Tp {
 		case ast.AlterTableAddColumns:
-			if len(spec.NewColumns) != 1 {
+			if sctx.GetSessionVars().StmtCtx.MultiSchemaInfo != nil {
+				// Every column is added by its own sub-job in a multi-schema change.
+				for _, newCol := range spec.NewColumns {
+					colSpec := *spec
+					colSpec.NewColumns = []*ast.ColumnDef{newCol}
+					if err = d.AddColumn(sctx, ident, &colSpec); err != nil {
+						break
+					}
+				}
+			} else if len(spec.NewColumns) != 1 {
 				err = d.AddColumns(sctx, ident, []*ast.AlterTableSpec{spec})
 			} else {
 				err = d.AddColumn(sctx, ident, spec)
@@ -2685,7 +2723,7 @@ func (d *ddl) AlterTable(ctx context.Context, sctx sessionctx.Context, ident ast
 		case ast.AlterTableCoalescePartitions:
 			err = d.CoalescePartitions(sctx, ident, spec)
 		case ast.AlterTableReorganizePartition:
-			err = errors.Trace(errUnsupportedReorganizePartition)
+			err = d.ReorganizePartitions(sctx, ident, spec)
 		case ast.AlterTableCheckPartitions:
 			err = errors.Trace(errUnsupportedCheckPartition)
 		case ast.AlterTableRebuildPartition:
@@ -2693,7 +2731,7 @@ func (d *ddl) AlterTable(ctx context.Context, sctx sessionctx.Context, ident ast
 		case ast.AlterTableOptimizePartition:
 			err = errors.Trace(errUnsupportedOptimizePartition)
 		case ast.AlterTableRemovePartitioning:
-			err = errors.Trace(errUnsupportedRemovePartition)
+			err = d.RemovePartitioning(sctx, ident)
 		case ast.AlterTableRepairPartition:
 			err = errors.Trace(errUnsupportedRepairPartition)
 		case ast.AlterTableDropColumn:
@@ -2746,7 +2784,7 @@ func (d *ddl) AlterTable(ctx context.Context, sctx sessionctx.Context, ident ast
 			case ast.ConstraintFulltext:
 				sctx.GetSessionVars().StmtCtx.AppendWarning(ErrTableCantHandleFt)
 			case ast.ConstraintCheck:
-				sctx.GetSessionVars().StmtCtx.AppendWarning(ErrUnsupportedConstraintCheck.GenWithStackByArgs("ADD CONSTRAINT CHECK"))
+				err = d.CreateCheckConstraint(sctx, ident, constr)
 			default:
 				// Nothing to do now.
 			}
@@ -2772,8 +2810,7 @@ func (d *ddl) AlterTable(ctx context.Context, sctx sessionctx.Context, ident ast
 				err = errors.New("alter partition alter placement is experimental and it is switched off by tidb_enable_alter_placement")
 			}
 		case ast.AlterTablePartition:
-			// Prevent silent succeed if user executes ALTER TABLE x PARTITION BY ...
-			err = errors.New("alter table partition is unsupported")
+			err = d.AlterTablePartitioning(sctx, ident, spec)
 		case ast.AlterTableOption:
 			var placementSettings *model.PlacementSettings
 			var placementPolicyRef *model.PolicyRefInfo
@@ -2845,9 +2882,9 @@ func (d *ddl) AlterTable(ctx context.Context, sctx sessionctx.Context, ident ast
 		case ast.AlterTableIndexInvisible:
 			err = d.AlterIndexVisibility(sctx, ident, spec.IndexName, spec.Visibility)
 		case ast.AlterTableAlterCheck:
-			sctx.GetSessionVars().StmtCtx.AppendWarning(ErrUnsupportedConstraintCheck.GenWithStackByArgs("ALTER CHECK"))
+			err = d.AlterCheckConstraint(sctx, ident, model.NewCIStr(spec.Constraint.Name), spec.Constraint.Enforced)
 		case ast.AlterTableDropCheck:
-			sctx.GetSessionVars().StmtCtx.AppendWarning(ErrUnsupportedConstraintCheck.GenWithStackByArgs("DROP CHECK"))
+			err = d.DropCheckConstraint(sctx, ident, model.NewCIStr(spec.Constraint.Name))
 		case ast.AlterTableWithValidation:
 			sctx.GetSessionVars().StmtCtx.AppendWarning(errUnsupportedAlterTableWithValidation)
 		case ast.AlterTableWithoutValidation:
@@ -2873,6 +2910,36 @@ func (d *ddl) AlterTable(ctx context.Context, sctx sessionctx.Context, ident ast
 		}
 	}
 
+	if info := sctx.GetSessionVars().StmtCtx.MultiSchemaInfo; info != nil && len(info.SubJobs) > 0 {
+		return d.MultiSchemaChange(sctx, ident)
+	}
+	return nil
+}
+
+// checkMultiSchemaSpecs checks whether the specs can be run in one multi-schema change.
+func checkMultiSchemaSpecs(specs []*ast.AlterTableSpec) error {
+	for _, spec := range specs {
+		switch spec.Tp {
+		case ast.AlterTableAddColumns, ast.AlterTableDropColumn, ast.AlterTableModifyColumn, ast.AlterTableChangeColumn,
+			ast.AlterTableRenameColumn, ast.AlterTableAlterColumn, ast.AlterTableDropIndex, ast.AlterTableDropPrimaryKey,
+			ast.AlterTableRenameIndex, ast.AlterTableIndexInvisible:
+		case ast.AlterTableAddConstraint:
+			switch spec.Constraint.Tp {
+			case ast.ConstraintKey, ast.ConstraintIndex, ast.ConstraintUniq, ast.ConstraintUniqIndex,
+				ast.ConstraintUniqKey, ast.ConstraintPrimaryKey:
+			default:
+				return errRunMultiSchemaChanges
+			}
+		case ast.AlterTableOption:
+			for _, opt := range spec.Options {
+				if opt.Tp != ast.TableOptionComment {
+					return errRunMultiSchemaChanges
+				}
+			}
+		default:
+			return errRunMultiSchemaChanges
+		}
+	}
 	return nil
 }
 
@@ -3005,10 +3072,12 @@ func checkUnsupportedColumnConstraint(col *ast.ColumnDef, ti ast.Ident) error {
 	return nil
 }
 
-func checkAndCreateNewColumn(ctx sessionctx.Context, ti ast.Ident, schema *model.DBInfo, spec *ast.AlterTableSpec, t table.Table, specNewColumn *ast.ColumnDef) (*table.Column, error) {
+// checkAndCreateNewColumn checks the column to add and creates it, the check constraints defined in the column
+// options are returned with it.
+func checkAndCreateNewColumn(ctx sessionctx.Context, ti ast.Ident, schema *model.DBInfo, spec *ast.AlterTableSpec, t table.Table, specNewColumn *ast.ColumnDef) (*table.Column, []*ast.Constraint, error) {
 	err := checkUnsupportedColumnConstraint(specNewColumn, ti)
 	if err != nil {
-		return nil, errors.Trace(err)
+		return nil, nil, errors.Trace(err)
 	}
 
 	colName := specNewColumn.Name.Name.O
@@ -3018,15 +3087,15 @@ func checkAndCreateNewColumn(ctx sessionctx.Context, ti ast.Ident, schema *model
 		err = infoschema.ErrColumnExists.GenWithStackByArgs(colName)
 		if spec.IfNotExists {
 			ctx.GetSessionVars().StmtCtx.AppendNote(err)
-			return nil, nil
+			return nil, nil, nil
 		}
-		return nil, err
+		return nil, nil, err
 	}
 	if err = checkColumnAttributes(colName, specNewColumn.Tp); err != nil {
-		return nil, errors.Trace(err)
+		return nil, nil, errors.Trace(err)
 	}
 	if utf8.RuneCountInString(colName) > mysql.MaxColumnNameLength {
-		return nil, ErrTooLongIdent.GenWithStackByArgs(colName)
+		return nil, nil, ErrTooLongIdent.GenWithStackByArgs(colName)
 	}
 
 	// If new column is a generated column, do validation.
@@ -3035,17 +3104,17 @@ func checkAndCreateNewColumn(ctx sessionctx.Context, ti ast.Ident, schema *model
 	for _, option := range specNewColumn.Options {
 		if option.Tp == ast.ColumnOptionGenerated {
 			if err := checkIllegalFn4Generated(specNewColumn.Name.Name.L, typeColumn, option.Expr); err != nil {
-				return nil, errors.Trace(err)
+				return nil, nil, errors.Trace(err)
 			}
 
 			if option.Stored {
-				return nil, ErrUnsupportedOnGeneratedColumn.GenWithStackByArgs("Adding generated stored column through ALTER TABLE")
+				return nil, nil, ErrUnsupportedOnGeneratedColumn.GenWithStackByArgs("Adding generated stored column through ALTER TABLE")
 			}
 
 			_, dependColNames := findDependedColumnNames(specNewColumn)
 			if !ctx.GetSessionVars().EnableAutoIncrementInGenerated {
 				if err = checkAutoIncrementRef(specNewColumn.Name.Name.L, dependColNames, t.Meta()); err != nil {
-					return nil, errors.Trace(err)
+					return nil, nil, errors.Trace(err)
 				}
 			}
 			duplicateColNames := make(map[string]struct{}, len(dependColNames))
@@ -3055,11 +3124,11 @@ func checkAndCreateNewColumn(ctx sessionctx.Context, ti ast.Ident, schema *model
 			cols := t.Cols()
 
 			if err = checkDependedColExist(dependColNames, cols); err != nil {
-				return nil, errors.Trace(err)
+				return nil, nil, errors.Trace(err)
 			}
 
 			if err = verifyColumnGenerationSingle(duplicateColNames, cols, spec.Position); err != nil {
-				return nil, errors.Trace(err)
+				return nil, nil, errors.Trace(err)
 			}
 		}
 		// Specially, since sequence has been supported, if a newly added column has a
@@ -3069,10 +3138,10 @@ func checkAndCreateNewColumn(ctx sessionctx.Context, ti ast.Ident, schema *model
 		if option.Tp == ast.ColumnOptionDefaultValue {
 			_, isSeqExpr, err := tryToGetSequenceDefaultValue(option)
 			if err != nil {
-				return nil, errors.Trace(err)
+				return nil, nil, errors.Trace(err)
 			}
 			if isSeqExpr {
-				return nil, errors.Trace(ErrAddColumnWithSequenceAsDefault.GenWithStackByArgs(specNewColumn.Name.Name.O))
+				return nil, nil, errors.Trace(ErrAddColumnWithSequenceAsDefault.GenWithStackByArgs(specNewColumn.Name.Name.O))
 			}
 		}
 	}
@@ -3082,11 +3151,10 @@ func checkAndCreateNewColumn(ctx sessionctx.Context, ti ast.Ident, schema *model
 		ast.CharsetOpt{Chs: schema.Charset, Col: schema.Collate},
 	)
 	if err != nil {
-		return nil, errors.Trace(err)
+		return nil, nil, errors.Trace(err)
 	}
-	// Ignore table constraints now, they will be checked later.
 	// We use length(t.Cols()) as the default offset firstly, we will change the column's offset later.
-	col, _, err = buildColumnAndConstraint(
+	col, constraints, err := buildColumnAndConstraint(
 		ctx,
 		len(t.Cols()),
 		specNewColumn,
@@ -3095,16 +3163,21 @@ func checkAndCreateNewColumn(ctx sessionctx.Context, ti ast.Ident, schema *model
 		tableCollate,
 	)
 	if err != nil {
-		return nil, errors.Trace(err)
+		return nil, nil, errors.Trace(err)
+	}
+	// The existing rows are validated with the origin default value of the new column,
+	// which is not the value of a generated column.
+	if len(constraints) > 0 && col.IsGenerated() {
+		return nil, nil, ErrUnsupportedConstraintCheck.GenWithStackByArgs("ADD generated COLUMN with CONSTRAINT CHECK")
 	}
 
 	originDefVal, err := generateOriginDefaultValue(col.ToInfo())
 	if err != nil {
-		return nil, errors.Trace(err)
+		return nil, nil, errors.Trace(err)
 	}
 
 	err = col.SetOriginDefaultValue(originDefVal)
-	return col, err
+	return col, constraints, err
 }
 
 // AddColumn will add a new column to the table.
@@ -3117,7 +3190,7 @@ func (d *ddl) AddColumn(ctx sessionctx.Context, ti ast.Ident, spec *ast.AlterTab
 	if err = checkAddColumnTooManyColumns(len(t.Cols()) + 1); err != nil {
 		return errors.Trace(err)
 	}
-	col, err := checkAndCreateNewColumn(ctx, ti, schema, spec, t, specNewColumn)
+	col, constraints, err := checkAndCreateNewColumn(ctx, ti, schema, spec, t, specNewColumn)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -3125,6 +3198,22 @@ func (d *ddl) AddColumn(ctx sessionctx.Context, ti ast.Ident, spec *ast.AlterTab
 	if col == nil {
 		return nil
 	}
+	// The names generated for the anonymous constraints should not be used by the other sub-jobs.
+	usedNames := make(map[string]struct{})
+	if info := ctx.GetSessionVars().StmtCtx.MultiSchemaInfo; info != nil {
+		for _, sub := range info.SubJobs {
+			if sub.Type != model.ActionAddColumn || len(sub.Args) < 4 {
+				continue
+			}
+			for _, constraintInfo := range sub.Args[3].([]*model.ConstraintInfo) {
+				usedNames[constraintInfo.Name.L] = struct{}{}
+			}
+		}
+	}
+	constraintInfos, err := buildAddColumnCheckConstraints(ctx, t.Meta(), []*table.Column{col}, constraints, usedNames)
+	if err != nil {
+		return errors.Trace(err)
+	}
 
 	job := &model.Job{
 		SchemaID:   schema.ID,
@@ -3132,7 +3221,7 @@ func (d *ddl) AddColumn(ctx sessionctx.Context, ti ast.Ident, spec *ast.AlterTab
 		SchemaName: schema.Name.L,
 		Type:       model.ActionAddColumn,
 		BinlogInfo: &model.HistoryInfo{},
-		Args:       []interface{}{col, spec.Position, 0},
+		Args:       []interface{}{col, spec.Position, 0, constraintInfos},
 	}
 
 	err = d.doDDLJob(ctx, job)
@@ -3171,6 +3260,7 @@ func (d *ddl) AddColumns(ctx sessionctx.Context, ti ast.Ident, specs []*ast.Alte
 	positions := make([]*ast.ColumnPosition, 0, len(addingColumnNames))
 	offsets := make([]int, 0, len(addingColumnNames))
 	ifNotExists := make([]bool, 0, len(addingColumnNames))
+	var constraints []*ast.Constraint
 	newColumnsCount := 0
 	// Check the columns one by one.
 	for _, spec := range specs {
@@ -3180,7 +3270,7 @@ func (d *ddl) AddColumns(ctx sessionctx.Context, ti ast.Ident, specs []*ast.Alte
 				ctx.GetSessionVars().StmtCtx.AppendNote(err)
 				continue
 			}
-			col, err := checkAndCreateNewColumn(ctx, ti, schema, spec, t, specNewColumn)
+			col, colConstraints, err := checkAndCreateNewColumn(ctx, ti, schema, spec, t, specNewColumn)
 			if err != nil {
 				return errors.Trace(err)
 			}
@@ -3189,6 +3279,7 @@ func (d *ddl) AddColumns(ctx sessionctx.Context, ti ast.Ident, specs []*ast.Alte
 				continue
 			}
 			columns = append(columns, col)
+			constraints = append(constraints, colConstraints...)
 			positions = append(positions, spec.Position)
 			offsets = append(offsets, 0)
 			ifNotExists = append(ifNotExists, spec.IfNotExists)
@@ -3201,6 +3292,10 @@ func (d *ddl) AddColumns(ctx sessionctx.Context, ti ast.Ident, specs []*ast.Alte
 	if err = checkAddColumnTooManyColumns(len(t.Cols()) + newColumnsCount); err != nil {
 		return errors.Trace(err)
 	}
+	constraintInfos, err := buildAddColumnCheckConstraints(ctx, t.Meta(), columns, constraints, make(map[string]struct{}))
+	if err != nil {
+		return errors.Trace(err)
+	}
 
 	job := &model.Job{
 		SchemaID:   schema.ID,
@@ -3208,7 +3303,7 @@ func (d *ddl) AddColumns(ctx sessionctx.Context, ti ast.Ident, specs []*ast.Alte
 		SchemaName: schema.Name.L,
 		Type:       model.ActionAddColumns,
 		BinlogInfo: &model.HistoryInfo{},
-		Args:       []interface{}{columns, positions, offsets, ifNotExists},
+		Args:       []interface{}{columns, positions, offsets, ifNotExists, constraintInfos},
 	}
 
 	err = d.doDDLJob(ctx, job)
@@ -3410,6 +3505,182 @@ func (d *ddl) DropTablePartition(ctx sessionctx.Context, ident ast.Ident, spec *
 	return errors.Trace(err)
 }
 
+// ReorganizePartitions reorganizes some partitions of a RANGE or LIST partitioned table into new partitions.
+// The rows are copied online, see onReorganizePartition for the details.
+func (d *ddl) ReorganizePartitions(ctx sessionctx.Context, ident ast.Ident, spec *ast.AlterTableSpec) error {
+	is := d.infoCache.GetLatest()
+	schema, ok := is.SchemaByName(ident.Schema)
+	if !ok {
+		return errors.Trace(infoschema.ErrDatabaseNotExists.GenWithStackByArgs(schema))
+	}
+	t, err := is.TableByName(ident.Schema, ident.Name)
+	if err != nil {
+		return errors.Trace(infoschema.ErrTableNotExists.GenWithStackByArgs(ident.Schema, ident.Name))
+	}
+
+	meta := t.Meta()
+	pi := meta.GetPartitionInfo()
+	if pi == nil {
+		return errors.Trace(ErrPartitionMgmtOnNonpartitioned)
+	}
+	switch pi.Type {
+	case model.PartitionTypeRange, model.PartitionTypeList:
+	default:
+		return errors.Trace(errUnsupportedReorganizePartition)
+	}
+	if spec.OnAllPartitions {
+		return errors.Trace(ErrReorgNoParam)
+	}
+	if meta.TiFlashReplica != nil || hasGlobalIndex(meta) {
+		return errors.Trace(errUnsupportedReorganizeTable)
+	}
+
+	partNames := make([]string, len(spec.PartitionNames))
+	for i, partCIName := range spec.PartitionNames {
+		partNames[i] = partCIName.L
+	}
+	droppingDefs, err := checkReorganizePartitionNames(pi, partNames)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	partInfo, err := buildAddedPartitionInfo(ctx, meta, spec)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := d.assignPartitionIDs(partInfo.Definitions); err != nil {
+		return errors.Trace(err)
+	}
+
+	if pi.Type == model.PartitionTypeRange {
+		isLast := droppingDefs[len(droppingDefs)-1].ID == pi.Definitions[len(pi.Definitions)-1].ID
+		if err := checkReorganizeRangeEnd(ctx, meta, droppingDefs, partInfo.Definitions, isLast); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	// Check the partitions as they will be after the reorganization.
+	clonedMeta := meta.Clone()
+	tmp := *pi
+	tmp.Definitions = tables.ReplacePartitionDefinitions(pi.Definitions, droppingDefs, partInfo.Definitions)
+	clonedMeta.Partition = &tmp
+	if err := checkPartitionDefinitionConstraints(ctx, clonedMeta); err != nil {
+		return errors.Trace(err)
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    meta.ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionReorganizePartition,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{partNames, partInfo},
+	}
+
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
+// AlterTablePartitioning changes the partitioning of a table by ALTER TABLE ... PARTITION BY,
+// the table can be a partitioned table or not. The rows are copied online, see onReorganizePartition for the details.
+func (d *ddl) AlterTablePartitioning(ctx sessionctx.Context, ident ast.Ident, spec *ast.AlterTableSpec) error {
+	schema, t, err := d.getSchemaAndTableByIdent(ctx, ident)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	meta := t.Meta()
+	if err = checkPartitioningChangeable(meta); err != nil {
+		return errors.Trace(err)
+	}
+
+	newMeta := meta.Clone()
+	newMeta.Partition = nil
+	if err = buildTablePartitionInfo(ctx, spec.Partition, newMeta); err != nil {
+		return errors.Trace(err)
+	}
+	partInfo := newMeta.Partition
+	if partInfo == nil {
+		// Unlike CREATE TABLE, the table can't be treated as a normal table here.
+		return errors.Trace(errUnsupportedAlterPartitioning)
+	}
+	if err = checkPartitionFuncType(ctx, spec.Partition.Expr, newMeta); err != nil {
+		return errors.Trace(err)
+	}
+	if err = checkPartitionDefinitionConstraints(ctx, newMeta); err != nil {
+		return errors.Trace(err)
+	}
+	if err = checkUniqueKeysIncludePartKey(partInfo, newMeta); err != nil {
+		return errors.Trace(err)
+	}
+	if err = d.assignPartitionIDs(partInfo.Definitions); err != nil {
+		return errors.Trace(err)
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    meta.ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionAlterTablePartitioning,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{[]string(nil), partInfo},
+	}
+
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
+// RemovePartitioning turns a partitioned table into a non-partitioned table by ALTER TABLE ... REMOVE PARTITIONING.
+// All the partitions are reorganized into a single one, which becomes the table at the end.
+func (d *ddl) RemovePartitioning(ctx sessionctx.Context, ident ast.Ident) error {
+	schema, t, err := d.getSchemaAndTableByIdent(ctx, ident)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	meta := t.Meta()
+	if meta.GetPartitionInfo() == nil {
+		return errors.Trace(ErrPartitionMgmtOnNonpartitioned)
+	}
+	if err = checkPartitioningChangeable(meta); err != nil {
+		return errors.Trace(err)
+	}
+
+	partInfo := &model.PartitionInfo{
+		Type:        model.PartitionTypeNone,
+		Enable:      true,
+		Num:         1,
+		Definitions: []model.PartitionDefinition{{Name: model.NewCIStr("CollapsedPartitions")}},
+	}
+	if err = d.assignPartitionIDs(partInfo.Definitions); err != nil {
+		return errors.Trace(err)
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    meta.ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionRemovePartitioning,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{[]string(nil), partInfo},
+	}
+
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
+// checkPartitioningChangeable checks whether all the rows of the table can be copied into a new partition layout.
+func checkPartitioningChangeable(tblInfo *model.TableInfo) error {
+	if tblInfo.TiFlashReplica != nil || hasGlobalIndex(tblInfo) {
+		return errors.Trace(errUnsupportedReorganizeTable)
+	}
+	if tblInfo.TableCacheStatusType != model.TableCacheStatusDisable {
+		return errors.Trace(ErrOptOnCacheTable.GenWithStackByArgs("partition mode"))
+	}
+	return nil
+}
+
 func checkFieldTypeCompatible(ft *types.FieldType, other *types.FieldType) bool {
 	// int(1) could match the type with int(8)
 	partialEqual := ft.Tp == other.Tp &&
@@ -4116,6 +4387,9 @@ func (d *ddl) getModifiableColumnJob(ctx context.Context, sctx sessionctx.Contex
 		if c != nil {
 			return nil, infoschema.ErrColumnExists.GenWithStackByArgs(newColName)
 		}
+		if err = checkColumnDependedByCheckConstraint(t.Meta(), col.Name, true); err != nil {
+			return nil, errors.Trace(err)
+		}
 	}
 
 	// Constraints in the new column means adding new constraints. Errors should thrown,
@@ -4479,6 +4753,9 @@ func (d *ddl) RenameColumn(ctx sessionctx.Context, ident ast.Ident, spec *ast.Al
 	if fkInfo := getColumnForeignKeyInfo(oldColName.L, tbl.Meta().ForeignKeys); fkInfo != nil {
 		return errFKIncompatibleColumns.GenWithStackByArgs(oldColName, fkInfo.Name)
 	}
+	if err = checkColumnDependedByCheckConstraint(tbl.Meta(), oldCol.Name, true); err != nil {
+		return errors.Trace(err)
+	}
 
 	// Check generated expression.
 	for _, col := range allCols {
@@ -5441,6 +5718,13 @@ func (d *ddl) CreateIndex(ctx sessionctx.Context, ti ast.Ident, keyType ast.Inde
 	if err != nil {
 		return err
 	}
+	if keyType == ast.IndexKeyTypeUnique {
+		for _, hiddenCol := range hiddenCols {
+			if hiddenCol.ArrayElem != nil {
+				return errUnsupportedIndexType.GenWithStack("unique multi-valued index is not supported")
+			}
+		}
+	}
 	if err = checkAddColumnTooManyColumns(len(t.Cols()) + len(hiddenCols)); err != nil {
 		return errors.Trace(err)
 	}
@@ -5448,6 +5732,14 @@ func (d *ddl) CreateIndex(ctx sessionctx.Context, ti ast.Ident, keyType ast.Inde
 	finalColumns := make([]*model.ColumnInfo, len(tblInfo.Columns), len(tblInfo.Columns)+len(hiddenCols))
 	copy(finalColumns, tblInfo.Columns)
 	finalColumns = append(finalColumns, hiddenCols...)
+	if info := ctx.GetSessionVars().StmtCtx.MultiSchemaInfo; info != nil {
+		// The index may be built on the columns added in the same multi-schema change.
+		for _, sub := range info.SubJobs {
+			if sub.Type == model.ActionAddColumn {
+				finalColumns = append(finalColumns, sub.Args[0].(*table.Column).ToInfo())
+			}
+		}
+	}
 	// Check before the job is put to the queue.
 	// This check is redundant, but useful. If DDL check fail before the job is put
 	// to job queue, the fail path logic is super fast.
@@ -5534,9 +5826,10 @@ func buildFKInfo(fkName model.CIStr, keys []*ast.IndexPartSpecification, refer *
 	}
 
 	fkInfo := &model.FKInfo{
-		Name:     fkName,
-		RefTable: refer.Table.Name,
-		Cols:     make([]model.CIStr, len(keys)),
+		Name:      fkName,
+		RefSchema: refer.Table.Schema,
+		RefTable:  refer.Table.Name,
+		Cols:      make([]model.CIStr, len(keys)),
 	}
 
 	for i, key := range keys {
@@ -5618,6 +5911,13 @@ func (d *ddl) CreateForeignKey(ctx sessionctx.Context, ti ast.Ident, fkName mode
 	if err != nil {
 		return errors.Trace(err)
 	}
+	// Unlike CREATE TABLE, the index isn't created implicitly since it needs to backfill the rows.
+	if !hasForeignKeyIndex(t.Meta(), fkInfo.Cols, nil) {
+		return ErrFkNoIndexChild.GenWithStackByArgs(fkInfo.Name.O, t.Meta().Name.O)
+	}
+	if err = checkForeignKeyParentIndex(is, schema.Name, t.Meta(), fkInfo); err != nil {
+		return err
+	}
 
 	job := &model.Job{
 		SchemaID:   schema.ID,
@@ -5659,6 +5959,91 @@ func (d *ddl) DropForeignKey(ctx sessionctx.Context, ti ast.Ident, fkName model.
 	return errors.Trace(err)
 }
 
+// CreateCheckConstraint adds a check constraint to the table.
+func (d *ddl) CreateCheckConstraint(ctx sessionctx.Context, ti ast.Ident, constr *ast.Constraint) error {
+	schema, t, err := d.getSchemaAndTableByIdent(ctx, ti)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	tblInfo := t.Meta()
+
+	constrName := model.NewCIStr(constr.Name)
+	if constr.Name == "" {
+		constrName = genCheckConstraintName(tblInfo, nil)
+	} else if tblInfo.FindConstraintInfoByName(constrName.L) != nil {
+		return ErrCheckConstraintDupName.GenWithStackByArgs(constrName.O)
+	}
+	constraintInfo, err := buildConstraintInfo(ctx, tblInfo, constrName, constr)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    tblInfo.ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionAddCheckConstraint,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{constraintInfo},
+	}
+
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
+// DropCheckConstraint drops the check constraint from the table.
+func (d *ddl) DropCheckConstraint(ctx sessionctx.Context, ti ast.Ident, constrName model.CIStr) error {
+	schema, t, err := d.getSchemaAndTableByIdent(ctx, ti)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if t.Meta().FindConstraintInfoByName(constrName.L) == nil {
+		return ErrCheckConstraintNotFound.GenWithStackByArgs(constrName.O)
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    t.Meta().ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionDropCheckConstraint,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{constrName},
+	}
+
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
+// AlterCheckConstraint changes whether the check constraint is enforced.
+func (d *ddl) AlterCheckConstraint(ctx sessionctx.Context, ti ast.Ident, constrName model.CIStr, enforced bool) error {
+	schema, t, err := d.getSchemaAndTableByIdent(ctx, ti)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	constraintInfo := t.Meta().FindConstraintInfoByName(constrName.L)
+	if constraintInfo == nil {
+		return ErrCheckConstraintNotFound.GenWithStackByArgs(constrName.O)
+	}
+	if constraintInfo.Enforced == enforced {
+		return nil
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    t.Meta().ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionAlterCheckConstraint,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{constrName, enforced},
+	}
+
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
 func (d *ddl) DropIndex(ctx sessionctx.Context, ti ast.Ident, indexName model.CIStr, ifExists bool) error {
 	is := d.infoCache.GetLatest()
 	schema, ok := is.SchemaByName(ti.Schema)
@@ -5691,6 +6076,9 @@ func (d *ddl) DropIndex(ctx sessionctx.Context, ti ast.Ident, indexName model.CI
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if err = checkDropIndexNeededInForeignKey(is, schema.Name, t.Meta(), indexInfo); err != nil {
+		return errors.Trace(err)
+	}
 
 	jobTp := model.ActionDropIndex
 	if isPK {
@@ -5721,6 +6109,7 @@ func (d *ddl) DropIndexes(ctx sessionctx.Context, ti ast.Ident, specs []*ast.Alt
 	if err != nil {
 		return err
 	}
+	is := d.GetInfoSchemaWithInterceptor(ctx)
 
 	indexNames := make([]model.CIStr, 0, len(specs))
 	ifExists := make([]bool, 0, len(specs))
@@ -5741,6 +6130,9 @@ func (d *ddl) DropIndexes(ctx sessionctx.Context, ti ast.Ident, specs []*ast.Alt
 			if err := checkDropIndexOnAutoIncrementColumn(t.Meta(), indexInfo); err != nil {
 				return errors.Trace(err)
 			}
+			if err := checkDropIndexNeededInForeignKey(is, schema.Name, t.Meta(), indexInfo); err != nil {
+				return errors.Trace(err)
+			}
 		}
 
 		indexNames = append(indexNames, indexName)
@@ -5804,6 +6196,9 @@ func isDroppableColumn(multiSchemaChange bool, tblInfo *model.TableInfo, colName
 	if fkInfo := getColumnForeignKeyInfo(colName.L, tblInfo.ForeignKeys); fkInfo != nil {
 		return errFkColumnCannotDrop.GenWithStackByArgs(colName, fkInfo.Name)
 	}
+	if err := checkColumnDependedByCheckConstraint(tblInfo, colName, false); err != nil {
+		return errors.Trace(err)
+	}
 	return nil
 }
 
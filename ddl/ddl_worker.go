@@ -362,7 +362,8 @@ func (w *worker) updateDDLJob(t *meta.Meta, job *model.Job, meetErr bool) error
 	updateRawArgs := true
 	// If there is an error when running job and the RawArgs hasn't been decoded by DecodeArgs,
 	// so we shouldn't replace RawArgs with the marshaling Args.
-	if meetErr && (job.RawArgs != nil && job.Args == nil) {
+	// The multi-schema change job has no args itself, and only the decoded args of its sub-jobs are marshaled.
+	if meetErr && (job.RawArgs != nil && job.Args == nil) && job.Type != model.ActionMultiSchemaChange {
 		logutil.Logger(w.logCtx).Info("[ddl] meet something wrong before update DDL job, shouldn't update raw args",
 			zap.String("job", job.String()))
 		updateRawArgs = false
@@ -388,19 +389,8 @@ func (w *worker) finishDDLJob(t *meta.Meta, job *model.Job) (err error) {
 		metrics.DDLWorkerHistogram.WithLabelValues(metrics.WorkerFinishDDLJob, job.Type.String(), metrics.RetLabel(err)).Observe(time.Since(startTime).Seconds())
 	}()
 
-	if !job.IsCancelled() {
-		switch job.Type {
-		case model.ActionAddIndex, model.ActionAddPrimaryKey:
-			if job.State != model.JobStateRollbackDone {
-				break
-			}
-
-			// After rolling back an AddIndex operation, we need to use delete-range to delete the half-done index data.
-			err = w.deleteRange(w.ddlJobCtx, job)
-		case model.ActionDropSchema, model.ActionDropTable, model.ActionTruncateTable, model.ActionDropIndex, model.ActionDropPrimaryKey,
-			model.ActionDropTablePartition, model.ActionTruncateTablePartition, model.ActionDropColumn, model.ActionDropColumns, model.ActionModifyColumn, model.ActionDropIndexes:
-			err = w.deleteRange(w.ddlJobCtx, job)
-		}
+	if jobNeedGC(job) {
+		err = w.deleteRange(w.ddlJobCtx, job)
 	}
 	if job.Type == model.ActionRecoverTable {
 		err = finishRecoverTable(w, job)
@@ -426,6 +416,28 @@ func (w *worker) finishDDLJob(t *meta.Meta, job *model.Job) (err error) {
 	return errors.Trace(err)
 }
 
+// jobNeedGC returns whether the finished job needs to put its ranges into the delete-range table.
+func jobNeedGC(job *model.Job) bool {
+	if !job.IsCancelled() {
+		switch job.Type {
+		case model.ActionAddIndex, model.ActionAddPrimaryKey:
+			// After rolling back an AddIndex operation, we need to use delete-range to delete the half-done index data.
+			return job.State == model.JobStateRollbackDone
+		case model.ActionDropSchema, model.ActionDropTable, model.ActionTruncateTable, model.ActionDropIndex, model.ActionDropPrimaryKey,
+			model.ActionDropTablePartition, model.ActionTruncateTablePartition, model.ActionDropColumn, model.ActionDropColumns, model.ActionModifyColumn, model.ActionDropIndexes,
+			model.ActionReorganizePartition, model.ActionAlterTablePartitioning, model.ActionRemovePartitioning:
+			return true
+		case model.ActionMultiSchemaChange:
+			for _, sub := range job.MultiSchemaInfo.SubJobs {
+				if jobNeedGC(sub.ToProxyJob(job)) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
 func finishRecoverTable(w *worker, job *model.Job) error {
 	tbInfo := &model.TableInfo{}
 	var autoIncID, autoRandID, dropJobID, recoverTableCheckFlag int64
@@ -756,6 +768,8 @@ func (w *worker) runDDLJob(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64,
 		ver, err = onDropTableOrView(d, t, job)
 	case model.ActionDropTablePartition:
 		ver, err = w.onDropTablePartition(d, t, job)
+	case model.ActionReorganizePartition, model.ActionAlterTablePartitioning, model.ActionRemovePartitioning:
+		ver, err = w.onReorganizePartition(d, t, job)
 	case model.ActionTruncateTablePartition:
 		ver, err = onTruncateTablePartition(d, t, job)
 	case model.ActionExchangeTablePartition:
@@ -786,6 +800,12 @@ func (w *worker) runDDLJob(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64,
 		ver, err = onCreateForeignKey(t, job)
 	case model.ActionDropForeignKey:
 		ver, err = onDropForeignKey(t, job)
+	case model.ActionAddCheckConstraint:
+		ver, err = onAddCheckConstraint(w, t, job)
+	case model.ActionDropCheckConstraint:
+		ver, err = onDropCheckConstraint(t, job)
+	case model.ActionAlterCheckConstraint:
+		ver, err = onAlterCheckConstraint(w, t, job)
 	case model.ActionTruncateTable:
 		ver, err = onTruncateTable(d, t, job)
 	case model.ActionRebaseAutoID:
@@ -840,6 +860,8 @@ func (w *worker) runDDLJob(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64,
 		ver, err = onAlterTablePlacement(d, t, job)
 	case model.ActionAlterCacheTable:
 		ver, err = onAlterCacheTable(t, job)
+	case model.ActionMultiSchemaChange:
+		ver, err = onMultiSchemaChange(w, d, t, job)
 	default:
 		// Invalid job, cancel it.
 		job.State = model.JobStateCancelled
@@ -1052,7 +1074,8 @@ func updateSchemaVersion(t *meta.Meta, job *model.Job) (int64, error) {
 			newIDs := job.CtxVars[1].([]int64)
 			diff.AffectedOpts = buildPlacementAffects(oldIDs, newIDs)
 		}
-	case model.ActionDropTablePartition, model.ActionRecoverTable, model.ActionDropTable:
+	case model.ActionDropTablePartition, model.ActionRecoverTable, model.ActionDropTable, model.ActionReorganizePartition,
+		model.ActionAlterTablePartitioning:
 		// affects are used to update placement rule cache
 		diff.TableID = job.TableID
 		if len(job.CtxVars) > 0 {
@@ -1060,6 +1083,14 @@ func updateSchemaVersion(t *meta.Meta, job *model.Job) (int64, error) {
 				diff.AffectedOpts = buildPlacementAffects(oldIDs, oldIDs)
 			}
 		}
+	case model.ActionRemovePartitioning:
+		// The table gets a new table ID when the partitioning is removed.
+		diff.TableID = job.TableID
+		diff.OldTableID = job.TableID
+		if len(job.CtxVars) > 1 {
+			diff.AffectedOpts = buildPlacementAffects(job.CtxVars[0].([]int64), job.CtxVars[0].([]int64))
+			diff.TableID = job.CtxVars[1].(int64)
+		}
 	case model.ActionAlterTableAlterPartition:
 		diff.TableID = job.TableID
 		if len(job.CtxVars) > 0 {
@@ -297,7 +297,8 @@ func insertJobIntoDeleteRangeTable(ctx context.Context, sctx sessionctx.Context,
 		startKey = tablecodec.EncodeTablePrefix(tableID)
 		endKey := tablecodec.EncodeTablePrefix(tableID + 1)
 		return doInsert(ctx, s, job.ID, tableID, startKey, endKey, now)
-	case model.ActionDropTablePartition, model.ActionTruncateTablePartition:
+	case model.ActionDropTablePartition, model.ActionTruncateTablePartition, model.ActionReorganizePartition,
+		model.ActionAlterTablePartitioning, model.ActionRemovePartitioning:
 		var physicalTableIDs []int64
 		if err := job.DecodeArgs(&physicalTableIDs); err != nil {
 			return errors.Trace(err)
@@ -406,6 +407,16 @@ func insertJobIntoDeleteRangeTable(ctx context.Context, sctx sessionctx.Context,
 				return doBatchDeleteIndiceRange(ctx, s, job.ID, job.TableID, indexIDs, now)
 			}
 		}
+	case model.ActionMultiSchemaChange:
+		for _, sub := range job.MultiSchemaInfo.SubJobs {
+			proxyJob := sub.ToProxyJob(job)
+			if !jobNeedGC(proxyJob) {
+				continue
+			}
+			if err := insertJobIntoDeleteRangeTable(ctx, sctx, proxyJob); err != nil {
+				return errors.Trace(err)
+			}
+		}
 	case model.ActionModifyColumn:
 		var indexIDs []int64
 		var partitionIDs []int64
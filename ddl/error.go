@@ -93,10 +93,10 @@ var (
 	// ErrUnsupportedCoalescePartition returns for does not support coalesce partitions.
 	ErrUnsupportedCoalescePartition   = dbterror.ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation, parser_mysql.Message(fmt.Sprintf(mysql.MySQLErrName[mysql.ErrUnsupportedDDLOperation].Raw, "coalesce partitions"), nil))
 	errUnsupportedReorganizePartition = dbterror.ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation, parser_mysql.Message(fmt.Sprintf(mysql.MySQLErrName[mysql.ErrUnsupportedDDLOperation].Raw, "reorganize partition"), nil))
+	errUnsupportedReorganizeTable     = dbterror.ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation, parser_mysql.Message(fmt.Sprintf(mysql.MySQLErrName[mysql.ErrUnsupportedDDLOperation].Raw, "reorganize partition of a table with global index or TiFlash replica"), nil))
 	errUnsupportedCheckPartition      = dbterror.ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation, parser_mysql.Message(fmt.Sprintf(mysql.MySQLErrName[mysql.ErrUnsupportedDDLOperation].Raw, "check partition"), nil))
 	errUnsupportedOptimizePartition   = dbterror.ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation, parser_mysql.Message(fmt.Sprintf(mysql.MySQLErrName[mysql.ErrUnsupportedDDLOperation].Raw, "optimize partition"), nil))
 	errUnsupportedRebuildPartition    = dbterror.ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation, parser_mysql.Message(fmt.Sprintf(mysql.MySQLErrName[mysql.ErrUnsupportedDDLOperation].Raw, "rebuild partition"), nil))
-	errUnsupportedRemovePartition     = dbterror.ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation, parser_mysql.Message(fmt.Sprintf(mysql.MySQLErrName[mysql.ErrUnsupportedDDLOperation].Raw, "remove partitioning"), nil))
 	errUnsupportedRepairPartition     = dbterror.ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation, parser_mysql.Message(fmt.Sprintf(mysql.MySQLErrName[mysql.ErrUnsupportedDDLOperation].Raw, "repair partition"), nil))
 	// ErrGeneratedColumnFunctionIsNotAllowed returns for unsupported functions for generated columns.
 	ErrGeneratedColumnFunctionIsNotAllowed = dbterror.ClassDDL.NewStd(mysql.ErrGeneratedColumnFunctionIsNotAllowed)
@@ -110,6 +110,7 @@ var (
 	ErrFunctionalIndexRowValueIsNotAllowed = dbterror.ClassDDL.NewStd(mysql.ErrFunctionalIndexRowValueIsNotAllowed)
 	errUnsupportedCreatePartition          = dbterror.ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation, parser_mysql.Message(fmt.Sprintf(mysql.MySQLErrName[mysql.ErrUnsupportedDDLOperation].Raw, "partition type, treat as normal table"), nil))
 	errTablePartitionDisabled              = dbterror.ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation, parser_mysql.Message("Partitions are ignored because Table Partition is disabled, please set 'tidb_enable_table_partition' if you need to need to enable it", nil))
+	errUnsupportedAlterPartitioning        = dbterror.ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation, parser_mysql.Message(fmt.Sprintf(mysql.MySQLErrName[mysql.ErrUnsupportedDDLOperation].Raw, "partition type in ALTER TABLE ... PARTITION BY, or table partition is disabled"), nil))
 	errUnsupportedIndexType                = dbterror.ClassDDL.NewStdErr(mysql.ErrUnsupportedDDLOperation, parser_mysql.Message(fmt.Sprintf(mysql.MySQLErrName[mysql.ErrUnsupportedDDLOperation].Raw, "index type"), nil))
 	errWindowInvalidWindowFuncUse          = dbterror.ClassDDL.NewStd(mysql.ErrWindowInvalidWindowFuncUse)
 
@@ -117,6 +118,12 @@ var (
 	ErrDupKeyName = dbterror.ClassDDL.NewStd(mysql.ErrDupKeyName)
 	// ErrFkDupName returns for duplicated FK name.
 	ErrFkDupName = dbterror.ClassDDL.NewStd(mysql.ErrFkDupName)
+	// ErrFkNoIndexChild returns for adding a foreign key without an index on its columns.
+	ErrFkNoIndexChild = dbterror.ClassDDL.NewStd(mysql.ErrFkNoIndexChild)
+	// ErrFkNoIndexParent returns for adding a foreign key without an index on the referenced columns.
+	ErrFkNoIndexParent = dbterror.ClassDDL.NewStd(mysql.ErrFkNoIndexParent)
+	// ErrDropIndexNeededInForeignKey returns for dropping the index needed by a foreign key.
+	ErrDropIndexNeededInForeignKey = dbterror.ClassDDL.NewStd(mysql.ErrDropIndexFk)
 	// ErrInvalidDDLState returns for invalid ddl model object state.
 	ErrInvalidDDLState = dbterror.ClassDDL.NewStdErr(mysql.ErrInvalidDDLState, parser_mysql.Message(fmt.Sprintf(mysql.MySQLErrName[mysql.ErrInvalidDDLState].Raw), nil))
 	// ErrUnsupportedModifyPrimaryKey returns an error when add or drop the primary key.
@@ -178,6 +185,12 @@ var (
 	ErrPartitionMaxvalue = dbterror.ClassDDL.NewStd(mysql.ErrPartitionMaxvalue)
 	// ErrDropLastPartition returns cannot remove all partitions, use drop table instead.
 	ErrDropLastPartition = dbterror.ClassDDL.NewStd(mysql.ErrDropLastPartition)
+	// ErrReorgNoParam returns REORGANIZE PARTITION without parameters can only be used on auto-partitioned tables using HASH PARTITIONs.
+	ErrReorgNoParam = dbterror.ClassDDL.NewStd(mysql.ErrReorgNoParam)
+	// ErrConsecutiveReorgPartitions returns when reorganizing a set of partitions they must be in consecutive order.
+	ErrConsecutiveReorgPartitions = dbterror.ClassDDL.NewStd(mysql.ErrConsecutiveReorgPartitions)
+	// ErrReorgOutsideRange returns reorganize of range partitions cannot change total ranges except for last partition where it can extend the range.
+	ErrReorgOutsideRange = dbterror.ClassDDL.NewStd(mysql.ErrReorgOutsideRange)
 	// ErrTooManyPartitions returns too many partitions were defined.
 	ErrTooManyPartitions = dbterror.ClassDDL.NewStd(mysql.ErrTooManyPartitions)
 	// ErrPartitionConstDomain returns partition constant is out of partition function domain.
@@ -308,4 +321,31 @@ var (
 	errDependentByFunctionalIndex = dbterror.ClassDDL.NewStd(mysql.ErrDependentByFunctionalIndex)
 	// errFunctionalIndexOnBlob when the expression of expression index returns blob or text.
 	errFunctionalIndexOnBlob = dbterror.ClassDDL.NewStd(mysql.ErrFunctionalIndexOnBlob)
+	// ErrOperateSameColumn returns when a column is operated by more than one sub-job in a multi-schema change.
+	ErrOperateSameColumn = dbterror.ClassDDL.NewStd(mysql.ErrOperateSameColumn)
+	// ErrOperateSameIndex returns when an index is operated by more than one sub-job in a multi-schema change.
+	ErrOperateSameIndex = dbterror.ClassDDL.NewStd(mysql.ErrOperateSameIndex)
+
+	// ErrNonBooleanExprForCheckConstraint returns when the check constraint expression is not boolean.
+	ErrNonBooleanExprForCheckConstraint = dbterror.ClassDDL.NewStd(mysql.ErrNonBooleanExprForCheckConstraint)
+	// ErrColumnCheckConstraintReferencesOtherColumn returns when a column check constraint refers to other columns.
+	ErrColumnCheckConstraintReferencesOtherColumn = dbterror.ClassDDL.NewStd(mysql.ErrColumnCheckConstraintReferencesOtherColumn)
+	// ErrCheckConstraintNamedFunctionIsNotAllowed returns when the check constraint expression contains a disallowed function.
+	ErrCheckConstraintNamedFunctionIsNotAllowed = dbterror.ClassDDL.NewStd(mysql.ErrCheckConstraintNamedFunctionIsNotAllowed)
+	// ErrCheckConstraintFunctionIsNotAllowed returns when the check constraint expression contains a subquery or VALUES().
+	ErrCheckConstraintFunctionIsNotAllowed = dbterror.ClassDDL.NewStd(mysql.ErrCheckConstraintFunctionIsNotAllowed)
+	// ErrCheckConstraintVariables returns when the check constraint expression refers to a user or system variable.
+	ErrCheckConstraintVariables = dbterror.ClassDDL.NewStd(mysql.ErrCheckConstraintVariables)
+	// ErrCheckConstraintRowValueIsNotAllowed returns when the check constraint expression refers to a row value.
+	ErrCheckConstraintRowValueIsNotAllowed = dbterror.ClassDDL.NewStd(mysql.ErrCheckConstraintRowValueIsNotAllowed)
+	// ErrCheckConstraintRefersAutoIncrementColumn returns when the check constraint refers to an auto-increment column.
+	ErrCheckConstraintRefersAutoIncrementColumn = dbterror.ClassDDL.NewStd(mysql.ErrCheckConstraintRefersAutoIncrementColumn)
+	// ErrCheckConstraintRefersUnknownColumn returns when the check constraint refers to a non-existing column.
+	ErrCheckConstraintRefersUnknownColumn = dbterror.ClassDDL.NewStd(mysql.ErrCheckConstraintRefersUnknownColumn)
+	// ErrCheckConstraintNotFound returns when the check constraint to alter or drop does not exist.
+	ErrCheckConstraintNotFound = dbterror.ClassDDL.NewStd(mysql.ErrCheckConstraintNotFound)
+	// ErrCheckConstraintDupName returns when the check constraint name is already used in the table.
+	ErrCheckConstraintDupName = dbterror.ClassDDL.NewStd(mysql.ErrCheckConstraintDupName)
+	// ErrDependentByCheckConstraint returns when the dropped or renamed column is used by a check constraint.
+	ErrDependentByCheckConstraint = dbterror.ClassDDL.NewStd(mysql.ErrDependentByCheckConstraint)
 )
@@ -18,7 +18,9 @@ import (
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/types"
 )
 
 func onCreateForeignKey(t *meta.Meta, job *model.Job) (ver int64, _ error) {
@@ -111,3 +113,94 @@ func onDropForeignKey(t *meta.Meta, job *model.Job) (ver int64, _ error) {
 	}
 
 }
+
+// hasForeignKeyIndex reports whether the table has an index other than the excluded one to look up the rows by
+// the foreign key columns, that is, the columns are the prefix of the index. The foreign key checks on the child
+// table would scan the whole table without such an index.
+func hasForeignKeyIndex(tblInfo *model.TableInfo, cols []model.CIStr, exclude *model.IndexInfo) bool {
+	if tblInfo.PKIsHandle && len(cols) == 1 {
+		if pkCol := tblInfo.GetPkColInfo(); pkCol != nil && pkCol.Name.L == cols[0].L {
+			return true
+		}
+	}
+	for _, index := range tblInfo.Indices {
+		if index == exclude || index.State != model.StatePublic || len(index.Columns) < len(cols) {
+			continue
+		}
+		matched := true
+		for i, col := range cols {
+			// The prefix index can't be used to look up the whole value.
+			if index.Columns[i].Name.L != col.L || index.Columns[i].Length != types.UnspecifiedLength {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// buildForeignKeyIndices builds the indices on the foreign key columns of the new table which have no index to
+// be used by the foreign key checks. Like MySQL, the index is created implicitly and named after the foreign key.
+func buildForeignKeyIndices(tbInfo *model.TableInfo) error {
+	for _, fk := range tbInfo.ForeignKeys {
+		if hasForeignKeyIndex(tbInfo, fk.Cols, nil) {
+			continue
+		}
+		if tbInfo.FindIndexByName(fk.Name.L) != nil {
+			return ErrDupKeyName.GenWithStackByArgs(fk.Name.O)
+		}
+		keys := make([]*ast.IndexPartSpecification, 0, len(fk.Cols))
+		for _, col := range fk.Cols {
+			keys = append(keys, &ast.IndexPartSpecification{Column: &ast.ColumnName{Name: col}, Length: types.UnspecifiedLength})
+		}
+		idxInfo, err := buildIndexInfo(tbInfo, fk.Name, keys, model.StatePublic)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		idxInfo.Tp = model.IndexTypeBtree
+		idxInfo.ID = allocateIndexID(tbInfo)
+		tbInfo.Indices = append(tbInfo.Indices, idxInfo)
+	}
+	return nil
+}
+
+// checkForeignKeyParentIndex checks the referenced table has an index to look up the rows by the referenced columns,
+// the foreign key checks on the child table would scan the whole referenced table without it. It's not checked if
+// the referenced table doesn't exist.
+func checkForeignKeyParentIndex(is infoschema.InfoSchema, schema model.CIStr, tblInfo *model.TableInfo, fk *model.FKInfo) error {
+	refSchema := fk.RefSchema
+	if refSchema.L == "" {
+		refSchema = schema
+	}
+	parent := tblInfo
+	if refSchema.L != schema.L || fk.RefTable.L != tblInfo.Name.L {
+		refTbl, err := is.TableByName(refSchema, fk.RefTable)
+		if err != nil {
+			return nil
+		}
+		parent = refTbl.Meta()
+	}
+	if !hasForeignKeyIndex(parent, fk.RefCols, nil) {
+		return ErrFkNoIndexParent.GenWithStackByArgs(fk.Name.O, parent.Name.O)
+	}
+	return nil
+}
+
+// checkDropIndexNeededInForeignKey checks the foreign keys of the table and the ones referring to it still have
+// indices after the index is dropped.
+func checkDropIndexNeededInForeignKey(is infoschema.InfoSchema, schema model.CIStr, tblInfo *model.TableInfo, indexInfo *model.IndexInfo) error {
+	for _, fk := range tblInfo.ForeignKeys {
+		if !hasForeignKeyIndex(tblInfo, fk.Cols, indexInfo) {
+			return ErrDropIndexNeededInForeignKey.GenWithStackByArgs(indexInfo.Name.O)
+		}
+	}
+	for _, ref := range is.ReferringForeignKeys(schema, tblInfo.Name) {
+		if !hasForeignKeyIndex(tblInfo, ref.FK.RefCols, indexInfo) {
+			return ErrDropIndexNeededInForeignKey.GenWithStackByArgs(indexInfo.Name.O)
+		}
+	}
+	return nil
+}
@@ -121,7 +121,19 @@ func checkIndexPrefixLength(columns []*model.ColumnInfo, idxColumns []*model.Ind
 	return nil
 }
 
+// mvIndexElemColumn returns the column with the element type if the column is the
+// array key part of a multi-valued index, so that it's checked as the elements.
+func mvIndexElemColumn(col *model.ColumnInfo) *model.ColumnInfo {
+	if col.ArrayElem == nil {
+		return col
+	}
+	elemCol := *col
+	elemCol.FieldType = *col.ArrayElem
+	return &elemCol
+}
+
 func checkIndexColumn(col *model.ColumnInfo, indexColumnLen int) error {
+	col = mvIndexElemColumn(col)
 	if col.Flen == 0 && (types.IsTypeChar(col.FieldType.Tp) || types.IsTypeVarchar(col.FieldType.Tp)) {
 		if col.Hidden {
 			return errors.Trace(errWrongKeyColumnFunctionalIndex.GenWithStackByArgs(col.GeneratedExprString))
@@ -183,6 +195,7 @@ func checkIndexColumn(col *model.ColumnInfo, indexColumnLen int) error {
 
 // getIndexColumnLength calculate the bytes number required in an index column.
 func getIndexColumnLength(col *model.ColumnInfo, colLen int) (int, error) {
+	col = mvIndexElemColumn(col)
 	length := types.UnspecifiedLength
 	if colLen != types.UnspecifiedLength {
 		length = colLen
@@ -237,6 +250,15 @@ func buildIndexInfo(tblInfo *model.TableInfo, indexName model.CIStr, indexPartSp
 		Columns: idxColumns,
 		State:   state,
 	}
+	for _, idxCol := range idxColumns {
+		if tblInfo.Columns[idxCol.Offset].ArrayElem == nil {
+			continue
+		}
+		if idxInfo.MVIndex {
+			return nil, errUnsupportedIndexType.GenWithStack("more than one multi-valued key part per index is not supported")
+		}
+		idxInfo.MVIndex = true
+	}
 	return idxInfo, nil
 }
 
@@ -305,6 +327,12 @@ func onRenameIndex(t *meta.Meta, job *model.Job) (ver int64, _ error) {
 		return ver, errors.Trace(err)
 	}
 
+	if job.MultiSchemaInfo != nil && job.MultiSchemaInfo.Revertible {
+		// Rename the index after all the sub-jobs of the multi-schema change become non-revertible.
+		job.MarkNonRevertible()
+		return ver, nil
+	}
+
 	idx := tblInfo.FindIndexByName(from.L)
 	idx.Name = to
 	if ver, err = updateVersionAndTableInfo(t, job, tblInfo, true); err != nil {
@@ -329,6 +357,12 @@ func onAlterIndexVisibility(t *meta.Meta, job *model.Job) (ver int64, _ error) {
 	if err != nil || tblInfo == nil {
 		return ver, errors.Trace(err)
 	}
+	if job.MultiSchemaInfo != nil && job.MultiSchemaInfo.Revertible {
+		// Alter the index visibility after all the sub-jobs of the multi-schema change become non-revertible.
+		job.MarkNonRevertible()
+		return ver, nil
+	}
+
 	idx := tblInfo.FindIndexByName(from.L)
 	idx.Invisible = invisible
 	if ver, err = updateVersionAndTableInfoWithCheck(t, job, tblInfo, true); err != nil {
@@ -546,44 +580,21 @@ func (w *worker) onCreateIndex(d *ddlCtx, t *meta.Meta, job *model.Job, isPK boo
 		job.SchemaState = model.StateWriteReorganization
 	case model.StateWriteReorganization:
 		// reorganization -> public
-		tbl, err := getTable(d.store, schemaID, tblInfo)
-		if err != nil {
-			return ver, errors.Trace(err)
-		}
-
-		elements := []*meta.Element{{ID: indexInfo.ID, TypeKey: meta.IndexElementKey}}
-		reorgInfo, err := getReorgInfo(d, t, job, tbl, elements)
-		if err != nil || reorgInfo.first {
-			// If we run reorg firstly, we should update the job snapshot version
-			// and then run the reorg next time.
-			return ver, errors.Trace(err)
-		}
-
-		err = w.runReorgJob(t, reorgInfo, tbl.Meta(), d.lease, func() (addIndexErr error) {
-			defer util.Recover(metrics.LabelDDL, "onCreateIndex",
-				func() {
-					addIndexErr = errCancelledDDLJob.GenWithStack("add table `%v` index `%v` panic", tblInfo.Name, indexInfo.Name)
-				}, false)
-			return w.addTableIndex(tbl, indexInfo, reorgInfo)
-		})
-		if err != nil {
-			if errWaitReorgTimeout.Equal(err) {
-				// if timeout, we should return, check for the owner and re-wait job done.
-				return ver, nil
+		// The index data has been backfilled if the sub-job of a multi-schema change is non-revertible.
+		if job.MultiSchemaInfo == nil || job.MultiSchemaInfo.Revertible {
+			var done bool
+			done, ver, err = w.doReorgWorkForCreateIndex(d, t, job, tblInfo, indexInfo)
+			if !done {
+				return ver, err
 			}
-			if kv.ErrKeyExists.Equal(err) || errCancelledDDLJob.Equal(err) || errCantDecodeRecord.Equal(err) {
-				logutil.BgLogger().Warn("[ddl] run add index job failed, convert job to rollback", zap.String("job", job.String()), zap.Error(err))
-				ver, err = convertAddIdxJob2RollbackJob(t, job, tblInfo, indexInfo, err)
-				if err1 := t.RemoveDDLReorgHandle(job, reorgInfo.elements); err1 != nil {
-					logutil.BgLogger().Warn("[ddl] run add index job failed, convert job to rollback, RemoveDDLReorgHandle failed", zap.String("job", job.String()), zap.Error(err1))
-				}
+			if job.MultiSchemaInfo != nil {
+				// Make the index public after all the sub-jobs of the multi-schema change become non-revertible.
+				job.MarkNonRevertible()
+				// Reset SnapshotVer so that the rollback won't wait for the finished reorganization.
+				job.SnapshotVer = 0
+				return ver, nil
 			}
-			// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
-			w.reorgCtx.cleanNotifyReorgCancel()
-			return ver, errors.Trace(err)
 		}
-		// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
-		w.reorgCtx.cleanNotifyReorgCancel()
 
 		indexInfo.State = model.StatePublic
 		// Set column index flag.
@@ -606,6 +617,50 @@ func (w *worker) onCreateIndex(d *ddlCtx, t *meta.Meta, job *model.Job, isPK boo
 	return ver, errors.Trace(err)
 }
 
+// doReorgWorkForCreateIndex backfills the index data, done is true if the reorganization is finished.
+func (w *worker) doReorgWorkForCreateIndex(d *ddlCtx, t *meta.Meta, job *model.Job,
+	tblInfo *model.TableInfo, indexInfo *model.IndexInfo) (done bool, ver int64, err error) {
+	tbl, err := getTable(d.store, job.SchemaID, tblInfo)
+	if err != nil {
+		return false, ver, errors.Trace(err)
+	}
+
+	elements := []*meta.Element{{ID: indexInfo.ID, TypeKey: meta.IndexElementKey}}
+	reorgInfo, err := getReorgInfo(d, t, job, tbl, elements)
+	if err != nil || reorgInfo.first {
+		// If we run reorg firstly, we should update the job snapshot version
+		// and then run the reorg next time.
+		return false, ver, errors.Trace(err)
+	}
+
+	err = w.runReorgJob(t, reorgInfo, tbl.Meta(), d.lease, func() (addIndexErr error) {
+		defer util.Recover(metrics.LabelDDL, "onCreateIndex",
+			func() {
+				addIndexErr = errCancelledDDLJob.GenWithStack("add table `%v` index `%v` panic", tblInfo.Name, indexInfo.Name)
+			}, false)
+		return w.addTableIndex(tbl, indexInfo, reorgInfo)
+	})
+	if err != nil {
+		if errWaitReorgTimeout.Equal(err) {
+			// if timeout, we should return, check for the owner and re-wait job done.
+			return false, ver, nil
+		}
+		if kv.ErrKeyExists.Equal(err) || errCancelledDDLJob.Equal(err) || errCantDecodeRecord.Equal(err) {
+			logutil.BgLogger().Warn("[ddl] run add index job failed, convert job to rollback", zap.String("job", job.String()), zap.Error(err))
+			ver, err = convertAddIdxJob2RollbackJob(t, job, tblInfo, indexInfo, err)
+			if err1 := t.RemoveDDLReorgHandle(job, reorgInfo.elements); err1 != nil {
+				logutil.BgLogger().Warn("[ddl] run add index job failed, convert job to rollback, RemoveDDLReorgHandle failed", zap.String("job", job.String()), zap.Error(err1))
+			}
+		}
+		// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+		w.reorgCtx.cleanNotifyReorgCancel()
+		return false, ver, errors.Trace(err)
+	}
+	// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+	w.reorgCtx.cleanNotifyReorgCancel()
+	return true, ver, nil
+}
+
 func onDropIndex(t *meta.Meta, job *model.Job) (ver int64, _ error) {
 	tblInfo, indexInfo, err := checkDropIndex(t, job)
 	if err != nil {
@@ -622,6 +677,12 @@ func onDropIndex(t *meta.Meta, job *model.Job) (ver int64, _ error) {
 	originalState := indexInfo.State
 	switch indexInfo.State {
 	case model.StatePublic:
+		if job.MultiSchemaInfo != nil && job.MultiSchemaInfo.Revertible {
+			// Dropping an index can't be rolled back, start it after all the sub-jobs
+			// of the multi-schema change become non-revertible.
+			job.MarkNonRevertible()
+			return ver, nil
+		}
 		// public -> write only
 		indexInfo.State = model.StateWriteOnly
 		ver, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != indexInfo.State)
@@ -1077,7 +1138,12 @@ func (w *baseIndexWorker) getIndexRecord(idxInfo *model.IndexInfo, handle kv.Han
 			idxVal[j] = idxColumnVal
 			continue
 		}
-		idxColumnVal, err = tables.GetColDefaultValue(w.sessCtx, col, w.defaultVals)
+		if col.State != model.StatePublic && col.ChangeStateInfo == nil {
+			// The column is added by another sub-job of the multi-schema change, the row has its origin default value.
+			idxColumnVal, err = table.GetColOriginDefaultValue(w.sessCtx, col.ToInfo())
+		} else {
+			idxColumnVal, err = tables.GetColDefaultValue(w.sessCtx, col, w.defaultVals)
+		}
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
@@ -1574,13 +1640,14 @@ func (w *worker) updateReorgInfoForPartitions(t table.PartitionedTable, reorg *r
 	}
 
 	var pid int64
-	for i, pi := range partitionIDs {
-		if pi == reorg.PhysicalTableID {
+	for i, id := range partitionIDs {
+		if id == reorg.PhysicalTableID {
 			if i == len(partitionIDs)-1 {
 				return true, nil
 			}
+			pid = partitionIDs[i+1]
+			break
 		}
-		pid = partitionIDs[i+1]
 	}
 
 	currentVer, err := getValidCurrentVersion(reorg.d.store)
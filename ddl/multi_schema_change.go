@@ -0,0 +1,375 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl
+
+import (
+	"github.com/pingcap/errors"
+	"github.com/pingcap/failpoint"
+	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+)
+
+// MultiSchemaChange submits the sub-jobs collected from the specs of an ALTER TABLE statement as one DDL job.
+func (d *ddl) MultiSchemaChange(ctx sessionctx.Context, ti ast.Ident) error {
+	schema, t, err := d.getSchemaAndTableByIdent(ctx, ti)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	info := ctx.GetSessionVars().StmtCtx.MultiSchemaInfo
+	if err = checkMultiSchemaInfo(info, t); err != nil {
+		return errors.Trace(err)
+	}
+	// The sub-jobs are collected, the job below should be put to the queue directly.
+	ctx.GetSessionVars().StmtCtx.MultiSchemaInfo = nil
+
+	job := &model.Job{
+		SchemaID:        schema.ID,
+		TableID:         t.Meta().ID,
+		SchemaName:      schema.Name.L,
+		Type:            model.ActionMultiSchemaChange,
+		BinlogInfo:      &model.HistoryInfo{},
+		MultiSchemaInfo: info,
+		ReorgMeta: &model.DDLReorgMeta{
+			SQLMode:       ctx.GetSessionVars().SQLMode,
+			Warnings:      make(map[errors.ErrorID]*terror.Error),
+			WarningsCount: make(map[errors.ErrorID]int64),
+		},
+		Priority: ctx.GetSessionVars().DDLReorgPriority,
+	}
+	err = d.doDDLJob(ctx, job)
+	err = d.callHookOnChanged(err)
+	return errors.Trace(err)
+}
+
+// appendToSubJobs appends the job to the sub-jobs of the multi-schema change instead of running it.
+func appendToSubJobs(info *model.MultiSchemaInfo, job *model.Job) error {
+	switch job.Type {
+	case model.ActionAddColumn, model.ActionDropColumn, model.ActionModifyColumn, model.ActionSetDefaultValue,
+		model.ActionAddIndex, model.ActionAddPrimaryKey, model.ActionDropIndex, model.ActionDropPrimaryKey,
+		model.ActionRenameIndex, model.ActionAlterIndexVisibility, model.ActionModifyTableComment:
+	default:
+		return errRunMultiSchemaChanges
+	}
+	fillMultiSchemaInfo(info, job)
+	info.SubJobs = append(info.SubJobs, &model.SubJob{
+		Type:       job.Type,
+		Args:       job.Args,
+		Revertible: true,
+		CtxVars:    job.CtxVars,
+	})
+	return nil
+}
+
+// fillMultiSchemaInfo records the columns and indexes operated by the job, they are used to check
+// the conflicts between the sub-jobs.
+func fillMultiSchemaInfo(info *model.MultiSchemaInfo, job *model.Job) {
+	switch job.Type {
+	case model.ActionAddColumn:
+		col := job.Args[0].(*table.Column)
+		pos := job.Args[1].(*ast.ColumnPosition)
+		info.AddColumns = append(info.AddColumns, col.Name)
+		if pos != nil && pos.Tp == ast.ColumnPositionAfter {
+			info.RelativeColumns = append(info.RelativeColumns, pos.RelativeColumn.Name)
+		}
+	case model.ActionDropColumn:
+		colName := job.Args[0].(model.CIStr)
+		info.DropColumns = append(info.DropColumns, colName)
+	case model.ActionModifyColumn:
+		newCol := *job.Args[0].(**table.Column)
+		oldColName := job.Args[1].(model.CIStr)
+		pos := job.Args[2].(*ast.ColumnPosition)
+		info.ModifyColumns = append(info.ModifyColumns, oldColName)
+		if newCol.Name.L != oldColName.L {
+			info.AddColumns = append(info.AddColumns, newCol.Name)
+		}
+		if pos != nil && pos.Tp == ast.ColumnPositionAfter {
+			info.RelativeColumns = append(info.RelativeColumns, pos.RelativeColumn.Name)
+		}
+	case model.ActionSetDefaultValue:
+		col := job.Args[0].(*table.Column)
+		info.ModifyColumns = append(info.ModifyColumns, col.Name)
+	case model.ActionAddIndex, model.ActionAddPrimaryKey:
+		indexName := job.Args[1].(model.CIStr)
+		indexPartSpecifications := job.Args[2].([]*ast.IndexPartSpecification)
+		info.AddIndexes = append(info.AddIndexes, indexName)
+		for _, indexPartSpecification := range indexPartSpecifications {
+			if indexPartSpecification.Column != nil {
+				info.RelativeColumns = append(info.RelativeColumns, indexPartSpecification.Column.Name)
+			}
+		}
+	case model.ActionDropIndex, model.ActionDropPrimaryKey:
+		indexName := job.Args[0].(model.CIStr)
+		info.DropIndexes = append(info.DropIndexes, indexName)
+	case model.ActionRenameIndex:
+		from := job.Args[0].(model.CIStr)
+		to := job.Args[1].(model.CIStr)
+		info.AlterIndexes = append(info.AlterIndexes, from, to)
+	case model.ActionAlterIndexVisibility:
+		indexName := job.Args[0].(model.CIStr)
+		info.AlterIndexes = append(info.AlterIndexes, indexName)
+	}
+}
+
+// checkMultiSchemaInfo checks whether the sub-jobs of the multi-schema change conflict with each other.
+func checkMultiSchemaInfo(info *model.MultiSchemaInfo, t table.Table) error {
+	if err := checkOperateSameColumn(info, t.Meta()); err != nil {
+		return err
+	}
+	if err := checkOperateSameIndex(info); err != nil {
+		return err
+	}
+	if len(info.DropColumns) > 0 {
+		if err := checkDropVisibleColumnCnt(t, len(info.DropColumns)); err != nil {
+			return err
+		}
+	}
+	return checkAddColumnTooManyColumns(len(t.Cols()) + len(info.AddColumns))
+}
+
+func checkOperateSameColumn(info *model.MultiSchemaInfo, tblInfo *model.TableInfo) error {
+	operatedCols := make(map[string]struct{})
+	for _, cols := range [][]model.CIStr{info.AddColumns, info.DropColumns, info.ModifyColumns} {
+		for _, col := range cols {
+			if _, ok := operatedCols[col.L]; ok {
+				return ErrOperateSameColumn.GenWithStackByArgs(col.O)
+			}
+			operatedCols[col.L] = struct{}{}
+		}
+	}
+
+	// The columns referred by the other sub-jobs can't be dropped or modified.
+	relativeCols := info.RelativeColumns
+	for _, indexes := range [][]model.CIStr{info.DropIndexes, info.AlterIndexes} {
+		for _, indexName := range indexes {
+			idx := tblInfo.FindIndexByName(indexName.L)
+			if idx == nil {
+				continue
+			}
+			for _, idxCol := range idx.Columns {
+				relativeCols = append(relativeCols, idxCol.Name)
+			}
+		}
+	}
+	changedCols := make(map[string]struct{}, len(info.DropColumns)+len(info.ModifyColumns))
+	for _, cols := range [][]model.CIStr{info.DropColumns, info.ModifyColumns} {
+		for _, col := range cols {
+			changedCols[col.L] = struct{}{}
+		}
+	}
+	for _, col := range relativeCols {
+		if _, ok := changedCols[col.L]; ok {
+			return ErrOperateSameColumn.GenWithStackByArgs(col.O)
+		}
+	}
+	return nil
+}
+
+func checkOperateSameIndex(info *model.MultiSchemaInfo) error {
+	operatedIndexes := make(map[string]struct{})
+	for _, indexes := range [][]model.CIStr{info.AddIndexes, info.DropIndexes, info.AlterIndexes} {
+		for _, indexName := range indexes {
+			if _, ok := operatedIndexes[indexName.L]; ok {
+				return ErrOperateSameIndex.GenWithStackByArgs(indexName.O)
+			}
+			operatedIndexes[indexName.L] = struct{}{}
+		}
+	}
+	return nil
+}
+
+// onMultiSchemaChange runs the sub-jobs of a multi-schema change.
+// The sub-jobs are stepped together, every step moves all of them to their next schema states with
+// one schema version, so that the other TiDB instances never see the sub-jobs in different stages.
+// At first, every sub-job runs until it reaches its last revertible state, so that all of them can be
+// rolled back if any of them fails. After that, the sub-jobs are run to finish.
+func onMultiSchemaChange(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error) {
+	subJobs := job.MultiSchemaInfo.SubJobs
+	if job.MultiSchemaInfo.Revertible {
+		// Handle the rolling back job, the unfinished sub-jobs are rolled back together.
+		if job.IsRollingback() {
+			var rollingBack []*model.SubJob
+			for _, sub := range subJobs {
+				if !sub.IsFinished() {
+					rollingBack = append(rollingBack, sub)
+				}
+			}
+			if len(rollingBack) > 0 {
+				return stepSubJobs(w, d, t, job, rollingBack)
+			}
+			return finishMultiSchemaJob(t, job, model.JobStateRollbackDone, model.StateNone)
+		}
+
+		// Run the sub-jobs until they become non-revertible.
+		var stepping, reorging []*model.SubJob
+		for _, sub := range subJobs {
+			if !sub.Revertible || sub.IsFinished() {
+				continue
+			}
+			if sub.SchemaState == model.StateWriteReorganization {
+				reorging = append(reorging, sub)
+			} else {
+				stepping = append(stepping, sub)
+			}
+		}
+		if len(stepping) == 0 && len(reorging) > 0 {
+			// The reorganization doesn't change the schema states, and the worker can only
+			// run one reorganization at a time.
+			stepping = reorging[:1]
+		}
+		if len(stepping) > 0 {
+			return stepSubJobs(w, d, t, job, stepping)
+		}
+		// All the sub-jobs are non-revertible.
+		job.MarkNonRevertible()
+		return ver, nil
+	}
+
+	// Run the non-revertible sub-jobs to finish.
+	stepping := make([]*model.SubJob, 0, len(subJobs))
+	for _, sub := range subJobs {
+		if !sub.IsFinished() {
+			stepping = append(stepping, sub)
+		}
+	}
+	if len(stepping) > 0 {
+		return stepSubJobs(w, d, t, job, stepping)
+	}
+	return finishMultiSchemaJob(t, job, model.JobStateDone, model.StatePublic)
+}
+
+// stepSubJobs runs one step of each sub-job in one transaction, and updates the schema version once
+// if any of them has changed the table info.
+func stepSubJobs(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job, subJobs []*model.SubJob) (ver int64, err error) {
+	// The sub-jobs are restored if the transaction is discarded because of an error.
+	origins := make([]model.SubJob, len(subJobs))
+	for i, sub := range subJobs {
+		origins[i] = *sub
+	}
+	// The transaction of a rolling back job isn't discarded by the worker on error, so the table info
+	// is restored as well.
+	rollingBack := job.IsRollingback()
+	var originTblInfo *model.TableInfo
+	if rollingBack {
+		originTblInfo, err = getTableInfo(t, job.TableID, job.SchemaID)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+	}
+	schemaChanged := false
+	for i, sub := range subJobs {
+		proxyJob := sub.ToProxyJob(job)
+		proxyJob.MultiSchemaInfo.SkipVersion = true
+		_, err = w.runDDLJob(d, t, proxyJob)
+		failpoint.Inject("mockSubJobStepError", func(val failpoint.Value) {
+			if val.(int) == i && err == nil {
+				err = errors.New("mock sub-job step error")
+			}
+		})
+		sub.FromProxyJob(proxyJob, 0)
+		schemaChanged = schemaChanged || proxyJob.MultiSchemaInfo.SchemaChanged
+		if job.MultiSchemaInfo.Revertible && !rollingBack {
+			handleRevertibleException(job, sub, proxyJob.Error)
+		} else if !sub.IsNormal() && proxyJob.Error != nil {
+			// The other sub-jobs can't be rolled back anymore, keep the error as a warning.
+			logutil.Logger(w.logCtx).Warn("[ddl] non-revertible sub-job of multi-schema change failed",
+				zap.String("job", proxyJob.String()), zap.Error(proxyJob.Error))
+			job.MultiSchemaInfo.Warnings = append(job.MultiSchemaInfo.Warnings, proxyJob.Error)
+		}
+		if err != nil {
+			if job.IsRollingback() && !rollingBack {
+				// The transaction is kept to roll back the multi-schema change.
+				break
+			}
+			for j := 0; j <= i; j++ {
+				*subJobs[j] = origins[j]
+			}
+			if rollingBack {
+				if restoreErr := t.UpdateTable(job.SchemaID, originTblInfo); restoreErr != nil {
+					return ver, errors.Trace(restoreErr)
+				}
+			}
+			return ver, err
+		}
+	}
+	if !schemaChanged {
+		return ver, err
+	}
+	var verErr error
+	ver, verErr = updateSchemaVersion(t, job)
+	if verErr != nil {
+		return ver, errors.Trace(verErr)
+	}
+	for _, sub := range subJobs {
+		sub.SchemaVer = ver
+	}
+	return ver, err
+}
+
+// handleRevertibleException rolls back the multi-schema change if the sub-job fails in the revertible stage.
+func handleRevertibleException(job *model.Job, subJob *model.SubJob, err *terror.Error) {
+	if subJob.IsNormal() {
+		return
+	}
+	job.State = model.JobStateRollingback
+	if err == nil {
+		err = toTError(errCancelledDDLJob)
+	}
+	job.Error = err
+	cancelSubJobs(job)
+}
+
+// cancelSubJobs flushes the cancelling state to the running sub-jobs, and cancels the ones which haven't run.
+func cancelSubJobs(job *model.Job) {
+	for _, sub := range job.MultiSchemaInfo.SubJobs {
+		switch sub.State {
+		case model.JobStateRunning:
+			sub.State = model.JobStateCancelling
+		case model.JobStateNone:
+			sub.State = model.JobStateCancelled
+		}
+	}
+}
+
+func finishMultiSchemaJob(t *meta.Meta, job *model.Job, jobState model.JobState, schemaState model.SchemaState) (ver int64, err error) {
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	// The schema version has been updated by the sub-jobs.
+	var subVer int64
+	for _, sub := range job.MultiSchemaInfo.SubJobs {
+		if sub.SchemaVer > subVer {
+			subVer = sub.SchemaVer
+		}
+	}
+	job.FinishTableJob(jobState, schemaState, subVer, tblInfo)
+	return ver, nil
+}
+
+func rollingbackMultiSchemaChange(job *model.Job) error {
+	if !job.MultiSchemaInfo.Revertible {
+		job.State = model.JobStateRunning
+		return nil
+	}
+	cancelSubJobs(job)
+	job.State = model.JobStateRollingback
+	return errCancelledDDLJob
+}
@@ -0,0 +1,259 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ddl_test
+
+import (
+	"fmt"
+
+	. "github.com/pingcap/check"
+	"github.com/pingcap/failpoint"
+	"github.com/pingcap/tidb/ddl"
+	"github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/util/testkit"
+)
+
+func (s *serialTestStateChangeSuite) TestMultiSchemaChange(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b int, c int, e int, f int, index i1(b), index i2(e))")
+	tk.MustExec("insert into t values (1, 2, 3, 4, 0), (5, 6, 7, 8, 0)")
+	multiSchemaChangeSQL := "alter table t add column d int default 5 after a, add index i3(d), modify column c varchar(10), " +
+		"drop index i2, rename index i1 to i4, alter column f set default 10, comment = 'multi'"
+	tk.MustExec("set @@global.tidb_enable_change_multi_schema = 0")
+	tk = testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustGetErrCode(multiSchemaChangeSQL, errno.ErrUnsupportedDDLOperation)
+
+	tk.MustExec("set @@global.tidb_enable_change_multi_schema = 1")
+	tk = testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec(multiSchemaChangeSQL)
+	tk.MustExec("admin check table t")
+	tk.MustExec("insert into t (a, b, c, e) values (9, 9, 'x', 9)")
+	tk.MustQuery("select * from t order by a").Check(testkit.Rows("1 5 2 3 4 0", "5 5 6 7 8 0", "9 5 9 x 9 10"))
+	tk.MustQuery("select column_name, data_type from information_schema.columns where table_schema = 'test' and table_name = 't' order by ordinal_position").
+		Check(testkit.Rows("a int", "d int", "b int", "c varchar", "e int", "f int"))
+	tk.MustQuery("select index_name, column_name from information_schema.statistics where table_schema = 'test' and table_name = 't' order by index_name").
+		Check(testkit.Rows("i3 d", "i4 b"))
+	tk.MustQuery("select table_comment from information_schema.tables where table_schema = 'test' and table_name = 't'").Check(testkit.Rows("multi"))
+
+	// The changing column isn't the last column when other sub-jobs add columns.
+	tk.MustExec("drop table t")
+	tk.MustExec("create table t (a int, b int, index i1(b))")
+	tk.MustExec("insert into t values (1, 2), (3, 4)")
+	tk.MustExec("alter table t add column c int default 1, modify column b varchar(10)")
+	tk.MustExec("admin check table t")
+	tk.MustQuery("select * from t order by a").Check(testkit.Rows("1 2 1", "3 4 1"))
+	tk.MustExec("alter table t modify column b int, add column d int default 2 first, add index i2(d)")
+	tk.MustExec("admin check table t")
+	tk.MustQuery("select * from t order by a").Check(testkit.Rows("2 1 2 1", "2 3 4 1"))
+	tk.MustQuery("select column_name, data_type from information_schema.columns where table_schema = 'test' and table_name = 't' order by ordinal_position").
+		Check(testkit.Rows("d int", "a int", "b int", "c int"))
+
+	// The check constraints of the added columns take effect with them, and the names generated
+	// for the anonymous ones are not duplicated among the sub-jobs.
+	tk.MustExec("alter table t add column e int check (e < 5), add column f int default 1 check (f > 0), add index i3(c)")
+	tk.MustExec("admin check table t")
+	tk.MustQuery("select constraint_name, check_clause from information_schema.check_constraints where constraint_schema = 'test' order by constraint_name").
+		Check(testkit.Rows("t_chk_1 (`e` < 5)", "t_chk_2 (`f` > 0)"))
+	tk.MustGetErrMsg("insert into t (a, f) values (5, 0)", "[table:3819]Check constraint 't_chk_2' is violated.")
+	tk.MustGetErrCode("alter table t add column g int default 0 check (g > 0), add index i4(e)", errno.ErrCheckConstraintViolated)
+	tk.MustExec("drop table t")
+}
+
+func (s *serialTestStateChangeSuite) TestMultiSchemaChangeSchemaStates(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b int)")
+	tk.MustExec("insert into t values (1, 2), (3, 4)")
+
+	// The added column and index change their states together, one schema version for each state.
+	var states []string
+	callback := &ddl.TestDDLCallback{Do: s.dom}
+	callback.OnJobUpdatedExported = func(job *model.Job) {
+		if job.Type != model.ActionMultiSchemaChange {
+			return
+		}
+		tbl, err := s.dom.InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+		if err != nil {
+			return
+		}
+		colState, idxState := model.StateNone, model.StateNone
+		if col := model.FindColumnInfo(tbl.Meta().Columns, "c"); col != nil {
+			colState = col.State
+		}
+		if idx := tbl.Meta().FindIndexByName("i1"); idx != nil {
+			idxState = idx.State
+		}
+		state := fmt.Sprintf("%s, %s", colState, idxState)
+		if colState == model.StateNone && idxState == model.StateNone {
+			return
+		}
+		if len(states) == 0 || states[len(states)-1] != state {
+			states = append(states, state)
+		}
+	}
+	d := s.dom.DDL()
+	originalCallback := d.GetHook()
+	defer d.(ddl.DDLForTest).SetHook(originalCallback)
+	d.(ddl.DDLForTest).SetHook(callback)
+
+	verBefore := s.dom.InfoSchema().SchemaMetaVersion()
+	tk.MustExec("alter table t add column c int default 1, add index i1(c)")
+	c.Assert(s.dom.InfoSchema().SchemaMetaVersion()-verBefore, Equals, int64(4))
+	c.Assert(states, DeepEquals, []string{
+		"delete only, delete only",
+		"write only, write only",
+		"write reorganization, write reorganization",
+		"public, public",
+	})
+	tk.MustExec("admin check table t")
+	tk.MustQuery("select * from t use index(i1) order by a").Check(testkit.Rows("1 2 1", "3 4 1"))
+	tk.MustExec("drop table t")
+}
+
+func (s *serialTestStateChangeSuite) TestMultiSchemaChangeSubJobError(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b int)")
+	tk.MustExec("insert into t values (1, 2), (3, 4)")
+
+	// The failpoint is evaluated once for each sub-job, so the second sub-job fails after it has run its
+	// first step, and the step of all the sub-jobs is retried from their original states.
+	var retriedStates []model.SchemaState
+	callback := &ddl.TestDDLCallback{Do: s.dom}
+	callback.OnJobRunBeforeExported = func(job *model.Job) {
+		if job.Type != model.ActionMultiSchemaChange || job.ErrorCount == 0 || retriedStates != nil {
+			return
+		}
+		for _, sub := range job.MultiSchemaInfo.SubJobs {
+			retriedStates = append(retriedStates, sub.SchemaState)
+		}
+	}
+	d := s.dom.DDL()
+	originalCallback := d.GetHook()
+	defer d.(ddl.DDLForTest).SetHook(originalCallback)
+	d.(ddl.DDLForTest).SetHook(callback)
+
+	c.Assert(failpoint.Enable("github.com/pingcap/tidb/ddl/mockSubJobStepError", `2*return(1)`), IsNil)
+	defer func() {
+		c.Assert(failpoint.Disable("github.com/pingcap/tidb/ddl/mockSubJobStepError"), IsNil)
+	}()
+	tk.MustExec("alter table t add column c int default 1, add index i1(b)")
+	c.Assert(retriedStates, DeepEquals, []model.SchemaState{model.StateNone, model.StateNone})
+	tk.MustExec("admin check table t")
+	tk.MustQuery("select * from t use index(i1) order by a").Check(testkit.Rows("1 2 1", "3 4 1"))
+	tk.MustQuery("select index_name, column_name from information_schema.statistics where table_schema = 'test' and table_name = 't' order by index_name").
+		Check(testkit.Rows("i1 b"))
+	tk.MustExec("drop table t")
+}
+
+func (s *serialTestStateChangeSuite) TestMultiSchemaChangeConflict(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b int, c int, index i1(b))")
+	tk.MustGetErrCode("alter table t modify column a bigint, drop column a", errno.ErrOperateSameColumn)
+	tk.MustGetErrCode("alter table t add column d int, add column d bigint", errno.ErrDupFieldName)
+	tk.MustGetErrCode("alter table t add index i2(a), drop column a", errno.ErrOperateSameColumn)
+	tk.MustGetErrCode("alter table t add column d int after a, change column a e int", errno.ErrOperateSameColumn)
+	tk.MustGetErrCode("alter table t drop index i1, modify column b bigint", errno.ErrOperateSameColumn)
+	tk.MustGetErrCode("alter table t drop index i1, rename index i1 to i2", errno.ErrOperateSameIndex)
+	tk.MustGetErrCode("alter table t add index i2(a), add index i2(c)", errno.ErrOperateSameIndex)
+	tk.MustGetErrCode("alter table t add column d int, rename to t1", errno.ErrUnsupportedDDLOperation)
+	tk.MustGetErrCode("alter table t add column d int, auto_increment = 10", errno.ErrUnsupportedDDLOperation)
+	tk.MustQuery("select column_name from information_schema.columns where table_schema = 'test' and table_name = 't' order by ordinal_position").
+		Check(testkit.Rows("a", "b", "c"))
+	tk.MustExec("drop table t")
+}
+
+func (s *serialTestStateChangeSuite) TestMultiSchemaChangeRollback(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b int, c int, e int, index i1(c))")
+	tk.MustExec("insert into t values (1, 1, 1, 1), (2, 1, 2, 2)")
+	// The failed unique index rolls back all the other sub-jobs.
+	tk.MustGetErrCode("alter table t add column d int, drop column a, add unique index u(b), drop index i1, modify column e varchar(10)",
+		errno.ErrDupEntry)
+	tk.MustExec("admin check table t")
+	tk.MustQuery("select * from t order by a").Check(testkit.Rows("1 1 1 1", "2 1 2 2"))
+	tk.MustQuery("select column_name, data_type from information_schema.columns where table_schema = 'test' and table_name = 't' order by ordinal_position").
+		Check(testkit.Rows("a int", "b int", "c int", "e int"))
+	tk.MustQuery("select index_name, column_name from information_schema.statistics where table_schema = 'test' and table_name = 't' order by index_name").
+		Check(testkit.Rows("i1 c"))
+	tk.MustExec("drop table t")
+}
+
+func (s *serialTestStateChangeSuite) TestMultiSchemaChangeRollbackSchemaStates(c *C) {
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (a int, b int, c int, index i1(c))")
+	tk.MustExec("insert into t values (1, 1, 1), (2, 1, 2)")
+
+	// The sub-jobs are rolled back together, one schema version for each state, the same as they are run.
+	var states []string
+	callback := &ddl.TestDDLCallback{Do: s.dom}
+	callback.OnJobUpdatedExported = func(job *model.Job) {
+		if job.Type != model.ActionMultiSchemaChange {
+			return
+		}
+		tbl, err := s.dom.InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+		if err != nil {
+			return
+		}
+		colState, idxState, dropIdxState := model.StateNone, model.StateNone, model.StateNone
+		if col := model.FindColumnInfo(tbl.Meta().Columns, "d"); col != nil {
+			colState = col.State
+		}
+		if idx := tbl.Meta().FindIndexByName("u"); idx != nil {
+			idxState = idx.State
+		}
+		if idx := tbl.Meta().FindIndexByName("i1"); idx != nil {
+			dropIdxState = idx.State
+		}
+		state := fmt.Sprintf("%s, %s, %s", colState, idxState, dropIdxState)
+		if len(states) == 0 || states[len(states)-1] != state {
+			states = append(states, state)
+		}
+	}
+	d := s.dom.DDL()
+	originalCallback := d.GetHook()
+	defer d.(ddl.DDLForTest).SetHook(originalCallback)
+	d.(ddl.DDLForTest).SetHook(callback)
+
+	verBefore := s.dom.InfoSchema().SchemaMetaVersion()
+	tk.MustGetErrCode("alter table t add column d int, add unique index u(b), drop index i1", errno.ErrDupEntry)
+	c.Assert(s.dom.InfoSchema().SchemaMetaVersion()-verBefore, Equals, int64(7))
+	c.Assert(states, DeepEquals, []string{
+		"queueing, queueing, public",
+		"delete only, delete only, public",
+		"write only, write only, public",
+		"write reorganization, write reorganization, public",
+		"write reorganization, delete only, public",
+		"delete only, delete reorganization, public",
+		"delete reorganization, queueing, public",
+		"queueing, queueing, public",
+	})
+	tk.MustExec("admin check table t")
+	tk.MustQuery("select index_name, column_name from information_schema.statistics where table_schema = 'test' and table_name = 't' order by index_name").
+		Check(testkit.Rows("i1 c"))
+	tk.MustExec("drop table t")
+}
@@ -33,6 +33,7 @@ import (
 	"github.com/pingcap/tidb/domain/infosync"
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/meta"
 	"github.com/pingcap/tidb/metrics"
 	"github.com/pingcap/tidb/parser"
@@ -42,6 +43,7 @@ import (
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/table/tables"
 	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
 	driver "github.com/pingcap/tidb/types/parser_driver"
@@ -50,8 +52,11 @@ import (
 	"github.com/pingcap/tidb/util/collate"
 	"github.com/pingcap/tidb/util/hack"
 	"github.com/pingcap/tidb/util/logutil"
+	decoder "github.com/pingcap/tidb/util/rowDecoder"
 	"github.com/pingcap/tidb/util/slice"
 	"github.com/pingcap/tidb/util/sqlexec"
+	"github.com/pingcap/tidb/util/timeutil"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/tikv/client-go/v2/tikv"
 	"go.uber.org/zap"
 )
@@ -920,6 +925,93 @@ func checkDropTablePartition(meta *model.TableInfo, partLowerNames []string) err
 	return nil
 }
 
+// checkReorganizePartitionNames checks the partitions to reorganize exist and returns their definitions.
+// For RANGE partitioning, the partitions must also be consecutive.
+func checkReorganizePartitionNames(pi *model.PartitionInfo, partLowerNames []string) ([]model.PartitionDefinition, error) {
+	nameSet := make(map[string]struct{}, len(partLowerNames))
+	for _, pn := range partLowerNames {
+		if _, ok := nameSet[pn]; ok {
+			return nil, errors.Trace(ErrSameNamePartition.GenWithStackByArgs(pn))
+		}
+		nameSet[pn] = struct{}{}
+	}
+	defs := make([]model.PartitionDefinition, 0, len(partLowerNames))
+	firstIdx, lastIdx := -1, -1
+	for i, def := range pi.Definitions {
+		if _, ok := nameSet[def.Name.L]; !ok {
+			continue
+		}
+		if firstIdx < 0 {
+			firstIdx = i
+		}
+		lastIdx = i
+		defs = append(defs, def)
+		delete(nameSet, def.Name.L)
+	}
+	for _, pn := range partLowerNames {
+		if _, ok := nameSet[pn]; ok {
+			return nil, errors.Trace(ErrDropPartitionNonExistent.GenWithStackByArgs("REORGANIZE"))
+		}
+	}
+	if pi.Type == model.PartitionTypeRange && lastIdx-firstIdx+1 != len(defs) {
+		return nil, errors.Trace(ErrConsecutiveReorgPartitions)
+	}
+	return defs, nil
+}
+
+// checkReorganizeRangeEnd checks the new RANGE partitions cover the same range as the old ones.
+// Only the last partition of the table can be extended.
+func checkReorganizeRangeEnd(ctx sessionctx.Context, tblInfo *model.TableInfo, oldDefs, newDefs []model.PartitionDefinition, isLast bool) error {
+	oldEnd := oldDefs[len(oldDefs)-1].LessThan
+	newEnd := newDefs[len(newDefs)-1].LessThan
+	if isSameRangeEnd(oldEnd, newEnd) {
+		return nil
+	}
+	if !isLast {
+		return errors.Trace(ErrReorgOutsideRange)
+	}
+	if strings.EqualFold(oldEnd[len(oldEnd)-1], partitionMaxValue) {
+		// Nothing is bigger than MAXVALUE.
+		return errors.Trace(ErrReorgOutsideRange)
+	}
+	if strings.EqualFold(newEnd[len(newEnd)-1], partitionMaxValue) {
+		return nil
+	}
+	pi := tblInfo.Partition
+	if len(pi.Columns) > 0 {
+		return errors.Trace(ErrReorgOutsideRange)
+	}
+	isUnsigned := isColUnsigned(tblInfo.Columns, pi)
+	oldVal, _, err := getRangeValue(ctx, oldEnd[0], isUnsigned)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	newVal, _, err := getRangeValue(ctx, newEnd[0], isUnsigned)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if isUnsigned {
+		if newVal.(uint64) < oldVal.(uint64) {
+			return errors.Trace(ErrReorgOutsideRange)
+		}
+	} else if newVal.(int64) < oldVal.(int64) {
+		return errors.Trace(ErrReorgOutsideRange)
+	}
+	return nil
+}
+
+func isSameRangeEnd(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !strings.EqualFold(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
 // updateDroppingPartitionInfo move dropping partitions to DroppingDefinitions, and return partitionIDs
 func updateDroppingPartitionInfo(tblInfo *model.TableInfo, partLowerNames []string) []int64 {
 	oldDefs := tblInfo.Partition.Definitions
@@ -1140,6 +1232,516 @@ func (w *worker) onDropTablePartition(d *ddlCtx, t *meta.Meta, job *model.Job) (
 	return ver, errors.Trace(err)
 }
 
+// onReorganizePartition reorganizes some partitions of a table into new partitions.
+// It also handles ALTER TABLE ... PARTITION BY and REMOVE PARTITIONING, which reorganize
+// all the partitions of a table, a non-partitioned table being seen as a single partition.
+// The new partitions are kept in AddingDefinitions and the old ones in DroppingDefinitions,
+// while PartitionInfo.DDLState tells the table layer where the rows should be double written:
+//  none -> delete only: deletes are applied to the new partitions.
+//  delete only -> write only: all changes are double written to the new partitions.
+//  write only -> write reorganization: copy the old rows into the new partitions.
+//  write reorganization -> delete reorganization: the new partitions replace the old ones,
+//     changes are double written to the old partitions for the servers not seeing the new ones yet.
+//  delete reorganization -> none: the old partitions are removed by delete-range.
+func (w *worker) onReorganizePartition(d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, _ error) {
+	var partNames []string
+	partInfo := &model.PartitionInfo{}
+	if err := job.DecodeArgs(&partNames, &partInfo); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	if job.IsRollingback() {
+		return rollbackReorganizePartition(d, t, job, tblInfo)
+	}
+
+	pi := tblInfo.GetPartitionInfo()
+	switch job.SchemaState {
+	case model.StateNone:
+		if pi == nil && job.Type == model.ActionAlterTablePartitioning {
+			// The rows of the non-partitioned table are kept under the table ID,
+			// which is used as its single partition until the new partitions replace it.
+			pi = &model.PartitionInfo{
+				Type:        model.PartitionTypeNone,
+				Enable:      true,
+				Num:         1,
+				Definitions: []model.PartitionDefinition{{ID: tblInfo.ID, Name: model.NewCIStr("pFullTable")}},
+			}
+			tblInfo.Partition = pi
+		}
+		if pi == nil {
+			job.State = model.JobStateCancelled
+			return ver, errors.Trace(ErrPartitionMgmtOnNonpartitioned)
+		}
+		droppingDefs := pi.Definitions
+		if job.Type == model.ActionReorganizePartition {
+			droppingDefs, err = checkReorganizePartitionNames(pi, partNames)
+			if err != nil {
+				job.State = model.JobStateCancelled
+				return ver, errors.Trace(err)
+			}
+		}
+		pi.AddingDefinitions = partInfo.Definitions
+		pi.DroppingDefinitions = droppingDefs
+		pi.DDLAction = job.Type
+		pi.DDLState = model.StateDeleteOnly
+		pi.DDLType = partInfo.Type
+		pi.DDLExpr = partInfo.Expr
+		pi.DDLColumns = partInfo.Columns
+
+		bundles, err := newBundlesFromPartitionDefs(t, job, partInfo.Definitions)
+		if err != nil {
+			job.State = model.JobStateCancelled
+			return ver, errors.Trace(err)
+		}
+		if err = infosync.PutRuleBundles(context.TODO(), bundles); err != nil {
+			job.State = model.JobStateCancelled
+			return ver, errors.Wrapf(err, "failed to notify PD the placement rules")
+		}
+
+		ver, err = updateVersionAndTableInfoWithCheck(t, job, tblInfo, true)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		// none -> delete only
+		job.SchemaState = model.StateDeleteOnly
+	case model.StateDeleteOnly:
+		// delete only -> write only
+		pi.DDLState = model.StateWriteOnly
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		job.SchemaState = model.StateWriteOnly
+	case model.StateWriteOnly:
+		// write only -> reorganization
+		pi.DDLState = model.StateWriteReorganization
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		// Initialize SnapshotVer to 0 for later reorganization check.
+		job.SnapshotVer = 0
+		job.SchemaState = model.StateWriteReorganization
+	case model.StateWriteReorganization:
+		tbl, err := getTable(d.store, job.SchemaID, tblInfo)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		physicalTableIDs := getPartitionIDsFromDefinitions(pi.DroppingDefinitions)
+		// The rows are copied together with their index entries, so one element is enough to record the progress.
+		elements := []*meta.Element{{ID: tblInfo.ID, TypeKey: meta.ColumnElementKey}}
+		reorgInfo, err := getReorgInfoFromPartitions(d, t, job, tbl, physicalTableIDs, elements)
+		if err != nil || reorgInfo.first {
+			// If we run reorg firstly, we should update the job snapshot version
+			// and then run the reorg next time.
+			return ver, errors.Trace(err)
+		}
+		err = w.runReorgJob(t, reorgInfo, tbl.Meta(), d.lease, func() (reorgErr error) {
+			defer tidbutil.Recover(metrics.LabelDDL, "onReorganizePartition",
+				func() {
+					reorgErr = errCancelledDDLJob.GenWithStack("reorganize partition for table `%v` panic", tblInfo.Name)
+				}, false)
+			return w.reorgPartitionData(tbl.(table.PartitionedTable), physicalTableIDs, reorgInfo)
+		})
+		if err != nil {
+			if errWaitReorgTimeout.Equal(err) {
+				// if timeout, we should return, check for the owner and re-wait job done.
+				return ver, nil
+			}
+			if kv.ErrKeyExists.Equal(err) || errCancelledDDLJob.Equal(err) || errCantDecodeRecord.Equal(err) ||
+				table.ErrNoPartitionForGivenValue.Equal(err) {
+				logutil.BgLogger().Warn("[ddl] run reorganize partition job failed, convert job to rollback", zap.String("job", job.String()), zap.Error(err))
+				job.State = model.JobStateRollingback
+				if err1 := t.RemoveDDLReorgHandle(job, reorgInfo.elements); err1 != nil {
+					logutil.BgLogger().Warn("[ddl] run reorganize partition job failed, convert job to rollback, RemoveDDLReorgHandle failed", zap.String("job", job.String()), zap.Error(err1))
+				}
+			}
+			// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+			w.reorgCtx.cleanNotifyReorgCancel()
+			return ver, errors.Trace(err)
+		}
+		// Clean up the channel of notifyCancelReorgJob. Make sure it can't affect other jobs.
+		w.reorgCtx.cleanNotifyReorgCancel()
+
+		// reorganization -> delete reorganization
+		// Make the new partitions visible, the old ones are still double written.
+		pi.Definitions = tables.ReplacePartitionDefinitions(pi.Definitions, pi.DroppingDefinitions, pi.AddingDefinitions)
+		pi.Num = uint64(len(pi.Definitions))
+		pi.Type, pi.DDLType = pi.DDLType, pi.Type
+		pi.Expr, pi.DDLExpr = pi.DDLExpr, pi.Expr
+		pi.Columns, pi.DDLColumns = pi.DDLColumns, pi.Columns
+		pi.DDLState = model.StateDeleteReorganization
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		job.SchemaState = model.StateDeleteReorganization
+	case model.StateDeleteReorganization:
+		physicalTableIDs := getPartitionIDsFromDefinitions(pi.DroppingDefinitions)
+		bundleIDs := physicalTableIDs
+		if pi.DDLType == model.PartitionTypeNone {
+			// The single partition of the non-partitioned table shares the table ID, so does its placement bundle.
+			bundleIDs = nil
+		}
+		err = dropRuleBundles(d, bundleIDs)
+		if err != nil {
+			return ver, errors.Wrapf(err, "failed to notify PD the placement rules")
+		}
+		err = dropLabelRules(d, job.SchemaName, tblInfo.Name.L, getDroppedPartitionNames(pi))
+		if err != nil {
+			return ver, errors.Wrapf(err, "failed to notify PD the label rules")
+		}
+		addingDefs := pi.AddingDefinitions
+		pi.AddingDefinitions = nil
+		pi.DroppingDefinitions = nil
+		pi.DDLAction = model.ActionNone
+		pi.DDLState = model.StateNone
+		pi.DDLType = model.PartitionTypeNone
+		pi.DDLExpr = ""
+		pi.DDLColumns = nil
+		pi.GCPartitionStates()
+		// used by ApplyDiff in updateSchemaVersion
+		job.CtxVars = []interface{}{physicalTableIDs}
+		if job.Type == model.ActionRemovePartitioning {
+			if err = removePartitionInfo(d, t, job, tblInfo); err != nil {
+				return ver, errors.Trace(err)
+			}
+			job.CtxVars = append(job.CtxVars, tblInfo.ID)
+		}
+		ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+		if err != nil {
+			return ver, errors.Trace(err)
+		}
+		job.FinishTableJob(model.JobStateDone, model.StateNone, ver, tblInfo)
+		if job.Type == model.ActionRemovePartitioning {
+			asyncNotifyEvent(d, &util.Event{Tp: job.Type, TableInfo: tblInfo})
+		} else {
+			asyncNotifyEvent(d, &util.Event{Tp: job.Type, TableInfo: tblInfo, PartInfo: &model.PartitionInfo{Definitions: addingDefs}})
+		}
+		// A background job will be created to delete old partition data.
+		job.Args = []interface{}{physicalTableIDs}
+	default:
+		err = ErrInvalidDDLState.GenWithStackByArgs("partition", job.SchemaState)
+	}
+	return ver, errors.Trace(err)
+}
+
+// getDroppedPartitionNames returns the names of the reorganized partitions which are not reused by the new partitions.
+func getDroppedPartitionNames(pi *model.PartitionInfo) []string {
+	names := make([]string, 0, len(pi.DroppingDefinitions))
+	for _, def := range pi.DroppingDefinitions {
+		reused := false
+		for _, newDef := range pi.AddingDefinitions {
+			if newDef.Name.L == def.Name.L {
+				reused = true
+				break
+			}
+		}
+		if !reused {
+			names = append(names, def.Name.L)
+		}
+	}
+	return names
+}
+
+// removePartitionInfo turns the table into a non-partitioned table at the end of REMOVE PARTITIONING.
+// The rows are already in its single partition, so the partition ID becomes the new table ID.
+func removePartitionInfo(d *ddlCtx, t *meta.Meta, job *model.Job, tblInfo *model.TableInfo) error {
+	oldTableID := tblInfo.ID
+	newTableID := tblInfo.Partition.Definitions[0].ID
+	autoIDs, err := t.GetAutoIDAccessors(job.SchemaID, oldTableID).Get()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	err = t.DropTableOrView(job.SchemaID, oldTableID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	err = t.GetAutoIDAccessors(job.SchemaID, oldTableID).Del()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	is := d.infoCache.GetLatest()
+	if oldBundle, ok := is.BundleByName(placement.GroupID(oldTableID)); ok {
+		bundles := []*placement.Bundle{oldBundle.Clone().Reset(placement.RuleIndexTable, []int64{newTableID})}
+		if err = infosync.PutRuleBundles(context.TODO(), bundles); err != nil {
+			return errors.Wrapf(err, "failed to notify PD the placement rules")
+		}
+		if err = dropRuleBundles(d, []int64{oldTableID}); err != nil {
+			return errors.Wrapf(err, "failed to notify PD the placement rules")
+		}
+	}
+
+	tblInfo.ID = newTableID
+	tblInfo.Partition = nil
+	tableRuleID, partRuleIDs, oldRuleIDs, oldRules, err := getOldLabelRules(tblInfo, job.SchemaName, tblInfo.Name.L)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get old label rules from PD")
+	}
+	err = updateLabelRules(job, tblInfo, oldRules, tableRuleID, partRuleIDs, oldRuleIDs, newTableID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to update the label rule to PD")
+	}
+
+	err = t.CreateTableOrView(job.SchemaID, tblInfo)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(t.GetAutoIDAccessors(job.SchemaID, newTableID).Put(autoIDs))
+}
+
+// rollbackReorganizePartition removes the new partitions from the table, their data is removed by delete-range.
+func rollbackReorganizePartition(d *ddlCtx, t *meta.Meta, job *model.Job, tblInfo *model.TableInfo) (ver int64, err error) {
+	pi := tblInfo.GetPartitionInfo()
+	physicalTableIDs := getPartitionIDsFromDefinitions(pi.AddingDefinitions)
+	err = dropRuleBundles(d, physicalTableIDs)
+	if err != nil {
+		return ver, errors.Wrapf(err, "failed to notify PD the placement rules")
+	}
+	if pi.Type == model.PartitionTypeNone {
+		// The table was not partitioned before the job.
+		tblInfo.Partition = nil
+	} else {
+		pi.AddingDefinitions = nil
+		pi.DroppingDefinitions = nil
+		pi.DDLAction = model.ActionNone
+		pi.DDLState = model.StateNone
+		pi.DDLType = model.PartitionTypeNone
+		pi.DDLExpr = ""
+		pi.DDLColumns = nil
+	}
+	ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.FinishTableJob(model.JobStateRollbackDone, model.StateNone, ver, tblInfo)
+	job.Args = []interface{}{physicalTableIDs}
+	return ver, nil
+}
+
+// reorgPartitionData copies the rows of the reorganized partitions into the new partitions.
+func (w *worker) reorgPartitionData(tbl table.PartitionedTable, partitionIDs []int64, reorgInfo *reorgInfo) error {
+	var err error
+	var finish bool
+	for !finish {
+		p := tbl.GetPartition(reorgInfo.PhysicalTableID)
+		if p == nil {
+			return errCancelledDDLJob.GenWithStack("Can not find partition id %d for table %d", reorgInfo.PhysicalTableID, tbl.Meta().ID)
+		}
+		logutil.BgLogger().Info("[ddl] start to reorganize partition", zap.String("job", reorgInfo.Job.String()), zap.String("reorgInfo", reorgInfo.String()))
+		err = w.writePhysicalTableRecord(p, typeReorgPartitionWorker, nil, nil, nil, reorgInfo)
+		if err != nil {
+			break
+		}
+		finish, err = w.updateReorgInfoForPartitions(tbl, reorgInfo, partitionIDs)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return errors.Trace(err)
+}
+
+// getReorganizedTable returns the table as it will be after the partitions are reorganized.
+func getReorganizedTable(store kv.Storage, schemaID int64, tblInfo *model.TableInfo) (table.PartitionedTable, error) {
+	pi := *tblInfo.Partition
+	pi.Definitions = tables.ReplacePartitionDefinitions(pi.Definitions, pi.DroppingDefinitions, pi.AddingDefinitions)
+	pi.Num = uint64(len(pi.Definitions))
+	pi.Type = pi.DDLType
+	pi.Expr = pi.DDLExpr
+	pi.Columns = pi.DDLColumns
+	pi.AddingDefinitions = nil
+	pi.DroppingDefinitions = nil
+	pi.DDLAction = model.ActionNone
+	pi.DDLState = model.StateNone
+	newTblInfo := *tblInfo
+	newTblInfo.Partition = &pi
+	tbl, err := getTable(store, schemaID, &newTblInfo)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return tbl.(table.PartitionedTable), nil
+}
+
+type reorgPartitionWorker struct {
+	*backfillWorker
+	reorgedTbl    table.PartitionedTable
+	metricCounter prometheus.Counter
+
+	// The following attributes are used to reduce memory allocation.
+	rowKeys     []kv.Key
+	handles     []kv.Handle
+	defaultVals []types.Datum
+	rowMap      map[int64]types.Datum
+	rowDecoder  *decoder.RowDecoder
+
+	sqlMode mysql.SQLMode
+}
+
+func newReorgPartitionWorker(sessCtx sessionctx.Context, worker *worker, id int, t table.PhysicalTable, schemaID int64, decodeColMap map[int64]decoder.Column, sqlMode mysql.SQLMode) (*reorgPartitionWorker, error) {
+	reorgedTbl, err := getReorganizedTable(sessCtx.GetStore(), schemaID, t.Meta())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	rowDecoder := decoder.NewRowDecoder(t, t.WritableCols(), decodeColMap)
+	return &reorgPartitionWorker{
+		backfillWorker: newBackfillWorker(sessCtx, worker, id, t),
+		reorgedTbl:     reorgedTbl,
+		metricCounter:  metrics.BackfillTotalCounter.WithLabelValues("reorg_partition_speed"),
+		defaultVals:    make([]types.Datum, len(t.WritableCols())),
+		rowMap:         make(map[int64]types.Datum, len(decodeColMap)),
+		rowDecoder:     rowDecoder,
+		sqlMode:        sqlMode,
+	}, nil
+}
+
+func (w *reorgPartitionWorker) AddMetricInfo(cnt float64) {
+	w.metricCounter.Add(cnt)
+}
+
+// BackfillDataInTxn copies the rows of the handle range into the new partitions in a transaction,
+// together with their index entries.
+func (w *reorgPartitionWorker) BackfillDataInTxn(handleRange reorgBackfillTask) (taskCtx backfillTaskContext, errInTxn error) {
+	oprStartTime := time.Now()
+	errInTxn = kv.RunInNewTxn(context.Background(), w.sessCtx.GetStore(), true, func(ctx context.Context, txn kv.Transaction) error {
+		taskCtx.addedCount = 0
+		taskCtx.scanCount = 0
+		txn.SetOption(kv.Priority, w.priority)
+
+		nextKey, taskDone, err := w.fetchRowKeys(txn, handleRange)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		taskCtx.nextKey = nextKey
+		taskCtx.done = taskDone
+
+		// The rows are read again in this transaction, they may have been changed since the snapshot.
+		vals, err := txn.BatchGet(ctx, w.rowKeys)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for i, key := range w.rowKeys {
+			taskCtx.scanCount++
+			val, ok := vals[string(key)]
+			if !ok {
+				// The row is deleted, the deletion is already double written to the new partitions.
+				continue
+			}
+			// Lock the row key so that the transaction is retried if the row is changed concurrently.
+			err = txn.LockKeys(ctx, new(kv.LockCtx), key)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			err = w.copyRecord(txn, w.handles[i], val)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			taskCtx.addedCount++
+		}
+		return nil
+	})
+	logSlowOperations(time.Since(oprStartTime), "ReorgPartitionBackfillDataInTxn", 3000)
+
+	return
+}
+
+// fetchRowKeys fetches at most w.batchCnt row keys of the task range from the snapshot.
+func (w *reorgPartitionWorker) fetchRowKeys(txn kv.Transaction, taskRange reorgBackfillTask) (kv.Key, bool, error) {
+	w.rowKeys = w.rowKeys[:0]
+	w.handles = w.handles[:0]
+	startTime := time.Now()
+
+	// taskDone means that the reorged handle is out of taskRange.endHandle.
+	taskDone := false
+	var lastAccessedKey kv.Key
+	err := iterateSnapshotRows(w.sessCtx.GetStore(), w.priority, w.table, txn.StartTS(), taskRange.startKey, taskRange.endKey,
+		func(handle kv.Handle, recordKey kv.Key, rawRow []byte) (bool, error) {
+			taskDone = recordKey.Cmp(taskRange.endKey) > 0
+			if taskDone || len(w.rowKeys) >= w.batchCnt {
+				return false, nil
+			}
+			w.rowKeys = append(w.rowKeys, recordKey)
+			w.handles = append(w.handles, handle)
+			lastAccessedKey = recordKey
+			if recordKey.Cmp(taskRange.endKey) == 0 {
+				// If taskRange.endIncluded == false, we will not reach here when handle == taskRange.endHandle
+				taskDone = true
+				return false, nil
+			}
+			return true, nil
+		})
+
+	if len(w.rowKeys) == 0 {
+		taskDone = true
+	}
+
+	logutil.BgLogger().Debug("[ddl] txn fetches handle info", zap.Uint64("txnStartTS", txn.StartTS()),
+		zap.String("taskRange", taskRange.String()), zap.Duration("takeTime", time.Since(startTime)))
+	if !taskDone {
+		// The task is not done. So we need to pick the last processed entry's handle and add one.
+		return lastAccessedKey.Next(), taskDone, errors.Trace(err)
+	}
+	return taskRange.endKey.Next(), taskDone, errors.Trace(err)
+}
+
+// copyRecord writes the row and its index entries into the new partition the row belongs to.
+func (w *reorgPartitionWorker) copyRecord(txn kv.Transaction, handle kv.Handle, rawRow []byte) error {
+	_, err := w.rowDecoder.DecodeAndEvalRowWithMap(w.sessCtx, handle, rawRow, time.UTC, timeutil.SystemLocation(), w.rowMap)
+	if err != nil {
+		return errors.Trace(errCantDecodeRecord.GenWithStackByArgs("partition", err))
+	}
+	cols := w.table.WritableCols()
+	row := make([]types.Datum, len(cols))
+	for i, col := range cols {
+		val, ok := w.rowMap[col.ID]
+		if !ok {
+			val, err = tables.GetColDefaultValue(w.sessCtx, col, w.defaultVals)
+			if err != nil {
+				return errors.Trace(err)
+			}
+		}
+		row[i] = val
+	}
+	// If there are generated column, rowDecoder will use column value that not in the row to calculate
+	// the generated value, so we need to clear up the reusing map.
+	for id := range w.rowMap {
+		delete(w.rowMap, id)
+	}
+
+	p, err := w.reorgedTbl.GetPartitionByRow(w.sessCtx, row)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	err = txn.Set(tablecodec.EncodeRecordKey(p.RecordPrefix(), handle), rawRow)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	tblInfo := w.table.Meta()
+	for _, idx := range p.Indices() {
+		if tblInfo.IsCommonHandle && idx.Meta().Primary {
+			continue
+		}
+		idxVals, err := idx.FetchValues(row, nil)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		rsData := tables.TryGetHandleRestoredDataWrapper(p, row, nil, idx.Meta())
+		dupHandle, err := idx.Create(w.sessCtx, txn, idxVals, handle, rsData)
+		if err != nil {
+			if kv.ErrKeyExists.Equal(err) && handle.Equal(dupHandle) {
+				// The index entry is already double written.
+				continue
+			}
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
 // onTruncateTablePartition truncates old partition meta.
 func onTruncateTablePartition(d *ddlCtx, t *meta.Meta, job *model.Job) (int64, error) {
 	var ver int64
@@ -1697,6 +2299,41 @@ func checkPartitioningKeysConstraints(sctx sessionctx.Context, s *ast.CreateTabl
 	return nil
 }
 
+// checkUniqueKeysIncludePartKey checks every unique key of the table uses every column of the partitioning pi.
+// Global indexes are not built when the partitioning of an existing table is changed.
+func checkUniqueKeysIncludePartKey(pi *model.PartitionInfo, tblInfo *model.TableInfo) error {
+	for _, index := range tblInfo.Indices {
+		if !index.Unique {
+			continue
+		}
+		ok, err := checkPartitionKeysConstraint(pi, index.Columns, tblInfo)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if !ok {
+			if index.Primary {
+				return ErrUniqueKeyNeedAllFieldsInPf.GenWithStackByArgs("PRIMARY KEY")
+			}
+			return ErrUniqueKeyNeedAllFieldsInPf.GenWithStackByArgs("UNIQUE INDEX")
+		}
+	}
+	// when PKIsHandle, tblInfo.Indices will not contain the primary key.
+	if tblInfo.PKIsHandle {
+		indexCols := []*model.IndexColumn{{
+			Name:   tblInfo.GetPkName(),
+			Length: types.UnspecifiedLength,
+		}}
+		ok, err := checkPartitionKeysConstraint(pi, indexCols, tblInfo)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if !ok {
+			return ErrUniqueKeyNeedAllFieldsInPf.GenWithStackByArgs("PRIMARY KEY")
+		}
+	}
+	return nil
+}
+
 func checkPartitionKeysConstraint(pi *model.PartitionInfo, indexColumns []*model.IndexColumn, tblInfo *model.TableInfo) (bool, error) {
 	var (
 		partCols []*model.ColumnInfo
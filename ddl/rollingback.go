@@ -151,7 +151,7 @@ func rollingbackModifyColumn(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job)
 }
 
 func rollingbackAddColumn(t *meta.Meta, job *model.Job) (ver int64, err error) {
-	tblInfo, columnInfo, col, _, _, err := checkAddColumn(t, job)
+	tblInfo, columnInfo, col, _, _, _, err := checkAddColumn(t, job)
 	if err != nil {
 		return ver, errors.Trace(err)
 	}
@@ -175,7 +175,7 @@ func rollingbackAddColumn(t *meta.Meta, job *model.Job) (ver int64, err error) {
 }
 
 func rollingbackAddColumns(t *meta.Meta, job *model.Job) (ver int64, err error) {
-	tblInfo, columnInfos, _, _, _, _, err := checkAddColumns(t, job)
+	tblInfo, columnInfos, _, _, _, _, _, err := checkAddColumns(t, job)
 	if err != nil {
 		return ver, errors.Trace(err)
 	}
@@ -389,6 +389,30 @@ func rollingbackDropTablePartition(t *meta.Meta, job *model.Job) (ver int64, err
 	return cancelOnlyNotHandledJob(job)
 }
 
+func rollingbackReorganizePartition(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job) (ver int64, err error) {
+	switch job.SchemaState {
+	case model.StateNone:
+		// The partition info isn't changed yet, the job can be cancelled directly.
+		job.State = model.JobStateCancelled
+		return ver, errCancelledDDLJob
+	case model.StateWriteReorganization:
+		// If the value of SnapshotVer isn't zero, it means the reorg workers have been started.
+		if job.SnapshotVer != 0 {
+			logutil.Logger(w.logCtx).Info("[ddl] run the cancelling DDL job", zap.String("job", job.String()))
+			w.reorgCtx.notifyReorgCancel()
+			// Give the job one more round to run, the errCancelledDDLJob should be fetched from the bottom up.
+			return w.onReorganizePartition(d, t, job)
+		}
+	case model.StateDeleteReorganization:
+		// The new partitions are already public, the job couldn't be cancelled.
+		job.State = model.JobStateRunning
+		return ver, nil
+	}
+	// The new partitions will be removed in the next round.
+	job.State = model.JobStateRollingback
+	return ver, errCancelledDDLJob
+}
+
 func rollingbackDropSchema(t *meta.Meta, job *model.Job) error {
 	dbInfo, err := checkSchemaExistAndCancelNotExistJob(t, job)
 	if err != nil {
@@ -431,6 +455,30 @@ func cancelOnlyNotHandledJob(job *model.Job) (ver int64, err error) {
 	return ver, nil
 }
 
+func rollingbackAddCheckConstraint(t *meta.Meta, job *model.Job) (ver int64, err error) {
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	if job.SchemaState == model.StateNone {
+		job.State = model.JobStateCancelled
+		return ver, errCancelledDDLJob
+	}
+
+	var constraintInfo model.ConstraintInfo
+	if err = job.DecodeArgs(&constraintInfo); err != nil {
+		job.State = model.JobStateCancelled
+		return ver, errors.Trace(err)
+	}
+	removeCheckConstraint(tblInfo, constraintInfo.Name)
+	ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
+	if err != nil {
+		return ver, errors.Trace(err)
+	}
+	job.FinishTableJob(model.JobStateRollbackDone, model.StateNone, ver, tblInfo)
+	return ver, errCancelledDDLJob
+}
+
 func rollingbackTruncateTable(t *meta.Meta, job *model.Job) (ver int64, err error) {
 	_, err = getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
 	if err != nil {
@@ -463,6 +511,8 @@ func convertJob2RollbackJob(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job)
 		err = rollingbackDropTableOrView(t, job)
 	case model.ActionDropTablePartition:
 		ver, err = rollingbackDropTablePartition(t, job)
+	case model.ActionReorganizePartition, model.ActionAlterTablePartitioning, model.ActionRemovePartitioning:
+		ver, err = rollingbackReorganizePartition(w, d, t, job)
 	case model.ActionDropSchema:
 		err = rollingbackDropSchema(t, job)
 	case model.ActionRenameIndex:
@@ -471,12 +521,17 @@ func convertJob2RollbackJob(w *worker, d *ddlCtx, t *meta.Meta, job *model.Job)
 		ver, err = rollingbackTruncateTable(t, job)
 	case model.ActionModifyColumn:
 		ver, err = rollingbackModifyColumn(w, d, t, job)
+	case model.ActionMultiSchemaChange:
+		err = rollingbackMultiSchemaChange(job)
+	case model.ActionAddCheckConstraint:
+		ver, err = rollingbackAddCheckConstraint(t, job)
 	case model.ActionRebaseAutoID, model.ActionShardRowID, model.ActionAddForeignKey,
 		model.ActionDropForeignKey, model.ActionRenameTable, model.ActionRenameTables,
 		model.ActionModifyTableCharsetAndCollate, model.ActionTruncateTablePartition,
 		model.ActionModifySchemaCharsetAndCollate, model.ActionRepairTable,
 		model.ActionModifyTableAutoIdCache, model.ActionAlterIndexVisibility,
-		model.ActionExchangeTablePartition, model.ActionModifySchemaDefaultPlacement:
+		model.ActionExchangeTablePartition, model.ActionModifySchemaDefaultPlacement,
+		model.ActionDropCheckConstraint, model.ActionAlterCheckConstraint:
 		ver, err = cancelOnlyNotHandledJob(job)
 	default:
 		job.State = model.JobStateCancelled
@@ -953,6 +953,12 @@ func onModifyTableComment(t *meta.Meta, job *model.Job) (ver int64, _ error) {
 		return ver, errors.Trace(err)
 	}
 
+	if job.MultiSchemaInfo != nil && job.MultiSchemaInfo.Revertible {
+		// Modify the comment after all the sub-jobs of the multi-schema change become non-revertible.
+		job.MarkNonRevertible()
+		return ver, nil
+	}
+
 	tblInfo.Comment = comment
 	ver, err = updateVersionAndTableInfo(t, job, tblInfo, true)
 	if err != nil {
@@ -1203,7 +1209,10 @@ func updateVersionAndTableInfo(t *meta.Meta, job *model.Job, tblInfo *model.Tabl
 		default:
 		}
 	})
-	if shouldUpdateVer {
+	if shouldUpdateVer && job.MultiSchemaInfo != nil && job.MultiSchemaInfo.SkipVersion {
+		// The schema version is updated once for all the sub-jobs stepped in the multi-schema change.
+		job.MultiSchemaInfo.SchemaChanged = true
+	} else if shouldUpdateVer {
 		ver, err = updateSchemaVersion(t, job)
 		if err != nil {
 			return 0, errors.Trace(err)
@@ -1198,6 +1198,29 @@ func (do *Domain) PlanReplayerLoop() {
 	}()
 }
 
+// UserResourceUsageLoop creates a goroutine that calls flush in a loop to write the resource usage counted by
+// this instance to mysql.user_resource_usage, it should be called only once in BootstrapSession.
+func (do *Domain) UserResourceUsageLoop(interval time.Duration, flush func()) {
+	do.wg.Add(1)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer func() {
+			ticker.Stop()
+			do.wg.Done()
+			logutil.BgLogger().Info("UserResourceUsageLoop exited.")
+			util.Recover(metrics.LabelDomain, "UserResourceUsageLoop", nil, false)
+		}()
+		for {
+			select {
+			case <-do.exit:
+				return
+			case <-ticker.C:
+				flush()
+			}
+		}
+	}()
+}
+
 // StatsHandle returns the statistic handle.
 func (do *Domain) StatsHandle() *handle.Handle {
 	return (*handle.Handle)(atomic.LoadPointer(&do.statsHandle))
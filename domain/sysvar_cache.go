@@ -233,6 +233,8 @@ func (do *Domain) checkEnableServerGlobalVar(name, sVal string) {
 		variable.TopSQLVariable.ReportIntervalSeconds.Store(val)
 	case variable.TiDBRestrictedReadOnly:
 		variable.RestrictedReadOnly.Store(variable.TiDBOptOn(sVal))
+	case variable.ProtocolCompressionAlgorithms:
+		variable.ProtocolCompressionAlgorithmsValue.Store(sVal)
 	case variable.TiDBStoreLimit:
 		var val int64
 		val, err = strconv.ParseInt(sVal, 10, 64)
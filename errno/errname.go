@@ -246,6 +246,7 @@ var MySQLErrName = map[uint16]*mysql.ErrMessage{
 	ErrVarCantBeRead:                            mysql.Message("Variable '%-.64s' can only be set, not read", nil),
 	ErrCantUseOptionHere:                        mysql.Message("Incorrect usage/placement of '%s'", nil),
 	ErrNotSupportedYet:                          mysql.Message("This version of TiDB doesn't yet support '%s'", nil),
+	ErrMasterFatalErrorReadingBinlog:            mysql.Message("Got fatal error %d from master when reading data from binary log: '%-.512s'", nil),
 	ErrIncorrectGlobalLocalVar:                  mysql.Message("Variable '%-.192s' is a %s variable", nil),
 	ErrWrongFkDef:                               mysql.Message("Incorrect foreign key definition for '%-.192s': %s", nil),
 	ErrKeyRefDoNotMatchTableRef:                 mysql.Message("Key reference and table reference don't match", nil),
@@ -835,6 +836,7 @@ var MySQLErrName = map[uint16]*mysql.ErrMessage{
 	ErrGeneratedColumnRefAutoInc:                             mysql.Message("Generated column '%s' cannot refer to auto-increment column.", nil),
 	ErrWarnConflictingHint:                                   mysql.Message("Hint %s is ignored as conflicting/duplicated.", nil),
 	ErrUnresolvedHintName:                                    mysql.Message("Unresolved name '%s' for %s hint", nil),
+	ErrForeignKeyCascadeDepthExceeded:                        mysql.Message("Foreign key cascade delete/update exceeds max depth of %v.", nil),
 	ErrInvalidFieldSize:                                      mysql.Message("Invalid size for column '%s'.", nil),
 	ErrInvalidArgumentForLogarithm:                           mysql.Message("Invalid argument for logarithm", nil),
 	ErrAggregateOrderNonAggQuery:                             mysql.Message("Expression #%d of ORDER BY contains aggregate function and applies to the result of a non-aggregated query", nil),
@@ -881,6 +883,11 @@ var MySQLErrName = map[uint16]*mysql.ErrMessage{
 	ErrMaxExecTimeExceeded:                                   mysql.Message("Query execution was interrupted, max_execution_time exceeded.", nil),
 	ErrLockAcquireFailAndNoWaitSet:                           mysql.Message("Statement aborted because lock(s) could not be acquired immediately and NOWAIT is set.", nil),
 	ErrNotHintUpdatable:                                      mysql.Message("Variable '%s' cannot be set using SET_VAR hint.", nil),
+	ErrCredentialsContradictToHistory:                        mysql.Message("Cannot use these credentials for '%s@%s' because they contradict the password history policy", nil),
+	ErrMissingJSONTableValue:                                 mysql.Message("Missing value for JSON_TABLE column '%s'", nil),
+	ErrWrongJSONTableValue:                                   mysql.Message("Can't store an array or an object in the scalar column '%s' of JSON_TABLE '%s'.", nil),
+	ErrJSONTableForbiddenJoinType:                            mysql.Message("INNER or LEFT JOIN must be used for LATERAL references made by '%s'", nil),
+	ErrJSONTableValueOutOfRange:                              mysql.Message("Value is out of range for JSON_TABLE's column '%s'", nil),
 	ErrDataTruncatedFunctionalIndex:                          mysql.Message("Data truncated for expression index '%s' at row %d", nil),
 	ErrDataOutOfRangeFunctionalIndex:                         mysql.Message("Value is out of range for expression index '%s' at row %d", nil),
 	ErrFunctionalIndexOnJSONOrGeometryFunction:               mysql.Message("Cannot create an expression index on a function that returns a JSON or GEOMETRY value", nil),
@@ -895,6 +902,17 @@ var MySQLErrName = map[uint16]*mysql.ErrMessage{
 	ErrFunctionalIndexOnField:                                mysql.Message("Expression index on a column is not supported. Consider using a regular index instead", nil),
 	ErrFKIncompatibleColumns:                                 mysql.Message("Referencing column '%s' in foreign key constraint '%s' are incompatible", nil),
 	ErrFunctionalIndexRowValueIsNotAllowed:                   mysql.Message("Expression of expression index '%s' cannot refer to a row value", nil),
+	ErrNonBooleanExprForCheckConstraint:                      mysql.Message("An expression of non-boolean type specified to a check constraint '%s'.", nil),
+	ErrColumnCheckConstraintReferencesOtherColumn:            mysql.Message("Column check constraint '%s' references other column.", nil),
+	ErrCheckConstraintNamedFunctionIsNotAllowed:              mysql.Message("An expression of a check constraint '%s' contains disallowed function: %s.", nil),
+	ErrCheckConstraintFunctionIsNotAllowed:                   mysql.Message("An expression of a check constraint '%s' contains disallowed function.", nil),
+	ErrCheckConstraintVariables:                              mysql.Message("An expression of a check constraint '%s' cannot refer to a user or system variable.", nil),
+	ErrCheckConstraintRowValueIsNotAllowed:                   mysql.Message("Check constraint '%s' cannot refer to a row value.", nil),
+	ErrCheckConstraintRefersAutoIncrementColumn:              mysql.Message("Check constraint '%s' cannot refer to an auto-increment column.", nil),
+	ErrCheckConstraintViolated:                               mysql.Message("Check constraint '%s' is violated.", nil),
+	ErrCheckConstraintRefersUnknownColumn:                    mysql.Message("Check constraint '%s' refers to non-existing column '%s'.", nil),
+	ErrCheckConstraintNotFound:                               mysql.Message("Check constraint '%s' is not found in the table.", nil),
+	ErrCheckConstraintDupName:                                mysql.Message("Duplicate check constraint name '%s'.", nil),
 	ErrDependentByFunctionalIndex:                            mysql.Message("Column '%s' has an expression index dependency and cannot be dropped or renamed", nil),
 	ErrInvalidJSONValueForFuncIndex:                          mysql.Message("Invalid JSON value for CAST for expression index '%s'", nil),
 	ErrJSONValueOutOfRangeForFuncIndex:                       mysql.Message("Out of range JSON value for CAST for expression index '%s'", nil),
@@ -902,6 +920,8 @@ var MySQLErrName = map[uint16]*mysql.ErrMessage{
 	ErrFunctionalIndexNotApplicable:                          mysql.Message("Cannot use expression index '%s' due to type or collation conversion", nil),
 	ErrUnsupportedConstraintCheck:                            mysql.Message("%s is not supported", nil),
 	ErrDynamicPrivilegeNotRegistered:                         mysql.Message("Dynamic privilege '%s' is not registered with the server.", nil),
+	ErrUserAccessDeniedForUserAccountBlockedByPasswordLock:   mysql.Message("Access denied for user '%s'@'%s'. Account is blocked for %s day(s) (%s day(s) remaining) due to %d consecutive failed logins.", nil),
+	ErrDependentByCheckConstraint:                            mysql.Message("Check constraint '%s' uses column '%s', hence column cannot be dropped or renamed.", nil),
 	ErrIllegalPrivilegeLevel:                                 mysql.Message("Illegal privilege level specified for %s", nil),
 	ErrCTERecursiveRequiresUnion:                             mysql.Message("Recursive Common Table Expression '%s' should contain a UNION", nil),
 	ErrCTERecursiveRequiresNonRecursiveFirst:                 mysql.Message("Recursive Common Table Expression '%s' should have one or more non-recursive query blocks followed by one or more recursive ones", nil),
@@ -1021,6 +1041,10 @@ var MySQLErrName = map[uint16]*mysql.ErrMessage{
 	ErrUnsupportedType:                     mysql.Message("Unsupported type %T", nil),
 	ErrAnalyzeMissIndex:                    mysql.Message("Index '%s' in field list does not exist in table '%s'", nil),
 	ErrAnalyzeMissColumn:                   mysql.Message("Column '%s' in ANALYZE column option does not exist in table '%s'", nil),
+	ErrCompressionAlgorithmNotAllowed:      mysql.Message("Compression algorithm '%s' is not permitted by protocol_compression_algorithms", nil),
+	ErrCannotMigrateSession:                mysql.Message("Cannot migrate the current session: %s", nil),
+	ErrInvalidSessionStates:                mysql.Message("Invalid session states: %s", nil),
+	ErrServerDraining:                      mysql.Message("TiDB server is draining, please reconnect to another TiDB server", nil),
 	ErrCartesianProductUnsupported:         mysql.Message("Cartesian product is unsupported", nil),
 	ErrPreparedStmtNotFound:                mysql.Message("Prepared statement not found", nil),
 	ErrWrongParamCount:                     mysql.Message("Wrong parameter count", nil),
@@ -1062,6 +1086,8 @@ var MySQLErrName = map[uint16]*mysql.ErrMessage{
 	ErrPlacementPolicyWithDirectOption: mysql.Message("Placement policy '%s' can't co-exist with direct placement options", nil),
 	ErrPlacementPolicyInUse:            mysql.Message("Placement policy '%-.192s' is still in use", nil),
 	ErrOptOnCacheTable:                 mysql.Message("'%s' is unsupported on cache tables.", nil),
+	ErrOperateSameColumn:               mysql.Message("Unsupported operate same column '%s' in one multi-schema change", nil),
+	ErrOperateSameIndex:                mysql.Message("Unsupported operate same index '%s' in one multi-schema change", nil),
 	// TiKV/PD errors.
 	ErrPDServerTimeout:           mysql.Message("PD server timeout", nil),
 	ErrTiKVServerTimeout:         mysql.Message("TiKV server timeout", nil),
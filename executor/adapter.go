@@ -178,11 +178,6 @@ func (a *recordSet) Close() error {
 	return err
 }
 
-// OnFetchReturned implements commandLifeCycle#OnFetchReturned
-func (a *recordSet) OnFetchReturned() {
-	a.stmt.LogSlowQuery(a.txnStartTS, a.lastErr == nil, true)
-}
-
 // TelemetryInfo records some telemetry information during execution.
 type TelemetryInfo struct {
 	UseNonRecursive bool
@@ -942,7 +937,10 @@ func (a *ExecStmt) LogSlowQuery(txnTS uint64, succ bool, hasMoreResults bool) {
 	enable := cfg.Log.EnableSlowLog
 	// if the level is Debug, or trace is enabled, print slow logs anyway
 	force := level <= zapcore.DebugLevel || trace.IsEnabled()
-	if (!enable || costTime < threshold) && !force {
+	// The statements killed by the memory killer are logged regardless of the threshold.
+	memoryKilled := sessVars.StmtCtx.MemoryKilled.Load()
+	isSlow := costTime >= threshold || len(memoryKilled) > 0
+	if (!enable || !isSlow) && !force {
 		return
 	}
 	sql := FormatSQL(a.GetTextToLog())
@@ -999,6 +997,7 @@ func (a *ExecStmt) LogSlowQuery(txnTS uint64, succ bool, hasMoreResults bool) {
 		ExecDetail:        execDetail,
 		MemMax:            memMax,
 		DiskMax:           diskMax,
+		MemoryKilled:      memoryKilled,
 		Succ:              succ,
 		Plan:              getPlanTree(a.Ctx, a.Plan),
 		PlanDigest:        planDigest.String(),
@@ -1024,7 +1023,7 @@ func (a *ExecStmt) LogSlowQuery(txnTS uint64, succ bool, hasMoreResults bool) {
 	if trace.IsEnabled() {
 		trace.Log(a.GoCtx, "details", sessVars.SlowLogFormat(slowItems))
 	}
-	if costTime < threshold {
+	if !isSlow {
 		logutil.SlowQueryLogger.Debug(sessVars.SlowLogFormat(slowItems))
 	} else {
 		logutil.SlowQueryLogger.Warn(sessVars.SlowLogFormat(slowItems))
@@ -1202,6 +1201,8 @@ func (a *ExecStmt) SummaryStmt(succ bool) {
 		Digest:          digest.String(),
 		PrevSQL:         prevSQL,
 		PrevSQLDigest:   prevSQLDigest,
+		QueryAttributes: sessVars.QueryAttributes,
+		ConnAttributes:  sessVars.ConnectionAttrs,
 		PlanGenerator:   planGenerator,
 		PlanDigest:      planDigest,
 		PlanDigestGen:   planDigestGen,
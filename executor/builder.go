@@ -90,6 +90,8 @@ type executorBuilder struct {
 	// isStaleness means whether this statement use stale read.
 	isStaleness      bool
 	readReplicaScope string
+	// fkCascadeDepth is the depth of the statement generated by the foreign key cascades, the top level statement is 0.
+	fkCascadeDepth int
 }
 
 // CTEStorages stores resTbl and iterInTbl for CTEExec.
@@ -226,6 +228,8 @@ func (b *executorBuilder) build(p plannercore.Plan) Executor {
 		return b.buildMemTable(v)
 	case *plannercore.PhysicalTableDual:
 		return b.buildTableDual(v)
+	case *plannercore.PhysicalJSONTable:
+		return b.buildJSONTable(v)
 	case *plannercore.PhysicalApply:
 		return b.buildApply(v)
 	case *plannercore.PhysicalMaxOneRow:
@@ -828,6 +832,15 @@ func (b *executorBuilder) buildInsert(v *plannercore.Insert) Executor {
 		b.err = err
 		return nil
 	}
+	if ivs.fkChecks, b.err = b.buildFKCheckExecs(v.Table, v.FKChecks); b.err != nil {
+		return nil
+	}
+	if ivs.fkCascades, b.err = b.buildFKCascadeExecs(v.Table, v.FKCascades); b.err != nil {
+		return nil
+	}
+	if ivs.checkConstraints, b.err = table.WritableConstraints(b.ctx, v.Table.Meta()); b.err != nil {
+		return nil
+	}
 
 	if v.IsReplace {
 		return b.buildReplace(ivs)
@@ -865,6 +878,9 @@ func (b *executorBuilder) buildLoadData(v *plannercore.LoadData) Executor {
 		ColumnsAndUserVars: v.ColumnsAndUserVars,
 		Ctx:                b.ctx,
 	}
+	if insertVal.checkConstraints, b.err = table.WritableConstraints(b.ctx, tbl.Meta()); b.err != nil {
+		return nil
+	}
 	columnNames := loadDataInfo.initFieldMappings()
 	err := loadDataInfo.initLoadColumns(columnNames)
 	if err != nil {
@@ -1417,6 +1433,23 @@ func (b *executorBuilder) buildProjection(v *plannercore.PhysicalProjection) Exe
 	return e
 }
 
+func (b *executorBuilder) buildJSONTable(v *plannercore.PhysicalJSONTable) Executor {
+	sc := &stmtctx.StatementContext{TimeZone: b.ctx.GetSessionVars().Location()}
+	offset := 0
+	root, err := buildJSONTableNode(sc, v.Path, v.Columns, v.Schema(), &offset)
+	if err != nil {
+		b.err = err
+		return nil
+	}
+	return &JSONTableExec{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID()),
+		doc:          v.Doc,
+		asName:       v.AsName,
+		root:         root,
+		sc:           sc,
+	}
+}
+
 func (b *executorBuilder) buildTableDual(v *plannercore.PhysicalTableDual) Executor {
 	if v.RowCount != 0 && v.RowCount != 1 {
 		b.err = errors.Errorf("buildTableDual failed, invalid row count for dual table: %v", v.RowCount)
@@ -1607,7 +1640,12 @@ func (b *executorBuilder) buildMemTable(v *plannercore.PhysicalMemTable) Executo
 			strings.ToLower(infoschema.TableClientErrorsSummaryByUser),
 			strings.ToLower(infoschema.TableClientErrorsSummaryByHost),
 			strings.ToLower(infoschema.TableAttributes),
-			strings.ToLower(infoschema.TablePlacementRules):
+			strings.ToLower(infoschema.TablePlacementRules),
+			strings.ToLower(infoschema.TableCheckConstraints),
+			strings.ToLower(infoschema.TableSessionConnectAttrs),
+			strings.ToLower(infoschema.TableUserResourceUsage),
+			strings.ToLower(infoschema.TableMemoryKillHistory),
+			strings.ToLower(infoschema.TableInstancePlanCache):
 			return &MemTableReaderExec{
 				baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID()),
 				table:        v.Table,
@@ -1779,6 +1817,8 @@ func (b *executorBuilder) buildApply(v *plannercore.PhysicalApply) Executor {
 	}
 	tupleJoiner := newJoiner(b.ctx, v.JoinType, v.InnerChildIdx == 0,
 		defaultValues, otherConditions, retTypes(leftChild), retTypes(rightChild), nil)
+	// The plan may come from the plan cache, so the session variables are checked again.
+	canUseCache := v.CanUseCache && b.ctx.GetSessionVars().MemQuotaApplyCache > 0
 	serialExec := &NestedLoopApplyExec{
 		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID(), outerExec, innerExec),
 		innerExec:    innerExec,
@@ -1789,12 +1829,12 @@ func (b *executorBuilder) buildApply(v *plannercore.PhysicalApply) Executor {
 		joiner:       tupleJoiner,
 		outerSchema:  v.OuterSchema,
 		ctx:          b.ctx,
-		canUseCache:  v.CanUseCache,
+		canUseCache:  canUseCache,
 	}
 	executorCounterNestedLoopApplyExec.Inc()
 
 	// try parallel mode
-	if v.Concurrency > 1 {
+	if v.Concurrency > 1 && b.ctx.GetSessionVars().EnableParallelApply {
 		innerExecs := make([]Executor, 0, v.Concurrency)
 		innerFilters := make([]expression.CNFExprs, 0, v.Concurrency)
 		corCols := make([][]*expression.CorrelatedColumn, 0, v.Concurrency)
@@ -1830,7 +1870,7 @@ func (b *executorBuilder) buildApply(v *plannercore.PhysicalApply) Executor {
 			joiners:      joiners,
 			corCols:      corCols,
 			concurrency:  v.Concurrency,
-			useCache:     v.CanUseCache,
+			useCache:     canUseCache,
 		}
 	}
 	return serialExec
@@ -1849,8 +1889,18 @@ func (b *executorBuilder) buildMaxOneRow(v *plannercore.PhysicalMaxOneRow) Execu
 }
 
 func (b *executorBuilder) buildUnionAll(v *plannercore.PhysicalUnionAll) Executor {
-	childExecs := make([]Executor, len(v.Children()))
-	for i, child := range v.Children() {
+	children := v.Children()
+	if v.DeferredPruning {
+		children, b.err = b.prunePartitionUnionChildren(v)
+		if b.err != nil {
+			return nil
+		}
+		if len(children) == 0 {
+			return &TableDualExec{baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID())}
+		}
+	}
+	childExecs := make([]Executor, len(children))
+	for i, child := range children {
 		childExecs[i] = b.build(child)
 		if b.err != nil {
 			return nil
@@ -1863,6 +1913,76 @@ func (b *executorBuilder) buildUnionAll(v *plannercore.PhysicalUnionAll) Executo
 	return e
 }
 
+// prunePartitionUnionChildren prunes the partitions of the partition union whose pruning is deferred to the
+// execution, the children whose partitions can't be found are kept.
+func (b *executorBuilder) prunePartitionUnionChildren(v *plannercore.PhysicalUnionAll) ([]plannercore.PhysicalPlan, error) {
+	var usedPartitions map[int64]struct{}
+	children := make([]plannercore.PhysicalPlan, 0, len(v.Children()))
+	for _, child := range v.Children() {
+		tblID, pid, partitionInfo := partitionOfUnionChild(child)
+		if partitionInfo == nil {
+			children = append(children, child)
+			continue
+		}
+		if usedPartitions == nil {
+			tmp, ok := b.is.TableByID(tblID)
+			if !ok {
+				return nil, infoschema.ErrTableNotExists.GenWithStackByArgs(b.ctx.GetSessionVars().CurrentDB, tblID)
+			}
+			partitions, err := partitionPruning(b.ctx, tmp.(table.PartitionedTable), partitionInfo.PruningConds,
+				partitionInfo.PartitionNames, partitionInfo.Columns, partitionInfo.ColumnNames)
+			if err != nil {
+				return nil, err
+			}
+			usedPartitions = make(map[int64]struct{}, len(partitions))
+			for _, partition := range partitions {
+				usedPartitions[partition.GetPhysicalID()] = struct{}{}
+			}
+		}
+		if _, ok := usedPartitions[pid]; ok {
+			children = append(children, child)
+		}
+	}
+	return children, nil
+}
+
+// partitionOfUnionChild returns the table ID, the partition ID and the partition information of the reader of the
+// partition union's child.
+func partitionOfUnionChild(p plannercore.PhysicalPlan) (int64, int64, *plannercore.PartitionInfo) {
+	for {
+		switch x := p.(type) {
+		case *plannercore.PhysicalTableReader:
+			if x.StoreType != kv.TiKV {
+				return 0, 0, nil
+			}
+			if ts := x.GetTableScan(); ts != nil {
+				if ok, pid := ts.IsPartition(); ok {
+					return ts.Table.ID, pid, &x.PartitionInfo
+				}
+			}
+			return 0, 0, nil
+		case *plannercore.PhysicalIndexReader:
+			if is, ok := x.IndexPlans[0].(*plannercore.PhysicalIndexScan); ok {
+				if ok, pid := is.IsPartition(); ok {
+					return is.Table.ID, pid, &x.PartitionInfo
+				}
+			}
+			return 0, 0, nil
+		case *plannercore.PhysicalIndexLookUpReader:
+			if is, ok := x.IndexPlans[0].(*plannercore.PhysicalIndexScan); ok {
+				if ok, pid := is.IsPartition(); ok {
+					return is.Table.ID, pid, &x.PartitionInfo
+				}
+			}
+			return 0, 0, nil
+		}
+		if len(p.Children()) != 1 {
+			return 0, 0, nil
+		}
+		p = p.Children()[0]
+	}
+}
+
 func buildHandleColsForSplit(sc *stmtctx.StatementContext, tbInfo *model.TableInfo) plannercore.HandleCols {
 	if tbInfo.IsCommonHandle {
 		primaryIdx := tables.FindPrimaryIndex(tbInfo)
@@ -1972,6 +2092,16 @@ func (b *executorBuilder) buildUpdate(v *plannercore.Update) Executor {
 		tblColPosInfos:            v.TblColPosInfos,
 		assignFlag:                assignFlag,
 	}
+	updateExec.fkChecks, updateExec.fkCascades, b.err = b.buildTblID2FKExecs(tblID2table, v.FKChecks, v.FKCascades)
+	if b.err != nil {
+		return nil
+	}
+	updateExec.checkConstraints = make(map[int64][]*table.Constraint, len(tblID2table))
+	for tblID, tbl := range tblID2table {
+		if updateExec.checkConstraints[tblID], b.err = table.WritableConstraints(b.ctx, tbl.Meta()); b.err != nil {
+			return nil
+		}
+	}
 	return updateExec
 }
 
@@ -2014,6 +2144,10 @@ func (b *executorBuilder) buildDelete(v *plannercore.Delete) Executor {
 		IsMultiTable:   v.IsMultiTable,
 		tblColPosInfos: v.TblColPosInfos,
 	}
+	deleteExec.fkChecks, deleteExec.fkCascades, b.err = b.buildTblID2FKExecs(tblID2table, v.FKChecks, v.FKCascades)
+	if b.err != nil {
+		return nil
+	}
 	return deleteExec
 }
 
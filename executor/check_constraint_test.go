@@ -0,0 +1,82 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckConstraintOnInsert(t *testing.T) {
+	t.Parallel()
+
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (id int primary key, a int check (a > 0), b int, constraint a_lt_b check (a < b))")
+
+	tk.MustExec("insert into t values (1, 1, 2), (2, null, 1), (3, 1, null)")
+	tk.MustGetErrMsg("insert into t values (4, 0, 2)", "[table:3819]Check constraint 't_chk_1' is violated.")
+	tk.MustGetErrCode("insert into t values (4, 2, 1)", errno.ErrCheckConstraintViolated)
+	tk.MustGetErrCode("replace into t values (1, 2, 1)", errno.ErrCheckConstraintViolated)
+	tk.MustGetErrCode("insert into t select 4, 2, 1", errno.ErrCheckConstraintViolated)
+
+	// The rows violating the constraints are discarded with warnings by INSERT IGNORE.
+	tk.MustExec("insert ignore into t values (4, 0, 2), (5, 2, 3), (6, 2, 1)")
+	require.Equal(t, uint16(2), tk.Session().GetSessionVars().StmtCtx.WarningCount())
+	tk.MustQuery("show warnings").Check(testkit.Rows(
+		"Warning 3819 Check constraint 't_chk_1' is violated.",
+		"Warning 3819 Check constraint 'a_lt_b' is violated."))
+	tk.MustExec("replace into t values (1, 2, 3)")
+	tk.MustQuery("select * from t order by id").Check(testkit.Rows("1 2 3", "2 <nil> 1", "3 1 <nil>", "5 2 3"))
+
+	// The constraint which is not enforced is not checked.
+	tk.MustExec("alter table t alter check a_lt_b not enforced")
+	tk.MustExec("insert into t values (6, 2, 1)")
+	tk.MustGetErrCode("insert into t values (7, 0, 1)", errno.ErrCheckConstraintViolated)
+}
+
+func TestCheckConstraintOnUpdate(t *testing.T) {
+	t.Parallel()
+
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t1 (id int primary key, a int, constraint c1 check (a between 0 and 10))")
+	tk.MustExec("create table t2 (id int primary key, a int, constraint c2 check (a <> 5))")
+	tk.MustExec("insert into t1 values (1, 1), (2, 2)")
+	tk.MustExec("insert into t2 values (1, 1), (2, 2)")
+
+	tk.MustGetErrCode("update t1 set a = 11 where id = 1", errno.ErrCheckConstraintViolated)
+	tk.MustGetErrCode("update t1 set id = 3, a = -1 where id = 1", errno.ErrCheckConstraintViolated)
+	tk.MustGetErrCode("insert into t1 values (1, 1) on duplicate key update a = 11", errno.ErrCheckConstraintViolated)
+	tk.MustGetErrMsg("update t1, t2 set t1.a = 5, t2.a = 5 where t1.id = t2.id", "[table:3819]Check constraint 'c2' is violated.")
+	tk.MustQuery("select * from t1 order by id").Check(testkit.Rows("1 1", "2 2"))
+
+	// The rows violating the constraints are skipped with warnings by UPDATE IGNORE.
+	tk.MustExec("update ignore t1 set a = a * 6")
+	require.Equal(t, uint16(1), tk.Session().GetSessionVars().StmtCtx.WarningCount())
+	tk.MustQuery("select * from t1 order by id").Check(testkit.Rows("1 6", "2 2"))
+	tk.MustExec("insert ignore into t1 values (2, 2) on duplicate key update a = 20")
+	require.Equal(t, uint16(1), tk.Session().GetSessionVars().StmtCtx.WarningCount())
+	tk.MustQuery("select * from t1 order by id").Check(testkit.Rows("1 6", "2 2"))
+}
@@ -43,18 +43,36 @@ type DeleteExec struct {
 	// the columns ordinals is present in ordinal range format, @see plannercore.TblColPosInfos
 	tblColPosInfos plannercore.TblColPosInfoSlice
 	memTracker     *memory.Tracker
+
+	fkChecks   map[int64][]*FKCheckExec
+	fkCascades map[int64][]*FKCascadeExec
 }
 
 // Next implements the Executor Next interface.
 func (e *DeleteExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	req.Reset()
+	var err error
 	if e.IsMultiTable {
-		return e.deleteMultiTablesByChunk(ctx)
+		err = e.deleteMultiTablesByChunk(ctx)
+	} else {
+		err = e.deleteSingleTableByChunk(ctx)
+	}
+	if err != nil {
+		return err
+	}
+	return e.executeFKCascades(ctx)
+}
+
+func (e *DeleteExec) executeFKCascades(ctx context.Context) error {
+	for _, fkCascades := range e.fkCascades {
+		if err := executeFKCascades(ctx, fkCascades); err != nil {
+			return err
+		}
 	}
-	return e.deleteSingleTableByChunk(ctx)
+	return nil
 }
 
-func (e *DeleteExec) deleteOneRow(tbl table.Table, handleCols plannercore.HandleCols, isExtraHandle bool, row []types.Datum) error {
+func (e *DeleteExec) deleteOneRow(ctx context.Context, tbl table.Table, handleCols plannercore.HandleCols, isExtraHandle bool, row []types.Datum) error {
 	end := len(row)
 	if isExtraHandle {
 		end--
@@ -63,7 +81,7 @@ func (e *DeleteExec) deleteOneRow(tbl table.Table, handleCols plannercore.Handle
 	if err != nil {
 		return err
 	}
-	err = e.removeRow(e.ctx, tbl, handle, row[:end])
+	err = e.removeRow(ctx, e.ctx, tbl, handle, row[:end])
 	if err != nil {
 		return err
 	}
@@ -130,7 +148,7 @@ func (e *DeleteExec) deleteSingleTableByChunk(ctx context.Context) error {
 				datumRow = append(datumRow, datum)
 			}
 
-			err = e.deleteOneRow(tbl, handleCols, isExtrahandle, datumRow)
+			err = e.deleteOneRow(ctx, tbl, handleCols, isExtrahandle, datumRow)
 			if err != nil {
 				return err
 			}
@@ -143,6 +161,10 @@ func (e *DeleteExec) deleteSingleTableByChunk(ctx context.Context) error {
 }
 
 func (e *DeleteExec) doBatchDelete(ctx context.Context) error {
+	// The referential actions of the deleted rows should be committed with them.
+	if err := e.executeFKCascades(ctx); err != nil {
+		return ErrBatchInsertFail.GenWithStack("BatchDelete failed with error: %v", err)
+	}
 	txn, err := e.ctx.Txn(false)
 	if err != nil {
 		return ErrBatchInsertFail.GenWithStack("BatchDelete failed with error: %v", err)
@@ -201,14 +223,22 @@ func (e *DeleteExec) deleteMultiTablesByChunk(ctx context.Context) error {
 		chk = chunk.Renew(chk, e.maxChunkSize)
 	}
 
-	return e.removeRowsInTblRowMap(tblRowMap)
+	return e.removeRowsInTblRowMap(ctx, tblRowMap)
 }
 
-func (e *DeleteExec) removeRowsInTblRowMap(tblRowMap tableRowMapType) error {
-	for id, rowMap := range tblRowMap {
+func (e *DeleteExec) removeRowsInTblRowMap(ctx context.Context, tblRowMap tableRowMapType) error {
+	// The tables are deleted in the order of tblColPosInfos rather than the map, so that the foreign key checks
+	// of the tables are always done in the same order.
+	for _, info := range e.tblColPosInfos {
+		id := info.TblID
+		rowMap, ok := tblRowMap[id]
+		if !ok {
+			continue
+		}
+		delete(tblRowMap, id)
 		var err error
 		rowMap.Range(func(h kv.Handle, val interface{}) bool {
-			err = e.removeRow(e.ctx, e.tblID2Table[id], h, val.([]types.Datum))
+			err = e.removeRow(ctx, e.ctx, e.tblID2Table[id], h, val.([]types.Datum))
 			return err == nil
 		})
 		if err != nil {
@@ -219,18 +249,27 @@ func (e *DeleteExec) removeRowsInTblRowMap(tblRowMap tableRowMapType) error {
 	return nil
 }
 
-func (e *DeleteExec) removeRow(ctx sessionctx.Context, t table.Table, h kv.Handle, data []types.Datum) error {
+func (e *DeleteExec) removeRow(ctx context.Context, sctx sessionctx.Context, t table.Table, h kv.Handle, data []types.Datum) error {
 	txnState, err := e.ctx.Txn(false)
 	if err != nil {
 		return err
 	}
 	memUsageOfTxnState := txnState.Size()
-	err = t.RemoveRecord(ctx, h, data)
+	err = t.RemoveRecord(sctx, h, data)
 	if err != nil {
 		return err
 	}
+	tblID := t.Meta().ID
+	for _, fkc := range e.fkChecks[tblID] {
+		if err = fkc.deleteRowNeedToCheck(ctx, data); err != nil {
+			return err
+		}
+	}
+	for _, fkc := range e.fkCascades[tblID] {
+		fkc.onDeleteRow(data)
+	}
 	e.memTracker.Consume(int64(txnState.Size() - memUsageOfTxnState))
-	ctx.GetSessionVars().StmtCtx.AddAffectedRows(1)
+	sctx.GetSessionVars().StmtCtx.AddAffectedRows(1)
 	return nil
 }
 
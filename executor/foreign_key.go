@@ -0,0 +1,565 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/planner"
+	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/codec"
+)
+
+// maxForeignKeyCascadeDepth is the max depth of the nested foreign key cascades, it's the same as MySQL.
+const maxForeignKeyCascadeDepth = 15
+
+// fkCascadeBatchSize is the max number of the parent rows handled by one statement generated by the cascade.
+const fkCascadeBatchSize = 1024
+
+// FKCheckExec checks the foreign key constraint on the rows written by the DML executors.
+type FKCheckExec struct {
+	*plannercore.FKCheck
+	ctx sessionctx.Context
+
+	// valueOffsets are the offsets of the checked values in the rows of the written table.
+	valueOffsets []int
+	// checkCols are the columns of the checked table matching the values.
+	checkCols []*model.ColumnInfo
+	// physicalIDs are the IDs of the physical tables to be checked.
+	physicalIDs []int64
+
+	snapshot   kv.Snapshot
+	snapshotTS uint64
+}
+
+func buildFKCheckExec(sctx sessionctx.Context, tbl table.Table, fkCheck *plannercore.FKCheck) (*FKCheckExec, error) {
+	valueCols, checkCols := fkCheck.FK.Cols, fkCheck.FK.RefCols
+	if !fkCheck.CheckExist {
+		valueCols, checkCols = checkCols, valueCols
+	}
+	e := &FKCheckExec{FKCheck: fkCheck, ctx: sctx}
+	for _, name := range valueCols {
+		col := table.FindCol(tbl.Cols(), name.L)
+		if col == nil {
+			return nil, table.ErrUnknownColumn.GenWithStackByArgs(name.O, "foreign key")
+		}
+		e.valueOffsets = append(e.valueOffsets, col.Offset)
+	}
+	if fkCheck.Tbl == nil {
+		return e, nil
+	}
+	tblInfo := fkCheck.Tbl.Meta()
+	// The foreign keys can't be created without the indices on both tables, and the indices can't be dropped,
+	// so the checked table never needs to be scanned.
+	if fkCheck.Idx == nil && !fkCheck.IdxIsPrimaryKey {
+		if fkCheck.CheckExist {
+			return nil, ErrFkNoIndexParent.GenWithStackByArgs(fkCheck.FK.Name.O, tblInfo.Name.O)
+		}
+		return nil, ErrFkNoIndexChild.GenWithStackByArgs(fkCheck.FK.Name.O, tblInfo.Name.O)
+	}
+	for _, name := range checkCols {
+		col := model.FindColumnInfo(tblInfo.Columns, name.L)
+		if col == nil {
+			return nil, table.ErrUnknownColumn.GenWithStackByArgs(name.O, "foreign key")
+		}
+		e.checkCols = append(e.checkCols, col)
+	}
+	if pi := tblInfo.GetPartitionInfo(); pi != nil && (fkCheck.Idx == nil || !fkCheck.Idx.Global) {
+		for _, def := range pi.Definitions {
+			e.physicalIDs = append(e.physicalIDs, def.ID)
+		}
+	} else {
+		e.physicalIDs = []int64{tblInfo.ID}
+	}
+	return e, nil
+}
+
+// insertRowNeedToCheck checks the foreign key constraint on the inserted row.
+func (e *FKCheckExec) insertRowNeedToCheck(ctx context.Context, row []types.Datum) error {
+	if !e.CheckExist {
+		return nil
+	}
+	return e.checkRow(ctx, row)
+}
+
+// updateRowNeedToCheck checks the foreign key constraint on the updated row if the checked columns are modified.
+func (e *FKCheckExec) updateRowNeedToCheck(ctx context.Context, oldRow, newRow []types.Datum, modified []bool) error {
+	if !isAnyOffsetModified(e.valueOffsets, modified) {
+		return nil
+	}
+	if e.CheckExist {
+		return e.checkRow(ctx, newRow)
+	}
+	return e.checkRow(ctx, oldRow)
+}
+
+// deleteRowNeedToCheck checks no child row refers to the deleted parent row.
+func (e *FKCheckExec) deleteRowNeedToCheck(ctx context.Context, row []types.Datum) error {
+	if e.CheckExist {
+		return nil
+	}
+	return e.checkRow(ctx, row)
+}
+
+func (e *FKCheckExec) checkRow(ctx context.Context, row []types.Datum) error {
+	vals := make([]types.Datum, 0, len(e.valueOffsets))
+	for i, offset := range e.valueOffsets {
+		v := row[offset]
+		// The row with any NULL value in the foreign key columns always satisfies the constraint.
+		if v.IsNull() {
+			return nil
+		}
+		if e.Tbl != nil {
+			var err error
+			v, err = table.CastValue(e.ctx, v, e.checkCols[i], false, true)
+			if err != nil {
+				return err
+			}
+		}
+		vals = append(vals, v)
+	}
+	if e.Tbl == nil {
+		if e.CheckExist {
+			return ErrNoReferencedRow2.GenWithStackByArgs(plannercore.FKInfoString(e.ChildSchema, e.ChildTable, e.FK))
+		}
+		return nil
+	}
+	txn, err := e.ctx.Txn(true)
+	if err != nil {
+		return err
+	}
+	key, err := e.exists(ctx, txn, vals)
+	if err != nil {
+		return err
+	}
+	if !e.CheckExist {
+		if key != nil {
+			return ErrRowIsReferenced2.GenWithStackByArgs(plannercore.FKInfoString(e.ChildSchema, e.ChildTable, e.FK))
+		}
+		return nil
+	}
+	if key == nil {
+		return ErrNoReferencedRow2.GenWithStackByArgs(plannercore.FKInfoString(e.ChildSchema, e.ChildTable, e.FK))
+	}
+	// Lock the referenced row to prevent it from being deleted or updated by other transactions. The optimistic
+	// transaction doesn't lock the key at once, but the key is checked for write conflicts when it commits.
+	sessVars := e.ctx.GetSessionVars()
+	return doLockKeys(ctx, e.ctx, newLockCtx(sessVars, sessVars.LockWaitTimeout), key)
+}
+
+// exists checks whether any row of the checked table matches the values, it returns the key of the matched row,
+// or nil if no row matches.
+func (e *FKCheckExec) exists(ctx context.Context, txn kv.Transaction, vals []types.Datum) (kv.Key, error) {
+	sc := e.ctx.GetSessionVars().StmtCtx
+	memBuffer := txn.GetMemBuffer()
+	snapshot := e.getSnapshot(txn)
+	for _, pid := range e.physicalIDs {
+		var (
+			key kv.Key
+			err error
+		)
+		switch {
+		case e.IdxIsPrimaryKey && e.Idx == nil:
+			key = tablecodec.EncodeRowKeyWithHandle(pid, kv.IntHandle(vals[0].GetInt64()))
+		case e.IdxIsPrimaryKey:
+			var encoded []byte
+			encoded, err = codec.EncodeKey(sc, nil, vals...)
+			key = tablecodec.EncodeRowKey(pid, encoded)
+		default:
+			key, _, err = tablecodec.GenIndexKey(sc, e.Tbl.Meta(), e.Idx, pid, vals, nil, nil)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if e.IdxIsExclusive {
+			found, err := keyExists(ctx, memBuffer, snapshot, key)
+			if err != nil || found {
+				return key, err
+			}
+			continue
+		}
+		found, err := scanKeyPrefix(memBuffer, snapshot, key)
+		if err != nil || found != nil {
+			return found, err
+		}
+	}
+	return nil, nil
+}
+
+// getSnapshot returns the snapshot to read the checked rows, the pessimistic transaction reads the latest data
+// by the for update ts, the same as the other DML statements.
+func (e *FKCheckExec) getSnapshot(txn kv.Transaction) kv.Snapshot {
+	txnCtx := e.ctx.GetSessionVars().TxnCtx
+	if !txnCtx.IsPessimistic {
+		return txn.GetSnapshot()
+	}
+	forUpdateTS := txnCtx.GetForUpdateTS()
+	if e.snapshot == nil || e.snapshotTS != forUpdateTS {
+		e.snapshot = e.ctx.GetStore().GetSnapshot(kv.NewVersion(forUpdateTS))
+		e.snapshotTS = forUpdateTS
+	}
+	return e.snapshot
+}
+
+// keyExists checks whether the key exists in the membuffer or the snapshot.
+func keyExists(ctx context.Context, memBuffer kv.MemBuffer, snapshot kv.Snapshot, key kv.Key) (bool, error) {
+	val, err := memBuffer.Get(ctx, key)
+	if err == nil {
+		// The empty value means the key is deleted in the transaction.
+		return len(val) > 0, nil
+	}
+	if !kv.IsErrNotFound(err) {
+		return false, err
+	}
+	_, err = snapshot.Get(ctx, key)
+	if err == nil {
+		return true, nil
+	}
+	if kv.IsErrNotFound(err) {
+		return false, nil
+	}
+	return false, err
+}
+
+// scanKeyPrefix scans the keys with the prefix in the membuffer and the snapshot, and returns the first key.
+func scanKeyPrefix(memBuffer kv.MemBuffer, snapshot kv.Snapshot, prefix kv.Key) (kv.Key, error) {
+	upperBound := prefix.PrefixNext()
+	// The keys written in the transaction, including the deleted ones, overwrite the keys in the snapshot.
+	memKeys := make(map[string]struct{})
+	found, err := iterKeyPrefix(memBuffer, prefix, upperBound, func(k kv.Key, v []byte) (bool, error) {
+		memKeys[string(k)] = struct{}{}
+		return len(v) > 0, nil
+	})
+	if err != nil || found != nil {
+		return found, err
+	}
+	return iterKeyPrefix(snapshot, prefix, upperBound, func(k kv.Key, v []byte) (bool, error) {
+		_, ok := memKeys[string(k)]
+		return !ok, nil
+	})
+}
+
+func iterKeyPrefix(retriever kv.Retriever, prefix, upperBound kv.Key, fn func(k kv.Key, v []byte) (bool, error)) (kv.Key, error) {
+	iter, err := retriever.Iter(prefix, upperBound)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+	for iter.Valid() {
+		ok, err := fn(iter.Key(), iter.Value())
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return iter.Key().Clone(), nil
+		}
+		if err = iter.Next(); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+func isAnyOffsetModified(offsets []int, modified []bool) bool {
+	for _, offset := range offsets {
+		if modified[offset] {
+			return true
+		}
+	}
+	return false
+}
+
+// FKCascadeExec performs the foreign key referential action CASCADE or SET NULL on the child table, it collects
+// the deleted or updated parent rows during the execution of the DML executor, and then modifies the child rows
+// referring to them by the generated DELETE or UPDATE statements.
+type FKCascadeExec struct {
+	*plannercore.FKCascade
+	ctx              sessionctx.Context
+	is               infoschema.InfoSchema
+	readReplicaScope string
+	// depth is the depth of the statement owning the cascade, the top level statement is 0.
+	depth int
+
+	// valueOffsets are the offsets of the referenced columns in the rows of the parent table.
+	valueOffsets []int
+	// childCols are the foreign key columns of the child table.
+	childCols []*model.ColumnInfo
+	// oldValues are the referenced values of the deleted or updated parent rows.
+	oldValues [][]types.Datum
+	// newValues are the new referenced values of the updated parent rows, only used by ON UPDATE CASCADE.
+	newValues [][]types.Datum
+}
+
+func (b *executorBuilder) buildFKCascadeExec(tbl table.Table, fkCascade *plannercore.FKCascade) (*FKCascadeExec, error) {
+	e := &FKCascadeExec{
+		FKCascade:        fkCascade,
+		ctx:              b.ctx,
+		is:               b.is,
+		readReplicaScope: b.readReplicaScope,
+		depth:            b.fkCascadeDepth,
+	}
+	for _, name := range fkCascade.FK.RefCols {
+		col := table.FindCol(tbl.Cols(), name.L)
+		if col == nil {
+			return nil, table.ErrUnknownColumn.GenWithStackByArgs(name.O, "foreign key")
+		}
+		e.valueOffsets = append(e.valueOffsets, col.Offset)
+	}
+	for _, name := range fkCascade.FK.Cols {
+		col := model.FindColumnInfo(fkCascade.ChildTable.Meta().Columns, name.L)
+		if col == nil {
+			return nil, table.ErrUnknownColumn.GenWithStackByArgs(name.O, "foreign key")
+		}
+		e.childCols = append(e.childCols, col)
+	}
+	return e, nil
+}
+
+func (b *executorBuilder) buildFKCheckExecs(tbl table.Table, fkChecks []*plannercore.FKCheck) ([]*FKCheckExec, error) {
+	if len(fkChecks) == 0 {
+		return nil, nil
+	}
+	execs := make([]*FKCheckExec, 0, len(fkChecks))
+	for _, fkCheck := range fkChecks {
+		e, err := buildFKCheckExec(b.ctx, tbl, fkCheck)
+		if err != nil {
+			return nil, err
+		}
+		execs = append(execs, e)
+	}
+	return execs, nil
+}
+
+func (b *executorBuilder) buildFKCascadeExecs(tbl table.Table, fkCascades []*plannercore.FKCascade) ([]*FKCascadeExec, error) {
+	if len(fkCascades) == 0 {
+		return nil, nil
+	}
+	execs := make([]*FKCascadeExec, 0, len(fkCascades))
+	for _, fkCascade := range fkCascades {
+		e, err := b.buildFKCascadeExec(tbl, fkCascade)
+		if err != nil {
+			return nil, err
+		}
+		execs = append(execs, e)
+	}
+	return execs, nil
+}
+
+// onDeleteRow collects the referenced values of the deleted parent row.
+func (e *FKCascadeExec) onDeleteRow(row []types.Datum) {
+	if e.Tp != plannercore.FKCascadeOnDelete {
+		return
+	}
+	if vals := e.fetchValues(row); vals != nil {
+		e.oldValues = append(e.oldValues, vals)
+	}
+}
+
+// onUpdateRow collects the old and new referenced values of the updated parent row if they are modified.
+func (e *FKCascadeExec) onUpdateRow(oldRow, newRow []types.Datum, modified []bool) {
+	if e.Tp != plannercore.FKCascadeOnUpdate || !isAnyOffsetModified(e.valueOffsets, modified) {
+		return
+	}
+	vals := e.fetchValues(oldRow)
+	if vals == nil {
+		return
+	}
+	e.oldValues = append(e.oldValues, vals)
+	if e.ReferOption() == ast.ReferOptionCascade {
+		newVals := make([]types.Datum, 0, len(e.valueOffsets))
+		for _, offset := range e.valueOffsets {
+			newVals = append(newVals, *newRow[offset].Clone())
+		}
+		e.newValues = append(e.newValues, newVals)
+	}
+}
+
+// fetchValues returns the referenced values of the row, or nil if any value is NULL since no child row refers to it.
+func (e *FKCascadeExec) fetchValues(row []types.Datum) []types.Datum {
+	vals := make([]types.Datum, 0, len(e.valueOffsets))
+	for _, offset := range e.valueOffsets {
+		if row[offset].IsNull() {
+			return nil
+		}
+		vals = append(vals, *row[offset].Clone())
+	}
+	return vals
+}
+
+// execute performs the referential action on the child rows referring to the collected parent rows.
+func (e *FKCascadeExec) execute(ctx context.Context) error {
+	if len(e.oldValues) == 0 {
+		return nil
+	}
+	if e.depth >= maxForeignKeyCascadeDepth {
+		return ErrForeignKeyCascadeDepthExceed.GenWithStackByArgs(maxForeignKeyCascadeDepth)
+	}
+	// The rows modified by the cascades aren't counted as the affected rows of the statement, the same as MySQL.
+	sc := e.ctx.GetSessionVars().StmtCtx
+	counters := sc.GetRowCounters()
+	defer sc.SetRowCounters(counters)
+
+	oldValues, newValues := e.oldValues, e.newValues
+	e.oldValues, e.newValues = nil, nil
+	for len(oldValues) > 0 {
+		batch := fkCascadeBatchSize
+		if newValues != nil {
+			// Each updated parent row has its own new values.
+			batch = 1
+		}
+		if batch > len(oldValues) {
+			batch = len(oldValues)
+		}
+		var assigns [][]types.Datum
+		if newValues != nil {
+			assigns, newValues = newValues[:batch], newValues[batch:]
+		}
+		stmt := e.buildStmt(oldValues[:batch], assigns)
+		oldValues = oldValues[batch:]
+		if err := e.executeStmt(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// buildStmt builds the statement modifying the child rows referring to the parent values.
+func (e *FKCascadeExec) buildStmt(oldValues, newValues [][]types.Datum) ast.StmtNode {
+	childInfo := e.ChildTable.Meta()
+	tn := &ast.TableName{
+		Schema:    e.ChildSchema,
+		Name:      childInfo.Name,
+		TableInfo: childInfo,
+	}
+	if db, ok := e.is.SchemaByName(e.ChildSchema); ok {
+		tn.DBInfo = db
+	}
+	tableRefs := &ast.TableRefsClause{TableRefs: &ast.Join{Left: &ast.TableSource{Source: tn}}}
+	where := e.buildWhere(oldValues)
+	if e.Tp == plannercore.FKCascadeOnDelete && e.ReferOption() == ast.ReferOptionCascade {
+		return &ast.DeleteStmt{TableRefs: tableRefs, Where: where}
+	}
+	assignments := make([]*ast.Assignment, 0, len(e.childCols))
+	for i, col := range e.childCols {
+		var expr ast.ExprNode
+		if len(newValues) > 0 {
+			expr = e.valueExpr(col, newValues[0][i])
+		} else {
+			expr = ast.NewValueExpr(nil, "", "")
+		}
+		assignments = append(assignments, &ast.Assignment{Column: &ast.ColumnName{Name: col.Name}, Expr: expr})
+	}
+	return &ast.UpdateStmt{TableRefs: tableRefs, List: assignments, Where: where}
+}
+
+// buildWhere builds the condition `(c1, c2, ...) IN ((v1, v2, ...), ...)` on the foreign key columns.
+func (e *FKCascadeExec) buildWhere(values [][]types.Datum) ast.ExprNode {
+	buildRow := func(exprs []ast.ExprNode) ast.ExprNode {
+		if len(exprs) == 1 {
+			return exprs[0]
+		}
+		return &ast.RowExpr{Values: exprs}
+	}
+	cols := make([]ast.ExprNode, 0, len(e.childCols))
+	for _, col := range e.childCols {
+		cols = append(cols, &ast.ColumnNameExpr{Name: &ast.ColumnName{Name: col.Name}})
+	}
+	list := make([]ast.ExprNode, 0, len(values))
+	for _, vals := range values {
+		exprs := make([]ast.ExprNode, 0, len(vals))
+		for i, v := range vals {
+			exprs = append(exprs, e.valueExpr(e.childCols[i], v))
+		}
+		list = append(list, buildRow(exprs))
+	}
+	return &ast.PatternInExpr{Expr: buildRow(cols), List: list}
+}
+
+func (e *FKCascadeExec) valueExpr(col *model.ColumnInfo, v types.Datum) ast.ExprNode {
+	return ast.NewValueExpr(v.GetValue(), col.Charset, col.Collate)
+}
+
+// executeStmt plans and executes the statement generated by the cascade, the statement may trigger the cascades
+// of the deeper level.
+func (e *FKCascadeExec) executeStmt(ctx context.Context, stmt ast.StmtNode) error {
+	p, err := planner.OptimizeForForeignKeyCascade(ctx, e.ctx, stmt, e.is)
+	if err != nil {
+		return err
+	}
+	b := newExecutorBuilder(e.ctx, e.is, nil, 0, false, e.readReplicaScope)
+	b.fkCascadeDepth = e.depth + 1
+	exec := b.build(p)
+	if b.err != nil {
+		return b.err
+	}
+	if err = exec.Open(ctx); err != nil {
+		terror.Call(exec.Close)
+		return err
+	}
+	err = Next(ctx, exec, newFirstChunk(exec))
+	closeErr := exec.Close()
+	if err != nil {
+		return err
+	}
+	return closeErr
+}
+
+// executeFKCascades performs the collected referential actions.
+func executeFKCascades(ctx context.Context, fkCascades []*FKCascadeExec) error {
+	for _, fkc := range fkCascades {
+		if err := fkc.execute(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *executorBuilder) buildTblID2FKExecs(tblID2table map[int64]table.Table, fkChecks map[int64][]*plannercore.FKCheck,
+	fkCascades map[int64][]*plannercore.FKCascade) (map[int64][]*FKCheckExec, map[int64][]*FKCascadeExec, error) {
+	var (
+		tblID2FKChecks   map[int64][]*FKCheckExec
+		tblID2FKCascades map[int64][]*FKCascadeExec
+	)
+	for tblID, checks := range fkChecks {
+		execs, err := b.buildFKCheckExecs(tblID2table[tblID], checks)
+		if err != nil {
+			return nil, nil, err
+		}
+		if tblID2FKChecks == nil {
+			tblID2FKChecks = make(map[int64][]*FKCheckExec, len(fkChecks))
+		}
+		tblID2FKChecks[tblID] = execs
+	}
+	for tblID, cascades := range fkCascades {
+		execs, err := b.buildFKCascadeExecs(tblID2table[tblID], cascades)
+		if err != nil {
+			return nil, nil, err
+		}
+		if tblID2FKCascades == nil {
+			tblID2FKCascades = make(map[int64][]*FKCascadeExec, len(fkCascades))
+		}
+		tblID2FKCascades[tblID] = execs
+	}
+	return tblID2FKChecks, tblID2FKCascades, nil
+}
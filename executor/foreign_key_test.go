@@ -0,0 +1,336 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/parser/auth"
+	"github.com/pingcap/tidb/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForeignKeyCheckOnInsertAndUpdateChild(t *testing.T) {
+	t.Parallel()
+
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@foreign_key_checks = 1")
+
+	parents := []string{
+		// The int handle.
+		"create table t1 (id int primary key, a int)",
+		// The clustered index.
+		"create table t1 (id varchar(10) primary key clustered, a int)",
+		// The unique index.
+		"create table t1 (a int, id int, unique key(id))",
+		// The non-unique index with more columns.
+		"create table t1 (a int, id int, key(id, a))",
+	}
+	for _, parent := range parents {
+		tk.MustExec("drop table if exists t2, t1")
+		tk.MustExec(parent)
+		tk.MustExec("create table t2 (id int primary key, pid int, constraint fk foreign key (pid) references t1(id))")
+		tk.MustExec("insert into t1 (id, a) values (1, 1), (2, 2)")
+
+		tk.MustExec("insert into t2 values (1, 1), (2, null)")
+		tk.MustGetErrCode("insert into t2 values (3, 3)", errno.ErrNoReferencedRow2)
+		tk.MustExec("insert ignore into t2 values (3, 3), (4, 2)")
+		require.Equal(t, uint16(1), tk.Session().GetSessionVars().StmtCtx.WarningCount())
+		tk.MustQuery("select * from t2 order by id").Check(testkit.Rows("1 1", "2 <nil>", "4 2"))
+
+		tk.MustExec("update t2 set pid = 2 where id = 1")
+		tk.MustGetErrCode("update t2 set pid = 3 where id = 1", errno.ErrNoReferencedRow2)
+		tk.MustGetErrCode("insert into t2 values (1, 1) on duplicate key update pid = 3", errno.ErrNoReferencedRow2)
+		tk.MustGetErrCode("replace into t2 values (1, 3)", errno.ErrNoReferencedRow2)
+		tk.MustQuery("select * from t2 order by id").Check(testkit.Rows("1 2", "2 <nil>", "4 2"))
+
+		// The parent rows written in the same transaction are visible to the check.
+		tk.MustExec("begin")
+		tk.MustExec("insert into t1 (id, a) values (3, 3)")
+		tk.MustExec("insert into t2 values (3, 3)")
+		tk.MustExec("delete from t2 where id = 3")
+		tk.MustExec("delete from t1 where id = 3")
+		tk.MustGetErrCode("insert into t2 values (3, 3)", errno.ErrNoReferencedRow2)
+		tk.MustExec("commit")
+		tk.MustQuery("select count(*) from t2").Check(testkit.Rows("3"))
+	}
+}
+
+func TestForeignKeyCheckOnParent(t *testing.T) {
+	t.Parallel()
+
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@foreign_key_checks = 1")
+	tk.MustExec("create table t1 (id int primary key, a int)")
+	tk.MustExec("create table t2 (id int primary key, pid int, key(pid), constraint fk foreign key (pid) references t1(id) on delete restrict)")
+	tk.MustExec("insert into t1 values (1, 1), (2, 2), (3, 3)")
+	tk.MustExec("insert into t2 values (1, 1), (2, 2)")
+
+	tk.MustGetErrMsg("delete from t1 where id = 1", "[executor:1451]Cannot delete or update a parent row: "+
+		"a foreign key constraint fails (`test`.`t2`, CONSTRAINT `fk` FOREIGN KEY (`pid`) REFERENCES `t1` (`id`) ON DELETE RESTRICT)")
+	tk.MustGetErrCode("update t1 set id = 10 where id = 2", errno.ErrRowIsReferenced2)
+	tk.MustGetErrCode("replace into t1 values (1, 10)", errno.ErrRowIsReferenced2)
+	tk.MustGetErrCode("delete t1, t2 from t1 join t2 on t1.id = t2.pid where t1.id = 1", errno.ErrRowIsReferenced2)
+
+	// The unreferenced rows and the columns not referenced can be changed.
+	tk.MustExec("update t1 set a = 10 where id = 1")
+	tk.MustExec("insert into t1 values (2, 2) on duplicate key update a = 20")
+	tk.MustExec("delete from t1 where id = 3")
+	tk.MustQuery("select * from t1 order by id").Check(testkit.Rows("1 10", "2 20"))
+
+	// The parent row can be deleted after the child rows are deleted in the same transaction.
+	tk.MustExec("begin")
+	tk.MustExec("delete from t2 where pid = 1")
+	tk.MustExec("delete from t1 where id = 1")
+	tk.MustExec("commit")
+	tk.MustQuery("select * from t1").Check(testkit.Rows("2 20"))
+
+	// The foreign key checks can be disabled by foreign_key_checks.
+	tk.MustExec("set @@foreign_key_checks = 0")
+	tk.MustExec("delete from t1 where id = 2")
+	tk.MustExec("insert into t2 values (3, 3)")
+	tk.MustQuery("select * from t2 order by id").Check(testkit.Rows("2 2", "3 3"))
+}
+
+func TestForeignKeyCascade(t *testing.T) {
+	t.Parallel()
+
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@foreign_key_checks = 1")
+	tk.MustExec("create table t1 (id int primary key, a int)")
+	tk.MustExec("create table t2 (id int primary key, pid int, constraint fk2 foreign key (pid) references t1(id) on delete cascade on update cascade)")
+	tk.MustExec("create table t3 (id int primary key, pid int, constraint fk3 foreign key (pid) references t2(id) on delete set null on update set null)")
+	tk.MustExec("insert into t1 values (1, 1), (2, 2), (3, 3)")
+	tk.MustExec("insert into t2 values (1, 1), (2, 1), (3, 2)")
+	tk.MustExec("insert into t3 values (1, 1), (2, 2), (3, 3)")
+
+	tk.MustExec("delete from t1 where id = 1")
+	require.Equal(t, uint64(1), tk.Session().AffectedRows())
+	tk.MustQuery("select * from t2").Check(testkit.Rows("3 2"))
+	tk.MustQuery("select * from t3 order by id").Check(testkit.Rows("1 <nil>", "2 <nil>", "3 3"))
+
+	tk.MustExec("update t1 set id = 20 where id = 2")
+	require.Equal(t, uint64(1), tk.Session().AffectedRows())
+	tk.MustQuery("select * from t2").Check(testkit.Rows("3 20"))
+	tk.MustExec("update t2 set id = 30 where id = 3")
+	tk.MustQuery("select * from t3 order by id").Check(testkit.Rows("1 <nil>", "2 <nil>", "3 <nil>"))
+
+	tk.MustExec("replace into t1 values (20, 200)")
+	tk.MustQuery("select count(*) from t2").Check(testkit.Rows("0"))
+
+	// The self-referencing table.
+	tk.MustExec("create table t4 (id int primary key, pid int, constraint fk4 foreign key (pid) references t4(id) on delete cascade)")
+	tk.MustExec("insert into t4 values (1, null), (2, 1), (3, 2), (4, 2), (5, null)")
+	tk.MustExec("delete from t4 where id = 1")
+	tk.MustQuery("select * from t4").Check(testkit.Rows("5 <nil>"))
+
+	// The cascade is limited by the max depth.
+	tk.MustExec("delete from t4")
+	tk.MustExec("insert into t4 values (1, null)")
+	for i := 2; i <= 20; i++ {
+		tk.MustExec(fmt.Sprintf("insert into t4 values (%d, %d)", i, i-1))
+	}
+	tk.MustGetErrCode("delete from t4 where id = 1", errno.ErrForeignKeyCascadeDepthExceeded)
+	tk.MustExec("delete from t4 where id = 6")
+	tk.MustQuery("select count(*) from t4").Check(testkit.Rows("5"))
+}
+
+func TestForeignKeyCascadePrivilege(t *testing.T) {
+	t.Parallel()
+
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@global.foreign_key_checks = 1")
+	tk.MustExec("create table t1 (id int primary key, a int)")
+	tk.MustExec("create table t2 (id int primary key, pid int, constraint fk2 foreign key (pid) references t1(id) on delete cascade on update set null)")
+	tk.MustExec("insert into t1 values (1, 1), (2, 2)")
+	tk.MustExec("insert into t2 values (1, 1), (2, 2)")
+	tk.MustExec("create user fk_user")
+	tk.MustExec("grant select, delete, update on test.t1 to fk_user")
+
+	// Like MySQL, the referential actions don't require the privileges on the child table,
+	// they are performed on behalf of the statement modifying the parent table.
+	userTK := testkit.NewTestKit(t, store)
+	require.True(t, userTK.Session().Auth(&auth.UserIdentity{Username: "fk_user", Hostname: "%"}, nil, nil))
+	userTK.MustExec("use test")
+	userTK.MustGetErrCode("delete from t2", errno.ErrTableaccessDenied)
+	userTK.MustExec("delete from t1 where id = 1")
+	userTK.MustExec("update t1 set id = 20 where id = 2")
+	tk.MustQuery("select * from t2").Check(testkit.Rows("2 <nil>"))
+}
+
+func TestForeignKeyExplain(t *testing.T) {
+	t.Parallel()
+
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@foreign_key_checks = 1")
+	tk.MustExec("create table t1 (id int primary key, a int)")
+	tk.MustExec("create table t2 (id int primary key, pid int, key(pid), constraint fk2 foreign key (pid) references t1(id))")
+	tk.MustExec("create table t3 (id int primary key, pid int, constraint fk3 foreign key (pid) references t1(id) on delete cascade)")
+
+	tk.MustQuery("explain format = 'brief' insert into t2 values (1, 1)").Check(testkit.Rows(
+		"Insert N/A root  N/A",
+		"└─Foreign_Key_Check 0.00 root table:t1, handle foreign_key:fk2, check_exist"))
+	tk.MustQuery("explain format = 'brief' delete from t1 where id = 1").Check(testkit.Rows(
+		"Delete N/A root  N/A",
+		"├─Point_Get 1.00 root table:t1 handle:1",
+		"├─Foreign_Key_Check 0.00 root table:t2, index:pid foreign_key:fk2, check_not_exist",
+		"└─Foreign_Key_Cascade 0.00 root table:t3 foreign_key:fk3, on_delete:CASCADE"))
+	tk.MustQuery("explain format = 'brief' update t1 set a = 2 where id = 1").Check(testkit.Rows(
+		"Update N/A root  N/A",
+		"└─Point_Get 1.00 root table:t1 handle:1"))
+
+	tk.MustExec("set @@foreign_key_checks = 0")
+	tk.MustQuery("explain format = 'brief' insert into t2 values (1, 1)").Check(testkit.Rows(
+		"Insert N/A root  N/A"))
+}
+
+func TestForeignKeyLockParentRow(t *testing.T) {
+	t.Parallel()
+
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@foreign_key_checks = 1")
+	tk.MustExec("create table t1 (id int primary key)")
+	tk.MustExec("create table t2 (id int primary key, pid int, constraint fk foreign key (pid) references t1(id))")
+	tk.MustExec("insert into t1 values (1)")
+
+	tk2 := testkit.NewTestKit(t, store)
+	tk2.MustExec("use test")
+	tk2.MustExec("set @@foreign_key_checks = 1")
+	tk2.MustExec("set @@innodb_lock_wait_timeout = 1")
+
+	tk.MustExec("begin pessimistic")
+	tk.MustExec("insert into t2 values (1, 1)")
+	// The referenced parent row is locked by the inserting transaction.
+	tk2.MustExec("begin pessimistic")
+	tk2.MustGetErrCode("delete from t1 where id = 1", errno.ErrLockWaitTimeout)
+	tk2.MustExec("rollback")
+	tk.MustExec("commit")
+	tk2.MustGetErrCode("delete from t1 where id = 1", errno.ErrRowIsReferenced2)
+}
+
+func TestForeignKeyLockParentRowOptimistic(t *testing.T) {
+	t.Parallel()
+
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@foreign_key_checks = 1")
+	tk.MustExec("create table t1 (id int primary key)")
+	tk.MustExec("create table t2 (id int primary key, pid int, constraint fk foreign key (pid) references t1(id))")
+	tk.MustExec("insert into t1 values (1), (2)")
+
+	tk2 := testkit.NewTestKit(t, store)
+	tk2.MustExec("use test")
+	tk2.MustExec("set @@foreign_key_checks = 1")
+
+	// The parent row is deleted after the child row is inserted, the inserting transaction fails to commit.
+	tk.MustExec("begin optimistic")
+	tk.MustExec("insert into t2 values (1, 1)")
+	tk2.MustExec("begin optimistic")
+	tk2.MustExec("delete from t1 where id = 1")
+	tk2.MustExec("commit")
+	tk.MustGetErrCode("commit", errno.ErrWriteConflict)
+	tk.MustQuery("select * from t2").Check(testkit.Rows())
+
+	// The same for updating the referenced column of the parent row.
+	tk.MustExec("begin optimistic")
+	tk.MustExec("insert into t2 values (2, 2)")
+	tk2.MustExec("update t1 set id = 3 where id = 2")
+	tk.MustGetErrCode("commit", errno.ErrWriteConflict)
+	tk.MustQuery("select * from t1").Check(testkit.Rows("3"))
+	tk.MustQuery("select * from t2").Check(testkit.Rows())
+}
+
+func TestForeignKeyIndex(t *testing.T) {
+	t.Parallel()
+
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("set @@foreign_key_checks = 1")
+	tk.MustExec("create table t1 (id int primary key)")
+
+	// The index on the foreign key columns is created implicitly if there is no index to check the child rows.
+	tk.MustExec("create table t2 (id int primary key, pid int, constraint fk foreign key (pid) references t1(id))")
+	tk.MustQuery("select index_name, column_name from information_schema.statistics where table_schema = 'test' and table_name = 't2' order by index_name").
+		Check(testkit.Rows("PRIMARY id", "fk pid"))
+	tk.MustQuery("explain format = 'brief' delete from t1 where id = 1").Check(testkit.Rows(
+		"Delete N/A root  N/A",
+		"├─Point_Get 1.00 root table:t1 handle:1",
+		"└─Foreign_Key_Check 0.00 root table:t2, index:fk foreign_key:fk, check_not_exist"))
+	tk.MustExec("create table t3 (id int primary key, pid int, key(pid, id), constraint fk3 foreign key (pid) references t1(id))")
+	tk.MustQuery("select index_name from information_schema.statistics where table_schema = 'test' and table_name = 't3' order by index_name").
+		Check(testkit.Rows("PRIMARY", "pid", "pid"))
+	tk.MustGetErrCode("create table t4 (id int primary key, pid int, key fk4(id), constraint fk4 foreign key (pid) references t1(id))", errno.ErrDupKeyName)
+
+	// The index isn't created implicitly by ALTER TABLE, and the index needed by the foreign key can't be dropped.
+	tk.MustExec("create table t4 (id int primary key, pid int)")
+	tk.MustGetErrCode("alter table t4 add constraint fk4 foreign key (pid) references t1(id)", errno.ErrFkNoIndexChild)
+	tk.MustExec("alter table t4 add index i(pid)")
+	tk.MustExec("alter table t4 add constraint fk4 foreign key (pid) references t1(id)")
+	tk.MustGetErrCode("alter table t4 drop index i", errno.ErrDropIndexFk)
+	tk.MustGetErrCode("alter table t2 drop index fk", errno.ErrDropIndexFk)
+	tk.MustExec("alter table t2 add index i(pid)")
+	tk.MustExec("alter table t2 drop index fk")
+
+	// The referenced columns need an index too, it isn't created implicitly.
+	tk.MustExec("create table p1 (a int, id int)")
+	tk.MustExec("create table p2 (id varchar(10), key(id(5)))")
+	tk.MustGetErrCode("create table c1 (id int primary key, pid int, foreign key fk(pid) references p1(id))", errno.ErrFkNoIndexParent)
+	tk.MustGetErrCode("create table c1 (id int primary key, pid varchar(10), foreign key fk(pid) references p2(id))", errno.ErrFkNoIndexParent)
+	tk.MustGetErrCode("create table c1 (id int, pid int, foreign key fk(pid) references c1(id))", errno.ErrFkNoIndexParent)
+	tk.MustExec("create table c1 (id int primary key, pid int, foreign key fk(pid) references c1(id))")
+	tk.MustGetErrCode("alter table t4 add constraint fk5 foreign key (pid) references p1(id)", errno.ErrFkNoIndexParent)
+	tk.MustExec("alter table p1 add index k(id, a)")
+	tk.MustExec("alter table t4 add constraint fk5 foreign key (pid) references p1(id)")
+	tk.MustGetErrCode("alter table p1 drop index k", errno.ErrDropIndexFk)
+	tk.MustExec("alter table t4 drop foreign key fk5")
+	tk.MustExec("alter table p1 drop index k")
+
+	// The referenced table created after the foreign key without an index can't be checked.
+	tk.MustExec("create table c2 (id int primary key, pid int, foreign key fk(pid) references p3(id))")
+	tk.MustExec("create table p3 (id int)")
+	tk.MustGetErrCode("insert into c2 values (1, 1)", errno.ErrFkNoIndexParent)
+}
@@ -60,6 +60,7 @@ import (
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/collate"
 	"github.com/pingcap/tidb/util/deadlockhistory"
+	"github.com/pingcap/tidb/util/expensivequery"
 	"github.com/pingcap/tidb/util/keydecoder"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/pdapi"
@@ -166,6 +167,16 @@ func (e *memtableRetriever) retrieve(ctx context.Context, sctx sessionctx.Contex
 			err = e.setDataForAttributes(sctx)
 		case infoschema.TablePlacementRules:
 			err = e.setDataFromPlacementRules(ctx, sctx, dbs)
+		case infoschema.TableCheckConstraints:
+			e.setDataFromCheckConstraints(sctx, dbs)
+		case infoschema.TableSessionConnectAttrs:
+			e.setDataForSessionConnectAttrs(sctx)
+		case infoschema.TableUserResourceUsage:
+			err = e.setDataForUserResourceUsage(ctx, sctx)
+		case infoschema.TableMemoryKillHistory:
+			e.setDataForMemoryKillHistory(sctx)
+		case infoschema.TableInstancePlanCache:
+			err = e.setDataForInstancePlanCache(sctx)
 		}
 		if err != nil {
 			return nil, err
@@ -1315,6 +1326,102 @@ func (e *memtableRetriever) setDataForProcessList(ctx sessionctx.Context) {
 	e.rows = records
 }
 
+func (e *memtableRetriever) setDataForSessionConnectAttrs(ctx sessionctx.Context) {
+	e.rows = infoschema.GetSessionConnectAttrsRows(ctx, false)
+}
+
+func (e *memtableRetriever) setDataForUserResourceUsage(ctx context.Context, sctx sessionctx.Context) error {
+	// The hourly counters are summed up for the current hour, see mysql.user_resource_usage.
+	window := time.Now().Truncate(time.Hour).Unix()
+	sql := new(strings.Builder)
+	sqlexec.MustFormatSQL(sql, `SELECT u.User, u.Host, u.max_questions, CAST(IFNULL(SUM(IF(r.Window_start = %?, r.Questions, 0)), 0) AS UNSIGNED),
+		u.max_updates, CAST(IFNULL(SUM(IF(r.Window_start = %?, r.Updates, 0)), 0) AS UNSIGNED),
+		u.max_connections, CAST(IFNULL(SUM(IF(r.Window_start = %?, r.Connections, 0)), 0) AS UNSIGNED),
+		u.max_user_connections, CAST(IFNULL(SUM(r.User_connections), 0) AS UNSIGNED)
+		FROM %n.%n u LEFT JOIN %n.%n r ON u.Host = r.Host AND u.User = r.User
+		WHERE (u.max_questions > 0 OR u.max_updates > 0 OR u.max_connections > 0 OR u.max_user_connections > 0)`,
+		window, window, window, mysql.SystemDB, mysql.UserTable, mysql.SystemDB, mysql.UserResourceUsageTable)
+	// Only the usage of the current account is visible without the PROCESS privilege.
+	if loginUser := sctx.GetSessionVars().User; !hasPriv(sctx, mysql.ProcessPriv) && loginUser != nil {
+		sqlexec.MustFormatSQL(sql, " AND u.User = %? AND u.Host = %?", loginUser.AuthUsername, loginUser.AuthHostname)
+	}
+	sqlexec.MustFormatSQL(sql, " GROUP BY u.User, u.Host, u.max_questions, u.max_updates, u.max_connections, u.max_user_connections ORDER BY u.User, u.Host")
+
+	exec := sctx.(sqlexec.RestrictedSQLExecutor)
+	stmt, err := exec.ParseWithParams(ctx, sql.String())
+	if err != nil {
+		return err
+	}
+	rows, _, err := exec.ExecRestrictedStmt(ctx, stmt)
+	if err != nil {
+		return err
+	}
+	records := make([][]types.Datum, 0, len(rows))
+	for _, row := range rows {
+		record := types.MakeDatums(row.GetString(0), row.GetString(1))
+		for i := 2; i < row.Len(); i++ {
+			record = append(record, types.NewUintDatum(row.GetUint64(i)))
+		}
+		records = append(records, record)
+	}
+	e.rows = records
+	return nil
+}
+
+func (e *memtableRetriever) setDataForMemoryKillHistory(ctx sessionctx.Context) {
+	loginUser := ctx.GetSessionVars().User
+	hasProcessPriv := hasPriv(ctx, mysql.ProcessPriv)
+	var records [][]types.Datum
+	for _, record := range expensivequery.MemoryKillRecords() {
+		// The same as processlist, only the statements of the current user are visible without the PROCESS privilege.
+		if !hasProcessPriv && loginUser != nil && record.User != loginUser.Username {
+			continue
+		}
+		records = append(records, types.MakeDatums(
+			types.NewTime(types.FromGoTime(record.Time), mysql.TypeDatetime, types.MaxFsp),
+			record.Quota,
+			record.QuotaBytes,
+			record.UsedBytes,
+			record.StmtBytes,
+			record.ConnID,
+			record.User,
+			record.Host,
+			record.DB,
+			record.Digest,
+			record.SQL,
+		))
+	}
+	e.rows = records
+}
+
+func (e *memtableRetriever) setDataForInstancePlanCache(ctx sessionctx.Context) error {
+	// The cached statements are shared by all the users.
+	if !hasPriv(ctx, mysql.ProcessPriv) {
+		return plannercore.ErrSpecificAccessDenied.GenWithStackByArgs("PROCESS")
+	}
+	entries := plannercore.InstancePlanCacheEntries()
+	records := make([][]types.Datum, 0, len(entries))
+	for _, entry := range entries {
+		lastHitTime := types.NewDatum(nil)
+		if !entry.LastHitTime.IsZero() {
+			lastHitTime = types.NewTimeDatum(types.NewTime(types.FromGoTime(entry.LastHitTime), mysql.TypeDatetime, types.MaxFsp))
+		}
+		record := types.MakeDatums(
+			entry.SQLText,
+			entry.DB,
+			entry.SchemaVersion,
+			entry.ParamTypes,
+			entry.PlanDigest,
+			entry.MemUsage,
+			entry.Hits,
+			types.NewTime(types.FromGoTime(entry.CreateTime), mysql.TypeDatetime, types.MaxFsp),
+		)
+		records = append(records, append(record, lastHitTime))
+	}
+	e.rows = records
+	return nil
+}
+
 func (e *memtableRetriever) setDataFromUserPrivileges(ctx sessionctx.Context) {
 	pm := privilege.GetPrivilegeManager(ctx)
 	// The results depend on the user querying the information.
@@ -1645,6 +1752,48 @@ func (e *memtableRetriever) setDataFromTableConstraints(ctx sessionctx.Context,
 				)
 				rows = append(rows, record)
 			}
+
+			for _, constraint := range tbl.Constraints {
+				if constraint.State != model.StatePublic {
+					continue
+				}
+				record := types.MakeDatums(
+					infoschema.CatalogVal,          // CONSTRAINT_CATALOG
+					schema.Name.O,                  // CONSTRAINT_SCHEMA
+					constraint.Name.O,              // CONSTRAINT_NAME
+					schema.Name.O,                  // TABLE_SCHEMA
+					tbl.Name.O,                     // TABLE_NAME
+					infoschema.CheckConstraintType, // CONSTRAINT_TYPE
+				)
+				rows = append(rows, record)
+			}
+		}
+	}
+	e.rows = rows
+}
+
+// setDataFromCheckConstraints constructs data for table information_schema.check_constraints.
+// See https://dev.mysql.com/doc/refman/8.0/en/information-schema-check-constraints-table.html
+func (e *memtableRetriever) setDataFromCheckConstraints(ctx sessionctx.Context, schemas []*model.DBInfo) {
+	checker := privilege.GetPrivilegeManager(ctx)
+	var rows [][]types.Datum
+	for _, schema := range schemas {
+		for _, tbl := range schema.Tables {
+			if checker != nil && !checker.RequestVerification(ctx.GetSessionVars().ActiveRoles, schema.Name.L, tbl.Name.L, "", mysql.AllPrivMask) {
+				continue
+			}
+			for _, constraint := range tbl.Constraints {
+				if constraint.State != model.StatePublic {
+					continue
+				}
+				record := types.MakeDatums(
+					infoschema.CatalogVal, // CONSTRAINT_CATALOG
+					schema.Name.O,         // CONSTRAINT_SCHEMA
+					constraint.Name.O,     // CONSTRAINT_NAME
+					fmt.Sprintf("(%s)", constraint.ExprString), // CHECK_CLAUSE
+				)
+				rows = append(rows, record)
+			}
 		}
 	}
 	e.rows = rows
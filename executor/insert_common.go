@@ -92,6 +92,11 @@ type InsertValues struct {
 	// We use mutex to protect routine from using invalid txn.
 	isLoadData bool
 	txnInUse   sync.Mutex
+
+	fkChecks   []*FKCheckExec
+	fkCascades []*FKCascadeExec
+
+	checkConstraints []*table.Constraint
 }
 
 type defaultVal struct {
@@ -1116,9 +1121,20 @@ func (e *InsertValues) addRecord(ctx context.Context, row []types.Datum) error {
 
 func (e *InsertValues) addRecordWithAutoIDHint(ctx context.Context, row []types.Datum, reserveAutoIDCount int) (err error) {
 	vars := e.ctx.GetSessionVars()
+	if err = table.CheckRowConstraints(e.ctx, e.checkConstraints, row); err != nil {
+		// For `INSERT IGNORE`, the record violating the check constraints is discarded with a warning.
+		if vars.StmtCtx.DupKeyAsWarning && table.ErrCheckConstraintViolated.Equal(err) {
+			vars.StmtCtx.AppendWarning(err)
+			return nil
+		}
+		return err
+	}
 	if !vars.ConstraintCheckInPlace {
 		vars.PresumeKeyNotExists = true
 	}
+	if len(e.fkChecks) > 0 {
+		return e.addRecordWithFKCheck(ctx, row, reserveAutoIDCount)
+	}
 	if reserveAutoIDCount > 0 {
 		_, err = e.Table.AddRecord(e.ctx, row, table.WithCtx(ctx), table.WithReserveAutoIDHint(reserveAutoIDCount))
 	} else {
@@ -1135,6 +1151,43 @@ func (e *InsertValues) addRecordWithAutoIDHint(ctx context.Context, row []types.
 	return nil
 }
 
+// addRecordWithFKCheck adds the record and checks the foreign key constraints on it. For `INSERT IGNORE`,
+// the record violating the constraints is discarded with a warning.
+func (e *InsertValues) addRecordWithFKCheck(ctx context.Context, row []types.Datum, reserveAutoIDCount int) error {
+	vars := e.ctx.GetSessionVars()
+	txn, err := e.ctx.Txn(true)
+	if err != nil {
+		return err
+	}
+	memBuffer := txn.GetMemBuffer()
+	sh := memBuffer.Staging()
+	defer memBuffer.Cleanup(sh)
+	if reserveAutoIDCount > 0 {
+		_, err = e.Table.AddRecord(e.ctx, row, table.WithCtx(ctx), table.WithReserveAutoIDHint(reserveAutoIDCount))
+	} else {
+		_, err = e.Table.AddRecord(e.ctx, row, table.WithCtx(ctx))
+	}
+	vars.PresumeKeyNotExists = false
+	if err != nil {
+		return err
+	}
+	for _, fkc := range e.fkChecks {
+		if err = fkc.insertRowNeedToCheck(ctx, row); err != nil {
+			if vars.StmtCtx.DupKeyAsWarning && ErrNoReferencedRow2.Equal(err) {
+				vars.StmtCtx.AppendWarning(err)
+				return nil
+			}
+			return err
+		}
+	}
+	memBuffer.Release(sh)
+	vars.StmtCtx.AddAffectedRows(1)
+	if e.lastInsertID != 0 {
+		vars.SetLastInsertID(e.lastInsertID)
+	}
+	return nil
+}
+
 // InsertRuntimeStat record the stat about insert and check
 type InsertRuntimeStat struct {
 	*execdetails.BasicRuntimeStats
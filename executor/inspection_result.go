@@ -21,6 +21,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
@@ -1190,6 +1191,36 @@ func (c checkStoreRegionTooMuch) getItem() string {
 	return "region-count"
 }
 
+// checkClusterTLSCertExpire checks whether the cluster TLS certificates of TiDB are not rotated
+// and will expire in an hour.
+type checkClusterTLSCertExpire struct{}
+
+func (c checkClusterTLSCertExpire) genSQL(timeRange plannercore.QueryTimeRange) string {
+	return fmt.Sprintf(`select instance, type, max(value) - %d as remaining from metrics_schema.tidb_cluster_tls_cert_expire_time %s group by instance, type having remaining < 3600`,
+		timeRange.To.Unix(), timeRange.Condition())
+}
+
+func (c checkClusterTLSCertExpire) genResult(sql string, row chunk.Row) inspectionResult {
+	remaining := row.GetFloat64(2)
+	severity, detail := "warning", fmt.Sprintf("the cluster TLS %s will expire in %s", row.GetString(1), time.Duration(remaining)*time.Second)
+	if remaining <= 0 {
+		severity, detail = "critical", fmt.Sprintf("the cluster TLS %s has expired", row.GetString(1))
+	}
+	return inspectionResult{
+		tp:       "tidb",
+		instance: row.GetString(0),
+		item:     c.getItem(),
+		actual:   fmt.Sprintf("%.0fs", remaining),
+		expected: ">= 3600s",
+		severity: severity,
+		detail:   detail,
+	}
+}
+
+func (c checkClusterTLSCertExpire) getItem() string {
+	return "cluster-tls-cert-expire"
+}
+
 func (thresholdCheckInspection) inspectThreshold3(ctx context.Context, sctx sessionctx.Context, filter inspectionFilter) []inspectionResult {
 	var rules = []ruleChecker{
 		compareStoreStatus{
@@ -1209,6 +1240,7 @@ func (thresholdCheckInspection) inspectThreshold3(ctx context.Context, sctx sess
 		},
 		checkRegionHealth{},
 		checkStoreRegionTooMuch{},
+		checkClusterTLSCertExpire{},
 	}
 	return checkRules(ctx, sctx, filter, rules)
 }
@@ -0,0 +1,266 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"context"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/memory"
+)
+
+// JSONTableExec generates the rows of JSON_TABLE from the JSON document.
+// The document is evaluated in Open, so the executor can be reopened for every
+// outer row when it is the inner child of an Apply.
+type JSONTableExec struct {
+	baseExecutor
+
+	doc    expression.Expression
+	asName model.CIStr
+	root   *jsonTableNode
+	// sc is used to convert the values to the column types, the conversion errors
+	// are not ignored or turned into warnings by it, so that ON ERROR can take effect.
+	sc *stmtctx.StatementContext
+
+	rows   [][]types.Datum
+	cursor int
+	// memTracker tracks the memory of the rows, which are all generated in Open.
+	memTracker *memory.Tracker
+}
+
+// jsonTableNode is the row path of JSON_TABLE or a NESTED PATH, which produces
+// one row for every value matched by the path.
+type jsonTableNode struct {
+	path    json.PathExpression
+	columns []*jsonTableColumn
+	nested  []*jsonTableNode
+	// begin and end are the offsets of the columns of the node, including the
+	// columns of the nested paths.
+	begin, end int
+}
+
+// jsonTableColumn is a column which is not a NESTED PATH.
+type jsonTableColumn struct {
+	*ast.JSONTableColumn
+	offset int
+	ft     *types.FieldType
+	path   json.PathExpression
+	// onEmpty and onError are the values of the DEFAULT responses.
+	onEmpty types.Datum
+	onError types.Datum
+}
+
+func buildJSONTableNode(sc *stmtctx.StatementContext, path string, cols []*ast.JSONTableColumn, schema *expression.Schema, offset *int) (*jsonTableNode, error) {
+	pe, err := json.ParseJSONPathExpr(path)
+	if err != nil {
+		return nil, err
+	}
+	node := &jsonTableNode{path: pe, begin: *offset}
+	for _, col := range cols {
+		if col.Tp == ast.JSONTableColumnNested {
+			child, err := buildJSONTableNode(sc, col.Path, col.Columns, schema, offset)
+			if err != nil {
+				return nil, err
+			}
+			node.nested = append(node.nested, child)
+			continue
+		}
+		c := &jsonTableColumn{JSONTableColumn: col, offset: *offset, ft: schema.Columns[*offset].RetType}
+		*offset++
+		if col.Tp == ast.JSONTableColumnOrdinality {
+			node.columns = append(node.columns, c)
+			continue
+		}
+		if c.path, err = json.ParseJSONPathExpr(col.Path); err != nil {
+			return nil, err
+		}
+		if col.OnEmpty != nil && col.OnEmpty.Tp == ast.JSONTableOnResponseDefault {
+			if c.onEmpty, err = c.convertDefault(sc, col.OnEmpty.Default); err != nil {
+				return nil, err
+			}
+		}
+		if col.OnError != nil && col.OnError.Tp == ast.JSONTableOnResponseDefault {
+			if c.onError, err = c.convertDefault(sc, col.OnError.Default); err != nil {
+				return nil, err
+			}
+		}
+		node.columns = append(node.columns, c)
+	}
+	node.end = *offset
+	return node, nil
+}
+
+// Open implements the Executor Open interface.
+func (e *JSONTableExec) Open(ctx context.Context) error {
+	if e.memTracker == nil {
+		e.memTracker = memory.NewTracker(e.id, -1)
+		e.memTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.MemTracker)
+	}
+	e.memTracker.Consume(-e.memTracker.BytesConsumed())
+	e.rows = e.rows[:0]
+	e.cursor = 0
+	doc, isNull, err := e.doc.EvalJSON(e.ctx, chunk.Row{})
+	if err != nil || isNull {
+		return err
+	}
+	row := make([]types.Datum, e.Schema().Len())
+	return e.root.produce(e, doc, row)
+}
+
+// Next implements the Executor Next interface.
+func (e *JSONTableExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	for ; e.cursor < len(e.rows) && req.NumRows() < e.maxChunkSize; e.cursor++ {
+		for i := range e.rows[e.cursor] {
+			req.AppendDatum(i, &e.rows[e.cursor][i])
+		}
+	}
+	return nil
+}
+
+// Close implements the Executor Close interface.
+func (e *JSONTableExec) Close() error {
+	e.rows = nil
+	if e.memTracker != nil {
+		e.memTracker.Consume(-e.memTracker.BytesConsumed())
+	}
+	return e.baseExecutor.Close()
+}
+
+// produce appends a row for every value matched by the path in the document.
+func (n *jsonTableNode) produce(e *JSONTableExec, doc json.BinaryJSON, row []types.Datum) error {
+	for i, val := range doc.ExtractAll(n.path) {
+		for _, col := range n.columns {
+			d, err := col.eval(e, val, i+1)
+			if err != nil {
+				return err
+			}
+			row[col.offset] = d
+		}
+		if err := n.produceNested(e, val, row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// produceNested appends the rows of the nested paths. The sibling nested paths
+// are joined like a UNION, the columns of the other siblings are NULL. If none of
+// them matches anything, a single row with all the nested columns being NULL is
+// appended, like a LEFT JOIN.
+func (n *jsonTableNode) produceNested(e *JSONTableExec, val json.BinaryJSON, row []types.Datum) error {
+	numRows := len(e.rows)
+	for _, child := range n.nested {
+		n.setNestedNull(row)
+		if err := child.produce(e, val, row); err != nil {
+			return err
+		}
+	}
+	if len(n.nested) == 0 || len(e.rows) == numRows {
+		n.setNestedNull(row)
+		e.rows = append(e.rows, append([]types.Datum(nil), row...))
+		e.memTracker.Consume(types.EstimatedMemUsage(row, 1))
+	}
+	return nil
+}
+
+func (n *jsonTableNode) setNestedNull(row []types.Datum) {
+	for _, child := range n.nested {
+		for i := child.begin; i < child.end; i++ {
+			row[i].SetNull()
+		}
+	}
+}
+
+func (c *jsonTableColumn) eval(e *JSONTableExec, val json.BinaryJSON, ordinality int) (types.Datum, error) {
+	sc := e.sc
+	switch c.Tp {
+	case ast.JSONTableColumnOrdinality:
+		return types.NewUintDatum(uint64(ordinality)), nil
+	case ast.JSONTableColumnExistsPath:
+		_, found := val.Extract([]json.PathExpression{c.path})
+		d := types.NewIntDatum(0)
+		if found {
+			d.SetInt64(1)
+		}
+		return d.ConvertTo(sc, c.ft)
+	}
+
+	res, found := val.Extract([]json.PathExpression{c.path})
+	if !found {
+		return c.respond(c.OnEmpty, c.onEmpty, ErrMissingJSONTableValue.GenWithStackByArgs(c.Name.O))
+	}
+	if c.ft.Tp == mysql.TypeJSON {
+		return types.NewJSONDatum(res), nil
+	}
+	var d types.Datum
+	switch res.TypeCode {
+	case json.TypeCodeObject, json.TypeCodeArray:
+		return c.respond(c.OnError, c.onError, ErrWrongJSONTableValue.GenWithStackByArgs(c.Name.O, e.asName.O))
+	case json.TypeCodeLiteral:
+		if res.Value[0] == json.LiteralNil {
+			return d, nil
+		}
+		d = types.NewJSONDatum(res)
+	case json.TypeCodeString:
+		d = types.NewStringDatum(string(res.GetString()))
+	default:
+		d = types.NewJSONDatum(res)
+	}
+	d, err := d.ConvertTo(sc, c.ft)
+	if err != nil {
+		if types.ErrOverflow.Equal(err) {
+			err = ErrJSONTableValueOutOfRange.GenWithStackByArgs(c.Name.O)
+		}
+		return c.respond(c.OnError, c.onError, err)
+	}
+	return d, nil
+}
+
+// respond returns the value of the ON EMPTY or ON ERROR clause, NULL is returned
+// if the clause is absent.
+func (c *jsonTableColumn) respond(resp *ast.JSONTableOnResponse, def types.Datum, err error) (types.Datum, error) {
+	if resp == nil {
+		return types.Datum{}, nil
+	}
+	switch resp.Tp {
+	case ast.JSONTableOnResponseError:
+		return types.Datum{}, err
+	case ast.JSONTableOnResponseDefault:
+		return def, nil
+	}
+	return types.Datum{}, nil
+}
+
+func (c *jsonTableColumn) convertDefault(sc *stmtctx.StatementContext, val string) (types.Datum, error) {
+	if c.ft.Tp == mysql.TypeJSON {
+		j, err := json.ParseBinaryFromString(val)
+		if err != nil {
+			return types.Datum{}, err
+		}
+		return types.NewJSONDatum(j), nil
+	}
+	d := types.NewStringDatum(val)
+	d, err := d.ConvertTo(sc, c.ft)
+	return d, errors.Trace(err)
+}
@@ -0,0 +1,109 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONTable(t *testing.T) {
+	t.Parallel()
+
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+
+	tk.MustQuery(`select * from json_table('[{"a": 1, "b": "x"}, {"a": 2}, {"b": "z"}]', '$[*]' columns (
+		id for ordinality, a int path '$.a', b varchar(10) path '$.b', c int exists path '$.b')) as jt`).Check(testkit.Rows(
+		"1 1 x 1", "2 2 <nil> 0", "3 <nil> z 1"))
+	tk.MustQuery(`select * from json_table('{"a": [1, 2]}', '$' columns (a json path '$.a', b int path '$.a')) as jt`).Check(testkit.Rows(
+		"[1, 2] <nil>"))
+	tk.MustQuery(`select * from json_table(null, '$[*]' columns (a int path '$')) as jt`).Check(testkit.Rows())
+	tk.MustQuery(`select jt.a + 1 from json_table('[1, 2, 3]', '$[*]' columns (a int path '$')) jt where jt.a > 1 order by jt.a desc`).Check(testkit.Rows(
+		"4", "3"))
+
+	// ON EMPTY and ON ERROR.
+	tk.MustQuery(`select * from json_table('[{"a": "x"}, {}]', '$[*]' columns (
+		a int path '$.a' default '-1' on empty default '-2' on error,
+		b varchar(10) path '$.b' default 'none' on empty)) as jt`).Check(testkit.Rows(
+		"-2 none", "-1 none"))
+	tk.MustGetErrCode(`select * from json_table('[{}]', '$[*]' columns (a int path '$.a' error on empty)) as jt`, errno.ErrMissingJSONTableValue)
+	tk.MustGetErrMsg(`select * from json_table('[{"a": [1]}]', '$[*]' columns (a int path '$.a' error on error)) as jt`,
+		"[executor:3666]Can't store an array or an object in the scalar column 'a' of JSON_TABLE 'jt'.")
+	tk.MustGetErrCode(`select * from json_table('[{"a": 1000}]', '$[*]' columns (a tinyint path '$.a' error on error)) as jt`, errno.ErrJSONTableValueOutOfRange)
+
+	// NESTED PATH.
+	tk.MustQuery(`select * from json_table('[{"a": 1, "b": [11, 111]}, {"a": 2, "b": [22, 222]}, {"a": 3}]', '$[*]' columns (
+		a int path '$.a',
+		nested path '$.b[*]' columns (b1 int path '$', n1 for ordinality),
+		nested path '$.b[*]' columns (b2 int path '$'))) as jt`).Check(testkit.Rows(
+		"1 11 1 <nil>", "1 111 2 <nil>", "1 <nil> <nil> 11", "1 <nil> <nil> 111",
+		"2 22 1 <nil>", "2 222 2 <nil>", "2 <nil> <nil> 22", "2 <nil> <nil> 222",
+		"3 <nil> <nil> <nil>"))
+	tk.MustQuery(`select * from json_table('{"a": [{"b": [1, 2]}, {"b": []}]}', '$.a[*]' columns (
+		id for ordinality, nested path '$.b[*]' columns (b int path '$'))) as jt`).Check(testkit.Rows(
+		"1 1", "1 2", "2 <nil>"))
+
+	tk.MustGetErrCode(`select * from json_table('[1]', '$[*]' columns (a int path '$', a int path '$')) as jt`, errno.ErrDupFieldName)
+	tk.MustGetErrCode(`select * from json_table('[1]', '$[*' columns (a int path '$')) as jt`, errno.ErrInvalidJSONPath)
+}
+
+func TestJSONTableMemQuota(t *testing.T) {
+	t.Parallel()
+
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+
+	// The rows generated from the document are counted in the memory quota of the query.
+	sql := `select count(*) from json_table(concat('[', repeat('"abcdefghij", ', 10000), '"z"]'), '$[*]' columns (a varchar(10) path '$')) as jt`
+	tk.MustQuery(sql).Check(testkit.Rows("10001"))
+	tk.MustExec("set @@tidb_mem_quota_query = 100000")
+	err := tk.ExecToErr(sql)
+	require.Error(t, err)
+	require.Regexp(t, "Out Of Memory Quota!.*", err.Error())
+}
+
+func TestJSONTableLateral(t *testing.T) {
+	t.Parallel()
+
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (id int primary key, j json)")
+	tk.MustExec(`insert into t values (1, '[1, 2]'), (2, '[]'), (3, null), (4, '[3]')`)
+
+	tk.MustQuery(`select t.id, jt.a from t, json_table(t.j, '$[*]' columns (a int path '$')) as jt order by t.id, jt.a`).Check(testkit.Rows(
+		"1 1", "1 2", "4 3"))
+	tk.MustQuery(`select t.id, jt.a from t join json_table(t.j, '$[*]' columns (a int path '$')) as jt on jt.a > 1 order by t.id, jt.a`).Check(testkit.Rows(
+		"1 2", "4 3"))
+	tk.MustQuery(`select t.id, jt.a from t left join json_table(t.j, '$[*]' columns (a int path '$')) as jt on true order by t.id, jt.a`).Check(testkit.Rows(
+		"1 1", "1 2", "2 <nil>", "3 <nil>", "4 3"))
+	tk.MustQuery(`select t.id, (select sum(a) from json_table(t.j, '$[*]' columns (a int path '$')) as jt) from t order by t.id`).Check(testkit.Rows(
+		"1 3", "2 <nil>", "3 <nil>", "4 3"))
+	tk.MustQuery(`select t.id, jt.a from t, json_table('[1, 2]', '$[*]' columns (a int path '$')) as jt where t.id = jt.a order by t.id`).Check(testkit.Rows(
+		"1 1", "2 2"))
+	tk.MustGetErrCode(`select * from t right join json_table(t.j, '$[*]' columns (a int path '$')) as jt on true`, errno.ErrJSONTableForbiddenJoinType)
+}
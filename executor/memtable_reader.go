@@ -42,6 +42,7 @@ import (
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/clustertls"
 	"github.com/pingcap/tidb/util/execdetails"
 	"github.com/pingcap/tidb/util/pdapi"
 	"github.com/pingcap/tidb/util/set"
@@ -380,13 +381,11 @@ func serverInfoItemToRows(items []*diagnosticspb.ServerInfoItem, tp, addr string
 
 func getServerInfoByGRPC(ctx context.Context, address string, tp diagnosticspb.ServerInfoType) ([]*diagnosticspb.ServerInfoItem, error) {
 	opt := grpc.WithInsecure()
-	security := config.GetGlobalConfig().Security
-	if len(security.ClusterSSLCA) != 0 {
-		clusterSecurity := security.ClusterSecurity()
-		tlsConfig, err := clusterSecurity.ToTLSConfig()
-		if err != nil {
-			return nil, errors.Trace(err)
-		}
+	m, err := clustertls.Global()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if tlsConfig := m.ClientTLSConfig(); tlsConfig != nil {
 		opt = grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))
 	}
 	conn, err := grpc.Dial(address, opt)
@@ -548,13 +547,11 @@ func (e *clusterLogRetriever) startRetrieving(
 	req *diagnosticspb.SearchLogRequest) ([]chan logStreamResult, error) {
 	// gRPC options
 	opt := grpc.WithInsecure()
-	security := config.GetGlobalConfig().Security
-	if len(security.ClusterSSLCA) != 0 {
-		clusterSecurity := security.ClusterSecurity()
-		tlsConfig, err := clusterSecurity.ToTLSConfig()
-		if err != nil {
-			return nil, errors.Trace(err)
-		}
+	m, err := clustertls.Global()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if tlsConfig := m.ClientTLSConfig(); tlsConfig != nil {
 		opt = grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))
 	}
 
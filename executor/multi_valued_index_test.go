@@ -0,0 +1,109 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiValuedIndexDDLAndDML(t *testing.T) {
+	t.Parallel()
+
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+
+	tk.MustQuery(`select cast(cast('[1, "2", 3.0]' as json) as signed array)`).Check(testkit.Rows("[1, 2, 3]"))
+	tk.MustQuery(`select cast(cast('1' as json) as unsigned array)`).Check(testkit.Rows("[1]"))
+	tk.MustGetErrCode(`select cast('[1, 2]' as signed array)`, errno.ErrNotSupportedYet)
+	err := tk.QueryToErr(`select cast(cast('{"a": 1}' as json) as signed array)`)
+	require.EqualError(t, err, "[expression:1235]This version of TiDB doesn't yet support 'CAST-ing JSON OBJECT type to array'")
+	tk.MustGetErrCode(`select cast(cast('[1]' as json) as decimal(10, 2) array)`, errno.ErrNotSupportedYet)
+
+	tk.MustExec("create table t (id int primary key, j json, index idx((cast(j->'$.tags' as unsigned array))))")
+	tk.MustGetErrCode("create table t1 (j json, unique index idx((cast(j as signed array))))", errno.ErrUnsupportedDDLOperation)
+	tk.MustGetErrCode("create table t1 (j json, index idx((cast(j->'$.a' as signed array)), (cast(j->'$.b' as signed array))))", errno.ErrUnsupportedDDLOperation)
+
+	tk.MustExec(`insert into t values (1, '{"tags": [1, 2, 2]}'), (2, '{"tags": [3]}'), (3, '{"tags": []}'), (4, null), (5, '{"tags": 2}')`)
+	tk.MustGetErrCode(`insert into t values (6, '{"tags": [[1]]}')`, errno.ErrNotSupportedYet)
+	tk.MustExec("admin check table t")
+	tk.MustQuery("select id from t where 2 member of (j->'$.tags') order by id").Check(testkit.Rows("1", "5"))
+
+	tk.MustExec(`update t set j = '{"tags": [5]}' where id = 1`)
+	tk.MustExec("delete from t where id = 2")
+	tk.MustExec("admin check table t")
+	tk.MustQuery("select id from t where 5 member of (j->'$.tags')").Check(testkit.Rows("1"))
+	tk.MustQuery("select id from t where 2 member of (j->'$.tags')").Check(testkit.Rows("5"))
+	tk.MustQuery("select id from t where 3 member of (j->'$.tags')").Check(testkit.Rows())
+
+	tk.MustExec("create table t2 (id int primary key, j json)")
+	tk.MustExec(`insert into t2 values (1, '[1, 2, 2]'), (2, '["a"]')`)
+	tk.MustExec("alter table t2 add index idx((cast(j as char(10) array)))")
+	tk.MustGetErrCode("alter table t2 add unique index uk((cast(j as char(10) array)))", errno.ErrUnsupportedDDLOperation)
+	tk.MustExec("admin check table t2")
+	tk.MustQuery(`select id from t2 where '2' member of (j)`).Check(testkit.Rows())
+	tk.MustQuery(`select id from t2 where json_overlaps(j, '["a", "b"]')`).Check(testkit.Rows("2"))
+}
+
+func TestMultiValuedIndexQuery(t *testing.T) {
+	t.Parallel()
+
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t (id int primary key, a int, j json, index idx((cast(j->'$.tags' as signed array))))")
+	tk.MustExec(`insert into t values (1, 1, '{"tags": [1, 2, 3]}'), (2, 2, '{"tags": [3, 4]}'), (3, 3, '{"tags": [5]}'), (4, 4, '{"tags": []}'), (5, 5, null)`)
+
+	hasIndexMerge := func(sql string) bool {
+		return tk.HasPlan(sql, "IndexMerge")
+	}
+	for _, sql := range []string{
+		"select id from t where 3 member of (j->'$.tags')",
+		"select id from t where json_contains(j->'$.tags', '[3, 4]')",
+		"select id from t where json_overlaps(j->'$.tags', '[2, 5]')",
+		"select id from t where json_overlaps('[2, 5]', j->'$.tags')",
+		"select /*+ use_index(t, idx) */ id from t where 3 member of (j->'$.tags') and a > 1",
+	} {
+		require.True(t, hasIndexMerge(sql), sql)
+	}
+	require.False(t, hasIndexMerge("select id from t where json_contains(j->'$.tags', '[]')"))
+	require.False(t, hasIndexMerge("select id from t where a member of (j->'$.tags')"))
+	require.False(t, hasIndexMerge("select /*+ no_index_merge() */ id from t where 3 member of (j->'$.tags')"))
+
+	tk.MustQuery("select id from t where 3 member of (j->'$.tags') order by id").Check(testkit.Rows("1", "2"))
+	tk.MustQuery("select id from t where '3' member of (j->'$.tags')").Check(testkit.Rows())
+	tk.MustQuery("select id from t where json_contains(j->'$.tags', '[3, 4]')").Check(testkit.Rows("2"))
+	tk.MustQuery("select id from t where json_contains(j->'$.tags', '[]') order by id").Check(testkit.Rows("1", "2", "3", "4"))
+	tk.MustQuery("select id from t where json_overlaps(j->'$.tags', '[2, 5]') order by id").Check(testkit.Rows("1", "3"))
+	tk.MustQuery("select id from t where json_overlaps('[2, 5]', j->'$.tags') and a > 1").Check(testkit.Rows("3"))
+	tk.MustQuery("select count(*) from t where 3 member of (j->'$.tags') or a = 5").Check(testkit.Rows("3"))
+
+	// The index isn't used in the write transaction.
+	tk.MustExec("begin")
+	tk.MustExec(`insert into t values (6, 6, '{"tags": [3]}')`)
+	require.False(t, hasIndexMerge("select id from t where 3 member of (j->'$.tags')"))
+	tk.MustQuery("select id from t where 3 member of (j->'$.tags') order by id").Check(testkit.Rows("1", "2", "6"))
+	tk.MustExec("commit")
+	tk.MustQuery("select id from t where 3 member of (j->'$.tags') order by id").Check(testkit.Rows("1", "2", "6"))
+	tk.MustExec("admin check table t")
+}
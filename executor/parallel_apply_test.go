@@ -16,14 +16,17 @@ package executor_test
 
 import (
 	"fmt"
+	"math"
 	"strings"
 
 	. "github.com/pingcap/check"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/session"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/util/collate"
 	"github.com/pingcap/tidb/util/israce"
+	"github.com/pingcap/tidb/util/kvcache"
 	"github.com/pingcap/tidb/util/testkit"
 )
 
@@ -409,6 +412,14 @@ func (s *testSerialSuite) TestApplyWithOtherFeatures(c *C) {
 	// plan cache
 	orgEnable := core.PreparedPlanCacheEnabled()
 	core.SetPreparedPlanCache(true)
+	orgSe := tk.Se
+	var err error
+	tk.Se, err = session.CreateSession4TestWithOpt(s.store, &session.Opt{
+		PreparedPlanCache: kvcache.NewSimpleLRUCache(100, 0.1, math.MaxUint64),
+	})
+	c.Assert(err, IsNil)
+	tk.MustExec("use test")
+	tk.MustExec("set tidb_enable_parallel_apply=true")
 	tk.MustExec("drop table if exists t1, t2")
 	tk.MustExec("create table t1(a int, b int)")
 	tk.MustExec("create table t2(a int, b int)")
@@ -419,8 +430,9 @@ func (s *testSerialSuite) TestApplyWithOtherFeatures(c *C) {
 	tk.MustQuery("execute stmt using @a").Sort().Check(testkit.Rows("1 1", "1 5", "2 3", "2 4"))
 	tk.MustExec("set @a=2")
 	tk.MustQuery("execute stmt using @a").Sort().Check(testkit.Rows("1 5", "2 3", "2 4"))
-	tk.MustQuery(" select @@last_plan_from_cache").Check(testkit.Rows("0")) // sub-queries are not cacheable
+	tk.MustQuery(" select @@last_plan_from_cache").Check(testkit.Rows("1"))
 	core.SetPreparedPlanCache(orgEnable)
+	tk.Se = orgSe
 
 	// cluster index
 	tk.Se.GetSessionVars().EnableClusteredIndex = variable.ClusteredIndexDefModeOn
@@ -0,0 +1,414 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/auth"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/privilege/privileges"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/util/sqlexec"
+)
+
+// passwordOrLockOptions is the password management and account locking options of CREATE USER and ALTER USER.
+type passwordOrLockOptions struct {
+	// accountLocked is "Y" or "N", it's empty if the account is neither locked nor unlocked.
+	accountLocked   string
+	passwordExpired bool
+
+	// The following options are nil if they are set to DEFAULT, which means the global variables take effect.
+	lifetimeSpecified     bool
+	lifetime              interface{}
+	reuseHistorySpecified bool
+	reuseHistory          interface{}
+	reuseTimeSpecified    bool
+	reuseTime             interface{}
+
+	failedLoginAttempts *int64
+	// passwordLockTime is -1 if it's UNBOUNDED.
+	passwordLockTime *int64
+}
+
+func newPasswordOrLockOptions(options []*ast.PasswordOrLockOption) *passwordOrLockOptions {
+	o := &passwordOrLockOptions{}
+	for _, opt := range options {
+		count := opt.Count
+		switch opt.Type {
+		case ast.Lock:
+			o.accountLocked = "Y"
+		case ast.Unlock:
+			o.accountLocked = "N"
+		case ast.PasswordExpire:
+			o.passwordExpired = true
+		case ast.PasswordExpireDefault:
+			o.lifetimeSpecified, o.lifetime = true, nil
+		case ast.PasswordExpireNever:
+			o.lifetimeSpecified, o.lifetime = true, 0
+		case ast.PasswordExpireInterval:
+			o.lifetimeSpecified, o.lifetime = true, count
+		case ast.PasswordHistoryDefault:
+			o.reuseHistorySpecified, o.reuseHistory = true, nil
+		case ast.PasswordHistory:
+			o.reuseHistorySpecified, o.reuseHistory = true, count
+		case ast.PasswordReuseDefault:
+			o.reuseTimeSpecified, o.reuseTime = true, nil
+		case ast.PasswordReuseInterval:
+			o.reuseTimeSpecified, o.reuseTime = true, count
+		case ast.FailedLoginAttempts:
+			o.failedLoginAttempts = &count
+		case ast.PasswordLockTime:
+			o.passwordLockTime = &count
+		case ast.PasswordLockTimeUnbounded:
+			unbounded := int64(-1)
+			o.passwordLockTime = &unbounded
+		}
+	}
+	return o
+}
+
+// changesPasswordLocking reports whether the failed-login tracking attributes of the account should be rewritten.
+// Both setting the options and unlocking the account reset the consecutive failed logins.
+func (o *passwordOrLockOptions) changesPasswordLocking() bool {
+	return o.failedLoginAttempts != nil || o.passwordLockTime != nil || o.accountLocked == "N"
+}
+
+// passwordLocking returns the new failed-login tracking attributes based on the old ones.
+func (o *passwordOrLockOptions) passwordLocking(old privileges.PasswordLocking) privileges.PasswordLocking {
+	locking := privileges.PasswordLocking{
+		FailedLoginAttempts:  old.FailedLoginAttempts,
+		PasswordLockTimeDays: old.PasswordLockTimeDays,
+	}
+	if o.failedLoginAttempts != nil {
+		locking.FailedLoginAttempts = *o.failedLoginAttempts
+	}
+	if o.passwordLockTime != nil {
+		locking.PasswordLockTimeDays = *o.passwordLockTime
+	}
+	return locking
+}
+
+// userAttributes returns the User_attributes of a new account, it's nil if no failed-login tracking option is specified.
+func (o *passwordOrLockOptions) userAttributes() interface{} {
+	if o.failedLoginAttempts == nil && o.passwordLockTime == nil {
+		return nil
+	}
+	return o.passwordLocking(privileges.PasswordLocking{}).JSON()
+}
+
+// updateUserTable applies the options to an existing account.
+func (o *passwordOrLockOptions) updateUserTable(ctx context.Context, exec sqlexec.RestrictedSQLExecutor, user, host string) error {
+	assignments := make([]string, 0, 6)
+	args := []interface{}{mysql.SystemDB, mysql.UserTable}
+	set := func(column string, value interface{}) {
+		assignments = append(assignments, "%n=%?")
+		args = append(args, column, value)
+	}
+	if o.accountLocked != "" {
+		set("Account_locked", o.accountLocked)
+	}
+	if o.passwordExpired {
+		set("Password_expired", "Y")
+	}
+	if o.lifetimeSpecified {
+		set("Password_lifetime", o.lifetime)
+	}
+	if o.reuseHistorySpecified {
+		set("Password_reuse_history", o.reuseHistory)
+	}
+	if o.reuseTimeSpecified {
+		set("Password_reuse_time", o.reuseTime)
+	}
+	if o.changesPasswordLocking() {
+		old, err := loadPasswordLocking(ctx, exec, user, host)
+		if err != nil {
+			return err
+		}
+		set("User_attributes", o.passwordLocking(old).JSON())
+	}
+	if len(assignments) == 0 {
+		return nil
+	}
+	args = append(args, host, user)
+	stmt, err := exec.ParseWithParams(ctx, "UPDATE %n.%n SET "+strings.Join(assignments, ", ")+" WHERE Host=%? AND User=%?", args...)
+	if err != nil {
+		return err
+	}
+	_, _, err = exec.ExecRestrictedStmt(ctx, stmt)
+	return err
+}
+
+func loadPasswordLocking(ctx context.Context, exec sqlexec.RestrictedSQLExecutor, user, host string) (privileges.PasswordLocking, error) {
+	stmt, err := exec.ParseWithParams(ctx, "SELECT User_attributes FROM %n.%n WHERE Host=%? AND User=%?", mysql.SystemDB, mysql.UserTable, host, user)
+	if err != nil {
+		return privileges.PasswordLocking{}, err
+	}
+	rows, _, err := exec.ExecRestrictedStmt(ctx, stmt)
+	if err != nil || len(rows) == 0 || rows[0].IsNull(0) {
+		return privileges.PasswordLocking{}, err
+	}
+	return privileges.ParsePasswordLocking(rows[0].GetJSON(0).String())
+}
+
+// validatePassword checks the cleartext password against the validate_password_* policy if validate_password_enable is ON.
+func (e *SimpleExec) validatePassword(user, pwd string) error {
+	sessionVars := e.ctx.GetSessionVars()
+	getInt := func(name string) (int, error) {
+		val, err := sessionVars.GlobalVarsAccessor.GetGlobalSysVar(name)
+		if err != nil {
+			return 0, err
+		}
+		return strconv.Atoi(val)
+	}
+	enabled, err := sessionVars.GlobalVarsAccessor.GetGlobalSysVar(variable.ValidatePasswordEnable)
+	if err != nil || !variable.TiDBOptOn(enabled) {
+		return err
+	}
+
+	checkUserName, err := sessionVars.GlobalVarsAccessor.GetGlobalSysVar(variable.ValidatePasswordCheckUserName)
+	if err != nil {
+		return err
+	}
+	if variable.TiDBOptOn(checkUserName) && user != "" && (pwd == user || pwd == reverseString(user)) {
+		return ErrNotValidPassword.GenWithStackByArgs()
+	}
+	length, err := getInt(variable.ValidatePasswordLength)
+	if err != nil {
+		return err
+	}
+	if utf8.RuneCountInString(pwd) < length {
+		return ErrNotValidPassword.GenWithStackByArgs()
+	}
+
+	policy, err := sessionVars.GlobalVarsAccessor.GetGlobalSysVar(variable.ValidatePasswordPolicy)
+	if err != nil {
+		return err
+	}
+	if strings.EqualFold(policy, "LOW") {
+		return nil
+	}
+	var upper, lower, number, special int
+	for _, r := range pwd {
+		switch {
+		case unicode.IsUpper(r):
+			upper++
+		case unicode.IsLower(r):
+			lower++
+		case unicode.IsDigit(r):
+			number++
+		default:
+			special++
+		}
+	}
+	for name, count := range map[string]int{
+		variable.ValidatePasswordMixedCaseCount:   mathMin(upper, lower),
+		variable.ValidatePasswordNumberCount:      number,
+		variable.ValidatePasswordSpecialCharCount: special,
+	} {
+		required, err := getInt(name)
+		if err != nil {
+			return err
+		}
+		if count < required {
+			return ErrNotValidPassword.GenWithStackByArgs()
+		}
+	}
+	if strings.EqualFold(policy, "MEDIUM") {
+		return nil
+	}
+
+	dictionaryFile, err := sessionVars.GlobalVarsAccessor.GetGlobalSysVar(variable.ValidatePasswordDictionaryFile)
+	if err != nil || dictionaryFile == "" {
+		return err
+	}
+	words, err := loadPasswordDictionary(dictionaryFile)
+	if err != nil {
+		return err
+	}
+	// Any substring of the password whose length is at least 4 mustn't be a dictionary word.
+	lowerPwd := strings.ToLower(pwd)
+	for i := 0; i < len(lowerPwd); i++ {
+		for j := i + 4; j <= len(lowerPwd); j++ {
+			if _, ok := words[lowerPwd[i:j]]; ok {
+				return ErrNotValidPassword.GenWithStackByArgs()
+			}
+		}
+	}
+	return nil
+}
+
+func loadPasswordDictionary(path string) (map[string]struct{}, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	//nolint:errcheck
+	defer f.Close()
+	words := make(map[string]struct{})
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if word := strings.ToLower(strings.TrimSpace(scanner.Text())); word != "" {
+			words[word] = struct{}{}
+		}
+	}
+	return words, errors.Trace(scanner.Err())
+}
+
+func reverseString(s string) string {
+	runes := []rune(s)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
+
+func mathMin(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// newPassword is the password being set to an account.
+type newPassword struct {
+	// authString is the value stored in mysql.user.authentication_string.
+	authString string
+	// cleartext is empty if the statement specifies the hashed password only.
+	cleartext string
+}
+
+// matches reports whether the password is the same as the one stored in the password history.
+func (p *newPassword) matches(authString string) bool {
+	if authString == p.authString {
+		return true
+	}
+	if p.cleartext == "" {
+		return false
+	}
+	if strings.HasPrefix(authString, "$A$") {
+		ok, err := auth.CheckShaPassword([]byte(authString), p.cleartext)
+		return err == nil && ok
+	}
+	return authString == auth.EncodePassword(p.cleartext)
+}
+
+// passwordReusePolicy returns the password history and reuse interval of the account, the values of the account
+// take precedence over the password_history and password_reuse_interval global variables.
+func (e *SimpleExec) passwordReusePolicy(ctx context.Context, user, host string) (history, reuseTime int64, err error) {
+	exec := e.ctx.(sqlexec.RestrictedSQLExecutor)
+	stmt, err := exec.ParseWithParams(ctx, "SELECT Password_reuse_history, Password_reuse_time FROM %n.%n WHERE Host=%? AND User=%?",
+		mysql.SystemDB, mysql.UserTable, host, user)
+	if err != nil {
+		return 0, 0, err
+	}
+	rows, _, err := exec.ExecRestrictedStmt(ctx, stmt)
+	if err != nil {
+		return 0, 0, err
+	}
+	values := [2]int64{}
+	for i, name := range []string{variable.PasswordHistory, variable.PasswordReuseInterval} {
+		if len(rows) > 0 && !rows[0].IsNull(i) {
+			values[i] = int64(rows[0].GetUint64(i))
+			continue
+		}
+		val, err := e.ctx.GetSessionVars().GlobalVarsAccessor.GetGlobalSysVar(name)
+		if err != nil {
+			return 0, 0, err
+		}
+		if values[i], err = strconv.ParseInt(val, 10, 64); err != nil {
+			return 0, 0, errors.Trace(err)
+		}
+	}
+	return values[0], values[1], nil
+}
+
+// checkPasswordReuse returns ErrCredentialsContradictToHistory if the password is one of the latest `history`
+// passwords or one used within `reuseTime` days.
+func (e *SimpleExec) checkPasswordReuse(ctx context.Context, user, host string, pwd *newPassword, history, reuseTime int64) error {
+	if pwd.authString == "" || (history == 0 && reuseTime == 0) {
+		return nil
+	}
+	exec := e.ctx.(sqlexec.RestrictedSQLExecutor)
+	stmt, err := exec.ParseWithParams(ctx, "SELECT Password, Password_timestamp >= DATE_SUB(NOW(6), INTERVAL %? DAY) FROM %n.%n WHERE Host=%? AND User=%? ORDER BY Password_timestamp DESC",
+		reuseTime, mysql.SystemDB, mysql.PasswordHistoryTable, host, user)
+	if err != nil {
+		return err
+	}
+	rows, _, err := exec.ExecRestrictedStmt(ctx, stmt)
+	if err != nil {
+		return err
+	}
+	for i, row := range rows {
+		inHistory := int64(i) < history
+		inReuseInterval := reuseTime > 0 && row.GetInt64(1) == 1
+		if (inHistory || inReuseInterval) && pwd.matches(row.GetString(0)) {
+			return ErrCredentialsContradictToHistory.GenWithStackByArgs(user, host)
+		}
+	}
+	return nil
+}
+
+// recordPasswordHistory saves the new password of the account in mysql.password_history and removes
+// the passwords which are no longer restricted by the password history and reuse interval.
+func (e *SimpleExec) recordPasswordHistory(ctx context.Context, user, host string, pwd *newPassword, history, reuseTime int64) error {
+	if pwd.authString == "" || (history == 0 && reuseTime == 0) {
+		return nil
+	}
+	exec := e.ctx.(sqlexec.RestrictedSQLExecutor)
+	stmt, err := exec.ParseWithParams(ctx, "INSERT INTO %n.%n (Host, User, Password) VALUES (%?, %?, %?)",
+		mysql.SystemDB, mysql.PasswordHistoryTable, host, user, pwd.authString)
+	if err != nil {
+		return err
+	}
+	if _, _, err = exec.ExecRestrictedStmt(ctx, stmt); err != nil {
+		return err
+	}
+
+	// Passwords older than the `history`-th latest one and out of the reuse interval are useless.
+	var boundary interface{}
+	if history > 0 {
+		stmt, err = exec.ParseWithParams(ctx, "SELECT CAST(Password_timestamp AS CHAR) FROM %n.%n WHERE Host=%? AND User=%? ORDER BY Password_timestamp DESC LIMIT %?, 1",
+			mysql.SystemDB, mysql.PasswordHistoryTable, host, user, history-1)
+		if err != nil {
+			return err
+		}
+		rows, _, err := exec.ExecRestrictedStmt(ctx, stmt)
+		if err != nil || len(rows) == 0 {
+			return err
+		}
+		boundary = rows[0].GetString(0)
+	}
+	if boundary != nil {
+		stmt, err = exec.ParseWithParams(ctx, "DELETE FROM %n.%n WHERE Host=%? AND User=%? AND Password_timestamp < %? AND Password_timestamp < DATE_SUB(NOW(6), INTERVAL %? DAY)",
+			mysql.SystemDB, mysql.PasswordHistoryTable, host, user, boundary, reuseTime)
+	} else {
+		stmt, err = exec.ParseWithParams(ctx, "DELETE FROM %n.%n WHERE Host=%? AND User=%? AND Password_timestamp < DATE_SUB(NOW(6), INTERVAL %? DAY)",
+			mysql.SystemDB, mysql.PasswordHistoryTable, host, user, reuseTime)
+	}
+	if err != nil {
+		return err
+	}
+	_, _, err = exec.ExecRestrictedStmt(ctx, stmt)
+	return err
+}
@@ -0,0 +1,179 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasswordOrLockOptions(t *testing.T) {
+	t.Parallel()
+
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	query := "SELECT Account_locked, Password_expired, Password_lifetime, Password_reuse_history, Password_reuse_time, User_attributes FROM mysql.user WHERE User = 'u1'"
+	tk.MustExec("CREATE USER u1 PASSWORD EXPIRE INTERVAL 90 DAY PASSWORD HISTORY 3 PASSWORD REUSE INTERVAL 30 DAY FAILED_LOGIN_ATTEMPTS 3 PASSWORD_LOCK_TIME 2 ACCOUNT LOCK")
+	tk.MustQuery(query).Check(testkit.Rows(`Y N 90 3 30 {"Password_locking": {"failed_login_attempts": 3, "password_lock_time_days": 2}}`))
+
+	tk.MustExec("ALTER USER u1 PASSWORD EXPIRE NEVER PASSWORD HISTORY DEFAULT PASSWORD_LOCK_TIME UNBOUNDED ACCOUNT UNLOCK")
+	tk.MustQuery(query).Check(testkit.Rows(`N N 0 <nil> 30 {"Password_locking": {"failed_login_attempts": 3, "password_lock_time_days": -1}}`))
+
+	tk.MustExec("ALTER USER u1 PASSWORD EXPIRE DEFAULT PASSWORD REUSE INTERVAL DEFAULT FAILED_LOGIN_ATTEMPTS 0")
+	tk.MustQuery(query).Check(testkit.Rows(`N N <nil> <nil> <nil> {"Password_locking": {"failed_login_attempts": 0, "password_lock_time_days": -1}}`))
+
+	tk.MustExec("ALTER USER u1 PASSWORD EXPIRE")
+	tk.MustQuery("SELECT Password_expired FROM mysql.user WHERE User = 'u1'").Check(testkit.Rows("Y"))
+	tk.MustExec("ALTER USER u1 IDENTIFIED BY 'abc'")
+	tk.MustQuery("SELECT Password_expired FROM mysql.user WHERE User = 'u1'").Check(testkit.Rows("N"))
+	tk.MustExec("ALTER USER u1 IDENTIFIED BY 'def' PASSWORD EXPIRE")
+	tk.MustQuery("SELECT Password_expired FROM mysql.user WHERE User = 'u1'").Check(testkit.Rows("Y"))
+
+	// Roles are locked by default.
+	tk.MustExec("CREATE ROLE r1")
+	tk.MustQuery("SELECT Account_locked, User_attributes FROM mysql.user WHERE User = 'r1'").Check(testkit.Rows("Y <nil>"))
+}
+
+func TestPasswordHistory(t *testing.T) {
+	t.Parallel()
+
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("CREATE USER u1 IDENTIFIED BY 'p1' PASSWORD HISTORY 2")
+	tk.MustGetErrCode("ALTER USER u1 IDENTIFIED BY 'p1'", errno.ErrCredentialsContradictToHistory)
+	tk.MustExec("ALTER USER u1 IDENTIFIED BY 'p2'")
+	tk.MustGetErrCode("SET PASSWORD FOR u1 = 'p1'", errno.ErrCredentialsContradictToHistory)
+	tk.MustExec("ALTER USER u1 IDENTIFIED BY 'p3'")
+	// p1 is out of the latest 2 passwords.
+	tk.MustExec("SET PASSWORD FOR u1 = 'p1'")
+	tk.MustQuery("SELECT COUNT(*) FROM mysql.password_history WHERE User = 'u1'").Check(testkit.Rows("2"))
+
+	// The hashed password is compared with the history as well.
+	tk.MustExec("CREATE USER u2 IDENTIFIED WITH 'caching_sha2_password' BY 'p1' PASSWORD REUSE INTERVAL 1 DAY")
+	tk.MustExec("ALTER USER u2 IDENTIFIED BY 'p2'")
+	tk.MustGetErrCode("ALTER USER u2 IDENTIFIED BY 'p1'", errno.ErrCredentialsContradictToHistory)
+	tk.MustExec("CREATE USER u3 IDENTIFIED BY PASSWORD '*23AE809DDACAF96AF0FD78ED04B6A265E05AA257' PASSWORD HISTORY 1")
+	tk.MustGetErrCode("SET PASSWORD FOR u3 = '123'", errno.ErrCredentialsContradictToHistory)
+
+	// The global variables take effect if the account doesn't specify the policy.
+	tk.MustExec("CREATE USER u4 IDENTIFIED BY 'p1'")
+	tk.MustExec("ALTER USER u4 IDENTIFIED BY 'p1'")
+	tk.MustExec("SET GLOBAL password_history = 1")
+	tk.MustExec("ALTER USER u4 IDENTIFIED BY 'p2'")
+	tk.MustGetErrCode("ALTER USER u4 IDENTIFIED BY 'p2'", errno.ErrCredentialsContradictToHistory)
+	tk.MustExec("ALTER USER u4 PASSWORD HISTORY 0")
+	tk.MustExec("ALTER USER u4 IDENTIFIED BY 'p2'")
+
+	tk.MustExec("RENAME USER u1 TO u5")
+	tk.MustQuery("SELECT COUNT(*) FROM mysql.password_history WHERE User = 'u5'").Check(testkit.Rows("2"))
+	tk.MustExec("DROP USER u5")
+	tk.MustQuery("SELECT COUNT(*) FROM mysql.password_history WHERE User = 'u5'").Check(testkit.Rows("0"))
+}
+
+func TestValidatePassword(t *testing.T) {
+	t.Parallel()
+
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("CREATE USER u0 IDENTIFIED BY '1'")
+	tk.MustExec("SET GLOBAL validate_password_enable = ON")
+	tk.MustGetErrCode("CREATE USER u1 IDENTIFIED BY 'Abc1!'", errno.ErrNotValidPassword)
+	tk.MustGetErrCode("CREATE USER u1 IDENTIFIED BY 'abcdef1!'", errno.ErrNotValidPassword)
+	tk.MustGetErrCode("CREATE USER u1 IDENTIFIED BY 'Abcdefg!'", errno.ErrNotValidPassword)
+	tk.MustGetErrCode("CREATE USER u1 IDENTIFIED BY 'Abcdefg1'", errno.ErrNotValidPassword)
+	tk.MustExec("CREATE USER u1 IDENTIFIED BY 'Abcdef1!'")
+	tk.MustGetErrCode("ALTER USER u1 IDENTIFIED BY 'abc'", errno.ErrNotValidPassword)
+	tk.MustGetErrCode("SET PASSWORD FOR u1 = 'abc'", errno.ErrNotValidPassword)
+	// The hashed password isn't validated.
+	tk.MustExec("ALTER USER u1 IDENTIFIED BY PASSWORD '*23AE809DDACAF96AF0FD78ED04B6A265E05AA257'")
+
+	tk.MustExec("SET GLOBAL validate_password_policy = LOW")
+	tk.MustExec("SET GLOBAL validate_password_check_user_name = ON")
+	tk.MustExec("CREATE USER u2 IDENTIFIED BY 'abcdefgh'")
+	tk.MustGetErrCode("CREATE USER abcdefgh IDENTIFIED BY 'abcdefgh'", errno.ErrNotValidPassword)
+	tk.MustGetErrCode("CREATE USER abcdefgh IDENTIFIED BY 'hgfedcba'", errno.ErrNotValidPassword)
+
+	dictionary := filepath.Join(t.TempDir(), "dictionary.txt")
+	require.NoError(t, os.WriteFile(dictionary, []byte("password\nSecret\n"), 0600))
+	tk.MustExec("SET GLOBAL validate_password_policy = STRONG")
+	tk.MustExec("SET GLOBAL validate_password_dictionary_file = '" + dictionary + "'")
+	tk.MustGetErrCode("CREATE USER u3 IDENTIFIED BY 'MyPassword1!'", errno.ErrNotValidPassword)
+	tk.MustGetErrCode("CREATE USER u3 IDENTIFIED BY 'mysecret1A!'", errno.ErrNotValidPassword)
+	tk.MustExec("CREATE USER u3 IDENTIFIED BY 'MyPass1!word'")
+}
+
+func TestUserResourceOptions(t *testing.T) {
+	t.Parallel()
+
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	query := "SELECT max_questions, max_updates, max_connections, max_user_connections FROM mysql.user WHERE User = 'u1'"
+	tk.MustExec("CREATE USER u1 WITH MAX_QUERIES_PER_HOUR 10 MAX_USER_CONNECTIONS 2")
+	tk.MustQuery(query).Check(testkit.Rows("10 0 0 2"))
+	tk.MustQuery("SHOW CREATE USER u1").Check(testkit.Rows("CREATE USER 'u1'@'%' IDENTIFIED WITH 'mysql_native_password' AS '' REQUIRE NONE WITH MAX_QUERIES_PER_HOUR 10 MAX_USER_CONNECTIONS 2 PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK"))
+
+	// The usage of all the instances in the current hour is summed up.
+	tk.MustExec("INSERT INTO mysql.user_resource_usage VALUES ('%', 'u1', 'tidb-1:4000', FLOOR(UNIX_TIMESTAMP() / 3600) * 3600, 3, 1, 2, 1), " +
+		"('%', 'u1', 'tidb-2:4000', FLOOR(UNIX_TIMESTAMP() / 3600) * 3600, 4, 0, 1, 1), ('%', 'u1', 'tidb-3:4000', 0, 100, 100, 100, 0)")
+	usage := "SELECT * FROM information_schema.user_resource_usage WHERE USER = 'u1'"
+	tk.MustQuery(usage).Check(testkit.Rows("u1 % 10 7 0 1 0 3 2 2"))
+
+	// Changing the limits resets the hourly counters.
+	tk.MustExec("ALTER USER u1 WITH MAX_UPDATES_PER_HOUR 5 MAX_QUERIES_PER_HOUR 0")
+	tk.MustQuery(query).Check(testkit.Rows("0 5 0 2"))
+	tk.MustQuery(usage).Check(testkit.Rows("u1 % 0 0 5 0 0 0 2 2"))
+
+	tk.MustExec("RENAME USER u1 TO u2")
+	tk.MustQuery("SELECT COUNT(*) FROM mysql.user_resource_usage WHERE User = 'u2'").Check(testkit.Rows("3"))
+	tk.MustExec("DROP USER u2")
+	tk.MustQuery("SELECT COUNT(*) FROM mysql.user_resource_usage").Check(testkit.Rows("0"))
+	tk.MustQuery("SELECT * FROM information_schema.user_resource_usage").Check(testkit.Rows())
+}
+
+func TestUserMemoryQuota(t *testing.T) {
+	t.Parallel()
+
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	query := "SELECT max_user_connections, max_memory_quota FROM mysql.user WHERE User = 'u1'"
+	tk.MustExec("CREATE USER u1 WITH MAX_MEMORY_QUOTA 1073741824")
+	tk.MustQuery(query).Check(testkit.Rows("0 1073741824"))
+	tk.MustQuery("SHOW CREATE USER u1").Check(testkit.Rows("CREATE USER 'u1'@'%' IDENTIFIED WITH 'mysql_native_password' AS '' REQUIRE NONE WITH MAX_MEMORY_QUOTA 1073741824 PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK"))
+	tk.MustExec("ALTER USER u1 WITH MAX_USER_CONNECTIONS 2 MAX_MEMORY_QUOTA 0")
+	tk.MustQuery(query).Check(testkit.Rows("2 0"))
+	tk.MustQuery("SHOW CREATE USER u1").Check(testkit.Rows("CREATE USER 'u1'@'%' IDENTIFIED WITH 'mysql_native_password' AS '' REQUIRE NONE WITH MAX_USER_CONNECTIONS 2 PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK"))
+
+	tk.MustExec("set @@global.tidb_server_memory_limit = 1 << 30")
+	defer tk.MustExec("set @@global.tidb_server_memory_limit = default")
+	tk.MustQuery("select @@global.tidb_server_memory_limit").Check(testkit.Rows("1073741824"))
+	tk.MustExec("set @@tidb_mem_quota_session = 1 << 20")
+	tk.MustQuery("select @@tidb_mem_quota_session").Check(testkit.Rows("1048576"))
+	tk.MustQuery("select count(*) from information_schema.memory_kill_history").Check(testkit.Rows("0"))
+}
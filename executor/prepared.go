@@ -106,6 +106,16 @@ func NewPrepareExec(ctx sessionctx.Context, sqlTxt string) *PrepareExec {
 	}
 }
 
+// NewPrepareExecWithID creates a PrepareExec which prepares the statement with the given ID and name, it
+// restores the prepared statements of a migrated session within SET SESSION_STATES.
+func NewPrepareExecWithID(ctx sessionctx.Context, sqlTxt, name string, id uint32) *PrepareExec {
+	e := NewPrepareExec(ctx, sqlTxt)
+	e.name, e.ID = name, id
+	// The statement context has been reset by SET SESSION_STATES.
+	e.needReset = false
+	return e
+}
+
 // Next implements the Executor Next interface.
 func (e *PrepareExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	vars := e.ctx.GetSessionVars()
@@ -201,7 +211,11 @@ func (e *PrepareExec) Next(ctx context.Context, req *chunk.Chunk) error {
 	if !plannercore.PreparedPlanCacheEnabled() {
 		prepared.UseCache = false
 	} else {
-		prepared.UseCache = plannercore.CacheableWithCtx(e.ctx, stmt, ret.InfoSchema)
+		var reason string
+		prepared.UseCache, reason = plannercore.CacheableWithReason(e.ctx, stmt, ret.InfoSchema)
+		if !prepared.UseCache {
+			vars.StmtCtx.AppendWarning(errors.Errorf("skip plan-cache: %s", reason))
+		}
 	}
 
 	// We try to build the real statement of preparedStmt.
@@ -235,6 +249,9 @@ func (e *PrepareExec) Next(ctx context.Context, req *chunk.Chunk) error {
 		SQLDigest:           digest,
 		ForUpdateRead:       destBuilder.GetIsForUpdateRead(),
 		SnapshotTSEvaluator: ret.SnapshotTSEvaluator,
+		StmtText:            e.sqlText,
+		StmtDB:              vars.CurrentDB,
+		LimitParams:         plannercore.ExtractLimitParams(stmt),
 	}
 	return vars.AddPreparedStmt(e.ID, preparedObj)
 }
@@ -589,18 +589,18 @@ func (s *testPrepareSuite) TestPlanCacheOperators(c *C) {
 		// cases for NestedLoopJoin (Apply)
 		{"select * from t t1 where t1.b>? and t1.a > (select min(t2.a) from t t2 where t2.b < t1.b)", []ExecCase{
 			{[]string{"1"}, false},
-			{[]string{"3"}, false}, // plans with sub-queries cannot be cached, but the result must be correct
-			{[]string{"5"}, false},
+			{[]string{"3"}, true},
+			{[]string{"5"}, true},
 		}},
 		{"select * from t t1 where t1.a > (select min(t2.a) from t t2 where t2.b < t1.b+?)", []ExecCase{
 			{[]string{"1"}, false},
-			{[]string{"3"}, false},
-			{[]string{"5"}, false},
+			{[]string{"3"}, true},
+			{[]string{"5"}, true},
 		}},
 		{"select * from t t1 where t1.b>? and t1.a > (select min(t2.a) from t t2 where t2.b < t1.b+?)", []ExecCase{
 			{[]string{"1", "1"}, false},
-			{[]string{"3", "2"}, false},
-			{[]string{"5", "3"}, false},
+			{[]string{"3", "2"}, true},
+			{[]string{"5", "3"}, true},
 		}},
 		{"drop table t", nil},
 
@@ -644,7 +644,8 @@ func (s *testPrepareSuite) TestPlanCacheOperators(c *C) {
 		{"insert into t values (1), (1), (2), (2), (3), (4), (5), (6), (7), (8), (9), (0), (0)", nil},
 		{"select * from t limit ?", []ExecCase{
 			{[]string{"20"}, false},
-			{[]string{"30"}, false},
+			{[]string{"30"}, false}, // the values of the limit parameters are a part of the cache key
+			{[]string{"30"}, true},
 		}},
 		{"select * from t limit 40, ?", []ExecCase{
 			{[]string{"1"}, false},
@@ -657,6 +658,7 @@ func (s *testPrepareSuite) TestPlanCacheOperators(c *C) {
 		{"select * from t limit ?, ?", []ExecCase{
 			{[]string{"20", "20"}, false},
 			{[]string{"20", "40"}, false},
+			{[]string{"20", "20"}, true},
 		}},
 		{"select * from t where a<? limit 20", []ExecCase{
 			{[]string{"2"}, false},
@@ -692,6 +694,7 @@ func (s *testPrepareSuite) TestPlanCacheOperators(c *C) {
 		{"select * from t order by b limit ?", []ExecCase{
 			{[]string{"1"}, false},
 			{[]string{"2"}, false},
+			{[]string{"1"}, true},
 		}},
 		{"select * from t order by b limit 10, ?", []ExecCase{
 			{[]string{"1"}, false},
@@ -1094,8 +1097,7 @@ func (s *testSerialSuite) TestPreparePlanCache4DifferentSystemVars(c *C) {
 	// Do not use the parallel apply.
 	c.Assert(strings.Contains(executionInfo, "Concurrency") == false, Equals, true)
 	tk.MustExec("execute stmt;")
-	// The subquery plan can not be cached.
-	tk.MustQuery("select @@last_plan_from_cache;").Check(testkit.Rows("0"))
+	tk.MustQuery("select @@last_plan_from_cache;").Check(testkit.Rows("1"))
 
 	// test for apply cache
 	tk.MustExec("set @@tidb_enable_collect_execution_info=1;")
@@ -1124,8 +1126,7 @@ func (s *testSerialSuite) TestPreparePlanCache4DifferentSystemVars(c *C) {
 	// Do not use the apply cache.
 	c.Assert(strings.Contains(executionInfo, "cache:OFF") == true, Equals, true)
 	tk.MustExec("execute stmt;")
-	// The subquery plan can not be cached.
-	tk.MustQuery("select @@last_plan_from_cache;").Check(testkit.Rows("0"))
+	tk.MustQuery("select @@last_plan_from_cache;").Check(testkit.Rows("1"))
 }
 
 func (s *testSerialSuite) TestTemporaryTable4PlanCache(c *C) {
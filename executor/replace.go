@@ -89,6 +89,14 @@ func (e *ReplaceExec) removeRow(ctx context.Context, txn kv.Transaction, handle
 	if err != nil {
 		return false, err
 	}
+	for _, fkc := range e.fkChecks {
+		if err = fkc.deleteRowNeedToCheck(ctx, oldRow); err != nil {
+			return false, err
+		}
+	}
+	for _, fkc := range e.fkCascades {
+		fkc.onDeleteRow(oldRow)
+	}
 	e.ctx.GetSessionVars().StmtCtx.AddAffectedRows(1)
 	return false, nil
 }
@@ -255,10 +263,16 @@ func (e *ReplaceExec) Next(ctx context.Context, req *chunk.Chunk) error {
 		ctx = context.WithValue(ctx, autoid.AllocatorRuntimeStatsCtxKey, e.stats.AllocatorRuntimeStats)
 	}
 
+	var err error
 	if len(e.children) > 0 && e.children[0] != nil {
-		return insertRowsFromSelect(ctx, e)
+		err = insertRowsFromSelect(ctx, e)
+	} else {
+		err = insertRows(ctx, e)
+	}
+	if err != nil {
+		return err
 	}
-	return insertRows(ctx, e)
+	return executeFKCascades(ctx, e.fkCascades)
 }
 
 // setMessage sets info message(ERR_INSERT_INFO) generated by REPLACE statement
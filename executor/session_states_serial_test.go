@@ -0,0 +1,131 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/parser/auth"
+	"github.com/pingcap/tidb/sessionctx/sessionstates"
+	"github.com/pingcap/tidb/testkit"
+	"github.com/pingcap/tidb/util"
+	"github.com/stretchr/testify/require"
+)
+
+func setSessionTokenSigningCert(t *testing.T) {
+	dir := t.TempDir()
+	certPath, keyPath := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	require.NoError(t, util.CreateTLSCertificates(certPath, keyPath, 2048))
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.Security.SessionTokenSigningCert = certPath
+		conf.Security.SessionTokenSigningKey = keyPath
+	})
+}
+
+func showSessionStates(tk *testkit.TestKit) string {
+	rows := tk.MustQuery("show session_states").Rows()
+	return rows[0][0].(string)
+}
+
+func TestSessionStatesMigration(t *testing.T) {
+	defer config.RestoreFunc()()
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk1 := testkit.NewTestKit(t, store)
+	require.True(t, sessionstates.ErrCannotMigrateSession.Equal(tk1.QueryToErr("show session_states")))
+	setSessionTokenSigningCert(t)
+
+	tk1.MustExec("create user 'u1'@'%', 'u2'@'%'")
+	tk1.MustExec("grant all on test.* to 'u1'@'%'")
+	require.True(t, tk1.Session().Auth(&auth.UserIdentity{Username: "u1", Hostname: "%"}, nil, nil))
+	tk1.MustExec("use test")
+	tk1.MustExec("create table t(a int)")
+	tk1.MustExec("insert into t values (1), (2)")
+	tk1.MustExec("set @a = 1, @b = 'x'")
+	tk1.MustExec("set sql_mode = 'ANSI_QUOTES'")
+	tk1.MustExec("set tidb_mem_quota_query = 12345")
+	tk1.MustExec("prepare s1 from 'select count(*) from t where a > ?'")
+	tk1.MustExec("create temporary table tmp(id int auto_increment primary key)")
+	token := showSessionStates(tk1)
+
+	// Only the sessions of the same account can restore the session states.
+	tk3 := testkit.NewTestKit(t, store)
+	require.True(t, tk3.Session().Auth(&auth.UserIdentity{Username: "u2", Hostname: "%"}, nil, nil))
+	tk3.MustGetErrCode("set session_states '"+token+"'", errno.ErrInvalidSessionStates)
+	tk3 = testkit.NewTestKit(t, store)
+	tk3.MustGetErrCode("set session_states '"+token+"'", errno.ErrInvalidSessionStates)
+
+	tk2 := testkit.NewTestKit(t, store)
+	require.True(t, tk2.Session().Auth(&auth.UserIdentity{Username: "u1", Hostname: "%"}, nil, nil))
+	tk2.MustExec("set session_states '" + token + "'")
+	tk2.MustQuery("select current_user(), database()").Check(testkit.Rows("u1@% test"))
+	tk2.MustQuery("select @a, @b").Check(testkit.Rows("1 x"))
+	tk2.MustQuery("select @@sql_mode, @@tidb_mem_quota_query").Check(testkit.Rows("ANSI_QUOTES 12345"))
+	tk2.MustExec("set @p = 1")
+	tk2.MustQuery("execute s1 using @p").Check(testkit.Rows("1"))
+	tk2.MustExec("insert into tmp values ()")
+	tk2.MustQuery("select id from tmp").Check(testkit.Rows("1"))
+
+	// The temporary table which has data and the active transaction can't be migrated.
+	require.True(t, sessionstates.ErrCannotMigrateSession.Equal(tk2.QueryToErr("show session_states")))
+	tk2.MustExec("drop temporary table tmp")
+	tk2.MustExec("begin")
+	require.True(t, sessionstates.ErrCannotMigrateSession.Equal(tk2.QueryToErr("show session_states")))
+	tk2.MustExec("rollback")
+	showSessionStates(tk2)
+
+	// The statement IDs can't conflict.
+	tk2.MustGetErrCode("set session_states '"+token+"'", errno.ErrCannotMigrateSession)
+	tk3.MustGetErrCode("set session_states 'invalid'", errno.ErrInvalidSessionStates)
+
+	// The token is not recorded in the statements summary and the slow log.
+	tk3.MustQuery("select distinct digest_text, query_sample_text from information_schema.statements_summary where digest_text like 'set session_states%'").
+		Check(testkit.Rows("set session_states ? set session_states ?"))
+}
+
+func TestSessionStatesRevokedRoles(t *testing.T) {
+	defer config.RestoreFunc()()
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	setSessionTokenSigningCert(t)
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	tk.MustExec("create table t(a int)")
+	tk.MustExec("create user 'u1'@'%'")
+	tk.MustExec("create role 'r1', 'r2'")
+	tk.MustExec("grant select on test.* to 'r1'")
+	tk.MustExec("grant insert on test.* to 'r2'")
+	tk.MustExec("grant 'r1', 'r2' to 'u1'@'%'")
+
+	tk1 := testkit.NewTestKit(t, store)
+	require.True(t, tk1.Session().Auth(&auth.UserIdentity{Username: "u1", Hostname: "%"}, nil, nil))
+	tk1.MustExec("set role 'r1', 'r2'")
+	tk1.MustQuery("select a from test.t").Check(testkit.Rows())
+	token := showSessionStates(tk1)
+
+	// The role which is revoked after the session states are shown is not active anymore.
+	tk.MustExec("revoke 'r1' from 'u1'@'%'")
+	tk2 := testkit.NewTestKit(t, store)
+	require.True(t, tk2.Session().Auth(&auth.UserIdentity{Username: "u1", Hostname: "%"}, nil, nil))
+	tk2.MustExec("set session_states '" + token + "'")
+	tk2.MustQuery("select current_role()").Check(testkit.Rows("`r2`@`%`"))
+	tk2.MustExec("insert into test.t values (1)")
+	tk2.MustGetErrCode("select a from test.t", errno.ErrTableaccessDenied)
+}
@@ -50,6 +50,7 @@ import (
 	"github.com/pingcap/tidb/privilege"
 	"github.com/pingcap/tidb/privilege/privileges"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/sessionstates"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/store/helper"
@@ -215,6 +216,8 @@ func (e *ShowExec) fetchAll(ctx context.Context) error {
 		return e.fetchShowTableRegions()
 	case ast.ShowBuiltins:
 		return e.fetchShowBuiltins()
+	case ast.ShowSessionStates:
+		return e.fetchShowSessionStates(ctx)
 	case ast.ShowBackups:
 		return e.fetchShowBRIE(ast.BRIEKindBackup)
 	case ast.ShowRestores:
@@ -1006,6 +1009,16 @@ func ConstructResultOfShowCreateTable(ctx sessionctx.Context, tableInfo *model.T
 		}
 	}
 
+	for _, constraint := range tableInfo.Constraints {
+		if constraint.State != model.StatePublic {
+			continue
+		}
+		fmt.Fprintf(buf, ",\n  CONSTRAINT %s CHECK ((%s))", stringutil.Escape(constraint.Name.O, sqlMode), constraint.ExprString)
+		if !constraint.Enforced {
+			buf.WriteString(" /*!80016 NOT ENFORCED */")
+		}
+	}
+
 	buf.WriteString("\n")
 
 	buf.WriteString(") ENGINE=InnoDB")
@@ -1229,7 +1242,8 @@ func appendDirectPlacementInfo(directPlacementOpts *model.PlacementSettings, buf
 }
 
 func appendPartitionInfo(partitionInfo *model.PartitionInfo, buf *bytes.Buffer, sqlMode mysql.SQLMode) {
-	if partitionInfo == nil {
+	// A table of partition type NONE is changing its partitioning, it is shown as a non-partitioned table.
+	if partitionInfo == nil || partitionInfo.Type == model.PartitionTypeNone {
 		return
 	}
 	if partitionInfo.Type == model.PartitionTypeHash {
@@ -1433,7 +1447,7 @@ func (e *ShowExec) fetchShowCreateUser(ctx context.Context) error {
 
 	exec := e.ctx.(sqlexec.RestrictedSQLExecutor)
 
-	stmt, err := exec.ParseWithParams(ctx, `SELECT plugin FROM %n.%n WHERE User=%? AND Host=%?`, mysql.SystemDB, mysql.UserTable, userName, hostName)
+	stmt, err := exec.ParseWithParams(ctx, `SELECT plugin, max_questions, max_updates, max_connections, max_user_connections, max_memory_quota FROM %n.%n WHERE User=%? AND Host=%?`, mysql.SystemDB, mysql.UserTable, userName, hostName)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -1452,6 +1466,15 @@ func (e *ShowExec) fetchShowCreateUser(ctx context.Context) error {
 	if len(rows) == 1 && rows[0].GetString(0) != "" {
 		authplugin = rows[0].GetString(0)
 	}
+	var resources strings.Builder
+	for i, option := range []string{"MAX_QUERIES_PER_HOUR", "MAX_UPDATES_PER_HOUR", "MAX_CONNECTIONS_PER_HOUR", "MAX_USER_CONNECTIONS", "MAX_MEMORY_QUOTA"} {
+		if limit := rows[0].GetUint64(i + 1); limit > 0 {
+			if resources.Len() == 0 {
+				resources.WriteString(" WITH")
+			}
+			fmt.Fprintf(&resources, " %s %d", option, limit)
+		}
+	}
 
 	stmt, err = exec.ParseWithParams(ctx, `SELECT Priv FROM %n.%n WHERE User=%? AND Host=%?`, mysql.SystemDB, mysql.GlobalPrivTable, userName, hostName)
 	if err != nil {
@@ -1480,8 +1503,8 @@ func (e *ShowExec) fetchShowCreateUser(ctx context.Context) error {
 	}
 
 	// FIXME: the returned string is not escaped safely
-	showStr := fmt.Sprintf("CREATE USER '%s'@'%s' IDENTIFIED WITH '%s'%s REQUIRE %s PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK",
-		e.User.Username, e.User.Hostname, authplugin, authStr, require)
+	showStr := fmt.Sprintf("CREATE USER '%s'@'%s' IDENTIFIED WITH '%s'%s REQUIRE %s%s PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK",
+		e.User.Username, e.User.Hostname, authplugin, authStr, require, resources.String())
 	e.appendRow([]interface{}{showStr})
 	return nil
 }
@@ -1838,6 +1861,21 @@ func (e *ShowExec) fetchShowBuiltins() error {
 	return nil
 }
 
+// fetchShowSessionStates exports the states of the session as a signed token, which can be restored by
+// SET SESSION_STATES on another TiDB instance.
+func (e *ShowExec) fetchShowSessionStates(ctx context.Context) error {
+	sessionStates := &sessionstates.SessionStates{}
+	if err := e.ctx.EncodeSessionStates(ctx, e.ctx, sessionStates); err != nil {
+		return err
+	}
+	token, err := sessionstates.EncodeToken(sessionStates)
+	if err != nil {
+		return err
+	}
+	e.appendRow([]interface{}{token})
+	return nil
+}
+
 // tryFillViewColumnType fill the columns type info of a view.
 // Because view's underlying table's column could change or recreate, so view's column type may change over time.
 // To avoid this situation we need to generate a logical plan and extract current column types from Schema.
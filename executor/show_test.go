@@ -865,7 +865,7 @@ func (s *testSuite5) TestShowCreateTable(c *C) {
 			") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin",
 	))
 
-	// TiDB defaults (and only supports) foreign_key_checks=0
+	// TiDB defaults foreign_key_checks=0
 	// This means that the child table can be created before the parent table.
 	// This behavior is required for mysqldump restores.
 	tk.MustExec(`DROP TABLE IF EXISTS parent, child`)
@@ -1238,7 +1238,7 @@ func (s *testSuite5) TestShowBuiltin(c *C) {
 	res := tk.MustQuery("show builtins;")
 	c.Assert(res, NotNil)
 	rows := res.Rows()
-	const builtinFuncNum = 273
+	const builtinFuncNum = 280
 	c.Assert(builtinFuncNum, Equals, len(rows))
 	c.Assert("abs", Equals, rows[0][0].(string))
 	c.Assert("yearweek", Equals, rows[builtinFuncNum-1][0].(string))
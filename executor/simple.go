@@ -41,6 +41,7 @@ import (
 	"github.com/pingcap/tidb/plugin"
 	"github.com/pingcap/tidb/privilege"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/sessionstates"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/chunk"
@@ -159,8 +160,14 @@ func (e *SimpleExec) Next(ctx context.Context, req *chunk.Chunk) (err error) {
 		err = e.executeSetDefaultRole(ctx, x)
 	case *ast.ShutdownStmt:
 		err = e.executeShutdown(x)
+	case *ast.SetSessionStatesStmt:
+		err = e.executeSetSessionStates(ctx, x)
 	case *ast.AdminStmt:
-		err = e.executeAdminReloadStatistics(x)
+		if x.Tp == ast.AdminFlushInstancePlanCache {
+			plannercore.FlushInstancePlanCache()
+		} else {
+			err = e.executeAdminReloadStatistics(x)
+		}
 	}
 	e.done = true
 	return err
@@ -752,14 +759,27 @@ func (e *SimpleExec) executeCreateUser(ctx context.Context, s *ast.CreateUserStm
 		return err
 	}
 
-	sql := new(strings.Builder)
-	if s.IsCreateRole {
-		sqlexec.MustFormatSQL(sql, `INSERT INTO %n.%n (Host, User, authentication_string, plugin, Account_locked) VALUES `, mysql.SystemDB, mysql.UserTable)
-	} else {
-		sqlexec.MustFormatSQL(sql, `INSERT INTO %n.%n (Host, User, authentication_string, plugin) VALUES `, mysql.SystemDB, mysql.UserTable)
+	opts := newPasswordOrLockOptions(s.PasswordOrLockOptions)
+	accountLocked := opts.accountLocked
+	if accountLocked == "" {
+		// Roles are locked by default.
+		accountLocked = "N"
+		if s.IsCreateRole {
+			accountLocked = "Y"
+		}
 	}
+	passwordExpired := "N"
+	if opts.passwordExpired {
+		passwordExpired = "Y"
+	}
+
+	resources := newUserResources(s.ResourceOptions)
+
+	sql := new(strings.Builder)
+	sqlexec.MustFormatSQL(sql, `INSERT INTO %n.%n (Host, User, authentication_string, plugin, Account_locked, Password_expired, Password_lifetime, Password_reuse_history, Password_reuse_time, User_attributes, max_questions, max_updates, max_connections, max_user_connections, max_memory_quota) VALUES `, mysql.SystemDB, mysql.UserTable)
 
 	users := make([]*auth.UserIdentity, 0, len(s.Specs))
+	passwords := make([]*newPassword, 0, len(s.Specs))
 	for _, spec := range s.Specs {
 		if len(users) > 0 {
 			sqlexec.MustFormatSQL(sql, ",")
@@ -780,29 +800,30 @@ func (e *SimpleExec) executeCreateUser(ctx context.Context, s *ast.CreateUserStm
 			e.ctx.GetSessionVars().StmtCtx.AppendNote(err)
 			continue
 		}
-		pwd, ok := spec.EncodedPassword()
-
-		if !ok {
-			return errors.Trace(ErrPasswordFormat)
-		}
 		authPlugin := mysql.AuthNativePassword
 		if spec.AuthOpt != nil && spec.AuthOpt.AuthPlugin != "" {
 			authPlugin = spec.AuthOpt.AuthPlugin
 		}
-
-		switch authPlugin {
-		case mysql.AuthNativePassword, mysql.AuthCachingSha2Password, mysql.AuthSocket:
-		default:
-			return ErrPluginIsNotLoaded.GenWithStackByArgs(spec.AuthOpt.AuthPlugin)
+		if spec.AuthOpt != nil && spec.AuthOpt.ByAuthString {
+			if err := e.validatePassword(spec.User.Username, spec.AuthOpt.AuthString); err != nil {
+				return err
+			}
+		}
+		pwd, err := encodedPassword(spec, authPlugin)
+		if err != nil {
+			return err
 		}
 
 		hostName := strings.ToLower(spec.User.Hostname)
-		if s.IsCreateRole {
-			sqlexec.MustFormatSQL(sql, `(%?, %?, %?, %?, %?)`, hostName, spec.User.Username, pwd, authPlugin, "Y")
-		} else {
-			sqlexec.MustFormatSQL(sql, `(%?, %?, %?, %?)`, hostName, spec.User.Username, pwd, authPlugin)
-		}
+		sqlexec.MustFormatSQL(sql, `(%?, %?, %?, %?, %?, %?, %?, %?, %?, %?, %?, %?, %?, %?, %?)`, hostName, spec.User.Username, pwd, authPlugin,
+			accountLocked, passwordExpired, opts.lifetime, opts.reuseHistory, opts.reuseTime, opts.userAttributes(),
+			resources.MaxQuestions, resources.MaxUpdates, resources.MaxConnections, resources.MaxUserConnections, resources.MaxMemoryQuota)
 		users = append(users, spec.User)
+		newPwd := &newPassword{authString: pwd}
+		if spec.AuthOpt != nil && spec.AuthOpt.ByAuthString {
+			newPwd.cleartext = spec.AuthOpt.AuthString
+		}
+		passwords = append(passwords, newPwd)
 	}
 	if len(users) == 0 {
 		return nil
@@ -845,6 +866,16 @@ func (e *SimpleExec) executeCreateUser(ctx context.Context, s *ast.CreateUserStm
 	if _, err := sqlExecutor.ExecuteInternal(context.TODO(), "commit"); err != nil {
 		return errors.Trace(err)
 	}
+	for i, user := range users {
+		hostName := strings.ToLower(user.Hostname)
+		history, reuseTime, err := e.passwordReusePolicy(ctx, user.Username, hostName)
+		if err != nil {
+			return err
+		}
+		if err := e.recordPasswordHistory(ctx, user.Username, hostName, passwords[i], history, reuseTime); err != nil {
+			return err
+		}
+	}
 	return domain.GetDomain(e.ctx).NotifyUpdatePrivilege()
 }
 
@@ -869,6 +900,12 @@ func (e *SimpleExec) executeAlterUser(ctx context.Context, s *ast.AlterUserStmt)
 		return err
 	}
 
+	opts := newPasswordOrLockOptions(s.PasswordOrLockOptions)
+	passwordExpired := "N"
+	if opts.passwordExpired {
+		passwordExpired = "Y"
+	}
+
 	failedUsers := make([]string, 0, len(s.Specs))
 	checker := privilege.GetPrivilegeManager(e.ctx)
 	if checker == nil {
@@ -925,6 +962,14 @@ func (e *SimpleExec) executeAlterUser(ctx context.Context, s *ast.AlterUserStmt)
 		}
 
 		exec := e.ctx.(sqlexec.RestrictedSQLExecutor)
+		if err := opts.updateUserTable(ctx, exec, spec.User.Username, spec.User.Hostname); err != nil {
+			failedUsers = append(failedUsers, spec.User.String())
+			continue
+		}
+		if err := updateUserResources(ctx, exec, s.ResourceOptions, spec.User.Username, spec.User.Hostname); err != nil {
+			failedUsers = append(failedUsers, spec.User.String())
+			continue
+		}
 		if spec.AuthOpt != nil {
 			if spec.AuthOpt.AuthPlugin == "" {
 				authplugin, err := e.userAuthPlugin(spec.User.Username, spec.User.Hostname)
@@ -933,18 +978,29 @@ func (e *SimpleExec) executeAlterUser(ctx context.Context, s *ast.AlterUserStmt)
 				}
 				spec.AuthOpt.AuthPlugin = authplugin
 			}
-			switch spec.AuthOpt.AuthPlugin {
-			case mysql.AuthNativePassword, mysql.AuthCachingSha2Password, mysql.AuthSocket, "":
-			default:
-				return ErrPluginIsNotLoaded.GenWithStackByArgs(spec.AuthOpt.AuthPlugin)
+			if spec.AuthOpt.ByAuthString {
+				if err := e.validatePassword(spec.User.Username, spec.AuthOpt.AuthString); err != nil {
+					return err
+				}
 			}
-			pwd, ok := spec.EncodedPassword()
-			if !ok {
-				return errors.Trace(ErrPasswordFormat)
+			pwd, err := encodedPassword(spec, spec.AuthOpt.AuthPlugin)
+			if err != nil {
+				return err
+			}
+			newPwd := &newPassword{authString: pwd}
+			if spec.AuthOpt.ByAuthString {
+				newPwd.cleartext = spec.AuthOpt.AuthString
+			}
+			history, reuseTime, err := e.passwordReusePolicy(ctx, spec.User.Username, spec.User.Hostname)
+			if err != nil {
+				return err
+			}
+			if err := e.checkPasswordReuse(ctx, spec.User.Username, spec.User.Hostname, newPwd, history, reuseTime); err != nil {
+				return err
 			}
 			stmt, err := exec.ParseWithParams(ctx,
-				`UPDATE %n.%n SET authentication_string=%?, plugin=%? WHERE Host=%? and User=%?;`,
-				mysql.SystemDB, mysql.UserTable, pwd, spec.AuthOpt.AuthPlugin, spec.User.Hostname, spec.User.Username,
+				`UPDATE %n.%n SET authentication_string=%?, plugin=%?, Password_last_changed=NOW(), Password_expired=%? WHERE Host=%? and User=%?;`,
+				mysql.SystemDB, mysql.UserTable, pwd, spec.AuthOpt.AuthPlugin, passwordExpired, spec.User.Hostname, spec.User.Username,
 			)
 			if err != nil {
 				return err
@@ -952,6 +1008,13 @@ func (e *SimpleExec) executeAlterUser(ctx context.Context, s *ast.AlterUserStmt)
 			_, _, err = exec.ExecRestrictedStmt(ctx, stmt)
 			if err != nil {
 				failedUsers = append(failedUsers, spec.User.String())
+			} else {
+				if err := e.recordPasswordHistory(ctx, spec.User.Username, spec.User.Hostname, newPwd, history, reuseTime); err != nil {
+					return err
+				}
+				if user != nil && user.AuthUsername == spec.User.Username && user.AuthHostname == spec.User.Hostname {
+					e.ctx.GetSessionVars().InSandBoxMode = passwordExpired == "Y"
+				}
 			}
 		}
 
@@ -987,6 +1050,71 @@ func (e *SimpleExec) executeAlterUser(ctx context.Context, s *ast.AlterUserStmt)
 	return domain.GetDomain(e.ctx).NotifyUpdatePrivilege()
 }
 
+// newUserResources returns the resource limits of a new account specified by the resource options.
+func newUserResources(options []*ast.ResourceOption) privilege.UserResources {
+	var resources privilege.UserResources
+	for _, opt := range options {
+		switch opt.Type {
+		case ast.MaxQueriesPerHour:
+			resources.MaxQuestions = opt.Count
+		case ast.MaxUpdatesPerHour:
+			resources.MaxUpdates = opt.Count
+		case ast.MaxConnectionsPerHour:
+			resources.MaxConnections = opt.Count
+		case ast.MaxUserConnections:
+			resources.MaxUserConnections = opt.Count
+		case ast.MaxMemoryQuota:
+			resources.MaxMemoryQuota = opt.Count
+		}
+	}
+	return resources
+}
+
+// userResourceColumns maps the resource options to the columns of mysql.user.
+var userResourceColumns = map[int]string{
+	ast.MaxQueriesPerHour:     "max_questions",
+	ast.MaxUpdatesPerHour:     "max_updates",
+	ast.MaxConnectionsPerHour: "max_connections",
+	ast.MaxUserConnections:    "max_user_connections",
+	ast.MaxMemoryQuota:        "max_memory_quota",
+}
+
+// updateUserResources applies the resource options to an existing account. Like MySQL, the hourly
+// counters of the account are reset if any resource limit is changed.
+func updateUserResources(ctx context.Context, exec sqlexec.RestrictedSQLExecutor, options []*ast.ResourceOption, user, host string) error {
+	if len(options) == 0 {
+		return nil
+	}
+	assignments := make([]string, 0, len(options))
+	args := []interface{}{mysql.SystemDB, mysql.UserTable}
+	for _, opt := range options {
+		column, ok := userResourceColumns[opt.Type]
+		if !ok {
+			continue
+		}
+		assignments = append(assignments, "%n=%?")
+		args = append(args, column, opt.Count)
+	}
+	if len(assignments) == 0 {
+		return nil
+	}
+	args = append(args, host, user)
+	stmt, err := exec.ParseWithParams(ctx, "UPDATE %n.%n SET "+strings.Join(assignments, ", ")+" WHERE Host=%? AND User=%?", args...)
+	if err != nil {
+		return err
+	}
+	if _, _, err = exec.ExecRestrictedStmt(ctx, stmt); err != nil {
+		return err
+	}
+	stmt, err = exec.ParseWithParams(ctx, "UPDATE %n.%n SET Questions=0, Updates=0, Connections=0 WHERE Host=%? AND User=%?",
+		mysql.SystemDB, mysql.UserResourceUsageTable, host, user)
+	if err != nil {
+		return err
+	}
+	_, _, err = exec.ExecRestrictedStmt(ctx, stmt)
+	return err
+}
+
 func (e *SimpleExec) executeGrantRole(ctx context.Context, s *ast.GrantRoleStmt) error {
 	sessionVars := e.ctx.GetSessionVars()
 	for i, user := range s.Users {
@@ -1135,6 +1263,18 @@ func (e *SimpleExec) executeRenameUser(s *ast.RenameUserStmt) error {
 			break
 		}
 
+		// rename the password history from mysql.password_history
+		if err = renameUserHostInSystemTable(sqlExecutor, mysql.PasswordHistoryTable, "User", "Host", userToUser); err != nil {
+			failedUser = oldUser.String() + " TO " + newUser.String() + " " + mysql.PasswordHistoryTable + " error"
+			break
+		}
+
+		// rename the resource usage from mysql.user_resource_usage
+		if err = renameUserHostInSystemTable(sqlExecutor, mysql.UserResourceUsageTable, "User", "Host", userToUser); err != nil {
+			failedUser = oldUser.String() + " TO " + newUser.String() + " " + mysql.UserResourceUsageTable + " error"
+			break
+		}
+
 		//TODO: need update columns_priv once we implement columns_priv functionality.
 		// When that is added, please refactor both executeRenameUser and executeDropUser to use an array of tables
 		// to loop over, so it is easier to maintain.
@@ -1296,6 +1436,22 @@ func (e *SimpleExec) executeDropUser(ctx context.Context, s *ast.DropUserStmt) e
 			break
 		}
 
+		// delete the password history from mysql.password_history
+		sql.Reset()
+		sqlexec.MustFormatSQL(sql, `DELETE FROM %n.%n WHERE Host = %? and User = %?;`, mysql.SystemDB, mysql.PasswordHistoryTable, user.Hostname, user.Username)
+		if _, err = sqlExecutor.ExecuteInternal(context.TODO(), sql.String()); err != nil {
+			failedUsers = append(failedUsers, user.String())
+			break
+		}
+
+		// delete the resource usage from mysql.user_resource_usage
+		sql.Reset()
+		sqlexec.MustFormatSQL(sql, `DELETE FROM %n.%n WHERE Host = %? and User = %?;`, mysql.SystemDB, mysql.UserResourceUsageTable, user.Hostname, user.Username)
+		if _, err = sqlExecutor.ExecuteInternal(context.TODO(), sql.String()); err != nil {
+			failedUsers = append(failedUsers, user.String())
+			break
+		}
+
 		//TODO: need delete columns_priv once we implement columns_priv functionality.
 	}
 
@@ -1349,6 +1505,39 @@ func userExistsInternal(sqlExecutor sqlexec.SQLExecutor, name string, host strin
 	return rows > 0, err
 }
 
+// encodedPassword returns the authentication_string of the user stored in mysql.user,
+// the password of the account using an authentication plugin is encoded by the plugin.
+func encodedPassword(spec *ast.UserSpec, authPlugin string) (string, error) {
+	switch authPlugin {
+	case mysql.AuthNativePassword, mysql.AuthCachingSha2Password, mysql.AuthSocket, "":
+		pwd, ok := spec.EncodedPassword()
+		if !ok {
+			return "", errors.Trace(ErrPasswordFormat)
+		}
+		return pwd, nil
+	}
+	authPluginImpl := plugin.GetAuthenticationPlugin(authPlugin)
+	if authPluginImpl == nil {
+		return "", ErrPluginIsNotLoaded.GenWithStackByArgs(authPlugin)
+	}
+	opt := spec.AuthOpt
+	if opt.ByAuthString {
+		if authPluginImpl.GenerateAuthenticationString == nil {
+			return "", errors.Trace(ErrPasswordFormat)
+		}
+		pwd, ok := authPluginImpl.GenerateAuthenticationString(opt.AuthString)
+		if !ok {
+			return "", errors.Trace(ErrPasswordFormat)
+		}
+		return pwd, nil
+	}
+	if opt.HashString != "" && authPluginImpl.ValidateAuthenticationString != nil &&
+		!authPluginImpl.ValidateAuthenticationString(opt.HashString) {
+		return "", errors.Trace(ErrPasswordFormat)
+	}
+	return opt.HashString, nil
+}
+
 func (e *SimpleExec) userAuthPlugin(name string, host string) (string, error) {
 	pm := privilege.GetPrivilegeManager(e.ctx)
 	authplugin, err := pm.GetAuthPlugin(name, host)
@@ -1387,6 +1576,11 @@ func (e *SimpleExec) executeSetPwd(ctx context.Context, s *ast.SetPwdStmt) error
 	if err != nil {
 		return err
 	}
+	if authplugin != mysql.AuthSocket {
+		if err := e.validatePassword(u, s.Password); err != nil {
+			return err
+		}
+	}
 	var pwd string
 	switch authplugin {
 	case mysql.AuthCachingSha2Password:
@@ -1394,13 +1588,28 @@ func (e *SimpleExec) executeSetPwd(ctx context.Context, s *ast.SetPwdStmt) error
 	case mysql.AuthSocket:
 		e.ctx.GetSessionVars().StmtCtx.AppendNote(ErrSetPasswordAuthPlugin.GenWithStackByArgs(u, h))
 		pwd = ""
-	default:
+	case mysql.AuthNativePassword, "":
 		pwd = auth.EncodePassword(s.Password)
+	default:
+		spec := &ast.UserSpec{AuthOpt: &ast.AuthOption{ByAuthString: true, AuthString: s.Password}}
+		pwd, err = encodedPassword(spec, authplugin)
+		if err != nil {
+			return err
+		}
+	}
+
+	newPwd := &newPassword{authString: pwd, cleartext: s.Password}
+	history, reuseTime, err := e.passwordReusePolicy(ctx, u, h)
+	if err != nil {
+		return err
+	}
+	if err := e.checkPasswordReuse(ctx, u, h, newPwd, history, reuseTime); err != nil {
+		return err
 	}
 
 	// update mysql.user
 	exec := e.ctx.(sqlexec.RestrictedSQLExecutor)
-	stmt, err := exec.ParseWithParams(ctx, `UPDATE %n.%n SET authentication_string=%? WHERE User=%? AND Host=%?;`, mysql.SystemDB, mysql.UserTable, pwd, u, h)
+	stmt, err := exec.ParseWithParams(ctx, `UPDATE %n.%n SET authentication_string=%?, Password_last_changed=NOW(), Password_expired='N' WHERE User=%? AND Host=%?;`, mysql.SystemDB, mysql.UserTable, pwd, u, h)
 	if err != nil {
 		return err
 	}
@@ -1408,6 +1617,12 @@ func (e *SimpleExec) executeSetPwd(ctx context.Context, s *ast.SetPwdStmt) error
 	if err != nil {
 		return err
 	}
+	if err := e.recordPasswordHistory(ctx, u, h, newPwd, history, reuseTime); err != nil {
+		return err
+	}
+	if user := e.ctx.GetSessionVars().User; user != nil && user.AuthUsername == u && user.AuthHostname == h {
+		e.ctx.GetSessionVars().InSandBoxMode = false
+	}
 	return domain.GetDomain(e.ctx).NotifyUpdatePrivilege()
 }
 
@@ -1578,6 +1793,15 @@ func (e *SimpleExec) autoNewTxn() bool {
 	return false
 }
 
+// executeSetSessionStates restores the session states exported by SHOW SESSION_STATES on another TiDB instance.
+func (e *SimpleExec) executeSetSessionStates(ctx context.Context, s *ast.SetSessionStatesStmt) error {
+	sessionStates, err := sessionstates.DecodeToken(s.SessionStates)
+	if err != nil {
+		return err
+	}
+	return e.ctx.DecodeSessionStates(ctx, e.ctx, sessionStates)
+}
+
 func (e *SimpleExec) executeShutdown(s *ast.ShutdownStmt) error {
 	sessVars := e.ctx.GetSessionVars()
 	logutil.BgLogger().Info("execute shutdown statement", zap.Uint64("conn", sessVars.ConnectionID))
@@ -582,6 +582,13 @@ func (e *slowQueryRetriever) parseLog(ctx context.Context, sctx sessionctx.Conte
 					}
 					host := parseUserOrHostValue(fields[1])
 					valid = e.setColumnValue(sctx, row, tz, variable.SlowLogHostStr, host, e.checker, fileLine)
+				} else if strings.HasPrefix(line, variable.SlowLogQueryAttributes+variable.SlowLogSpaceMarkStr) {
+					// The JSON value may contain spaces and colons, so it can't be split like the others.
+					value := line[len(variable.SlowLogQueryAttributes+variable.SlowLogSpaceMarkStr):]
+					valid = e.setColumnValue(sctx, row, tz, variable.SlowLogQueryAttributes, value, e.checker, fileLine)
+				} else if strings.HasPrefix(line, variable.SlowLogConnAttributes+variable.SlowLogSpaceMarkStr) {
+					value := line[len(variable.SlowLogConnAttributes+variable.SlowLogSpaceMarkStr):]
+					valid = e.setColumnValue(sctx, row, tz, variable.SlowLogConnAttributes, value, e.checker, fileLine)
 				} else if strings.HasPrefix(line, variable.SlowLogCopBackoffPrefix) {
 					valid = e.setColumnValue(sctx, row, tz, variable.SlowLogBackoffDetail, line, e.checker, fileLine)
 				} else {
@@ -723,7 +730,8 @@ func getColumnValueFactoryByName(sctx sessionctx.Context, colName string, column
 		}, nil
 	case variable.SlowLogUserStr, variable.SlowLogHostStr, execdetails.BackoffTypesStr, variable.SlowLogDBStr, variable.SlowLogIndexNamesStr, variable.SlowLogDigestStr,
 		variable.SlowLogStatsInfoStr, variable.SlowLogCopProcAddr, variable.SlowLogCopWaitAddr, variable.SlowLogPlanDigest,
-		variable.SlowLogPrevStmt, variable.SlowLogQuerySQLStr:
+		variable.SlowLogPrevStmt, variable.SlowLogQuerySQLStr, variable.SlowLogQueryAttributes, variable.SlowLogConnAttributes,
+		variable.SlowLogMemQuotaKilled:
 		return func(row []types.Datum, value string, tz *time.Location, checker *slowLogChecker) (valid bool, err error) {
 			row[columnIdx] = types.NewStringDatum(value)
 			return true, nil
@@ -67,6 +67,11 @@ type UpdateExec struct {
 	tableUpdatable []bool
 	changed        []bool
 	matches        []bool
+
+	fkChecks   map[int64][]*FKCheckExec
+	fkCascades map[int64][]*FKCascadeExec
+
+	checkConstraints map[int64][]*table.Constraint
 }
 
 // prepare `handles`, `tableUpdatable`, `changed` to avoid re-computations.
@@ -189,14 +194,15 @@ func (e *UpdateExec) exec(ctx context.Context, schema *expression.Schema, row, n
 		flags := bAssignFlag[content.Start:content.End]
 
 		// Update row
-		changed, err1 := updateRecord(ctx, e.ctx, handle, oldData, newTableData, flags, tbl, false, e.memTracker)
+		changed, err1 := updateRecord(ctx, e.ctx, handle, oldData, newTableData, flags, tbl, false, e.memTracker,
+			e.fkChecks[content.TblID], e.fkCascades[content.TblID], e.checkConstraints[content.TblID])
 		if err1 == nil {
 			e.updatedRowKeys[content.Start].Set(handle, changed)
 			continue
 		}
 
 		sc := e.ctx.GetSessionVars().StmtCtx
-		if kv.ErrKeyExists.Equal(err1) && sc.DupKeyAsWarning {
+		if (kv.ErrKeyExists.Equal(err1) || table.ErrCheckConstraintViolated.Equal(err1)) && sc.DupKeyAsWarning {
 			sc.AppendWarning(err1)
 			continue
 		}
@@ -229,6 +235,11 @@ func (e *UpdateExec) Next(ctx context.Context, req *chunk.Chunk) error {
 		}
 		e.drained = true
 		e.ctx.GetSessionVars().StmtCtx.AddRecordRows(uint64(numRows))
+		for _, fkCascades := range e.fkCascades {
+			if err = executeFKCascades(ctx, fkCascades); err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
@@ -29,6 +29,7 @@ import (
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/format"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
@@ -48,6 +49,7 @@ var (
 	_ functionClass = &castAsTimeFunctionClass{}
 	_ functionClass = &castAsDurationFunctionClass{}
 	_ functionClass = &castAsJSONFunctionClass{}
+	_ functionClass = &castJSONAsArrayFunctionClass{}
 )
 
 var (
@@ -106,6 +108,7 @@ var (
 	_ builtinFunc = &builtinCastJSONAsTimeSig{}
 	_ builtinFunc = &builtinCastJSONAsDurationSig{}
 	_ builtinFunc = &builtinCastJSONAsJSONSig{}
+	_ builtinFunc = &builtinCastJSONAsArraySig{}
 )
 
 type castAsIntFunctionClass struct {
@@ -451,6 +454,51 @@ func (c *castAsJSONFunctionClass) getFunction(ctx sessionctx.Context, args []Exp
 	return sig, nil
 }
 
+type castJSONAsArrayFunctionClass struct {
+	baseFunctionClass
+
+	tp *types.FieldType
+}
+
+func (c *castJSONAsArrayFunctionClass) verifyArgs(args []Expression) error {
+	if err := c.baseFunctionClass.verifyArgs(args); err != nil {
+		return err
+	}
+	if args[0].GetType().EvalType() != types.ETJson {
+		return errNotSupportedYet.GenWithStackByArgs("CAST-ing Non-JSON Array type to array")
+	}
+	switch elemTp := c.tp.ArrayElem; elemTp.EvalType() {
+	case types.ETInt:
+		if elemTp.Tp == mysql.TypeLonglong {
+			return nil
+		}
+	case types.ETReal:
+		if elemTp.Tp == mysql.TypeDouble {
+			return nil
+		}
+	case types.ETString:
+		if elemTp.Tp == mysql.TypeVarString && elemTp.Flen != types.UnspecifiedLength {
+			return nil
+		}
+	}
+	var sb strings.Builder
+	c.tp.ArrayElem.RestoreAsCastType(format.NewRestoreCtx(format.DefaultRestoreFlags, &sb), false)
+	return errNotSupportedYet.GenWithStackByArgs("CAST-ing data to array of " + sb.String())
+}
+
+func (c *castJSONAsArrayFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (sig builtinFunc, err error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFunc(ctx, c.funcName, args, c.tp.EvalType())
+	if err != nil {
+		return nil, err
+	}
+	bf.tp = c.tp
+	sig = &builtinCastJSONAsArraySig{bf}
+	return sig, nil
+}
+
 type builtinCastIntAsIntSig struct {
 	baseBuiltinCastFunc
 }
@@ -1625,6 +1673,75 @@ func (b *builtinCastJSONAsJSONSig) evalJSON(row chunk.Row) (res json.BinaryJSON,
 	return b.args[0].EvalJSON(b.ctx, row)
 }
 
+// builtinCastJSONAsArraySig converts every element of the JSON array to the
+// element type, a nonarray value is treated as an array with only one element.
+type builtinCastJSONAsArraySig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastJSONAsArraySig) Clone() builtinFunc {
+	newSig := &builtinCastJSONAsArraySig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastJSONAsArraySig) evalJSON(row chunk.Row) (res json.BinaryJSON, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalJSON(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	if val.TypeCode == json.TypeCodeObject {
+		return res, false, errNotSupportedYet.GenWithStackByArgs("CAST-ing JSON OBJECT type to array")
+	}
+	elems := []json.BinaryJSON{val}
+	if val.TypeCode == json.TypeCodeArray {
+		elems = make([]json.BinaryJSON, 0, val.GetElemCount())
+		for i := 0; i < val.GetElemCount(); i++ {
+			elems = append(elems, val.ArrayGetElem(i))
+		}
+	}
+	arr := make([]interface{}, 0, len(elems))
+	for _, elem := range elems {
+		v, err := b.convertElem(elem)
+		if err != nil {
+			return res, false, err
+		}
+		arr = append(arr, v)
+	}
+	return json.CreateBinary(arr), false, nil
+}
+
+func (b *builtinCastJSONAsArraySig) convertElem(elem json.BinaryJSON) (interface{}, error) {
+	var d types.Datum
+	switch elem.TypeCode {
+	case json.TypeCodeObject, json.TypeCodeArray:
+		return nil, errNotSupportedYet.GenWithStackByArgs("CAST-ing Non-scalar JSON value to array element")
+	case json.TypeCodeLiteral:
+		if elem.Value[0] == json.LiteralNil {
+			return nil, errNotSupportedYet.GenWithStackByArgs("CAST-ing JSON null to array element")
+		}
+		d = types.NewJSONDatum(elem)
+	case json.TypeCodeString:
+		d = types.NewStringDatum(string(elem.GetString()))
+	default:
+		d = types.NewJSONDatum(elem)
+	}
+	d, err := d.ConvertTo(b.ctx.GetSessionVars().StmtCtx, b.tp.ArrayElem)
+	if err != nil {
+		return nil, err
+	}
+	switch d.Kind() {
+	case types.KindInt64:
+		return d.GetInt64(), nil
+	case types.KindUint64:
+		return d.GetUint64(), nil
+	case types.KindFloat64:
+		return d.GetFloat64(), nil
+	default:
+		return d.GetString(), nil
+	}
+}
+
 type builtinCastJSONAsIntSig struct {
 	baseBuiltinCastFunc
 }
@@ -1820,6 +1937,14 @@ func BuildCastFunction4Union(ctx sessionctx.Context, expr Expression, tp *types.
 
 // BuildCastFunction builds a CAST ScalarFunction from the Expression.
 func BuildCastFunction(ctx sessionctx.Context, expr Expression, tp *types.FieldType) (res Expression) {
+	res, err := BuildCastFunctionWithCheck(ctx, expr, tp)
+	terror.Log(err)
+	return res
+}
+
+// BuildCastFunctionWithCheck builds a CAST ScalarFunction from the Expression and
+// returns the error if the CAST is invalid, e.g. CAST-ing non-JSON value to array.
+func BuildCastFunctionWithCheck(ctx sessionctx.Context, expr Expression, tp *types.FieldType) (res Expression, err error) {
 	expr = TryPushCastIntoControlFunctionForHybridType(ctx, expr, tp)
 	var fc functionClass
 	switch tp.EvalType() {
@@ -1834,12 +1959,18 @@ func BuildCastFunction(ctx sessionctx.Context, expr Expression, tp *types.FieldT
 	case types.ETDuration:
 		fc = &castAsDurationFunctionClass{baseFunctionClass{ast.Cast, 1, 1}, tp}
 	case types.ETJson:
-		fc = &castAsJSONFunctionClass{baseFunctionClass{ast.Cast, 1, 1}, tp}
+		if tp.ArrayElem != nil {
+			fc = &castJSONAsArrayFunctionClass{baseFunctionClass{ast.Cast, 1, 1}, tp}
+		} else {
+			fc = &castAsJSONFunctionClass{baseFunctionClass{ast.Cast, 1, 1}, tp}
+		}
 	case types.ETString:
 		fc = &castAsStringFunctionClass{baseFunctionClass{ast.Cast, 1, 1}, tp}
 	}
 	f, err := fc.getFunction(ctx, []Expression{expr})
-	terror.Log(err)
+	if err != nil {
+		return nil, err
+	}
 	res = &ScalarFunction{
 		FuncName: model.NewCIStr(ast.Cast),
 		RetType:  tp,
@@ -1851,7 +1982,7 @@ func BuildCastFunction(ctx sessionctx.Context, expr Expression, tp *types.FieldT
 	if tp.EvalType() != types.ETJson {
 		res = FoldConstant(res)
 	}
-	return res
+	return res, nil
 }
 
 // WrapWithCastAsInt wraps `expr` with `cast` if the return type of expr is not
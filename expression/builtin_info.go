@@ -385,6 +385,49 @@ func (b *builtinLastInsertIDWithIDSig) evalInt(row chunk.Row) (res int64, isNull
 	return res, false, nil
 }
 
+type queryAttributeStringFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *queryAttributeStringFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, types.ETString)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.Charset, bf.tp.Collate = ctx.GetSessionVars().GetCharsetInfo()
+	bf.tp.Flen = mysql.MaxBlobWidth
+	sig := &builtinQueryAttributeStringSig{bf}
+	return sig, nil
+}
+
+type builtinQueryAttributeStringSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinQueryAttributeStringSig) Clone() builtinFunc {
+	newSig := &builtinQueryAttributeStringSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals MYSQL_QUERY_ATTRIBUTE_STRING(name).
+// It returns NULL if the attribute is not sent with the current statement.
+// See https://dev.mysql.com/doc/refman/8.0/en/query-attributes.html
+func (b *builtinQueryAttributeStringSig) evalString(row chunk.Row) (string, bool, error) {
+	name, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	val, ok := b.ctx.GetSessionVars().QueryAttributes[name]
+	if !ok {
+		return "", true, nil
+	}
+	return val, false, nil
+}
+
 type versionFunctionClass struct {
 	baseFunctionClass
 }
@@ -311,6 +311,35 @@ func TestLastInsertID(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestQueryAttributeString(t *testing.T) {
+	t.Parallel()
+	ctx := createContext(t)
+	ctx.GetSessionVars().QueryAttributes = map[string]string{"a": "x", "b": ""}
+	tbl := []struct {
+		name   interface{}
+		expect interface{}
+	}{
+		{"a", "x"},
+		{"b", ""},
+		{"c", nil},
+		{nil, nil},
+	}
+	fc := funcs[ast.QueryAttributeString]
+	for _, tt := range tbl {
+		f, err := fc.getFunction(ctx, datumsToConstants(types.MakeDatums(tt.name)))
+		require.NoError(t, err)
+		d, err := evalBuiltinFunc(f, chunk.Row{})
+		require.NoError(t, err)
+		trequire.DatumEqual(t, types.NewDatum(tt.expect), d)
+	}
+	ctx.GetSessionVars().QueryAttributes = nil
+	f, err := fc.getFunction(ctx, datumsToConstants(types.MakeDatums("a")))
+	require.NoError(t, err)
+	d, err := evalBuiltinFunc(f, chunk.Row{})
+	require.NoError(t, err)
+	require.True(t, d.IsNull())
+}
+
 func TestFormatBytes(t *testing.T) {
 	t.Parallel()
 	ctx := createContext(t)
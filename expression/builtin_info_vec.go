@@ -327,6 +327,37 @@ func (b *builtinVersionSig) vecEvalString(input *chunk.Chunk, result *chunk.Colu
 	return nil
 }
 
+func (b *builtinQueryAttributeStringSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinQueryAttributeStringSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf, err := b.bufAllocator.get()
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(buf)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf); err != nil {
+		return err
+	}
+	attrs := b.ctx.GetSessionVars().QueryAttributes
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		val, ok := attrs[buf.GetString(i)]
+		if !ok {
+			result.AppendNull()
+			continue
+		}
+		result.AppendString(val)
+	}
+	return nil
+}
+
 func (b *builtinTiDBDecodeKeySig) vectorized() bool {
 	return true
 }
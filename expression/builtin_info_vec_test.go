@@ -76,6 +76,9 @@ var vecBuiltinInfoCases = map[string][]vecExprBenchCase{
 	ast.RowCount: {
 		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{}},
 	},
+	ast.QueryAttributeString: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString}},
+	},
 	ast.CurrentRole: {
 		{retEvalType: types.ETString, childrenTypes: []types.EvalType{}},
 	},
@@ -1266,3 +1266,51 @@ func (b *builtinJSONMergePatchSig) vecEvalJSON(input *chunk.Chunk, result *chunk
 
 	return nil
 }
+
+func (b *builtinJSONMemberOfSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinJSONMemberOfSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	return b.vecEvalJSONPredicate(input, result, json.MemberOfBinary)
+}
+
+func (b *builtinJSONOverlapsSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinJSONOverlapsSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	return b.vecEvalJSONPredicate(input, result, json.OverlapsBinary)
+}
+
+// vecEvalJSONPredicate evaluates the two JSON arguments and applies the predicate on them.
+func (b *baseBuiltinFunc) vecEvalJSONPredicate(input *chunk.Chunk, result *chunk.Column, pred func(left, right json.BinaryJSON) bool) error {
+	nr := input.NumRows()
+	leftCol, err := b.bufAllocator.get()
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(leftCol)
+	if err := b.args[0].VecEvalJSON(b.ctx, input, leftCol); err != nil {
+		return err
+	}
+	rightCol, err := b.bufAllocator.get()
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(rightCol)
+	if err := b.args[1].VecEvalJSON(b.ctx, input, rightCol); err != nil {
+		return err
+	}
+
+	result.ResizeInt64(nr, false)
+	result.MergeNulls(leftCol, rightCol)
+	i64s := result.Int64s()
+	for i := 0; i < nr; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		i64s[i] = boolToInt64(pred(leftCol.GetJSON(i), rightCol.GetJSON(i)))
+	}
+	return nil
+}
@@ -0,0 +1,580 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pingcap/tidb/parser/charset"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/collate"
+	"github.com/pingcap/tipb/go-tipb"
+)
+
+var (
+	_ functionClass = &regexpLikeFunctionClass{}
+	_ functionClass = &regexpInStrFunctionClass{}
+	_ functionClass = &regexpSubstrFunctionClass{}
+	_ functionClass = &regexpReplaceFunctionClass{}
+)
+
+var (
+	_ builtinFunc = &builtinRegexpLikeFuncSig{}
+	_ builtinFunc = &builtinRegexpInStrFuncSig{}
+	_ builtinFunc = &builtinRegexpSubstrFuncSig{}
+	_ builtinFunc = &builtinRegexpReplaceFuncSig{}
+)
+
+const (
+	regexpInvalidMatchType    = "Invalid match type"
+	regexpInvalidIndex        = "Index out of bounds in regular expression search"
+	regexpInvalidReturnOption = "Incorrect arguments to regexp_instr: return_option must be 1 or 0"
+)
+
+// regexpBaseFuncSig is the shared part of the REGEXP_* functions. The regexp is
+// memorized when both the pattern and the match type are constant.
+type regexpBaseFuncSig struct {
+	baseBuiltinFunc
+	// isBinCollation indicates the positions are counted in bytes instead of characters.
+	isBinCollation bool
+	// isCICollation indicates the match is case-insensitive unless the match type says otherwise.
+	isCICollation bool
+
+	memorizedRegexp *regexp.Regexp
+	memorizedErr    error
+}
+
+func newRegexpBaseFuncSig(bf baseBuiltinFunc) regexpBaseFuncSig {
+	return regexpBaseFuncSig{
+		baseBuiltinFunc: bf,
+		isBinCollation:  bf.collation == charset.CollationBin,
+		isCICollation:   collate.IsCICollation(bf.collation),
+	}
+}
+
+func (b *regexpBaseFuncSig) clone(from *regexpBaseFuncSig) {
+	b.cloneFrom(&from.baseBuiltinFunc)
+	b.isBinCollation = from.isBinCollation
+	b.isCICollation = from.isCICollation
+	if from.memorizedRegexp != nil {
+		b.memorizedRegexp = from.memorizedRegexp.Copy()
+	}
+	b.memorizedErr = from.memorizedErr
+}
+
+// canMemorize checks whether the pattern and the match type are constant.
+func (b *regexpBaseFuncSig) canMemorize(patternIdx, matchTypeIdx int) bool {
+	sc := b.ctx.GetSessionVars().StmtCtx
+	if !b.args[patternIdx].ConstItem(sc) {
+		return false
+	}
+	return matchTypeIdx >= len(b.args) || b.args[matchTypeIdx].ConstItem(sc)
+}
+
+// getRegexp returns the compiled regexp of the pattern and the match type, the
+// result is memorized if canMemorize is true.
+func (b *regexpBaseFuncSig) getRegexp(pattern, matchType string, canMemorize bool) (*regexp.Regexp, error) {
+	if canMemorize && (b.memorizedRegexp != nil || b.memorizedErr != nil) {
+		return b.memorizedRegexp, b.memorizedErr
+	}
+	re, err := b.compile(pattern, matchType)
+	if canMemorize {
+		b.memorizedRegexp, b.memorizedErr = re, err
+	}
+	return re, err
+}
+
+func (b *regexpBaseFuncSig) compile(pattern, matchType string) (*regexp.Regexp, error) {
+	flags, err := b.buildFlags(matchType)
+	if err != nil {
+		return nil, err
+	}
+	if len(flags) > 0 {
+		pattern = "(?" + flags + ")" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, ErrRegexp.GenWithStackByArgs(err.Error())
+	}
+	return re, nil
+}
+
+// buildFlags converts the match type to the flags of the Go regexp.
+// See https://dev.mysql.com/doc/refman/8.0/en/regexp.html#function_regexp-like
+func (b *regexpBaseFuncSig) buildFlags(matchType string) (string, error) {
+	ci, multiLine, dotAll := b.isCICollation, false, false
+	for _, c := range matchType {
+		switch c {
+		case 'c':
+			ci = false
+		case 'i':
+			ci = true
+		case 'm':
+			multiLine = true
+		case 'n':
+			dotAll = true
+		case 'u':
+			// Only '\n' is recognized as the line terminator in Go regexp.
+		default:
+			return "", ErrRegexp.GenWithStackByArgs(regexpInvalidMatchType)
+		}
+	}
+	var flags strings.Builder
+	if ci {
+		flags.WriteByte('i')
+	}
+	if multiLine {
+		flags.WriteByte('m')
+	}
+	if dotAll {
+		flags.WriteByte('s')
+	}
+	return flags.String(), nil
+}
+
+// getByteOffset converts the 1-based position into the byte offset of the
+// string. The position counts characters unless the collation is binary.
+func (b *regexpBaseFuncSig) getByteOffset(s string, pos int64) (int, error) {
+	if pos < 1 {
+		return 0, ErrRegexp.GenWithStackByArgs(regexpInvalidIndex)
+	}
+	if b.isBinCollation {
+		if pos > int64(len(s))+1 {
+			return 0, ErrRegexp.GenWithStackByArgs(regexpInvalidIndex)
+		}
+		return int(pos - 1), nil
+	}
+	offset := 0
+	for i := int64(1); i < pos; i++ {
+		if offset >= len(s) {
+			return 0, ErrRegexp.GenWithStackByArgs(regexpInvalidIndex)
+		}
+		_, size := utf8.DecodeRuneInString(s[offset:])
+		offset += size
+	}
+	return offset, nil
+}
+
+// getPosition converts the byte offset of the string into the 1-based position.
+func (b *regexpBaseFuncSig) getPosition(s string, offset int) int64 {
+	if b.isBinCollation {
+		return int64(offset) + 1
+	}
+	return int64(utf8.RuneCountInString(s[:offset])) + 1
+}
+
+// findOccurrence returns the byte indexes of the occurrence-th match in the
+// string starting at the offset, or nil if there are not enough matches.
+func findOccurrence(re *regexp.Regexp, s string, offset int, occurrence int64) []int {
+	if occurrence < 1 {
+		occurrence = 1
+	}
+	matches := re.FindAllStringIndex(s[offset:], int(occurrence))
+	if int64(len(matches)) < occurrence {
+		return nil
+	}
+	match := matches[occurrence-1]
+	return []int{match[0] + offset, match[1] + offset}
+}
+
+type regexpLikeFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *regexpLikeFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := []types.EvalType{types.ETString, types.ETString}
+	if len(args) == 3 {
+		argTps = append(argTps, types.ETString)
+	}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETInt, argTps...)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.Flen = 1
+	sig := newBuiltinRegexpLikeFuncSig(bf)
+	if sig.isBinCollation {
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpLikeSig)
+	} else {
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpLikeUTF8Sig)
+	}
+	return sig, nil
+}
+
+type builtinRegexpLikeFuncSig struct {
+	regexpBaseFuncSig
+}
+
+func newBuiltinRegexpLikeFuncSig(bf baseBuiltinFunc) *builtinRegexpLikeFuncSig {
+	return &builtinRegexpLikeFuncSig{regexpBaseFuncSig: newRegexpBaseFuncSig(bf)}
+}
+
+func (b *builtinRegexpLikeFuncSig) Clone() builtinFunc {
+	newSig := &builtinRegexpLikeFuncSig{}
+	newSig.clone(&b.regexpBaseFuncSig)
+	return newSig
+}
+
+// evalInt evals `REGEXP_LIKE(expr, pat[, match_type])`.
+// See https://dev.mysql.com/doc/refman/8.0/en/regexp.html#function_regexp-like
+func (b *builtinRegexpLikeFuncSig) evalInt(row chunk.Row) (int64, bool, error) {
+	expr, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	pat, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	matchType := ""
+	if len(b.args) > 2 {
+		matchType, isNull, err = b.args[2].EvalString(b.ctx, row)
+		if isNull || err != nil {
+			return 0, true, err
+		}
+	}
+	re, err := b.getRegexp(pat, matchType, b.canMemorize(1, 2))
+	if err != nil {
+		return 0, true, err
+	}
+	return boolToInt64(re.MatchString(expr)), false, nil
+}
+
+type regexpInStrFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *regexpInStrFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := []types.EvalType{types.ETString, types.ETString, types.ETInt, types.ETInt, types.ETInt, types.ETString}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETInt, argTps[:len(args)]...)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.Flen = mysql.MaxIntWidth
+	sig := newBuiltinRegexpInStrFuncSig(bf)
+	if sig.isBinCollation {
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpInStrSig)
+	} else {
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpInStrUTF8Sig)
+	}
+	return sig, nil
+}
+
+type builtinRegexpInStrFuncSig struct {
+	regexpBaseFuncSig
+}
+
+func newBuiltinRegexpInStrFuncSig(bf baseBuiltinFunc) *builtinRegexpInStrFuncSig {
+	return &builtinRegexpInStrFuncSig{regexpBaseFuncSig: newRegexpBaseFuncSig(bf)}
+}
+
+func (b *builtinRegexpInStrFuncSig) Clone() builtinFunc {
+	newSig := &builtinRegexpInStrFuncSig{}
+	newSig.clone(&b.regexpBaseFuncSig)
+	return newSig
+}
+
+// evalInt evals `REGEXP_INSTR(expr, pat[, pos[, occurrence[, return_option[, match_type]]]])`.
+// See https://dev.mysql.com/doc/refman/8.0/en/regexp.html#function_regexp-instr
+func (b *builtinRegexpInStrFuncSig) evalInt(row chunk.Row) (int64, bool, error) {
+	expr, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	pat, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	intArgs := []int64{1, 1, 0}
+	for i := 2; i < len(b.args) && i < 5; i++ {
+		intArgs[i-2], isNull, err = b.args[i].EvalInt(b.ctx, row)
+		if isNull || err != nil {
+			return 0, true, err
+		}
+	}
+	matchType := ""
+	if len(b.args) > 5 {
+		matchType, isNull, err = b.args[5].EvalString(b.ctx, row)
+		if isNull || err != nil {
+			return 0, true, err
+		}
+	}
+	re, err := b.getRegexp(pat, matchType, b.canMemorize(1, 5))
+	if err != nil {
+		return 0, true, err
+	}
+	res, err := b.instr(re, expr, intArgs[0], intArgs[1], intArgs[2])
+	if err != nil {
+		return 0, true, err
+	}
+	return res, false, nil
+}
+
+func (b *builtinRegexpInStrFuncSig) instr(re *regexp.Regexp, expr string, pos, occurrence, returnOption int64) (int64, error) {
+	if returnOption != 0 && returnOption != 1 {
+		return 0, ErrRegexp.GenWithStackByArgs(regexpInvalidReturnOption)
+	}
+	offset, err := b.getByteOffset(expr, pos)
+	if err != nil {
+		return 0, err
+	}
+	match := findOccurrence(re, expr, offset, occurrence)
+	if match == nil {
+		return 0, nil
+	}
+	return b.getPosition(expr, match[returnOption]), nil
+}
+
+type regexpSubstrFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *regexpSubstrFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := []types.EvalType{types.ETString, types.ETString, types.ETInt, types.ETInt, types.ETString}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, argTps[:len(args)]...)
+	if err != nil {
+		return nil, err
+	}
+	argType := args[0].GetType()
+	bf.tp.Flen = argType.Flen
+	SetBinFlagOrBinStr(argType, bf.tp)
+	sig := newBuiltinRegexpSubstrFuncSig(bf)
+	if sig.isBinCollation {
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpSubstrSig)
+	} else {
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpSubstrUTF8Sig)
+	}
+	return sig, nil
+}
+
+type builtinRegexpSubstrFuncSig struct {
+	regexpBaseFuncSig
+}
+
+func newBuiltinRegexpSubstrFuncSig(bf baseBuiltinFunc) *builtinRegexpSubstrFuncSig {
+	return &builtinRegexpSubstrFuncSig{regexpBaseFuncSig: newRegexpBaseFuncSig(bf)}
+}
+
+func (b *builtinRegexpSubstrFuncSig) Clone() builtinFunc {
+	newSig := &builtinRegexpSubstrFuncSig{}
+	newSig.clone(&b.regexpBaseFuncSig)
+	return newSig
+}
+
+// evalString evals `REGEXP_SUBSTR(expr, pat[, pos[, occurrence[, match_type]]])`.
+// See https://dev.mysql.com/doc/refman/8.0/en/regexp.html#function_regexp-substr
+func (b *builtinRegexpSubstrFuncSig) evalString(row chunk.Row) (string, bool, error) {
+	expr, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	pat, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", true, err
+	}
+	intArgs := []int64{1, 1}
+	for i := 2; i < len(b.args) && i < 4; i++ {
+		intArgs[i-2], isNull, err = b.args[i].EvalInt(b.ctx, row)
+		if isNull || err != nil {
+			return "", true, err
+		}
+	}
+	matchType := ""
+	if len(b.args) > 4 {
+		matchType, isNull, err = b.args[4].EvalString(b.ctx, row)
+		if isNull || err != nil {
+			return "", true, err
+		}
+	}
+	re, err := b.getRegexp(pat, matchType, b.canMemorize(1, 4))
+	if err != nil {
+		return "", true, err
+	}
+	return b.substr(re, expr, intArgs[0], intArgs[1])
+}
+
+func (b *builtinRegexpSubstrFuncSig) substr(re *regexp.Regexp, expr string, pos, occurrence int64) (string, bool, error) {
+	offset, err := b.getByteOffset(expr, pos)
+	if err != nil {
+		return "", true, err
+	}
+	match := findOccurrence(re, expr, offset, occurrence)
+	if match == nil {
+		return "", true, nil
+	}
+	return expr[match[0]:match[1]], false, nil
+}
+
+type regexpReplaceFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *regexpReplaceFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := []types.EvalType{types.ETString, types.ETString, types.ETString, types.ETInt, types.ETInt, types.ETString}
+	bf, err := newBaseBuiltinFuncWithTp(ctx, c.funcName, args, types.ETString, argTps[:len(args)]...)
+	if err != nil {
+		return nil, err
+	}
+	bf.tp.Flen = mysql.MaxBlobWidth
+	SetBinFlagOrBinStr(args[0].GetType(), bf.tp)
+	sig := newBuiltinRegexpReplaceFuncSig(bf)
+	if sig.isBinCollation {
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpReplaceSig)
+	} else {
+		sig.setPbCode(tipb.ScalarFuncSig_RegexpReplaceUTF8Sig)
+	}
+	return sig, nil
+}
+
+type builtinRegexpReplaceFuncSig struct {
+	regexpBaseFuncSig
+}
+
+func newBuiltinRegexpReplaceFuncSig(bf baseBuiltinFunc) *builtinRegexpReplaceFuncSig {
+	return &builtinRegexpReplaceFuncSig{regexpBaseFuncSig: newRegexpBaseFuncSig(bf)}
+}
+
+func (b *builtinRegexpReplaceFuncSig) Clone() builtinFunc {
+	newSig := &builtinRegexpReplaceFuncSig{}
+	newSig.clone(&b.regexpBaseFuncSig)
+	return newSig
+}
+
+// evalString evals `REGEXP_REPLACE(expr, pat, repl[, pos[, occurrence[, match_type]]])`.
+// See https://dev.mysql.com/doc/refman/8.0/en/regexp.html#function_regexp-replace
+func (b *builtinRegexpReplaceFuncSig) evalString(row chunk.Row) (string, bool, error) {
+	var (
+		strArgs [3]string
+		isNull  bool
+		err     error
+	)
+	for i := range strArgs {
+		strArgs[i], isNull, err = b.args[i].EvalString(b.ctx, row)
+		if isNull || err != nil {
+			return "", true, err
+		}
+	}
+	intArgs := []int64{1, 0}
+	for i := 3; i < len(b.args) && i < 5; i++ {
+		intArgs[i-3], isNull, err = b.args[i].EvalInt(b.ctx, row)
+		if isNull || err != nil {
+			return "", true, err
+		}
+	}
+	matchType := ""
+	if len(b.args) > 5 {
+		matchType, isNull, err = b.args[5].EvalString(b.ctx, row)
+		if isNull || err != nil {
+			return "", true, err
+		}
+	}
+	re, err := b.getRegexp(strArgs[1], matchType, b.canMemorize(1, 5))
+	if err != nil {
+		return "", true, err
+	}
+	res, err := b.replace(re, strArgs[0], strArgs[2], intArgs[0], intArgs[1])
+	if err != nil {
+		return "", true, err
+	}
+	return res, false, nil
+}
+
+// replace replaces the occurrence-th match starting at the position with the
+// replacement, all the matches are replaced if occurrence is less than 1.
+func (b *builtinRegexpReplaceFuncSig) replace(re *regexp.Regexp, expr, repl string, pos, occurrence int64) (string, error) {
+	offset, err := b.getByteOffset(expr, pos)
+	if err != nil {
+		return "", err
+	}
+	n := -1
+	if occurrence > 0 {
+		n = int(occurrence)
+	}
+	src := expr[offset:]
+	matches := re.FindAllStringSubmatchIndex(src, n)
+	if occurrence > 0 {
+		if int64(len(matches)) < occurrence {
+			return expr, nil
+		}
+		matches = matches[occurrence-1:]
+	}
+	if len(matches) == 0 {
+		return expr, nil
+	}
+	template := convertRegexpReplacement(repl)
+	res := make([]byte, 0, len(expr))
+	res = append(res, expr[:offset]...)
+	last := 0
+	for _, match := range matches {
+		res = append(res, src[last:match[0]]...)
+		res = re.ExpandString(res, template, src, match)
+		last = match[1]
+	}
+	res = append(res, src[last:]...)
+	return string(res), nil
+}
+
+// convertRegexpReplacement converts the replacement of MySQL, in which `$n`
+// refers to the n-th captured group and `\` escapes the next character, into
+// the template of Go regexp.
+func convertRegexpReplacement(repl string) string {
+	if !strings.ContainsAny(repl, `$\`) {
+		return repl
+	}
+	var sb strings.Builder
+	for i := 0; i < len(repl); i++ {
+		switch c := repl[i]; c {
+		case '\\':
+			if i+1 < len(repl) {
+				i++
+				if repl[i] == '$' {
+					sb.WriteString("$$")
+				} else {
+					sb.WriteByte(repl[i])
+				}
+			}
+		case '$':
+			j := i + 1
+			for j < len(repl) && repl[j] >= '0' && repl[j] <= '9' {
+				j++
+			}
+			if j == i+1 {
+				sb.WriteString("$$")
+			} else {
+				sb.WriteString("${" + repl[i+1:j] + "}")
+				i = j - 1
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
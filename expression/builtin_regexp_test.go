@@ -0,0 +1,161 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/charset"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/testkit/trequire"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/stretchr/testify/require"
+)
+
+func testRegexpFunc(t *testing.T, funcName string, args []interface{}, expect interface{}, expectErr error) {
+	ctx := createContext(t)
+	comment := fmt.Sprintf("%s%v", funcName, args)
+	datums := types.MakeDatums(args...)
+	for i := range datums {
+		if datums[i].Kind() == types.KindString {
+			datums[i].SetString(datums[i].GetString(), charset.CollationUTF8MB4)
+		}
+	}
+	f, err := funcs[funcName].getFunction(ctx, datumsToConstants(datums))
+	require.NoError(t, err, comment)
+	res, err := evalBuiltinFunc(f, chunk.Row{})
+	if expectErr != nil {
+		require.True(t, terror.ErrorEqual(err, expectErr), comment)
+		return
+	}
+	require.NoError(t, err, comment)
+	trequire.DatumEqual(t, types.NewDatum(expect), res, comment)
+}
+
+func TestRegexpLike(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		args   []interface{}
+		expect interface{}
+		err    error
+	}{
+		{[]interface{}{"abc", "^a"}, 1, nil},
+		{[]interface{}{"abc", "^b"}, 0, nil},
+		{[]interface{}{"abc", "B"}, 0, nil},
+		{[]interface{}{"abc", "B", "i"}, 1, nil},
+		{[]interface{}{"abc", "B", "ic"}, 0, nil},
+		{[]interface{}{"abc", "B", "ci"}, 1, nil},
+		{[]interface{}{"a\nb", "^b$"}, 0, nil},
+		{[]interface{}{"a\nb", "^b$", "m"}, 1, nil},
+		{[]interface{}{"a\nb", "a.b"}, 0, nil},
+		{[]interface{}{"a\nb", "a.b", "n"}, 1, nil},
+		{[]interface{}{"你好", "^.{2}$"}, 1, nil},
+		{[]interface{}{nil, "a"}, nil, nil},
+		{[]interface{}{"a", nil}, nil, nil},
+		{[]interface{}{"a", "a", nil}, nil, nil},
+		{[]interface{}{"a", "(", ""}, nil, ErrRegexp},
+		{[]interface{}{"a", "a", "x"}, nil, ErrRegexp},
+	}
+	for _, tt := range tests {
+		testRegexpFunc(t, ast.RegexpLike, tt.args, tt.expect, tt.err)
+	}
+}
+
+func TestRegexpInStr(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		args   []interface{}
+		expect interface{}
+		err    error
+	}{
+		{[]interface{}{"dog cat dog", "dog"}, 1, nil},
+		{[]interface{}{"dog cat dog", "dog", 2}, 9, nil},
+		{[]interface{}{"dog cat dog", "dog", 1, 2}, 9, nil},
+		{[]interface{}{"dog cat dog", "dog", 1, 3}, 0, nil},
+		{[]interface{}{"dog cat dog", "dog", 1, 0}, 1, nil},
+		{[]interface{}{"dog cat dog", "dog", 1, 1, 1}, 4, nil},
+		{[]interface{}{"dog cat dog", "DOG", 1, 1, 0, "i"}, 1, nil},
+		{[]interface{}{"aa aaa aaaa", "a{4}"}, 8, nil},
+		{[]interface{}{"你好世界", "世"}, 3, nil},
+		{[]interface{}{"你好世界", "世", 3}, 3, nil},
+		{[]interface{}{"你好世界", "世", 4}, 0, nil},
+		{[]interface{}{"你好世界", "世", 1, 1, 1}, 4, nil},
+		{[]interface{}{[]byte("你好世界"), "世"}, 7, nil},
+		{[]interface{}{[]byte("你好世界"), "世", 7}, 7, nil},
+		{[]interface{}{"abc", "c", 4}, 0, nil},
+		{[]interface{}{"abc", "c", 5}, nil, ErrRegexp},
+		{[]interface{}{"abc", "c", 0}, nil, ErrRegexp},
+		{[]interface{}{"abc", "c", 1, 1, 2}, nil, ErrRegexp},
+		{[]interface{}{nil, "c"}, nil, nil},
+		{[]interface{}{"abc", "c", nil}, nil, nil},
+	}
+	for _, tt := range tests {
+		testRegexpFunc(t, ast.RegexpInStr, tt.args, tt.expect, tt.err)
+	}
+}
+
+func TestRegexpSubstr(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		args   []interface{}
+		expect interface{}
+		err    error
+	}{
+		{[]interface{}{"abc def ghi", "[a-z]+"}, "abc", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", 1, 3}, "ghi", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", 2}, "bc", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", 1, 4}, nil, nil},
+		{[]interface{}{"abc def ghi", "[A-Z]+"}, nil, nil},
+		{[]interface{}{"abc def ghi", "[A-Z]+", 1, 2, "i"}, "def", nil},
+		{[]interface{}{"你好世界", ".", 3}, "世", nil},
+		{[]interface{}{[]byte("你好世界"), ".", 7}, "世", nil},
+		{[]interface{}{"abc", "b", 5}, nil, ErrRegexp},
+		{[]interface{}{nil, "b"}, nil, nil},
+		{[]interface{}{"abc", "b", 1, nil}, nil, nil},
+	}
+	for _, tt := range tests {
+		testRegexpFunc(t, ast.RegexpSubstr, tt.args, tt.expect, tt.err)
+	}
+}
+
+func TestRegexpReplace(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		args   []interface{}
+		expect interface{}
+		err    error
+	}{
+		{[]interface{}{"a b c", "b", "X"}, "a X c", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", "X"}, "X X X", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", "X", 1, 3}, "abc def X", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", "X", 2}, "aX X X", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", "X", 5, 1}, "abc X ghi", nil},
+		{[]interface{}{"abc def ghi", "[a-z]+", "X", 1, 4}, "abc def ghi", nil},
+		{[]interface{}{"abc def ghi", "[A-Z]+", "X", 1, 0, "i"}, "X X X", nil},
+		{[]interface{}{"abc def", "([a-z]+) ([a-z]+)", "$2 $1"}, "def abc", nil},
+		{[]interface{}{"abc def", "([a-z]+) ([a-z]+)", "$2x"}, "defx", nil},
+		{[]interface{}{"abc", "b", `\$1`}, "a$1c", nil},
+		{[]interface{}{"你好世界", ".", "X", 3}, "你好XX", nil},
+		{[]interface{}{"abc", "b", "X", 5}, nil, ErrRegexp},
+		{[]interface{}{nil, "b", "X"}, nil, nil},
+		{[]interface{}{"abc", "b", nil}, nil, nil},
+	}
+	for _, tt := range tests {
+		testRegexpFunc(t, ast.RegexpReplace, tt.args, tt.expect, tt.err)
+	}
+}
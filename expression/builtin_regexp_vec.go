@@ -0,0 +1,216 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+// vecEvalArgs evaluates all the arguments into the buffers, which must be
+// released by the returned function.
+func (b *regexpBaseFuncSig) vecEvalArgs(input *chunk.Chunk) ([]*chunk.Column, func(), error) {
+	bufs := make([]*chunk.Column, 0, len(b.args))
+	release := func() {
+		for _, buf := range bufs {
+			b.bufAllocator.put(buf)
+		}
+	}
+	for _, arg := range b.args {
+		buf, err := b.bufAllocator.get()
+		if err != nil {
+			release()
+			return nil, nil, err
+		}
+		bufs = append(bufs, buf)
+		switch arg.GetType().EvalType() {
+		case types.ETInt:
+			err = arg.VecEvalInt(b.ctx, input, buf)
+		default:
+			err = arg.VecEvalString(b.ctx, input, buf)
+		}
+		if err != nil {
+			release()
+			return nil, nil, err
+		}
+	}
+	return bufs, release, nil
+}
+
+func (b *builtinRegexpLikeFuncSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRegexpLikeFuncSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufs, release, err := b.vecEvalArgs(input)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	canMemorize := b.canMemorize(1, 2)
+	result.ResizeInt64(n, false)
+	result.MergeNulls(bufs...)
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		matchType := ""
+		if len(bufs) > 2 {
+			matchType = bufs[2].GetString(i)
+		}
+		re, err := b.getRegexp(bufs[1].GetString(i), matchType, canMemorize)
+		if err != nil {
+			return err
+		}
+		i64s[i] = boolToInt64(re.MatchString(bufs[0].GetString(i)))
+	}
+	return nil
+}
+
+func (b *builtinRegexpInStrFuncSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRegexpInStrFuncSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufs, release, err := b.vecEvalArgs(input)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	canMemorize := b.canMemorize(1, 5)
+	result.ResizeInt64(n, false)
+	result.MergeNulls(bufs...)
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		intArgs := []int64{1, 1, 0}
+		for j := 2; j < len(bufs) && j < 5; j++ {
+			intArgs[j-2] = bufs[j].GetInt64(i)
+		}
+		matchType := ""
+		if len(bufs) > 5 {
+			matchType = bufs[5].GetString(i)
+		}
+		re, err := b.getRegexp(bufs[1].GetString(i), matchType, canMemorize)
+		if err != nil {
+			return err
+		}
+		i64s[i], err = b.instr(re, bufs[0].GetString(i), intArgs[0], intArgs[1], intArgs[2])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *builtinRegexpSubstrFuncSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRegexpSubstrFuncSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufs, release, err := b.vecEvalArgs(input)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	canMemorize := b.canMemorize(1, 4)
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if hasNullInBufs(bufs, i) {
+			result.AppendNull()
+			continue
+		}
+		intArgs := []int64{1, 1}
+		for j := 2; j < len(bufs) && j < 4; j++ {
+			intArgs[j-2] = bufs[j].GetInt64(i)
+		}
+		matchType := ""
+		if len(bufs) > 4 {
+			matchType = bufs[4].GetString(i)
+		}
+		re, err := b.getRegexp(bufs[1].GetString(i), matchType, canMemorize)
+		if err != nil {
+			return err
+		}
+		res, isNull, err := b.substr(re, bufs[0].GetString(i), intArgs[0], intArgs[1])
+		if err != nil {
+			return err
+		}
+		if isNull {
+			result.AppendNull()
+			continue
+		}
+		result.AppendString(res)
+	}
+	return nil
+}
+
+func (b *builtinRegexpReplaceFuncSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRegexpReplaceFuncSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufs, release, err := b.vecEvalArgs(input)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	canMemorize := b.canMemorize(1, 5)
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if hasNullInBufs(bufs, i) {
+			result.AppendNull()
+			continue
+		}
+		intArgs := []int64{1, 0}
+		for j := 3; j < len(bufs) && j < 5; j++ {
+			intArgs[j-3] = bufs[j].GetInt64(i)
+		}
+		matchType := ""
+		if len(bufs) > 5 {
+			matchType = bufs[5].GetString(i)
+		}
+		re, err := b.getRegexp(bufs[1].GetString(i), matchType, canMemorize)
+		if err != nil {
+			return err
+		}
+		res, err := b.replace(re, bufs[0].GetString(i), bufs[2].GetString(i), intArgs[0], intArgs[1])
+		if err != nil {
+			return err
+		}
+		result.AppendString(res)
+	}
+	return nil
+}
+
+func hasNullInBufs(bufs []*chunk.Column, i int) bool {
+	for _, buf := range bufs {
+		if buf.IsNull(i) {
+			return true
+		}
+	}
+	return false
+}
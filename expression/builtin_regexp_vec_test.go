@@ -0,0 +1,67 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expression
+
+import (
+	"testing"
+
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+)
+
+var (
+	regexpExprGener      = newSelectStringGener([]string{"abc", "ABCabc", "abc def abc", "你好abc", ""})
+	regexpPatternGener   = newSelectStringGener([]string{"abc", "^a", "c$", "[a-z]+", "b*", "你."})
+	regexpMatchTypeGener = newSelectStringGener([]string{"", "c", "i", "ci", "mn"})
+)
+
+var vecBuiltinRegexpCases = map[string][]vecExprBenchCase{
+	ast.RegexpLike: {
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString},
+			geners: []dataGenerator{regexpExprGener, regexpPatternGener}},
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETString},
+			geners: []dataGenerator{regexpExprGener, regexpPatternGener, regexpMatchTypeGener}},
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETString},
+			geners:    []dataGenerator{regexpExprGener},
+			constants: []*Constant{nil, {Value: types.NewStringDatum("^ab"), RetType: types.NewFieldType(mysql.TypeString)}, {Value: types.NewStringDatum("i"), RetType: types.NewFieldType(mysql.TypeString)}}},
+	},
+	ast.RegexpInStr: {
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString},
+			geners: []dataGenerator{regexpExprGener, regexpPatternGener}},
+		{retEvalType: types.ETInt, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETInt, types.ETInt, types.ETInt, types.ETString},
+			geners: []dataGenerator{regexpExprGener, regexpPatternGener, newRangeInt64Gener(1, 2), newRangeInt64Gener(-1, 3), newRangeInt64Gener(0, 2), regexpMatchTypeGener}},
+	},
+	ast.RegexpSubstr: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETString},
+			geners: []dataGenerator{regexpExprGener, regexpPatternGener}},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETInt, types.ETInt, types.ETString},
+			geners: []dataGenerator{regexpExprGener, regexpPatternGener, newRangeInt64Gener(1, 2), newRangeInt64Gener(-1, 3), regexpMatchTypeGener}},
+	},
+	ast.RegexpReplace: {
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETString},
+			geners: []dataGenerator{regexpExprGener, regexpPatternGener, newSelectStringGener([]string{"", "x", "$0$0"})}},
+		{retEvalType: types.ETString, childrenTypes: []types.EvalType{types.ETString, types.ETString, types.ETString, types.ETInt, types.ETInt, types.ETString},
+			geners: []dataGenerator{regexpExprGener, regexpPatternGener, newSelectStringGener([]string{"", "x", "$0$0"}), newRangeInt64Gener(1, 2), newRangeInt64Gener(-1, 3), regexpMatchTypeGener}},
+	},
+}
+
+func TestVectorizedBuiltinRegexpFunc(t *testing.T) {
+	testVectorizedBuiltinFunc(t, vecBuiltinRegexpCases)
+}
+
+func BenchmarkVectorizedBuiltinRegexpFunc(b *testing.B) {
+	benchmarkVectorizedBuiltinFunc(b, vecBuiltinRegexpCases)
+}
@@ -915,6 +915,30 @@ func TestExprPushDownToFlash(t *testing.T) {
 	require.NoError(t, err)
 	exprs = append(exprs, function)
 
+	// RegexpLikeUTF8Sig
+	function, err = NewFunction(mock.NewContext(), ast.RegexpLike, types.NewFieldType(mysql.TypeLonglong), stringColumn, stringColumn, stringColumn)
+	require.NoError(t, err)
+	require.Equal(t, tipb.ScalarFuncSig_RegexpLikeUTF8Sig, function.(*ScalarFunction).Function.PbCode())
+	exprs = append(exprs, function)
+
+	// RegexpInStrUTF8Sig
+	function, err = NewFunction(mock.NewContext(), ast.RegexpInStr, types.NewFieldType(mysql.TypeLonglong), stringColumn, stringColumn, intColumn)
+	require.NoError(t, err)
+	require.Equal(t, tipb.ScalarFuncSig_RegexpInStrUTF8Sig, function.(*ScalarFunction).Function.PbCode())
+	exprs = append(exprs, function)
+
+	// RegexpSubstrUTF8Sig
+	function, err = NewFunction(mock.NewContext(), ast.RegexpSubstr, types.NewFieldType(mysql.TypeString), stringColumn, stringColumn)
+	require.NoError(t, err)
+	require.Equal(t, tipb.ScalarFuncSig_RegexpSubstrUTF8Sig, function.(*ScalarFunction).Function.PbCode())
+	exprs = append(exprs, function)
+
+	// RegexpReplaceUTF8Sig
+	function, err = NewFunction(mock.NewContext(), ast.RegexpReplace, types.NewFieldType(mysql.TypeString), stringColumn, stringColumn, stringColumn)
+	require.NoError(t, err)
+	require.Equal(t, tipb.ScalarFuncSig_RegexpReplaceUTF8Sig, function.(*ScalarFunction).Function.PbCode())
+	exprs = append(exprs, function)
+
 	canPush := CanExprsPushDown(sc, exprs, client, kv.TiFlash)
 	require.Equal(t, true, canPush)
 
@@ -966,6 +990,18 @@ func TestExprPushDownToFlash(t *testing.T) {
 	require.NoError(t, err)
 	exprs = append(exprs, function)
 
+	// RegexpLikeSig: can not be pushed
+	function, err = NewFunction(mock.NewContext(), ast.RegexpLike, types.NewFieldType(mysql.TypeLonglong), binaryStringColumn, binaryStringColumn)
+	require.NoError(t, err)
+	require.Equal(t, tipb.ScalarFuncSig_RegexpLikeSig, function.(*ScalarFunction).Function.PbCode())
+	exprs = append(exprs, function)
+
+	// RegexpReplaceSig: can not be pushed
+	function, err = NewFunction(mock.NewContext(), ast.RegexpReplace, types.NewFieldType(mysql.TypeString), binaryStringColumn, binaryStringColumn, binaryStringColumn)
+	require.NoError(t, err)
+	require.Equal(t, tipb.ScalarFuncSig_RegexpReplaceSig, function.(*ScalarFunction).Function.PbCode())
+	exprs = append(exprs, function)
+
 	pushed, remained := PushDownExprs(sc, exprs, client, kv.TiFlash)
 	require.Len(t, pushed, 0)
 	require.Len(t, remained, len(exprs))
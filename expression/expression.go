@@ -1119,6 +1119,13 @@ func scalarExprSupportedByFlash(function *ScalarFunction) bool {
 		case tipb.ScalarFuncSig_Replace:
 			return true
 		}
+	case ast.RegexpLike, ast.RegexpInStr, ast.RegexpSubstr, ast.RegexpReplace:
+		// The regexp functions of the binary collation are not supported by TiFlash yet.
+		switch function.Function.PbCode() {
+		case tipb.ScalarFuncSig_RegexpLikeUTF8Sig, tipb.ScalarFuncSig_RegexpInStrUTF8Sig,
+			tipb.ScalarFuncSig_RegexpSubstrUTF8Sig, tipb.ScalarFuncSig_RegexpReplaceUTF8Sig:
+			return true
+		}
 	case ast.StrToDate:
 		switch function.Function.PbCode() {
 		case
@@ -30,6 +30,8 @@ var UnCacheableFunctions = map[string]struct{}{
 	ast.RowCount:     {},
 	ast.Version:      {},
 	ast.Like:         {},
+
+	ast.QueryAttributeString: {},
 }
 
 // unFoldableFunctions stores functions which can not be folded duration constant folding stage.
@@ -49,6 +51,8 @@ var unFoldableFunctions = map[string]struct{}{
 	ast.NextVal:   {},
 	ast.LastVal:   {},
 	ast.SetVal:    {},
+
+	ast.QueryAttributeString: {},
 }
 
 // DisableFoldFunctions stores functions which prevent child scope functions from being constant folded.
@@ -117,6 +121,8 @@ var IllegalFunctions4GeneratedColumns = map[string]struct{}{
 	ast.SetVar:           {},
 	ast.GetVar:           {},
 	ast.ReleaseAllLocks:  {},
+
+	ast.QueryAttributeString: {},
 }
 
 // DeferredFunctions stores functions which are foldable but should be deferred as well when plan cache is enabled.
@@ -253,6 +259,7 @@ var booleanFunctions = map[string]struct{}{
 	ast.IsNull:             {},
 	ast.Like:               {},
 	ast.Regexp:             {},
+	ast.RegexpLike:         {},
 	ast.IsIPv4:             {},
 	ast.IsIPv4Compat:       {},
 	ast.IsIPv4Mapped:       {},
@@ -5222,7 +5222,10 @@ func (s *testIntegrationSuite) TestForeignKeyVar(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 
 	tk.MustExec("SET FOREIGN_KEY_CHECKS=1")
-	tk.MustQuery("SHOW WARNINGS").Check(testkit.Rows("Warning 8047 variable 'foreign_key_checks' does not yet support value: 1"))
+	tk.MustQuery("SHOW WARNINGS").Check(testkit.Rows())
+	tk.MustQuery("SELECT @@foreign_key_checks").Check(testkit.Rows("1"))
+	tk.MustExec("SET FOREIGN_KEY_CHECKS=0")
+	tk.MustQuery("SELECT @@foreign_key_checks").Check(testkit.Rows("0"))
 }
 
 func (s *testIntegrationSuite) TestUserVarMockWindFunc(c *C) {
@@ -6318,6 +6321,33 @@ func (s *testIntegrationSerialSuite) TestCacheRegexpr(c *C) {
 	tk.MustQuery("execute stmt1 using @a").Check(testkit.Rows("R1"))
 }
 
+func (s *testIntegrationSerialSuite) TestRegexpFunctions(c *C) {
+	collate.SetNewCollationEnabledForTest(true)
+	defer collate.SetNewCollationEnabledForTest(false)
+	tk := testkit.NewTestKit(c, s.store)
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t (id int, a varchar(32) collate utf8mb4_general_ci, b varchar(32) collate utf8mb4_bin, c varbinary(32))")
+	tk.MustExec("insert into t values (1, 'Abc aBC', 'Abc aBC', 'Abc aBC'), (2, '你好世界', '你好世界', '你好世界'), (3, null, null, null)")
+	for _, vec := range []string{"on", "off"} {
+		tk.MustExec("set @@tidb_enable_vectorized_expression = " + vec)
+		tk.MustQuery("select id, regexp_like(a, 'abc'), regexp_like(b, 'abc'), regexp_like(b, 'abc', 'i'), regexp_like(a, 'abc', 'c') from t order by id").
+			Check(testkit.Rows("1 1 0 1 0", "2 0 0 0 0", "3 <nil> <nil> <nil> <nil>"))
+		tk.MustQuery("select id, regexp_instr(a, 'abc', 1, 2), regexp_instr(b, 'abc', 1, 2), regexp_instr(b, '世'), regexp_instr(c, '世') from t order by id").
+			Check(testkit.Rows("1 5 0 0 0", "2 0 0 3 7", "3 <nil> <nil> <nil> <nil>"))
+		tk.MustQuery("select id, regexp_substr(a, 'abc', 2), regexp_substr(b, '[a-z]+', 1, 2), regexp_substr(b, '.', 3) from t order by id").
+			Check(testkit.Rows("1 aBC a c", "2 <nil> <nil> 世", "3 <nil> <nil> <nil>"))
+		tk.MustQuery("select id, regexp_replace(a, 'abc', 'x'), regexp_replace(b, 'abc', 'x'), regexp_replace(b, '(\\\\w+) (\\\\w+)', '$2 $1') from t order by id").
+			Check(testkit.Rows("1 x x Abc aBC aBC Abc", "2 你好世界 你好世界 你好世界", "3 <nil> <nil> <nil>"))
+	}
+	err := tk.QueryToErr("select regexp_like(b, 'a', 'x') from t")
+	c.Assert(err.Error(), Equals, "[expression:1139]Got error 'Invalid match type' from regexp")
+	err = tk.QueryToErr("select regexp_instr(b, 'a', 100) from t")
+	c.Assert(err.Error(), Equals, "[expression:1139]Got error 'Index out of bounds in regular expression search' from regexp")
+	err = tk.QueryToErr("select regexp_replace(b, '(', 'x') from t")
+	c.Assert(terror.ErrorEqual(err, expression.ErrRegexp), IsTrue)
+}
+
 func (s *testIntegrationSerialSuite) TestCacheRefineArgs(c *C) {
 	tk := testkit.NewTestKit(c, s.store)
 	orgEnable := plannercore.PreparedPlanCacheEnabled()
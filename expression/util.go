@@ -121,6 +121,27 @@ func ExtractColumns(expr Expression) []*Column {
 	return extractColumns(result, expr, nil)
 }
 
+// SetCtxForExprs sets the session context of the expressions cloned from the ones built by another session, e.g. the
+// expressions of the plan shared by the instance plan cache. The parameter markers are bound to the context, too.
+func SetCtxForExprs(ctx sessionctx.Context, exprs ...Expression) {
+	for _, expr := range exprs {
+		switch v := expr.(type) {
+		case *ScalarFunction:
+			v.Function.setCtx(ctx)
+			SetCtxForExprs(ctx, v.GetArgs()...)
+		case *Constant:
+			if v.ParamMarker != nil {
+				v.ParamMarker = &ParamMarker{ctx: ctx, order: v.ParamMarker.order}
+			}
+			if v.DeferredExpr != nil {
+				// The deferred expression is shared by the cloned constants.
+				v.DeferredExpr = v.DeferredExpr.Clone()
+				SetCtxForExprs(ctx, v.DeferredExpr)
+			}
+		}
+	}
+}
+
 // ExtractCorColumns extracts correlated column from given expression.
 func ExtractCorColumns(expr Expression) (cols []*CorrelatedColumn) {
 	switch v := expr.(type) {
@@ -75,7 +75,8 @@ func (b *Builder) ApplyDiff(m *meta.Meta, diff *model.SchemaDiff) ([]int64, erro
 		newTableID = diff.TableID
 	case model.ActionDropTable, model.ActionDropView, model.ActionDropSequence:
 		oldTableID = diff.TableID
-	case model.ActionTruncateTable, model.ActionCreateView, model.ActionExchangeTablePartition:
+	case model.ActionTruncateTable, model.ActionCreateView, model.ActionExchangeTablePartition,
+		model.ActionRemovePartitioning:
 		oldTableID = diff.OldTableID
 		newTableID = diff.TableID
 	default:
@@ -103,6 +104,13 @@ func (b *Builder) ApplyDiff(m *meta.Meta, diff *model.SchemaDiff) ([]int64, erro
 		if err := b.applyPlacementUpdate(placement.GroupID(newTableID)); err != nil {
 			return nil, errors.Trace(err)
 		}
+	case model.ActionRemovePartitioning:
+		if oldTableID != newTableID {
+			b.applyPlacementDelete(placement.GroupID(oldTableID))
+			if err := b.applyPlacementUpdate(placement.GroupID(newTableID)); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
 	}
 	dbInfo := b.copySchemaTables(roDBInfo.Name.L)
 	b.copySortedTables(oldTableID, newTableID)
@@ -169,7 +177,8 @@ func (b *Builder) ApplyDiff(m *meta.Meta, diff *model.SchemaDiff) ([]int64, erro
 					return nil, errors.Trace(err)
 				}
 				continue
-			case model.ActionDropTable, model.ActionDropTablePartition:
+			case model.ActionDropTable, model.ActionDropTablePartition, model.ActionReorganizePartition,
+				model.ActionAlterTablePartitioning, model.ActionRemovePartitioning:
 				b.applyPlacementDelete(placement.GroupID(opt.OldTableID))
 				continue
 			case model.ActionTruncateTable:
@@ -459,6 +468,7 @@ func (b *Builder) applyCreateTable(m *meta.Meta, dbInfo *model.DBInfo, tableID i
 	sortedTbls = append(sortedTbls, tbl)
 	sort.Sort(sortedTbls)
 	b.is.sortedTablesBuckets[bucketIdx] = sortedTbls
+	b.is.addReferringFKs(dbInfo.Name, tbl)
 
 	newTbl, ok := b.is.TableByID(tableID)
 	if ok {
@@ -510,6 +520,7 @@ func (b *Builder) applyDropTable(dbInfo *model.DBInfo, tableID int64, affected [
 		delete(tableNames.tables, tblInfo.Name.L)
 		affected = appendAffectedIDs(affected, tblInfo)
 	}
+	b.is.removeReferringFKs(dbInfo.Name, sortedTbls[idx].Meta())
 	// Remove the table in sorted table slice.
 	b.is.sortedTablesBuckets[bucketIdx] = append(sortedTbls[0:idx], sortedTbls[idx+1:]...)
 
@@ -559,6 +570,9 @@ func (b *Builder) InitWithOldInfoSchema(oldSchema InfoSchema) *Builder {
 	b.copyPoliciesMap(oldIS)
 
 	copy(b.is.sortedTablesBuckets, oldIS.sortedTablesBuckets)
+	for k, v := range oldIS.referringFKMap {
+		b.is.referringFKMap[k] = v
+	}
 	return b
 }
 
@@ -651,6 +665,7 @@ func (b *Builder) createSchemaTablesForDB(di *model.DBInfo, tableFromMeta tableF
 		schTbls.tables[t.Name.L] = tbl
 		sortedTbls := b.is.sortedTablesBuckets[tableBucketIdx(t.ID)]
 		b.is.sortedTablesBuckets[tableBucketIdx(t.ID)] = append(sortedTbls, tbl)
+		b.is.addReferringFKs(di.Name, tbl)
 	}
 	return nil
 }
@@ -676,6 +691,7 @@ func NewBuilder(store kv.Storage) *Builder {
 			policyMap:           map[string]*model.PolicyInfo{},
 			ruleBundleMap:       map[string]*placement.Bundle{},
 			sortedTablesBuckets: make([]sortedTables, bucketCount),
+			referringFKMap:      map[fkParentKey][]*ReferringForeignKey{},
 		},
 	}
 }
@@ -59,6 +59,21 @@ type InfoSchema interface {
 	RuleBundles() []*placement.Bundle
 	// AllPlacementPolicies returns all placement policies
 	AllPlacementPolicies() []*model.PolicyInfo
+	// ReferringForeignKeys returns the foreign keys referring to the table.
+	ReferringForeignKeys(schema, table model.CIStr) []*ReferringForeignKey
+}
+
+// ReferringForeignKey is a foreign key of the child table referring to a parent table.
+type ReferringForeignKey struct {
+	ChildSchema model.CIStr
+	ChildTable  table.Table
+	FK          *model.FKInfo
+}
+
+// fkParentKey identifies the parent table of foreign keys by the lower-case schema name and table name.
+type fkParentKey struct {
+	schema string
+	table  string
 }
 
 type sortedTables []table.Table
@@ -106,6 +121,9 @@ type infoSchema struct {
 	// sortedTablesBuckets is a slice of sortedTables, a table's bucket index is (tableID % bucketCount).
 	sortedTablesBuckets []sortedTables
 
+	// referringFKMap indexes the foreign keys by their parent tables.
+	referringFKMap map[fkParentKey][]*ReferringForeignKey
+
 	// schemaMetaVersion is the version of schema, and we should check version when change schema.
 	schemaMetaVersion int64
 }
@@ -117,6 +135,7 @@ func MockInfoSchema(tbList []*model.TableInfo) InfoSchema {
 	result.policyMap = make(map[string]*model.PolicyInfo)
 	result.ruleBundleMap = make(map[string]*placement.Bundle)
 	result.sortedTablesBuckets = make([]sortedTables, bucketCount)
+	result.referringFKMap = make(map[fkParentKey][]*ReferringForeignKey)
 	dbInfo := &model.DBInfo{ID: 0, Name: model.NewCIStr("test"), Tables: tbList}
 	tableNames := &schemaTables{
 		dbInfo: dbInfo,
@@ -128,6 +147,7 @@ func MockInfoSchema(tbList []*model.TableInfo) InfoSchema {
 		tableNames.tables[tb.Name.L] = tbl
 		bucketIdx := tableBucketIdx(tb.ID)
 		result.sortedTablesBuckets[bucketIdx] = append(result.sortedTablesBuckets[bucketIdx], tbl)
+		result.addReferringFKs(dbInfo.Name, tbl)
 	}
 	for i := range result.sortedTablesBuckets {
 		sort.Sort(result.sortedTablesBuckets[i])
@@ -142,6 +162,7 @@ func MockInfoSchemaWithSchemaVer(tbList []*model.TableInfo, schemaVer int64) Inf
 	result.policyMap = make(map[string]*model.PolicyInfo)
 	result.ruleBundleMap = make(map[string]*placement.Bundle)
 	result.sortedTablesBuckets = make([]sortedTables, bucketCount)
+	result.referringFKMap = make(map[fkParentKey][]*ReferringForeignKey)
 	dbInfo := &model.DBInfo{ID: 0, Name: model.NewCIStr("test"), Tables: tbList}
 	tableNames := &schemaTables{
 		dbInfo: dbInfo,
@@ -153,6 +174,7 @@ func MockInfoSchemaWithSchemaVer(tbList []*model.TableInfo, schemaVer int64) Inf
 		tableNames.tables[tb.Name.L] = tbl
 		bucketIdx := tableBucketIdx(tb.ID)
 		result.sortedTablesBuckets[bucketIdx] = append(result.sortedTablesBuckets[bucketIdx], tbl)
+		result.addReferringFKs(dbInfo.Name, tbl)
 	}
 	for i := range result.sortedTablesBuckets {
 		sort.Sort(result.sortedTablesBuckets[i])
@@ -376,6 +398,53 @@ func (is *infoSchema) PolicyByName(name model.CIStr) (*model.PolicyInfo, bool) {
 	return t, r
 }
 
+// ReferringForeignKeys implements InfoSchema.ReferringForeignKeys interface.
+func (is *infoSchema) ReferringForeignKeys(schema, table model.CIStr) []*ReferringForeignKey {
+	return is.referringFKMap[fkParentKey{schema: schema.L, table: table.L}]
+}
+
+// addReferringFKs indexes the foreign keys of the child table. The slices in the map are shared with
+// the old InfoSchema, so they are copied instead of being modified in place.
+func (is *infoSchema) addReferringFKs(childSchema model.CIStr, childTable table.Table) {
+	for _, fk := range childTable.Meta().ForeignKeys {
+		refSchema := fk.RefSchema
+		if refSchema.L == "" {
+			refSchema = childSchema
+		}
+		key := fkParentKey{schema: refSchema.L, table: fk.RefTable.L}
+		old := is.referringFKMap[key]
+		fks := make([]*ReferringForeignKey, 0, len(old)+1)
+		fks = append(fks, old...)
+		is.referringFKMap[key] = append(fks, &ReferringForeignKey{ChildSchema: childSchema, ChildTable: childTable, FK: fk})
+	}
+}
+
+// removeReferringFKs removes the foreign keys of the child table from the index.
+func (is *infoSchema) removeReferringFKs(childSchema model.CIStr, childTblInfo *model.TableInfo) {
+	for _, fk := range childTblInfo.ForeignKeys {
+		refSchema := fk.RefSchema
+		if refSchema.L == "" {
+			refSchema = childSchema
+		}
+		key := fkParentKey{schema: refSchema.L, table: fk.RefTable.L}
+		old, ok := is.referringFKMap[key]
+		if !ok {
+			continue
+		}
+		fks := make([]*ReferringForeignKey, 0, len(old))
+		for _, ref := range old {
+			if ref.ChildTable.Meta().ID != childTblInfo.ID {
+				fks = append(fks, ref)
+			}
+		}
+		if len(fks) == 0 {
+			delete(is.referringFKMap, key)
+		} else {
+			is.referringFKMap[key] = fks
+		}
+	}
+}
+
 // AllPlacementPolicies returns all placement policies
 func (is *infoSchema) AllPlacementPolicies() []*model.PolicyInfo {
 	is.policyMutex.RLock()
@@ -535,6 +604,18 @@ func (is *LocalTemporaryTables) RemoveTable(schema, table model.CIStr) (exist bo
 	return true
 }
 
+// ForEachTable calls the function on each table with the schema it belongs to, it stops at the first error.
+func (is *LocalTemporaryTables) ForEachTable(fn func(db *model.DBInfo, tbl table.Table) error) error {
+	for _, tbls := range is.schemaMap {
+		for _, tbl := range tbls.tables {
+			if err := fn(tbls.dbInfo, tbl); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // SchemaByTable get a table's schema name
 func (is *LocalTemporaryTables) SchemaByTable(tableInfo *model.TableInfo) (*model.DBInfo, bool) {
 	if tableInfo == nil {
@@ -16,6 +16,7 @@ package infoschema_test
 
 import (
 	"context"
+	"sort"
 	"strings"
 	"testing"
 
@@ -32,6 +33,7 @@ import (
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/session"
 	"github.com/pingcap/tidb/store/mockstore"
+	"github.com/pingcap/tidb/testkit"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/testutil"
@@ -236,6 +238,39 @@ func TestMockInfoSchema(t *testing.T) {
 	require.Equal(t, colInfo, tbl.Cols()[0].ColumnInfo)
 }
 
+func TestReferringForeignKeys(t *testing.T) {
+	t.Parallel()
+
+	store, dom, clean := testkit.CreateMockStoreAndDomain(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("create database fk_db1")
+	tk.MustExec("create database fk_db2")
+	tk.MustExec("create table fk_db1.t1 (id int primary key)")
+	tk.MustExec("create table fk_db1.t2 (id int primary key, pid int, constraint fk1 foreign key (pid) references t1(id))")
+	tk.MustExec("create table fk_db2.t3 (id int primary key, pid int, constraint fk2 foreign key (pid) references fk_db1.t1(id))")
+
+	getFKNames := func() []string {
+		var names []string
+		for _, ref := range dom.InfoSchema().ReferringForeignKeys(model.NewCIStr("fk_db1"), model.NewCIStr("T1")) {
+			names = append(names, ref.ChildSchema.L+"."+ref.ChildTable.Meta().Name.L+"."+ref.FK.Name.L)
+		}
+		sort.Strings(names)
+		return names
+	}
+	require.Equal(t, []string{"fk_db1.t2.fk1", "fk_db2.t3.fk2"}, getFKNames())
+	require.Empty(t, dom.InfoSchema().ReferringForeignKeys(model.NewCIStr("fk_db1"), model.NewCIStr("t2")))
+
+	// The index is updated with the child tables.
+	tk.MustExec("alter table fk_db1.t2 add column c int")
+	require.Equal(t, []string{"fk_db1.t2.fk1", "fk_db2.t3.fk2"}, getFKNames())
+	tk.MustExec("drop table fk_db1.t2")
+	require.Equal(t, []string{"fk_db2.t3.fk2"}, getFKNames())
+	tk.MustExec("drop database fk_db2")
+	require.Empty(t, getFKNames())
+}
+
 func checkApplyCreateNonExistsSchemaDoesNotPanic(t *testing.T, txn kv.Transaction, builder *infoschema.Builder) {
 	m := meta.NewMeta(txn)
 	_, err := builder.ApplyDiff(m, &model.SchemaDiff{Type: model.ActionCreateSchema, SchemaID: 999})
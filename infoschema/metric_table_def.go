@@ -144,6 +144,11 @@ var MetricTableMap = map[string]MetricTableDef{
 		Labels:  []string{"instance"},
 		Comment: "TiDB prepare statements count",
 	},
+	"tidb_cluster_tls_cert_expire_time": {
+		PromQL:  "tidb_server_cluster_tls_cert_expire_time_seconds{$LABEL_CONDITIONS}",
+		Labels:  []string{"instance", "type"},
+		Comment: "The expiration time(unix timestamp) of the cluster TLS certificates of TiDB",
+	},
 	"tidb_time_jump_back_ops": {
 		PromQL:  "sum(increase(tidb_monitor_time_jump_back_total{$LABEL_CONDITIONS}[$RANGE_DURATION])) by (instance)",
 		Labels:  []string{"instance"},
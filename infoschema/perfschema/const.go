@@ -46,6 +46,8 @@ var perfSchemaTables = []string{
 	tablePDProfileAllocs,
 	tablePDProfileBlock,
 	tablePDProfileGoroutines,
+	tableSessionConnectAttrs,
+	tableSessionAccountConnectAttrs,
 }
 
 // tableGlobalStatus contains the column name definitions for table global_status, same as MySQL.
@@ -544,3 +546,17 @@ const tablePDProfileGoroutines = "CREATE TABLE IF NOT EXISTS " + tableNamePDProf
 	"ID INT(8) NOT NULL," +
 	"STATE VARCHAR(16) NOT NULL," +
 	"LOCATION VARCHAR(512) NOT NULL);"
+
+// tableSessionConnectAttrs contains the column name definitions for table session_connect_attrs, same as MySQL.
+const tableSessionConnectAttrs = "CREATE TABLE IF NOT EXISTS performance_schema." + tableNameSessionConnectAttrs + " (" +
+	"PROCESSLIST_ID BIGINT(20) UNSIGNED NOT NULL," +
+	"ATTR_NAME VARCHAR(32) NOT NULL," +
+	"ATTR_VALUE VARCHAR(1024)," +
+	"ORDINAL_POSITION INT(11));"
+
+// tableSessionAccountConnectAttrs contains the column name definitions for table session_account_connect_attrs, same as MySQL.
+const tableSessionAccountConnectAttrs = "CREATE TABLE IF NOT EXISTS performance_schema." + tableNameSessionAccountConnectAttrs + " (" +
+	"PROCESSLIST_ID BIGINT(20) UNSIGNED NOT NULL," +
+	"ATTR_NAME VARCHAR(32) NOT NULL," +
+	"ATTR_VALUE VARCHAR(1024)," +
+	"ORDINAL_POSITION INT(11));"
@@ -68,6 +68,8 @@ const (
 	tableNamePDProfileAllocs                 = "pd_profile_allocs"
 	tableNamePDProfileBlock                  = "pd_profile_block"
 	tableNamePDProfileGoroutines             = "pd_profile_goroutines"
+	tableNameSessionConnectAttrs             = "session_connect_attrs"
+	tableNameSessionAccountConnectAttrs      = "session_account_connect_attrs"
 )
 
 var tableIDMap = map[string]int64{
@@ -101,6 +103,8 @@ var tableIDMap = map[string]int64{
 	tableNamePDProfileAllocs:                 autoid.PerformanceSchemaDBID + 28,
 	tableNamePDProfileBlock:                  autoid.PerformanceSchemaDBID + 29,
 	tableNamePDProfileGoroutines:             autoid.PerformanceSchemaDBID + 30,
+	tableNameSessionConnectAttrs:             autoid.PerformanceSchemaDBID + 31,
+	tableNameSessionAccountConnectAttrs:      autoid.PerformanceSchemaDBID + 32,
 }
 
 // perfSchemaTable stands for the fake table all its data is in the memory.
@@ -247,6 +251,10 @@ func (vt *perfSchemaTable) getRows(ctx sessionctx.Context, cols []*table.Column)
 		fullRows, err = dataForRemoteProfile(ctx, "pd", "/pd/api/v1/debug/pprof/block", false)
 	case tableNamePDProfileGoroutines:
 		fullRows, err = dataForRemoteProfile(ctx, "pd", "/pd/api/v1/debug/pprof/goroutine?debug=2", true)
+	case tableNameSessionConnectAttrs:
+		fullRows = infoschema.GetSessionConnectAttrsRows(ctx, false)
+	case tableNameSessionAccountConnectAttrs:
+		fullRows = infoschema.GetSessionConnectAttrsRows(ctx, true)
 	}
 	if err != nil {
 		return
@@ -38,6 +38,7 @@ import (
 	"github.com/pingcap/tidb/domain/infosync"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/meta/autoid"
+	"github.com/pingcap/tidb/privilege"
 	"github.com/pingcap/tidb/session/txninfo"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/variable"
@@ -178,6 +179,16 @@ const (
 	TableAttributes = "ATTRIBUTES"
 	// TablePlacementRules is the string constant of placement rules table.
 	TablePlacementRules = "PLACEMENT_RULES"
+	// TableCheckConstraints is the string constant of CHECK_CONSTRAINTS.
+	TableCheckConstraints = "CHECK_CONSTRAINTS"
+	// TableSessionConnectAttrs is the string constant of SESSION_CONNECT_ATTRS.
+	TableSessionConnectAttrs = "SESSION_CONNECT_ATTRS"
+	// TableUserResourceUsage is the string constant of USER_RESOURCE_USAGE.
+	TableUserResourceUsage = "USER_RESOURCE_USAGE"
+	// TableMemoryKillHistory is the string constant of MEMORY_KILL_HISTORY.
+	TableMemoryKillHistory = "MEMORY_KILL_HISTORY"
+	// TableInstancePlanCache is the string constant of INSTANCE_PLAN_CACHE.
+	TableInstancePlanCache = "INSTANCE_PLAN_CACHE"
 )
 
 const (
@@ -276,6 +287,11 @@ var tableIDMap = map[string]int64{
 	TableAttributes:                      autoid.InformationSchemaDBID + 77,
 	TableTiDBHotRegionsHistory:           autoid.InformationSchemaDBID + 78,
 	TablePlacementRules:                  autoid.InformationSchemaDBID + 79,
+	TableCheckConstraints:                autoid.InformationSchemaDBID + 80,
+	TableSessionConnectAttrs:             autoid.InformationSchemaDBID + 81,
+	TableUserResourceUsage:               autoid.InformationSchemaDBID + 82,
+	TableMemoryKillHistory:               autoid.InformationSchemaDBID + 83,
+	TableInstancePlanCache:               autoid.InformationSchemaDBID + 84,
 }
 
 type columnInfo struct {
@@ -572,6 +588,59 @@ var tableConstraintsCols = []columnInfo{
 	{name: "CONSTRAINT_TYPE", tp: mysql.TypeVarchar, size: 64},
 }
 
+var tableCheckConstraintsCols = []columnInfo{
+	{name: "CONSTRAINT_CATALOG", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag},
+	{name: "CONSTRAINT_SCHEMA", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag},
+	{name: "CONSTRAINT_NAME", tp: mysql.TypeVarchar, size: 64, flag: mysql.NotNullFlag},
+	{name: "CHECK_CLAUSE", tp: mysql.TypeLongBlob, size: types.UnspecifiedLength, flag: mysql.NotNullFlag},
+}
+
+var tableSessionConnectAttrsCols = []columnInfo{
+	{name: "PROCESSLIST_ID", tp: mysql.TypeLonglong, size: 21, flag: mysql.NotNullFlag | mysql.UnsignedFlag},
+	{name: "ATTR_NAME", tp: mysql.TypeVarchar, size: 32, flag: mysql.NotNullFlag},
+	{name: "ATTR_VALUE", tp: mysql.TypeVarchar, size: 1024},
+	{name: "ORDINAL_POSITION", tp: mysql.TypeLong, size: 11},
+}
+
+var tableUserResourceUsageCols = []columnInfo{
+	{name: "USER", tp: mysql.TypeVarchar, size: 32, flag: mysql.NotNullFlag},
+	{name: "HOST", tp: mysql.TypeVarchar, size: 255, flag: mysql.NotNullFlag},
+	{name: "MAX_QUESTIONS", tp: mysql.TypeLonglong, size: 21, flag: mysql.NotNullFlag | mysql.UnsignedFlag},
+	{name: "QUESTIONS", tp: mysql.TypeLonglong, size: 21, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "Statements in the current hour"},
+	{name: "MAX_UPDATES", tp: mysql.TypeLonglong, size: 21, flag: mysql.NotNullFlag | mysql.UnsignedFlag},
+	{name: "UPDATES", tp: mysql.TypeLonglong, size: 21, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "Statements modifying data in the current hour"},
+	{name: "MAX_CONNECTIONS_PER_HOUR", tp: mysql.TypeLonglong, size: 21, flag: mysql.NotNullFlag | mysql.UnsignedFlag},
+	{name: "CONNECTIONS", tp: mysql.TypeLonglong, size: 21, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "Connections in the current hour"},
+	{name: "MAX_USER_CONNECTIONS", tp: mysql.TypeLonglong, size: 21, flag: mysql.NotNullFlag | mysql.UnsignedFlag},
+	{name: "USER_CONNECTIONS", tp: mysql.TypeLonglong, size: 21, flag: mysql.NotNullFlag | mysql.UnsignedFlag, comment: "Current connections of all the TiDB instances"},
+}
+
+var tableMemoryKillHistoryCols = []columnInfo{
+	{name: "TIME", tp: mysql.TypeDatetime, size: 26, decimal: 6},
+	{name: "QUOTA", tp: mysql.TypeVarchar, size: 16, comment: "The exceeded memory quota: server, session or user"},
+	{name: "QUOTA_BYTES", tp: mysql.TypeLonglong, size: 21},
+	{name: "USED_BYTES", tp: mysql.TypeLonglong, size: 21, comment: "Memory used by the instance, the session or the account"},
+	{name: "STMT_BYTES", tp: mysql.TypeLonglong, size: 21, comment: "Memory used by the killed statement"},
+	{name: "CONN_ID", tp: mysql.TypeLonglong, size: 21, flag: mysql.UnsignedFlag},
+	{name: "USER", tp: mysql.TypeVarchar, size: 32},
+	{name: "HOST", tp: mysql.TypeVarchar, size: 255},
+	{name: "DB", tp: mysql.TypeVarchar, size: 64},
+	{name: "DIGEST", tp: mysql.TypeVarchar, size: 64},
+	{name: "QUERY", tp: mysql.TypeLongBlob, size: types.UnspecifiedLength},
+}
+
+var tableInstancePlanCacheCols = []columnInfo{
+	{name: "SQL_TEXT", tp: mysql.TypeLongBlob, size: types.UnspecifiedLength, comment: "Normalized SQL text of the cached statement"},
+	{name: "DB", tp: mysql.TypeVarchar, size: 64},
+	{name: "SCHEMA_VERSION", tp: mysql.TypeLonglong, size: 21},
+	{name: "PARAM_TYPES", tp: mysql.TypeVarchar, size: 1024},
+	{name: "PLAN_DIGEST", tp: mysql.TypeVarchar, size: 64},
+	{name: "MEM_USAGE", tp: mysql.TypeLonglong, size: 21, comment: "Estimated memory used by the cached plan"},
+	{name: "HITS", tp: mysql.TypeLonglong, size: 21, flag: mysql.UnsignedFlag},
+	{name: "CREATE_TIME", tp: mysql.TypeDatetime, size: 26, decimal: 6},
+	{name: "LAST_HIT_TIME", tp: mysql.TypeDatetime, size: 26, decimal: 6},
+}
+
 var tableTriggersCols = []columnInfo{
 	{name: "TRIGGER_CATALOG", tp: mysql.TypeVarchar, size: 512},
 	{name: "TRIGGER_SCHEMA", tp: mysql.TypeVarchar, size: 64},
@@ -858,6 +927,7 @@ var slowQueryCols = []columnInfo{
 	{name: variable.SlowLogCopWaitAddr, tp: mysql.TypeVarchar, size: 64},
 	{name: variable.SlowLogMemMax, tp: mysql.TypeLonglong, size: 20},
 	{name: variable.SlowLogDiskMax, tp: mysql.TypeLonglong, size: 20},
+	{name: variable.SlowLogMemQuotaKilled, tp: mysql.TypeVarchar, size: 16},
 	{name: variable.SlowLogKVTotal, tp: mysql.TypeDouble, size: 22},
 	{name: variable.SlowLogPDTotal, tp: mysql.TypeDouble, size: 22},
 	{name: variable.SlowLogBackoffTotal, tp: mysql.TypeDouble, size: 22},
@@ -869,6 +939,8 @@ var slowQueryCols = []columnInfo{
 	{name: variable.SlowLogIsExplicitTxn, tp: mysql.TypeTiny, size: 1},
 	{name: variable.SlowLogPlanFromCache, tp: mysql.TypeTiny, size: 1},
 	{name: variable.SlowLogPlanFromBinding, tp: mysql.TypeTiny, size: 1},
+	{name: variable.SlowLogQueryAttributes, tp: mysql.TypeVarchar, size: 4096},
+	{name: variable.SlowLogConnAttributes, tp: mysql.TypeVarchar, size: 4096},
 	{name: variable.SlowLogPlan, tp: mysql.TypeLongBlob, size: types.UnspecifiedLength},
 	{name: variable.SlowLogPlanDigest, tp: mysql.TypeVarchar, size: 128},
 	{name: variable.SlowLogPrevStmt, tp: mysql.TypeLongBlob, size: types.UnspecifiedLength},
@@ -1270,6 +1342,8 @@ var tableStatementsSummaryCols = []columnInfo{
 	{name: stmtsummary.PlanInBindingStr, tp: mysql.TypeTiny, size: 1, flag: mysql.NotNullFlag, comment: "Whether the last statement is matched with the hints in the binding"},
 	{name: stmtsummary.QuerySampleTextStr, tp: mysql.TypeBlob, size: types.UnspecifiedLength, comment: "Sampled original statement"},
 	{name: stmtsummary.PrevSampleTextStr, tp: mysql.TypeBlob, size: types.UnspecifiedLength, comment: "The previous statement before commit"},
+	{name: stmtsummary.QuerySampleAttrsStr, tp: mysql.TypeBlob, size: types.UnspecifiedLength, comment: "Query attributes of the sampled statement"},
+	{name: stmtsummary.ConnSampleAttrsStr, tp: mysql.TypeBlob, size: types.UnspecifiedLength, comment: "Connection attributes of the sampled statement"},
 	{name: stmtsummary.PlanDigestStr, tp: mysql.TypeVarchar, size: 64, comment: "Digest of its execution plan"},
 	{name: stmtsummary.PlanStr, tp: mysql.TypeBlob, size: types.UnspecifiedLength, comment: "Sampled execution plan"},
 }
@@ -1498,6 +1572,8 @@ const (
 	PrimaryConstraint = "PRIMARY"
 	// UniqueKeyType is the string constant of UNIQUE.
 	UniqueKeyType = "UNIQUE"
+	// CheckConstraintType is the string constant of CHECK.
+	CheckConstraintType = "CHECK"
 )
 
 // ServerInfo represents the basic server information of single cluster component
@@ -1765,6 +1841,31 @@ func GetTiFlashStoreCount(ctx sessionctx.Context) (cnt uint64, err error) {
 	return cnt, nil
 }
 
+// GetSessionConnectAttrsRows returns the connection attributes of the sessions on this instance. The sessions of
+// other accounts, i.e. with another user or host, are visible only with the PROCESS privilege unless sameAccount is set.
+func GetSessionConnectAttrsRows(ctx sessionctx.Context, sameAccount bool) [][]types.Datum {
+	sm := ctx.GetSessionManager()
+	if sm == nil {
+		return nil
+	}
+	loginUser := ctx.GetSessionVars().User
+	allAccounts := !sameAccount
+	if allAccounts {
+		pm := privilege.GetPrivilegeManager(ctx)
+		allAccounts = pm == nil || pm.RequestVerification(ctx.GetSessionVars().ActiveRoles, "", "", "", mysql.ProcessPriv)
+	}
+	var rows [][]types.Datum
+	for _, pi := range sm.ShowProcessList() {
+		if !allAccounts && loginUser != nil && (pi.User != loginUser.Username || pi.Host != loginUser.Hostname) {
+			continue
+		}
+		for _, row := range pi.ToConnectAttrsRows() {
+			rows = append(rows, types.MakeDatums(row...))
+		}
+	}
+	return rows
+}
+
 var tableNameToColumns = map[string][]columnInfo{
 	TableSchemata:                           schemataCols,
 	TableTables:                             tablesCols,
@@ -1836,6 +1937,11 @@ var tableNameToColumns = map[string][]columnInfo{
 	TableDataLockWaits:                      tableDataLockWaitsCols,
 	TableAttributes:                         tableAttributesCols,
 	TablePlacementRules:                     tablePlacementRulesCols,
+	TableCheckConstraints:                   tableCheckConstraintsCols,
+	TableSessionConnectAttrs:                tableSessionConnectAttrsCols,
+	TableUserResourceUsage:                  tableUserResourceUsageCols,
+	TableMemoryKillHistory:                  tableMemoryKillHistoryCols,
+	TableInstancePlanCache:                  tableInstancePlanCacheCols,
 }
 
 func createInfoSchemaTable(_ autoid.Allocators, meta *model.TableInfo) (table.Table, error) {
@@ -323,7 +323,7 @@ func TestSomeTables(t *testing.T) {
 	require.NoError(t, err)
 	tk := testkit.NewTestKit(t, store)
 	tk.SetSession(se)
-	sm := &mockSessionManager{make(map[uint64]*util.ProcessInfo, 2), nil}
+	sm := &mockSessionManager{make(map[uint64]*util.ProcessInfo, 3), nil}
 	sm.processInfoMap[1] = &util.ProcessInfo{
 		ID:      1,
 		User:    "user-1",
@@ -427,6 +427,51 @@ func TestSomeTables(t *testing.T) {
 		))
 }
 
+func TestSessionConnectAttrs(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("create user 'attr'@'localhost'")
+	tk.MustExec("grant select on performance_schema.* to 'attr'@'localhost'")
+	sm := &mockSessionManager{make(map[uint64]*util.ProcessInfo, 2), nil}
+	sm.processInfoMap[1] = &util.ProcessInfo{
+		ID:           1,
+		User:         "root",
+		Host:         "%",
+		ConnectAttrs: map[string]string{"_client_name": "libmysql", "_os": "Linux"},
+	}
+	sm.processInfoMap[2] = &util.ProcessInfo{
+		ID:           2,
+		User:         "attr",
+		Host:         "localhost",
+		ConnectAttrs: map[string]string{"program_name": "app"},
+	}
+	sm.processInfoMap[3] = &util.ProcessInfo{
+		ID:           3,
+		User:         "attr",
+		Host:         "127.0.0.1",
+		ConnectAttrs: map[string]string{"program_name": "cli"},
+	}
+	tk.Session().SetSessionManager(sm)
+	tk.MustQuery("select * from information_schema.session_connect_attrs order by processlist_id, ordinal_position").Check(
+		testkit.Rows("1 _client_name libmysql 1", "1 _os Linux 2", "2 program_name app 1", "3 program_name cli 1"))
+	tk.MustQuery("select * from performance_schema.session_connect_attrs order by processlist_id, ordinal_position").Check(
+		testkit.Rows("1 _client_name libmysql 1", "1 _os Linux 2", "2 program_name app 1", "3 program_name cli 1"))
+
+	// Without the PROCESS privilege, only the sessions of the current account are visible in both tables.
+	tk1 := testkit.NewTestKit(t, store)
+	require.True(t, tk1.Session().Auth(&auth.UserIdentity{Username: "attr", Hostname: "localhost"}, nil, nil))
+	tk1.Session().SetSessionManager(sm)
+	tk1.MustQuery("select * from information_schema.session_connect_attrs").Check(testkit.Rows("2 program_name app 1"))
+	tk1.MustQuery("select * from performance_schema.session_connect_attrs").Check(testkit.Rows("2 program_name app 1"))
+	tk1.MustQuery("select * from performance_schema.session_account_connect_attrs").Check(testkit.Rows("2 program_name app 1"))
+	tk.MustExec("grant process on *.* to 'attr'@'localhost'")
+	tk1.MustQuery("select count(*) from information_schema.session_connect_attrs").Check(testkit.Rows("4"))
+	tk1.MustQuery("select count(*) from performance_schema.session_connect_attrs").Check(testkit.Rows("4"))
+	tk1.MustQuery("select * from performance_schema.session_account_connect_attrs").Check(testkit.Rows("2 program_name app 1"))
+}
+
 func prepareSlowLogfile(t *testing.T, slowLogFileName string) {
 	f, err := os.OpenFile(slowLogFileName, os.O_CREATE|os.O_WRONLY, 0644)
 	require.NoError(t, err)
@@ -556,13 +601,13 @@ func TestSlowQuery(t *testing.T) {
 	tk.MustExec(fmt.Sprintf("set @@tidb_slow_query_file='%v'", slowLogFileName))
 	tk.MustExec("set time_zone = '+08:00';")
 	re := tk.MustQuery("select * from information_schema.slow_query")
-	re.Check(testutil.RowsWithSep("|", "2019-02-12 19:33:56.571953|406315658548871171|root|localhost|6|57|0.12|4.895492|0.4|0.2|0.000000003|2|0.000000002|0.00000001|0.000000003|0.19|0.21|0.01|0|0.18|[txnLock]|0.03|0|15|480|1|8|0.3824278|0.161|0.101|0.092|1.71|1|100001|100000|100|10|10|10|100|test||0|42a1c8aae6f133e934d4bf0147491709a8812ea05ff8819ec522780fe657b772|t1:1,t2:2|0.1|0.2|0.03|127.0.0.1:20160|0.05|0.6|0.8|0.0.0.0:20160|70724|65536|0|0|0|0|10||0|1|0|1|0|abcd|60e9378c746d9a2be1c791047e008967cf252eb6de9167ad3aa6098fa2d523f4|update t set i = 2;|select * from t_slim;",
-		"2021-09-08|14:39:54.506967|427578666238083075|root|172.16.0.0|40507|0|0|25.571605962|0.002923536|0.006800973|0.002100764|0|0|0|0.000015801|25.542014572|0|0.002294647|0.000605473|12.483|[tikvRPC regionMiss tikvRPC regionMiss regionMiss]|0|0|624|172064|60|0|0|0|0|0|0|0|0|0|0|0|0|0|0|rtdb||0|124acb3a0bec903176baca5f9da00b4e7512a41c93b417923f26502edeb324cc||0|0|0||0|0|0||856544|0|86.635049185|0.015486658|100.054|0|0||0|1|0|0|0||||INSERT INTO ...;",
+	re.Check(testutil.RowsWithSep("|", "2019-02-12 19:33:56.571953|406315658548871171|root|localhost|6|57|0.12|4.895492|0.4|0.2|0.000000003|2|0.000000002|0.00000001|0.000000003|0.19|0.21|0.01|0|0.18|[txnLock]|0.03|0|15|480|1|8|0.3824278|0.161|0.101|0.092|1.71|1|100001|100000|100|10|10|10|100|test||0|42a1c8aae6f133e934d4bf0147491709a8812ea05ff8819ec522780fe657b772|t1:1,t2:2|0.1|0.2|0.03|127.0.0.1:20160|0.05|0.6|0.8|0.0.0.0:20160|70724|65536||0|0|0|0|10||0|1|0|1|0|||abcd|60e9378c746d9a2be1c791047e008967cf252eb6de9167ad3aa6098fa2d523f4|update t set i = 2;|select * from t_slim;",
+		"2021-09-08|14:39:54.506967|427578666238083075|root|172.16.0.0|40507|0|0|25.571605962|0.002923536|0.006800973|0.002100764|0|0|0|0.000015801|25.542014572|0|0.002294647|0.000605473|12.483|[tikvRPC regionMiss tikvRPC regionMiss regionMiss]|0|0|624|172064|60|0|0|0|0|0|0|0|0|0|0|0|0|0|0|rtdb||0|124acb3a0bec903176baca5f9da00b4e7512a41c93b417923f26502edeb324cc||0|0|0||0|0|0||856544|0||86.635049185|0.015486658|100.054|0|0||0|1|0|0|0||||||INSERT INTO ...;",
 	))
 	tk.MustExec("set time_zone = '+00:00';")
 	re = tk.MustQuery("select * from information_schema.slow_query")
-	re.Check(testutil.RowsWithSep("|", "2019-02-12 11:33:56.571953|406315658548871171|root|localhost|6|57|0.12|4.895492|0.4|0.2|0.000000003|2|0.000000002|0.00000001|0.000000003|0.19|0.21|0.01|0|0.18|[txnLock]|0.03|0|15|480|1|8|0.3824278|0.161|0.101|0.092|1.71|1|100001|100000|100|10|10|10|100|test||0|42a1c8aae6f133e934d4bf0147491709a8812ea05ff8819ec522780fe657b772|t1:1,t2:2|0.1|0.2|0.03|127.0.0.1:20160|0.05|0.6|0.8|0.0.0.0:20160|70724|65536|0|0|0|0|10||0|1|0|1|0|abcd|60e9378c746d9a2be1c791047e008967cf252eb6de9167ad3aa6098fa2d523f4|update t set i = 2;|select * from t_slim;",
-		"2021-09-08|06:39:54.506967|427578666238083075|root|172.16.0.0|40507|0|0|25.571605962|0.002923536|0.006800973|0.002100764|0|0|0|0.000015801|25.542014572|0|0.002294647|0.000605473|12.483|[tikvRPC regionMiss tikvRPC regionMiss regionMiss]|0|0|624|172064|60|0|0|0|0|0|0|0|0|0|0|0|0|0|0|rtdb||0|124acb3a0bec903176baca5f9da00b4e7512a41c93b417923f26502edeb324cc||0|0|0||0|0|0||856544|0|86.635049185|0.015486658|100.054|0|0||0|1|0|0|0||||INSERT INTO ...;",
+	re.Check(testutil.RowsWithSep("|", "2019-02-12 11:33:56.571953|406315658548871171|root|localhost|6|57|0.12|4.895492|0.4|0.2|0.000000003|2|0.000000002|0.00000001|0.000000003|0.19|0.21|0.01|0|0.18|[txnLock]|0.03|0|15|480|1|8|0.3824278|0.161|0.101|0.092|1.71|1|100001|100000|100|10|10|10|100|test||0|42a1c8aae6f133e934d4bf0147491709a8812ea05ff8819ec522780fe657b772|t1:1,t2:2|0.1|0.2|0.03|127.0.0.1:20160|0.05|0.6|0.8|0.0.0.0:20160|70724|65536||0|0|0|0|10||0|1|0|1|0|||abcd|60e9378c746d9a2be1c791047e008967cf252eb6de9167ad3aa6098fa2d523f4|update t set i = 2;|select * from t_slim;",
+		"2021-09-08|06:39:54.506967|427578666238083075|root|172.16.0.0|40507|0|0|25.571605962|0.002923536|0.006800973|0.002100764|0|0|0|0.000015801|25.542014572|0|0.002294647|0.000605473|12.483|[tikvRPC regionMiss tikvRPC regionMiss regionMiss]|0|0|624|172064|60|0|0|0|0|0|0|0|0|0|0|0|0|0|0|rtdb||0|124acb3a0bec903176baca5f9da00b4e7512a41c93b417923f26502edeb324cc||0|0|0||0|0|0||856544|0||86.635049185|0.015486658|100.054|0|0||0|1|0|0|0||||||INSERT INTO ...;",
 	))
 
 	// Test for long query.
@@ -129,8 +129,9 @@ var (
 const (
 	LblAction = "action"
 
-	LblAddIndex     = "add_index"
-	LblModifyColumn = "modify_column"
+	LblAddIndex       = "add_index"
+	LblModifyColumn   = "modify_column"
+	LblReorgPartition = "reorganize_partition"
 )
 
 // GetBackfillProgressByLabel returns the Gauge showing the percentage progress for the given type label.
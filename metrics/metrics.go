@@ -109,6 +109,7 @@ func RegisterMetrics() {
 	prometheus.MustRegister(PlanCacheCounter)
 	prometheus.MustRegister(PseudoEstimation)
 	prometheus.MustRegister(PacketIOHistogram)
+	prometheus.MustRegister(PacketIOCompressionCounter)
 	prometheus.MustRegister(QueryDurationHistogram)
 	prometheus.MustRegister(QueryTotalCounter)
 	prometheus.MustRegister(SchemaLeaseErrorCounter)
@@ -149,6 +150,11 @@ func RegisterMetrics() {
 	prometheus.MustRegister(ConnIdleDurationHistogram)
 	prometheus.MustRegister(ServerInfo)
 	prometheus.MustRegister(TokenGauge)
+	prometheus.MustRegister(ListenerConnGauge)
+	prometheus.MustRegister(ListenerTokenGauge)
+	prometheus.MustRegister(ListenerRejectedCounter)
+	prometheus.MustRegister(ClusterTLSCertExpireTime)
+	prometheus.MustRegister(ClusterTLSReloadCounter)
 	prometheus.MustRegister(ConfigStatus)
 	prometheus.MustRegister(TiFlashQueryTotalCounter)
 	prometheus.MustRegister(SmallTxnWriteDuration)
@@ -36,6 +36,14 @@ var (
 			Buckets:   prometheus.ExponentialBuckets(4, 4, 21), // 4Bytes ~ 4TB
 		}, []string{LblType})
 
+	PacketIOCompressionCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tidb",
+			Subsystem: "server",
+			Name:      "packet_io_compression_bytes",
+			Help:      "Counter of packet IO bytes before and after protocol compression, the quotient of them is the compression ratio.",
+		}, []string{LblType, LblStage})
+
 	QueryDurationHistogram = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Namespace: "tidb",
@@ -94,6 +102,7 @@ var (
 
 	EventStart        = "start"
 	EventGracefulDown = "graceful_shutdown"
+	EventDrain        = "drain"
 	// Eventkill occurs when the server.Kill() function is called.
 	EventKill          = "kill"
 	EventClose         = "close"
@@ -214,6 +223,46 @@ var (
 		},
 	)
 
+	ListenerConnGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "tidb",
+			Subsystem: "server",
+			Name:      "listener_connections",
+			Help:      "Number of connections of each listener.",
+		}, []string{LblListener})
+
+	ListenerTokenGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "tidb",
+			Subsystem: "server",
+			Name:      "listener_tokens",
+			Help:      "The number of concurrent executing session of each listener.",
+		}, []string{LblListener})
+
+	ListenerRejectedCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tidb",
+			Subsystem: "server",
+			Name:      "listener_rejected_connections",
+			Help:      "Counter of connections rejected by each listener.",
+		}, []string{LblListener, LblType})
+
+	ClusterTLSCertExpireTime = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "tidb",
+			Subsystem: "server",
+			Name:      "cluster_tls_cert_expire_time_seconds",
+			Help:      "The expiration time of the cluster TLS certificates in unix timestamp.",
+		}, []string{LblType})
+
+	ClusterTLSReloadCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "tidb",
+			Subsystem: "server",
+			Name:      "cluster_tls_reload_total",
+			Help:      "Counter of reloading the rotated cluster TLS certificates.",
+		}, []string{LblResult})
+
 	ConfigStatus = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Namespace: "tidb",
@@ -156,4 +156,6 @@ const (
 	LblVersion     = "version"
 	LblHash        = "hash"
 	LblCTEType     = "cte_type"
+	LblStage       = "stage"
+	LblListener    = "listener"
 )
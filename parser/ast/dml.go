@@ -21,6 +21,7 @@ import (
 	"github.com/pingcap/tidb/parser/format"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/types"
 )
 
 var (
@@ -1020,6 +1021,164 @@ func (s *TableSample) Accept(v Visitor) (node Node, ok bool) {
 	return v.Leave(s)
 }
 
+// JSONTable is the JSON_TABLE table function, which extracts rows from a JSON document.
+// See https://dev.mysql.com/doc/refman/8.0/en/json-table-functions.html
+type JSONTable struct {
+	node
+
+	// Expr is the JSON document.
+	Expr ExprNode
+	// Path is the path expression which selects the rows from the document.
+	Path string
+	// Columns are the columns of the result table.
+	Columns []*JSONTableColumn
+}
+
+func (*JSONTable) resultSet() {}
+
+// Restore implements Node interface.
+func (n *JSONTable) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("JSON_TABLE")
+	ctx.WritePlain("(")
+	if err := n.Expr.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore JSONTable.Expr")
+	}
+	ctx.WritePlain(", ")
+	ctx.WriteString(n.Path)
+	ctx.WritePlain(" ")
+	if err := restoreJSONTableColumns(ctx, n.Columns); err != nil {
+		return err
+	}
+	ctx.WritePlain(")")
+	return nil
+}
+
+// Accept implements Node Accept interface.
+func (n *JSONTable) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*JSONTable)
+	node, ok := n.Expr.Accept(v)
+	if !ok {
+		return n, false
+	}
+	n.Expr = node.(ExprNode)
+	return v.Leave(n)
+}
+
+func restoreJSONTableColumns(ctx *format.RestoreCtx, cols []*JSONTableColumn) error {
+	ctx.WriteKeyWord("COLUMNS")
+	ctx.WritePlain("(")
+	for i, col := range cols {
+		if i != 0 {
+			ctx.WritePlain(", ")
+		}
+		if err := col.Restore(ctx); err != nil {
+			return errors.Annotatef(err, "An error occurred while restore JSONTable.Columns[%d]", i)
+		}
+	}
+	ctx.WritePlain(")")
+	return nil
+}
+
+// JSONTableColumnType is the type of a JSON_TABLE column.
+type JSONTableColumnType int
+
+const (
+	// JSONTableColumnPath is a column whose value is extracted by a path.
+	JSONTableColumnPath JSONTableColumnType = iota
+	// JSONTableColumnExistsPath is a column which indicates whether a path exists.
+	JSONTableColumnExistsPath
+	// JSONTableColumnOrdinality is a column which enumerates the rows.
+	JSONTableColumnOrdinality
+	// JSONTableColumnNested flattens a nested array into the columns it contains.
+	JSONTableColumnNested
+)
+
+// JSONTableColumn is a column definition in JSON_TABLE.
+type JSONTableColumn struct {
+	Tp JSONTableColumnType
+	// Name is the column name, it is empty for the NESTED PATH columns.
+	Name model.CIStr
+	// FieldType is the column type, it is only set for the PATH and EXISTS PATH columns.
+	FieldType *types.FieldType
+	Path      string
+	// OnEmpty and OnError are only set for the PATH columns, nil means the clause is absent.
+	OnEmpty *JSONTableOnResponse
+	OnError *JSONTableOnResponse
+	// Columns are the columns of the NESTED PATH.
+	Columns []*JSONTableColumn
+}
+
+// Restore writes the column definition into ctx.
+func (n *JSONTableColumn) Restore(ctx *format.RestoreCtx) error {
+	if n.Tp == JSONTableColumnNested {
+		ctx.WriteKeyWord("NESTED PATH ")
+		ctx.WriteString(n.Path)
+		ctx.WritePlain(" ")
+		return restoreJSONTableColumns(ctx, n.Columns)
+	}
+	ctx.WriteName(n.Name.O)
+	if n.Tp == JSONTableColumnOrdinality {
+		ctx.WriteKeyWord(" FOR ORDINALITY")
+		return nil
+	}
+	ctx.WritePlain(" ")
+	if err := n.FieldType.Restore(ctx); err != nil {
+		return errors.Annotate(err, "An error occurred while restore JSONTableColumn.FieldType")
+	}
+	if n.Tp == JSONTableColumnExistsPath {
+		ctx.WriteKeyWord(" EXISTS")
+	}
+	ctx.WriteKeyWord(" PATH ")
+	ctx.WriteString(n.Path)
+	if n.OnEmpty != nil {
+		ctx.WritePlain(" ")
+		n.OnEmpty.Restore(ctx)
+		ctx.WriteKeyWord(" ON EMPTY")
+	}
+	if n.OnError != nil {
+		ctx.WritePlain(" ")
+		n.OnError.Restore(ctx)
+		ctx.WriteKeyWord(" ON ERROR")
+	}
+	return nil
+}
+
+// JSONTableOnResponseType is the type of the ON EMPTY and ON ERROR clauses.
+type JSONTableOnResponseType int
+
+const (
+	// JSONTableOnResponseNull sets the column to NULL, it is the default.
+	JSONTableOnResponseNull JSONTableOnResponseType = iota
+	// JSONTableOnResponseError throws an error.
+	JSONTableOnResponseError
+	// JSONTableOnResponseDefault sets the column to the default value.
+	JSONTableOnResponseDefault
+)
+
+// JSONTableOnResponse is the ON EMPTY or ON ERROR clause of a JSON_TABLE column.
+type JSONTableOnResponse struct {
+	Tp JSONTableOnResponseType
+	// Default is the JSON string used by DEFAULT.
+	Default string
+}
+
+// Restore writes the response without the ON EMPTY or ON ERROR keywords into ctx.
+func (n *JSONTableOnResponse) Restore(ctx *format.RestoreCtx) {
+	switch n.Tp {
+	case JSONTableOnResponseNull:
+		ctx.WriteKeyWord("NULL")
+	case JSONTableOnResponseError:
+		ctx.WriteKeyWord("ERROR")
+	case JSONTableOnResponseDefault:
+		ctx.WriteKeyWord("DEFAULT ")
+		ctx.WriteString(n.Default)
+	}
+}
+
 type SelectStmtKind uint8
 
 const (
@@ -2602,6 +2761,7 @@ const (
 	ShowPlacementForTable
 	ShowPlacementForPartition
 	ShowPlacementLabels
+	ShowSessionStates
 )
 
 const (
@@ -2818,6 +2978,8 @@ func (n *ShowStmt) Restore(ctx *format.RestoreCtx) error {
 		ctx.WriteKeyWord("PRIVILEGES")
 	case ShowBuiltins:
 		ctx.WriteKeyWord("BUILTINS")
+	case ShowSessionStates:
+		ctx.WriteKeyWord("SESSION_STATES")
 	case ShowCreateImport:
 		ctx.WriteKeyWord("CREATE IMPORT ")
 		ctx.WriteName(n.DBName)
@@ -211,6 +211,10 @@ const (
 	Ord             = "ord"
 	Position        = "position"
 	Quote           = "quote"
+	RegexpLike      = "regexp_like"
+	RegexpInStr     = "regexp_instr"
+	RegexpSubstr    = "regexp_substr"
+	RegexpReplace   = "regexp_replace"
 	Repeat          = "repeat"
 	Replace         = "replace"
 	Reverse         = "reverse"
@@ -248,6 +252,7 @@ const (
 	Database             = "database"
 	FoundRows            = "found_rows"
 	LastInsertId         = "last_insert_id"
+	QueryAttributeString = "mysql_query_attribute_string"
 	RowCount             = "row_count"
 	Schema               = "schema"
 	SessionUser          = "session_user"
@@ -338,6 +343,8 @@ const (
 	JSONDepth         = "json_depth"
 	JSONKeys          = "json_keys"
 	JSONLength        = "json_length"
+	JSONMemberOf      = "json_memberof"
+	JSONOverlaps      = "json_overlaps"
 
 	// TiDB internal function.
 	TiDBDecodeKey       = "tidb_decode_key"
@@ -389,6 +396,19 @@ func (n *FuncCallExpr) Restore(ctx *format.RestoreCtx) error {
 		return nil
 	}
 
+	if n.FnName.L == JSONMemberOf && len(n.Schema.String()) == 0 {
+		if err := n.Args[0].Restore(ctx); err != nil {
+			return errors.Annotatef(err, "An error occurred while restore FuncCallExpr.Args[0]")
+		}
+		ctx.WriteKeyWord(" MEMBER OF ")
+		ctx.WritePlain("(")
+		if err := n.Args[1].Restore(ctx); err != nil {
+			return errors.Annotatef(err, "An error occurred while restore FuncCallExpr.Args[1]")
+		}
+		ctx.WritePlain(")")
+		return nil
+	}
+
 	if len(n.Schema.String()) != 0 {
 		ctx.WriteName(n.Schema.O)
 		ctx.WritePlain(".")
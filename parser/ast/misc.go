@@ -945,6 +945,36 @@ func (n *SetConfigStmt) Accept(v Visitor) (Node, bool) {
 	return v.Leave(n)
 }
 
+// SetSessionStatesStmt is a statement to restore the session states exported by SHOW SESSION_STATES.
+type SetSessionStatesStmt struct {
+	stmtNode
+
+	SessionStates string
+}
+
+// Restore implements Node interface.
+func (n *SetSessionStatesStmt) Restore(ctx *format.RestoreCtx) error {
+	ctx.WriteKeyWord("SET SESSION_STATES ")
+	ctx.WriteString(n.SessionStates)
+	return nil
+}
+
+// SecureText implements SensitiveStatement interface.
+func (n *SetSessionStatesStmt) SecureText() string {
+	// The token is a credential of the session, it must not be logged.
+	return "set session_states ?"
+}
+
+// Accept implements Node Accept interface.
+func (n *SetSessionStatesStmt) Accept(v Visitor) (Node, bool) {
+	newNode, skipChildren := v.Enter(n)
+	if skipChildren {
+		return v.Leave(newNode)
+	}
+	n = newNode.(*SetSessionStatesStmt)
+	return v.Leave(n)
+}
+
 /*
 // SetCharsetStmt is a statement to assign values to character and collation variables.
 // See https://dev.mysql.com/doc/refman/5.7/en/set-statement.html
@@ -1266,6 +1296,7 @@ const (
 	MaxUpdatesPerHour
 	MaxConnectionsPerHour
 	MaxUserConnections
+	MaxMemoryQuota
 )
 
 type ResourceOption struct {
@@ -1283,6 +1314,8 @@ func (r *ResourceOption) Restore(ctx *format.RestoreCtx) error {
 		ctx.WriteKeyWord("MAX_CONNECTIONS_PER_HOUR ")
 	case MaxUserConnections:
 		ctx.WriteKeyWord("MAX_USER_CONNECTIONS ")
+	case MaxMemoryQuota:
+		ctx.WriteKeyWord("MAX_MEMORY_QUOTA ")
 	default:
 		return errors.Errorf("Unsupported ResourceOption.Type %d", r.Type)
 	}
@@ -1297,6 +1330,13 @@ const (
 	PasswordExpireInterval
 	Lock
 	Unlock
+	PasswordHistory
+	PasswordHistoryDefault
+	PasswordReuseInterval
+	PasswordReuseDefault
+	FailedLoginAttempts
+	PasswordLockTime
+	PasswordLockTimeUnbounded
 )
 
 type PasswordOrLockOption struct {
@@ -1320,6 +1360,25 @@ func (p *PasswordOrLockOption) Restore(ctx *format.RestoreCtx) error {
 		ctx.WriteKeyWord("ACCOUNT LOCK")
 	case Unlock:
 		ctx.WriteKeyWord("ACCOUNT UNLOCK")
+	case PasswordHistory:
+		ctx.WriteKeyWord("PASSWORD HISTORY")
+		ctx.WritePlainf(" %d", p.Count)
+	case PasswordHistoryDefault:
+		ctx.WriteKeyWord("PASSWORD HISTORY DEFAULT")
+	case PasswordReuseInterval:
+		ctx.WriteKeyWord("PASSWORD REUSE INTERVAL")
+		ctx.WritePlainf(" %d", p.Count)
+		ctx.WriteKeyWord(" DAY")
+	case PasswordReuseDefault:
+		ctx.WriteKeyWord("PASSWORD REUSE INTERVAL DEFAULT")
+	case FailedLoginAttempts:
+		ctx.WriteKeyWord("FAILED_LOGIN_ATTEMPTS")
+		ctx.WritePlainf(" %d", p.Count)
+	case PasswordLockTime:
+		ctx.WriteKeyWord("PASSWORD_LOCK_TIME")
+		ctx.WritePlainf(" %d", p.Count)
+	case PasswordLockTimeUnbounded:
+		ctx.WriteKeyWord("PASSWORD_LOCK_TIME UNBOUNDED")
 	default:
 		return errors.Errorf("Unsupported PasswordOrLockOption.Type %d", p.Type)
 	}
@@ -1845,6 +1904,7 @@ const (
 	AdminShowTelemetry
 	AdminResetTelemetryID
 	AdminReloadStatistics
+	AdminFlushInstancePlanCache
 )
 
 // HandleRange represents a range where handle value >= Begin and < End.
@@ -2059,6 +2119,8 @@ func (n *AdminStmt) Restore(ctx *format.RestoreCtx) error {
 		ctx.WriteKeyWord("RESET TELEMETRY_ID")
 	case AdminReloadStatistics:
 		ctx.WriteKeyWord("RELOAD STATS_EXTENDED")
+	case AdminFlushInstancePlanCache:
+		ctx.WriteKeyWord("FLUSH INSTANCE PLAN_CACHE")
 	default:
 		return errors.New("Unsupported AdminStmt type")
 	}
@@ -3324,7 +3386,7 @@ func (n *TableOptimizerHint) Restore(ctx *format.RestoreCtx) error {
 	}
 	// Hints without args except query block.
 	switch n.HintName.L {
-	case "hash_agg", "stream_agg", "agg_to_cop", "read_consistent_replica", "no_index_merge", "qb_name", "ignore_plan_cache", "limit_to_cop":
+	case "hash_agg", "stream_agg", "agg_to_cop", "read_consistent_replica", "no_index_merge", "qb_name", "ignore_plan_cache", "limit_to_cop", "no_decorrelate":
 		ctx.WritePlain(")")
 		return nil
 	}
@@ -3337,7 +3399,8 @@ func (n *TableOptimizerHint) Restore(ctx *format.RestoreCtx) error {
 		ctx.WritePlainf("%d", n.HintData.(uint64))
 	case "nth_plan":
 		ctx.WritePlainf("%d", n.HintData.(int64))
-	case "tidb_hj", "tidb_smj", "tidb_inlj", "hash_join", "merge_join", "inl_join", "broadcast_join", "broadcast_join_local", "inl_hash_join", "inl_merge_join":
+	case "tidb_hj", "tidb_smj", "tidb_inlj", "hash_join", "merge_join", "inl_join", "broadcast_join", "broadcast_join_local", "inl_hash_join", "inl_merge_join", "leading",
+		"hash_join_build", "hash_join_probe", "no_hash_join", "no_merge_join", "no_index_join":
 		for i, table := range n.Tables {
 			if i != 0 {
 				ctx.WritePlain(", ")
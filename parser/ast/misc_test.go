@@ -245,8 +245,14 @@ func TestTableOptimizerHintRestore(t *testing.T) {
 		{"BROADCAST_JOIN(t1,t2)", "BROADCAST_JOIN(`t1`, `t2`)"},
 		{"INL_HASH_JOIN(t1,t2)", "INL_HASH_JOIN(`t1`, `t2`)"},
 		{"INL_MERGE_JOIN(t1,t2)", "INL_MERGE_JOIN(`t1`, `t2`)"},
+		{"LEADING(t3,t1@sel1,t2)", "LEADING(`t3`, `t1`@`sel1`, `t2`)"},
 		{"INL_JOIN(t1,t2)", "INL_JOIN(`t1`, `t2`)"},
 		{"HASH_JOIN(t1,t2)", "HASH_JOIN(`t1`, `t2`)"},
+		{"HASH_JOIN_BUILD(t1)", "HASH_JOIN_BUILD(`t1`)"},
+		{"HASH_JOIN_PROBE(t1@sel1)", "HASH_JOIN_PROBE(`t1`@`sel1`)"},
+		{"NO_HASH_JOIN(t1,t2)", "NO_HASH_JOIN(`t1`, `t2`)"},
+		{"NO_MERGE_JOIN(t1,t2)", "NO_MERGE_JOIN(`t1`, `t2`)"},
+		{"NO_INDEX_JOIN(t1,t2)", "NO_INDEX_JOIN(`t1`, `t2`)"},
 		{"MAX_EXECUTION_TIME(3000)", "MAX_EXECUTION_TIME(3000)"},
 		{"MAX_EXECUTION_TIME(@sel1 3000)", "MAX_EXECUTION_TIME(@`sel1` 3000)"},
 		{"USE_INDEX_MERGE(t1 c1)", "USE_INDEX_MERGE(`t1` `c1`)"},
@@ -274,6 +280,8 @@ func TestTableOptimizerHintRestore(t *testing.T) {
 		{"LIMIT_TO_COP()", "LIMIT_TO_COP()"},
 		{"NO_INDEX_MERGE()", "NO_INDEX_MERGE()"},
 		{"NO_INDEX_MERGE(@sel1)", "NO_INDEX_MERGE(@`sel1`)"},
+		{"NO_DECORRELATE()", "NO_DECORRELATE()"},
+		{"NO_DECORRELATE(@sel1)", "NO_DECORRELATE(@`sel1`)"},
 		{"READ_CONSISTENT_REPLICA()", "READ_CONSISTENT_REPLICA()"},
 		{"READ_CONSISTENT_REPLICA(@sel1)", "READ_CONSISTENT_REPLICA(@`sel1`)"},
 		{"QB_NAME(sel1)", "QB_NAME(`sel1`)"},
@@ -105,6 +105,12 @@ import (
 	hintNthPlan               "NTH_PLAN"
 	hintLimitToCop            "LIMIT_TO_COP"
 	hintForceIndex            "FORCE_INDEX"
+	hintLeading               "LEADING"
+	hintHashJoinBuild         "HASH_JOIN_BUILD"
+	hintHashJoinProbe         "HASH_JOIN_PROBE"
+	hintNoMergeJoin           "NO_MERGE_JOIN"
+	hintNoIndexJoin           "NO_INDEX_JOIN"
+	hintNoDecorrelate         "NO_DECORRELATE"
 
 	/* Other keywords */
 	hintOLAP            "OLAP"
@@ -525,8 +531,7 @@ UnsupportedTableLevelOptimizerHintName:
 |	"NO_BKA"
 |	"BNL"
 |	"NO_BNL"
-/* HASH_JOIN is supported by TiDB */
-|	"NO_HASH_JOIN"
+/* HASH_JOIN and NO_HASH_JOIN are supported by TiDB */
 |	"MERGE"
 |	"NO_MERGE"
 
@@ -540,6 +545,12 @@ SupportedTableLevelOptimizerHintName:
 |	"NO_SWAP_JOIN_INPUTS"
 |	"INL_MERGE_JOIN"
 |	"HASH_JOIN"
+|	"LEADING"
+|	"HASH_JOIN_BUILD"
+|	"HASH_JOIN_PROBE"
+|	"NO_HASH_JOIN"
+|	"NO_MERGE_JOIN"
+|	"NO_INDEX_JOIN"
 
 UnsupportedIndexLevelOptimizerHintName:
 	"INDEX_MERGE"
@@ -580,6 +591,7 @@ NullaryHintName:
 |	"NO_INDEX_MERGE"
 |	"READ_CONSISTENT_REPLICA"
 |	"IGNORE_PLAN_CACHE"
+|	"NO_DECORRELATE"
 
 HintQueryType:
 	"OLAP"
@@ -645,6 +657,12 @@ Identifier:
 |	"USE_CASCADES"
 |	"NTH_PLAN"
 |	"FORCE_INDEX"
+|	"LEADING"
+|	"HASH_JOIN_BUILD"
+|	"HASH_JOIN_PROBE"
+|	"NO_MERGE_JOIN"
+|	"NO_INDEX_JOIN"
+|	"NO_DECORRELATE"
 /* other keywords */
 |	"OLAP"
 |	"OLTP"
@@ -155,6 +155,7 @@ var tokenMap = map[string]int{
 	"APPROX_PERCENTILE":        approxPercentile,
 	"AS":                       as,
 	"ASC":                      asc,
+	"ARRAY":                    array,
 	"ASCII":                    ascii,
 	"ATTRIBUTES":               attributes,
 	"STATS_OPTIONS":            statsOptions,
@@ -301,6 +302,7 @@ var tokenMap = map[string]int{
 	"DUMP":                     dump,
 	"DUPLICATE":                duplicate,
 	"DYNAMIC":                  dynamic,
+	"EMPTY":                    emptyKwd,
 	"ELSE":                     elseKwd,
 	"ENABLE":                   enable,
 	"ENCLOSED":                 enclosed,
@@ -330,6 +332,7 @@ var tokenMap = map[string]int{
 	"EXTENDED":                 extended,
 	"EXTRACT":                  extract,
 	"FALSE":                    falseKwd,
+	"FAILED_LOGIN_ATTEMPTS":    failedLoginAttempts,
 	"FAULTS":                   faultsSym,
 	"FETCH":                    fetch,
 	"FIELDS":                   fields,
@@ -410,6 +413,7 @@ var tokenMap = map[string]int{
 	"JOIN":                     join,
 	"JSON_ARRAYAGG":            jsonArrayagg,
 	"JSON_OBJECTAGG":           jsonObjectAgg,
+	"JSON_TABLE":               jsonTable,
 	"JSON":                     jsonType,
 	"KEY_BLOCK_SIZE":           keyBlockSize,
 	"KEY":                      key,
@@ -450,6 +454,7 @@ var tokenMap = map[string]int{
 	"MATCH":                    match,
 	"MAX_CONNECTIONS_PER_HOUR": maxConnectionsPerHour,
 	"MAX_IDXNUM":               max_idxnum,
+	"MAX_MEMORY_QUOTA":         maxMemoryQuota,
 	"MAX_MINUTES":              max_minutes,
 	"MAX_QUERIES_PER_HOUR":     maxQueriesPerHour,
 	"MAX_ROWS":                 maxRows,
@@ -461,6 +466,7 @@ var tokenMap = map[string]int{
 	"MEDIUMBLOB":               mediumblobType,
 	"MEDIUMINT":                mediumIntType,
 	"MEDIUMTEXT":               mediumtextType,
+	"MEMBER":                   member,
 	"MEMORY":                   memory,
 	"MERGE":                    merge,
 	"MICROSECOND":              microsecond,
@@ -478,6 +484,7 @@ var tokenMap = map[string]int{
 	"NATIONAL":                 national,
 	"NATURAL":                  natural,
 	"NCHAR":                    ncharType,
+	"NESTED":                   nested,
 	"NEVER":                    never,
 	"NEXT_ROW_ID":              next_row_id,
 	"NEXT":                     next,
@@ -516,6 +523,7 @@ var tokenMap = map[string]int{
 	"OPTIONALLY":               optionally,
 	"OR":                       or,
 	"ORDER":                    order,
+	"ORDINALITY":               ordinality,
 	"OUTER":                    outer,
 	"OUTFILE":                  outfile,
 	"PACK_KEYS":                packKeys,
@@ -526,12 +534,15 @@ var tokenMap = map[string]int{
 	"PARTITIONING":             partitioning,
 	"PARTITIONS":               partitions,
 	"PASSWORD":                 password,
+	"PASSWORD_LOCK_TIME":       passwordLockTime,
+	"PATH":                     pathKwd,
 	"PERCENT":                  percent,
 	"PER_DB":                   per_db,
 	"PER_TABLE":                per_table,
 	"PESSIMISTIC":              pessimistic,
 	"PLACEMENT":                placement,
 	"PLAN":                     plan,
+	"PLAN_CACHE":               planCache,
 	"PLUGINS":                  plugins,
 	"POLICY":                   policy,
 	"POSITION":                 position,
@@ -603,6 +614,7 @@ var tokenMap = map[string]int{
 	"ROWS":                     rows,
 	"RTREE":                    rtree,
 	"RESUME":                   resume,
+	"REUSE":                    reuse,
 	"RUNNING":                  running,
 	"S3":                       s3,
 	"SAMPLES":                  samples,
@@ -624,6 +636,7 @@ var tokenMap = map[string]int{
 	"SERIAL":                   serial,
 	"SERIALIZABLE":             serializable,
 	"SESSION":                  session,
+	"SESSION_STATES":           sessionStates,
 	"SET":                      set,
 	"SETVAL":                   setval,
 	"SHARD_ROW_ID_BITS":        shardRowIDBits,
@@ -928,6 +941,12 @@ var hintTokenMap = map[string]int{
 	"USE_CASCADES":            hintUseCascades,
 	"NTH_PLAN":                hintNthPlan,
 	"FORCE_INDEX":             hintForceIndex,
+	"LEADING":                 hintLeading,
+	"HASH_JOIN_BUILD":         hintHashJoinBuild,
+	"HASH_JOIN_PROBE":         hintHashJoinProbe,
+	"NO_MERGE_JOIN":           hintNoMergeJoin,
+	"NO_INDEX_JOIN":           hintNoIndexJoin,
+	"NO_DECORRELATE":          hintNoDecorrelate,
 
 	// TiDB hint aliases
 	"TIDB_HJ":   hintHashJoin,
@@ -89,6 +89,10 @@ const (
 	ActionAlterTablePlacement           ActionType = 56
 	ActionAlterCacheTable               ActionType = 57
 	ActionAlterTableStatsOptions        ActionType = 58
+	ActionReorganizePartition           ActionType = 59
+	ActionAlterTablePartitioning        ActionType = 60
+	ActionRemovePartitioning            ActionType = 61
+	ActionMultiSchemaChange             ActionType = 62
 )
 
 var actionMap = map[ActionType]string{
@@ -147,6 +151,10 @@ var actionMap = map[ActionType]string{
 	ActionModifySchemaDefaultPlacement:  "modify schema default placement",
 	ActionAlterCacheTable:               "alter cache table",
 	ActionAlterTableStatsOptions:        "alter table statistics options",
+	ActionReorganizePartition:           "alter table reorganize partition",
+	ActionAlterTablePartitioning:        "alter table partition by",
+	ActionRemovePartitioning:            "alter table remove partitioning",
+	ActionMultiSchemaChange:             "alter table multi-schema change",
 }
 
 // String return current ddl action in string
@@ -206,7 +214,112 @@ func NewDDLReorgMeta() *DDLReorgMeta {
 
 // MultiSchemaInfo keeps some information for multi schema change.
 type MultiSchemaInfo struct {
+	// SubJobs are the schema changes bundled in one ActionMultiSchemaChange job.
+	SubJobs []*SubJob `json:"sub_jobs"`
+	// Revertible is true if all the sub-jobs can still be rolled back.
+	Revertible bool `json:"revertible"`
+	// SkipVersion is set on the proxy job of a sub-job that is stepped together with the other
+	// sub-jobs, the schema version is updated once by the multi-schema change for all of them.
+	SkipVersion bool `json:"-"`
+	// SchemaChanged is set if the proxy job with SkipVersion has changed the table info.
+	SchemaChanged bool `json:"-"`
+
 	Warnings []*errors.Error
+
+	// The following fields are only used to check the conflicts between sub-jobs
+	// before the job is submitted, they are not persisted.
+	AddColumns    []CIStr `json:"-"`
+	DropColumns   []CIStr `json:"-"`
+	ModifyColumns []CIStr `json:"-"`
+	AddIndexes    []CIStr `json:"-"`
+	DropIndexes   []CIStr `json:"-"`
+	AlterIndexes  []CIStr `json:"-"`
+	// RelativeColumns are the columns referred by the sub-jobs but not changed by them,
+	// e.g. the columns of an added index or the column in `AFTER col`.
+	RelativeColumns []CIStr `json:"-"`
+}
+
+// NewMultiSchemaInfo new a MultiSchemaInfo.
+func NewMultiSchemaInfo() *MultiSchemaInfo {
+	return &MultiSchemaInfo{
+		SubJobs:    nil,
+		Revertible: true,
+	}
+}
+
+// SubJob is a representation of one schema change in a multi-schema change job.
+type SubJob struct {
+	Type        ActionType      `json:"type"`
+	Args        []interface{}   `json:"-"`
+	RawArgs     json.RawMessage `json:"raw_args"`
+	SchemaState SchemaState     `json:"schema_state"`
+	SnapshotVer uint64          `json:"snapshot_ver"`
+	// Revertible is true if the sub-job hasn't reached its last revertible state.
+	Revertible bool     `json:"revertible"`
+	State      JobState `json:"state"`
+	RowCount   int64    `json:"row_count"`
+	SchemaVer  int64    `json:"schema_version"`
+	// CtxVars are variables attached to the sub-job, see Job.CtxVars.
+	CtxVars []interface{} `json:"-"`
+}
+
+// IsNormal returns true if the sub-job is normally running.
+func (sub *SubJob) IsNormal() bool {
+	switch sub.State {
+	case JobStateCancelling, JobStateCancelled,
+		JobStateRollingback, JobStateRollbackDone:
+		return false
+	default:
+		return true
+	}
+}
+
+// IsFinished returns true if the sub-job is done or cancelled.
+func (sub *SubJob) IsFinished() bool {
+	return sub.State == JobStateDone ||
+		sub.State == JobStateRollbackDone ||
+		sub.State == JobStateCancelled
+}
+
+// ToProxyJob converts a sub-job to a proxy job, so that the sub-job can be run
+// by the handler of its own action type.
+func (sub *SubJob) ToProxyJob(parentJob *Job) *Job {
+	return &Job{
+		ID:              parentJob.ID,
+		Type:            sub.Type,
+		SchemaID:        parentJob.SchemaID,
+		TableID:         parentJob.TableID,
+		SchemaName:      parentJob.SchemaName,
+		State:           sub.State,
+		RowCount:        sub.RowCount,
+		CtxVars:         sub.CtxVars,
+		Args:            sub.Args,
+		RawArgs:         sub.RawArgs,
+		SchemaState:     sub.SchemaState,
+		SnapshotVer:     sub.SnapshotVer,
+		RealStartTS:     parentJob.RealStartTS,
+		StartTS:         parentJob.StartTS,
+		DependencyID:    parentJob.DependencyID,
+		Query:           parentJob.Query,
+		BinlogInfo:      parentJob.BinlogInfo,
+		Version:         parentJob.Version,
+		ReorgMeta:       parentJob.ReorgMeta,
+		MultiSchemaInfo: &MultiSchemaInfo{Revertible: sub.Revertible},
+		Priority:        parentJob.Priority,
+	}
+}
+
+// FromProxyJob updates the sub-job with the proxy job after it is run.
+func (sub *SubJob) FromProxyJob(proxyJob *Job, ver int64) {
+	sub.Revertible = proxyJob.MultiSchemaInfo.Revertible
+	sub.SchemaState = proxyJob.SchemaState
+	sub.SnapshotVer = proxyJob.SnapshotVer
+	sub.Args = proxyJob.Args
+	sub.State = proxyJob.State
+	sub.RowCount = proxyJob.RowCount
+	if ver > 0 {
+		sub.SchemaVer = ver
+	}
 }
 
 // Job is for a DDL operation.
@@ -258,6 +371,15 @@ type Job struct {
 	Priority int `json:"priority"`
 }
 
+// MarkNonRevertible marks the current job to be non-revertible.
+// It is called by a sub-job of a multi-schema change when it reaches its last revertible state,
+// the sub-job will be resumed after all the other sub-jobs become non-revertible.
+func (job *Job) MarkNonRevertible() {
+	if job.MultiSchemaInfo != nil {
+		job.MultiSchemaInfo.Revertible = false
+	}
+}
+
 // FinishTableJob is called when a job is finished.
 // It updates the job's state information and adds tblInfo to the binlog.
 func (job *Job) FinishTableJob(jobState JobState, schemaState SchemaState, ver int64, tblInfo *TableInfo) {
@@ -316,6 +438,18 @@ func (job *Job) Encode(updateRawArgs bool) ([]byte, error) {
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
+		if job.MultiSchemaInfo != nil {
+			for _, sub := range job.MultiSchemaInfo.SubJobs {
+				// Only update the args of the sub-jobs that have been run.
+				if sub.Args == nil {
+					continue
+				}
+				sub.RawArgs, err = json.Marshal(sub.Args)
+				if err != nil {
+					return nil, errors.Trace(err)
+				}
+			}
+		}
 	}
 
 	var b []byte
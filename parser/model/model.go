@@ -534,6 +534,13 @@ func (t *TableInfo) Clone() *TableInfo {
 		nt.ForeignKeys[i] = t.ForeignKeys[i].Clone()
 	}
 
+	if t.Constraints != nil {
+		nt.Constraints = make([]*ConstraintInfo, len(t.Constraints))
+		for i := range t.Constraints {
+			nt.Constraints[i] = t.Constraints[i].Clone()
+		}
+	}
+
 	return &nt
 }
 
@@ -773,6 +780,10 @@ type PartitionType int
 
 // Partition types.
 const (
+	// PartitionTypeNone is only used while a non-partitioned table is being
+	// partitioned or a partitioned table is having its partitioning removed,
+	// all the rows of the table are in its single partition.
+	PartitionTypeNone       PartitionType = 0
 	PartitionTypeRange      PartitionType = 1
 	PartitionTypeHash       PartitionType = 2
 	PartitionTypeList       PartitionType = 3
@@ -792,6 +803,8 @@ func (p PartitionType) String() string {
 		return "KEY"
 	case PartitionTypeSystemTime:
 		return "SYSTEM_TIME"
+	case PartitionTypeNone:
+		return "NONE"
 	default:
 		return ""
 	}
@@ -816,6 +829,16 @@ type PartitionInfo struct {
 	DroppingDefinitions []PartitionDefinition `json:"dropping_definitions"`
 	States              []PartitionState      `json:"states"`
 	Num                 uint64                `json:"num"`
+	// DDLState is the schema state of the partitions being reorganized.
+	// Only used when DDLAction is not ActionNone.
+	DDLState SchemaState `json:"ddl_state"`
+	// DDLAction is the DDL action currently changing the partition layout.
+	DDLAction ActionType `json:"ddl_action"`
+	// DDLType, DDLExpr and DDLColumns are the partitioning of the partitions
+	// which are not in Definitions while the partition layout is changed.
+	DDLType    PartitionType `json:"ddl_type"`
+	DDLExpr    string        `json:"ddl_expr"`
+	DDLColumns []CIStr       `json:"ddl_columns"`
 }
 
 // GetNameByID gets the partition name by ID.
@@ -998,6 +1021,7 @@ type IndexInfo struct {
 	Primary   bool           `json:"is_primary"`   // Whether the index is primary key.
 	Invisible bool           `json:"is_invisible"` // Whether the index is invisible.
 	Global    bool           `json:"is_global"`    // Whether the index is global.
+	MVIndex   bool           `json:"mv_index"`     // Whether the index is a multi-valued index.
 }
 
 // Clone clones IndexInfo.
@@ -1054,14 +1078,17 @@ func (t *TableInfo) FindConstraintInfoByName(constrName string) *ConstraintInfo
 
 // FKInfo provides meta data describing a foreign key constraint.
 type FKInfo struct {
-	ID       int64       `json:"id"`
-	Name     CIStr       `json:"fk_name"`
-	RefTable CIStr       `json:"ref_table"`
-	RefCols  []CIStr     `json:"ref_cols"`
-	Cols     []CIStr     `json:"cols"`
-	OnDelete int         `json:"on_delete"`
-	OnUpdate int         `json:"on_update"`
-	State    SchemaState `json:"state"`
+	ID   int64 `json:"id"`
+	Name CIStr `json:"fk_name"`
+	// RefSchema is the schema of the referenced table, it's empty if the foreign key is created
+	// by the old version, which means the referenced table is in the same schema.
+	RefSchema CIStr       `json:"ref_schema"`
+	RefTable  CIStr       `json:"ref_table"`
+	RefCols   []CIStr     `json:"ref_cols"`
+	Cols      []CIStr     `json:"cols"`
+	OnDelete  int         `json:"on_delete"`
+	OnUpdate  int         `json:"on_update"`
+	State     SchemaState `json:"state"`
 }
 
 // Clone clones FKInfo.
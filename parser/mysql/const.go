@@ -155,6 +155,12 @@ const (
 	ClientPluginAuth
 	ClientConnectAtts
 	ClientPluginAuthLenencClientData
+	ClientCanHandleExpiredPasswords
+	ClientSessionTrack
+	ClientDeprecateEOF
+	ClientOptionalResultsetMetadata
+	ClientZstdCompressionAlgorithm
+	ClientQueryAttributes
 )
 
 // Cache type information.
@@ -193,6 +199,10 @@ const (
 	RoleEdgeTable = "role_edges"
 	// DefaultRoleTable is the table contain default active role info
 	DefaultRoleTable = "default_roles"
+	// PasswordHistoryTable is the table contains the previous passwords of the users.
+	PasswordHistoryTable = "password_history"
+	// UserResourceUsageTable is the table contains the resource usage of the users which have resource limits.
+	UserResourceUsageTable = "user_resource_usage"
 )
 
 // MySQL type maximum length.
@@ -52,6 +52,7 @@ import (
 	/*yy:token "%c"     */
 	identifier "identifier"
 	asof       "AS OF"
+	memberof   "MEMBER OF"
 
 	/*yy:token "_%c"    */
 	underscoreCS "UNDERSCORE_CHARSET"
@@ -166,6 +167,7 @@ import (
 	int4Type          "INT4"
 	int8Type          "INT8"
 	join              "JOIN"
+	jsonTable         "JSON_TABLE"
 	key               "KEY"
 	keys              "KEYS"
 	kill              "KILL"
@@ -296,6 +298,7 @@ import (
 	algorithm             "ALGORITHM"
 	always                "ALWAYS"
 	any                   "ANY"
+	array                 "ARRAY"
 	ascii                 "ASCII"
 	attributes            "ATTRIBUTES"
 	statsOptions          "STATS_OPTIONS"
@@ -375,6 +378,7 @@ import (
 	do                    "DO"
 	duplicate             "DUPLICATE"
 	dynamic               "DYNAMIC"
+	emptyKwd              "EMPTY"
 	enable                "ENABLE"
 	encryption            "ENCRYPTION"
 	end                   "END"
@@ -393,6 +397,7 @@ import (
 	expansion             "EXPANSION"
 	expire                "EXPIRE"
 	extended              "EXTENDED"
+	failedLoginAttempts   "FAILED_LOGIN_ATTEMPTS"
 	faultsSym             "FAULTS"
 	fields                "FIELDS"
 	file                  "FILE"
@@ -445,11 +450,13 @@ import (
 	max_idxnum            "MAX_IDXNUM"
 	max_minutes           "MAX_MINUTES"
 	maxConnectionsPerHour "MAX_CONNECTIONS_PER_HOUR"
+	maxMemoryQuota        "MAX_MEMORY_QUOTA"
 	maxQueriesPerHour     "MAX_QUERIES_PER_HOUR"
 	maxRows               "MAX_ROWS"
 	maxUpdatesPerHour     "MAX_UPDATES_PER_HOUR"
 	maxUserConnections    "MAX_USER_CONNECTIONS"
 	mb                    "MB"
+	member                "MEMBER"
 	memory                "MEMORY"
 	merge                 "MERGE"
 	microsecond           "MICROSECOND"
@@ -462,6 +469,7 @@ import (
 	names                 "NAMES"
 	national              "NATIONAL"
 	ncharType             "NCHAR"
+	nested                "NESTED"
 	never                 "NEVER"
 	next                  "NEXT"
 	nextval               "NEXTVAL"
@@ -483,6 +491,7 @@ import (
 	only                  "ONLY"
 	open                  "OPEN"
 	optional              "OPTIONAL"
+	ordinality            "ORDINALITY"
 	packKeys              "PACK_KEYS"
 	pageSym               "PAGE"
 	parser                "PARSER"
@@ -490,9 +499,12 @@ import (
 	partitioning          "PARTITIONING"
 	partitions            "PARTITIONS"
 	password              "PASSWORD"
+	passwordLockTime      "PASSWORD_LOCK_TIME"
+	pathKwd               "PATH"
 	percent               "PERCENT"
 	per_db                "PER_DB"
 	per_table             "PER_TABLE"
+	planCache             "PLAN_CACHE"
 	pipesAsOr
 	plugins               "PLUGINS"
 	policy                "POLICY"
@@ -529,6 +541,7 @@ import (
 	restore               "RESTORE"
 	restores              "RESTORES"
 	resume                "RESUME"
+	reuse                 "REUSE"
 	reverse               "REVERSE"
 	role                  "ROLE"
 	rollback              "ROLLBACK"
@@ -548,6 +561,7 @@ import (
 	serial                "SERIAL"
 	serializable          "SERIALIZABLE"
 	session               "SESSION"
+	sessionStates         "SESSION_STATES"
 	setval                "SETVAL"
 	shardRowIDBits        "SHARD_ROW_ID_BITS"
 	share                 "SHARE"
@@ -1050,6 +1064,11 @@ import (
 	IndexPartSpecificationList             "List of index column name or expression"
 	IndexPartSpecificationListOpt          "Optional list of index column name or expression"
 	InsertValues                           "Rest part of INSERT/REPLACE INTO statement"
+	JSONTableColumn                        "JSON_TABLE column definition"
+	JSONTableColumnList                    "JSON_TABLE column definition list"
+	JSONTableColumnsClause                 "JSON_TABLE COLUMNS clause"
+	JSONTableOnEmptyErrorOpt               "Optional JSON_TABLE ON EMPTY and ON ERROR clauses"
+	JSONTableOnResponse                    "JSON_TABLE ON EMPTY or ON ERROR response"
 	JoinTable                              "join table"
 	JoinType                               "join type"
 	KillOrKillTiDB                         "Kill or Kill TiDB"
@@ -5527,6 +5546,10 @@ PredicateExpr:
 	{
 		$$ = &ast.PatternRegexpExpr{Expr: $1, Pattern: $3, Not: !$2.(bool)}
 	}
+|	BitExpr memberof '(' SimpleExpr ')'
+	{
+		$$ = &ast.FuncCallExpr{FnName: model.NewCIStr(ast.JSONMemberOf), Args: []ast.ExprNode{$1, $4}}
+	}
 |	BitExpr
 
 RegexpSym:
@@ -5853,6 +5876,9 @@ UnReservedKeyword:
 |	"COLUMNS"
 |	"CONFIG"
 |	"SAN"
+|	"REUSE"
+|	"FAILED_LOGIN_ATTEMPTS"
+|	"PASSWORD_LOCK_TIME"
 |	"COMMIT"
 |	"COMPACT"
 |	"COMPRESSED"
@@ -5913,6 +5939,7 @@ UnReservedKeyword:
 |	"ROLE"
 |	"ROLLBACK"
 |	"SESSION"
+|	"SESSION_STATES"
 |	"SIGNED"
 |	"SHARD_ROW_ID_BITS"
 |	"SHUTDOWN"
@@ -6015,6 +6042,8 @@ UnReservedKeyword:
 |	"MAX_QUERIES_PER_HOUR"
 |	"MAX_UPDATES_PER_HOUR"
 |	"MAX_USER_CONNECTIONS"
+|	"MAX_MEMORY_QUOTA"
+|	"PLAN_CACHE"
 |	"REPLICATION"
 |	"CLIENT"
 |	"SLAVE"
@@ -6160,6 +6189,12 @@ UnReservedKeyword:
 |	"CLUSTERED"
 |	"NONCLUSTERED"
 |	"PRESERVE"
+|	"EMPTY"
+|	"NESTED"
+|	"ORDINALITY"
+|	"PATH"
+|	"ARRAY"
+|	"MEMBER"
 
 TiDBKeyword:
 	"ADMIN"
@@ -6936,6 +6971,31 @@ SimpleExpr:
 			ExplicitCharSet: explicitCharset,
 		}
 	}
+|	builtinCast '(' Expression "AS" CastType "ARRAY" ')'
+	{
+		tp := $5.(*types.FieldType)
+		defaultFlen, defaultDecimal := mysql.GetDefaultFieldLengthAndDecimalForCast(tp.Tp)
+		if tp.Flen == types.UnspecifiedLength {
+			tp.Flen = defaultFlen
+		}
+		if tp.Decimal == types.UnspecifiedLength {
+			tp.Decimal = defaultDecimal
+		}
+		x := types.NewFieldType(mysql.TypeJSON)
+		x.Flen, x.Decimal = mysql.GetDefaultFieldLengthAndDecimalForCast(mysql.TypeJSON)
+		x.Flag |= mysql.BinaryFlag
+		x.Charset = mysql.DefaultCharset
+		x.Collate = mysql.DefaultCollationName
+		x.ArrayElem = tp
+		explicitCharset := parser.explicitCharset
+		parser.explicitCharset = false
+		$$ = &ast.FuncCastExpr{
+			Expr:            $3,
+			Tp:              x,
+			FunctionType:    ast.CastFunction,
+			ExplicitCharSet: explicitCharset,
+		}
+	}
 |	"CASE" ExpressionOpt WhenClauseList ElseOpt "END"
 	{
 		x := &ast.CaseExpr{WhenClauses: $3.([]*ast.WhenClause)}
@@ -8829,6 +8889,11 @@ TableFactor:
 		j.ExplicitParens = true
 		$$ = $2
 	}
+|	"JSON_TABLE" '(' Expression ',' stringLit JSONTableColumnsClause ')' TableAsName
+	{
+		jt := &ast.JSONTable{Expr: $3, Path: $5, Columns: $6.([]*ast.JSONTableColumn)}
+		$$ = &ast.TableSource{Source: jt, AsName: $8.(model.CIStr)}
+	}
 
 PartitionNameListOpt:
 	/* empty */
@@ -8857,6 +8922,87 @@ TableAsName:
 		$$ = model.NewCIStr($2)
 	}
 
+JSONTableColumnsClause:
+	"COLUMNS" '(' JSONTableColumnList ')'
+	{
+		$$ = $3
+	}
+
+JSONTableColumnList:
+	JSONTableColumn
+	{
+		$$ = []*ast.JSONTableColumn{$1.(*ast.JSONTableColumn)}
+	}
+|	JSONTableColumnList ',' JSONTableColumn
+	{
+		$$ = append($1.([]*ast.JSONTableColumn), $3.(*ast.JSONTableColumn))
+	}
+
+JSONTableColumn:
+	Identifier "FOR" "ORDINALITY"
+	{
+		$$ = &ast.JSONTableColumn{Tp: ast.JSONTableColumnOrdinality, Name: model.NewCIStr($1)}
+	}
+|	Identifier Type "PATH" stringLit JSONTableOnEmptyErrorOpt
+	{
+		col := $5.(*ast.JSONTableColumn)
+		col.Tp = ast.JSONTableColumnPath
+		col.Name = model.NewCIStr($1)
+		col.FieldType = $2.(*types.FieldType)
+		col.Path = $4
+		$$ = col
+	}
+|	Identifier Type "EXISTS" "PATH" stringLit
+	{
+		$$ = &ast.JSONTableColumn{
+			Tp:        ast.JSONTableColumnExistsPath,
+			Name:      model.NewCIStr($1),
+			FieldType: $2.(*types.FieldType),
+			Path:      $5,
+		}
+	}
+|	"NESTED" stringLit JSONTableColumnsClause
+	{
+		$$ = &ast.JSONTableColumn{Tp: ast.JSONTableColumnNested, Path: $2, Columns: $3.([]*ast.JSONTableColumn)}
+	}
+|	"NESTED" "PATH" stringLit JSONTableColumnsClause
+	{
+		$$ = &ast.JSONTableColumn{Tp: ast.JSONTableColumnNested, Path: $3, Columns: $4.([]*ast.JSONTableColumn)}
+	}
+
+/* JSONTableOnEmptyErrorOpt returns a column which only has OnEmpty and OnError set. */
+JSONTableOnEmptyErrorOpt:
+	/* empty */
+	{
+		$$ = &ast.JSONTableColumn{}
+	}
+|	JSONTableOnResponse "ON" "EMPTY"
+	{
+		$$ = &ast.JSONTableColumn{OnEmpty: $1.(*ast.JSONTableOnResponse)}
+	}
+|	JSONTableOnResponse "ON" "ERROR"
+	{
+		$$ = &ast.JSONTableColumn{OnError: $1.(*ast.JSONTableOnResponse)}
+	}
+|	JSONTableOnResponse "ON" "EMPTY" JSONTableOnResponse "ON" "ERROR"
+	{
+		$$ = &ast.JSONTableColumn{OnEmpty: $1.(*ast.JSONTableOnResponse), OnError: $4.(*ast.JSONTableOnResponse)}
+	}
+
+JSONTableOnResponse:
+	"NULL"
+	{
+		$$ = &ast.JSONTableOnResponse{Tp: ast.JSONTableOnResponseNull}
+	}
+|	"ERROR"
+	{
+		$$ = &ast.JSONTableOnResponse{Tp: ast.JSONTableOnResponseError}
+	}
+|	"DEFAULT" stringLit
+	{
+		$$ = &ast.JSONTableOnResponse{Tp: ast.JSONTableOnResponseDefault, Default: $2}
+	}
+
 IndexHintType:
 	"USE" KeyOrIndex
 	{
@@ -9662,6 +9808,10 @@ SetStmt:
 	{
 		$$ = &ast.SetConfigStmt{Instance: $3, Name: $4, Value: $6}
 	}
+|	"SET" "SESSION_STATES" stringLit
+	{
+		$$ = &ast.SetSessionStatesStmt{SessionStates: $3}
+	}
 
 SetRoleStmt:
 	"SET" "ROLE" SetRoleOpt
@@ -10189,6 +10339,12 @@ AdminStmt:
 			Tp: ast.AdminFlushBindings,
 		}
 	}
+|	"ADMIN" "FLUSH" "INSTANCE" "PLAN_CACHE"
+	{
+		$$ = &ast.AdminStmt{
+			Tp: ast.AdminFlushInstancePlanCache,
+		}
+	}
 |	"ADMIN" "CAPTURE" "BINDINGS"
 	{
 		$$ = &ast.AdminStmt{
@@ -10457,6 +10613,12 @@ ShowStmt:
 			Tp: ast.ShowBuiltins,
 		}
 	}
+|	"SHOW" "SESSION_STATES"
+	{
+		$$ = &ast.ShowStmt{
+			Tp: ast.ShowSessionStates,
+		}
+	}
 |	"SHOW" "PLACEMENT" "FOR" ShowPlacementTarget
 	{
 		$$ = $4.(*ast.ShowStmt)
@@ -12371,6 +12533,13 @@ ConnectionOption:
 			Count: $2.(int64),
 		}
 	}
+|	"MAX_MEMORY_QUOTA" Int64Num
+	{
+		$$ = &ast.ResourceOption{
+			Type:  ast.MaxMemoryQuota,
+			Count: $2.(int64),
+		}
+	}
 
 RequireClauseOpt:
 	{
@@ -12459,11 +12628,6 @@ PasswordOrLockOptions:
 		$$ = l
 	}
 |	PasswordOrLockOptionList
-	{
-		$$ = $1
-		yylex.AppendError(yylex.Errorf("TiDB does not support PASSWORD EXPIRE and ACCOUNT LOCK now, they would be parsed but ignored."))
-		parser.lastErrorAsWarn()
-	}
 
 PasswordOrLockOptionList:
 	PasswordOrLockOption
@@ -12515,6 +12679,52 @@ PasswordOrLockOption:
 			Type: ast.PasswordExpireDefault,
 		}
 	}
+|	"PASSWORD" "HISTORY" "DEFAULT"
+	{
+		$$ = &ast.PasswordOrLockOption{
+			Type: ast.PasswordHistoryDefault,
+		}
+	}
+|	"PASSWORD" "HISTORY" Int64Num
+	{
+		$$ = &ast.PasswordOrLockOption{
+			Type:  ast.PasswordHistory,
+			Count: $3.(int64),
+		}
+	}
+|	"PASSWORD" "REUSE" "INTERVAL" "DEFAULT"
+	{
+		$$ = &ast.PasswordOrLockOption{
+			Type: ast.PasswordReuseDefault,
+		}
+	}
+|	"PASSWORD" "REUSE" "INTERVAL" Int64Num "DAY"
+	{
+		$$ = &ast.PasswordOrLockOption{
+			Type:  ast.PasswordReuseInterval,
+			Count: $4.(int64),
+		}
+	}
+|	"FAILED_LOGIN_ATTEMPTS" Int64Num
+	{
+		$$ = &ast.PasswordOrLockOption{
+			Type:  ast.FailedLoginAttempts,
+			Count: $2.(int64),
+		}
+	}
+|	"PASSWORD_LOCK_TIME" Int64Num
+	{
+		$$ = &ast.PasswordOrLockOption{
+			Type:  ast.PasswordLockTime,
+			Count: $2.(int64),
+		}
+	}
+|	"PASSWORD_LOCK_TIME" "UNBOUNDED"
+	{
+		$$ = &ast.PasswordOrLockOption{
+			Type: ast.PasswordLockTimeUnbounded,
+		}
+	}
 
 PasswordExpire:
 	"PASSWORD" "EXPIRE" ClearPasswordExpireOptions
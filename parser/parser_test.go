@@ -100,6 +100,7 @@ func TestSimple(t *testing.T) {
 		"following", "preceding", "unbounded", "respect", "nulls", "current", "last", "against", "expansion",
 		"chain", "error", "general", "nvarchar", "pack_keys", "p", "shard_row_id_bits", "pre_split_regions",
 		"constraints", "role", "replicas", "policy", "s3", "strict", "running", "stop", "preserve", "placement",
+		"reuse", "failed_login_attempts", "password_lock_time",
 	}
 	for _, kw := range unreservedKws {
 		src := fmt.Sprintf("SELECT %s FROM tbl;", kw)
@@ -807,6 +808,7 @@ func TestDMLStmt(t *testing.T) {
 		{"admin plugins disable audit, whitelist", true, "ADMIN PLUGINS DISABLE audit, whitelist"},
 		{"admin plugins enable audit, whitelist", true, "ADMIN PLUGINS ENABLE audit, whitelist"},
 		{"admin flush bindings", true, "ADMIN FLUSH BINDINGS"},
+		{"admin flush instance plan_cache", true, "ADMIN FLUSH INSTANCE PLAN_CACHE"},
 		{"admin capture bindings", true, "ADMIN CAPTURE BINDINGS"},
 		{"admin evolve bindings", true, "ADMIN EVOLVE BINDINGS"},
 		{"admin reload bindings", true, "ADMIN RELOAD BINDINGS"},
@@ -1242,6 +1244,13 @@ func TestDBAStmt(t *testing.T) {
 		{"show config where instance='127.0.0.1:3306'", true, "SHOW CONFIG WHERE `instance`=_UTF8MB4'127.0.0.1:3306'"},
 		{"create table CONFIG (a int)", true, "CREATE TABLE `CONFIG` (`a` INT)"}, // check that `CONFIG` is unreserved keyword
 
+		// for session states
+		{"show session_states", true, "SHOW SESSION_STATES"},
+		{"set session_states 'eyJzdGF0ZXMiOnt9fQ=='", true, "SET SESSION_STATES 'eyJzdGF0ZXMiOnt9fQ=='"},
+		{"set session_states", false, ""},
+		{"set session_states = 1", true, "SET @@SESSION.`session_states`=1"},
+		{"create table session_states (a int)", true, "CREATE TABLE `session_states` (`a` INT)"},
+
 		// for FLUSH statement
 		{"flush no_write_to_binlog tables tbl1 with read lock", true, "FLUSH NO_WRITE_TO_BINLOG TABLES `tbl1` WITH READ LOCK"},
 		{"flush table", true, "FLUSH TABLES"},
@@ -1572,6 +1581,12 @@ func TestBuiltin(t *testing.T) {
 		{"select cast(1 as float(53));", true, "SELECT CAST(1 AS DOUBLE)"},
 		{"select cast(1 as float(54));", false, ""},
 
+		// for cast as array
+		{"select cast(a->'$.b' as unsigned array) from t;", true, "SELECT CAST(JSON_EXTRACT(`a`, _UTF8MB4'$.b') AS UNSIGNED ARRAY) FROM `t`"},
+		{"select cast(a as char(10) array);", true, "SELECT CAST(`a` AS CHAR(10) ARRAY)"},
+		{"select cast(a as date array);", true, "SELECT CAST(`a` AS DATE ARRAY)"},
+		{"select convert(a, signed array);", false, ""},
+
 		{"select cast(1 as real);", true, "SELECT CAST(1 AS DOUBLE)"},
 		{"select cast('2000' as year);", true, "SELECT CAST(_UTF8MB4'2000' AS YEAR)"},
 		{"select cast(time '2000' as year);", true, "SELECT CAST(TIME '2000' AS YEAR)"},
@@ -2107,6 +2122,11 @@ func TestBuiltin(t *testing.T) {
 		{`SELECT JSON_UNQUOTE();`, true, "SELECT JSON_UNQUOTE()"},
 		{`SELECT JSON_TYPE('[123]');`, true, "SELECT JSON_TYPE(_UTF8MB4'[123]')"},
 		{`SELECT JSON_TYPE();`, true, "SELECT JSON_TYPE()"},
+		{`SELECT 1 MEMBER OF ('[1, 2]');`, true, "SELECT 1 MEMBER OF (_UTF8MB4'[1, 2]')"},
+		{`SELECT * FROM t WHERE 1 member of (a->'$.b');`, true, "SELECT * FROM `t` WHERE 1 MEMBER OF (JSON_EXTRACT(`a`, _UTF8MB4'$.b'))"},
+		{`SELECT member FROM t member WHERE member.member member of ('[1]');`, true, "SELECT `member` FROM `t` AS `member` WHERE `member`.`member` MEMBER OF (_UTF8MB4'[1]')"},
+		{`SELECT 1 MEMBER OF '[1]';`, false, ""},
+		{`SELECT JSON_OVERLAPS(a, '[1, 2]');`, true, "SELECT JSON_OVERLAPS(`a`, _UTF8MB4'[1, 2]')"},
 
 		// For two json grammar sugar.
 		{`SELECT a->'$.a' FROM t`, true, "SELECT JSON_EXTRACT(`a`, _UTF8MB4'$.a') FROM `t`"},
@@ -3798,6 +3818,60 @@ func TestOptimizerHints(t *testing.T) {
 	require.Equal(t, "t3", hints[1].Tables[0].TableName.L)
 	require.Equal(t, "t4", hints[1].Tables[1].TableName.L)
 
+	// Test LEADING
+	stmt, _, err = p.Parse("select /*+ LEADING(t3, T2), leading(t1@sel_2, t2) */ c1, c2 from t1, t2, t3 where t1.c1 = t2.c1", "", "")
+	require.NoError(t, err)
+	selectStmt = stmt[0].(*ast.SelectStmt)
+
+	hints = selectStmt.TableHints
+	require.Len(t, hints, 2)
+	require.Equal(t, "leading", hints[0].HintName.L)
+	require.Len(t, hints[0].Tables, 2)
+	require.Equal(t, "t3", hints[0].Tables[0].TableName.L)
+	require.Equal(t, "t2", hints[0].Tables[1].TableName.L)
+
+	require.Equal(t, "leading", hints[1].HintName.L)
+	require.Len(t, hints[1].Tables, 2)
+	require.Equal(t, "t1", hints[1].Tables[0].TableName.L)
+	require.Equal(t, "sel_2", hints[1].Tables[0].QBName.L)
+	require.Equal(t, "t2", hints[1].Tables[1].TableName.L)
+
+	// Test HASH_JOIN_BUILD and HASH_JOIN_PROBE
+	stmt, _, err = p.Parse("select /*+ HASH_JOIN_BUILD(t1), hash_join_probe(t2@sel_2) */ c1, c2 from t1, t2 where t1.c1 = t2.c1", "", "")
+	require.NoError(t, err)
+	selectStmt = stmt[0].(*ast.SelectStmt)
+
+	hints = selectStmt.TableHints
+	require.Len(t, hints, 2)
+	require.Equal(t, "hash_join_build", hints[0].HintName.L)
+	require.Len(t, hints[0].Tables, 1)
+	require.Equal(t, "t1", hints[0].Tables[0].TableName.L)
+
+	require.Equal(t, "hash_join_probe", hints[1].HintName.L)
+	require.Len(t, hints[1].Tables, 1)
+	require.Equal(t, "t2", hints[1].Tables[0].TableName.L)
+	require.Equal(t, "sel_2", hints[1].Tables[0].QBName.L)
+
+	// Test NO_HASH_JOIN, NO_MERGE_JOIN and NO_INDEX_JOIN
+	stmt, _, err = p.Parse("select /*+ NO_HASH_JOIN(t1, T2), no_merge_join(t3), No_Index_Join(t4) */ c1, c2 from t1, t2 where t1.c1 = t2.c1", "", "")
+	require.NoError(t, err)
+	selectStmt = stmt[0].(*ast.SelectStmt)
+
+	hints = selectStmt.TableHints
+	require.Len(t, hints, 3)
+	require.Equal(t, "no_hash_join", hints[0].HintName.L)
+	require.Len(t, hints[0].Tables, 2)
+	require.Equal(t, "t1", hints[0].Tables[0].TableName.L)
+	require.Equal(t, "t2", hints[0].Tables[1].TableName.L)
+
+	require.Equal(t, "no_merge_join", hints[1].HintName.L)
+	require.Len(t, hints[1].Tables, 1)
+	require.Equal(t, "t3", hints[1].Tables[0].TableName.L)
+
+	require.Equal(t, "no_index_join", hints[2].HintName.L)
+	require.Len(t, hints[2].Tables, 1)
+	require.Equal(t, "t4", hints[2].Tables[0].TableName.L)
+
 	// Test TIDB_HJ
 	stmt, _, err = p.Parse("select /*+ TIDB_HJ(t1, T2), tidb_hj(t3, t4) */ c1, c2 from t1, t2 where t1.c1 = t2.c1", "", "")
 	require.NoError(t, err)
@@ -4064,6 +4138,16 @@ func TestOptimizerHints(t *testing.T) {
 	require.Equal(t, "no_index_merge", hints[0].HintName.L)
 	require.Equal(t, "no_index_merge", hints[1].HintName.L)
 
+	// Test NO_DECORRELATE
+	stmt, _, err = p.Parse("select c1 from t1 where c1 in (select /*+ NO_DECORRELATE() */ c1 from t2 where t2.c2 = t1.c2)", "", "")
+	require.NoError(t, err)
+	selectStmt = stmt[0].(*ast.SelectStmt)
+	subq := selectStmt.Where.(*ast.PatternInExpr).Sel.(*ast.SubqueryExpr).Query.(*ast.SelectStmt)
+
+	hints = subq.TableHints
+	require.Len(t, hints, 1)
+	require.Equal(t, "no_decorrelate", hints[0].HintName.L)
+
 	// Test READ_CONSISTENT_REPLICA
 	stmt, _, err = p.Parse("select /*+ READ_CONSISTENT_REPLICA(), read_consistent_replica() */ c1, c2 from t1, t2 where t1.c1 = t2.c1", "", "")
 	require.NoError(t, err)
@@ -4165,6 +4249,12 @@ func TestPrivilege(t *testing.T) {
 		{"create user 'test@localhost' password expire never;", true, "CREATE USER `test@localhost`@`%` PASSWORD EXPIRE NEVER"},
 		{"create user 'test@localhost' password expire default;", true, "CREATE USER `test@localhost`@`%` PASSWORD EXPIRE DEFAULT"},
 		{"create user 'test@localhost' password expire interval 3 day;", true, "CREATE USER `test@localhost`@`%` PASSWORD EXPIRE INTERVAL 3 DAY"},
+		{"create user 'test@localhost' password history 3 password reuse interval 90 day;", true, "CREATE USER `test@localhost`@`%` PASSWORD HISTORY 3 PASSWORD REUSE INTERVAL 90 DAY"},
+		{"create user 'test@localhost' password history default password reuse interval default;", true, "CREATE USER `test@localhost`@`%` PASSWORD HISTORY DEFAULT PASSWORD REUSE INTERVAL DEFAULT"},
+		{"create user 'test@localhost' failed_login_attempts 3 password_lock_time 2;", true, "CREATE USER `test@localhost`@`%` FAILED_LOGIN_ATTEMPTS 3 PASSWORD_LOCK_TIME 2"},
+		{"create user 'test@localhost' failed_login_attempts 3 password_lock_time unbounded;", true, "CREATE USER `test@localhost`@`%` FAILED_LOGIN_ATTEMPTS 3 PASSWORD_LOCK_TIME UNBOUNDED"},
+		{"create user 'test@localhost' password reuse interval 3;", false, ""},
+		{"create user 'test@localhost' password_lock_time default;", false, ""},
 		{"CREATE USER 'sha_test'@'localhost' IDENTIFIED WITH 'caching_sha2_password' BY 'sha_test'", true, "CREATE USER `sha_test`@`localhost` IDENTIFIED WITH 'caching_sha2_password' BY 'sha_test'"},
 		{"CREATE USER 'sha_test3'@'localhost' IDENTIFIED WITH 'caching_sha2_password' AS 0x24412430303524255B03496C662C1055127B3B654A2F04207D01485276703644704B76303247474564416A516662346C5868646D32764C6B514F43585A473779565947514F34", true, "CREATE USER `sha_test3`@`localhost` IDENTIFIED WITH 'caching_sha2_password' AS '$A$005$%[\x03Ilf,\x10U\x12{;eJ/\x04 }\x01HRvp6DpKv02GGEdAjQfb4lXhdm2vLkQOCXZG7yVYGQO4'"},
 		{"CREATE USER 'sha_test4'@'localhost' IDENTIFIED WITH 'caching_sha2_password' AS '$A$005$%[\x03Ilf,\x10U\x12{;eJ/\x04 }\x01HRvp6DpKv02GGEdAjQfb4lXhdm2vLkQOCXZG7yVYGQO4'", true, "CREATE USER `sha_test4`@`localhost` IDENTIFIED WITH 'caching_sha2_password' AS '$A$005$%[\x03Ilf,\x10U\x12{;eJ/\x04 }\x01HRvp6DpKv02GGEdAjQfb4lXhdm2vLkQOCXZG7yVYGQO4'"},
@@ -4191,6 +4281,8 @@ func TestPrivilege(t *testing.T) {
 		{"alter user 'test@localhost' password expire never;", true, "ALTER USER `test@localhost`@`%` PASSWORD EXPIRE NEVER"},
 		{"alter user 'test@localhost' password expire default;", true, "ALTER USER `test@localhost`@`%` PASSWORD EXPIRE DEFAULT"},
 		{"alter user 'test@localhost' password expire interval 3 day;", true, "ALTER USER `test@localhost`@`%` PASSWORD EXPIRE INTERVAL 3 DAY"},
+		{"alter user 'test@localhost' password history 5 failed_login_attempts 0 account lock;", true, "ALTER USER `test@localhost`@`%` PASSWORD HISTORY 5 FAILED_LOGIN_ATTEMPTS 0 ACCOUNT LOCK"},
+		{"alter user 'test@localhost' password reuse interval 0 day password_lock_time unbounded;", true, "ALTER USER `test@localhost`@`%` PASSWORD REUSE INTERVAL 0 DAY PASSWORD_LOCK_TIME UNBOUNDED"},
 		{"ALTER USER 'ttt' REQUIRE X509;", true, "ALTER USER `ttt`@`%` REQUIRE X509"},
 		{"ALTER USER 'ttt' REQUIRE SSL;", true, "ALTER USER `ttt`@`%` REQUIRE SSL"},
 		{"ALTER USER 'ttt' REQUIRE NONE;", true, "ALTER USER `ttt`@`%` REQUIRE NONE"},
@@ -4199,6 +4291,8 @@ func TestPrivilege(t *testing.T) {
 		{"ALTER USER 'ttt' WITH MAX_UPDATES_PER_HOUR 2;", true, "ALTER USER `ttt`@`%` WITH MAX_UPDATES_PER_HOUR 2"},
 		{"ALTER USER 'ttt' WITH MAX_CONNECTIONS_PER_HOUR 2;", true, "ALTER USER `ttt`@`%` WITH MAX_CONNECTIONS_PER_HOUR 2"},
 		{"ALTER USER 'ttt' WITH MAX_USER_CONNECTIONS 2;", true, "ALTER USER `ttt`@`%` WITH MAX_USER_CONNECTIONS 2"},
+		{"ALTER USER 'ttt' WITH MAX_MEMORY_QUOTA 1048576;", true, "ALTER USER `ttt`@`%` WITH MAX_MEMORY_QUOTA 1048576"},
+		{"CREATE USER 'ttt' WITH MAX_USER_CONNECTIONS 2 MAX_MEMORY_QUOTA 1048576;", true, "CREATE USER `ttt`@`%` WITH MAX_USER_CONNECTIONS 2 MAX_MEMORY_QUOTA 1048576"},
 		{"ALTER USER 'ttt'@'localhost' REQUIRE NONE WITH MAX_QUERIES_PER_HOUR 1 MAX_UPDATES_PER_HOUR 10 PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK;", true, "ALTER USER `ttt`@`localhost` REQUIRE NONE WITH MAX_QUERIES_PER_HOUR 1 MAX_UPDATES_PER_HOUR 10 PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK"},
 		{`DROP USER 'root'@'localhost', 'root1'@'localhost'`, true, "DROP USER `root`@`localhost`, `root1`@`localhost`"},
 		{`DROP USER IF EXISTS 'root'@'localhost'`, true, "DROP USER IF EXISTS `root`@`localhost`"},
@@ -5197,6 +5291,30 @@ func TestAnalyze(t *testing.T) {
 	RunTest(t, table, false)
 }
 
+func TestJSONTable(t *testing.T) {
+	t.Parallel()
+	table := []testCase{
+		// positive test cases
+		{"select * from json_table('[1,2]', '$[*]' columns (a int path '$')) as jt", true, "SELECT * FROM JSON_TABLE(_UTF8MB4'[1,2]', '$[*]' COLUMNS(`a` INT PATH '$')) AS `jt`"},
+		{"select * from json_table('[1,2]', '$[*]' columns (id for ordinality, a int path '$')) jt", true, "SELECT * FROM JSON_TABLE(_UTF8MB4'[1,2]', '$[*]' COLUMNS(`id` FOR ORDINALITY, `a` INT PATH '$')) AS `jt`"},
+		{"select * from t, json_table(t.j, '$' columns (a varchar(10) exists path '$.a')) as jt", true, "SELECT * FROM (`t`) JOIN JSON_TABLE(`t`.`j`, '$' COLUMNS(`a` VARCHAR(10) EXISTS PATH '$.a')) AS `jt`"},
+		{"select * from json_table('{}', '$' columns (a int path '$.a' null on empty)) as jt", true, "SELECT * FROM JSON_TABLE(_UTF8MB4'{}', '$' COLUMNS(`a` INT PATH '$.a' NULL ON EMPTY)) AS `jt`"},
+		{"select * from json_table('{}', '$' columns (a int path '$.a' error on error)) as jt", true, "SELECT * FROM JSON_TABLE(_UTF8MB4'{}', '$' COLUMNS(`a` INT PATH '$.a' ERROR ON ERROR)) AS `jt`"},
+		{"select * from json_table('{}', '$' columns (a int path '$.a' default '1' on empty default '2' on error)) as jt", true, "SELECT * FROM JSON_TABLE(_UTF8MB4'{}', '$' COLUMNS(`a` INT PATH '$.a' DEFAULT '1' ON EMPTY DEFAULT '2' ON ERROR)) AS `jt`"},
+		{"select * from json_table('{}', '$' columns (a json path '$.a', nested path '$.b[*]' columns (b int path '$', nested '$.c' columns (c int path '$')))) as jt", true, "SELECT * FROM JSON_TABLE(_UTF8MB4'{}', '$' COLUMNS(`a` JSON PATH '$.a', NESTED PATH '$.b[*]' COLUMNS(`b` INT PATH '$', NESTED PATH '$.c' COLUMNS(`c` INT PATH '$')))) AS `jt`"},
+		{"select * from t left join json_table(t.j, '$[*]' columns (path int path '$', nested int path '$.nested', ordinality int path '$.ordinality')) as jt on true", true, "SELECT * FROM `t` LEFT JOIN JSON_TABLE(`t`.`j`, '$[*]' COLUMNS(`path` INT PATH '$', `nested` INT PATH '$.nested', `ordinality` INT PATH '$.ordinality')) AS `jt` ON TRUE"},
+		{"create table empty (nested int, path int, ordinality int)", true, "CREATE TABLE `empty` (`nested` INT,`path` INT,`ordinality` INT)"},
+
+		// negative test cases
+		{"select * from json_table('[1,2]', '$[*]' columns (a int path '$'))", false, ""},
+		{"select * from json_table('[1,2]', '$[*]' columns ()) as jt", false, ""},
+		{"select * from json_table('[1,2]', '$[*]' columns (a int path '$' error on error null on empty)) as jt", false, ""},
+		{"select * from json_table('[1,2]', '$[*]' columns (a int exists path '$' null on empty)) as jt", false, ""},
+		{"create table json_table (a int)", false, ""},
+	}
+	RunTest(t, table, false)
+}
+
 func TestTableSample(t *testing.T) {
 	t.Parallel()
 	table := []testCase{
@@ -46,6 +46,9 @@ type FieldType struct {
 	Collate string
 	// Elems is the element list for enum and set type.
 	Elems []string
+	// ArrayElem is the type of the elements if the type is a JSON array built by
+	// CAST(... AS ... ARRAY), which is used by the multi-valued index.
+	ArrayElem *FieldType `json:",omitempty"`
 }
 
 // NewFieldType returns a FieldType,
@@ -83,6 +86,9 @@ func (ft *FieldType) Equal(other *FieldType) bool {
 	if !partialEqual || len(ft.Elems) != len(other.Elems) {
 		return false
 	}
+	if (ft.ArrayElem == nil) != (other.ArrayElem == nil) || (ft.ArrayElem != nil && !ft.ArrayElem.Equal(other.ArrayElem)) {
+		return false
+	}
 	for i := range ft.Elems {
 		if ft.Elems[i] != other.Elems[i] {
 			return false
@@ -327,7 +333,12 @@ func (ft *FieldType) RestoreAsCastType(ctx *format.RestoreCtx, explicitCharset b
 			ctx.WriteKeyWord("SIGNED")
 		}
 	case mysql.TypeJSON:
-		ctx.WriteKeyWord("JSON")
+		if ft.ArrayElem != nil {
+			ft.ArrayElem.RestoreAsCastType(ctx, explicitCharset)
+			ctx.WriteKeyWord(" ARRAY")
+		} else {
+			ctx.WriteKeyWord("JSON")
+		}
 	case mysql.TypeDouble:
 		ctx.WriteKeyWord("DOUBLE")
 	case mysql.TypeFloat:
@@ -74,6 +74,17 @@ type pstmtPlanCacheKey struct {
 	timezoneOffset       int
 	isolationReadEngines map[kv.StoreType]struct{}
 	selectLimit          uint64
+	foreignKeyChecks     bool
+	// stmtText is the parameterized SQL of the statement cached by the non-prepared plan cache, or the normalized
+	// statement cached by the instance plan cache, see instancePlanCacheStmtText.
+	stmtText string
+	// stmtDB and optimizerVars are only set for the instance plan cache, stmtDB is the database when the statement is
+	// prepared, and optimizerVars encodes the session variables which affect the plans.
+	stmtDB        string
+	optimizerVars []byte
+	// limitParams are the values of the LIMIT parameters, the plans of the different LIMIT values are cached
+	// separately.
+	limitParams []uint64
 
 	hash []byte
 }
@@ -83,7 +94,7 @@ func (key *pstmtPlanCacheKey) Hash() []byte {
 	if len(key.hash) == 0 {
 		var (
 			dbBytes    = hack.Slice(key.database)
-			bufferSize = len(dbBytes) + 8*6 + 3*8
+			bufferSize = len(dbBytes) + 8*6 + 3*8 + 1
 		)
 		if key.hash == nil {
 			key.hash = make([]byte, 0, bufferSize)
@@ -104,6 +115,20 @@ func (key *pstmtPlanCacheKey) Hash() []byte {
 			key.hash = append(key.hash, kv.TiFlash.Name()...)
 		}
 		key.hash = codec.EncodeInt(key.hash, int64(key.selectLimit))
+		if key.foreignKeyChecks {
+			key.hash = append(key.hash, '1')
+		} else {
+			key.hash = append(key.hash, '0')
+		}
+		if key.optimizerVars != nil {
+			// Only the keys of the instance plan cache have the optimizer variables.
+			key.hash = codec.EncodeCompactBytes(key.hash, hack.Slice(key.stmtDB))
+			key.hash = codec.EncodeCompactBytes(key.hash, key.optimizerVars)
+		}
+		for _, param := range key.limitParams {
+			key.hash = codec.EncodeUint(key.hash, param)
+		}
+		key.hash = append(key.hash, hack.Slice(key.stmtText)...)
 	}
 	return key.hash
 }
@@ -139,6 +164,7 @@ func NewPSTMTPlanCacheKey(sessionVars *variable.SessionVars, pstmtID uint32, sch
 		timezoneOffset:       timezoneOffset,
 		isolationReadEngines: make(map[kv.StoreType]struct{}),
 		selectLimit:          sessionVars.SelectLimit,
+		foreignKeyChecks:     sessionVars.ForeignKeyChecks,
 	}
 	for k, v := range sessionVars.IsolationReadEngines {
 		key.isolationReadEngines[k] = v
@@ -146,6 +172,45 @@ func NewPSTMTPlanCacheKey(sessionVars *variable.SessionVars, pstmtID uint32, sch
 	return key
 }
 
+// NewNonPreparedPlanCacheKey creates the key of the plan cached by the non-prepared plan cache.
+func NewNonPreparedPlanCacheKey(sessionVars *variable.SessionVars, stmtText string, schemaVersion int64) kvcache.Key {
+	key := NewPSTMTPlanCacheKey(sessionVars, 0, schemaVersion).(*pstmtPlanCacheKey)
+	key.stmtText = stmtText
+	return key
+}
+
+// NewInstancePlanCacheKey creates the key of the plan cached by the instance plan cache. The plans of the same
+// statement are shared by the sessions whose variables affecting the plans are the same.
+func NewInstancePlanCacheKey(sessionVars *variable.SessionVars, stmt *CachedPrepareStmt) kvcache.Key {
+	key := NewPSTMTPlanCacheKey(sessionVars, 0, stmt.PreparedAst.SchemaVersion).(*pstmtPlanCacheKey)
+	key.connID = 0
+	if len(stmt.instanceCacheStmtText) == 0 {
+		stmt.instanceCacheStmtText = instancePlanCacheStmtText(stmt)
+	}
+	key.stmtText = stmt.instanceCacheStmtText
+	key.stmtDB = stmt.StmtDB
+	for _, on := range []bool{
+		sessionVars.AllowAggPushDown,
+		sessionVars.AllowDistinctAggPushDown,
+		sessionVars.GetEnableIndexMerge(),
+		sessionVars.EnableCascadesPlanner,
+		sessionVars.IsMPPAllowed(),
+		sessionVars.IsMPPEnforced(),
+	} {
+		if on {
+			key.optimizerVars = append(key.optimizerVars, '1')
+		} else {
+			key.optimizerVars = append(key.optimizerVars, '0')
+		}
+	}
+	key.optimizerVars = append(key.optimizerVars, sessionVars.PartitionPruneMode.Load()...)
+	for _, name := range instancePlanCacheVars {
+		val, _ := sessionVars.GetSystemVar(name)
+		key.optimizerVars = codec.EncodeCompactBytes(key.optimizerVars, hack.Slice(val))
+	}
+	return key
+}
+
 // FieldSlice is the slice of the types.FieldType
 type FieldSlice []types.FieldType
 
@@ -209,4 +274,16 @@ type CachedPrepareStmt struct {
 	PlanDigest          *parser.Digest
 	ForUpdateRead       bool
 	SnapshotTSEvaluator func(sessionctx.Context) (uint64, error)
+	// StmtText and StmtDB are used to prepare the statement again when the session is migrated.
+	StmtText string
+	StmtDB   string
+	// ParameterizedSQL is the SQL text whose literals are replaced by the parameter markers, it's only set for the
+	// statements cached by the non-prepared plan cache.
+	ParameterizedSQL string
+	// LimitParams are the parameter markers in the LIMIT clauses, whose values are part of the plan cache key.
+	LimitParams []ast.ParamMarkerExpr
+
+	// instanceCacheStmtText is the statement text in the key of the instance plan cache, it's built at the first time
+	// the statement is executed.
+	instanceCacheStmtText string
 }
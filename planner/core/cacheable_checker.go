@@ -15,6 +15,8 @@
 package core
 
 import (
+	"fmt"
+
 	"github.com/pingcap/tidb/expression"
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/parser/ast"
@@ -34,13 +36,19 @@ func Cacheable(node ast.Node, is infoschema.InfoSchema) bool {
 // Handle "ignore_plan_cache()" hint
 // If there are multiple hints, only one will take effect
 func CacheableWithCtx(sctx sessionctx.Context, node ast.Node, is infoschema.InfoSchema) bool {
+	cacheable, _ := CacheableWithReason(sctx, node, is)
+	return cacheable
+}
+
+// CacheableWithReason checks whether the input ast is cacheable, and returns the reason if it's not.
+func CacheableWithReason(sctx sessionctx.Context, node ast.Node, is infoschema.InfoSchema) (bool, string) {
 	_, isSelect := node.(*ast.SelectStmt)
 	_, isUpdate := node.(*ast.UpdateStmt)
 	_, isInsert := node.(*ast.InsertStmt)
 	_, isDelete := node.(*ast.DeleteStmt)
 	_, isSetOpr := node.(*ast.SetOprStmt)
 	if !(isSelect || isUpdate || isInsert || isDelete || isSetOpr) {
-		return false
+		return false, "not a SELECT, UPDATE, INSERT, DELETE or set operation statement"
 	}
 	checker := cacheableChecker{
 		sctx:      sctx,
@@ -48,18 +56,58 @@ func CacheableWithCtx(sctx sessionctx.Context, node ast.Node, is infoschema.Info
 		schema:    is,
 	}
 	node.Accept(&checker)
-	return checker.cacheable
+	return checker.cacheable, checker.reason
+}
+
+// ExtractLimitParams returns the parameter markers in the LIMIT clauses of the statement. The plans of the different
+// LIMIT values are cached separately because the values decide the shape of the plan.
+func ExtractLimitParams(node ast.Node) []ast.ParamMarkerExpr {
+	var extractor limitParamExtractor
+	node.Accept(&extractor)
+	return extractor.params
+}
+
+type limitParamExtractor struct {
+	params []ast.ParamMarkerExpr
+}
+
+// Enter implements Visitor interface.
+func (e *limitParamExtractor) Enter(in ast.Node) (out ast.Node, skipChildren bool) {
+	if limit, ok := in.(*ast.Limit); ok {
+		for _, expr := range []ast.ExprNode{limit.Count, limit.Offset} {
+			if param, ok := expr.(*driver.ParamMarkerExpr); ok {
+				e.params = append(e.params, param)
+			}
+		}
+	}
+	return in, false
+}
+
+// Leave implements Visitor interface.
+func (e *limitParamExtractor) Leave(in ast.Node) (out ast.Node, ok bool) {
+	return in, true
 }
 
 // cacheableChecker checks whether a query's plan can be cached, querys that:
-//	 1. have ExistsSubqueryExpr, or
-//	 2. have VariableExpr
-// will not be cached currently.
+//	 1. have VariableExpr, or
+//	 2. have un-cacheable functions, or
+//	 3. have parameterized ORDER BY, GROUP BY or window frame bounds, or
+//	 4. access temporary tables
+// will not be cached currently. The uncorrelated subqueries are not evaluated when the plan is built for the plan
+// cache, the partitions of the tables in static prune mode are pruned again when the plan is executed, and the
+// values of the LIMIT parameters are part of the cache key.
 // NOTE: we can add more rules in the future.
 type cacheableChecker struct {
 	sctx      sessionctx.Context
 	cacheable bool
 	schema    infoschema.InfoSchema
+	// reason is the reason why the statement can't be cached.
+	reason string
+}
+
+func (checker *cacheableChecker) setUncacheable(reason string) {
+	checker.cacheable = false
+	checker.reason = reason
 }
 
 // Enter implements Visitor interface.
@@ -68,79 +116,55 @@ func (checker *cacheableChecker) Enter(in ast.Node) (out ast.Node, skipChildren
 	case *ast.SelectStmt:
 		for _, hints := range node.TableHints {
 			if hints.HintName.L == HintIgnorePlanCache {
-				checker.cacheable = false
+				checker.setUncacheable("ignore_plan_cache() hint is used")
 				return in, true
 			}
 		}
 	case *ast.DeleteStmt:
 		for _, hints := range node.TableHints {
 			if hints.HintName.L == HintIgnorePlanCache {
-				checker.cacheable = false
+				checker.setUncacheable("ignore_plan_cache() hint is used")
 				return in, true
 			}
 		}
 	case *ast.UpdateStmt:
 		for _, hints := range node.TableHints {
 			if hints.HintName.L == HintIgnorePlanCache {
-				checker.cacheable = false
+				checker.setUncacheable("ignore_plan_cache() hint is used")
 				return in, true
 			}
 		}
-	case *ast.VariableExpr, *ast.ExistsSubqueryExpr, *ast.SubqueryExpr:
-		checker.cacheable = false
+	case *ast.VariableExpr:
+		checker.setUncacheable("query has variables")
 		return in, true
 	case *ast.FuncCallExpr:
 		if _, found := expression.UnCacheableFunctions[node.FnName.L]; found {
-			checker.cacheable = false
+			checker.setUncacheable(fmt.Sprintf("query has '%s' function", node.FnName.L))
 			return in, true
 		}
 	case *ast.OrderByClause:
 		for _, item := range node.Items {
 			if _, isParamMarker := item.Expr.(*driver.ParamMarkerExpr); isParamMarker {
-				checker.cacheable = false
+				checker.setUncacheable("query has 'order by ?'")
 				return in, true
 			}
 		}
 	case *ast.GroupByClause:
 		for _, item := range node.Items {
 			if _, isParamMarker := item.Expr.(*driver.ParamMarkerExpr); isParamMarker {
-				checker.cacheable = false
-				return in, true
-			}
-		}
-	case *ast.Limit:
-		if node.Count != nil {
-			if _, isParamMarker := node.Count.(*driver.ParamMarkerExpr); isParamMarker {
-				checker.cacheable = false
-				return in, true
-			}
-		}
-		if node.Offset != nil {
-			if _, isParamMarker := node.Offset.(*driver.ParamMarkerExpr); isParamMarker {
-				checker.cacheable = false
+				checker.setUncacheable("query has 'group by ?'")
 				return in, true
 			}
 		}
 	case *ast.FrameBound:
 		if _, ok := node.Expr.(*driver.ParamMarkerExpr); ok {
-			checker.cacheable = false
+			checker.setUncacheable("query has '? preceding/following' in the window frame")
 			return in, true
 		}
 	case *ast.TableName:
 		if checker.schema != nil {
-			if checker.isPartitionTable(node) {
-				if checker.sctx != nil && checker.sctx.GetSessionVars().UseDynamicPartitionPrune() {
-					return in, false // dynamic-mode for partition tables can use plan-cache
-				}
-				checker.cacheable = false
-				return in, true
-			}
-			if checker.hasGeneratedCol(node) {
-				checker.cacheable = false
-				return in, true
-			}
 			if checker.isTempTable(node) {
-				checker.cacheable = false
+				checker.setUncacheable("query accesses temporary tables")
 				return in, true
 			}
 		}
@@ -148,20 +172,6 @@ func (checker *cacheableChecker) Enter(in ast.Node) (out ast.Node, skipChildren
 	return in, false
 }
 
-func (checker *cacheableChecker) hasGeneratedCol(tn *ast.TableName) bool {
-	tb, err := checker.schema.TableByName(tn.Schema, tn.Name)
-	if err != nil {
-		logutil.BgLogger().Error("Error occur in checking cacheable", zap.Error(err))
-		return false
-	}
-	for _, col := range tb.Cols() {
-		if col.IsGenerated() {
-			return true
-		}
-	}
-	return false
-}
-
 func (checker *cacheableChecker) isTempTable(tn *ast.TableName) bool {
 	tb, err := checker.schema.TableByName(tn.Schema, tn.Name)
 	if err != nil {
@@ -174,18 +184,6 @@ func (checker *cacheableChecker) isTempTable(tn *ast.TableName) bool {
 	return false
 }
 
-func (checker *cacheableChecker) isPartitionTable(tn *ast.TableName) bool {
-	tb, err := checker.schema.TableByName(tn.Schema, tn.Name)
-	if err != nil {
-		logutil.BgLogger().Error("Error occur in checking cacheable", zap.Error(err))
-		return false
-	}
-	if tb.Meta().GetPartitionInfo() != nil {
-		return true
-	}
-	return false
-}
-
 // Leave implements Visitor interface.
 func (checker *cacheableChecker) Leave(in ast.Node) (out ast.Node, ok bool) {
 	return in, checker.cacheable
@@ -224,6 +224,17 @@ func (e *Execute) OptimizePreparedPlan(ctx context.Context, sctx sessionctx.Cont
 	if !ok {
 		return errors.Errorf("invalid CachedPrepareStmt type")
 	}
+	return e.optimizeCachedPrepareStmt(ctx, sctx, is, preparedObj)
+}
+
+// OptimizeNonPreparedPlan optimizes the statement cached by the non-prepared plan cache, the parameters are set
+// in e.PrepareParams.
+func (e *Execute) OptimizeNonPreparedPlan(ctx context.Context, sctx sessionctx.Context, is infoschema.InfoSchema, preparedObj *CachedPrepareStmt) error {
+	return e.optimizeCachedPrepareStmt(ctx, sctx, is, preparedObj)
+}
+
+func (e *Execute) optimizeCachedPrepareStmt(ctx context.Context, sctx sessionctx.Context, is infoschema.InfoSchema, preparedObj *CachedPrepareStmt) error {
+	vars := sctx.GetSessionVars()
 	prepared := preparedObj.PreparedAst
 	vars.StmtCtx.StmtType = prepared.StmtType
 
@@ -387,7 +398,7 @@ func (e *Execute) getPhysicalPlan(ctx context.Context, sctx sessionctx.Context,
 	stmtCtx.UseCache = prepared.UseCache
 	var cacheKey kvcache.Key
 	if prepared.UseCache {
-		cacheKey = NewPSTMTPlanCacheKey(sctx.GetSessionVars(), e.ExecID, prepared.SchemaVersion)
+		cacheKey = e.newPlanCacheKey(sctx, preparedStmt)
 	}
 	tps := make([]*types.FieldType, len(e.UsingVars))
 	for i, param := range e.UsingVars {
@@ -397,6 +408,14 @@ func (e *Execute) getPhysicalPlan(ctx context.Context, sctx sessionctx.Context,
 			tps[i] = types.NewFieldType(mysql.TypeNull)
 		}
 	}
+	if len(preparedStmt.ParameterizedSQL) > 0 {
+		// The plans of the parameterized statements are cached by the types of the literals.
+		tps = make([]*types.FieldType, len(e.PrepareParams))
+		for i, param := range e.PrepareParams {
+			tps[i] = types.NewFieldType(mysql.TypeUnspecified)
+			types.DefaultParamTypeForValue(param.GetValue(), tps[i])
+		}
+	}
 	if prepared.CachedPlan != nil {
 		// Rewriting the expression in the select.where condition  will convert its
 		// type from "paramMarker" to "Constant".When Point Select queries are executed,
@@ -423,6 +442,12 @@ func (e *Execute) getPhysicalPlan(ctx context.Context, sctx sessionctx.Context,
 		stmtCtx.PointExec = true
 		return nil
 	}
+	if prepared.UseCache && InstancePlanCacheEnabled() {
+		found, err := e.getPlanFromInstancePlanCache(ctx, sctx, is, preparedStmt, tps)
+		if err != nil || found {
+			return err
+		}
+	}
 	if prepared.UseCache {
 		if cacheValue, exists := sctx.PreparedPlanCache().Get(cacheKey); exists {
 			if err := e.checkPreparedPriv(ctx, sctx, preparedStmt, is); err != nil {
@@ -481,16 +506,33 @@ REBUILD:
 	e.names = names
 	e.Plan = p
 	_, isTableDual := p.(*PhysicalTableDual)
+	if prepared.UseCache && (isTableDual || stmtCtx.MaybeOverOptimized4PlanCache) {
+		reason := stmtCtx.SkipPlanCacheReason
+		if isTableDual {
+			reason = "get a TableDual plan"
+		} else if reason == "" {
+			reason = "the plan is optimized by the values of the parameters"
+		}
+		stmtCtx.AppendWarning(errors.Errorf("skip plan-cache: %s", reason))
+	}
 	if !isTableDual && prepared.UseCache && !stmtCtx.MaybeOverOptimized4PlanCache {
 		// rebuild key to exclude kv.TiFlash when stmt is not read only
 		if _, isolationReadContainTiFlash := sessVars.IsolationReadEngines[kv.TiFlash]; isolationReadContainTiFlash && !IsReadOnly(stmt, sessVars) {
 			delete(sessVars.IsolationReadEngines, kv.TiFlash)
-			cacheKey = NewPSTMTPlanCacheKey(sctx.GetSessionVars(), e.ExecID, prepared.SchemaVersion)
+			cacheKey = e.newPlanCacheKey(sctx, preparedStmt)
 			sessVars.IsolationReadEngines[kv.TiFlash] = struct{}{}
 		}
-		cached := NewPSTMTPlanCacheValue(p, names, stmtCtx.TblInfo2UnionScan, tps)
 		preparedStmt.NormalizedPlan, preparedStmt.PlanDigest = NormalizePlan(p)
 		stmtCtx.SetPlanDigest(preparedStmt.NormalizedPlan, preparedStmt.PlanDigest)
+		if InstancePlanCacheEnabled() {
+			// The plans which can't be shared by the sessions are still cached by the session.
+			if cached := newInstancePlanCacheValue(e.newInstancePlanCacheKey(sctx, preparedStmt), preparedStmt, p, names,
+				stmtCtx.TblInfo2UnionScan, tps); cached != nil {
+				globalInstancePlanCache.put(cached)
+				return e.setFoundInPlanCache(sctx, false)
+			}
+		}
+		cached := NewPSTMTPlanCacheValue(p, names, stmtCtx.TblInfo2UnionScan, tps)
 		if cacheVals, exists := sctx.PreparedPlanCache().Get(cacheKey); exists {
 			hitVal := false
 			for i, cacheVal := range cacheVals.([]*PSTMTPlanCacheValue) {
@@ -512,11 +554,78 @@ REBUILD:
 	return err
 }
 
+// getPlanFromInstancePlanCache gets the plan from the instance plan cache, the cached plan is cloned for the session.
+func (e *Execute) getPlanFromInstancePlanCache(ctx context.Context, sctx sessionctx.Context, is infoschema.InfoSchema,
+	preparedStmt *CachedPrepareStmt, tps []*types.FieldType) (bool, error) {
+	cached := globalInstancePlanCache.get(string(e.newInstancePlanCacheKey(sctx, preparedStmt).Hash()), tps)
+	if cached == nil {
+		return false, nil
+	}
+	if err := e.checkPreparedPriv(ctx, sctx, preparedStmt, is); err != nil {
+		return false, err
+	}
+	for tblInfo, unionScan := range cached.TblInfo2UnionScan {
+		if !unionScan && tableHasDirtyContent(sctx, tblInfo) {
+			// The plan is still valid for the other sessions.
+			return false, nil
+		}
+	}
+	plan := cached.clone(sctx, preparedStmt.PreparedAst.Params)
+	if err := e.rebuildRange(plan); err != nil {
+		logutil.BgLogger().Debug("rebuild range failed", zap.Error(err))
+		return false, nil
+	}
+	if err := e.setFoundInPlanCache(sctx, true); err != nil {
+		return false, err
+	}
+	if metrics.ResettablePlanCacheCounterFortTest {
+		metrics.PlanCacheCounter.WithLabelValues("prepare").Inc()
+	} else {
+		planCacheCounter.Inc()
+	}
+	e.names = cached.OutPutNames
+	e.Plan = plan
+	sctx.GetSessionVars().StmtCtx.SetPlanDigest(cached.normalizedPlan, cached.planDigest)
+	return true, nil
+}
+
+func (e *Execute) newInstancePlanCacheKey(sctx sessionctx.Context, preparedStmt *CachedPrepareStmt) kvcache.Key {
+	key := NewInstancePlanCacheKey(sctx.GetSessionVars(), preparedStmt)
+	key.(*pstmtPlanCacheKey).limitParams = limitParamValues(sctx, preparedStmt)
+	return key
+}
+
+// limitParamValues returns the values of the LIMIT parameters, the invalid values are reported when the plan is built.
+func limitParamValues(sctx sessionctx.Context, preparedStmt *CachedPrepareStmt) []uint64 {
+	if len(preparedStmt.LimitParams) == 0 {
+		return nil
+	}
+	values := make([]uint64, 0, len(preparedStmt.LimitParams))
+	for _, param := range preparedStmt.LimitParams {
+		val, _, _ := getUintFromNode(sctx, param)
+		values = append(values, val)
+	}
+	return values
+}
+
+func (e *Execute) newPlanCacheKey(sctx sessionctx.Context, preparedStmt *CachedPrepareStmt) kvcache.Key {
+	sessVars := sctx.GetSessionVars()
+	if len(preparedStmt.ParameterizedSQL) > 0 {
+		return NewNonPreparedPlanCacheKey(sessVars, preparedStmt.ParameterizedSQL, preparedStmt.PreparedAst.SchemaVersion)
+	}
+	key := NewPSTMTPlanCacheKey(sessVars, e.ExecID, preparedStmt.PreparedAst.SchemaVersion)
+	key.(*pstmtPlanCacheKey).limitParams = limitParamValues(sctx, preparedStmt)
+	return key
+}
+
 // tryCachePointPlan will try to cache point execution plan, there may be some
 // short paths for these executions, currently "point select" and "point update"
 func (e *Execute) tryCachePointPlan(ctx context.Context, sctx sessionctx.Context,
 	preparedStmt *CachedPrepareStmt, is infoschema.InfoSchema, p Plan) error {
-	if sctx.GetSessionVars().StmtCtx.MaybeOverOptimized4PlanCache {
+	// The point plan short path doesn't check the types of the parameters, so it's not used by the non-prepared
+	// plan cache, whose statements are already tried by the fast plan. It doesn't check the LIMIT values either.
+	if sctx.GetSessionVars().StmtCtx.MaybeOverOptimized4PlanCache || len(preparedStmt.ParameterizedSQL) > 0 ||
+		len(preparedStmt.LimitParams) > 0 {
 		return nil
 	}
 	var (
@@ -614,12 +723,6 @@ func (e *Execute) rebuildRange(p Plan) error {
 				}
 			}
 		}
-		// The code should never run here as long as we're not using point get for partition table.
-		// And if we change the logic one day, here work as defensive programming to cache the error.
-		if x.PartitionInfo != nil {
-			// TODO: relocate the partition after rebuilding range to make PlanCache support PointGet
-			return errors.New("point get for partition table can not use plan cache")
-		}
 		if x.HandleParam != nil {
 			var iv int64
 			iv, err = x.HandleParam.Datum.ToInt64(sc)
@@ -627,13 +730,16 @@ func (e *Execute) rebuildRange(p Plan) error {
 				return err
 			}
 			x.Handle = kv.IntHandle(iv)
-			return nil
 		}
 		for i, param := range x.IndexValueParams {
 			if param != nil {
 				x.IndexValues[i] = param.Datum
 			}
 		}
+		// The partition is located by the new handle or index values.
+		if x.PartitionInfo != nil {
+			return x.relocatePartition(sctx)
+		}
 		return nil
 	case *BatchPointGetPlan:
 		// if access condition is not nil, which means it's a point get generated by cbo.
@@ -890,6 +996,9 @@ type Insert struct {
 	AllAssignmentsAreConstant bool
 
 	RowLen int
+
+	FKChecks   []*FKCheck
+	FKCascades []*FKCascade
 }
 
 // Update represents Update plan.
@@ -911,6 +1020,9 @@ type Update struct {
 	PartitionedTable []table.PartitionedTable
 
 	tblID2Table map[int64]table.Table
+
+	FKChecks   map[int64][]*FKCheck
+	FKCascades map[int64][]*FKCascade
 }
 
 // Delete represents a delete plan.
@@ -922,6 +1034,9 @@ type Delete struct {
 	SelectPlan PhysicalPlan
 
 	TblColPosInfos TblColPosInfoSlice
+
+	FKChecks   map[int64][]*FKCheck
+	FKCascades map[int64][]*FKCascade
 }
 
 // AnalyzeInfo is used to store the database name, table name and partition name of analyze task.
@@ -1257,17 +1372,18 @@ func (e *Explain) explainPlanInRowFormat(p Plan, taskType, driverSide, indent st
 		}
 		err = e.explainPlanInRowFormat(x.tablePlan, "cop[tikv]", "(Probe)", childIndent, true)
 	case *Insert:
-		if x.SelectPlan != nil {
-			err = e.explainPlanInRowFormat(x.SelectPlan, "root", "", childIndent, true)
+		fkPlans := make([]Plan, 0, len(x.FKChecks)+len(x.FKCascades))
+		for _, fkc := range x.FKChecks {
+			fkPlans = append(fkPlans, fkc)
 		}
-	case *Update:
-		if x.SelectPlan != nil {
-			err = e.explainPlanInRowFormat(x.SelectPlan, "root", "", childIndent, true)
+		for _, fkc := range x.FKCascades {
+			fkPlans = append(fkPlans, fkc)
 		}
+		err = e.explainDMLChildren(x.SelectPlan, fkPlans, childIndent)
+	case *Update:
+		err = e.explainDMLChildren(x.SelectPlan, x.fkPlans(), childIndent)
 	case *Delete:
-		if x.SelectPlan != nil {
-			err = e.explainPlanInRowFormat(x.SelectPlan, "root", "", childIndent, true)
-		}
+		err = e.explainDMLChildren(x.SelectPlan, x.fkPlans(), childIndent)
 	case *Execute:
 		if x.Plan != nil {
 			err = e.explainPlanInRowFormat(x.Plan, "root", "", indent, true)
@@ -1318,6 +1434,23 @@ func getRuntimeInfo(ctx sessionctx.Context, p Plan, runtimeStatsColl *execdetail
 	return
 }
 
+// explainDMLChildren explains the select plan and the foreign key plans of the DML plan.
+func (e *Explain) explainDMLChildren(selectPlan PhysicalPlan, fkPlans []Plan, childIndent string) error {
+	if selectPlan != nil {
+		err := e.explainPlanInRowFormat(selectPlan, "root", "", childIndent, len(fkPlans) == 0)
+		if err != nil {
+			return err
+		}
+	}
+	for i, fkPlan := range fkPlans {
+		err := e.explainPlanInRowFormat(fkPlan, "root", "", childIndent, i == len(fkPlans)-1)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // prepareOperatorInfo generates the following information for every plan:
 // operator id, estimated rows, task type, access object and other operator info.
 func (e *Explain) prepareOperatorInfo(p Plan, taskType, driverSide, indent string, isLastChild bool) {
@@ -105,6 +105,7 @@ var (
 	ErrOptOnCacheTable           = dbterror.ClassOptimizer.NewStd(mysql.ErrOptOnCacheTable)
 	ErrDropTableOnTemporaryTable = dbterror.ClassOptimizer.NewStd(mysql.ErrDropTableOnTemporaryTable)
 	// ErrPartitionNoTemporary returns when partition at temporary mode
-	ErrPartitionNoTemporary     = dbterror.ClassOptimizer.NewStd(mysql.ErrPartitionNoTemporary)
-	ErrViewSelectTemporaryTable = dbterror.ClassOptimizer.NewStd(mysql.ErrViewSelectTmptable)
+	ErrPartitionNoTemporary       = dbterror.ClassOptimizer.NewStd(mysql.ErrPartitionNoTemporary)
+	ErrViewSelectTemporaryTable   = dbterror.ClassOptimizer.NewStd(mysql.ErrViewSelectTmptable)
+	ErrJSONTableForbiddenJoinType = dbterror.ClassOptimizer.NewStd(mysql.ErrJSONTableForbiddenJoinType)
 )
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"math"
 	"sort"
+	"strings"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
@@ -366,30 +367,54 @@ func (p *LogicalJoin) getHashJoins(prop *property.PhysicalProperty) []PhysicalPl
 	if !prop.IsEmpty() { // hash join doesn't promise any orders
 		return nil
 	}
+	forceLeftToBuild := (p.preferJoinType & (preferLeftAsHJBuild | preferRightAsHJProbe)) > 0
+	forceRightToBuild := (p.preferJoinType & (preferRightAsHJBuild | preferLeftAsHJProbe)) > 0
+	if forceLeftToBuild && forceRightToBuild {
+		p.ctx.GetSessionVars().StmtCtx.AppendWarning(ErrInternal.GenWithStack(
+			"Optimizer Hints HASH_JOIN_BUILD and HASH_JOIN_PROBE are conflict, both sides of the join are specified as the build side"))
+		forceLeftToBuild, forceRightToBuild = false, false
+	}
 	joins := make([]PhysicalPlan, 0, 2)
 	switch p.JoinType {
 	case SemiJoin, AntiSemiJoin, LeftOuterSemiJoin, AntiLeftOuterSemiJoin:
+		// The hash table of the semi joins is always built by the inner side.
+		if forceLeftToBuild {
+			p.ctx.GetSessionVars().StmtCtx.AppendWarning(ErrInternal.GenWithStack(
+				"Optimizer Hint HASH_JOIN_BUILD or HASH_JOIN_PROBE is inapplicable, the outer side of %s can't be the build side", p.JoinType))
+		}
 		joins = append(joins, p.getHashJoin(prop, 1, false))
 	case LeftOuterJoin:
 		if ForceUseOuterBuild4Test {
 			joins = append(joins, p.getHashJoin(prop, 1, true))
 		} else {
-			joins = append(joins, p.getHashJoin(prop, 1, false))
-			joins = append(joins, p.getHashJoin(prop, 1, true))
+			if !forceLeftToBuild {
+				joins = append(joins, p.getHashJoin(prop, 1, false))
+			}
+			if !forceRightToBuild {
+				joins = append(joins, p.getHashJoin(prop, 1, true))
+			}
 		}
 	case RightOuterJoin:
 		if ForceUseOuterBuild4Test {
 			joins = append(joins, p.getHashJoin(prop, 0, true))
 		} else {
-			joins = append(joins, p.getHashJoin(prop, 0, false))
-			joins = append(joins, p.getHashJoin(prop, 0, true))
+			if !forceRightToBuild {
+				joins = append(joins, p.getHashJoin(prop, 0, false))
+			}
+			if !forceLeftToBuild {
+				joins = append(joins, p.getHashJoin(prop, 0, true))
+			}
 		}
 	case InnerJoin:
 		if ForcedHashLeftJoin4Test {
 			joins = append(joins, p.getHashJoin(prop, 1, false))
 		} else {
-			joins = append(joins, p.getHashJoin(prop, 1, false))
-			joins = append(joins, p.getHashJoin(prop, 0, false))
+			if !forceLeftToBuild {
+				joins = append(joins, p.getHashJoin(prop, 1, false))
+			}
+			if !forceRightToBuild {
+				joins = append(joins, p.getHashJoin(prop, 0, false))
+			}
 		}
 	}
 	return joins
@@ -1764,7 +1789,8 @@ func (p *LogicalJoin) exhaustPhysicalPlans(prop *property.PhysicalProperty) ([]P
 		}
 	})
 
-	if (p.preferJoinType&preferBCJoin) == 0 && p.preferJoinType > 0 {
+	// NO_MERGE_JOIN and NO_INDEX_JOIN never prohibit the MPP joins.
+	if (p.preferJoinType&preferBCJoin) == 0 && (p.preferJoinType&^(preferNoMergeJoin|preferNoIndexJoin)) > 0 {
 		p.SCtx().GetSessionVars().RaiseWarningWhenMPPEnforced("MPP mode may be blocked because you have used hint to specify a join algorithm which is not supported by mpp now.")
 		if prop.IsFlashProp() {
 			return nil, false, nil
@@ -1774,7 +1800,10 @@ func (p *LogicalJoin) exhaustPhysicalPlans(prop *property.PhysicalProperty) ([]P
 		return nil, false, nil
 	}
 	joins := make([]PhysicalPlan, 0, 8)
-	canPushToTiFlash := p.canPushToCop(kv.TiFlash)
+	// The joins prohibited by the NO_XXX_JOIN hints are only used when all the join algorithms are prohibited.
+	var prohibitedJoins []PhysicalPlan
+	// The MPP joins are hash joins, so they are prohibited by NO_HASH_JOIN too.
+	canPushToTiFlash := p.canPushToCop(kv.TiFlash) && (p.preferJoinType&preferNoHashJoin) == 0
 	if p.ctx.GetSessionVars().IsMPPAllowed() && canPushToTiFlash {
 		if p.shouldUseMPPBCJ() {
 			mppJoins := p.tryToGetMppHashJoin(prop, true)
@@ -1801,21 +1830,52 @@ func (p *LogicalJoin) exhaustPhysicalPlans(prop *property.PhysicalProperty) ([]P
 	if (p.preferJoinType&preferMergeJoin) > 0 && len(mergeJoins) > 0 {
 		return mergeJoins, true, nil
 	}
-	joins = append(joins, mergeJoins...)
+	if (p.preferJoinType & preferNoMergeJoin) > 0 {
+		prohibitedJoins = append(prohibitedJoins, mergeJoins...)
+	} else {
+		joins = append(joins, mergeJoins...)
+	}
 
 	indexJoins, forced := p.tryToGetIndexJoin(prop)
 	if forced {
 		return indexJoins, true, nil
 	}
-	joins = append(joins, indexJoins...)
+	if (p.preferJoinType & preferNoIndexJoin) > 0 {
+		prohibitedJoins = append(prohibitedJoins, indexJoins...)
+	} else {
+		joins = append(joins, indexJoins...)
+	}
 
 	hashJoins := p.getHashJoins(prop)
-	if (p.preferJoinType&preferHashJoin) > 0 && len(hashJoins) > 0 {
+	if (p.preferJoinType&(preferHashJoin|preferHJSideMask)) > 0 && len(hashJoins) > 0 {
 		return hashJoins, true, nil
 	}
-	joins = append(joins, hashJoins...)
-
-	if p.preferJoinType > 0 {
+	if (p.preferJoinType & preferNoHashJoin) > 0 {
+		prohibitedJoins = append(prohibitedJoins, hashJoins...)
+	} else {
+		joins = append(joins, hashJoins...)
+	}
+
+	if len(joins) == 0 && len(prohibitedJoins) > 0 {
+		if !prop.IsEmpty() {
+			// The prohibited joins may be avoided by enforcing the property, so we try the hints again.
+			return joins, false, nil
+		}
+		// All the join algorithms are prohibited, so we ignore the NO_XXX_JOIN hints to make sure we can get a plan.
+		hintNames := make([]string, 0, 3)
+		for _, h := range []struct {
+			prefer uint
+			name   string
+		}{{preferNoHashJoin, HintNoHashJoin}, {preferNoMergeJoin, HintNoMergeJoin}, {preferNoIndexJoin, HintNoIndexJoin}} {
+			if (p.preferJoinType & h.prefer) > 0 {
+				hintNames = append(hintNames, strings.ToUpper(h.name))
+			}
+		}
+		errMsg := fmt.Sprintf("Optimizer Hint %s is inapplicable because all the join algorithms are prohibited", strings.Join(hintNames, ", "))
+		p.ctx.GetSessionVars().StmtCtx.AppendWarning(ErrInternal.GenWithStack(errMsg))
+		joins = prohibitedJoins
+	}
+	if (p.preferJoinType &^ preferNoJoinMask) > 0 {
 		// If we reach here, it means we have a hint that doesn't work.
 		// It might be affected by the required property, so we enforce
 		// this property and try the hint again.
@@ -2260,6 +2320,7 @@ func (la *LogicalApply) exhaustPhysicalPlans(prop *property.PhysicalProperty) ([
 		PhysicalHashJoin: *join,
 		OuterSchema:      la.CorCols,
 		CanUseCache:      canUseCache,
+		NoDecorrelate:    la.NoDecorrelate,
 	}.Init(la.ctx,
 		la.stats.ScaleByExpectCnt(prop.ExpectedCnt),
 		la.blockOffset,
@@ -2782,6 +2843,7 @@ func (p *LogicalPartitionUnionAll) exhaustPhysicalPlans(prop *property.PhysicalP
 	}
 	for _, ua := range uas {
 		ua.(*PhysicalUnionAll).tp = plancodec.TypePartitionUnion
+		ua.(*PhysicalUnionAll).DeferredPruning = p.deferredPruning
 	}
 	return uas, flagHint, nil
 }
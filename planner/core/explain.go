@@ -501,6 +501,16 @@ func (p *PhysicalTableDual) ExplainInfo() string {
 	return str.String()
 }
 
+// ExplainInfo implements Plan interface.
+func (p *PhysicalJSONTable) ExplainInfo() string {
+	return "doc:" + p.Doc.ExplainInfo() + ", path:" + p.Path
+}
+
+// ExplainNormalizedInfo implements Plan interface.
+func (p *PhysicalJSONTable) ExplainNormalizedInfo() string {
+	return "doc:" + p.Doc.ExplainNormalizedInfo() + ", path:" + p.Path
+}
+
 // ExplainInfo implements Plan interface.
 func (p *PhysicalSort) ExplainInfo() string {
 	buffer := bytes.NewBufferString("")
@@ -905,6 +915,11 @@ func (p *LogicalApply) ExplainInfo() string {
 	return p.LogicalJoin.ExplainInfo()
 }
 
+// ExplainInfo implements Plan interface.
+func (p *LogicalJSONTable) ExplainInfo() string {
+	return "doc:" + p.Doc.ExplainInfo() + ", path:" + p.Path
+}
+
 // ExplainInfo implements Plan interface.
 func (p *LogicalTableDual) ExplainInfo() string {
 	var str strings.Builder
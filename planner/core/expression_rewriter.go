@@ -315,7 +315,9 @@ func (er *expressionRewriter) constructBinaryOpFunction(l expression.Expression,
 	}
 }
 
-func (er *expressionRewriter) buildSubquery(ctx context.Context, subq *ast.SubqueryExpr) (LogicalPlan, error) {
+// buildSubquery builds the plan of the subquery, noDecorrelate is true if the correlated subquery has the NO_DECORRELATE
+// hint and the Apply built by the caller should be kept.
+func (er *expressionRewriter) buildSubquery(ctx context.Context, subq *ast.SubqueryExpr) (np LogicalPlan, noDecorrelate bool, err error) {
 	if er.schema != nil {
 		outerSchema := er.schema.Clone()
 		er.b.outerSchemas = append(er.b.outerSchemas, outerSchema)
@@ -326,13 +328,25 @@ func (er *expressionRewriter) buildSubquery(ctx context.Context, subq *ast.Subqu
 		}()
 	}
 
-	np, err := er.b.buildResultSetNode(ctx, subq.Query)
+	// The clauses of the subquery decide whether their subqueries are built as Apply by themselves.
+	subqueryAsApply := er.b.subqueryAsApply
+	er.b.subqueryAsApply = false
+	er.b.buildingSubquery, er.b.subqueryNoDecorrelate = true, false
+	np, err = er.b.buildResultSetNode(ctx, subq.Query)
+	er.b.subqueryAsApply = subqueryAsApply
+	noDecorrelate = er.b.subqueryNoDecorrelate
+	er.b.buildingSubquery, er.b.subqueryNoDecorrelate = false, false
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 	// Pop the handle map generated by the subquery.
 	er.b.handleHelper.popMap()
-	return np, nil
+	if noDecorrelate && (er.p == nil || len(extractCorColumnsBySchema4LogicalPlan(np, er.p.Schema())) == 0) {
+		er.sctx.GetSessionVars().StmtCtx.AppendWarning(ErrInternal.GenWithStack(
+			"NO_DECORRELATE() is inapplicable because there are no correlated columns"))
+		noDecorrelate = false
+	}
+	return np, noDecorrelate, nil
 }
 
 // Enter implements Visitor interface.
@@ -456,7 +470,7 @@ func (er *expressionRewriter) Enter(inNode ast.Node) (ast.Node, bool) {
 	return inNode, false
 }
 
-func (er *expressionRewriter) buildSemiApplyFromEqualSubq(np LogicalPlan, l, r expression.Expression, not bool) {
+func (er *expressionRewriter) buildSemiApplyFromEqualSubq(np LogicalPlan, l, r expression.Expression, not, noDecorrelate bool) {
 	if er.asScalar || not {
 		if expression.GetRowLen(r) == 1 {
 			rCol := r.(*expression.Column)
@@ -496,7 +510,7 @@ func (er *expressionRewriter) buildSemiApplyFromEqualSubq(np LogicalPlan, l, r e
 	if er.err != nil {
 		return
 	}
-	er.p, er.err = er.b.buildSemiApply(er.p, np, []expression.Expression{condition}, er.asScalar, not)
+	er.p, er.err = er.b.buildSemiApply(er.p, np, []expression.Expression{condition}, er.asScalar, not, noDecorrelate)
 }
 
 func (er *expressionRewriter) handleCompareSubquery(ctx context.Context, v *ast.CompareSubqueryExpr) (ast.Node, bool) {
@@ -512,7 +526,7 @@ func (er *expressionRewriter) handleCompareSubquery(ctx context.Context, v *ast.
 		er.err = errors.Errorf("Unknown compare type %T.", v.R)
 		return v, true
 	}
-	np, err := er.buildSubquery(ctx, subq)
+	np, noDecorrelate, err := er.buildSubquery(ctx, subq)
 	if err != nil {
 		er.err = err
 		return v, true
@@ -555,11 +569,11 @@ func (er *expressionRewriter) handleCompareSubquery(ctx context.Context, v *ast.
 	case opcode.EQ, opcode.NE, opcode.NullEQ:
 		if v.Op == opcode.EQ {
 			if v.All {
-				er.handleEQAll(lexpr, rexpr, np)
+				er.handleEQAll(lexpr, rexpr, np, noDecorrelate)
 			} else {
 				// `a = any(subq)` will be rewriten as `a in (subq)`.
 				er.asScalar = true
-				er.buildSemiApplyFromEqualSubq(np, lexpr, rexpr, false)
+				er.buildSemiApplyFromEqualSubq(np, lexpr, rexpr, false, noDecorrelate)
 				if er.err != nil {
 					return v, true
 				}
@@ -568,12 +582,12 @@ func (er *expressionRewriter) handleCompareSubquery(ctx context.Context, v *ast.
 			if v.All {
 				// `a != all(subq)` will be rewriten as `a not in (subq)`.
 				er.asScalar = true
-				er.buildSemiApplyFromEqualSubq(np, lexpr, rexpr, true)
+				er.buildSemiApplyFromEqualSubq(np, lexpr, rexpr, true, noDecorrelate)
 				if er.err != nil {
 					return v, true
 				}
 			} else {
-				er.handleNEAny(lexpr, rexpr, np)
+				er.handleNEAny(lexpr, rexpr, np, noDecorrelate)
 			}
 		} else {
 			// TODO: Support this in future.
@@ -583,7 +597,7 @@ func (er *expressionRewriter) handleCompareSubquery(ctx context.Context, v *ast.
 	default:
 		// When < all or > any , the agg function should use min.
 		useMin := ((v.Op == opcode.LT || v.Op == opcode.LE) && v.All) || ((v.Op == opcode.GT || v.Op == opcode.GE) && !v.All)
-		er.handleOtherComparableSubq(lexpr, rexpr, np, useMin, v.Op.String(), v.All)
+		er.handleOtherComparableSubq(lexpr, rexpr, np, useMin, v.Op.String(), v.All, noDecorrelate)
 	}
 	if er.asScalar {
 		// The parent expression only use the last column in schema, which represents whether the condition is matched.
@@ -595,7 +609,7 @@ func (er *expressionRewriter) handleCompareSubquery(ctx context.Context, v *ast.
 
 // handleOtherComparableSubq handles the queries like < any, < max, etc. For example, if the query is t.id < any (select s.id from s),
 // it will be rewrote to t.id < (select max(s.id) from s).
-func (er *expressionRewriter) handleOtherComparableSubq(lexpr, rexpr expression.Expression, np LogicalPlan, useMin bool, cmpFunc string, all bool) {
+func (er *expressionRewriter) handleOtherComparableSubq(lexpr, rexpr expression.Expression, np LogicalPlan, useMin bool, cmpFunc string, all, noDecorrelate bool) {
 	plan4Agg := LogicalAggregation{}.Init(er.sctx, er.b.getSelectOffset())
 	if hint := er.b.TableHints(); hint != nil {
 		plan4Agg.aggHints = hint.aggHints
@@ -625,11 +639,11 @@ func (er *expressionRewriter) handleOtherComparableSubq(lexpr, rexpr expression.
 	plan4Agg.AggFuncs = []*aggregation.AggFuncDesc{funcMaxOrMin}
 
 	cond := expression.NewFunctionInternal(er.sctx, cmpFunc, types.NewFieldType(mysql.TypeTiny), lexpr, colMaxOrMin)
-	er.buildQuantifierPlan(plan4Agg, cond, lexpr, rexpr, all)
+	er.buildQuantifierPlan(plan4Agg, cond, lexpr, rexpr, all, noDecorrelate)
 }
 
 // buildQuantifierPlan adds extra condition for any / all subquery.
-func (er *expressionRewriter) buildQuantifierPlan(plan4Agg *LogicalAggregation, cond, lexpr, rexpr expression.Expression, all bool) {
+func (er *expressionRewriter) buildQuantifierPlan(plan4Agg *LogicalAggregation, cond, lexpr, rexpr expression.Expression, all, noDecorrelate bool) {
 	innerIsNull := expression.NewFunctionInternal(er.sctx, ast.IsNull, types.NewFieldType(mysql.TypeTiny), rexpr)
 	outerIsNull := expression.NewFunctionInternal(er.sctx, ast.IsNull, types.NewFieldType(mysql.TypeTiny), lexpr)
 
@@ -685,12 +699,12 @@ func (er *expressionRewriter) buildQuantifierPlan(plan4Agg *LogicalAggregation,
 	// plan4Agg.buildProjectionIfNecessary()
 	if !er.asScalar {
 		// For Semi LogicalApply without aux column, the result is no matter false or null. So we can add it to join predicate.
-		er.p, er.err = er.b.buildSemiApply(er.p, plan4Agg, []expression.Expression{cond}, false, false)
+		er.p, er.err = er.b.buildSemiApply(er.p, plan4Agg, []expression.Expression{cond}, false, false, noDecorrelate)
 		return
 	}
 	// If we treat the result as a scalar value, we will add a projection with a extra column to output true, false or null.
 	outerSchemaLen := er.p.Schema().Len()
-	er.p = er.b.buildApplyWithJoinType(er.p, plan4Agg, InnerJoin)
+	er.p = er.b.buildApplyWithJoinType(er.p, plan4Agg, InnerJoin, noDecorrelate)
 	joinSchema := er.p.Schema()
 	proj := LogicalProjection{
 		Exprs: expression.Column2Exprs(joinSchema.Clone().Columns[:outerSchemaLen]),
@@ -711,7 +725,7 @@ func (er *expressionRewriter) buildQuantifierPlan(plan4Agg *LogicalAggregation,
 // handleNEAny handles the case of != any. For example, if the query is t.id != any (select s.id from s), it will be rewrote to
 // t.id != s.id or count(distinct s.id) > 1 or [any checker]. If there are two different values in s.id ,
 // there must exist a s.id that doesn't equal to t.id.
-func (er *expressionRewriter) handleNEAny(lexpr, rexpr expression.Expression, np LogicalPlan) {
+func (er *expressionRewriter) handleNEAny(lexpr, rexpr expression.Expression, np LogicalPlan, noDecorrelate bool) {
 	// If there is NULL in s.id column, s.id should be the value that isn't null in condition t.id != s.id.
 	// So use function max to filter NULL.
 	maxFunc, err := aggregation.NewAggFuncDesc(er.sctx, ast.AggFuncMax, []expression.Expression{rexpr}, false)
@@ -744,12 +758,12 @@ func (er *expressionRewriter) handleNEAny(lexpr, rexpr expression.Expression, np
 	gtFunc := expression.NewFunctionInternal(er.sctx, ast.GT, types.NewFieldType(mysql.TypeTiny), count, expression.NewOne())
 	neCond := expression.NewFunctionInternal(er.sctx, ast.NE, types.NewFieldType(mysql.TypeTiny), lexpr, maxResultCol)
 	cond := expression.ComposeDNFCondition(er.sctx, gtFunc, neCond)
-	er.buildQuantifierPlan(plan4Agg, cond, lexpr, rexpr, false)
+	er.buildQuantifierPlan(plan4Agg, cond, lexpr, rexpr, false, noDecorrelate)
 }
 
 // handleEQAll handles the case of = all. For example, if the query is t.id = all (select s.id from s), it will be rewrote to
 // t.id = (select s.id from s having count(distinct s.id) <= 1 and [all checker]).
-func (er *expressionRewriter) handleEQAll(lexpr, rexpr expression.Expression, np LogicalPlan) {
+func (er *expressionRewriter) handleEQAll(lexpr, rexpr expression.Expression, np LogicalPlan, noDecorrelate bool) {
 	firstRowFunc, err := aggregation.NewAggFuncDesc(er.sctx, ast.AggFuncFirstRow, []expression.Expression{rexpr}, false)
 	if err != nil {
 		er.err = err
@@ -781,7 +795,7 @@ func (er *expressionRewriter) handleEQAll(lexpr, rexpr expression.Expression, np
 	leFunc := expression.NewFunctionInternal(er.sctx, ast.LE, types.NewFieldType(mysql.TypeTiny), count, expression.NewOne())
 	eqCond := expression.NewFunctionInternal(er.sctx, ast.EQ, types.NewFieldType(mysql.TypeTiny), lexpr, firstRowResultCol)
 	cond := expression.ComposeCNFCondition(er.sctx, leFunc, eqCond)
-	er.buildQuantifierPlan(plan4Agg, cond, lexpr, rexpr, true)
+	er.buildQuantifierPlan(plan4Agg, cond, lexpr, rexpr, true, noDecorrelate)
 }
 
 func (er *expressionRewriter) handleExistSubquery(ctx context.Context, v *ast.ExistsSubqueryExpr) (ast.Node, bool) {
@@ -792,19 +806,20 @@ func (er *expressionRewriter) handleExistSubquery(ctx context.Context, v *ast.Ex
 		er.err = errors.Errorf("Unknown exists type %T.", v.Sel)
 		return v, true
 	}
-	np, err := er.buildSubquery(ctx, subq)
+	np, noDecorrelate, err := er.buildSubquery(ctx, subq)
 	if err != nil {
 		er.err = err
 		return v, true
 	}
 	np = er.popExistsSubPlan(np)
-	if len(ExtractCorrelatedCols4LogicalPlan(np)) > 0 {
-		er.p, er.err = er.b.buildSemiApply(er.p, np, nil, er.asScalar, v.Not)
+	if len(ExtractCorrelatedCols4LogicalPlan(np)) > 0 || er.subqueryAsApply() {
+		er.p, er.err = er.b.buildSemiApply(er.p, np, nil, er.asScalar, v.Not, noDecorrelate)
 		if er.err != nil || !er.asScalar {
 			return v, true
 		}
 		er.ctxStackAppend(er.p.Schema().Columns[er.p.Schema().Len()-1], er.p.OutputNames()[er.p.Schema().Len()-1])
 	} else {
+		er.skipPlanCache4Subquery()
 		// We don't want nth_plan hint to affect separately executed subqueries here, so disable nth_plan temporarily.
 		NthPlanBackup := er.sctx.GetSessionVars().StmtCtx.StmtHints.ForceNthPlan
 		er.sctx.GetSessionVars().StmtCtx.StmtHints.ForceNthPlan = -1
@@ -866,7 +881,7 @@ func (er *expressionRewriter) handleInSubquery(ctx context.Context, v *ast.Patte
 		er.err = errors.Errorf("Unknown compare type %T.", v.Sel)
 		return v, true
 	}
-	np, err := er.buildSubquery(ctx, subq)
+	np, noDecorrelate, err := er.buildSubquery(ctx, subq)
 	if err != nil {
 		er.err = err
 		return v, true
@@ -950,7 +965,7 @@ func (er *expressionRewriter) handleInSubquery(ctx context.Context, v *ast.Patte
 		}
 		er.p = join
 	} else {
-		er.p, er.err = er.b.buildSemiApply(er.p, np, expression.SplitCNFItems(checkCondition), asScalar, v.Not)
+		er.p, er.err = er.b.buildSemiApply(er.p, np, expression.SplitCNFItems(checkCondition), asScalar, v.Not, noDecorrelate)
 		if er.err != nil {
 			return v, true
 		}
@@ -967,14 +982,14 @@ func (er *expressionRewriter) handleInSubquery(ctx context.Context, v *ast.Patte
 func (er *expressionRewriter) handleScalarSubquery(ctx context.Context, v *ast.SubqueryExpr) (ast.Node, bool) {
 	ci := er.b.prepareCTECheckForSubQuery()
 	defer resetCTECheckForSubQuery(ci)
-	np, err := er.buildSubquery(ctx, v)
+	np, noDecorrelate, err := er.buildSubquery(ctx, v)
 	if err != nil {
 		er.err = err
 		return v, true
 	}
 	np = er.b.buildMaxOneRow(np)
-	if len(ExtractCorrelatedCols4LogicalPlan(np)) > 0 {
-		er.p = er.b.buildApplyWithJoinType(er.p, np, LeftOuterJoin)
+	if len(ExtractCorrelatedCols4LogicalPlan(np)) > 0 || er.subqueryAsApply() {
+		er.p = er.b.buildApplyWithJoinType(er.p, np, LeftOuterJoin, noDecorrelate)
 		if np.Schema().Len() > 1 {
 			newCols := make([]expression.Expression, 0, np.Schema().Len())
 			for _, col := range np.Schema().Columns {
@@ -991,6 +1006,7 @@ func (er *expressionRewriter) handleScalarSubquery(ctx context.Context, v *ast.S
 		}
 		return v, true
 	}
+	er.skipPlanCache4Subquery()
 	// We don't want nth_plan hint to affect separately executed subqueries here, so disable nth_plan temporarily.
 	NthPlanBackup := er.sctx.GetSessionVars().StmtCtx.StmtHints.ForceNthPlan
 	er.sctx.GetSessionVars().StmtCtx.StmtHints.ForceNthPlan = -1
@@ -1112,7 +1128,11 @@ func (er *expressionRewriter) Leave(originInNode ast.Node) (retNode ast.Node, ok
 			return retNode, false
 		}
 
-		castFunction := expression.BuildCastFunction(er.sctx, arg, v.Tp)
+		castFunction, err := expression.BuildCastFunctionWithCheck(er.sctx, arg, v.Tp)
+		if err != nil {
+			er.err = err
+			return retNode, false
+		}
 		if v.Tp.EvalType() == types.ETString {
 			castFunction.SetCoercibility(expression.CoercibilityImplicit)
 			if v.Tp.Charset == charset.CharsetASCII {
@@ -1229,6 +1249,20 @@ func (er *expressionRewriter) useCache() bool {
 	return er.sctx.GetSessionVars().StmtCtx.UseCache
 }
 
+// subqueryAsApply checks whether the uncorrelated subqueries are built as Apply. The results of the uncorrelated
+// subqueries depend on the data, so they're not evaluated when the plan is built for the plan cache.
+func (er *expressionRewriter) subqueryAsApply() bool {
+	return er.useCache() && er.b.subqueryAsApply && er.p != nil
+}
+
+// skipPlanCache4Subquery marks the plan un-cacheable because the uncorrelated subquery is evaluated when the plan is
+// built.
+func (er *expressionRewriter) skipPlanCache4Subquery() {
+	if er.useCache() {
+		er.sctx.GetSessionVars().StmtCtx.SetSkipPlanCache("query has uncorrelated sub-queries outside the WHERE, HAVING and select fields")
+	}
+}
+
 func (er *expressionRewriter) rewriteVariable(v *ast.VariableExpr) {
 	stkLen := len(er.ctxStack)
 	name := strings.ToLower(v.Name)
@@ -151,6 +151,21 @@ func (p *LogicalTableDual) findBestTask(prop *property.PhysicalProperty, planCou
 	return &rootTask{p: dual, isEmpty: p.RowCount == 0}, 1, nil
 }
 
+func (p *LogicalJSONTable) findBestTask(prop *property.PhysicalProperty, planCounter *PlanCounterTp) (task, int64, error) {
+	if !prop.IsEmpty() || planCounter.Empty() {
+		return invalidTask, 0, nil
+	}
+	jt := PhysicalJSONTable{
+		Doc:     p.Doc,
+		Path:    p.Path,
+		Columns: p.Columns,
+		AsName:  p.AsName,
+	}.Init(p.ctx, p.stats, p.blockOffset)
+	jt.SetSchema(p.schema)
+	planCounter.Dec(1)
+	return &rootTask{p: jt}, 1, nil
+}
+
 func (p *LogicalShow) findBestTask(prop *property.PhysicalProperty, planCounter *PlanCounterTp) (task, int64, error) {
 	if !prop.IsEmpty() || planCounter.Empty() {
 		return invalidTask, 0, nil
@@ -984,6 +999,17 @@ func (ds *DataSource) convertToPartialIndexScan(prop *property.PhysicalProperty,
 	partialCost float64) {
 	idx := path.Index
 	is, partialCost, rowCount := ds.getOriginalPhysicalIndexScan(prop, path, false, false)
+	if idx.MVIndex {
+		// The entries of the multi-valued index store the elements of the array,
+		// so the array key part is read with the element type.
+		for i, col := range is.schema.Columns {
+			if col.RetType.ArrayElem != nil {
+				elemCol := col.Clone().(*expression.Column)
+				elemCol.RetType = col.RetType.ArrayElem.Clone()
+				is.schema.Columns[i] = elemCol
+			}
+		}
+	}
 	rowSize := is.indexScanRowSize(idx, ds, false)
 	// TODO: Consider using isCoveringIndex() to avoid another TableRead
 	indexConds := path.IndexFilters
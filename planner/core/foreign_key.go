@@ -0,0 +1,429 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/types"
+)
+
+// FKCheck indicates the foreign key constraint checker.
+// The checker on the child side checks the referenced row exists in the parent table when a child row is
+// inserted or updated, and the checker on the parent side checks no row in the child table refers to the
+// parent row when the parent row is deleted or updated.
+type FKCheck struct {
+	basePhysicalPlan
+
+	FK          *model.FKInfo
+	ChildSchema model.CIStr
+	ChildTable  model.CIStr
+	// Tbl is the table to be checked, it's the parent table if CheckExist is true, otherwise it's the child table.
+	Tbl table.Table
+	// Idx is the index used to check the rows, it's nil if the check uses the int handle or scans the whole table.
+	Idx *model.IndexInfo
+	// IdxIsPrimaryKey indicates the check uses the int handle or the clustered index.
+	IdxIsPrimaryKey bool
+	// IdxIsExclusive indicates the checked columns are exactly the columns of the unique index or the primary key,
+	// so the check is a point get.
+	IdxIsExclusive bool
+	// CheckExist is true when the rows should exist in Tbl, otherwise the rows should not exist in Tbl.
+	CheckExist bool
+}
+
+// FKCascadeType indicates in which case the referential action is performed.
+type FKCascadeType int8
+
+const (
+	// FKCascadeOnDelete indicates the referential action is performed when the parent rows are deleted.
+	FKCascadeOnDelete FKCascadeType = iota
+	// FKCascadeOnUpdate indicates the referential action is performed when the parent rows are updated.
+	FKCascadeOnUpdate
+)
+
+// String implements the fmt.Stringer interface.
+func (tp FKCascadeType) String() string {
+	if tp == FKCascadeOnUpdate {
+		return "on_update"
+	}
+	return "on_delete"
+}
+
+// FKCascade indicates the foreign key referential action CASCADE or SET NULL, which modifies the rows of the child
+// table when the rows of the parent table are deleted or updated.
+type FKCascade struct {
+	basePhysicalPlan
+
+	Tp          FKCascadeType
+	FK          *model.FKInfo
+	ChildSchema model.CIStr
+	ChildTable  table.Table
+}
+
+// ReferOption returns the referential action of the cascade.
+func (f *FKCascade) ReferOption() ast.ReferOptionType {
+	if f.Tp == FKCascadeOnUpdate {
+		return ast.ReferOptionType(f.FK.OnUpdate)
+	}
+	return ast.ReferOptionType(f.FK.OnDelete)
+}
+
+// AccessObject implements dataAccesser interface.
+func (f *FKCheck) AccessObject(_ bool) string {
+	var buffer strings.Builder
+	buffer.WriteString("table:")
+	if f.Tbl == nil {
+		// The referenced table doesn't exist.
+		buffer.WriteString(f.FK.RefTable.O)
+		return buffer.String()
+	}
+	buffer.WriteString(f.Tbl.Meta().Name.O)
+	if f.Idx != nil {
+		buffer.WriteString(", index:")
+		buffer.WriteString(f.Idx.Name.O)
+	} else if f.IdxIsPrimaryKey {
+		buffer.WriteString(", handle")
+	}
+	return buffer.String()
+}
+
+// OperatorInfo implements dataAccesser interface.
+func (f *FKCheck) OperatorInfo(_ bool) string {
+	if f.CheckExist {
+		return "foreign_key:" + f.FK.Name.O + ", check_exist"
+	}
+	return "foreign_key:" + f.FK.Name.O + ", check_not_exist"
+}
+
+// ExplainInfo implements Plan interface.
+func (f *FKCheck) ExplainInfo() string {
+	return f.AccessObject(false) + ", " + f.OperatorInfo(false)
+}
+
+// AccessObject implements dataAccesser interface.
+func (f *FKCascade) AccessObject(_ bool) string {
+	return "table:" + f.ChildTable.Meta().Name.O
+}
+
+// OperatorInfo implements dataAccesser interface.
+func (f *FKCascade) OperatorInfo(_ bool) string {
+	return "foreign_key:" + f.FK.Name.O + ", " + f.Tp.String() + ":" + f.ReferOption().String()
+}
+
+// ExplainInfo implements Plan interface.
+func (f *FKCascade) ExplainInfo() string {
+	return f.AccessObject(false) + ", " + f.OperatorInfo(false)
+}
+
+// FKInfoString returns the foreign key definition used in the error message, which is the same as MySQL.
+func FKInfoString(childSchema, childTable model.CIStr, fk *model.FKInfo) string {
+	var buf strings.Builder
+	buf.WriteString("`" + childSchema.O + "`.`" + childTable.O + "`, CONSTRAINT `" + fk.Name.O + "` FOREIGN KEY (")
+	writeFKColumns(&buf, fk.Cols)
+	buf.WriteString(") REFERENCES `" + fk.RefTable.O + "` (")
+	writeFKColumns(&buf, fk.RefCols)
+	buf.WriteString(")")
+	if opt := ast.ReferOptionType(fk.OnDelete); opt != ast.ReferOptionNoOption {
+		buf.WriteString(" ON DELETE " + opt.String())
+	}
+	if opt := ast.ReferOptionType(fk.OnUpdate); opt != ast.ReferOptionNoOption {
+		buf.WriteString(" ON UPDATE " + opt.String())
+	}
+	return buf.String()
+}
+
+func writeFKColumns(buf *strings.Builder, cols []model.CIStr) {
+	for i, col := range cols {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString("`" + col.O + "`")
+	}
+}
+
+// fkRefSchema returns the schema of the referenced table of the foreign key.
+func fkRefSchema(childSchema model.CIStr, fk *model.FKInfo) model.CIStr {
+	if fk.RefSchema.L == "" {
+		return childSchema
+	}
+	return fk.RefSchema
+}
+
+// getReferringFKs returns all the public foreign keys referring to the table.
+func getReferringFKs(is infoschema.InfoSchema, schema model.CIStr, tblInfo *model.TableInfo) []*infoschema.ReferringForeignKey {
+	var fks []*infoschema.ReferringForeignKey
+	for _, ref := range is.ReferringForeignKeys(schema, tblInfo.Name) {
+		if ref.FK.State == model.StatePublic {
+			fks = append(fks, ref)
+		}
+	}
+	return fks
+}
+
+// findFKIndex finds the index whose leading columns are the columns to be checked, prefers the int handle
+// and the unique index whose columns are exactly the checked columns.
+func findFKIndex(tblInfo *model.TableInfo, cols []model.CIStr) (idx *model.IndexInfo, isPK, isExclusive bool) {
+	if tblInfo.PKIsHandle && len(cols) == 1 {
+		if pkCol := tblInfo.GetPkColInfo(); pkCol != nil && pkCol.Name.L == cols[0].L {
+			return nil, true, true
+		}
+	}
+	for _, index := range tblInfo.Indices {
+		if index.State != model.StatePublic || len(index.Columns) < len(cols) {
+			continue
+		}
+		matched := true
+		for i, col := range cols {
+			// The prefix index can't be used to check the whole value.
+			if index.Columns[i].Name.L != col.L || index.Columns[i].Length != types.UnspecifiedLength {
+				matched = false
+				break
+			}
+		}
+		if !matched {
+			continue
+		}
+		exclusive := index.Unique && len(index.Columns) == len(cols)
+		if idx == nil || (exclusive && !isExclusive) {
+			idx, isExclusive = index, exclusive
+		}
+	}
+	// The clustered index is checked by the record keys.
+	isPK = idx != nil && idx.Primary && tblInfo.IsCommonHandle
+	return idx, isPK, isExclusive
+}
+
+func buildFKCheck(ctx sessionctx.Context, childSchema, childTable model.CIStr, fk *model.FKInfo, tbl table.Table, checkExist bool) *FKCheck {
+	cols := fk.Cols
+	if checkExist {
+		cols = fk.RefCols
+	}
+	idx, isPK, isExclusive := findFKIndex(tbl.Meta(), cols)
+	return FKCheck{
+		FK:              fk,
+		ChildSchema:     childSchema,
+		ChildTable:      childTable,
+		Tbl:             tbl,
+		Idx:             idx,
+		IdxIsPrimaryKey: isPK,
+		IdxIsExclusive:  isExclusive,
+		CheckExist:      checkExist,
+	}.Init(ctx)
+}
+
+// buildFKChecksOnChild builds the checkers for the foreign keys of the child table whose columns are changed,
+// columns is nil means all the columns are changed.
+func buildFKChecksOnChild(ctx sessionctx.Context, is infoschema.InfoSchema, schema model.CIStr, tblInfo *model.TableInfo, columns map[string]struct{}) []*FKCheck {
+	var checks []*FKCheck
+	for _, fk := range tblInfo.ForeignKeys {
+		if fk.State != model.StatePublic || !isAnyFKColumnChanged(fk.Cols, columns) {
+			continue
+		}
+		parent, err := is.TableByName(fkRefSchema(schema, fk), fk.RefTable)
+		if err != nil {
+			// The parent table may be created after the child table when foreign_key_checks is off,
+			// or be dropped, the foreign key can't be satisfied then.
+			checks = append(checks, FKCheck{
+				FK:          fk,
+				ChildSchema: schema,
+				ChildTable:  tblInfo.Name,
+				CheckExist:  true,
+			}.Init(ctx))
+			continue
+		}
+		checks = append(checks, buildFKCheck(ctx, schema, tblInfo.Name, fk, parent, true))
+	}
+	return checks
+}
+
+// buildFKTriggersOnParent builds the checkers and the cascades for the foreign keys referring to the parent table
+// whose referenced columns are changed, columns is nil means the parent rows are deleted.
+func buildFKTriggersOnParent(ctx sessionctx.Context, is infoschema.InfoSchema, schema model.CIStr, tblInfo *model.TableInfo, columns map[string]struct{}) ([]*FKCheck, []*FKCascade) {
+	var (
+		checks   []*FKCheck
+		cascades []*FKCascade
+	)
+	tp := FKCascadeOnDelete
+	if columns != nil {
+		tp = FKCascadeOnUpdate
+	}
+	for _, ref := range getReferringFKs(is, schema, tblInfo) {
+		if !isAnyFKColumnChanged(ref.FK.RefCols, columns) {
+			continue
+		}
+		opt := ast.ReferOptionType(ref.FK.OnDelete)
+		if tp == FKCascadeOnUpdate {
+			opt = ast.ReferOptionType(ref.FK.OnUpdate)
+		}
+		switch opt {
+		case ast.ReferOptionCascade, ast.ReferOptionSetNull:
+			cascades = append(cascades, FKCascade{
+				Tp:          tp,
+				FK:          ref.FK,
+				ChildSchema: ref.ChildSchema,
+				ChildTable:  ref.ChildTable,
+			}.Init(ctx))
+		default:
+			// RESTRICT, NO ACTION and SET DEFAULT(which is rejected by InnoDB) reject the change of the parent rows.
+			checks = append(checks, buildFKCheck(ctx, ref.ChildSchema, ref.ChildTable.Meta().Name, ref.FK, ref.ChildTable, false))
+		}
+	}
+	return checks, cascades
+}
+
+func isAnyFKColumnChanged(cols []model.CIStr, columns map[string]struct{}) bool {
+	if columns == nil {
+		return true
+	}
+	for _, col := range cols {
+		if _, ok := columns[col.L]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// buildOnInsertFKTriggers builds the foreign key checkers and cascades of the insert plan.
+func (p *Insert) buildOnInsertFKTriggers(ctx sessionctx.Context, is infoschema.InfoSchema, schema model.CIStr) {
+	if !ctx.GetSessionVars().ForeignKeyChecks {
+		return
+	}
+	tblInfo := p.Table.Meta()
+	p.FKChecks = buildFKChecksOnChild(ctx, is, schema, tblInfo, nil)
+	var checks []*FKCheck
+	switch {
+	case p.IsReplace:
+		// The conflicting rows are deleted by REPLACE.
+		checks, p.FKCascades = buildFKTriggersOnParent(ctx, is, schema, tblInfo, nil)
+	case len(p.OnDuplicate) > 0:
+		columns := make(map[string]struct{}, len(p.OnDuplicate))
+		for _, assign := range p.OnDuplicate {
+			columns[assign.ColName.L] = struct{}{}
+		}
+		checks, p.FKCascades = buildFKTriggersOnParent(ctx, is, schema, tblInfo, columns)
+	}
+	p.FKChecks = append(p.FKChecks, checks...)
+}
+
+// buildOnUpdateFKTriggers builds the foreign key checkers and cascades of the update plan.
+func (p *Update) buildOnUpdateFKTriggers(ctx sessionctx.Context, is infoschema.InfoSchema, tblID2table map[int64]table.Table) {
+	if !ctx.GetSessionVars().ForeignKeyChecks {
+		return
+	}
+	tblID2Columns := make(map[int64]map[string]struct{}, len(p.TblColPosInfos))
+	for _, assign := range p.OrderedList {
+		for _, content := range p.TblColPosInfos {
+			if assign.Col.Index < content.Start || assign.Col.Index >= content.End {
+				continue
+			}
+			if tblID2Columns[content.TblID] == nil {
+				tblID2Columns[content.TblID] = make(map[string]struct{})
+			}
+			tblID2Columns[content.TblID][assign.ColName.L] = struct{}{}
+		}
+	}
+	for tblID, columns := range tblID2Columns {
+		tbl := tblID2table[tblID]
+		if tbl == nil {
+			continue
+		}
+		db, ok := is.SchemaByTable(tbl.Meta())
+		if !ok {
+			continue
+		}
+		checks := buildFKChecksOnChild(ctx, is, db.Name, tbl.Meta(), columns)
+		parentChecks, cascades := buildFKTriggersOnParent(ctx, is, db.Name, tbl.Meta(), columns)
+		checks = append(checks, parentChecks...)
+		if len(checks) > 0 {
+			if p.FKChecks == nil {
+				p.FKChecks = make(map[int64][]*FKCheck)
+			}
+			p.FKChecks[tblID] = checks
+		}
+		if len(cascades) > 0 {
+			if p.FKCascades == nil {
+				p.FKCascades = make(map[int64][]*FKCascade)
+			}
+			p.FKCascades[tblID] = cascades
+		}
+	}
+}
+
+// buildOnDeleteFKTriggers builds the foreign key checkers and cascades of the delete plan.
+func (p *Delete) buildOnDeleteFKTriggers(ctx sessionctx.Context, is infoschema.InfoSchema, tblID2table map[int64]table.Table) {
+	if !ctx.GetSessionVars().ForeignKeyChecks {
+		return
+	}
+	for tblID, tbl := range tblID2table {
+		if tbl == nil {
+			continue
+		}
+		db, ok := is.SchemaByTable(tbl.Meta())
+		if !ok {
+			continue
+		}
+		checks, cascades := buildFKTriggersOnParent(ctx, is, db.Name, tbl.Meta(), nil)
+		if len(checks) > 0 {
+			if p.FKChecks == nil {
+				p.FKChecks = make(map[int64][]*FKCheck)
+			}
+			p.FKChecks[tblID] = checks
+		}
+		if len(cascades) > 0 {
+			if p.FKCascades == nil {
+				p.FKCascades = make(map[int64][]*FKCascade)
+			}
+			p.FKCascades[tblID] = cascades
+		}
+	}
+}
+
+func collectFKPlans(fkChecks map[int64][]*FKCheck, fkCascades map[int64][]*FKCascade) []Plan {
+	tblIDs := make([]int64, 0, len(fkChecks)+len(fkCascades))
+	for tblID := range fkChecks {
+		tblIDs = append(tblIDs, tblID)
+	}
+	for tblID := range fkCascades {
+		if _, ok := fkChecks[tblID]; !ok {
+			tblIDs = append(tblIDs, tblID)
+		}
+	}
+	// Make the explain result stable.
+	sort.Slice(tblIDs, func(i, j int) bool { return tblIDs[i] < tblIDs[j] })
+	var plans []Plan
+	for _, tblID := range tblIDs {
+		for _, fkc := range fkChecks[tblID] {
+			plans = append(plans, fkc)
+		}
+		for _, fkc := range fkCascades[tblID] {
+			plans = append(plans, fkc)
+		}
+	}
+	return plans
+}
+
+func (p *Update) fkPlans() []Plan {
+	return collectFKPlans(p.FKChecks, p.FKCascades)
+}
+
+func (p *Delete) fkPlans() []Plan {
+	return collectFKPlans(p.FKChecks, p.FKCascades)
+}
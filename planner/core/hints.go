@@ -38,6 +38,10 @@ func GenHintsFromPhysicalPlan(p Plan) []*ast.TableOptimizerHint {
 	case PhysicalPlan:
 		hints = genHintsFromPhysicalPlan(pp, utilhint.TypeSelect)
 	}
+	// NO_INDEX_MERGE is a statement level hint, it's not recorded in the plan.
+	if len(hints) > 0 && p.SCtx().GetSessionVars().StmtCtx.NoIndexMergeHint {
+		hints = append(hints, &ast.TableOptimizerHint{HintName: model.NewCIStr(HintNoIndexMerge)})
+	}
 	return hints
 }
 
@@ -118,6 +122,19 @@ func getJoinHints(sctx sessionctx.Context, joinType string, parentOffset int, no
 	return res
 }
 
+// getSubqueryBlockOffset returns the block offset of the subquery in the inner side of the apply. The operators built
+// by the outer block upon the subquery, like MaxOneRow, are skipped.
+func getSubqueryBlockOffset(apply *PhysicalApply) int {
+	p := apply.children[1]
+	for p.SelectBlockOffset() == apply.SelectBlockOffset() && len(p.Children()) == 1 {
+		p = p.Children()[0]
+	}
+	if p.SelectBlockOffset() == apply.SelectBlockOffset() {
+		return -1
+	}
+	return p.SelectBlockOffset()
+}
+
 func genHintsFromPhysicalPlan(p PhysicalPlan, nodeType utilhint.NodeType) (res []*ast.TableOptimizerHint) {
 	if p == nil {
 		return res
@@ -201,6 +218,27 @@ func genHintsFromPhysicalPlan(p PhysicalPlan, nodeType utilhint.NodeType) (res [
 		res = append(res, getJoinHints(p.SCtx(), HintSMJ, p.SelectBlockOffset(), nodeType, pp.children...)...)
 	case *PhysicalHashJoin:
 		res = append(res, getJoinHints(p.SCtx(), HintHJ, p.SelectBlockOffset(), nodeType, pp.children...)...)
+		buildIdx := pp.InnerChildIdx
+		if pp.UseOuterToBuild {
+			buildIdx = 1 - pp.InnerChildIdx
+		}
+		res = append(res, getJoinHints(p.SCtx(), HintHashJoinBuild, p.SelectBlockOffset(), nodeType, pp.children[buildIdx])...)
+	case *PhysicalApply:
+		if !pp.NoDecorrelate {
+			break
+		}
+		subqueryOffset := getSubqueryBlockOffset(pp)
+		if subqueryOffset <= 0 {
+			break
+		}
+		subqueryQBName, err := utilhint.GenerateQBName(nodeType, subqueryOffset)
+		if err != nil {
+			break
+		}
+		res = append(res, &ast.TableOptimizerHint{
+			QBName:   subqueryQBName,
+			HintName: model.NewCIStr(HintNoDecorrelate),
+		})
 	case *PhysicalIndexJoin:
 		res = append(res, getJoinHints(p.SCtx(), HintINLJ, p.SelectBlockOffset(), nodeType, pp.children[pp.InnerChildIdx])...)
 	case *PhysicalIndexMergeJoin:
@@ -244,6 +244,20 @@ func (p Insert) Init(ctx sessionctx.Context) *Insert {
 	return &p
 }
 
+// Init initializes FKCheck.
+func (p FKCheck) Init(ctx sessionctx.Context) *FKCheck {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, plancodec.TypeForeignKeyCheck, &p, 0)
+	p.stats = &property.StatsInfo{}
+	return &p
+}
+
+// Init initializes FKCascade.
+func (p FKCascade) Init(ctx sessionctx.Context) *FKCascade {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, plancodec.TypeForeignKeyCascade, &p, 0)
+	p.stats = &property.StatsInfo{}
+	return &p
+}
+
 // Init initializes LoadData.
 func (p LoadData) Init(ctx sessionctx.Context) *LoadData {
 	p.basePlan = newBasePlan(ctx, plancodec.TypeLoadData, 0)
@@ -543,6 +557,19 @@ func (p PhysicalCTE) Init(ctx sessionctx.Context, stats *property.StatsInfo) *Ph
 	return &p
 }
 
+// Init initializes LogicalJSONTable.
+func (p LogicalJSONTable) Init(ctx sessionctx.Context, offset int) *LogicalJSONTable {
+	p.baseLogicalPlan = newBaseLogicalPlan(ctx, plancodec.TypeJSONTable, &p, offset)
+	return &p
+}
+
+// Init initializes PhysicalJSONTable.
+func (p PhysicalJSONTable) Init(ctx sessionctx.Context, stats *property.StatsInfo, offset int) *PhysicalJSONTable {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, plancodec.TypeJSONTable, &p, offset)
+	p.stats = stats
+	return &p
+}
+
 // Init only assigns type and context.
 func (p LogicalCTETable) Init(ctx sessionctx.Context, offset int) *LogicalCTETable {
 	p.baseLogicalPlan = newBaseLogicalPlan(ctx, plancodec.TypeCTETable, &p, offset)
@@ -0,0 +1,573 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/format"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/types"
+	driver "github.com/pingcap/tidb/types/parser_driver"
+	"github.com/pingcap/tidb/util/codec"
+	"github.com/pingcap/tidb/util/hack"
+	"github.com/pingcap/tidb/util/kvcache"
+)
+
+// The plans don't track their memory usage, so the memory usage of the cached plan is estimated roughly by the
+// numbers of its operators, expressions and ranges.
+const (
+	instancePlanCacheOperatorMemUsage = 1024
+	instancePlanCacheExprMemUsage     = 256
+	instancePlanCacheRangeMemUsage    = 256
+	instancePlanCacheNameMemUsage     = 128
+)
+
+var globalInstancePlanCache = newInstancePlanCache()
+
+// instancePlanCacheVars are the variables read by the optimizer besides the ones checked by NewInstancePlanCacheKey,
+// the plans built with the different values are cached separately.
+var instancePlanCacheVars = []string{
+	variable.TiDBOptPreferRangeScan,
+	variable.TiDBOptLimitPushDownThreshold,
+	variable.TiDBOptInSubqToJoinAndAgg,
+	variable.TiDBOptJoinReorderThreshold,
+	variable.TiDBOptEnableCorrelationAdjustment,
+	variable.TiDBOptCorrelationThreshold,
+	variable.TiDBOptCorrelationExpFactor,
+	variable.TiDBOptCPUFactor,
+	variable.TiDBOptCopCPUFactor,
+	variable.TiDBOptTiFlashConcurrencyFactor,
+	variable.TiDBOptNetworkFactor,
+	variable.TiDBOptScanFactor,
+	variable.TiDBOptDescScanFactor,
+	variable.TiDBOptSeekFactor,
+	variable.TiDBOptMemoryFactor,
+	variable.TiDBOptDiskFactor,
+	variable.TiDBOptConcurrencyFactor,
+	variable.TiDBOptBCJ,
+	variable.TiDBOptCartesianBCJ,
+	variable.TiDBOptMPPOuterJoinFixedBuildSide,
+	variable.TiDBBCJThresholdSize,
+	variable.TiDBBCJThresholdCount,
+	variable.TiDBAllowBatchCop,
+	variable.TiDBAllowFallbackToTiKV,
+	variable.TiDBOptimizerSelectivityLevel,
+	variable.TiDBEnablePseudoForOutdatedStats,
+	variable.TiDBEnableExtendedStats,
+	variable.TiDBEnableIndexMergeJoin,
+	variable.TiDBEnableParallelApply,
+	// The charset and collation of the string literals are not restored in the statement text.
+	variable.CharacterSetConnection,
+	variable.CollationConnection,
+}
+
+// instancePlanCacheStmtText returns the statement text in the key of the instance plan cache. The statements which
+// are the same after parser.Normalize share the plans, but Normalize drops the literals, the optimizer hints and the
+// letter case of the names, which are built into the plans and the result columns, so they are kept in the text too.
+func instancePlanCacheStmtText(stmt *CachedPrepareStmt) string {
+	sqlText := stmt.StmtText
+	if len(stmt.ParameterizedSQL) > 0 {
+		sqlText = stmt.ParameterizedSQL
+	}
+	collector := &normalizedTextCollector{text: codec.EncodeCompactBytes(nil, hack.Slice(parser.Normalize(sqlText)))}
+	stmt.PreparedAst.Stmt.Accept(collector)
+	return string(collector.text)
+}
+
+// normalizedTextCollector collects the parts of the statement which are dropped by parser.Normalize.
+type normalizedTextCollector struct {
+	text []byte
+}
+
+func (c *normalizedTextCollector) append(texts ...string) {
+	for _, text := range texts {
+		c.text = codec.EncodeCompactBytes(c.text, hack.Slice(text))
+	}
+}
+
+func (c *normalizedTextCollector) appendNode(node ast.Node) {
+	var sb strings.Builder
+	if err := node.Restore(format.NewRestoreCtx(format.DefaultRestoreFlags, &sb)); err != nil {
+		// The node can't be compared, so the statement is never shared with the others.
+		sb.WriteString(err.Error())
+	}
+	c.append(sb.String())
+}
+
+func (c *normalizedTextCollector) appendHints(hints []*ast.TableOptimizerHint) {
+	for _, hint := range hints {
+		c.appendNode(hint)
+	}
+}
+
+// Enter implements Visitor interface.
+func (c *normalizedTextCollector) Enter(in ast.Node) (out ast.Node, skipChildren bool) {
+	switch x := in.(type) {
+	case *driver.ParamMarkerExpr:
+	case *driver.ValueExpr:
+		c.appendNode(x)
+	case *ast.TableOptimizerHint:
+		// Only the hints of SELECT are visited.
+		c.appendNode(x)
+	case *ast.InsertStmt:
+		c.appendHints(x.TableHints)
+	case *ast.UpdateStmt:
+		c.appendHints(x.TableHints)
+	case *ast.DeleteStmt:
+		c.appendHints(x.TableHints)
+	case *ast.TableName:
+		c.append(x.Schema.O, x.Name.O)
+	case *ast.TableSource:
+		c.append(x.AsName.O)
+	case *ast.ColumnName:
+		c.append(x.Schema.O, x.Table.O, x.Name.O)
+	case *ast.SelectField:
+		// The name of the result column is the text of the expression if it has no alias.
+		c.append(x.AsName.O)
+		if _, ok := x.Expr.(*ast.ColumnNameExpr); !ok && x.AsName.L == "" {
+			c.append(x.Text())
+		}
+	}
+	return in, false
+}
+
+// Leave implements Visitor interface.
+func (c *normalizedTextCollector) Leave(in ast.Node) (out ast.Node, ok bool) {
+	return in, true
+}
+
+// InstancePlanCacheEnabled returns whether the instance plan cache is enabled.
+func InstancePlanCacheEnabled() bool {
+	return variable.EnableInstancePlanCache.Load()
+}
+
+// FlushInstancePlanCache removes all the plans cached by the instance plan cache.
+func FlushInstancePlanCache() {
+	globalInstancePlanCache.flush()
+}
+
+// InstancePlanCacheEntry is the information of a plan cached by the instance plan cache.
+type InstancePlanCacheEntry struct {
+	SQLText       string
+	DB            string
+	SchemaVersion int64
+	ParamTypes    string
+	PlanDigest    string
+	MemUsage      int64
+	Hits          int64
+	CreateTime    time.Time
+	LastHitTime   time.Time
+}
+
+// InstancePlanCacheEntries returns the plans cached by the instance plan cache, the most recently used ones come
+// first.
+func InstancePlanCacheEntries() []InstancePlanCacheEntry {
+	return globalInstancePlanCache.entries()
+}
+
+// instancePlanCacheValue is the plan cached by the instance plan cache. The cached plan isn't bound to any session,
+// it's cloned for the session which uses it.
+type instancePlanCacheValue struct {
+	*PSTMTPlanCacheValue
+
+	key            string
+	sqlText        string
+	db             string
+	schemaVersion  int64
+	normalizedPlan string
+	planDigest     *parser.Digest
+	memUsage       int64
+	createTime     time.Time
+
+	// hits and lastHitTime are protected by the mutex of the instance plan cache.
+	hits        int64
+	lastHitTime time.Time
+
+	// mu serializes the cloning of the plan, the lazily initialized fields of the expressions are set when they are
+	// cloned.
+	mu sync.Mutex
+}
+
+// instancePlanCache is the plan cache shared by all the sessions of the instance. The plans are evicted by LRU
+// when their memory usage exceeds tidb_instance_plan_cache_max_size.
+type instancePlanCache struct {
+	mu       sync.Mutex
+	lru      *list.List
+	elements map[string][]*list.Element
+	memUsage int64
+}
+
+func newInstancePlanCache() *instancePlanCache {
+	return &instancePlanCache{
+		lru:      list.New(),
+		elements: make(map[string][]*list.Element),
+	}
+}
+
+// get returns the cached plan whose parameter types are the same as tps.
+func (c *instancePlanCache) get(key string, tps []*types.FieldType) *instancePlanCacheValue {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, element := range c.elements[key] {
+		value := element.Value.(*instancePlanCacheValue)
+		if value.UserVarTypes.Equal(tps) {
+			c.lru.MoveToFront(element)
+			value.hits++
+			value.lastHitTime = time.Now()
+			return value
+		}
+	}
+	return nil
+}
+
+// put caches the plan, the cached plan of the same parameter types is replaced.
+func (c *instancePlanCache) put(value *instancePlanCacheValue) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	tps := toFieldTypes(value.UserVarTypes)
+	for _, element := range c.elements[value.key] {
+		if element.Value.(*instancePlanCacheValue).UserVarTypes.Equal(tps) {
+			c.removeElement(element)
+			break
+		}
+	}
+	c.elements[value.key] = append(c.elements[value.key], c.lru.PushFront(value))
+	c.memUsage += value.memUsage
+	maxMemUsage := variable.InstancePlanCacheMaxMemSize.Load()
+	for c.memUsage > maxMemUsage && c.lru.Len() > 0 {
+		c.removeElement(c.lru.Back())
+	}
+}
+
+func (c *instancePlanCache) removeElement(element *list.Element) {
+	value := element.Value.(*instancePlanCacheValue)
+	c.lru.Remove(element)
+	c.memUsage -= value.memUsage
+	elements := c.elements[value.key]
+	for i := range elements {
+		if elements[i] == element {
+			elements = append(elements[:i], elements[i+1:]...)
+			break
+		}
+	}
+	if len(elements) == 0 {
+		delete(c.elements, value.key)
+	} else {
+		c.elements[value.key] = elements
+	}
+}
+
+func (c *instancePlanCache) flush() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lru.Init()
+	c.elements = make(map[string][]*list.Element)
+	c.memUsage = 0
+}
+
+func (c *instancePlanCache) entries() []InstancePlanCacheEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entries := make([]InstancePlanCacheEntry, 0, c.lru.Len())
+	for element := c.lru.Front(); element != nil; element = element.Next() {
+		value := element.Value.(*instancePlanCacheValue)
+		paramTypes := make([]string, 0, len(value.UserVarTypes))
+		for i := range value.UserVarTypes {
+			paramTypes = append(paramTypes, types.TypeStr(value.UserVarTypes[i].Tp))
+		}
+		entry := InstancePlanCacheEntry{
+			SQLText:       value.sqlText,
+			DB:            value.db,
+			SchemaVersion: value.schemaVersion,
+			ParamTypes:    strings.Join(paramTypes, ","),
+			MemUsage:      value.memUsage,
+			Hits:          value.hits,
+			CreateTime:    value.createTime,
+			LastHitTime:   value.lastHitTime,
+		}
+		if value.planDigest != nil {
+			entry.PlanDigest = value.planDigest.String()
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func toFieldTypes(s FieldSlice) []*types.FieldType {
+	tps := make([]*types.FieldType, len(s))
+	for i := range s {
+		tps[i] = &s[i]
+	}
+	return tps
+}
+
+// newInstancePlanCacheValue creates the value cached by the instance plan cache. It returns nil if the plan can't
+// be shared by the sessions.
+func newInstancePlanCacheValue(key kvcache.Key, stmt *CachedPrepareStmt, plan Plan, names types.NameSlice,
+	tblInfo2UnionScan map[*model.TableInfo]bool, tps []*types.FieldType) *instancePlanCacheValue {
+	if !instancePlanCacheable(plan) {
+		return nil
+	}
+	// The cached plan is unbound from the session which builds it.
+	cloned := clonePlanForSession(plan, nil, nil)
+	sqlText := stmt.StmtText
+	if len(stmt.ParameterizedSQL) > 0 {
+		sqlText = stmt.ParameterizedSQL
+	}
+	value := &instancePlanCacheValue{
+		PSTMTPlanCacheValue: NewPSTMTPlanCacheValue(cloned, names, tblInfo2UnionScan, tps),
+		key:                 string(key.Hash()),
+		sqlText:             sqlText,
+		db:                  stmt.StmtDB,
+		schemaVersion:       stmt.PreparedAst.SchemaVersion,
+		normalizedPlan:      stmt.NormalizedPlan,
+		planDigest:          stmt.PlanDigest,
+		createTime:          time.Now(),
+	}
+	value.memUsage = int64(len(value.key)+len(sqlText)+len(value.normalizedPlan)) +
+		int64(len(names))*instancePlanCacheNameMemUsage + estimatePlanMemUsage(cloned)
+	return value
+}
+
+// clone clones the cached plan for the session, params are the parameter markers of the prepared statement of the
+// session.
+func (v *instancePlanCacheValue) clone(sctx sessionctx.Context, params []ast.ParamMarkerExpr) Plan {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return clonePlanForSession(v.Plan, sctx, params)
+}
+
+// instancePlanCacheable checks whether the plan can be shared by the sessions. Only the read-only plans reading
+// the non-partitioned tables by TiKV are shared, the plans which can't be shared are cached by the session.
+func instancePlanCacheable(p Plan) bool {
+	switch x := p.(type) {
+	case *PointGetPlan:
+		return x.PartitionInfo == nil && !x.Lock && !hasVirtualColumn(x.TblInfo)
+	case *BatchPointGetPlan:
+		return x.PartitionExpr == nil && !x.Lock && !hasVirtualColumn(x.TblInfo)
+	case *PhysicalTableReader:
+		if x.StoreType != kv.TiKV || !instancePlanCacheable(x.tablePlan) {
+			return false
+		}
+	case *PhysicalIndexReader:
+		if !instancePlanCacheable(x.indexPlan) {
+			return false
+		}
+	case *PhysicalIndexLookUpReader:
+		if !instancePlanCacheable(x.indexPlan) || !instancePlanCacheable(x.tablePlan) {
+			return false
+		}
+	case *PhysicalTableScan:
+		if x.isPartition || x.SampleInfo != nil || x.StoreType != kv.TiKV || hasVirtualColumn(x.Table) {
+			return false
+		}
+	case *PhysicalIndexScan:
+		if x.isPartition || len(x.GenExprs) > 0 || hasVirtualColumn(x.Table) {
+			return false
+		}
+	case *PhysicalSelection, *PhysicalProjection, *PhysicalLimit, *PhysicalTopN, *PhysicalSort,
+		*PhysicalHashAgg, *PhysicalStreamAgg:
+	default:
+		return false
+	}
+	for _, child := range p.(PhysicalPlan).Children() {
+		if !instancePlanCacheable(child) {
+			return false
+		}
+	}
+	return true
+}
+
+func hasVirtualColumn(tblInfo *model.TableInfo) bool {
+	for _, col := range tblInfo.Columns {
+		if col.IsGenerated() && !col.GeneratedStored {
+			return true
+		}
+	}
+	return false
+}
+
+// clonePlanForSession clones the plan checked by instancePlanCacheable and binds it to the session. The parameter
+// markers of the fast plans are replaced by the ones in params at the same positions if params isn't nil.
+func clonePlanForSession(p Plan, sctx sessionctx.Context, params []ast.ParamMarkerExpr) Plan {
+	switch x := p.(type) {
+	case *PointGetPlan:
+		cloned := *x
+		cloned.setCtx(sctx)
+		cloned.ctx = sctx
+		cloned.HandleParam = bindParamMarker(x.HandleParam, params)
+		cloned.IndexValues = append([]types.Datum(nil), x.IndexValues...)
+		cloned.IndexValueParams = bindParamMarkers(x.IndexValueParams, params)
+		cloned.AccessConditions = cloneExprs(x.AccessConditions)
+		expression.SetCtxForExprs(sctx, cloned.AccessConditions...)
+		return &cloned
+	case *BatchPointGetPlan:
+		cloned := *x
+		cloned.setCtx(sctx)
+		cloned.ctx = sctx
+		cloned.Handles = append([]kv.Handle(nil), x.Handles...)
+		cloned.HandleParams = bindParamMarkers(x.HandleParams, params)
+		cloned.IndexValues = make([][]types.Datum, len(x.IndexValues))
+		for i, values := range x.IndexValues {
+			cloned.IndexValues[i] = append([]types.Datum(nil), values...)
+		}
+		cloned.IndexValueParams = make([][]*driver.ParamMarkerExpr, len(x.IndexValueParams))
+		for i, markers := range x.IndexValueParams {
+			cloned.IndexValueParams[i] = bindParamMarkers(markers, params)
+		}
+		cloned.AccessConditions = cloneExprs(x.AccessConditions)
+		expression.SetCtxForExprs(sctx, cloned.AccessConditions...)
+		return &cloned
+	}
+	// The plans checked by instancePlanCacheable support cloning.
+	cloned, _ := p.(PhysicalPlan).Clone()
+	setCtxForPhysicalPlan(cloned, sctx)
+	return cloned
+}
+
+func bindParamMarker(marker *driver.ParamMarkerExpr, params []ast.ParamMarkerExpr) *driver.ParamMarkerExpr {
+	if marker == nil || params == nil {
+		return marker
+	}
+	return params[marker.Order].(*driver.ParamMarkerExpr)
+}
+
+func bindParamMarkers(markers []*driver.ParamMarkerExpr, params []ast.ParamMarkerExpr) []*driver.ParamMarkerExpr {
+	if markers == nil {
+		return nil
+	}
+	bound := make([]*driver.ParamMarkerExpr, len(markers))
+	for i, marker := range markers {
+		bound[i] = bindParamMarker(marker, params)
+	}
+	return bound
+}
+
+// setCtxForPhysicalPlan sets the session context of the cloned physical plan and its expressions.
+func setCtxForPhysicalPlan(p PhysicalPlan, sctx sessionctx.Context) {
+	p.(interface{ setCtx(sessionctx.Context) }).setCtx(sctx)
+	switch x := p.(type) {
+	case *PhysicalTableReader:
+		// The flattened plans are cloned separately by Clone.
+		x.TablePlans = flattenPushDownPlan(x.tablePlan)
+		setCtxForPhysicalPlan(x.tablePlan, sctx)
+	case *PhysicalIndexReader:
+		x.IndexPlans = flattenPushDownPlan(x.indexPlan)
+		setCtxForPhysicalPlan(x.indexPlan, sctx)
+	case *PhysicalIndexLookUpReader:
+		x.IndexPlans = flattenPushDownPlan(x.indexPlan)
+		x.TablePlans = flattenPushDownPlan(x.tablePlan)
+		setCtxForPhysicalPlan(x.indexPlan, sctx)
+		setCtxForPhysicalPlan(x.tablePlan, sctx)
+	case *PhysicalTableScan:
+		expression.SetCtxForExprs(sctx, x.AccessCondition...)
+		expression.SetCtxForExprs(sctx, x.filterCondition...)
+	case *PhysicalIndexScan:
+		expression.SetCtxForExprs(sctx, x.AccessCondition...)
+	case *PhysicalSelection:
+		expression.SetCtxForExprs(sctx, x.Conditions...)
+	case *PhysicalProjection:
+		expression.SetCtxForExprs(sctx, x.Exprs...)
+	case *PhysicalTopN:
+		for _, item := range x.ByItems {
+			expression.SetCtxForExprs(sctx, item.Expr)
+		}
+	case *PhysicalSort:
+		for _, item := range x.ByItems {
+			expression.SetCtxForExprs(sctx, item.Expr)
+		}
+	case *PhysicalHashAgg:
+		setCtxForAgg(&x.basePhysicalAgg, sctx)
+	case *PhysicalStreamAgg:
+		setCtxForAgg(&x.basePhysicalAgg, sctx)
+	}
+	for _, child := range p.Children() {
+		setCtxForPhysicalPlan(child, sctx)
+	}
+}
+
+func setCtxForAgg(p *basePhysicalAgg, sctx sessionctx.Context) {
+	for _, aggFunc := range p.AggFuncs {
+		expression.SetCtxForExprs(sctx, aggFunc.Args...)
+		for _, item := range aggFunc.OrderByItems {
+			expression.SetCtxForExprs(sctx, item.Expr)
+		}
+	}
+	expression.SetCtxForExprs(sctx, p.GroupByItems...)
+}
+
+// estimatePlanMemUsage estimates the memory usage of the plan checked by instancePlanCacheable.
+func estimatePlanMemUsage(p Plan) int64 {
+	memUsage := int64(instancePlanCacheOperatorMemUsage)
+	var exprs []expression.Expression
+	switch x := p.(type) {
+	case *PointGetPlan:
+		return memUsage + int64(len(x.AccessConditions)+len(x.IndexValues))*instancePlanCacheExprMemUsage
+	case *BatchPointGetPlan:
+		return memUsage + int64(len(x.AccessConditions)+len(x.Handles)+len(x.IndexValues))*instancePlanCacheExprMemUsage
+	case *PhysicalTableReader:
+		memUsage += estimatePlanMemUsage(x.tablePlan)
+	case *PhysicalIndexReader:
+		memUsage += estimatePlanMemUsage(x.indexPlan)
+	case *PhysicalIndexLookUpReader:
+		memUsage += estimatePlanMemUsage(x.indexPlan) + estimatePlanMemUsage(x.tablePlan)
+	case *PhysicalTableScan:
+		memUsage += int64(len(x.Ranges)) * instancePlanCacheRangeMemUsage
+		exprs = make([]expression.Expression, 0, len(x.AccessCondition)+len(x.filterCondition))
+		exprs = append(append(exprs, x.AccessCondition...), x.filterCondition...)
+	case *PhysicalIndexScan:
+		memUsage += int64(len(x.Ranges)) * instancePlanCacheRangeMemUsage
+		exprs = x.AccessCondition
+	case *PhysicalSelection:
+		exprs = x.Conditions
+	case *PhysicalProjection:
+		exprs = x.Exprs
+	case *PhysicalHashAgg:
+		memUsage += int64(len(x.AggFuncs)) * instancePlanCacheExprMemUsage
+		exprs = x.GroupByItems
+	case *PhysicalStreamAgg:
+		memUsage += int64(len(x.AggFuncs)) * instancePlanCacheExprMemUsage
+		exprs = x.GroupByItems
+	}
+	for _, expr := range exprs {
+		memUsage += countExprNodes(expr) * instancePlanCacheExprMemUsage
+	}
+	if pp, ok := p.(PhysicalPlan); ok {
+		for _, child := range pp.Children() {
+			memUsage += estimatePlanMemUsage(child)
+		}
+	}
+	return memUsage
+}
+
+func countExprNodes(expr expression.Expression) int64 {
+	count := int64(1)
+	if sf, ok := expr.(*expression.ScalarFunction); ok {
+		for _, arg := range sf.GetArgs() {
+			count += countExprNodes(arg)
+		}
+	}
+	return count
+}
@@ -37,6 +37,7 @@ import (
 	"github.com/pingcap/tidb/metrics"
 	"github.com/pingcap/tidb/parser"
 	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/charset"
 	"github.com/pingcap/tidb/parser/format"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
@@ -52,6 +53,7 @@ import (
 	"github.com/pingcap/tidb/table/tables"
 	"github.com/pingcap/tidb/table/temptable"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 	driver "github.com/pingcap/tidb/types/parser_driver"
 	util2 "github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/chunk"
@@ -86,6 +88,16 @@ const (
 	TiDBHashJoin = "tidb_hj"
 	// HintHJ is hint enforce hash join.
 	HintHJ = "hash_join"
+	// HintHashJoinBuild is hint enforce hash join and specifies the build side table.
+	HintHashJoinBuild = "hash_join_build"
+	// HintHashJoinProbe is hint enforce hash join and specifies the probe side table.
+	HintHashJoinProbe = "hash_join_probe"
+	// HintNoHashJoin is hint to prevent using hash join.
+	HintNoHashJoin = "no_hash_join"
+	// HintNoMergeJoin is hint to prevent using merge join.
+	HintNoMergeJoin = "no_merge_join"
+	// HintNoIndexJoin is hint to prevent using any kind of index nested loop join.
+	HintNoIndexJoin = "no_index_join"
 	// HintHashAgg is hint enforce hash aggregation.
 	HintHashAgg = "hash_agg"
 	// HintStreamAgg is hint enforce stream aggregation.
@@ -112,6 +124,12 @@ const (
 	HintIgnorePlanCache = "ignore_plan_cache"
 	// HintLimitToCop is a hint enforce pushing limit or topn to coprocessor.
 	HintLimitToCop = "limit_to_cop"
+	// HintLeading specifies the set of tables to be used as the prefix in the execution plan.
+	HintLeading = "leading"
+	// HintNoIndexMerge is a hint to prevent using index merge in the whole statement.
+	HintNoIndexMerge = "no_index_merge"
+	// HintNoDecorrelate is a hint to keep the correlated subquery from being decorrelated.
+	HintNoDecorrelate = "no_decorrelate"
 )
 
 const (
@@ -340,6 +358,10 @@ func (b *PlanBuilder) buildResultSetNode(ctx context.Context, node ast.ResultSet
 		case *ast.TableName:
 			p, err = b.buildDataSource(ctx, v, &x.AsName)
 			isTableName = true
+		case *ast.JSONTable:
+			p, err = b.buildJSONTable(ctx, v, x.AsName)
+			// JSON_TABLE is not a select block either.
+			isTableName = true
 		default:
 			err = ErrUnsupportedType.GenWithStackByArgs(v)
 		}
@@ -379,6 +401,101 @@ func (b *PlanBuilder) buildResultSetNode(ctx context.Context, node ast.ResultSet
 	}
 }
 
+// buildJSONTable builds the plan of JSON_TABLE. The document is rewritten without
+// any input columns, so the references to the outer tables, including the tables
+// preceding JSON_TABLE in the FROM clause, become correlated columns.
+func (b *PlanBuilder) buildJSONTable(ctx context.Context, jt *ast.JSONTable, asName model.CIStr) (LogicalPlan, error) {
+	dual := LogicalTableDual{RowCount: 1}.Init(b.ctx, b.getSelectOffset())
+	dual.SetSchema(expression.NewSchema())
+	doc, np, err := b.rewrite(ctx, jt.Expr, dual, nil, true)
+	if err != nil {
+		return nil, err
+	}
+	if np != dual {
+		return nil, ErrNotSupportedYet.GenWithStackByArgs("subquery in JSON_TABLE")
+	}
+	if _, err := json.ParseJSONPathExpr(jt.Path); err != nil {
+		return nil, err
+	}
+
+	p := LogicalJSONTable{
+		Doc:     expression.WrapWithCastAsJSON(b.ctx, doc),
+		Path:    jt.Path,
+		Columns: jt.Columns,
+		AsName:  asName,
+	}.Init(b.ctx, b.getSelectOffset())
+	schema := expression.NewSchema()
+	var names types.NameSlice
+	if err := b.buildJSONTableColumns(jt.Columns, asName, schema, &names); err != nil {
+		return nil, err
+	}
+	p.SetSchema(schema)
+	p.names = names
+	b.handleHelper.pushMap(nil)
+	return p, nil
+}
+
+// buildJSONTableColumns appends the columns of JSON_TABLE to the schema, the columns
+// of NESTED PATH are flattened in place.
+func (b *PlanBuilder) buildJSONTableColumns(cols []*ast.JSONTableColumn, asName model.CIStr, schema *expression.Schema, names *types.NameSlice) error {
+	for _, col := range cols {
+		if col.Tp != ast.JSONTableColumnOrdinality {
+			if _, err := json.ParseJSONPathExpr(col.Path); err != nil {
+				return err
+			}
+		}
+		var ft *types.FieldType
+		switch col.Tp {
+		case ast.JSONTableColumnNested:
+			if err := b.buildJSONTableColumns(col.Columns, asName, schema, names); err != nil {
+				return err
+			}
+			continue
+		case ast.JSONTableColumnOrdinality:
+			ft = types.NewFieldType(mysql.TypeLong)
+			ft.Flag |= mysql.UnsignedFlag | mysql.NotNullFlag
+		default:
+			ft = col.FieldType.Clone()
+		}
+		setJSONTableColumnType(ft)
+		schema.Append(&expression.Column{
+			UniqueID: b.ctx.GetSessionVars().AllocPlanColumnID(),
+			RetType:  ft,
+			OrigName: fmt.Sprintf("%v.%v", asName.O, col.Name.O),
+		})
+		*names = append(*names, &types.FieldName{
+			TblName:     asName,
+			OrigTblName: asName,
+			ColName:     col.Name,
+			OrigColName: col.Name,
+		})
+	}
+	return nil
+}
+
+// setJSONTableColumnType fills the unspecified charset, collation, length and
+// decimal of the column type, the charset of string columns is utf8mb4 by default.
+func setJSONTableColumnType(ft *types.FieldType) {
+	if ft.EvalType() == types.ETString {
+		if ft.Charset == "" {
+			ft.Charset = mysql.DefaultCharset
+		}
+		if ft.Collate == "" {
+			ft.Collate, _ = charset.GetDefaultCollation(ft.Charset)
+		}
+	} else {
+		ft.Charset = charset.CharsetBin
+		ft.Collate = charset.CollationBin
+	}
+	defaultFlen, defaultDecimal := mysql.GetDefaultFieldLengthAndDecimal(ft.Tp)
+	if ft.Flen == types.UnspecifiedLength {
+		ft.Flen = defaultFlen
+	}
+	if ft.Decimal == types.UnspecifiedLength {
+		ft.Decimal = defaultDecimal
+	}
+}
+
 // pushDownConstExpr checks if the condition is from filter condition, if true, push it down to both
 // children of join, whatever the join type is; if false, push it down to inner child of outer join,
 // and both children of non-outer-join.
@@ -588,11 +705,42 @@ func (p *LogicalJoin) setPreferredJoinType(hintInfo *tableHintInfo) {
 	if hintInfo.ifPreferINLMJ(rhsAlias) {
 		p.preferJoinType |= preferRightAsINLMJInner
 	}
+	if hintInfo.ifPreferHJBuild(lhsAlias) {
+		p.preferJoinType |= preferLeftAsHJBuild
+	}
+	if hintInfo.ifPreferHJBuild(rhsAlias) {
+		p.preferJoinType |= preferRightAsHJBuild
+	}
+	if hintInfo.ifPreferHJProbe(lhsAlias) {
+		p.preferJoinType |= preferLeftAsHJProbe
+	}
+	if hintInfo.ifPreferHJProbe(rhsAlias) {
+		p.preferJoinType |= preferRightAsHJProbe
+	}
+	if hintInfo.ifPreferNoHashJoin(lhsAlias, rhsAlias) {
+		p.preferJoinType |= preferNoHashJoin
+	}
+	if hintInfo.ifPreferNoMergeJoin(lhsAlias, rhsAlias) {
+		p.preferJoinType |= preferNoMergeJoin
+	}
+	if hintInfo.ifPreferNoIndexJoin(lhsAlias, rhsAlias) {
+		p.preferJoinType |= preferNoIndexJoin
+	}
 	if containDifferentJoinTypes(p.preferJoinType) {
 		errMsg := "Join hints are conflict, you can only specify one type of join"
 		warning := ErrInternal.GenWithStack(errMsg)
 		p.ctx.GetSessionVars().StmtCtx.AppendWarning(warning)
-		p.preferJoinType = 0
+		p.preferJoinType &= preferNoJoinMask
+	}
+	p.ignoreConflictingNoJoinHints(hintInfo)
+	if hintInfo.ifPreferLeading(lhsAlias, rhsAlias) {
+		if p.StraightJoin {
+			// The join order of a straight join is fixed, so the LEADING hint is ignored.
+			errMsg := fmt.Sprintf("Optimizer Hint %s is inapplicable to STRAIGHT_JOIN", restore2JoinHint(HintLeading, hintInfo.leadingJoinOrder))
+			p.ctx.GetSessionVars().StmtCtx.AppendWarning(ErrInternal.GenWithStack(errMsg))
+		} else {
+			p.leadingJoinOrder = hintInfo.leadingJoinOrder
+		}
 	}
 	// set hintInfo for further usage if this hint info can be used.
 	if p.preferJoinType != 0 {
@@ -600,6 +748,31 @@ func (p *LogicalJoin) setPreferredJoinType(hintInfo *tableHintInfo) {
 	}
 }
 
+// ignoreConflictingNoJoinHints clears the NO_XXX_JOIN hints which prohibit the join algorithm chosen by the other
+// join hints, the hint choosing an algorithm takes precedence.
+func (p *LogicalJoin) ignoreConflictingNoJoinHints(hintInfo *tableHintInfo) {
+	inlMask := preferLeftAsINLJInner | preferRightAsINLJInner | preferLeftAsINLHJInner | preferRightAsINLHJInner |
+		preferLeftAsINLMJInner | preferRightAsINLMJInner
+	conflicts := []struct {
+		noJoin, join uint
+		hintName     string
+		hintTables   []hintTableInfo
+	}{
+		{preferNoHashJoin, preferHashJoin | preferHJSideMask, HintNoHashJoin, hintInfo.noHashJoinTables},
+		{preferNoMergeJoin, preferMergeJoin, HintNoMergeJoin, hintInfo.noMergeJoinTables},
+		{preferNoIndexJoin, inlMask, HintNoIndexJoin, hintInfo.noIndexJoinTables},
+	}
+	for _, c := range conflicts {
+		if p.preferJoinType&c.noJoin == 0 || p.preferJoinType&c.join == 0 {
+			continue
+		}
+		p.preferJoinType &^= c.noJoin
+		errMsg := fmt.Sprintf("Optimizer Hint %s is inapplicable because it conflicts with the hint choosing the same join algorithm",
+			restore2JoinHint(c.hintName, c.hintTables))
+		p.ctx.GetSessionVars().StmtCtx.AppendWarning(ErrInternal.GenWithStack(errMsg))
+	}
+}
+
 func (ds *DataSource) setPreferredStoreType(hintInfo *tableHintInfo) {
 	if hintInfo == nil {
 		return
@@ -684,7 +857,17 @@ func (b *PlanBuilder) buildJoin(ctx context.Context, joinNode *ast.Join) (Logica
 		return nil, err
 	}
 
+	// JSON_TABLE can refer to the columns of the tables preceding it, like a lateral derived table.
+	jsonTable, isJSONTable := isJSONTableSource(joinNode.Right)
+	if isJSONTable {
+		b.outerSchemas = append(b.outerSchemas, leftPlan.Schema())
+		b.outerNames = append(b.outerNames, leftPlan.OutputNames())
+	}
 	rightPlan, err := b.buildResultSetNode(ctx, joinNode.Right)
+	if isJSONTable {
+		b.outerSchemas = b.outerSchemas[0 : len(b.outerSchemas)-1]
+		b.outerNames = b.outerNames[0 : len(b.outerNames)-1]
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -698,7 +881,19 @@ func (b *PlanBuilder) buildJoin(ctx context.Context, joinNode *ast.Join) (Logica
 	handleMap2 := b.handleHelper.popMap()
 	b.handleHelper.mergeAndPush(handleMap1, handleMap2)
 
-	joinPlan := LogicalJoin{StraightJoin: joinNode.StraightJoin || b.inStraightJoin}.Init(b.ctx, b.getSelectOffset())
+	var joinPlan *LogicalJoin
+	if isJSONTable && len(extractCorColumnsBySchema4LogicalPlan(rightPlan, leftPlan.Schema())) > 0 {
+		if joinNode.Tp == ast.RightJoin {
+			return nil, ErrJSONTableForbiddenJoinType.GenWithStackByArgs(jsonTable.AsName.O)
+		}
+		// The document is evaluated for every row of the left side, so build an Apply
+		// and let the decorrelate rule turn it into a join if possible.
+		b.optFlag = b.optFlag | flagBuildKeyInfo | flagDecorrelate
+		ap := LogicalApply{LogicalJoin: LogicalJoin{StraightJoin: joinNode.StraightJoin || b.inStraightJoin}}.Init(b.ctx, b.getSelectOffset())
+		joinPlan = &ap.LogicalJoin
+	} else {
+		joinPlan = LogicalJoin{StraightJoin: joinNode.StraightJoin || b.inStraightJoin}.Init(b.ctx, b.getSelectOffset())
+	}
 	joinPlan.SetChildren(leftPlan, rightPlan)
 	joinPlan.SetSchema(expression.MergeSchema(leftPlan.Schema(), rightPlan.Schema()))
 	joinPlan.names = make([]*types.FieldName, leftPlan.Schema().Len()+rightPlan.Schema().Len())
@@ -763,11 +958,11 @@ func (b *PlanBuilder) buildJoin(ctx context.Context, joinNode *ast.Join) (Logica
 		}
 	} else if joinNode.On != nil {
 		b.curClause = onClause
-		onExpr, newPlan, err := b.rewrite(ctx, joinNode.On.Expr, joinPlan, nil, false)
+		onExpr, newPlan, err := b.rewrite(ctx, joinNode.On.Expr, joinPlan.self, nil, false)
 		if err != nil {
 			return nil, err
 		}
-		if newPlan != joinPlan {
+		if newPlan != joinPlan.self {
 			return nil, errors.New("ON condition doesn't support subqueries yet")
 		}
 		onCondition := expression.SplitCNFItems(onExpr)
@@ -775,7 +970,7 @@ func (b *PlanBuilder) buildJoin(ctx context.Context, joinNode *ast.Join) (Logica
 		// possible decorrelate optimizations. The ON clause is actually treated as a WHERE clause now.
 		if joinPlan.JoinType == InnerJoin {
 			sel := LogicalSelection{Conditions: onCondition}.Init(b.ctx, b.getSelectOffset())
-			sel.SetChildren(joinPlan)
+			sel.SetChildren(joinPlan.self)
 			return sel, nil
 		}
 		joinPlan.AttachOnConds(onCondition)
@@ -785,7 +980,17 @@ func (b *PlanBuilder) buildJoin(ctx context.Context, joinNode *ast.Join) (Logica
 		joinPlan.cartesianJoin = true
 	}
 
-	return joinPlan, nil
+	return joinPlan.self, nil
+}
+
+// isJSONTableSource checks whether the node is a JSON_TABLE table source.
+func isJSONTableSource(node ast.ResultSetNode) (*ast.TableSource, bool) {
+	ts, ok := node.(*ast.TableSource)
+	if !ok {
+		return nil, false
+	}
+	_, ok = ts.Source.(*ast.JSONTable)
+	return ts, ok
 }
 
 // buildUsingClause eliminate the redundant columns and ordering columns based
@@ -962,6 +1167,18 @@ func (b *PlanBuilder) coalesceCommonColumns(p *LogicalJoin, leftPlan, rightPlan
 	return nil
 }
 
+// rewriteSubqueryAsApply rewrites the expression whose rewritten plan is kept, the uncorrelated subqueries in it are
+// built as Apply when the plan is built for the plan cache.
+func (b *PlanBuilder) rewriteSubqueryAsApply(ctx context.Context, expr ast.ExprNode, p LogicalPlan,
+	aggMapper map[*ast.AggregateFuncExpr]int) (expression.Expression, LogicalPlan, error) {
+	subqueryAsApply := b.subqueryAsApply
+	b.subqueryAsApply = true
+	defer func() {
+		b.subqueryAsApply = subqueryAsApply
+	}()
+	return b.rewrite(ctx, expr, p, aggMapper, false)
+}
+
 func (b *PlanBuilder) buildSelection(ctx context.Context, p LogicalPlan, where ast.ExprNode, aggMapper map[*ast.AggregateFuncExpr]int) (LogicalPlan, error) {
 	b.optFlag |= flagPredicatePushDown
 	if b.curClause != havingClause {
@@ -972,7 +1189,7 @@ func (b *PlanBuilder) buildSelection(ctx context.Context, p LogicalPlan, where a
 	expressions := make([]expression.Expression, 0, len(conditions))
 	selection := LogicalSelection{buildByHaving: aggMapper != nil}.Init(b.ctx, b.getSelectOffset())
 	for _, cond := range conditions {
-		expr, np, err := b.rewrite(ctx, cond, p, aggMapper, false)
+		expr, np, err := b.rewriteSubqueryAsApply(ctx, cond, p, aggMapper)
 		if err != nil {
 			return nil, err
 		}
@@ -1258,7 +1475,10 @@ func (b *PlanBuilder) buildProjection(ctx context.Context, p LogicalPlan, fields
 			newNames = append(newNames, name)
 			continue
 		}
+		subqueryAsApply := b.subqueryAsApply
+		b.subqueryAsApply = true
 		newExpr, np, err := b.rewriteWithPreprocess(ctx, field.Expr, p, mapper, windowMapper, true, nil)
+		b.subqueryAsApply = subqueryAsApply
 		if err != nil {
 			return nil, nil, 0, err
 		}
@@ -3241,17 +3461,23 @@ func (b *PlanBuilder) pushTableHints(hints []*ast.TableOptimizerHint, currentLev
 	hints = b.hintProcessor.GetCurrentStmtHints(hints, currentLevel)
 	var (
 		sortMergeTables, INLJTables, INLHJTables, INLMJTables, hashJoinTables, BCTables, BCJPreferLocalTables []hintTableInfo
+		hashJoinBuildTables, hashJoinProbeTables                                                              []hintTableInfo
+		noHashJoinTables, noMergeJoinTables, noIndexJoinTables                                                []hintTableInfo
 		indexHintList, indexMergeHintList                                                                     []indexHintInfo
 		tiflashTables, tikvTables                                                                             []hintTableInfo
 		aggHints                                                                                              aggHintInfo
 		timeRangeHint                                                                                         ast.HintTimeRange
 		limitHints                                                                                            limitHintInfo
+		leadingJoinOrder                                                                                      []hintTableInfo
+		leadingHintCnt                                                                                        int
+		noDecorrelate                                                                                         bool
 	)
 	for _, hint := range hints {
 		// Set warning for the hint that requires the table name.
 		switch hint.HintName.L {
 		case TiDBMergeJoin, HintSMJ, TiDBIndexNestedLoopJoin, HintINLJ, HintINLHJ, HintINLMJ,
-			TiDBHashJoin, HintHJ, HintUseIndex, HintIgnoreIndex, HintForceIndex, HintIndexMerge:
+			TiDBHashJoin, HintHJ, HintUseIndex, HintIgnoreIndex, HintForceIndex, HintIndexMerge, HintLeading,
+			HintHashJoinBuild, HintHashJoinProbe, HintNoHashJoin, HintNoMergeJoin, HintNoIndexJoin:
 			if len(hint.Tables) == 0 {
 				b.pushHintWithoutTableWarning(hint)
 				continue
@@ -3273,6 +3499,16 @@ func (b *PlanBuilder) pushTableHints(hints []*ast.TableOptimizerHint, currentLev
 			INLMJTables = append(INLMJTables, tableNames2HintTableInfo(b.ctx, hint.HintName.L, hint.Tables, b.hintProcessor, currentLevel)...)
 		case TiDBHashJoin, HintHJ:
 			hashJoinTables = append(hashJoinTables, tableNames2HintTableInfo(b.ctx, hint.HintName.L, hint.Tables, b.hintProcessor, currentLevel)...)
+		case HintHashJoinBuild:
+			hashJoinBuildTables = append(hashJoinBuildTables, tableNames2HintTableInfo(b.ctx, hint.HintName.L, hint.Tables, b.hintProcessor, currentLevel)...)
+		case HintHashJoinProbe:
+			hashJoinProbeTables = append(hashJoinProbeTables, tableNames2HintTableInfo(b.ctx, hint.HintName.L, hint.Tables, b.hintProcessor, currentLevel)...)
+		case HintNoHashJoin:
+			noHashJoinTables = append(noHashJoinTables, tableNames2HintTableInfo(b.ctx, hint.HintName.L, hint.Tables, b.hintProcessor, currentLevel)...)
+		case HintNoMergeJoin:
+			noMergeJoinTables = append(noMergeJoinTables, tableNames2HintTableInfo(b.ctx, hint.HintName.L, hint.Tables, b.hintProcessor, currentLevel)...)
+		case HintNoIndexJoin:
+			noIndexJoinTables = append(noIndexJoinTables, tableNames2HintTableInfo(b.ctx, hint.HintName.L, hint.Tables, b.hintProcessor, currentLevel)...)
 		case HintHashAgg:
 			aggHints.preferAggType |= preferHashAgg
 		case HintStreamAgg:
@@ -3350,16 +3586,33 @@ func (b *PlanBuilder) pushTableHints(hints []*ast.TableOptimizerHint, currentLev
 			timeRangeHint = hint.HintData.(ast.HintTimeRange)
 		case HintLimitToCop:
 			limitHints.preferLimitToCop = true
+		case HintLeading:
+			if leadingHintCnt == 0 {
+				leadingJoinOrder = tableNames2HintTableInfo(b.ctx, hint.HintName.L, hint.Tables, b.hintProcessor, currentLevel)
+			}
+			leadingHintCnt++
+		case HintNoDecorrelate:
+			noDecorrelate = true
 		default:
 			// ignore hints that not implemented
 		}
 	}
+	if leadingHintCnt > 1 {
+		// The join order can only be fixed by one LEADING hint, so all of them are ignored.
+		leadingJoinOrder = nil
+		b.ctx.GetSessionVars().StmtCtx.AppendWarning(ErrInternal.GenWithStack("We can only use one leading hint at most, when multiple leading hints are used, all leading hints will be invalid"))
+	}
 	b.tableHintInfo = append(b.tableHintInfo, tableHintInfo{
 		sortMergeJoinTables:         sortMergeTables,
 		broadcastJoinTables:         BCTables,
 		broadcastJoinPreferredLocal: BCJPreferLocalTables,
 		indexNestedLoopJoinTables:   indexNestedLoopJoinTables{INLJTables, INLHJTables, INLMJTables},
 		hashJoinTables:              hashJoinTables,
+		hashJoinBuildTables:         hashJoinBuildTables,
+		hashJoinProbeTables:         hashJoinProbeTables,
+		noHashJoinTables:            noHashJoinTables,
+		noMergeJoinTables:           noMergeJoinTables,
+		noIndexJoinTables:           noIndexJoinTables,
 		indexHintList:               indexHintList,
 		tiflashTables:               tiflashTables,
 		tikvTables:                  tikvTables,
@@ -3367,6 +3620,8 @@ func (b *PlanBuilder) pushTableHints(hints []*ast.TableOptimizerHint, currentLev
 		indexMergeHintList:          indexMergeHintList,
 		timeRangeHint:               timeRangeHint,
 		limitHints:                  limitHints,
+		leadingJoinOrder:            leadingJoinOrder,
+		noDecorrelate:               noDecorrelate,
 	})
 }
 
@@ -3388,6 +3643,12 @@ func (b *PlanBuilder) popTableHints() {
 	b.appendUnmatchedJoinHintWarning(HintBCJ, TiDBBroadCastJoin, hintInfo.broadcastJoinTables)
 	b.appendUnmatchedJoinHintWarning(HintBCJPreferLocal, "", hintInfo.broadcastJoinPreferredLocal)
 	b.appendUnmatchedJoinHintWarning(HintHJ, TiDBHashJoin, hintInfo.hashJoinTables)
+	b.appendUnmatchedJoinHintWarning(HintHashJoinBuild, "", hintInfo.hashJoinBuildTables)
+	b.appendUnmatchedJoinHintWarning(HintHashJoinProbe, "", hintInfo.hashJoinProbeTables)
+	b.appendUnmatchedJoinHintWarning(HintNoHashJoin, "", hintInfo.noHashJoinTables)
+	b.appendUnmatchedJoinHintWarning(HintNoMergeJoin, "", hintInfo.noMergeJoinTables)
+	b.appendUnmatchedJoinHintWarning(HintNoIndexJoin, "", hintInfo.noIndexJoinTables)
+	b.appendUnmatchedJoinHintWarning(HintLeading, "", hintInfo.leadingJoinOrder)
 	b.appendUnmatchedStorageHintWarning(hintInfo.tiflashTables, hintInfo.tikvTables)
 	b.tableHintInfo = b.tableHintInfo[:len(b.tableHintInfo)-1]
 }
@@ -3449,7 +3710,16 @@ func (b *PlanBuilder) TableHints() *tableHintInfo {
 func (b *PlanBuilder) buildSelect(ctx context.Context, sel *ast.SelectStmt) (p LogicalPlan, err error) {
 	b.pushSelectOffset(sel.QueryBlockOffset)
 	b.pushTableHints(sel.TableHints, sel.QueryBlockOffset)
+	isSubquery := b.buildingSubquery
+	b.buildingSubquery = false
+	if b.TableHints().noDecorrelate && !isSubquery {
+		b.ctx.GetSessionVars().StmtCtx.AppendWarning(ErrInternal.GenWithStack(
+			"NO_DECORRELATE() is inapplicable because it's not in an IN subquery, an EXISTS subquery, an ANY/ALL/SOME subquery or a scalar subquery"))
+	}
 	defer func() {
+		if isSubquery {
+			b.subqueryNoDecorrelate = b.TableHints().noDecorrelate
+		}
 		b.popSelectOffset()
 		// table hints are only visible in the current SELECT statement.
 		b.popTableHints()
@@ -4425,9 +4695,9 @@ func (b *PlanBuilder) buildProjUponView(ctx context.Context, dbName model.CIStr,
 
 // buildApplyWithJoinType builds apply plan with outerPlan and innerPlan, which apply join with particular join type for
 // every row from outerPlan and the whole innerPlan.
-func (b *PlanBuilder) buildApplyWithJoinType(outerPlan, innerPlan LogicalPlan, tp JoinType) LogicalPlan {
+func (b *PlanBuilder) buildApplyWithJoinType(outerPlan, innerPlan LogicalPlan, tp JoinType, noDecorrelate bool) LogicalPlan {
 	b.optFlag = b.optFlag | flagPredicatePushDown | flagBuildKeyInfo | flagDecorrelate
-	ap := LogicalApply{LogicalJoin: LogicalJoin{JoinType: tp}}.Init(b.ctx, b.getSelectOffset())
+	ap := LogicalApply{LogicalJoin: LogicalJoin{JoinType: tp}, NoDecorrelate: noDecorrelate}.Init(b.ctx, b.getSelectOffset())
 	ap.SetChildren(outerPlan, innerPlan)
 	ap.names = make([]*types.FieldName, outerPlan.Schema().Len()+innerPlan.Schema().Len())
 	copy(ap.names, outerPlan.OutputNames())
@@ -4444,7 +4714,7 @@ func (b *PlanBuilder) buildApplyWithJoinType(outerPlan, innerPlan LogicalPlan, t
 }
 
 // buildSemiApply builds apply plan with outerPlan and innerPlan, which apply semi-join for every row from outerPlan and the whole innerPlan.
-func (b *PlanBuilder) buildSemiApply(outerPlan, innerPlan LogicalPlan, condition []expression.Expression, asScalar, not bool) (LogicalPlan, error) {
+func (b *PlanBuilder) buildSemiApply(outerPlan, innerPlan LogicalPlan, condition []expression.Expression, asScalar, not, noDecorrelate bool) (LogicalPlan, error) {
 	b.optFlag = b.optFlag | flagPredicatePushDown | flagBuildKeyInfo | flagDecorrelate
 
 	join, err := b.buildSemiJoin(outerPlan, innerPlan, condition, asScalar, not)
@@ -4452,7 +4722,7 @@ func (b *PlanBuilder) buildSemiApply(outerPlan, innerPlan LogicalPlan, condition
 		return nil, err
 	}
 
-	ap := &LogicalApply{LogicalJoin: *join}
+	ap := &LogicalApply{LogicalJoin: *join, NoDecorrelate: noDecorrelate}
 	ap.tp = plancodec.TypeApply
 	ap.self = ap
 	return ap, nil
@@ -4513,10 +4783,32 @@ func (b *PlanBuilder) buildSemiJoin(outerPlan, innerPlan LogicalPlan, onConditio
 		if b.TableHints().ifPreferINLMJ(innerAlias) {
 			joinPlan.preferJoinType = preferRightAsINLMJInner
 		}
+		if b.TableHints().ifPreferHJBuild(outerAlias) {
+			joinPlan.preferJoinType |= preferLeftAsHJBuild
+		}
+		if b.TableHints().ifPreferHJBuild(innerAlias) {
+			joinPlan.preferJoinType |= preferRightAsHJBuild
+		}
+		if b.TableHints().ifPreferHJProbe(outerAlias) {
+			joinPlan.preferJoinType |= preferLeftAsHJProbe
+		}
+		if b.TableHints().ifPreferHJProbe(innerAlias) {
+			joinPlan.preferJoinType |= preferRightAsHJProbe
+		}
+		if b.TableHints().ifPreferNoHashJoin(outerAlias, innerAlias) {
+			joinPlan.preferJoinType |= preferNoHashJoin
+		}
+		if b.TableHints().ifPreferNoMergeJoin(outerAlias, innerAlias) {
+			joinPlan.preferJoinType |= preferNoMergeJoin
+		}
+		if b.TableHints().ifPreferNoIndexJoin(outerAlias, innerAlias) {
+			joinPlan.preferJoinType |= preferNoIndexJoin
+		}
 		// If there're multiple join hints, they're conflict.
-		if bits.OnesCount(joinPlan.preferJoinType) > 1 {
+		if containDifferentJoinTypes(joinPlan.preferJoinType) {
 			return nil, errors.New("Join hints are conflict, you can only specify one type of join")
 		}
+		joinPlan.ignoreConflictingNoJoinHints(b.TableHints())
 	}
 	return joinPlan, nil
 }
@@ -4718,9 +5010,13 @@ func (b *PlanBuilder) buildUpdate(ctx context.Context, update *ast.UpdateStmt) (
 		tblID2table[id], _ = b.is.TableByID(id)
 	}
 	updt.TblColPosInfos, err = buildColumns2Handle(updt.OutputNames(), tblID2Handle, tblID2table, true)
+	if err != nil {
+		return nil, err
+	}
 	updt.PartitionedTable = b.partitionedTable
 	updt.tblID2Table = tblID2table
-	return updt, err
+	updt.buildOnUpdateFKTriggers(b.ctx, b.is, tblID2table)
+	return updt, nil
 }
 
 type tblUpdateInfo struct {
@@ -5150,7 +5446,11 @@ func (b *PlanBuilder) buildDelete(ctx context.Context, delete *ast.DeleteStmt) (
 		tblID2table[id], _ = b.is.TableByID(id)
 	}
 	del.TblColPosInfos, err = buildColumns2Handle(del.names, tblID2Handle, tblID2table, false)
-	return del, err
+	if err != nil {
+		return nil, err
+	}
+	del.buildOnDeleteFKTriggers(b.ctx, b.is, tblID2table)
+	return del, nil
 }
 
 func resolveIndicesForTblID2Handle(tblID2Handle map[int64][]HandleCols, schema *expression.Schema) (map[int64][]HandleCols, error) {
@@ -6116,6 +6416,12 @@ func getInnerFromParenthesesAndUnaryPlus(expr ast.ExprNode) ast.ExprNode {
 // containDifferentJoinTypes checks whether `preferJoinType` contains different
 // join types.
 func containDifferentJoinTypes(preferJoinType uint) bool {
+	// The NO_XXX_JOIN hints don't choose a join type, and HASH_JOIN_BUILD and HASH_JOIN_PROBE choose the
+	// hash join, the conflicts between the build side and the probe side are checked when building hash joins.
+	preferJoinType &^= preferNoJoinMask
+	if preferJoinType&preferHJSideMask > 0 {
+		preferJoinType = preferJoinType&^preferHJSideMask | preferHashJoin
+	}
 	inlMask := preferRightAsINLJInner ^ preferLeftAsINLJInner
 	inlhjMask := preferRightAsINLHJInner ^ preferLeftAsINLHJInner
 	inlmjMask := preferRightAsINLMJInner ^ preferLeftAsINLMJInner
@@ -52,6 +52,7 @@ var (
 	_ LogicalPlan = &LogicalLock{}
 	_ LogicalPlan = &LogicalLimit{}
 	_ LogicalPlan = &LogicalWindow{}
+	_ LogicalPlan = &LogicalJSONTable{}
 )
 
 // JoinType contains CrossJoin, InnerJoin, LeftOuterJoin, RightOuterJoin, FullOuterJoin, SemiJoin.
@@ -110,10 +111,25 @@ const (
 	preferHashJoin
 	preferMergeJoin
 	preferBCJoin
+	preferLeftAsHJBuild
+	preferRightAsHJBuild
+	preferLeftAsHJProbe
+	preferRightAsHJProbe
+	preferNoHashJoin
+	preferNoMergeJoin
+	preferNoIndexJoin
 	preferHashAgg
 	preferStreamAgg
 )
 
+const (
+	// preferHJSideMask contains the bits set by the HASH_JOIN_BUILD and HASH_JOIN_PROBE hints.
+	preferHJSideMask = preferLeftAsHJBuild | preferRightAsHJBuild | preferLeftAsHJProbe | preferRightAsHJProbe
+	// preferNoJoinMask contains the bits set by the NO_HASH_JOIN, NO_MERGE_JOIN and NO_INDEX_JOIN hints, which
+	// only exclude some join algorithms instead of choosing one.
+	preferNoJoinMask = preferNoHashJoin | preferNoMergeJoin | preferNoIndexJoin
+)
+
 const (
 	preferTiKV = 1 << iota
 	preferTiFlash
@@ -131,6 +147,9 @@ type LogicalJoin struct {
 	// hintInfo stores the join algorithm hint information specified by client.
 	hintInfo       *tableHintInfo
 	preferJoinType uint
+	// leadingJoinOrder stores the tables of the LEADING hint if the hint matches
+	// any side of this join. It's used by the join reorder rule.
+	leadingJoinOrder []hintTableInfo
 
 	EqualConditions []*expression.ScalarFunction
 	LeftConditions  expression.CNFExprs
@@ -458,6 +477,8 @@ type LogicalApply struct {
 	LogicalJoin
 
 	CorCols []*expression.CorrelatedColumn
+	// NoDecorrelate is set by the NO_DECORRELATE hint of the subquery, the Apply is kept by the decorrelate rule then.
+	NoDecorrelate bool
 }
 
 // ExtractCorrelatedCols implements LogicalPlan interface.
@@ -981,6 +1002,9 @@ type LogicalUnionAll struct {
 // LogicalPartitionUnionAll represents the LogicalUnionAll plan is for partition table.
 type LogicalPartitionUnionAll struct {
 	LogicalUnionAll
+
+	// deferredPruning indicates the partitions are pruned when the plan is executed.
+	deferredPruning bool
 }
 
 // LogicalSort stands for the order by plan.
@@ -1286,6 +1310,25 @@ type LogicalCTE struct {
 	seedStat  *property.StatsInfo
 }
 
+// LogicalJSONTable is the logical plan of the JSON_TABLE table function. The document
+// may refer to the columns of the preceding tables, in which case the plan is the
+// inner child of an Apply, like a lateral derived table.
+type LogicalJSONTable struct {
+	logicalSchemaProducer
+
+	// Doc is the JSON document, it never refers to the columns of the children
+	// but may contain correlated columns.
+	Doc     expression.Expression
+	Path    string
+	Columns []*ast.JSONTableColumn
+	AsName  model.CIStr
+}
+
+// ExtractCorrelatedCols implements LogicalPlan interface.
+func (p *LogicalJSONTable) ExtractCorrelatedCols() []*expression.CorrelatedColumn {
+	return expression.ExtractCorColumns(p.Doc)
+}
+
 // LogicalCTETable is for CTE table
 type LogicalCTETable struct {
 	logicalSchemaProducer
@@ -0,0 +1,264 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/format"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	driver "github.com/pingcap/tidb/types/parser_driver"
+	"github.com/pingcap/tidb/util/hint"
+)
+
+// NonPreparedPlanCacheable checks whether the plan of the statement sent by the text protocol can be cached by the
+// non-prepared plan cache. Besides the rules of the prepared plan cache, only the simple SELECT, UPDATE, DELETE and
+// INSERT ... VALUES statements without set operators, CTEs, window functions or stale reads are cached.
+func NonPreparedPlanCacheable(sctx sessionctx.Context, node ast.Node, is infoschema.InfoSchema) bool {
+	switch x := node.(type) {
+	case *ast.SelectStmt:
+		if x.Kind != ast.SelectStmtKindSelect || x.SelectIntoOpt != nil {
+			return false
+		}
+	case *ast.InsertStmt:
+		if x.Select != nil {
+			return false
+		}
+	case *ast.UpdateStmt, *ast.DeleteStmt:
+	default:
+		return false
+	}
+	checker := nonPreparedCacheableChecker{cacheable: true}
+	node.Accept(&checker)
+	return checker.cacheable && CacheableWithCtx(sctx, node, is)
+}
+
+// nonPreparedCacheableChecker checks the rules of the non-prepared plan cache which are not covered by
+// cacheableChecker.
+type nonPreparedCacheableChecker struct {
+	cacheable bool
+}
+
+// Enter implements Visitor interface.
+func (checker *nonPreparedCacheableChecker) Enter(in ast.Node) (out ast.Node, skipChildren bool) {
+	switch node := in.(type) {
+	case *ast.SetOprStmt, *ast.WithClause, *ast.WindowFuncExpr, *ast.MatchAgainst, *driver.ParamMarkerExpr:
+		// The statements containing parameter markers are the parameterized statements themselves, which are
+		// optimized again when the cached plan can't be used.
+		checker.cacheable = false
+		return in, true
+	case *ast.TableName:
+		if node.AsOf != nil {
+			checker.cacheable = false
+			return in, true
+		}
+	}
+	return in, false
+}
+
+// Leave implements Visitor interface.
+func (checker *nonPreparedCacheableChecker) Leave(in ast.Node) (out ast.Node, ok bool) {
+	return in, checker.cacheable
+}
+
+// nonParameterizableFuncs are the functions whose arguments must be literals when the plan is built.
+var nonParameterizableFuncs = map[string]struct{}{
+	ast.NameConst:          {},
+	ast.AggFuncGroupConcat: {},
+	ast.Now:                {},
+	ast.CurrentTimestamp:   {},
+	ast.Sysdate:            {},
+	ast.CurrentTime:        {},
+	ast.Curtime:            {},
+	ast.UTCTime:            {},
+	ast.UTCTimestamp:       {},
+	ast.LocalTime:          {},
+	ast.LocalTimestamp:     {},
+}
+
+// literalParameterizer replaces the literals in the statement by the parameter markers. The literals in the
+// select fields, GROUP BY, HAVING, ORDER BY and LIMIT clauses are kept because they decide the names of the output
+// columns or the shape of the plan, and the expressions in them may refer to the select fields.
+type literalParameterizer struct {
+	markers  []*driver.ParamMarkerExpr
+	literals []*driver.ValueExpr
+}
+
+// Enter implements Visitor interface.
+func (p *literalParameterizer) Enter(in ast.Node) (out ast.Node, skipChildren bool) {
+	switch node := in.(type) {
+	case *ast.SelectField, *ast.GroupByClause, *ast.HavingClause, *ast.OrderByClause, *ast.Limit:
+		return in, true
+	case *ast.FuncCallExpr:
+		if _, ok := nonParameterizableFuncs[node.FnName.L]; ok {
+			return in, true
+		}
+	case *ast.AggregateFuncExpr:
+		if _, ok := nonParameterizableFuncs[strings.ToLower(node.F)]; ok {
+			return in, true
+		}
+	}
+	return in, false
+}
+
+// Leave implements Visitor interface.
+func (p *literalParameterizer) Leave(in ast.Node) (out ast.Node, ok bool) {
+	literal, ok := in.(*driver.ValueExpr)
+	if !ok || !parameterizable(literal) {
+		return in, true
+	}
+	marker := &driver.ParamMarkerExpr{Offset: len(p.markers), Order: len(p.markers)}
+	marker.Datum = literal.Datum
+	p.markers = append(p.markers, marker)
+	p.literals = append(p.literals, literal)
+	return marker, true
+}
+
+// parameterizable checks whether the literal has the same type as the parameter marker of its value, so that the
+// parameterized statement has the same semantics. Only the integers and the strings of the default collation are
+// parameterized, the decimals are kept because their precisions are part of the types.
+func parameterizable(literal *driver.ValueExpr) bool {
+	switch literal.Kind() {
+	case types.KindInt64, types.KindUint64:
+		return literal.Type.Flag&mysql.IsBooleanFlag == 0
+	case types.KindString:
+		return literal.Type.Charset == mysql.DefaultCharset && literal.Type.Collate == mysql.DefaultCollationName
+	}
+	return false
+}
+
+// literalRestorer puts the literals replaced by literalParameterizer back.
+type literalRestorer struct {
+	literals map[*driver.ParamMarkerExpr]*driver.ValueExpr
+}
+
+// Enter implements Visitor interface.
+func (r *literalRestorer) Enter(in ast.Node) (out ast.Node, skipChildren bool) {
+	return in, false
+}
+
+// Leave implements Visitor interface.
+func (r *literalRestorer) Leave(in ast.Node) (out ast.Node, ok bool) {
+	if marker, ok := in.(*driver.ParamMarkerExpr); ok {
+		if literal, ok := r.literals[marker]; ok {
+			return literal, true
+		}
+	}
+	return in, true
+}
+
+// parameterizedSQL restores the parameterized statement. The texts of the select fields are appended because they
+// are the names of the output columns.
+func parameterizedSQL(stmt ast.StmtNode) (string, error) {
+	var sb strings.Builder
+	if err := stmt.Restore(format.NewRestoreCtx(format.DefaultRestoreFlags, &sb)); err != nil {
+		return "", err
+	}
+	if sel, ok := stmt.(*ast.SelectStmt); ok && sel.Fields != nil {
+		for _, field := range sel.Fields.Fields {
+			sb.WriteString("\n")
+			sb.WriteString(field.Text())
+		}
+	}
+	return sb.String(), nil
+}
+
+// ParameterizeAST returns the SQL text of the statement whose literals are replaced by the parameter markers, and
+// the values of the parameters. The statement is unchanged after it returns.
+func ParameterizeAST(stmt ast.StmtNode) (string, []types.Datum, error) {
+	var p literalParameterizer
+	stmt.Accept(&p)
+	sql, err := parameterizedSQL(stmt)
+	restorer := &literalRestorer{literals: make(map[*driver.ParamMarkerExpr]*driver.ValueExpr, len(p.markers))}
+	for i, marker := range p.markers {
+		restorer.literals[marker] = p.literals[i]
+	}
+	stmt.Accept(restorer)
+	if err != nil {
+		return "", nil, err
+	}
+	params := make([]types.Datum, len(p.literals))
+	for i, literal := range p.literals {
+		params[i] = literal.Datum
+	}
+	return sql, params, nil
+}
+
+// NewNonPreparedCachedStmt parses the statement again and parameterizes it to the statement cached by the
+// non-prepared plan cache. It returns nil if the parameterized statement isn't the same as paramSQL, which is
+// returned by ParameterizeAST for the statement.
+func NewNonPreparedCachedStmt(ctx context.Context, sctx sessionctx.Context, stmt ast.StmtNode, paramSQL string) (*CachedPrepareStmt, error) {
+	vars := sctx.GetSessionVars()
+	charset, collation := vars.GetCharsetInfo()
+	p := parser.New()
+	p.SetParserConfig(vars.BuildParserConfig())
+	stmts, _, err := p.ParseSQL(stmt.Text(), parser.CharsetConnection(charset), parser.CollationConnection(collation))
+	if err != nil {
+		return nil, err
+	}
+	if len(stmts) != 1 {
+		return nil, errors.Errorf("invalid statement for the non-prepared plan cache: %s", stmt.Text())
+	}
+	paramStmt := stmts[0]
+	var parameterizer literalParameterizer
+	paramStmt.Accept(&parameterizer)
+	ret := &PreprocessorReturn{}
+	if err := Preprocess(sctx, paramStmt, InPrepare, WithPreprocessorReturn(ret)); err != nil {
+		return nil, err
+	}
+	sql, err := parameterizedSQL(paramStmt)
+	if err != nil || sql != paramSQL {
+		return nil, err
+	}
+
+	params := make([]ast.ParamMarkerExpr, len(parameterizer.markers))
+	for i, marker := range parameterizer.markers {
+		marker.Datum.SetNull()
+		params[i] = marker
+	}
+	prepared := &ast.Prepared{
+		Stmt:          paramStmt,
+		StmtType:      vars.StmtCtx.StmtType,
+		Params:        params,
+		SchemaVersion: ret.InfoSchema.SchemaMetaVersion(),
+		UseCache:      true,
+	}
+	// Build the logical plan to collect the information for checking the privileges when the cached plan is used.
+	// The parameters are not evaluated, and the warnings are discarded because the statement is built again.
+	vars.PlanID = 0
+	vars.PlanColumnID = 0
+	warnCount := int(vars.StmtCtx.WarningCount())
+	builder, _ := NewPlanBuilder().Init(sctx, ret.InfoSchema, &hint.BlockHintProcessor{})
+	_, err = builder.Build(ctx, paramStmt)
+	vars.StmtCtx.TruncateWarnings(warnCount)
+	if err != nil {
+		return nil, err
+	}
+	return &CachedPrepareStmt{
+		PreparedAst:      prepared,
+		VisitInfos:       builder.GetVisitInfo(),
+		ForUpdateRead:    builder.GetIsForUpdateRead(),
+		StmtText:         stmt.Text(),
+		StmtDB:           vars.CurrentDB,
+		ParameterizedSQL: paramSQL,
+	}, nil
+}
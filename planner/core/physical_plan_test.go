@@ -1056,6 +1056,12 @@ func (s *testPlanSuite) TestExplainJoinHints(c *C) {
 	tk.MustQuery("explain format='hint' select /*+ inl_hash_join(t2) */ * from t t1 inner join t t2 on t1.b = t2.b and t1.c = 1").Check(testkit.Rows(
 		"use_index(@`sel_1` `test`.`t1` `c`), use_index(@`sel_1` `test`.`t2` `b`), inl_hash_join(@`sel_1` `test`.`t2`), inl_hash_join(`t2`)",
 	))
+	tk.MustQuery("explain format='hint' select /*+ hash_join_build(t1) */ * from t t1 inner join t t2 on t1.a = t2.a").Check(testkit.Rows(
+		"use_index(@`sel_1` `test`.`t1` ), use_index(@`sel_1` `test`.`t2` ), hash_join(@`sel_1` `test`.`t1`), hash_join_build(@`sel_1` `test`.`t1`), hash_join_build(`t1`)",
+	))
+	tk.MustQuery("explain format='hint' select * from t t1 where exists (select /*+ no_decorrelate() */ 1 from t t2 where t2.a = t1.a)").Check(testkit.Rows(
+		"use_index(@`sel_1` `test`.`t1` ), use_index(@`sel_2` `test`.`t2` ), no_decorrelate(@`sel_2`)",
+	))
 }
 
 func (s *testPlanSuite) TestAggToCopHint(c *C) {
@@ -67,6 +67,7 @@ var (
 	_ PhysicalPlan = &PhysicalShuffleReceiverStub{}
 	_ PhysicalPlan = &BatchPointGetPlan{}
 	_ PhysicalPlan = &PhysicalTableSample{}
+	_ PhysicalPlan = &PhysicalJSONTable{}
 )
 
 // PhysicalTableReader is the table reader in tidb.
@@ -301,6 +302,7 @@ func (p *PhysicalIndexLookUpReader) Clone() (PhysicalPlan, error) {
 	if p.PushedLimit != nil {
 		cloned.PushedLimit = p.PushedLimit.Clone()
 	}
+	cloned.CommonHandleCols = cloneCols(p.CommonHandleCols)
 	return cloned, nil
 }
 
@@ -667,6 +669,8 @@ type PhysicalApply struct {
 	CanUseCache bool
 	Concurrency int
 	OuterSchema []*expression.CorrelatedColumn
+	// NoDecorrelate indicates the apply is kept by the NO_DECORRELATE hint.
+	NoDecorrelate bool
 }
 
 // Clone implements PhysicalPlan interface.
@@ -680,6 +684,7 @@ func (la *PhysicalApply) Clone() (PhysicalPlan, error) {
 	cloned.PhysicalHashJoin = *hj
 	cloned.CanUseCache = la.CanUseCache
 	cloned.Concurrency = la.Concurrency
+	cloned.NoDecorrelate = la.NoDecorrelate
 	for _, col := range la.OuterSchema {
 		cloned.OuterSchema = append(cloned.OuterSchema, col.Clone().(*expression.CorrelatedColumn))
 	}
@@ -982,6 +987,10 @@ type PhysicalUnionAll struct {
 	physicalSchemaProducer
 
 	mpp bool
+
+	// DeferredPruning indicates the children of the partition union are pruned when the plan is executed, because
+	// the pruning conditions contain the parameters of the cached plan.
+	DeferredPruning bool
 }
 
 // Clone implements PhysicalPlan interface.
@@ -992,6 +1001,8 @@ func (p *PhysicalUnionAll) Clone() (PhysicalPlan, error) {
 		return nil, err
 	}
 	cloned.physicalSchemaProducer = *base
+	cloned.mpp = p.mpp
+	cloned.DeferredPruning = p.DeferredPruning
 	return cloned, nil
 }
 
@@ -1424,6 +1435,21 @@ type PhysicalCTE struct {
 	cteAsName model.CIStr
 }
 
+// PhysicalJSONTable is the physical operator of the JSON_TABLE table function.
+type PhysicalJSONTable struct {
+	physicalSchemaProducer
+
+	Doc     expression.Expression
+	Path    string
+	Columns []*ast.JSONTableColumn
+	AsName  model.CIStr
+}
+
+// ExtractCorrelatedCols implements PhysicalPlan interface.
+func (p *PhysicalJSONTable) ExtractCorrelatedCols() []*expression.CorrelatedColumn {
+	return expression.ExtractCorColumns(p.Doc)
+}
+
 // PhysicalCTETable is for CTE table.
 type PhysicalCTETable struct {
 	physicalSchemaProducer
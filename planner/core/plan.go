@@ -698,3 +698,8 @@ func (p *basePhysicalPlan) SetChild(i int, child PhysicalPlan) {
 func (p *basePlan) SCtx() sessionctx.Context {
 	return p.ctx
 }
+
+// setCtx sets the session context of the plan, it's used when the plan is cloned for another session.
+func (p *basePlan) setCtx(ctx sessionctx.Context) {
+	p.ctx = ctx
+}
@@ -315,7 +315,12 @@ func (p *PhysicalIndexScan) ToPB(ctx sessionctx.Context, _ kv.StoreType) (*tipb.
 		} else if col.ID == model.ExtraPidColID {
 			columns = append(columns, model.NewExtraPartitionIDColInfo())
 		} else {
-			columns = append(columns, FindColumnInfoByID(tableColumns, col.ID))
+			colInfo := FindColumnInfoByID(tableColumns, col.ID)
+			if p.Index.MVIndex && colInfo.FieldType.ArrayElem != nil {
+				colInfo = colInfo.Clone()
+				colInfo.FieldType = *colInfo.FieldType.ArrayElem
+			}
+			columns = append(columns, colInfo)
 		}
 	}
 	var pkColIds []int64
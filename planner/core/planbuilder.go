@@ -88,6 +88,11 @@ type tableHintInfo struct {
 	broadcastJoinTables         []hintTableInfo
 	broadcastJoinPreferredLocal []hintTableInfo
 	hashJoinTables              []hintTableInfo
+	hashJoinBuildTables         []hintTableInfo
+	hashJoinProbeTables         []hintTableInfo
+	noHashJoinTables            []hintTableInfo
+	noMergeJoinTables           []hintTableInfo
+	noIndexJoinTables           []hintTableInfo
 	indexHintList               []indexHintInfo
 	tiflashTables               []hintTableInfo
 	tikvTables                  []hintTableInfo
@@ -95,6 +100,8 @@ type tableHintInfo struct {
 	indexMergeHintList          []indexHintInfo
 	timeRangeHint               ast.HintTimeRange
 	limitHints                  limitHintInfo
+	leadingJoinOrder            []hintTableInfo
+	noDecorrelate               bool
 }
 
 type limitHintInfo struct {
@@ -180,7 +187,8 @@ func tableNames2HintTableInfo(ctx sessionctx.Context, hintName string, hintTable
 			tableInfo.dbName = defaultDBName
 		}
 		switch hintName {
-		case TiDBMergeJoin, HintSMJ, TiDBIndexNestedLoopJoin, HintINLJ, HintINLHJ, HintINLMJ, TiDBHashJoin, HintHJ:
+		case TiDBMergeJoin, HintSMJ, TiDBIndexNestedLoopJoin, HintINLJ, HintINLHJ, HintINLMJ, TiDBHashJoin, HintHJ, HintLeading,
+			HintHashJoinBuild, HintHashJoinProbe, HintNoHashJoin, HintNoMergeJoin, HintNoIndexJoin:
 			if len(tableInfo.partitions) > 0 {
 				isInapplicable = true
 			}
@@ -224,6 +232,26 @@ func (info *tableHintInfo) ifPreferHashJoin(tableNames ...*hintTableInfo) bool {
 	return info.matchTableName(tableNames, info.hashJoinTables)
 }
 
+func (info *tableHintInfo) ifPreferHJBuild(tableNames ...*hintTableInfo) bool {
+	return info.matchTableName(tableNames, info.hashJoinBuildTables)
+}
+
+func (info *tableHintInfo) ifPreferHJProbe(tableNames ...*hintTableInfo) bool {
+	return info.matchTableName(tableNames, info.hashJoinProbeTables)
+}
+
+func (info *tableHintInfo) ifPreferNoHashJoin(tableNames ...*hintTableInfo) bool {
+	return info.matchTableName(tableNames, info.noHashJoinTables)
+}
+
+func (info *tableHintInfo) ifPreferNoMergeJoin(tableNames ...*hintTableInfo) bool {
+	return info.matchTableName(tableNames, info.noMergeJoinTables)
+}
+
+func (info *tableHintInfo) ifPreferNoIndexJoin(tableNames ...*hintTableInfo) bool {
+	return info.matchTableName(tableNames, info.noIndexJoinTables)
+}
+
 func (info *tableHintInfo) ifPreferINLJ(tableNames ...*hintTableInfo) bool {
 	return info.matchTableName(tableNames, info.indexNestedLoopJoinTables.inljTables)
 }
@@ -236,6 +264,10 @@ func (info *tableHintInfo) ifPreferINLMJ(tableNames ...*hintTableInfo) bool {
 	return info.matchTableName(tableNames, info.indexNestedLoopJoinTables.inlmjTables)
 }
 
+func (info *tableHintInfo) ifPreferLeading(tableNames ...*hintTableInfo) bool {
+	return info.matchTableName(tableNames, info.leadingJoinOrder)
+}
+
 func (info *tableHintInfo) ifPreferTiFlash(tableName *hintTableInfo) *hintTableInfo {
 	if tableName == nil {
 		return nil
@@ -513,6 +545,14 @@ type PlanBuilder struct {
 	isForUpdateRead             bool
 	allocIDForCTEStorage        int
 	buildingRecursivePartForCTE bool
+	// subqueryAsApply indicates the rewritten plan is kept by the caller, so the uncorrelated subqueries can be built
+	// as Apply instead of being evaluated when the plan is built for the plan cache.
+	subqueryAsApply bool
+	// buildingSubquery indicates the SELECT block to be built is a subquery in an expression, which can be kept
+	// as Apply by the NO_DECORRELATE hint.
+	buildingSubquery bool
+	// subqueryNoDecorrelate is set by the subquery built last time if it has the NO_DECORRELATE hint.
+	subqueryNoDecorrelate bool
 }
 
 type handleColHelper struct {
@@ -729,7 +769,7 @@ func (b *PlanBuilder) Build(ctx context.Context, node ast.Node) (Plan, error) {
 		*ast.BeginStmt, *ast.CommitStmt, *ast.RollbackStmt, *ast.CreateUserStmt, *ast.SetPwdStmt, *ast.AlterInstanceStmt,
 		*ast.GrantStmt, *ast.DropUserStmt, *ast.AlterUserStmt, *ast.RevokeStmt, *ast.KillStmt, *ast.DropStatsStmt,
 		*ast.GrantRoleStmt, *ast.RevokeRoleStmt, *ast.SetRoleStmt, *ast.SetDefaultRoleStmt, *ast.ShutdownStmt,
-		*ast.RenameUserStmt:
+		*ast.RenameUserStmt, *ast.SetSessionStatesStmt:
 		return b.buildSimple(ctx, node.(ast.StmtNode))
 	case ast.DDLNode:
 		return b.buildDDL(ctx, x)
@@ -1373,7 +1413,7 @@ func (b *PlanBuilder) buildAdmin(ctx context.Context, as *ast.AdminStmt) (Plan,
 		ret = p
 	case ast.AdminResetTelemetryID:
 		return &AdminResetTelemetryID{}, nil
-	case ast.AdminReloadStatistics:
+	case ast.AdminReloadStatistics, ast.AdminFlushInstancePlanCache:
 		return &Simple{Statement: as}, nil
 	default:
 		return nil, ErrUnsupportedType.GenWithStack("Unsupported ast.AdminStmt(%T) for buildAdmin", as)
@@ -1555,6 +1595,10 @@ func (b *PlanBuilder) buildPhysicalIndexLookUpReaders(ctx context.Context, dbNam
 			// Skip checking clustered index.
 			continue
 		}
+		if idxInfo.MVIndex {
+			// Skip checking multi-valued index, its entries don't correspond to the rows one by one.
+			continue
+		}
 		if idxInfo.State != model.StatePublic {
 			logutil.Logger(ctx).Info("build physical index lookup reader, the index isn't public",
 				zap.String("index", idxInfo.Name.O),
@@ -3097,6 +3141,7 @@ func (b *PlanBuilder) buildInsert(ctx context.Context, insert *ast.InsertStmt) (
 		return nil, err
 	}
 
+	insertPlan.buildOnInsertFKTriggers(b.ctx, b.is, tn.Schema)
 	err = insertPlan.ResolveIndices()
 	return insertPlan, err
 }
@@ -4332,6 +4377,9 @@ func buildShowSchema(s *ast.ShowStmt, isView bool, isSequence bool) (schema *exp
 	case ast.ShowBuiltins:
 		names = []string{"Supported_builtin_functions"}
 		ftypes = []byte{mysql.TypeVarchar}
+	case ast.ShowSessionStates:
+		names = []string{"Session_states"}
+		ftypes = []byte{mysql.TypeVarchar}
 	case ast.ShowBackups, ast.ShowRestores:
 		names = []string{"Destination", "State", "Progress", "Queue_time", "Execution_time", "Finish_time", "Connection", "Message"}
 		ftypes = []byte{mysql.TypeVarchar, mysql.TypeVarchar, mysql.TypeDouble, mysql.TypeDatetime, mysql.TypeDatetime, mysql.TypeDatetime, mysql.TypeLonglong, mysql.TypeVarchar}
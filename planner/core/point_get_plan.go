@@ -891,6 +891,10 @@ func tryPointGetPlan(ctx sessionctx.Context, selStmt *ast.SelectStmt, check bool
 		}
 		// Take partition selection into consideration.
 		if len(tblName.PartitionNames) > 0 {
+			// The partition selection isn't checked again when the partition is relocated for the cached plan.
+			if pairs[pos].param != nil && ctx.GetSessionVars().StmtCtx.UseCache {
+				ctx.GetSessionVars().StmtCtx.SetSkipPlanCache("query has partition selection")
+			}
 			if !partitionNameInSet(partitionInfo.Name, tblName.PartitionNames) {
 				p := newPointGetPlan(ctx, tblName.Schema.O, schema, tbl, names)
 				p.IsTableDual = true
@@ -1388,6 +1392,7 @@ func buildPointUpdatePlan(ctx sessionctx.Context, pointPlan PhysicalPlan, dbName
 			updatePlan.PartitionedTable = append(updatePlan.PartitionedTable, pt)
 		}
 	}
+	updatePlan.buildOnUpdateFKTriggers(ctx, is, updatePlan.tblID2Table)
 	return updatePlan
 }
 
@@ -1471,6 +1476,9 @@ func buildPointDeletePlan(ctx sessionctx.Context, pointPlan PhysicalPlan, dbName
 			},
 		},
 	}.Init(ctx)
+	is := ctx.GetInfoSchema().(infoschema.InfoSchema)
+	t, _ := is.TableByID(tbl.ID)
+	delPlan.buildOnDeleteFKTriggers(ctx, is, map[int64]table.Table{tbl.ID: t})
 	return delPlan
 }
 
@@ -1524,69 +1532,99 @@ func getPartitionInfo(ctx sessionctx.Context, tbl *model.TableInfo, pairs []name
 		return nil, 0, false
 	}
 
-	switch pi.Type {
-	case model.PartitionTypeHash:
-		expr := partitionExpr.OrigExpr
-		col, ok := expr.(*ast.ColumnNameExpr)
-		if !ok {
-			return nil, 0, false
-		}
-
-		partitionColName := col.Name
-		if partitionColName == nil {
-			return nil, 0, false
+	partitionColName := getPartitionColumnName(tbl, partitionExpr)
+	if partitionColName == "" {
+		return nil, 0, false
+	}
+	for i, pair := range pairs {
+		if partitionColName == pair.colName {
+			// val cannot be Null, we've check this in func getNameValuePairs
+			def, isTableDual := locatePartition(pi, partitionExpr, pair.value.GetInt64())
+			return def, i, isTableDual
 		}
+	}
+	return nil, 0, false
+}
 
-		for i, pair := range pairs {
-			if partitionColName.Name.L == pair.colName {
-				val := pair.value.GetInt64()
-				pos := math.Abs(val % int64(pi.Num))
-				return &pi.Definitions[pos], i, false
-			}
+// getPartitionColumnName returns the name of the partition column, it returns empty string if the partition can't
+// be located by the value of the column.
+func getPartitionColumnName(tbl *model.TableInfo, partitionExpr *tables.PartitionExpr) string {
+	pi := tbl.GetPartitionInfo()
+	switch pi.Type {
+	case model.PartitionTypeHash:
+		col, ok := partitionExpr.OrigExpr.(*ast.ColumnNameExpr)
+		if !ok || col.Name == nil {
+			return ""
 		}
+		return col.Name.Name.L
 	case model.PartitionTypeRange:
 		// left range columns partition for future development
 		if len(pi.Columns) == 0 {
 			if col, ok := partitionExpr.Expr.(*expression.Column); ok {
-				colInfo := findColNameByColID(tbl.Columns, col)
-				for i, pair := range pairs {
-					if colInfo.Name.L == pair.colName {
-						val := pair.value.GetInt64() // val cannot be Null, we've check this in func getNameValuePairs
-						unsigned := mysql.HasUnsignedFlag(col.GetType().Flag)
-						ranges := partitionExpr.ForRangePruning
-						length := len(ranges.LessThan)
-						pos := sort.Search(length, func(i int) bool {
-							return ranges.Compare(i, val, unsigned) > 0
-						})
-						if pos >= 0 && pos < length {
-							return &pi.Definitions[pos], i, false
-						}
-						return nil, 0, true
-					}
-				}
+				return findColNameByColID(tbl.Columns, col).Name.L
 			}
 		}
 	case model.PartitionTypeList:
 		// left list columns partition for future development
 		if partitionExpr.ForListPruning.ColPrunes == nil {
-			locateExpr := partitionExpr.ForListPruning.LocateExpr
-			if locateExpr, ok := locateExpr.(*expression.Column); ok {
-				colInfo := findColNameByColID(tbl.Columns, locateExpr)
-				for i, pair := range pairs {
-					if colInfo.Name.L == pair.colName {
-						val := pair.value.GetInt64() // val cannot be Null, we've check this in func getNameValuePairs
-						isNull := false
-						pos := partitionExpr.ForListPruning.LocatePartition(val, isNull)
-						if pos >= 0 {
-							return &pi.Definitions[pos], i, false
-						}
-						return nil, 0, true
-					}
-				}
+			if col, ok := partitionExpr.ForListPruning.LocateExpr.(*expression.Column); ok {
+				return findColNameByColID(tbl.Columns, col).Name.L
 			}
 		}
 	}
-	return nil, 0, false
+	return ""
+}
+
+// locatePartition locates the partition by the value of the partition column, it returns true if the value doesn't
+// belong to any partition.
+func locatePartition(pi *model.PartitionInfo, partitionExpr *tables.PartitionExpr, val int64) (*model.PartitionDefinition, bool) {
+	switch pi.Type {
+	case model.PartitionTypeHash:
+		pos := math.Abs(val % int64(pi.Num))
+		return &pi.Definitions[pos], false
+	case model.PartitionTypeRange:
+		unsigned := mysql.HasUnsignedFlag(partitionExpr.Expr.GetType().Flag)
+		ranges := partitionExpr.ForRangePruning
+		length := len(ranges.LessThan)
+		pos := sort.Search(length, func(i int) bool {
+			return ranges.Compare(i, val, unsigned) > 0
+		})
+		if pos >= 0 && pos < length {
+			return &pi.Definitions[pos], false
+		}
+	case model.PartitionTypeList:
+		isNull := false
+		pos := partitionExpr.ForListPruning.LocatePartition(val, isNull)
+		if pos >= 0 {
+			return &pi.Definitions[pos], false
+		}
+	}
+	return nil, true
+}
+
+// relocatePartition locates the partition again after the handle or the index values of the cached plan are
+// rebuilt by the parameters.
+func (p *PointGetPlan) relocatePartition(sctx sessionctx.Context) error {
+	partitionExpr := getPartitionExpr(sctx, p.TblInfo)
+	if partitionExpr == nil {
+		return errors.New("point get for partition table can not use plan cache")
+	}
+	var val int64
+	if p.IndexInfo == nil {
+		val = p.Handle.IntValue()
+	} else {
+		var err error
+		val, err = p.IndexValues[p.partitionColumnPos].ToInt64(sctx.GetSessionVars().StmtCtx)
+		if err != nil {
+			return err
+		}
+	}
+	def, isTableDual := locatePartition(p.TblInfo.GetPartitionInfo(), partitionExpr, val)
+	if isTableDual {
+		return errors.New("point get for partition table can not use plan cache")
+	}
+	p.PartitionInfo = def
+	return nil
 }
 
 func findPartitionIdx(idxInfo *model.IndexInfo, pos int, pairs []nameValuePair) int {
@@ -1633,6 +1671,8 @@ func getPartitionColumnPos(idx *model.IndexInfo, partitionExpr *tables.Partition
 		} else {
 			return 0, errors.Errorf("unsupported partition type in BatchGet")
 		}
+	default:
+		return 0, errors.Errorf("unsupported partition type in BatchGet")
 	}
 
 	for i, idxCol := range idx.Columns {
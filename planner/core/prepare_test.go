@@ -28,11 +28,13 @@ import (
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/metrics"
+	"github.com/pingcap/tidb/parser"
 	"github.com/pingcap/tidb/parser/auth"
 	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/session"
 	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/util/collate"
 	"github.com/pingcap/tidb/util/hint"
 	"github.com/pingcap/tidb/util/israce"
 	"github.com/pingcap/tidb/util/kvcache"
@@ -2115,3 +2117,361 @@ func (s *testPlanSerialSuite) TestPartitionWithVariedDatasources(c *C) {
 		}
 	}
 }
+
+func (s *testPrepareSerialSuite) TestNonPreparedPlanCache(c *C) {
+	defer testleak.AfterTest(c)()
+	store, dom, err := newStoreWithBootstrap()
+	c.Assert(err, IsNil)
+	tk := testkit.NewTestKit(c, store)
+	orgEnable := core.PreparedPlanCacheEnabled()
+	defer func() {
+		dom.Close()
+		err = store.Close()
+		c.Assert(err, IsNil)
+		core.SetPreparedPlanCache(orgEnable)
+	}()
+	core.SetPreparedPlanCache(true)
+	tk.Se, err = session.CreateSession4TestWithOpt(store, &session.Opt{
+		PreparedPlanCache: kvcache.NewSimpleLRUCache(100, 0.1, math.MaxUint64),
+	})
+	c.Assert(err, IsNil)
+
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int, b varchar(10), c int, key(a))")
+	tk.MustExec("insert into t values(1, 'x', 1), (2, 'y', 2), (3, 'z', 3)")
+
+	// The non-prepared plan cache is disabled by default.
+	tk.MustQuery("select * from t where a = 1").Check(testkit.Rows("1 x 1"))
+	tk.MustQuery("select * from t where a = 1").Check(testkit.Rows("1 x 1"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+
+	tk.MustExec("set @@tidb_enable_non_prepared_plan_cache = 1")
+	tk.MustQuery("select * from t where a = 1").Check(testkit.Rows("1 x 1"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk.MustQuery("select * from t where a = 2").Check(testkit.Rows("2 y 2"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	tk.MustQuery("select * from t where a = 3 and b = 'z'").Check(testkit.Rows("3 z 3"))
+	tk.MustQuery("select * from t where a = 1 and b = 'x'").Check(testkit.Rows("1 x 1"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	// The literals of different types are not parameterized to the same statement.
+	tk.MustQuery("select * from t where a = '2'").Check(testkit.Rows("2 y 2"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk.MustQuery("select * from t where a = 1.0").Check(testkit.Rows("1 x 1"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	// The literals in the select fields are kept.
+	tk.MustQuery("select a, 1 from t where a = 1").Check(testkit.Rows("1 1"))
+	tk.MustQuery("select a, 2 from t where a = 1").Check(testkit.Rows("1 2"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+
+	tk.MustExec("update t set c = 10 where a = 1")
+	tk.MustExec("update t set c = 20 where a = 2")
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	tk.MustExec("delete from t where a = 3")
+	tk.MustExec("insert into t values(3, 'z', 30)")
+	tk.MustExec("insert into t values(4, 'w', 40)")
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	tk.MustExec("delete from t where a = 4")
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	tk.MustQuery("select * from t where a > 0").Sort().Check(testkit.Rows("1 x 10", "2 y 20", "3 z 30"))
+
+	// The statements not supported by the non-prepared plan cache.
+	tk.MustQuery("select * from t where a = 1 union all select * from t where a = 2").Sort().Check(testkit.Rows("1 x 10", "2 y 20"))
+	tk.MustQuery("select * from t where a = 2 union all select * from t where a = 3").Sort().Check(testkit.Rows("2 y 20", "3 z 30"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk.MustQuery("select * from t where a = (select 1)").Check(testkit.Rows("1 x 10"))
+	tk.MustQuery("select * from t where a = (select 2)").Check(testkit.Rows("2 y 20"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+
+	// The cached plan is invalidated after the schema is changed.
+	tk.MustExec("alter table t add column d int")
+	tk.MustQuery("select * from t where a = 1").Check(testkit.Rows("1 x 10 <nil>"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk.MustQuery("select * from t where a = 2").Check(testkit.Rows("2 y 20 <nil>"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+
+	// The privileges are checked when the cached plan is used.
+	tk.MustExec("create user 'u_npc'@'localhost'")
+	tk.MustExec("grant select on test.t to 'u_npc'@'localhost'")
+	userSess := newSession(c, store, "test")
+	c.Assert(userSess.Auth(&auth.UserIdentity{Username: "u_npc", Hostname: "localhost"}, nil, nil), IsTrue)
+	mustExec(c, userSess, "set @@tidb_enable_non_prepared_plan_cache = 1")
+	rootSe := tk.Se
+	tk.Se = userSess
+	tk.MustQuery("select a from t where a = 1").Check(testkit.Rows("1"))
+	tk.MustQuery("select a from t where a = 2").Check(testkit.Rows("2"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	tk.Se = rootSe
+	tk.MustExec("revoke select on test.t from 'u_npc'@'localhost'")
+	tk.Se = userSess
+	_, err = tk.Exec("select a from t where a = 3")
+	c.Assert(core.ErrTableaccessDenied.Equal(err), IsTrue)
+}
+
+func (s *testPrepareSerialSuite) TestInstancePlanCache(c *C) {
+	defer testleak.AfterTest(c)()
+	store, dom, err := newStoreWithBootstrap()
+	c.Assert(err, IsNil)
+	tk := testkit.NewTestKit(c, store)
+	orgEnable := core.PreparedPlanCacheEnabled()
+	defer func() {
+		core.FlushInstancePlanCache()
+		dom.Close()
+		err = store.Close()
+		c.Assert(err, IsNil)
+		core.SetPreparedPlanCache(orgEnable)
+	}()
+	core.SetPreparedPlanCache(true)
+	core.FlushInstancePlanCache()
+	newCachedSession := func() session.Session {
+		se, err := session.CreateSession4TestWithOpt(store, &session.Opt{
+			PreparedPlanCache: kvcache.NewSimpleLRUCache(100, 0.1, math.MaxUint64),
+		})
+		c.Assert(err, IsNil)
+		return se
+	}
+	tk.Se = newCachedSession()
+	tk.MustExec("use test")
+	tk.MustExec("drop table if exists t")
+	tk.MustExec("create table t(a int primary key, b int, c int, key(b))")
+	tk.MustExec("insert into t values(1, 1, 1), (2, 2, 2), (3, 3, 3)")
+	tk.MustExec("set global tidb_enable_instance_plan_cache = 1")
+	defer tk.MustExec("set global tidb_enable_instance_plan_cache = default")
+
+	tk.MustExec("prepare stmt1 from 'select * from t where a = ?'")
+	tk.MustExec("prepare stmt2 from 'select a, c from t where b > ? order by a'")
+	tk.MustExec("set @a = 1")
+	tk.MustQuery("execute stmt1 using @a").Check(testkit.Rows("1 1 1"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk.MustQuery("execute stmt2 using @a").Check(testkit.Rows("2 2", "3 3"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+
+	// The plans cached by the first session are used by the other session.
+	tk2 := testkit.NewTestKit(c, store)
+	tk2.Se = newCachedSession()
+	tk2.MustExec("use test")
+	tk2.MustExec("prepare stmt1 from 'select * from t where a = ?'")
+	tk2.MustExec("prepare stmt2 from 'select a, c from t where b > ? order by a'")
+	tk2.MustExec("set @a = 2")
+	tk2.MustQuery("execute stmt1 using @a").Check(testkit.Rows("2 2 2"))
+	tk2.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	tk2.MustQuery("execute stmt2 using @a").Check(testkit.Rows("3 3"))
+	tk2.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	tk.MustExec("set @a = 3")
+	tk.MustQuery("execute stmt1 using @a").Check(testkit.Rows("3 3 3"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	// The point plan is reused by the prepared statement itself without looking up the cache.
+	tk.MustQuery("select sql_text, db, hits from information_schema.instance_plan_cache order by sql_text").Check(testkit.Rows(
+		"select * from t where a = ? test 1",
+		"select a, c from t where b > ? order by a test 1"))
+
+	// The uncommitted changes of the session are not visible to the shared plans.
+	tk2.MustExec("begin")
+	tk2.MustExec("insert into t values(4, 4, 4)")
+	tk2.MustQuery("execute stmt2 using @a").Check(testkit.Rows("3 3", "4 4"))
+	tk2.MustExec("rollback")
+
+	// The plans are not shared by the statements of different databases.
+	tk2.MustExec("create database if not exists test_ipc")
+	tk2.MustExec("use test_ipc")
+	tk2.MustExec("create table t(a int primary key, b int, c int, key(b))")
+	tk2.MustExec("prepare stmt1 from 'select * from t where a = ?'")
+	tk2.MustQuery("execute stmt1 using @a").Check(testkit.Rows())
+	tk2.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk2.MustExec("drop database test_ipc")
+
+	// The plans are shared by the statements which are the same after normalized, but not by the statements of
+	// different literals or optimizer variables.
+	tk2.MustExec("use test")
+	tk.MustExec("prepare stmt3 from 'select a, c from t where b > ? order by a'")
+	tk.MustQuery("execute stmt3 using @a").Check(testkit.Rows())
+	tk2.MustExec("prepare stmt3 from 'SELECT a,  c FROM t WHERE b > ? ORDER BY a /* comment */'")
+	tk2.MustQuery("execute stmt3 using @a").Check(testkit.Rows("3 3"))
+	tk2.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	tk2.MustExec("set tidb_opt_prefer_range_scan = 1")
+	tk2.MustExec("prepare stmt3 from 'select a, c from t where b > ? order by a'")
+	tk2.MustQuery("execute stmt3 using @a").Check(testkit.Rows("3 3"))
+	tk2.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk2.MustExec("set tidb_opt_prefer_range_scan = default")
+	tk2.MustExec("prepare stmt3 from 'select a from t where b > ? and c = 3'")
+	tk2.MustQuery("execute stmt3 using @a").Check(testkit.Rows("3"))
+	tk2.MustExec("prepare stmt3 from 'select a from t where b > ? and c = 4'")
+	tk2.MustQuery("execute stmt3 using @a").Check(testkit.Rows())
+	tk2.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+
+	// The plans are not shared by the sessions of different connection collations.
+	collate.SetNewCollationEnabledForTest(true)
+	defer collate.SetNewCollationEnabledForTest(false)
+	tk.MustExec("set @@collation_connection = 'utf8mb4_general_ci'")
+	tk.MustExec("prepare stmt4 from 'select a from t where a = ? and ''a'' = ''A'''")
+	tk.MustExec("set @a = 1")
+	tk.MustQuery("execute stmt4 using @a").Check(testkit.Rows("1"))
+	tk2.MustExec("set @@collation_connection = 'utf8mb4_bin'")
+	tk2.MustExec("prepare stmt4 from 'select a from t where a = ? and ''a'' = ''A'''")
+	tk2.MustExec("set @a = 1")
+	tk2.MustQuery("execute stmt4 using @a").Check(testkit.Rows())
+	tk2.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk2.MustExec("set @@collation_connection = 'utf8mb4_general_ci'")
+	tk2.MustExec("prepare stmt4 from 'select a from t where a = ? and ''a'' = ''A'''")
+	tk2.MustQuery("execute stmt4 using @a").Check(testkit.Rows("1"))
+	tk2.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	collate.SetNewCollationEnabledForTest(false)
+
+	tk.MustExec("admin flush instance plan_cache")
+	tk.MustQuery("select count(*) from information_schema.instance_plan_cache").Check(testkit.Rows("0"))
+	tk.MustExec("set @a = 1")
+	tk.MustQuery("execute stmt1 using @a").Check(testkit.Rows("1 1 1"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+
+	// The cached plans are evicted when the memory usage exceeds the limit.
+	tk.MustExec("set global tidb_instance_plan_cache_max_size = 1")
+	defer tk.MustExec("set global tidb_instance_plan_cache_max_size = default")
+	tk.MustQuery("execute stmt2 using @a").Check(testkit.Rows("2 2", "3 3"))
+	tk.MustQuery("select count(*) from information_schema.instance_plan_cache").Check(testkit.Rows("0"))
+}
+
+func (s *testPrepareSerialSuite) TestPlanCacheForPartitionSubqueryAndLimit(c *C) {
+	defer testleak.AfterTest(c)()
+	store, dom, err := newStoreWithBootstrap()
+	c.Assert(err, IsNil)
+	tk := testkit.NewTestKit(c, store)
+	orgEnable := core.PreparedPlanCacheEnabled()
+	defer func() {
+		dom.Close()
+		err = store.Close()
+		c.Assert(err, IsNil)
+		core.SetPreparedPlanCache(orgEnable)
+	}()
+	core.SetPreparedPlanCache(true)
+	tk.Se, err = session.CreateSession4TestWithOpt(store, &session.Opt{
+		PreparedPlanCache: kvcache.NewSimpleLRUCache(100, 0.1, math.MaxUint64),
+	})
+	c.Assert(err, IsNil)
+	tk.MustExec("use test")
+
+	// Partition tables in static mode are pruned again when the cached plan is executed.
+	tk.MustExec("set @@tidb_partition_prune_mode = 'static'")
+	tk.MustExec("drop table if exists t_range, t_hash")
+	tk.MustExec("create table t_range (a int, b int, key(b)) partition by range(a) (partition p0 values less than (10), partition p1 values less than (20), partition p2 values less than (30))")
+	tk.MustExec("insert into t_range values (1, 1), (11, 11), (21, 21)")
+	tk.MustExec("create table t_hash (a int primary key, b int) partition by hash(a) partitions 4")
+	tk.MustExec("insert into t_hash values (1, 1), (2, 2), (3, 3)")
+	tk.MustExec("prepare stmt1 from 'select b from t_range where a = ?'")
+	tk.MustExec("set @a = 1")
+	tk.MustQuery("execute stmt1 using @a").Check(testkit.Rows("1"))
+	tk.MustQuery("show warnings").Check(testkit.Rows())
+	tk.MustExec("set @a = 21")
+	tk.MustQuery("execute stmt1 using @a").Check(testkit.Rows("21"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	tk.MustExec("set @a = 30")
+	tk.MustQuery("execute stmt1 using @a").Check(testkit.Rows())
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	tk.MustExec("prepare stmt2 from 'select b from t_range where a >= ? and a < ? order by b'")
+	tk.MustExec("set @a = 0, @b = 10")
+	tk.MustQuery("execute stmt2 using @a, @b").Check(testkit.Rows("1"))
+	tk.MustExec("set @a = 5, @b = 25")
+	tk.MustQuery("execute stmt2 using @a, @b").Check(testkit.Rows("11", "21"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	tk.MustExec("prepare stmt3 from 'select b from t_hash where a = ?'")
+	tk.MustExec("set @a = 1")
+	tk.MustQuery("execute stmt3 using @a").Check(testkit.Rows("1"))
+	tk.MustExec("set @a = 2")
+	tk.MustQuery("execute stmt3 using @a").Check(testkit.Rows("2"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	tk.MustExec("set @a = 3")
+	tk.MustQuery("execute stmt3 using @a").Check(testkit.Rows("3"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+
+	// Uncorrelated subqueries are executed with the cached plan instead of being evaluated while building it.
+	tk.MustExec("drop table if exists t1, t2")
+	tk.MustExec("create table t1 (a int, b int)")
+	tk.MustExec("create table t2 (a int, b int)")
+	tk.MustExec("insert into t1 values (1, 1), (2, 2), (3, 3)")
+	tk.MustExec("insert into t2 values (1, 1)")
+	tk.MustExec("prepare stmt4 from 'select a from t1 where a > ? and exists (select 1 from t2 where b > ?) order by a'")
+	tk.MustExec("set @a = 1, @b = 0")
+	tk.MustQuery("execute stmt4 using @a, @b").Check(testkit.Rows("2", "3"))
+	tk.MustExec("set @b = 1")
+	tk.MustQuery("execute stmt4 using @a, @b").Check(testkit.Rows())
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	tk.MustExec("prepare stmt5 from 'select a from t1 where a > (select max(a) from t2 where b < ?) order by a'")
+	tk.MustExec("set @a = 2")
+	tk.MustQuery("execute stmt5 using @a").Check(testkit.Rows("2", "3"))
+	tk.MustExec("insert into t2 values (2, 1)")
+	tk.MustQuery("execute stmt5 using @a").Check(testkit.Rows("3"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+
+	// The values of the LIMIT parameters are a part of the cache key.
+	tk.MustExec("prepare stmt6 from 'select a from t1 order by a limit ?'")
+	tk.MustExec("set @a = 1")
+	tk.MustQuery("execute stmt6 using @a").Check(testkit.Rows("1"))
+	tk.MustQuery("execute stmt6 using @a").Check(testkit.Rows("1"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	tk.MustExec("set @a = 2")
+	tk.MustQuery("execute stmt6 using @a").Check(testkit.Rows("1", "2"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("0"))
+	tk.MustQuery("execute stmt6 using @a").Check(testkit.Rows("1", "2"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+	tk.MustExec("prepare stmt7 from 'select a from t1 order by a limit ?, ?'")
+	tk.MustExec("set @a = 1, @b = 1")
+	tk.MustQuery("execute stmt7 using @a, @b").Check(testkit.Rows("2"))
+	tk.MustExec("set @a = 2")
+	tk.MustQuery("execute stmt7 using @a, @b").Check(testkit.Rows("3"))
+
+	// Generated columns.
+	tk.MustExec("drop table if exists t_gen")
+	tk.MustExec("create table t_gen (a int, b int as (a + 1), key(b))")
+	tk.MustExec("insert into t_gen(a) values (1), (2)")
+	tk.MustExec("prepare stmt8 from 'select a from t_gen where a + 1 = ?'")
+	tk.MustExec("set @a = 2")
+	tk.MustQuery("execute stmt8 using @a").Check(testkit.Rows("1"))
+	tk.MustExec("set @a = 3")
+	tk.MustQuery("execute stmt8 using @a").Check(testkit.Rows("2"))
+	tk.MustQuery("select @@last_plan_from_cache").Check(testkit.Rows("1"))
+
+	// The reason is reported when the statement can not be cached.
+	tk.MustExec("prepare stmt9 from 'select a from t1 where a > @x'")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1105 skip plan-cache: query has variables"))
+	tk.MustExec("prepare stmt10 from 'select a from t1 order by ?'")
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1105 skip plan-cache: query has 'order by ?'"))
+	tk.MustExec("prepare stmt11 from 'select a from t1 where a > ? and 1 = 0'")
+	tk.MustQuery("execute stmt11 using @a").Check(testkit.Rows())
+	tk.MustQuery("show warnings").Check(testkit.Rows("Warning 1105 skip plan-cache: get a TableDual plan"))
+}
+
+func (s *testPrepareSuite) TestParameterizeAST(c *C) {
+	defer testleak.AfterTest(c)()
+	cases := []struct {
+		sql      string
+		paramSQL string
+		params   string
+	}{
+		{"select * from t where a = 1 and b = 'x'", "SELECT * FROM `t` WHERE `a`=? AND `b`=?\n", "[1 x]"},
+		{"select a, 1 from t where a > 2 order by 1 limit 3", "SELECT `a`,1 FROM `t` WHERE `a`>? ORDER BY 1 LIMIT 3\na\n1", "[2]"},
+		{"select * from t where a = 1.5 or b is null or c = true", "SELECT * FROM `t` WHERE `a`=1.5 OR `b` IS NULL OR `c`=TRUE\n", "[]"},
+		{"update t set a = 1 where b in (2, 3)", "UPDATE `t` SET `a`=? WHERE `b` IN (?,?)", "[1 2 3]"},
+		{"insert into t values (1, 'x')", "INSERT INTO `t` VALUES (?,?)", "[1 x]"},
+		{"delete from t where a = now()", "DELETE FROM `t` WHERE `a`=NOW()", "[]"},
+	}
+	p := parser.New()
+	for _, ca := range cases {
+		stmt, err := p.ParseOneStmt(ca.sql, "", "")
+		c.Assert(err, IsNil)
+		before := stmt.Text()
+		paramSQL, params, err := core.ParameterizeAST(stmt)
+		c.Assert(err, IsNil)
+		c.Assert(paramSQL, Equals, ca.paramSQL, Commentf("for %s", ca.sql))
+		vals := make([]string, 0, len(params))
+		for _, param := range params {
+			str, err := param.ToString()
+			c.Assert(err, IsNil)
+			vals = append(vals, str)
+		}
+		c.Assert(fmt.Sprintf("%v", vals), Equals, ca.params, Commentf("for %s", ca.sql))
+		// The statement is restored after it's parameterized.
+		again, _, err := core.ParameterizeAST(stmt)
+		c.Assert(err, IsNil)
+		c.Assert(again, Equals, paramSQL)
+		c.Assert(stmt.Text(), Equals, before)
+	}
+}
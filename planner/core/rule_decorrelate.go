@@ -129,6 +129,8 @@ func (s *decorrelateSolver) optimize(ctx context.Context, p LogicalPlan) (Logica
 			join := &apply.LogicalJoin
 			join.self = join
 			p = join
+		} else if apply.NoDecorrelate {
+			// The apply is kept as required by the NO_DECORRELATE hint.
 		} else if sel, ok := innerPlan.(*LogicalSelection); ok {
 			// If the inner plan is a selection, we add this condition to join predicates.
 			// Notice that no matter what kind of join is, it's always right.
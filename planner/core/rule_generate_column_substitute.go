@@ -75,12 +75,19 @@ func collectGenerateColumn(lp LogicalPlan, exprToColumn ExprColumnMap) {
 }
 
 func tryToSubstituteExpr(expr *expression.Expression, sctx sessionctx.Context, candidateExpr expression.Expression, tp types.EvalType, schema *expression.Schema, col *expression.Column) {
-	if (*expr).Equal(sctx, candidateExpr) && candidateExpr.GetType().EvalType() == tp &&
-		schema.ColumnIndex(col) != -1 {
+	if substitutable(sctx, *expr, candidateExpr, tp) && schema.ColumnIndex(col) != -1 {
 		*expr = col
 	}
 }
 
+// substitutable checks whether the expression can be substituted by the generated column. The parameters of the
+// cached plan are only equal to the constants of the generated column for the current values, so the expressions
+// containing the parameters are not substituted.
+func substitutable(sctx sessionctx.Context, expr, candidateExpr expression.Expression, tp types.EvalType) bool {
+	return expr.Equal(sctx, candidateExpr) && candidateExpr.GetType().EvalType() == tp &&
+		!expression.MaybeOverOptimized4PlanCache(sctx, []expression.Expression{expr})
+}
+
 func substituteExpression(cond expression.Expression, sctx *stmtctx.StatementContext, sessionCtx sessionctx.Context, exprToColumn ExprColumnMap, schema *expression.Schema) {
 	sf, ok := cond.(*expression.ScalarFunction)
 	if !ok {
@@ -159,8 +166,7 @@ func (gc *gcSubstituter) substitute(ctx context.Context, lp LogicalPlan, exprToC
 			for i := 0; i < len(aggFunc.Args); i++ {
 				tp = aggFunc.Args[i].GetType().EvalType()
 				for candidateExpr, column := range exprToColumn {
-					if aggFunc.Args[i].Equal(lp.SCtx(), candidateExpr) && candidateExpr.GetType().EvalType() == tp &&
-						x.Schema().ColumnIndex(column) != -1 {
+					if substitutable(lp.SCtx(), aggFunc.Args[i], candidateExpr, tp) && x.Schema().ColumnIndex(column) != -1 {
 						aggFunc.Args[i] = column
 					}
 				}
@@ -169,8 +175,7 @@ func (gc *gcSubstituter) substitute(ctx context.Context, lp LogicalPlan, exprToC
 		for i := 0; i < len(x.GroupByItems); i++ {
 			tp = x.GroupByItems[i].GetType().EvalType()
 			for candidateExpr, column := range exprToColumn {
-				if x.GroupByItems[i].Equal(lp.SCtx(), candidateExpr) && candidateExpr.GetType().EvalType() == tp &&
-					x.Schema().ColumnIndex(column) != -1 {
+				if substitutable(lp.SCtx(), x.GroupByItems[i], candidateExpr, tp) && x.Schema().ColumnIndex(column) != -1 {
 					x.GroupByItems[i] = column
 				}
 			}
@@ -16,35 +16,134 @@ package core
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/sessionctx"
 )
 
+// joinGroupResult is the result of extractJoinGroup.
+type joinGroupResult struct {
+	group      []LogicalPlan
+	eqEdges    []*expression.ScalarFunction
+	otherConds []expression.Expression
+	// outerJoinConds is aligned with group. A non-nil item means the node is the
+	// inner side of an outer join, and the item holds all the conditions of that
+	// outer join. Such a node can only be joined as the inner side of a left outer
+	// join, after all the outer columns used by the conditions are available.
+	outerJoinConds [][]expression.Expression
+	hasInnerJoin   bool
+	hasOuterJoin   bool
+	// leadingHints collects the LEADING hints matched by the joins in the group.
+	leadingHints [][]hintTableInfo
+}
+
 // extractJoinGroup extracts all the join nodes connected with continuous
 // InnerJoins to construct a join group. This join group is further used to
 // construct a new join order based on a reorder algorithm.
 //
 // For example: "InnerJoin(InnerJoin(a, b), LeftJoin(c, d))"
-// results in a join group {a, b, LeftJoin(c, d)}.
-func extractJoinGroup(p LogicalPlan) (group []LogicalPlan, eqEdges []*expression.ScalarFunction, otherConds []expression.Expression) {
+// results in a join group {a, b, c, d}, in which d can only be joined with
+// the join tree containing c as the inner side of a left outer join.
+//
+// Outer joins are only extracted together with inner joins, and only when
+// the other joins in the group don't refer to the inner sides of them.
+// Otherwise, the outer joins are kept as a whole in the join group, e.g.
+// "InnerJoin(InnerJoin(a, b), LeftJoin(c, d))" results in a join group
+// {a, b, LeftJoin(c, d)}.
+func extractJoinGroup(p LogicalPlan) *joinGroupResult {
+	result := extractJoinGroupImpl(p, true)
+	if result.hasOuterJoin && (!result.hasInnerJoin || !result.isOuterJoinReorderable()) {
+		result = extractJoinGroupImpl(p, false)
+	}
+	return result
+}
+
+func extractJoinGroupImpl(p LogicalPlan, withOuterJoin bool) *joinGroupResult {
 	join, isJoin := p.(*LogicalJoin)
-	if !isJoin || join.preferJoinType > uint(0) || join.JoinType != InnerJoin || join.StraightJoin {
-		return []LogicalPlan{p}, nil, nil
+	if !isJoin || join.preferJoinType > uint(0) || join.StraightJoin || !isReorderableJoinType(join, withOuterJoin) {
+		return &joinGroupResult{
+			group:          []LogicalPlan{p},
+			outerJoinConds: [][]expression.Expression{nil},
+		}
 	}
 
-	lhsGroup, lhsEqualConds, lhsOtherConds := extractJoinGroup(join.children[0])
-	rhsGroup, rhsEqualConds, rhsOtherConds := extractJoinGroup(join.children[1])
+	result := &joinGroupResult{}
+	if join.leadingJoinOrder != nil {
+		result.leadingHints = append(result.leadingHints, join.leadingJoinOrder)
+	}
+	if join.JoinType == InnerJoin {
+		result.eqEdges = append(result.eqEdges, join.EqualConditions...)
+		result.otherConds = append(result.otherConds, join.OtherConditions...)
+		result.merge(extractJoinGroupImpl(join.children[0], withOuterJoin))
+		result.merge(extractJoinGroupImpl(join.children[1], withOuterJoin))
+		result.hasInnerJoin = true
+		return result
+	}
+
+	outerChild, innerChild := join.children[0], join.children[1]
+	if join.JoinType == RightOuterJoin {
+		outerChild, innerChild = innerChild, outerChild
+	}
+	conds := make([]expression.Expression, 0, len(join.EqualConditions)+len(join.LeftConditions)+len(join.RightConditions)+len(join.OtherConditions))
+	conds = append(conds, expression.ScalarFuncs2Exprs(join.EqualConditions)...)
+	conds = append(conds, join.LeftConditions...)
+	conds = append(conds, join.RightConditions...)
+	conds = append(conds, join.OtherConditions...)
+	result.merge(extractJoinGroupImpl(outerChild, withOuterJoin))
+	result.group = append(result.group, innerChild)
+	result.outerJoinConds = append(result.outerJoinConds, conds)
+	result.hasOuterJoin = true
+	return result
+}
+
+// isReorderableJoinType checks whether the join can be extracted into a join group.
+// An outer join is only extracted when it has equal conditions, so that the inner
+// side won't be joined by a cartesian outer join.
+func isReorderableJoinType(join *LogicalJoin, withOuterJoin bool) bool {
+	switch join.JoinType {
+	case InnerJoin:
+		return true
+	case LeftOuterJoin, RightOuterJoin:
+		return withOuterJoin && len(join.EqualConditions) > 0 && join.DefaultValues == nil
+	}
+	return false
+}
+
+func (r *joinGroupResult) merge(other *joinGroupResult) {
+	r.group = append(r.group, other.group...)
+	r.eqEdges = append(r.eqEdges, other.eqEdges...)
+	r.otherConds = append(r.otherConds, other.otherConds...)
+	r.outerJoinConds = append(r.outerJoinConds, other.outerJoinConds...)
+	r.hasInnerJoin = r.hasInnerJoin || other.hasInnerJoin
+	r.hasOuterJoin = r.hasOuterJoin || other.hasOuterJoin
+	r.leadingHints = append(r.leadingHints, other.leadingHints...)
+}
 
-	group = append(group, lhsGroup...)
-	group = append(group, rhsGroup...)
-	eqEdges = append(eqEdges, join.EqualConditions...)
-	eqEdges = append(eqEdges, lhsEqualConds...)
-	eqEdges = append(eqEdges, rhsEqualConds...)
-	otherConds = append(otherConds, join.OtherConditions...)
-	otherConds = append(otherConds, lhsOtherConds...)
-	otherConds = append(otherConds, rhsOtherConds...)
-	return group, eqEdges, otherConds
+// isOuterJoinReorderable checks that the inner sides of the outer joins are only
+// referred by the conditions of the outer joins themselves. Otherwise, moving the
+// outer joins may change the result of the conditions.
+func (r *joinGroupResult) isOuterJoinReorderable() bool {
+	for i, conds := range r.outerJoinConds {
+		if conds == nil {
+			continue
+		}
+		innerSchema := r.group[i].Schema()
+		for _, edge := range r.eqEdges {
+			if innerSchema.Contains(edge.GetArgs()[0].(*expression.Column)) || innerSchema.Contains(edge.GetArgs()[1].(*expression.Column)) {
+				return false
+			}
+		}
+		for _, cond := range r.otherConds {
+			for _, col := range expression.ExtractColumns(cond) {
+				if innerSchema.Contains(col) {
+					return false
+				}
+			}
+		}
+	}
+	return true
 }
 
 type joinReOrderSolver struct {
@@ -62,7 +161,8 @@ func (s *joinReOrderSolver) optimize(ctx context.Context, p LogicalPlan) (Logica
 // optimizeRecursive recursively collects join groups and applies join reorder algorithm for each group.
 func (s *joinReOrderSolver) optimizeRecursive(ctx sessionctx.Context, p LogicalPlan) (LogicalPlan, error) {
 	var err error
-	curJoinGroup, eqEdges, otherConds := extractJoinGroup(p)
+	result := extractJoinGroup(p)
+	curJoinGroup, eqEdges, otherConds := result.group, result.eqEdges, result.otherConds
 	if len(curJoinGroup) > 1 {
 		for i := range curJoinGroup {
 			curJoinGroup[i], err = s.optimizeRecursive(ctx, curJoinGroup[i])
@@ -74,18 +174,48 @@ func (s *joinReOrderSolver) optimizeRecursive(ctx sessionctx.Context, p LogicalP
 			ctx:        ctx,
 			otherConds: otherConds,
 		}
+		for i, conds := range result.outerJoinConds {
+			if conds == nil {
+				continue
+			}
+			if baseGroupSolver.outerJoinConds == nil {
+				baseGroupSolver.outerJoinConds = make(map[LogicalPlan][]expression.Expression)
+			}
+			baseGroupSolver.outerJoinConds[curJoinGroup[i]] = conds
+		}
 		originalSchema := p.Schema()
-		if len(curJoinGroup) > ctx.GetSessionVars().TiDBOptJoinReorderThreshold {
+		var leadingJoin LogicalPlan
+		if hintTables := checkLeadingHints(ctx, result.leadingHints); hintTables != nil {
+			var ok bool
+			leadingJoin, curJoinGroup, eqEdges, ok = baseGroupSolver.generateLeadingJoin(curJoinGroup, eqEdges, hintTables, p.SelectBlockOffset())
+			if !ok {
+				errMsg := fmt.Sprintf("Optimizer Hint %s is inapplicable, check whether the tables are in the same join group", restore2JoinHint(HintLeading, hintTables))
+				ctx.GetSessionVars().StmtCtx.AppendWarning(ErrInternal.GenWithStack(errMsg))
+			}
+		}
+		switch {
+		case len(curJoinGroup) == 0:
+			// All the nodes are joined by the LEADING hint.
+			p = leadingJoin
+		// The DP solver doesn't support outer joins, so the greedy solver is always used for them.
+		case len(curJoinGroup) > ctx.GetSessionVars().TiDBOptJoinReorderThreshold || len(baseGroupSolver.outerJoinConds) > 0:
 			groupSolver := &joinReorderGreedySolver{
 				baseSingleGroupJoinOrderSolver: baseGroupSolver,
 				eqEdges:                        eqEdges,
+				leadingJoin:                    leadingJoin,
+			}
+			if leadingJoin != nil {
+				curJoinGroup = append([]LogicalPlan{leadingJoin}, curJoinGroup...)
 			}
 			p, err = groupSolver.solve(curJoinGroup)
-		} else {
+		default:
 			dpSolver := &joinReorderDPSolver{
 				baseSingleGroupJoinOrderSolver: baseGroupSolver,
 			}
 			dpSolver.newJoin = dpSolver.newJoinWithEdges
+			if leadingJoin != nil {
+				curJoinGroup = append([]LogicalPlan{leadingJoin}, curJoinGroup...)
+			}
 			p, err = dpSolver.solve(curJoinGroup, expression.ScalarFuncs2Exprs(eqEdges))
 		}
 		if err != nil {
@@ -124,11 +254,137 @@ func (s *joinReOrderSolver) optimizeRecursive(ctx sessionctx.Context, p LogicalP
 	return p, nil
 }
 
+// checkLeadingHints returns the tables of the LEADING hint used by the join group.
+// All the hints are ignored if the joins in the group specify different LEADING hints.
+func checkLeadingHints(ctx sessionctx.Context, leadingHints [][]hintTableInfo) []hintTableInfo {
+	if len(leadingHints) == 0 {
+		return nil
+	}
+	for _, hintTables := range leadingHints[1:] {
+		if !isSameLeadingHint(leadingHints[0], hintTables) {
+			ctx.GetSessionVars().StmtCtx.AppendWarning(ErrInternal.GenWithStack("We can only use one leading hint at most, when multiple leading hints are used, all leading hints will be invalid"))
+			return nil
+		}
+	}
+	return leadingHints[0]
+}
+
+func isSameLeadingHint(lhs, rhs []hintTableInfo) bool {
+	if len(lhs) != len(rhs) {
+		return false
+	}
+	for i := range lhs {
+		if lhs[i].dbName.L != rhs[i].dbName.L || lhs[i].tblName.L != rhs[i].tblName.L || lhs[i].selectOffset != rhs[i].selectOffset {
+			return false
+		}
+	}
+	return true
+}
+
 // nolint:structcheck
 type baseSingleGroupJoinOrderSolver struct {
 	ctx          sessionctx.Context
 	curJoinGroup []*jrNode
 	otherConds   []expression.Expression
+	// outerJoinConds maps the inner side of an outer join in the group to the
+	// conditions of that outer join.
+	outerJoinConds map[LogicalPlan][]expression.Expression
+}
+
+// generateLeadingJoin joins the nodes specified by the LEADING hint in the order
+// of the hint. It returns the leading join, together with the remaining nodes and
+// equal edges of the group. The group is left unchanged if the hint can't be
+// applied.
+func (s *baseSingleGroupJoinOrderSolver) generateLeadingJoin(group []LogicalPlan, eqEdges []*expression.ScalarFunction,
+	hintTables []hintTableInfo, blockOffset int) (LogicalPlan, []LogicalPlan, []*expression.ScalarFunction, bool) {
+	remainGroup := make([]LogicalPlan, len(group))
+	copy(remainGroup, group)
+	remainEdges := make([]*expression.ScalarFunction, len(eqEdges))
+	copy(remainEdges, eqEdges)
+	otherConds := make([]expression.Expression, len(s.otherConds))
+	copy(otherConds, s.otherConds)
+	var leadingJoin LogicalPlan
+	for _, hintTbl := range hintTables {
+		idx := findNodeIndexByHintTable(remainGroup, hintTbl, blockOffset)
+		if idx < 0 {
+			return nil, group, eqEdges, false
+		}
+		node := remainGroup[idx]
+		remainGroup = append(remainGroup[:idx], remainGroup[idx+1:]...)
+		_, isOuterJoinInner := s.outerJoinConds[node]
+		if leadingJoin == nil {
+			if isOuterJoinInner {
+				return nil, group, eqEdges, false
+			}
+			leadingJoin = node
+			continue
+		}
+		if isOuterJoinInner {
+			conds, ok := s.checkOuterJoinConnection(leadingJoin, node)
+			if !ok {
+				return nil, group, eqEdges, false
+			}
+			leadingJoin = s.newOuterJoin(leadingJoin, node, conds)
+			continue
+		}
+		var usedEdges []*expression.ScalarFunction
+		usedEdges, remainEdges = s.checkConnection(leadingJoin, node, remainEdges)
+		var usedOtherConds []expression.Expression
+		mergedSchema := expression.MergeSchema(leadingJoin.Schema(), node.Schema())
+		otherConds, usedOtherConds = expression.FilterOutInPlace(otherConds, func(expr expression.Expression) bool {
+			return expression.ExprFromSchema(expr, mergedSchema)
+		})
+		leadingJoin = s.newJoinWithEdges(leadingJoin, node, usedEdges, usedOtherConds)
+	}
+	s.otherConds = otherConds
+	return leadingJoin, remainGroup, remainEdges, true
+}
+
+func findNodeIndexByHintTable(group []LogicalPlan, hintTbl hintTableInfo, blockOffset int) int {
+	for i, node := range group {
+		alias := extractTableAlias(node, blockOffset)
+		if alias != nil && alias.dbName.L == hintTbl.dbName.L && alias.tblName.L == hintTbl.tblName.L && alias.selectOffset == hintTbl.selectOffset {
+			return i
+		}
+	}
+	return -1
+}
+
+// checkConnection picks the equal edges connecting the two nodes from eqEdges, the
+// picked edges are arranged so that the left argument comes from the left node.
+// The remaining edges are returned too.
+func (s *baseSingleGroupJoinOrderSolver) checkConnection(leftNode, rightNode LogicalPlan,
+	eqEdges []*expression.ScalarFunction) (usedEdges, remainEdges []*expression.ScalarFunction) {
+	for _, edge := range eqEdges {
+		lCol := edge.GetArgs()[0].(*expression.Column)
+		rCol := edge.GetArgs()[1].(*expression.Column)
+		if leftNode.Schema().Contains(lCol) && rightNode.Schema().Contains(rCol) {
+			usedEdges = append(usedEdges, edge)
+		} else if rightNode.Schema().Contains(lCol) && leftNode.Schema().Contains(rCol) {
+			newSf := expression.NewFunctionInternal(s.ctx, ast.EQ, edge.GetType(), rCol, lCol).(*expression.ScalarFunction)
+			usedEdges = append(usedEdges, newSf)
+		} else {
+			remainEdges = append(remainEdges, edge)
+		}
+	}
+	return usedEdges, remainEdges
+}
+
+// checkOuterJoinConnection checks whether the node can be joined with the join tree
+// as the inner side of its outer join, which requires that all the outer columns
+// used by the conditions of the outer join come from the join tree.
+func (s *baseSingleGroupJoinOrderSolver) checkOuterJoinConnection(joinTree, innerNode LogicalPlan) ([]expression.Expression, bool) {
+	conds, ok := s.outerJoinConds[innerNode]
+	if !ok {
+		return nil, false
+	}
+	mergedSchema := expression.MergeSchema(joinTree.Schema(), innerNode.Schema())
+	for _, cond := range conds {
+		if !expression.ExprFromSchema(cond, mergedSchema) {
+			return nil, false
+		}
+	}
+	return conds, true
 }
 
 // baseNodeCumCost calculate the cumulative cost of the node in the join group.
@@ -179,6 +435,15 @@ func (s *baseSingleGroupJoinOrderSolver) newCartesianJoin(lChild, rChild Logical
 	return join
 }
 
+// newOuterJoin joins the inner side of an outer join to the join tree by a left outer join.
+func (s *baseSingleGroupJoinOrderSolver) newOuterJoin(joinTree, innerNode LogicalPlan, conds []expression.Expression) LogicalPlan {
+	join := s.newCartesianJoin(joinTree, innerNode)
+	join.JoinType = LeftOuterJoin
+	resetNotNullFlag(join.schema, joinTree.Schema().Len(), join.schema.Len())
+	join.AttachOnConds(conds)
+	return join
+}
+
 func (s *baseSingleGroupJoinOrderSolver) newJoinWithEdges(lChild, rChild LogicalPlan, eqEdges []*expression.ScalarFunction, otherConds []expression.Expression) LogicalPlan {
 	newJoin := s.newCartesianJoin(lChild, rChild)
 	newJoin.EqualConditions = eqEdges
@@ -19,12 +19,14 @@ import (
 	"sort"
 
 	"github.com/pingcap/tidb/expression"
-	"github.com/pingcap/tidb/parser/ast"
 )
 
 type joinReorderGreedySolver struct {
 	*baseSingleGroupJoinOrderSolver
 	eqEdges []*expression.ScalarFunction
+	// leadingJoin is the join built for the LEADING hint, the join tree is always
+	// constructed from it first.
+	leadingJoin LogicalPlan
 }
 
 // solve reorders the join nodes in the group based on a greedy algorithm.
@@ -41,6 +43,10 @@ type joinReorderGreedySolver struct {
 //
 // For the nodes and join trees which don't have a join equal condition to
 // connect them, we make a bushy join tree to do the cartesian joins finally.
+//
+// The inner side of an outer join is never used to start a join tree, it's
+// joined with the join tree once the tree covers the outer columns used by the
+// outer join.
 func (s *joinReorderGreedySolver) solve(joinNodePlans []LogicalPlan) (LogicalPlan, error) {
 	for _, node := range joinNodePlans {
 		_, err := node.recursiveDeriveStats(nil)
@@ -55,22 +61,68 @@ func (s *joinReorderGreedySolver) solve(joinNodePlans []LogicalPlan) (LogicalPla
 	sort.SliceStable(s.curJoinGroup, func(i, j int) bool {
 		return s.curJoinGroup[i].cumCost < s.curJoinGroup[j].cumCost
 	})
+	if s.leadingJoin != nil {
+		for i, node := range s.curJoinGroup {
+			if node.p == s.leadingJoin {
+				copy(s.curJoinGroup[1:i+1], s.curJoinGroup[:i])
+				s.curJoinGroup[0] = node
+				break
+			}
+		}
+	}
 
 	var cartesianGroup []LogicalPlan
-	for len(s.curJoinGroup) > 0 {
-		newNode, err := s.constructConnectedJoinTree()
+	for {
+		startIdx := s.findStartNode()
+		if startIdx < 0 {
+			break
+		}
+		newNode, err := s.constructConnectedJoinTree(startIdx)
 		if err != nil {
 			return nil, err
 		}
 		cartesianGroup = append(cartesianGroup, newNode.p)
 	}
 
-	return s.makeBushyJoin(cartesianGroup), nil
+	return s.joinRemainedOuterJoinInners(s.makeBushyJoin(cartesianGroup))
 }
 
-func (s *joinReorderGreedySolver) constructConnectedJoinTree() (*jrNode, error) {
-	curJoinTree := s.curJoinGroup[0]
-	s.curJoinGroup = s.curJoinGroup[1:]
+// findStartNode returns the first node which can start a join tree, or -1 if
+// there is no such node.
+func (s *joinReorderGreedySolver) findStartNode() int {
+	for i, node := range s.curJoinGroup {
+		if _, isOuterJoinInner := s.outerJoinConds[node.p]; !isOuterJoinInner {
+			return i
+		}
+	}
+	return -1
+}
+
+// joinRemainedOuterJoinInners joins the remained inner sides of outer joins,
+// whose outer columns come from different connected join trees.
+func (s *joinReorderGreedySolver) joinRemainedOuterJoinInners(joinTree LogicalPlan) (LogicalPlan, error) {
+	for len(s.curJoinGroup) > 0 {
+		joined := false
+		for i, node := range s.curJoinGroup {
+			conds, ok := s.checkOuterJoinConnection(joinTree, node.p)
+			if !ok {
+				continue
+			}
+			joinTree = s.newOuterJoin(joinTree, node.p, conds)
+			s.curJoinGroup = append(s.curJoinGroup[:i], s.curJoinGroup[i+1:]...)
+			joined = true
+			break
+		}
+		if !joined {
+			return nil, ErrInternal.GenWithStack("the outer join can't be rebuilt by the join reorder")
+		}
+	}
+	return joinTree, nil
+}
+
+func (s *joinReorderGreedySolver) constructConnectedJoinTree(startIdx int) (*jrNode, error) {
+	curJoinTree := s.curJoinGroup[startIdx]
+	s.curJoinGroup = append(s.curJoinGroup[:startIdx], s.curJoinGroup[startIdx+1:]...)
 	for {
 		bestCost := math.MaxFloat64
 		bestIdx := -1
@@ -108,22 +160,19 @@ func (s *joinReorderGreedySolver) constructConnectedJoinTree() (*jrNode, error)
 }
 
 func (s *joinReorderGreedySolver) checkConnectionAndMakeJoin(leftNode, rightNode LogicalPlan) (LogicalPlan, []expression.Expression) {
-	var usedEdges []*expression.ScalarFunction
-	remainOtherConds := make([]expression.Expression, len(s.otherConds))
-	copy(remainOtherConds, s.otherConds)
-	for _, edge := range s.eqEdges {
-		lCol := edge.GetArgs()[0].(*expression.Column)
-		rCol := edge.GetArgs()[1].(*expression.Column)
-		if leftNode.Schema().Contains(lCol) && rightNode.Schema().Contains(rCol) {
-			usedEdges = append(usedEdges, edge)
-		} else if rightNode.Schema().Contains(lCol) && leftNode.Schema().Contains(rCol) {
-			newSf := expression.NewFunctionInternal(s.ctx, ast.EQ, edge.GetType(), rCol, lCol).(*expression.ScalarFunction)
-			usedEdges = append(usedEdges, newSf)
+	if _, isOuterJoinInner := s.outerJoinConds[rightNode]; isOuterJoinInner {
+		conds, ok := s.checkOuterJoinConnection(leftNode, rightNode)
+		if !ok {
+			return nil, nil
 		}
+		return s.newOuterJoin(leftNode, rightNode, conds), s.otherConds
 	}
+	usedEdges, _ := s.checkConnection(leftNode, rightNode, s.eqEdges)
 	if len(usedEdges) == 0 {
 		return nil, nil
 	}
+	remainOtherConds := make([]expression.Expression, len(s.otherConds))
+	copy(remainOtherConds, s.otherConds)
 	var otherConds []expression.Expression
 	mergedSchema := expression.MergeSchema(leftNode.Schema(), rightNode.Schema())
 	remainOtherConds, otherConds = expression.FilterOutInPlace(remainOtherConds, func(expr expression.Expression) bool {
@@ -627,6 +627,15 @@ func (s *partitionProcessor) prune(ds *DataSource) (LogicalPlan, error) {
 	for i, cond := range ds.allConds {
 		ds.allConds[i] = expression.PushDownNot(ds.ctx, cond)
 	}
+	// The parameters of the cached plan change in every execution, so the partitions are pruned when the plan is
+	// executed instead.
+	if expression.MaybeOverOptimized4PlanCache(ds.ctx, ds.allConds) {
+		p, err := s.makeUnionAllChildren(ds, pi, fullRange(len(pi.Definitions)))
+		if ua, ok := p.(*LogicalPartitionUnionAll); ok {
+			ua.deferredPruning = true
+		}
+		return p, err
+	}
 	// Try to locate partition directly for hash partition.
 	switch pi.Type {
 	case model.PartitionTypeRange:
@@ -23,13 +23,16 @@ import (
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/kv"
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/planner/property"
 	"github.com/pingcap/tidb/planner/util"
 	"github.com/pingcap/tidb/statistics"
+	"github.com/pingcap/tidb/tablecodec"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/ranger"
 	"go.uber.org/zap"
@@ -56,6 +59,26 @@ func (p *LogicalTableDual) DeriveStats(childStats []*property.StatsInfo, selfSch
 	return p.stats, nil
 }
 
+// jsonTableRowCount is the estimated row count of JSON_TABLE, the number of
+// the rows can't be known before the document is evaluated.
+const jsonTableRowCount = 10
+
+// DeriveStats implement LogicalPlan DeriveStats interface.
+func (p *LogicalJSONTable) DeriveStats(childStats []*property.StatsInfo, selfSchema *expression.Schema, childSchema []*expression.Schema, _ [][]*expression.Column) (*property.StatsInfo, error) {
+	if p.stats != nil {
+		return p.stats, nil
+	}
+	profile := &property.StatsInfo{
+		RowCount: jsonTableRowCount,
+		ColNDVs:  make(map[int64]float64, selfSchema.Len()),
+	}
+	for _, col := range selfSchema.Columns {
+		profile.ColNDVs[col.UniqueID] = jsonTableRowCount
+	}
+	p.stats = profile
+	return p.stats, nil
+}
+
 // DeriveStats implement LogicalPlan DeriveStats interface.
 func (p *LogicalMemTable) DeriveStats(childStats []*property.StatsInfo, selfSchema *expression.Schema, childSchema []*expression.Schema, _ [][]*expression.Column) (*property.StatsInfo, error) {
 	if p.stats != nil {
@@ -392,6 +415,7 @@ func (ds *DataSource) DeriveStats(childStats []*property.StatsInfo, selfSchema *
 	for i, expr := range ds.pushedDownConds {
 		ds.pushedDownConds[i] = expression.PushDownNot(ds.ctx, expr)
 	}
+	mvIndexPaths := ds.extractMVIndexPaths()
 	for _, path := range ds.possibleAccessPaths {
 		if path.IsTablePath() {
 			continue
@@ -441,9 +465,166 @@ func (ds *DataSource) DeriveStats(childStats []*property.StatsInfo, selfSchema *
 		ds.indexMergeHints = nil
 		ds.ctx.GetSessionVars().StmtCtx.AppendWarning(errors.Errorf("IndexMerge is inapplicable or disabled"))
 	}
+	// The multi-valued index can only be accessed by the IndexMerge, so it's
+	// considered even if the IndexMerge is not enabled by the session.
+	if len(mvIndexPaths) > 0 && !ds.ctx.GetSessionVars().StmtCtx.NoIndexMergeHint && isReadOnlyTxn && ds.tableInfo.TempTableType != model.TempTableLocal && !cond {
+		err := ds.generateIndexMerge4MVIndex(mvIndexPaths)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return ds.stats, nil
 }
 
+// extractMVIndexPaths removes the paths of the multi-valued indexes from the
+// possible access paths and returns them. The entries of a multi-valued index
+// don't correspond to the rows one by one, so they can't be used as normal index
+// paths.
+func (ds *DataSource) extractMVIndexPaths() []*util.AccessPath {
+	var mvIndexPaths []*util.AccessPath
+	regularPaths := ds.possibleAccessPaths[:0]
+	for _, path := range ds.possibleAccessPaths {
+		if path.Index != nil && path.Index.MVIndex {
+			mvIndexPaths = append(mvIndexPaths, path)
+			continue
+		}
+		regularPaths = append(regularPaths, path)
+	}
+	if len(regularPaths) == 0 {
+		// Only the multi-valued indexes are hinted, we still need the table path.
+		tablePath := &util.AccessPath{StoreType: kv.TiKV}
+		fillContentForTablePath(tablePath, ds.tableInfo)
+		regularPaths = append(regularPaths, tablePath)
+	}
+	ds.possibleAccessPaths = regularPaths
+	return mvIndexPaths
+}
+
+// generateIndexMerge4MVIndex generates the IndexMerge paths for the multi-valued
+// indexes. The conditions `val MEMBER OF (expr)`, `JSON_CONTAINS(expr, vals)` and
+// `JSON_OVERLAPS(expr, vals)` can be converted to point ranges on the index whose
+// array key part is `CAST(expr AS ... ARRAY)`. The conditions are still kept as
+// filters, since the index entries are the elements after the CAST.
+func (ds *DataSource) generateIndexMerge4MVIndex(mvIndexPaths []*util.AccessPath) error {
+	for _, mvPath := range mvIndexPaths {
+		if !ds.isInIndexMergeHints(mvPath.Index.Name.L) {
+			continue
+		}
+		arrayCol, jsonExpr := ds.mvIndexArrayKeyPart(mvPath.Index)
+		if arrayCol == nil {
+			continue
+		}
+		// The JSON functions can't be pushed down, so look for them in all the
+		// filters on this table.
+		for _, cond := range ds.allConds {
+			vals, ok := ds.mvIndexValuesFromCond(cond, jsonExpr, arrayCol.RetType)
+			if !ok || len(vals) == 0 {
+				continue
+			}
+			partialPaths := make([]*util.AccessPath, 0, len(vals))
+			totalCount := float64(0)
+			for _, val := range vals {
+				partialPath := &util.AccessPath{
+					Index:          mvPath.Index,
+					FullIdxCols:    mvPath.FullIdxCols,
+					FullIdxColLens: mvPath.FullIdxColLens,
+					Ranges:         []*ranger.Range{{LowVal: []types.Datum{val}, HighVal: []types.Datum{val}}},
+				}
+				count, err := ds.tableStats.HistColl.GetRowCountByIndexRanges(ds.ctx.GetSessionVars().StmtCtx, mvPath.Index.ID, partialPath.Ranges)
+				if err != nil {
+					return err
+				}
+				partialPath.CountAfterAccess = count
+				totalCount += count
+				partialPaths = append(partialPaths, partialPath)
+			}
+			indexMergePath := &util.AccessPath{PartialIndexPaths: partialPaths}
+			indexMergePath.TableFilters = append(indexMergePath.TableFilters, ds.pushedDownConds...)
+			indexMergePath.CountAfterAccess = math.Min(totalCount, ds.tableStats.RowCount)
+			ds.possibleAccessPaths = append(ds.possibleAccessPaths, indexMergePath)
+		}
+	}
+	return nil
+}
+
+// mvIndexArrayKeyPart returns the hidden column of the array key part of the
+// multi-valued index and the JSON expression which is CAST to the array. Only
+// the index whose first key part is the array is supported now.
+func (ds *DataSource) mvIndexArrayKeyPart(idx *model.IndexInfo) (*expression.Column, expression.Expression) {
+	colInfo := ds.tableInfo.Columns[idx.Columns[0].Offset]
+	if colInfo.FieldType.ArrayElem == nil {
+		return nil, nil
+	}
+	for _, col := range ds.TblCols {
+		if col.ID != colInfo.ID {
+			continue
+		}
+		sf, ok := col.VirtualExpr.(*expression.ScalarFunction)
+		if !ok || sf.FuncName.L != ast.Cast {
+			return nil, nil
+		}
+		return col, sf.GetArgs()[0]
+	}
+	return nil, nil
+}
+
+// mvIndexValuesFromCond returns the index values to look up for the condition
+// on the JSON expression, the values are converted like the CAST in the index.
+// For JSON_CONTAINS, only the first value is returned because looking up any
+// one of them is enough.
+func (ds *DataSource) mvIndexValuesFromCond(cond, jsonExpr expression.Expression, arrayTp *types.FieldType) ([]types.Datum, bool) {
+	sf, ok := cond.(*expression.ScalarFunction)
+	if !ok {
+		return nil, false
+	}
+	args := sf.GetArgs()
+	var valExpr expression.Expression
+	switch sf.FuncName.L {
+	case ast.JSONMemberOf:
+		if args[1].Equal(ds.ctx, jsonExpr) {
+			valExpr = args[0]
+		}
+	case ast.JSONContains:
+		if len(args) == 2 && args[0].Equal(ds.ctx, jsonExpr) {
+			valExpr = args[1]
+		}
+	case ast.JSONOverlaps:
+		if args[0].Equal(ds.ctx, jsonExpr) {
+			valExpr = args[1]
+		} else if args[1].Equal(ds.ctx, jsonExpr) {
+			valExpr = args[0]
+		}
+	}
+	if valExpr == nil || len(expression.ExtractColumns(valExpr)) > 0 ||
+		expression.ContainCorrelatedColumn([]expression.Expression{valExpr}) ||
+		expression.IsMutableEffectsExpr(valExpr) ||
+		expression.MaybeOverOptimized4PlanCache(ds.ctx, []expression.Expression{valExpr}) {
+		return nil, false
+	}
+	castExpr, err := expression.BuildCastFunctionWithCheck(ds.ctx, valExpr, arrayTp)
+	if err != nil {
+		return nil, false
+	}
+	// The values which can't be converted to the element type can't be found
+	// in the index either, fall back to other access paths for simplicity.
+	arr, isNull, err := castExpr.EvalJSON(ds.ctx, chunk.Row{})
+	if err != nil || isNull {
+		return nil, false
+	}
+	vals := make([]types.Datum, 0, arr.GetElemCount())
+	for i := 0; i < arr.GetElemCount(); i++ {
+		val, err := tablecodec.MVIndexElemDatum(arr.ArrayGetElem(i), arrayTp.ArrayElem)
+		if err != nil {
+			return nil, false
+		}
+		vals = append(vals, val)
+		if sf.FuncName.L == ast.JSONContains {
+			break
+		}
+	}
+	return vals, true
+}
+
 func (ds *DataSource) generateAndPruneIndexMergePath(needPrune bool) error {
 	regularPathCount := len(ds.possibleAccessPaths)
 	err := ds.generateIndexMergeOrPaths()
@@ -217,6 +217,15 @@ func Optimize(ctx context.Context, sctx sessionctx.Context, node ast.Node, is in
 	// No plan found from the bindings, or the bindings are ignored.
 	if bestPlan == nil {
 		sessVars.StmtCtx.StmtHints = originStmtHints
+		if stmtNode != nil && bindRecord == nil {
+			plan, planNames, found, err := getPlanFromNonPreparedPlanCache(ctx, sctx, stmtNode, is)
+			if err != nil {
+				return nil, nil, err
+			}
+			if found {
+				return plan, planNames, nil
+			}
+		}
 		bestPlan, names, _, err = optimize(ctx, sctx, node, is)
 		if err != nil {
 			return nil, nil, err
@@ -243,13 +252,20 @@ func Optimize(ctx context.Context, sctx sessionctx.Context, node ast.Node, is in
 				return bestPlan, names, nil
 			}
 			defPlanHints := plannercore.GenHintsFromPhysicalPlan(defPlan)
+			noIndexMergeGenerated := false
 			for _, hint := range defPlanHints {
 				if hint.HintName.String() == plannercore.HintReadFromStorage {
 					return bestPlan, names, nil
 				}
+				if hint.HintName.L == plannercore.HintNoIndexMerge {
+					noIndexMergeGenerated = true
+				}
 			}
 			// The hints generated from the plan do not contain the statement hints of the query, add them back.
 			for _, off := range originStmtHintsOffs {
+				if noIndexMergeGenerated && tableHints[off].HintName.L == plannercore.HintNoIndexMerge {
+					continue
+				}
 				defPlanHints = append(defPlanHints, tableHints[off])
 			}
 			defPlanHintsStr := hint.RestoreOptimizerHints(defPlanHints)
@@ -263,6 +279,51 @@ func Optimize(ctx context.Context, sctx sessionctx.Context, node ast.Node, is in
 	return bestPlan, names, nil
 }
 
+// getPlanFromNonPreparedPlanCache tries to get the plan of the statement sent by the text protocol from the
+// non-prepared plan cache. The literals of the statement are parameterized, and the parameterized statement is
+// optimized like an executed prepared statement, so the rules of the prepared plan cache are followed.
+func getPlanFromNonPreparedPlanCache(ctx context.Context, sctx sessionctx.Context, stmt ast.StmtNode, is infoschema.InfoSchema) (plannercore.Plan, types.NameSlice, bool, error) {
+	sessVars := sctx.GetSessionVars()
+	if !sessVars.EnableNonPreparedPlanCache || !plannercore.PreparedPlanCacheEnabled() || sessVars.InRestrictedSQL ||
+		sessVars.StmtCtx.InExplainStmt || sessVars.StmtCtx.IsStaleness || sessVars.SnapshotTS != 0 ||
+		sessVars.TxnCtx.IsStaleness || sessVars.TxnReadTS.PeakTxnReadTS() > 0 ||
+		!plannercore.NonPreparedPlanCacheable(sctx, stmt, is) {
+		return nil, nil, false, nil
+	}
+	paramSQL, params, err := plannercore.ParameterizeAST(stmt)
+	if err != nil || len(params) == 0 {
+		// The statements without parameters are optimized again by this function when the cached plan is rebuilt,
+		// while the ones with parameters are rejected by NonPreparedPlanCacheable then.
+		return nil, nil, false, err
+	}
+	stmtKey := sessVars.CurrentDB + "\n" + paramSQL
+	cachedStmt, _ := sessVars.GetNonPreparedPlanCacheStmt(stmtKey).(*plannercore.CachedPrepareStmt)
+	if cachedStmt == nil {
+		cachedStmt, err = plannercore.NewNonPreparedCachedStmt(ctx, sctx, stmt, paramSQL)
+		if err != nil || cachedStmt == nil {
+			// Optimize the statement without the plan cache, the error is reported again if there is one.
+			logutil.BgLogger().Debug("parameterize statement failed", zap.String("sql", paramSQL), zap.Error(err))
+			return nil, nil, false, nil
+		}
+		sessVars.AddNonPreparedPlanCacheStmt(stmtKey, cachedStmt)
+	}
+	// The cached plan skips optimize, which checks the restricted read-only mode.
+	if variable.RestrictedReadOnly.Load() {
+		allowed, err := allowInReadOnlyMode(sctx, stmt)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		if !allowed {
+			return nil, nil, false, errors.Trace(core.ErrSQLInReadOnlyMode)
+		}
+	}
+	execPlan := &plannercore.Execute{PrepareParams: params}
+	if err := execPlan.OptimizeNonPreparedPlan(ctx, sctx, is, cachedStmt); err != nil {
+		return nil, nil, false, err
+	}
+	return execPlan.Plan, execPlan.OutputNames(), true, nil
+}
+
 func allowInReadOnlyMode(sctx sessionctx.Context, node ast.Node) (bool, error) {
 	pm := privilege.GetPrivilegeManager(sctx)
 	if pm == nil {
@@ -391,6 +452,33 @@ func optimize(ctx context.Context, sctx sessionctx.Context, node ast.Node, is in
 	return finalPlan, names, cost, err
 }
 
+// OptimizeForForeignKeyCascade does optimization and creates a Plan for the DML statement generated by the
+// foreign key cascade. The privileges on the child tables aren't checked, which is the same as MySQL: the
+// referential actions are part of the statement modifying the parent table, and they are defined by the
+// foreign key that is created by a user having the privileges on the child table.
+func OptimizeForForeignKeyCascade(ctx context.Context, sctx sessionctx.Context, node ast.StmtNode, is infoschema.InfoSchema) (plannercore.Plan, error) {
+	hintProcessor := &hint.BlockHintProcessor{Ctx: sctx}
+	node.Accept(hintProcessor)
+
+	builder := planBuilderPool.Get().(*plannercore.PlanBuilder)
+	defer planBuilderPool.Put(builder.ResetForReuse())
+
+	builder.Init(sctx, is, hintProcessor)
+	p, err := builder.Build(ctx, node)
+	if err != nil {
+		return nil, err
+	}
+	if err := plannercore.CheckTableLock(sctx, is, builder.GetVisitInfo()); err != nil {
+		return nil, err
+	}
+	logic, isLogicalPlan := p.(plannercore.LogicalPlan)
+	if !isLogicalPlan {
+		return p, nil
+	}
+	finalPlan, _, err := plannercore.DoOptimize(ctx, sctx, builder.GetOptFlag(), logic)
+	return finalPlan, err
+}
+
 func extractSelectAndNormalizeDigest(stmtNode ast.StmtNode, specifiledDB string) (ast.StmtNode, string, string, error) {
 	switch x := stmtNode.(type) {
 	case *ast.ExplainStmt:
@@ -0,0 +1,67 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"crypto/tls"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/privilege/privileges"
+)
+
+func init() {
+	privileges.GetAuthPlugin = func(name string) privileges.AuthPlugin {
+		manifest := GetAuthenticationPlugin(name)
+		if manifest == nil {
+			return nil
+		}
+		return authPlugin{manifest}
+	}
+}
+
+// GetAuthenticationPlugin finds the ready and enabled authentication plugin by name,
+// returns nil if there is no such plugin.
+func GetAuthenticationPlugin(name string) *AuthenticationManifest {
+	var manifest *AuthenticationManifest
+	err := ForeachPlugin(Authentication, func(p *Plugin) error {
+		if p.Name == name {
+			manifest = DeclareAuthenticationManifest(p.Manifest)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil
+	}
+	return manifest
+}
+
+// authPlugin adapts the AuthenticationManifest to privileges.AuthPlugin.
+type authPlugin struct {
+	manifest *AuthenticationManifest
+}
+
+func (p authPlugin) AuthenticateUser(authUser, authHost, storedAuthString string, authResponse, salt []byte, connState *tls.ConnectionState) error {
+	if p.manifest.AuthenticateUser == nil {
+		return errors.Errorf("authentication plugin %s doesn't implement AuthenticateUser", p.manifest.Name)
+	}
+	return p.manifest.AuthenticateUser(authUser, authHost, storedAuthString, authResponse, salt, connState)
+}
+
+func (p authPlugin) ValidateAuthString(authString string) bool {
+	if p.manifest.ValidateAuthenticationString == nil {
+		return true
+	}
+	return p.manifest.ValidateAuthenticationString(authString)
+}
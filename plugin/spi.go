@@ -16,6 +16,7 @@ package plugin
 
 import (
 	"context"
+	"crypto/tls"
 	"reflect"
 	"unsafe"
 )
@@ -62,13 +63,31 @@ func ExportManifest(m interface{}) *Manifest {
 	return (*Manifest)(unsafe.Pointer(v.Pointer()))
 }
 
-// AuthenticationManifest presents a sub-manifest that every audit plugin must provide.
+// AuthenticationManifest presents a sub-manifest that every authentication plugin must provide.
+// The plugin is used to authenticate the users whose `plugin` column in mysql.user is the plugin name.
 type AuthenticationManifest struct {
 	Manifest
-	AuthenticateUser             func()
-	GenerateAuthenticationString func()
-	ValidateAuthenticationString func()
-	SetSalt                      func()
+	// RequiredClientSidePlugin is the client side authentication plugin asked for in the
+	// authentication switch request, the plugin name is used if it's empty.
+	RequiredClientSidePlugin string
+	// AuthenticateUser will be called when the user logins or changes user.
+	// authUser and authHost identify the account in mysql.user, storedAuthString is its
+	// authentication_string, authResponse is the data sent by the client and salt is the
+	// data sent to the client in the authentication switch request.
+	// return error will deny the login.
+	AuthenticateUser func(authUser, authHost, storedAuthString string, authResponse, salt []byte, connState *tls.ConnectionState) error
+	// GenerateAuthenticationString generates the authentication_string stored in mysql.user
+	// for `IDENTIFIED WITH plugin BY 'password'`.
+	// return false will reject the password. `IDENTIFIED WITH plugin BY` is not supported if it's nil.
+	GenerateAuthenticationString func(password string) (string, bool)
+	// ValidateAuthenticationString validates the authentication_string in mysql.user, which
+	// is either generated by GenerateAuthenticationString or set by `IDENTIFIED WITH plugin AS 'auth_string'`.
+	// All the authentication strings are valid if it's nil.
+	ValidateAuthenticationString func(authString string) bool
+	// SetSalt will be called before the authentication switch request is sent, salt is the random
+	// data generated by TiDB and the returned data will be sent to the client instead.
+	// The salt generated by TiDB is used if it's nil.
+	SetSalt func(salt []byte) []byte
 }
 
 // SchemaManifest presents a sub-manifest that every schema plugins must provide.
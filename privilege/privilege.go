@@ -15,11 +15,10 @@
 package privilege
 
 import (
-	"crypto/tls"
-
 	"github.com/pingcap/tidb/parser/auth"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/types"
 )
 
@@ -29,6 +28,38 @@ func (k keyType) String() string {
 	return "privilege-key"
 }
 
+// VerificationInfo records some information returned by Manager.ConnectionVerification.
+type VerificationInfo struct {
+	// AuthUser and AuthHost identify the matched account, they're set even if the verification fails.
+	AuthUser string
+	AuthHost string
+	// InSandBoxMode indicates the password of the account has expired, the session is only
+	// allowed to reset the password.
+	InSandBoxMode bool
+	// FailedDueToWrongPassword indicates the account exists but the authentication data is wrong.
+	FailedDueToWrongPassword bool
+	// FailedLoginTracking indicates FAILED_LOGIN_ATTEMPTS and PASSWORD_LOCK_TIME are both set for the account.
+	FailedLoginTracking bool
+	// FailedLoginCount is the number of consecutive failed logins recorded for the account.
+	FailedLoginCount int64
+}
+
+// UserResources is the resource limits of an account set by MAX_QUERIES_PER_HOUR, MAX_UPDATES_PER_HOUR,
+// MAX_CONNECTIONS_PER_HOUR, MAX_USER_CONNECTIONS and MAX_MEMORY_QUOTA, 0 means no limit.
+type UserResources struct {
+	MaxQuestions       int64
+	MaxUpdates         int64
+	MaxConnections     int64
+	MaxUserConnections int64
+	// MaxMemoryQuota limits the memory used by all the sessions of the account in a TiDB instance, in bytes.
+	MaxMemoryQuota int64
+}
+
+// IsLimited reports whether any resource of the account is limited.
+func (r UserResources) IsLimited() bool {
+	return r.MaxQuestions > 0 || r.MaxUpdates > 0 || r.MaxConnections > 0 || r.MaxUserConnections > 0 || r.MaxMemoryQuota > 0
+}
+
 // Manager is the interface for providing privilege related operations.
 type Manager interface {
 	// ShowGrants shows granted privileges for user.
@@ -59,7 +90,7 @@ type Manager interface {
 	RequestDynamicVerificationWithUser(privName string, grantable bool, user *auth.UserIdentity) bool
 
 	// ConnectionVerification verifies user privilege for connection.
-	ConnectionVerification(user, host string, auth, salt []byte, tlsState *tls.ConnectionState) (string, string, bool)
+	ConnectionVerification(user, host string, auth, salt []byte, sessionVars *variable.SessionVars) (VerificationInfo, error)
 
 	// GetAuthWithoutVerification uses to get auth name without verification.
 	GetAuthWithoutVerification(user, host string) (string, string, bool)
@@ -80,6 +111,9 @@ type Manager interface {
 	// GetDefaultRoles returns all default roles for certain user.
 	GetDefaultRoles(user, host string) []*auth.RoleIdentity
 
+	// GetUserResources returns the resource limits of the account identified by the user and host.
+	GetUserResources(user, host string) UserResources
+
 	// GetAllRoles return all roles of user.
 	GetAllRoles(user, host string) []*auth.RoleIdentity
 
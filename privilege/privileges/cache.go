@@ -30,6 +30,7 @@ import (
 	"github.com/pingcap/tidb/parser/auth"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/privilege"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util"
@@ -64,7 +65,8 @@ const (
 	References_priv,Alter_priv,Execute_priv,Index_priv,Create_view_priv,Show_view_priv,
 	Create_role_priv,Drop_role_priv,Create_tmp_table_priv,Lock_tables_priv,Create_routine_priv,
 	Alter_routine_priv,Event_priv,Shutdown_priv,Reload_priv,File_priv,Config_priv,Repl_client_priv,Repl_slave_priv,
-	account_locked,plugin FROM mysql.user`
+	account_locked,plugin,password_expired,password_last_changed,password_lifetime,user_attributes,
+	max_questions,max_updates,max_connections,max_user_connections,max_memory_quota FROM mysql.user`
 	sqlLoadGlobalGrantsTable = `SELECT HIGH_PRIORITY Host,User,Priv,With_Grant_Option FROM mysql.global_grants`
 )
 
@@ -98,6 +100,74 @@ type UserRecord struct {
 	Privileges           mysql.PrivilegeType
 	AccountLocked        bool // A role record when this field is true
 	AuthPlugin           string
+	PasswordExpired      bool
+	PasswordLastChanged  time.Time
+	PasswordLifeTime     int64 // -1 means the global default_password_lifetime is used
+	PasswordLocking      PasswordLocking
+	Resources            privilege.UserResources
+}
+
+// PasswordLocking is the failed-login tracking setting and state of an account, it's
+// stored in mysql.user.User_attributes as {"Password_locking": {...}}, so the state is
+// shared by all the TiDB instances and kept across restarts.
+type PasswordLocking struct {
+	FailedLoginAttempts int64 `json:"failed_login_attempts"`
+	// PasswordLockTimeDays is the number of days to lock the account, -1 means UNBOUNDED.
+	PasswordLockTimeDays int64 `json:"password_lock_time_days"`
+	FailedLoginCount     int64 `json:"failed_login_count,omitempty"`
+	// AutoLockedAt is the unix time the account is locked temporarily, 0 means not locked.
+	AutoLockedAt int64 `json:"auto_locked_at,omitempty"`
+}
+
+// userAttributes is the content of mysql.user.User_attributes.
+type userAttributes struct {
+	PasswordLocking *PasswordLocking `json:"Password_locking,omitempty"`
+}
+
+// ParsePasswordLocking parses the PasswordLocking from the content of mysql.user.User_attributes.
+func ParsePasswordLocking(attributes string) (PasswordLocking, error) {
+	var attrs userAttributes
+	if attributes != "" {
+		if err := json.Unmarshal(hack.Slice(attributes), &attrs); err != nil {
+			return PasswordLocking{}, errors.Trace(err)
+		}
+	}
+	if attrs.PasswordLocking == nil {
+		return PasswordLocking{}, nil
+	}
+	return *attrs.PasswordLocking, nil
+}
+
+// Enabled returns whether the failed-login tracking is enabled.
+func (l *PasswordLocking) Enabled() bool {
+	return l.FailedLoginAttempts > 0 && l.PasswordLockTimeDays != 0
+}
+
+// LockedDaysRemaining returns the remaining days the account is locked temporarily, it returns 0
+// if the account isn't locked or the lock has expired, and -1 if the account is locked until it's unlocked.
+func (l *PasswordLocking) LockedDaysRemaining(now time.Time) int64 {
+	if l.AutoLockedAt == 0 {
+		return 0
+	}
+	if l.PasswordLockTimeDays < 0 {
+		return -1
+	}
+	elapsed := now.Sub(time.Unix(l.AutoLockedAt, 0))
+	remaining := time.Duration(l.PasswordLockTimeDays)*24*time.Hour - elapsed
+	if remaining <= 0 {
+		return 0
+	}
+	return int64((remaining + 24*time.Hour - 1) / (24 * time.Hour))
+}
+
+// JSON returns the content of mysql.user.User_attributes for the PasswordLocking.
+func (l PasswordLocking) JSON() string {
+	if l == (PasswordLocking{}) {
+		return "{}"
+	}
+	data, err := json.Marshal(userAttributes{PasswordLocking: &l})
+	terror.Log(err)
+	return string(data)
 }
 
 // NewUserRecord return a UserRecord, only use for unit test.
@@ -640,6 +710,43 @@ func (p *MySQLPrivilege) decodeUserTableRow(row chunk.Row, fs []*ast.ResultField
 			} else {
 				value.AuthPlugin = mysql.AuthNativePassword
 			}
+		case f.ColumnAsName.L == "password_expired":
+			value.PasswordExpired = row.GetEnum(i).String() == "Y"
+		case f.ColumnAsName.L == "password_last_changed":
+			if !row.IsNull(i) {
+				lastChanged, err := row.GetTime(i).GoTime(time.Local)
+				if err != nil {
+					return errors.Trace(err)
+				}
+				value.PasswordLastChanged = lastChanged
+			}
+		case f.ColumnAsName.L == "password_lifetime":
+			if row.IsNull(i) {
+				value.PasswordLifeTime = -1
+			} else {
+				value.PasswordLifeTime = row.GetInt64(i)
+			}
+		case f.ColumnAsName.L == "user_attributes":
+			if row.IsNull(i) {
+				continue
+			}
+			passwordLocking, err := ParsePasswordLocking(row.GetJSON(i).String())
+			if err != nil {
+				logutil.BgLogger().Warn("failed to parse mysql.user.user_attributes",
+					zap.String("user", value.User), zap.String("host", value.Host), zap.Error(err))
+				continue
+			}
+			value.PasswordLocking = passwordLocking
+		case f.ColumnAsName.L == "max_questions":
+			value.Resources.MaxQuestions = int64(row.GetUint64(i))
+		case f.ColumnAsName.L == "max_updates":
+			value.Resources.MaxUpdates = int64(row.GetUint64(i))
+		case f.ColumnAsName.L == "max_connections":
+			value.Resources.MaxConnections = int64(row.GetUint64(i))
+		case f.ColumnAsName.L == "max_user_connections":
+			value.Resources.MaxUserConnections = int64(row.GetUint64(i))
+		case f.ColumnAsName.L == "max_memory_quota":
+			value.Resources.MaxMemoryQuota = int64(row.GetUint64(i))
 		case f.Column.Tp == mysql.TypeEnum:
 			if row.GetEnum(i).String() != "Y" {
 				continue
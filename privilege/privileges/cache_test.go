@@ -438,12 +438,16 @@ func TestAbnormalMySQLTable(t *testing.T) {
   max_updates int(11) unsigned NOT NULL DEFAULT '0',
   max_connections int(11) unsigned NOT NULL DEFAULT '0',
   max_user_connections int(11) unsigned NOT NULL DEFAULT '0',
+  max_memory_quota bigint(20) unsigned NOT NULL DEFAULT '0',
   plugin char(64) COLLATE utf8_bin DEFAULT 'mysql_native_password',
   authentication_string text COLLATE utf8_bin,
   password_expired enum('N','Y') CHARACTER SET utf8 NOT NULL DEFAULT 'N',
+  password_last_changed timestamp NULL DEFAULT NULL,
+  password_lifetime smallint(5) unsigned DEFAULT NULL,
+  User_attributes json DEFAULT NULL,
   PRIMARY KEY (Host,User)
 ) ENGINE=MyISAM DEFAULT CHARSET=utf8 COLLATE=utf8_bin COMMENT='Users and global privileges';`)
-	mustExec(t, se, `INSERT INTO user VALUES ('localhost','root','','Y','Y','Y','Y','Y','Y','Y','Y','Y','Y','Y','Y','Y','Y','Y','Y','Y','Y','Y','Y','Y','Y','Y','Y','Y','Y','Y','Y','Y','Y','Y','Y','Y','','','','',0,0,0,0,'mysql_native_password','','N');
+	mustExec(t, se, `INSERT INTO user VALUES ('localhost','root','','Y','Y','Y','Y','Y','Y','Y','Y','Y','Y','Y','Y','Y','Y','Y','Y','Y','Y','Y','Y','Y','Y','Y','Y','Y','Y','Y','Y','Y','Y','Y','Y','Y','','','','',0,0,0,0,0,'mysql_native_password','','N',NULL,NULL,NULL);
 `)
 	var p privileges.MySQLPrivilege
 	err = p.LoadUserTable(se)
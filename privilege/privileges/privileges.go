@@ -19,8 +19,10 @@ import (
 	"crypto/x509"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/pingcap/tidb/infoschema"
 	"github.com/pingcap/tidb/infoschema/perfschema"
@@ -28,6 +30,7 @@ import (
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/privilege"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/logutil"
@@ -38,6 +41,28 @@ import (
 // SkipWithGrant causes the server to start without using the privilege system at all.
 var SkipWithGrant = false
 
+// AuthPlugin is the authentication method provided by an authentication plugin.
+type AuthPlugin interface {
+	// AuthenticateUser returns error if the authentication of the account fails.
+	AuthenticateUser(authUser, authHost, storedAuthString string, authResponse, salt []byte, connState *tls.ConnectionState) error
+	// ValidateAuthString checks whether the authentication_string of the account is valid.
+	ValidateAuthString(authString string) bool
+}
+
+// GetAuthPlugin returns the loaded authentication plugin with the name, or nil if there
+// is no such plugin.
+// Note: it's initialized in the plugin package to avoid the import cycle.
+var GetAuthPlugin = func(name string) AuthPlugin { return nil }
+
+// isBuiltinAuthPlugin checks whether the authentication method is implemented by TiDB itself.
+func isBuiltinAuthPlugin(name string) bool {
+	switch name {
+	case mysql.AuthNativePassword, mysql.AuthCachingSha2Password, mysql.AuthSocket:
+		return true
+	}
+	return false
+}
+
 var _ privilege.Manager = (*UserPrivileges)(nil)
 var dynamicPrivs = []string{
 	"BACKUP_ADMIN",
@@ -54,6 +79,7 @@ var dynamicPrivs = []string{
 	"RESTRICTED_USER_ADMIN",           // User can not have their access revoked by SUPER users.
 	"RESTRICTED_CONNECTION_ADMIN",     // Can not be killed by PROCESS/CONNECTION_ADMIN privilege
 	"RESTRICTED_REPLICA_WRITER_ADMIN", // Can write to the sever even when tidb_restriced_read_only is turned on.
+	"SERVICE_CONNECTION_ADMIN",        // Can connect to the listeners reserved for the administrators.
 }
 var dynamicPrivLock sync.Mutex
 
@@ -191,6 +217,11 @@ func (p *UserPrivileges) RequestVerificationWithUser(db, table, column string, p
 
 func (p *UserPrivileges) isValidHash(record *UserRecord) bool {
 	pwd := record.AuthenticationString
+	// The account can't log in until its authentication plugin is loaded and enabled, even if the password is empty.
+	if !isBuiltinAuthPlugin(record.AuthPlugin) && GetAuthPlugin(record.AuthPlugin) == nil {
+		logutil.BgLogger().Error("the authentication plugin of the user is not loaded", zap.String("user", record.User), zap.String("plugin", record.AuthPlugin))
+		return false
+	}
 	if pwd == "" {
 		return true
 	}
@@ -214,6 +245,14 @@ func (p *UserPrivileges) isValidHash(record *UserRecord) bool {
 		return true
 	}
 
+	if !isBuiltinAuthPlugin(record.AuthPlugin) {
+		if GetAuthPlugin(record.AuthPlugin).ValidateAuthString(pwd) {
+			return true
+		}
+		logutil.BgLogger().Error("user password from system DB is rejected by the authentication plugin", zap.String("user", record.User), zap.String("plugin", record.AuthPlugin), zap.Int("hash_length", len(pwd)))
+		return false
+	}
+
 	logutil.BgLogger().Error("user password from system DB not like a known hash format", zap.String("user", record.User), zap.String("plugin", record.AuthPlugin), zap.Int("hash_length", len(pwd)))
 	return false
 }
@@ -233,6 +272,19 @@ func (p *UserPrivileges) GetEncodedPassword(user, host string) string {
 	return ""
 }
 
+// GetUserResources implements the Manager interface.
+func (p *UserPrivileges) GetUserResources(user, host string) privilege.UserResources {
+	if SkipWithGrant {
+		return privilege.UserResources{}
+	}
+	mysqlPriv := p.Handle.Get()
+	record := mysqlPriv.connectionVerification(user, host)
+	if record == nil {
+		return privilege.UserResources{}
+	}
+	return record.Resources
+}
+
 // GetAuthPlugin gets the authentication plugin for the account identified by the user and host
 func (p *UserPrivileges) GetAuthPlugin(user, host string) (string, error) {
 	if SkipWithGrant {
@@ -247,9 +299,13 @@ func (p *UserPrivileges) GetAuthPlugin(user, host string) (string, error) {
 	// zero-length auth string means no password for native and caching_sha2 auth.
 	// but for auth_socket it means there should be a 1-to-1 mapping between the TiDB user
 	// and the OS user.
-	if record.AuthenticationString == "" && record.AuthPlugin != mysql.AuthSocket {
+	if record.AuthenticationString == "" && record.AuthPlugin != mysql.AuthSocket && isBuiltinAuthPlugin(record.AuthPlugin) {
 		return "", nil
 	}
+	// Leave it to the caller to reject the login if the authentication plugin isn't loaded.
+	if !isBuiltinAuthPlugin(record.AuthPlugin) && GetAuthPlugin(record.AuthPlugin) == nil {
+		return record.AuthPlugin, nil
+	}
 	if p.isValidHash(record) {
 		return record.AuthPlugin, nil
 	}
@@ -282,11 +338,16 @@ func (p *UserPrivileges) GetAuthWithoutVerification(user, host string) (u string
 }
 
 // ConnectionVerification implements the Manager interface.
-func (p *UserPrivileges) ConnectionVerification(user, host string, authentication, salt []byte, tlsState *tls.ConnectionState) (u string, h string, success bool) {
+func (p *UserPrivileges) ConnectionVerification(user, host string, authentication, salt []byte, sessionVars *variable.SessionVars) (info privilege.VerificationInfo, err error) {
+	hasPassword := "YES"
+	if len(authentication) == 0 {
+		hasPassword = "NO"
+	}
 	if SkipWithGrant {
 		p.user = user
 		p.host = host
-		success = true
+		info.AuthUser = user
+		info.AuthHost = host
 		return
 	}
 
@@ -295,19 +356,20 @@ func (p *UserPrivileges) ConnectionVerification(user, host string, authenticatio
 	if record == nil {
 		logutil.BgLogger().Error("get user privilege record fail",
 			zap.String("user", user), zap.String("host", host))
-		return
+		return info, ErrAccessDenied.FastGenByArgs(user, host, hasPassword)
 	}
 
-	u = record.User
-	h = record.Host
+	info.AuthUser = record.User
+	info.AuthHost = record.Host
+	info.FailedLoginTracking = record.PasswordLocking.Enabled()
+	info.FailedLoginCount = record.PasswordLocking.FailedLoginCount
 
 	globalPriv := mysqlPriv.matchGlobalPriv(user, host)
 	if globalPriv != nil {
-		if !p.checkSSL(globalPriv, tlsState) {
+		if !p.checkSSL(globalPriv, sessionVars.TLSConnectionState) {
 			logutil.BgLogger().Error("global priv check ssl fail",
 				zap.String("user", user), zap.String("host", host))
-			success = false
-			return
+			return info, ErrAccessDenied.FastGenByArgs(user, host, hasPassword)
 		}
 	}
 
@@ -316,26 +378,57 @@ func (p *UserPrivileges) ConnectionVerification(user, host string, authenticatio
 	if locked {
 		logutil.BgLogger().Error("try to login a locked account",
 			zap.String("user", user), zap.String("host", host))
-		success = false
-		return
+		return info, ErrAccessDenied.FastGenByArgs(user, host, hasPassword)
 	}
 
+	// The account is locked temporarily due to too many consecutive failed logins.
+	if now := time.Now(); record.PasswordLocking.LockedDaysRemaining(now) != 0 {
+		logutil.BgLogger().Error("try to login an account locked due to consecutive failed logins",
+			zap.String("user", user), zap.String("host", host))
+		return info, PasswordLockedError(user, host, &record.PasswordLocking, now)
+	}
+
+	if !p.verifyAuthentication(record, user, authentication, salt, sessionVars) {
+		info.FailedDueToWrongPassword = true
+		return info, ErrAccessDenied.FastGenByArgs(user, host, hasPassword)
+	}
+
+	info.InSandBoxMode = p.isPasswordExpired(record, sessionVars)
+	p.user = user
+	p.host = record.Host
+	return info, nil
+}
+
+// verifyAuthentication checks the authentication data sent by the client against the account.
+func (p *UserPrivileges) verifyAuthentication(record *UserRecord, user string, authentication, salt []byte, sessionVars *variable.SessionVars) bool {
 	pwd := record.AuthenticationString
 	if !p.isValidHash(record) {
-		return
+		return false
+	}
+
+	// The authentication plugin decides on its own, even if the password is empty.
+	if !isBuiltinAuthPlugin(record.AuthPlugin) {
+		authPlugin := GetAuthPlugin(record.AuthPlugin)
+		if authPlugin == nil {
+			return false
+		}
+		err := authPlugin.AuthenticateUser(record.User, record.Host, pwd, authentication, salt, sessionVars.TLSConnectionState)
+		if err != nil {
+			logutil.BgLogger().Error("authentication plugin check fail", zap.String("user", user),
+				zap.String("host", record.Host), zap.String("plugin", record.AuthPlugin), zap.Error(err))
+			return false
+		}
+		return true
 	}
 
 	// empty password
 	if len(pwd) == 0 && len(authentication) == 0 {
-		p.user = user
-		p.host = h
-		success = true
-		return
+		return true
 	}
 
 	if len(pwd) == 0 || len(authentication) == 0 {
 		if record.AuthPlugin != mysql.AuthSocket {
-			return
+			return false
 		}
 	}
 
@@ -343,11 +436,11 @@ func (p *UserPrivileges) ConnectionVerification(user, host string, authenticatio
 		hpwd, err := auth.DecodePassword(pwd)
 		if err != nil {
 			logutil.BgLogger().Error("decode password string failed", zap.Error(err))
-			return
+			return false
 		}
 
 		if !auth.CheckScrambledPassword(salt, hpwd, authentication) {
-			return
+			return false
 		}
 	} else if record.AuthPlugin == mysql.AuthCachingSha2Password {
 		authok, err := auth.CheckShaPassword([]byte(pwd), string(authentication))
@@ -356,24 +449,54 @@ func (p *UserPrivileges) ConnectionVerification(user, host string, authenticatio
 		}
 
 		if !authok {
-			return
+			return false
 		}
 	} else if record.AuthPlugin == mysql.AuthSocket {
 		if string(authentication) != user && string(authentication) != pwd {
 			logutil.BgLogger().Error("Failed socket auth", zap.String("user", user),
 				zap.String("socket_user", string(authentication)),
 				zap.String("authentication_string", pwd))
-			return
+			return false
 		}
 	} else {
 		logutil.BgLogger().Error("unknown authentication plugin", zap.String("user", user), zap.String("plugin", record.AuthPlugin))
-		return
+		return false
 	}
+	return true
+}
 
-	p.user = user
-	p.host = h
-	success = true
-	return
+// isPasswordExpired checks whether the password of the account has expired, either manually by
+// PASSWORD EXPIRE or automatically by the password lifetime.
+func (p *UserPrivileges) isPasswordExpired(record *UserRecord, sessionVars *variable.SessionVars) bool {
+	if record.PasswordExpired {
+		return true
+	}
+	lifetime := record.PasswordLifeTime
+	if lifetime < 0 {
+		val, err := sessionVars.GlobalVarsAccessor.GetGlobalSysVar(variable.DefaultPasswordLifetime)
+		if err != nil {
+			logutil.BgLogger().Warn("get default_password_lifetime failed", zap.Error(err))
+			return false
+		}
+		lifetime, err = strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return false
+		}
+	}
+	if lifetime <= 0 || record.PasswordLastChanged.IsZero() {
+		return false
+	}
+	return time.Since(record.PasswordLastChanged) > time.Duration(lifetime)*24*time.Hour
+}
+
+// PasswordLockedError returns the error for the account locked due to consecutive failed logins.
+func PasswordLockedError(user, host string, locking *PasswordLocking, now time.Time) error {
+	lockTime, remainingDays := "unlimited", "unlimited"
+	if locking.PasswordLockTimeDays > 0 {
+		lockTime = strconv.FormatInt(locking.PasswordLockTimeDays, 10)
+		remainingDays = strconv.FormatInt(locking.LockedDaysRemaining(now), 10)
+	}
+	return ErrUserPasswordLocked.FastGenByArgs(user, host, lockTime, remainingDays, locking.FailedLoginAttempts)
 }
 
 type checkResult int
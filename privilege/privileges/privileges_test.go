@@ -26,6 +26,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/errno"
 	"github.com/pingcap/tidb/executor"
@@ -34,6 +35,7 @@ import (
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/plugin"
 	"github.com/pingcap/tidb/privilege"
 	"github.com/pingcap/tidb/privilege/privileges"
 	"github.com/pingcap/tidb/session"
@@ -2970,3 +2972,166 @@ func TestSkipGrantTable(t *testing.T) {
 	tk.MustExec(`GRANT RESTRICTED_TABLES_ADMIN ON *.* TO 'test2'@'%';`)
 	tk.MustExec(`GRANT RESTRICTED_USER_ADMIN ON *.* TO 'test2'@'%';`)
 }
+
+func TestAuthenticationPlugin(t *testing.T) {
+	plugin.SetTestHook(func(_ *plugin.Plugin, _ string, _ plugin.ID) (func() *plugin.Manifest, error) {
+		return func() *plugin.Manifest {
+			m := &plugin.AuthenticationManifest{
+				Manifest: plugin.Manifest{
+					Kind:    plugin.Authentication,
+					Name:    "token_auth",
+					Version: 1,
+					OnInit: func(ctx context.Context, manifest *plugin.Manifest) error {
+						return nil
+					},
+				},
+				AuthenticateUser: func(authUser, authHost, storedAuthString string, authResponse, salt []byte, connState *tls.ConnectionState) error {
+					if string(authResponse) != storedAuthString+string(salt) {
+						return errors.New("invalid token")
+					}
+					return nil
+				},
+				GenerateAuthenticationString: func(password string) (string, bool) {
+					return "token:" + password, password != ""
+				},
+				ValidateAuthenticationString: func(authString string) bool {
+					return strings.HasPrefix(authString, "token:")
+				},
+			}
+			return plugin.ExportManifest(m)
+		}, nil
+	})
+	cfg := plugin.Config{Plugins: []string{"token_auth-1"}}
+	require.NoError(t, plugin.Load(context.Background(), cfg))
+	require.NoError(t, plugin.Init(context.Background(), cfg))
+	defer plugin.Shutdown(context.Background())
+
+	store, clean := newStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("CREATE USER 'u1'@'localhost' IDENTIFIED WITH 'token_auth' BY 'abc'")
+	tk.MustExec("CREATE USER 'u2'@'localhost' IDENTIFIED WITH 'token_auth' AS 'token:def'")
+	tk.MustGetErrCode("CREATE USER 'u3'@'localhost' IDENTIFIED WITH 'token_auth' AS 'def'", errno.ErrPasswordFormat)
+	tk.MustGetErrCode("CREATE USER 'u3'@'localhost' IDENTIFIED WITH 'token_auth' BY ''", errno.ErrPasswordFormat)
+	tk.MustGetErrCode("CREATE USER 'u3'@'localhost' IDENTIFIED WITH 'unknown_auth' BY 'abc'", errno.ErrPluginIsNotLoaded)
+	tk.MustQuery("SELECT user, plugin, authentication_string FROM mysql.user WHERE user LIKE 'u%' ORDER BY user").Check(testkit.Rows(
+		"u1 token_auth token:abc", "u2 token_auth token:def"))
+
+	se := newSession(t, store, dbName)
+	salt := []byte("salt")
+	require.True(t, se.Auth(&auth.UserIdentity{Username: "u1", Hostname: "localhost"}, []byte("token:abcsalt"), salt))
+	require.False(t, se.Auth(&auth.UserIdentity{Username: "u1", Hostname: "localhost"}, []byte("token:abc"), salt))
+	require.False(t, se.Auth(&auth.UserIdentity{Username: "u1", Hostname: "localhost"}, nil, nil))
+	require.True(t, se.Auth(&auth.UserIdentity{Username: "u2", Hostname: "localhost"}, []byte("token:defsalt"), salt))
+
+	pm := privilege.GetPrivilegeManager(se)
+	authPlugin, err := pm.GetAuthPlugin("u1", "localhost")
+	require.NoError(t, err)
+	require.Equal(t, "token_auth", authPlugin)
+
+	// The password is encoded by the plugin when it's changed.
+	tk.MustExec("ALTER USER 'u1'@'localhost' IDENTIFIED BY 'xyz'")
+	tk.MustExec("SET PASSWORD FOR 'u2'@'localhost' = 'uvw'")
+	tk.MustQuery("SELECT user, plugin, authentication_string FROM mysql.user WHERE user LIKE 'u%' ORDER BY user").Check(testkit.Rows(
+		"u1 token_auth token:xyz", "u2 token_auth token:uvw"))
+	require.True(t, se.Auth(&auth.UserIdentity{Username: "u1", Hostname: "localhost"}, []byte("token:xyzsalt"), salt))
+	require.False(t, se.Auth(&auth.UserIdentity{Username: "u1", Hostname: "localhost"}, []byte("token:abcsalt"), salt))
+
+	// The accounts of an unloaded plugin can't log in, even if the password is empty.
+	tk.MustExec("CREATE USER 'u3'@'localhost' IDENTIFIED WITH 'token_auth'")
+	tk.MustQuery("SELECT authentication_string FROM mysql.user WHERE user = 'u3'").Check(testkit.Rows(""))
+	plugin.Shutdown(context.Background())
+	require.False(t, se.Auth(&auth.UserIdentity{Username: "u1", Hostname: "localhost"}, []byte("token:xyzsalt"), salt))
+	require.False(t, se.Auth(&auth.UserIdentity{Username: "u3", Hostname: "localhost"}, nil, nil))
+	require.False(t, se.Auth(&auth.UserIdentity{Username: "u3", Hostname: "localhost"}, nil, salt))
+	authPlugin, err = pm.GetAuthPlugin("u3", "localhost")
+	require.NoError(t, err)
+	require.Equal(t, "token_auth", authPlugin)
+	require.Equal(t, "", pm.GetEncodedPassword("u3", "localhost"))
+}
+
+func TestPasswordExpiration(t *testing.T) {
+	t.Parallel()
+	store, clean := newStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("CREATE USER 'expired'@'localhost' PASSWORD EXPIRE")
+	tk.MustExec("CREATE USER 'lifetime'@'localhost' PASSWORD EXPIRE INTERVAL 3 DAY")
+	tk.MustExec("CREATE USER 'default'@'localhost'")
+	tk.MustExec("UPDATE mysql.user SET Password_last_changed = DATE_SUB(NOW(), INTERVAL 5 DAY) WHERE User IN ('lifetime', 'default')")
+	tk.MustExec("FLUSH PRIVILEGES")
+
+	se := newSession(t, store, dbName)
+	require.NoError(t, se.AuthWithError(&auth.UserIdentity{Username: "expired", Hostname: "localhost"}, nil, nil))
+	require.True(t, se.GetSessionVars().InSandBoxMode)
+	_, err := se.ExecuteInternal(context.Background(), "SELECT 1")
+	require.NoError(t, err)
+	_, err = se.Execute(context.Background(), "SELECT 1")
+	require.True(t, terror.ErrorEqual(err, session.ErrMustChangePassword))
+	mustExec(t, se, "SET PASSWORD = 'abc'")
+	require.False(t, se.GetSessionVars().InSandBoxMode)
+	mustExec(t, se, "SELECT 1")
+	tk.MustQuery("SELECT Password_expired FROM mysql.user WHERE User = 'expired'").Check(testkit.Rows("N"))
+
+	se = newSession(t, store, dbName)
+	require.NoError(t, se.AuthWithError(&auth.UserIdentity{Username: "lifetime", Hostname: "localhost"}, nil, nil))
+	require.True(t, se.GetSessionVars().InSandBoxMode)
+
+	// The global default_password_lifetime takes effect if the account doesn't specify the lifetime.
+	se = newSession(t, store, dbName)
+	require.NoError(t, se.AuthWithError(&auth.UserIdentity{Username: "default", Hostname: "localhost"}, nil, nil))
+	require.False(t, se.GetSessionVars().InSandBoxMode)
+	tk.MustExec("SET GLOBAL default_password_lifetime = 4")
+	require.NoError(t, se.AuthWithError(&auth.UserIdentity{Username: "default", Hostname: "localhost"}, nil, nil))
+	require.True(t, se.GetSessionVars().InSandBoxMode)
+	tk.MustExec("ALTER USER 'default'@'localhost' PASSWORD EXPIRE NEVER")
+	require.NoError(t, se.AuthWithError(&auth.UserIdentity{Username: "default", Hostname: "localhost"}, nil, nil))
+	require.False(t, se.GetSessionVars().InSandBoxMode)
+}
+
+func TestFailedLoginTracking(t *testing.T) {
+	t.Parallel()
+	store, clean := newStore(t)
+	defer clean()
+
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("CREATE USER 'tracked'@'localhost' FAILED_LOGIN_ATTEMPTS 2 PASSWORD_LOCK_TIME 3")
+	user := &auth.UserIdentity{Username: "tracked", Hostname: "localhost"}
+
+	se := newSession(t, store, dbName)
+	err := se.AuthWithError(user, []byte("wrong"), nil)
+	require.True(t, terror.ErrorEqual(err, privileges.ErrAccessDenied))
+	// The failed logins are stored in mysql.user, so they're shared by the TiDB instances and kept across restarts.
+	tk.MustQuery("SELECT User_attributes FROM mysql.user WHERE User = 'tracked'").Check(testkit.Rows(
+		`{"Password_locking": {"failed_login_attempts": 2, "failed_login_count": 1, "password_lock_time_days": 3}}`))
+	handle := privileges.NewHandle()
+	require.NoError(t, handle.Update(tk.Session()))
+	require.Equal(t, int64(1), handle.Get().UserMap["tracked"][0].PasswordLocking.FailedLoginCount)
+	// A successful login resets the count.
+	require.NoError(t, se.AuthWithError(user, nil, nil))
+	tk.MustQuery("SELECT User_attributes FROM mysql.user WHERE User = 'tracked'").Check(testkit.Rows(
+		`{"Password_locking": {"failed_login_attempts": 2, "password_lock_time_days": 3}}`))
+	err = se.AuthWithError(user, []byte("wrong"), nil)
+	require.True(t, terror.ErrorEqual(err, privileges.ErrAccessDenied))
+	err = se.AuthWithError(user, []byte("wrong"), nil)
+	require.True(t, terror.ErrorEqual(err, privileges.ErrUserPasswordLocked))
+	require.EqualError(t, err, "[privilege:3955]Access denied for user 'tracked'@'localhost'. Account is blocked for 3 day(s) (3 day(s) remaining) due to 2 consecutive failed logins.")
+	// The right password can't login either.
+	err = se.AuthWithError(user, nil, nil)
+	require.True(t, terror.ErrorEqual(err, privileges.ErrUserPasswordLocked))
+
+	// ACCOUNT UNLOCK resets the temporary lock.
+	tk.MustExec("ALTER USER 'tracked'@'localhost' ACCOUNT UNLOCK")
+	require.NoError(t, se.AuthWithError(user, nil, nil))
+
+	tk.MustExec("ALTER USER 'tracked'@'localhost' FAILED_LOGIN_ATTEMPTS 1 PASSWORD_LOCK_TIME UNBOUNDED")
+	err = se.AuthWithError(user, []byte("wrong"), nil)
+	require.EqualError(t, err, "[privilege:3955]Access denied for user 'tracked'@'localhost'. Account is blocked for unlimited day(s) (unlimited day(s) remaining) due to 1 consecutive failed logins.")
+
+	// ACCOUNT LOCK doesn't count the failed logins.
+	tk.MustExec("ALTER USER 'tracked'@'localhost' ACCOUNT LOCK")
+	err = se.AuthWithError(user, nil, nil)
+	require.True(t, terror.ErrorEqual(err, privileges.ErrAccessDenied))
+}
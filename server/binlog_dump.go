@@ -0,0 +1,1137 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"hash/crc32"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/meta"
+	"github.com/pingcap/tidb/parser/charset"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/sessionctx/binloginfo"
+	"github.com/pingcap/tidb/tablecodec"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/codec"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tipb/go-binlog"
+	"github.com/tikv/client-go/v2/oracle"
+	"go.uber.org/zap"
+)
+
+// The binlog served by COM_BINLOG_DUMP is read from the pumps of the cluster, so it contains the transactions
+// and the DDL jobs committed through all the TiDB instances, and it's kept as long as the pumps keep it.
+// It is made up of virtual files, each of which contains one transaction or DDL job. The file tidb-binlog.<ts>
+// contains the first one committed after ts, and it is rotated to tidb-binlog.<commit ts> once it is sent.
+// So a client can resume from the position it has read on any instance, and the position of a GTID is the
+// commit TS.
+const (
+	binlogFilePrefix       = "tidb-binlog"
+	binlogFileStartPos     = 4
+	binlogVersion          = 4
+	binlogEventHeaderLen   = 19
+	binlogChecksumLen      = 4
+	binlogRowsEventMaxSize = 8192
+
+	defaultBinlogHeartbeatPeriod = 30 * time.Second
+)
+
+// binlogDDLJobRetryInterval is the interval to get the DDL job again, the job is moved to the history queue
+// after its binlog is committed.
+var binlogDDLJobRetryInterval = 100 * time.Millisecond
+
+// Flags of COM_BINLOG_DUMP and COM_BINLOG_DUMP_GTID.
+const (
+	binlogDumpNonBlock    uint16 = 0x01
+	binlogDumpThroughGTID uint16 = 0x04
+)
+
+// Binlog event types, see https://dev.mysql.com/doc/internals/en/binlog-event-type.html.
+const (
+	binlogQueryEvent             byte = 2
+	binlogRotateEvent            byte = 4
+	binlogFormatDescriptionEvent byte = 15
+	binlogXIDEvent               byte = 16
+	binlogTableMapEvent          byte = 19
+	binlogHeartbeatEvent         byte = 27
+	binlogWriteRowsEventV2       byte = 30
+	binlogUpdateRowsEventV2      byte = 31
+	binlogDeleteRowsEventV2      byte = 32
+	binlogGTIDEvent              byte = 33
+	binlogEventTypeCount              = 35
+)
+
+const (
+	binlogEventArtificialFlag uint16 = 0x20
+	binlogRowsStmtEndFlag     uint16 = 0x01
+
+	binlogChecksumOff   byte = 0
+	binlogChecksumCRC32 byte = 1
+
+	// optional metadata of the table map event.
+	binlogTableMapSignedness byte = 1
+	binlogTableMapColumnName byte = 4
+
+	// the temporal types with fractional seconds, which are only used in the binlog.
+	binlogTypeTimestamp2 byte = 17
+	binlogTypeDatetime2  byte = 18
+	binlogTypeTime2      byte = 19
+)
+
+// binlogPostHeaderLen is the post header length of the event types from 1 to binlogEventTypeCount.
+var binlogPostHeaderLen = [binlogEventTypeCount]byte{
+	56, 13, 0, 8, 0, 18, 0, 4, 4, 4, 4, 18, 0, 0, 57 + binlogEventTypeCount, 0, 4, 26, 8, 0,
+	0, 0, 8, 8, 8, 2, 0, 0, 0, 10, 10, 10, 42, 42, 0,
+}
+
+func binlogFileName(ts uint64) string {
+	return binlogFilePrefix + "." + strconv.FormatUint(ts, 10)
+}
+
+// parseBinlogPosition returns the commit TS after which the transactions are sent, and whether the first
+// of them has been read. The file tidb-binlog is accepted with the TSO returned by SHOW MASTER STATUS, and
+// the dump starts from currentTS if the file is empty.
+func parseBinlogPosition(file string, pos uint64, currentTS uint64) (ts uint64, skipFirst bool, err error) {
+	switch {
+	case file == "":
+		return currentTS, false, nil
+	case file == binlogFilePrefix:
+		return pos, false, nil
+	case strings.HasPrefix(file, binlogFilePrefix+"."):
+		ts, err = strconv.ParseUint(file[len(binlogFilePrefix)+1:], 10, 64)
+		if err == nil {
+			return ts, pos > binlogFileStartPos, nil
+		}
+	}
+	return 0, false, errMasterFatalReadingBinlog.GenWithStackByArgs(errno.ErrMasterFatalErrorReadingBinlog,
+		"could not find first log file name in binary log index file")
+}
+
+// binlogServerUUID is the source ID of the GTIDs, it's derived from the cluster so that
+// the GTIDs are the same on all the instances.
+func binlogServerUUID(storeUUID string) uuid.UUID {
+	return uuid.NewSHA1(uuid.NameSpaceOID, []byte(storeUUID))
+}
+
+// parseGTIDSet returns the largest GTID of the source in the encoded GTID set, see
+// https://dev.mysql.com/doc/internals/en/com-binlog-dump-gtid.html.
+func parseGTIDSet(data []byte, sid uuid.UUID) (gno uint64, ok bool, err error) {
+	if len(data) < 8 {
+		return 0, false, mysql.ErrMalformPacket
+	}
+	nSIDs := binary.LittleEndian.Uint64(data)
+	data = data[8:]
+	for i := uint64(0); i < nSIDs; i++ {
+		if len(data) < 24 {
+			return 0, false, mysql.ErrMalformPacket
+		}
+		match := bytes.Equal(data[:16], sid[:])
+		nIntervals := binary.LittleEndian.Uint64(data[16:])
+		data = data[24:]
+		if uint64(len(data)) < nIntervals*16 {
+			return 0, false, mysql.ErrMalformPacket
+		}
+		for j := uint64(0); j < nIntervals; j++ {
+			// the end of the interval is exclusive.
+			end := binary.LittleEndian.Uint64(data[8:])
+			data = data[16:]
+			if match && end > 0 && end-1 > gno {
+				gno, ok = end-1, true
+			}
+		}
+	}
+	return gno, ok, nil
+}
+
+// checkReplicationPrivilege checks whether the user has the REPLICATION SLAVE privilege.
+func (cc *clientConn) checkReplicationPrivilege() error {
+	checker := privilege.GetPrivilegeManager(cc.ctx.Session)
+	if checker != nil && !checker.RequestVerification(cc.ctx.GetSessionVars().ActiveRoles, "", "", "", mysql.ReplicationSlavePriv) {
+		return errSpecificAccessDenied.GenWithStackByArgs("REPLICATION SLAVE")
+	}
+	return nil
+}
+
+// handleRegisterSlave handles COM_REGISTER_SLAVE, the replica is not recorded since
+// SHOW SLAVE HOSTS is not supported.
+func (cc *clientConn) handleRegisterSlave(ctx context.Context) error {
+	if err := cc.checkReplicationPrivilege(); err != nil {
+		return err
+	}
+	return cc.writeOK(ctx)
+}
+
+// handleBinlogDump handles COM_BINLOG_DUMP and COM_BINLOG_DUMP_GTID, it sends the row-based binlog
+// events of the transactions and the query events of the DDL jobs of the cluster until the connection is closed.
+func (cc *clientConn) handleBinlogDump(ctx context.Context, data []byte, withGTID bool) error {
+	if err := cc.checkReplicationPrivilege(); err != nil {
+		return err
+	}
+	client := binloginfo.GetPumpsClient()
+	if client == nil {
+		return errMasterFatalReadingBinlog.GenWithStackByArgs(errno.ErrMasterFatalErrorReadingBinlog,
+			"binlog dump reads the binlog from the pumps, set binlog.enable to enable it")
+	}
+	var (
+		flags uint16
+		file  string
+		pos   uint64
+		gtids []byte
+	)
+	if !withGTID {
+		// binlog-pos (4), flags (2), server-id (4), binlog-filename
+		if len(data) < 10 {
+			return mysql.ErrMalformPacket
+		}
+		pos = uint64(binary.LittleEndian.Uint32(data))
+		flags = binary.LittleEndian.Uint16(data[4:])
+		file = string(data[10:])
+	} else {
+		// flags (2), server-id (4), binlog-filename-len (4), binlog-filename, binlog-pos (8), [data-size (4), data]
+		if len(data) < 10 {
+			return mysql.ErrMalformPacket
+		}
+		flags = binary.LittleEndian.Uint16(data)
+		nameLen := int(binary.LittleEndian.Uint32(data[6:]))
+		data = data[10:]
+		if len(data) < nameLen+8 {
+			return mysql.ErrMalformPacket
+		}
+		file = string(data[:nameLen])
+		pos = binary.LittleEndian.Uint64(data[nameLen:])
+		data = data[nameLen+8:]
+		if flags&binlogDumpThroughGTID != 0 {
+			if len(data) < 4 || len(data) < 4+int(binary.LittleEndian.Uint32(data)) {
+				return mysql.ErrMalformPacket
+			}
+			gtids = data[4 : 4+binary.LittleEndian.Uint32(data)]
+		}
+	}
+	file = strings.TrimRight(file, "\x00")
+
+	d := newBinlogDumper(cc)
+	ver, err := cc.ctx.GetStore().CurrentVersion(kv.GlobalTxnScope)
+	if err != nil {
+		return err
+	}
+	ts, skipFirst, err := parseBinlogPosition(file, pos, ver.Ver)
+	if err != nil {
+		return err
+	}
+	if len(gtids) > 0 {
+		gno, ok, err := parseGTIDSet(gtids, d.serverUUID)
+		if err != nil {
+			return err
+		}
+		if ok {
+			ts, skipFirst = gno, false
+		}
+	}
+	reader := binloginfo.NewChangeReader(client, ts)
+	defer reader.Close()
+	logutil.Logger(ctx).Info("start binlog dump", zap.String("file", file), zap.Uint64("pos", pos),
+		zap.Uint64("startTS", ts), zap.Bool("checksum", d.checksum))
+	var endTS uint64
+	if flags&binlogDumpNonBlock != 0 {
+		endTS = ver.Ver
+	}
+	return d.run(ctx, reader, ts, skipFirst, endTS)
+}
+
+// binlogDumper writes the binlog events to the replication client.
+type binlogDumper struct {
+	cc         *clientConn
+	dom        *domain.Domain
+	store      kv.Storage
+	serverID   uint32
+	serverUUID uuid.UUID
+	checksum   bool
+	heartbeat  time.Duration
+	file       string
+	pos        uint32
+}
+
+func newBinlogDumper(cc *clientConn) *binlogDumper {
+	d := &binlogDumper{
+		cc:         cc,
+		dom:        domain.GetDomain(cc.ctx.Session),
+		store:      cc.ctx.GetStore(),
+		serverID:   uint32(cc.server.ServerID()),
+		serverUUID: binlogServerUUID(cc.ctx.GetStore().UUID()),
+		heartbeat:  defaultBinlogHeartbeatPeriod,
+	}
+	if d.serverID == 0 {
+		d.serverID = 1
+	}
+	// the variables are set by the client before the dump, like MySQL does.
+	if v, ok := cc.getUserVar("master_binlog_checksum"); ok && strings.EqualFold(v, "CRC32") {
+		d.checksum = true
+	}
+	if v, ok := cc.getUserVar("master_heartbeat_period"); ok {
+		if period, err := strconv.ParseUint(v, 10, 64); err == nil && period > 0 {
+			d.heartbeat = time.Duration(period)
+		}
+	}
+	return d
+}
+
+func (cc *clientConn) getUserVar(name string) (string, bool) {
+	vars := cc.ctx.GetSessionVars()
+	vars.UsersLock.RLock()
+	defer vars.UsersLock.RUnlock()
+	d, ok := vars.Users[name]
+	if !ok || d.IsNull() {
+		return "", false
+	}
+	v, err := d.ToString()
+	return v, err == nil
+}
+
+// run sends the events committed after ts. If endTS isn't 0, it stops once the events committed before
+// endTS are sent, like the dump with the non-block flag stops at the end of the binlog.
+func (d *binlogDumper) run(ctx context.Context, reader *binloginfo.ChangeReader, ts uint64, skipFirst bool, endTS uint64) error {
+	d.file = binlogFileName(ts)
+	// the fake rotate event tells the client which file the events belong to.
+	if err := d.writeRotate(true); err != nil {
+		return err
+	}
+	d.pos = binlogFileStartPos
+	if err := d.writeFormatDescription(skipFirst); err != nil {
+		return err
+	}
+	if err := d.cc.flush(ctx); err != nil {
+		return err
+	}
+	lastSent := time.Now()
+	for {
+		ev := reader.TryNext()
+		if ev == nil || (endTS != 0 && ev.CommitTS > endTS) {
+			if endTS != 0 && (ev != nil || reader.ResolvedTS() >= endTS) {
+				if err := d.cc.writeEOF(0); err != nil {
+					return err
+				}
+				return d.cc.flush(ctx)
+			}
+			if time.Since(lastSent) >= d.heartbeat {
+				if err := d.writeEvent(binlogHeartbeatEvent, 0, binlogEventArtificialFlag, []byte(d.file)); err != nil {
+					return err
+				}
+				if err := d.cc.flush(ctx); err != nil {
+					return err
+				}
+				lastSent = time.Now()
+			}
+			waitCtx, cancel := context.WithDeadline(ctx, lastSent.Add(d.heartbeat))
+			err := reader.Wait(waitCtx)
+			cancel()
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if err != nil && err != context.DeadlineExceeded {
+				return err
+			}
+			continue
+		}
+		var job *model.Job
+		if ev.DDLJobID != 0 {
+			var err error
+			job, err = d.getDDLJob(ctx, ev)
+			if err != nil {
+				return err
+			}
+			if job == nil {
+				continue
+			}
+		}
+		if skipFirst {
+			// the transaction has been read by the client, so only rotate to the next file.
+			skipFirst = false
+			if err := d.rotate(ev.CommitTS, true); err != nil {
+				return err
+			}
+		} else if job != nil {
+			if err := d.writeDDL(ev, job); err != nil {
+				return err
+			}
+		} else if err := d.writeTransaction(ev); err != nil {
+			return err
+		}
+		if err := d.cc.flush(ctx); err != nil {
+			return err
+		}
+		lastSent = time.Now()
+	}
+}
+
+// getDDLJob gets the DDL job of the binlog, it's nil if the binlog shouldn't be sent. The binlog of a
+// DDL job is committed before the job is moved to the history queue, so it waits for the job.
+func (d *binlogDumper) getDDLJob(ctx context.Context, ev *binloginfo.ChangeEvent) (*model.Job, error) {
+	for {
+		var job *model.Job
+		err := kv.RunInNewTxn(ctx, d.store, false, func(ctx context.Context, txn kv.Transaction) error {
+			var err error
+			job, err = meta.NewMeta(txn).GetHistoryDDLJob(ev.DDLJobID)
+			return err
+		})
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if job != nil {
+			return binlogDDLJob(job, model.SchemaState(ev.DDLSchemaState)), nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(binlogDDLJobRetryInterval):
+		}
+	}
+}
+
+// binlogDDLJob returns the job if its binlog should be sent. Like drainer, the rolled back jobs are skipped,
+// and dropping columns is sent in the delete only state, since the rows written after it don't have the columns.
+func binlogDDLJob(job *model.Job, state model.SchemaState) *model.Job {
+	if job.State == model.JobStateRollbackDone || job.State == model.JobStateCancelled {
+		return nil
+	}
+	if job.Type == model.ActionDropColumn || job.Type == model.ActionDropColumns {
+		if state != model.StateDeleteOnly {
+			return nil
+		}
+	}
+	return job
+}
+
+// writeEvent writes an event with the header, see https://dev.mysql.com/doc/internals/en/binlog-event-header.html.
+func (d *binlogDumper) writeEvent(tp byte, timestamp uint32, flags uint16, body []byte) error {
+	size := binlogEventHeaderLen + len(body)
+	if d.checksum || tp == binlogFormatDescriptionEvent {
+		size += binlogChecksumLen
+	}
+	logPos := d.pos
+	if tp != binlogHeartbeatEvent {
+		if flags&binlogEventArtificialFlag != 0 {
+			logPos = 0
+		} else {
+			d.pos += uint32(size)
+			logPos = d.pos
+		}
+	}
+	data := make([]byte, 5, 5+size)
+	data[4] = mysql.OKHeader
+	data = dumpUint32(data, timestamp)
+	data = append(data, tp)
+	data = dumpUint32(data, d.serverID)
+	data = dumpUint32(data, uint32(size))
+	data = dumpUint32(data, logPos)
+	data = dumpUint16(data, flags)
+	data = append(data, body...)
+	if d.checksum || tp == binlogFormatDescriptionEvent {
+		data = dumpUint32(data, crc32.ChecksumIEEE(data[5:]))
+	}
+	return d.cc.writePacket(data)
+}
+
+func (d *binlogDumper) writeRotate(artificial bool) error {
+	var flags uint16
+	if artificial {
+		flags = binlogEventArtificialFlag
+	}
+	body := dumpUint64(make([]byte, 0, 8+len(d.file)), binlogFileStartPos)
+	body = append(body, d.file...)
+	return d.writeEvent(binlogRotateEvent, 0, flags, body)
+}
+
+func (d *binlogDumper) writeFormatDescription(artificial bool) error {
+	var flags uint16
+	if artificial {
+		flags = binlogEventArtificialFlag
+	}
+	body := make([]byte, 0, 57+binlogEventTypeCount+1)
+	body = dumpUint16(body, binlogVersion)
+	version := make([]byte, 50)
+	copy(version, mysql.ServerVersion)
+	body = append(body, version...)
+	body = dumpUint32(body, 0)
+	body = append(body, binlogEventHeaderLen)
+	body = append(body, binlogPostHeaderLen[:]...)
+	if d.checksum {
+		body = append(body, binlogChecksumCRC32)
+	} else {
+		body = append(body, binlogChecksumOff)
+	}
+	return d.writeEvent(binlogFormatDescriptionEvent, uint32(time.Now().Unix()), flags, body)
+}
+
+// rotate writes the rotate event to the file of the transaction and the format description event of the file.
+func (d *binlogDumper) rotate(commitTS uint64, artificial bool) error {
+	d.file = binlogFileName(commitTS)
+	if err := d.writeRotate(artificial); err != nil {
+		return err
+	}
+	d.pos = binlogFileStartPos
+	return d.writeFormatDescription(false)
+}
+
+func (d *binlogDumper) writeGTID(timestamp uint32, commitTS uint64) error {
+	// GTID event: flags (1), sid (16), gno (8), lt_type (1), last_committed (8), sequence_number (8).
+	body := make([]byte, 0, 42)
+	body = append(body, 1)
+	body = append(body, d.serverUUID[:]...)
+	body = dumpUint64(body, commitTS)
+	body = append(body, 2)
+	body = dumpUint64(body, 0)
+	body = dumpUint64(body, 1)
+	return d.writeEvent(binlogGTIDEvent, timestamp, 0, body)
+}
+
+func (d *binlogDumper) writeQuery(timestamp uint32, schema, query string) error {
+	// Query event: thread id (4), exec time (4), schema length (1), error code (2), status vars length (2), schema, query.
+	body := make([]byte, 13, 14+len(schema)+len(query))
+	body[8] = byte(len(schema))
+	body = append(body, schema...)
+	body = append(body, 0)
+	body = append(body, query...)
+	return d.writeEvent(binlogQueryEvent, timestamp, 0, body)
+}
+
+// writeDDL writes the query of the DDL job, which is a transaction itself.
+func (d *binlogDumper) writeDDL(ev *binloginfo.ChangeEvent, job *model.Job) error {
+	timestamp := uint32(oracle.GetTimeFromTS(ev.CommitTS).Unix())
+	schema := job.SchemaName
+	if schema == "" {
+		if is, err := d.dom.GetSnapshotInfoSchema(ev.CommitTS); err == nil {
+			if db, ok := is.SchemaByID(job.SchemaID); ok {
+				schema = db.Name.O
+			}
+		}
+	}
+	if err := d.writeGTID(timestamp, ev.CommitTS); err != nil {
+		return err
+	}
+	if err := d.writeQuery(timestamp, schema, ev.DDLQuery); err != nil {
+		return err
+	}
+	return d.rotate(ev.CommitTS, false)
+}
+
+func (d *binlogDumper) writeTransaction(ev *binloginfo.ChangeEvent) error {
+	timestamp := uint32(oracle.GetTimeFromTS(ev.CommitTS).Unix())
+	is, err := d.dom.GetSnapshotInfoSchema(ev.CommitTS)
+	if err != nil {
+		return err
+	}
+
+	if err := d.writeGTID(timestamp, ev.CommitTS); err != nil {
+		return err
+	}
+	if err := d.writeQuery(timestamp, "", "BEGIN"); err != nil {
+		return err
+	}
+
+	rows := &binlogRowsWriter{dumper: d, timestamp: timestamp}
+	for i := range ev.Mutations {
+		m := &ev.Mutations[i]
+		tbl, ok := newBinlogTable(is, m.TableId)
+		if !ok {
+			logutil.BgLogger().Warn("skip the binlog of the unknown table", zap.Int64("tableID", m.TableId),
+				zap.Uint64("commitTS", ev.CommitTS))
+			continue
+		}
+		body, err := tbl.tableMapBody()
+		if err != nil {
+			return err
+		}
+		if err := d.writeEvent(binlogTableMapEvent, timestamp, 0, body); err != nil {
+			return err
+		}
+		if err := rows.writeMutation(tbl, m); err != nil {
+			return err
+		}
+	}
+	if err := rows.flush(true); err != nil {
+		return err
+	}
+
+	if err := d.writeEvent(binlogXIDEvent, timestamp, 0, dumpUint64(nil, ev.StartTS)); err != nil {
+		return err
+	}
+	return d.rotate(ev.CommitTS, false)
+}
+
+// binlogTable is the table of the rows events, the columns are the public visible columns of the table.
+type binlogTable struct {
+	id      int64
+	schema  string
+	name    string
+	cols    []*model.ColumnInfo
+	offsets map[int64]int
+	// handleLen is the number of the handle datums prefixed to the inserted rows.
+	handleLen int
+	// handleOffsets are the offsets of the handle columns in the order of the handle datums, their
+	// values may be stored as the handle instead of in the inserted rows.
+	handleOffsets []int
+}
+
+func newBinlogTable(is infoschema.InfoSchema, physicalID int64) (*binlogTable, bool) {
+	tbl, ok := is.TableByID(physicalID)
+	if !ok {
+		if tbl, _, _ = is.FindTableByPartitionID(physicalID); tbl == nil {
+			return nil, false
+		}
+	}
+	tblInfo := tbl.Meta()
+	db, ok := is.SchemaByTable(tblInfo)
+	if !ok {
+		return nil, false
+	}
+	t := &binlogTable{
+		id:        physicalID,
+		schema:    db.Name.O,
+		name:      tblInfo.Name.O,
+		offsets:   make(map[int64]int, len(tblInfo.Columns)),
+		handleLen: 1,
+	}
+	for _, col := range tblInfo.Columns {
+		if col.State != model.StatePublic || col.Hidden {
+			continue
+		}
+		if tblInfo.PKIsHandle && mysql.HasPriKeyFlag(col.Flag) {
+			t.handleOffsets = []int{len(t.cols)}
+		}
+		t.offsets[col.ID] = len(t.cols)
+		t.cols = append(t.cols, col)
+	}
+	if tblInfo.IsCommonHandle {
+		for _, idx := range tblInfo.Indices {
+			if !idx.Primary {
+				continue
+			}
+			t.handleLen = len(idx.Columns)
+			t.handleOffsets = make([]int, 0, len(idx.Columns))
+			for _, idxCol := range idx.Columns {
+				offset, ok := t.offsets[tblInfo.Columns[idxCol.Offset].ID]
+				if !ok {
+					offset = -1
+				}
+				t.handleOffsets = append(t.handleOffsets, offset)
+			}
+		}
+	}
+	return t, true
+}
+
+// tableMapBody encodes the table map event, see https://dev.mysql.com/doc/internals/en/table-map-event.html.
+func (t *binlogTable) tableMapBody() ([]byte, error) {
+	body := make([]byte, 0, 64+len(t.cols)*16)
+	body = appendBinlogTableID(body, t.id)
+	body = dumpUint16(body, 0)
+	body = append(body, byte(len(t.schema)))
+	body = append(body, t.schema...)
+	body = append(body, 0)
+	body = append(body, byte(len(t.name)))
+	body = append(body, t.name...)
+	body = append(body, 0)
+	body = dumpLengthEncodedInt(body, uint64(len(t.cols)))
+	var meta []byte
+	for _, col := range t.cols {
+		tp, colMeta, err := binlogColumnType(&col.FieldType)
+		if err != nil {
+			return nil, errors.Annotatef(err, "column %s.%s.%s", t.schema, t.name, col.Name.O)
+		}
+		body = append(body, tp)
+		meta = append(meta, colMeta...)
+	}
+	body = dumpLengthEncodedString(body, meta)
+	nullable := make([]byte, (len(t.cols)+7)/8)
+	for i, col := range t.cols {
+		if !mysql.HasNotNullFlag(col.Flag) {
+			nullable[i/8] |= 1 << (i % 8)
+		}
+	}
+	body = append(body, nullable...)
+
+	// optional metadata, the signedness of the numeric columns is stored from the most significant bit.
+	var signedness []byte
+	numeric := 0
+	for _, col := range t.cols {
+		switch col.Tp {
+		case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong,
+			mysql.TypeFloat, mysql.TypeDouble, mysql.TypeNewDecimal:
+			if numeric%8 == 0 {
+				signedness = append(signedness, 0)
+			}
+			if mysql.HasUnsignedFlag(col.Flag) {
+				signedness[numeric/8] |= 1 << (7 - numeric%8)
+			}
+			numeric++
+		}
+	}
+	if numeric > 0 {
+		body = append(body, binlogTableMapSignedness)
+		body = dumpLengthEncodedString(body, signedness)
+	}
+	var names []byte
+	for _, col := range t.cols {
+		names = dumpLengthEncodedString(names, []byte(col.Name.O))
+	}
+	body = append(body, binlogTableMapColumnName)
+	body = dumpLengthEncodedString(body, names)
+	return body, nil
+}
+
+func appendBinlogTableID(buf []byte, id int64) []byte {
+	return append(buf, byte(id), byte(id>>8), byte(id>>16), byte(id>>24), byte(id>>32), byte(id>>40))
+}
+
+// decodeRow decodes the column ID and value pairs encoded by tablecodec.EncodeOldRow, the values of the
+// columns not in the row are nil.
+func (t *binlogTable) decodeRow(datums []types.Datum) ([]*types.Datum, error) {
+	row := make([]*types.Datum, len(t.cols))
+	for i := 0; i+1 < len(datums); i += 2 {
+		offset, ok := t.offsets[datums[i].GetInt64()]
+		if !ok {
+			continue
+		}
+		// the timestamp values are flattened in UTC.
+		d, err := tablecodec.Unflatten(datums[i+1], &t.cols[offset].FieldType, time.UTC)
+		if err != nil {
+			return nil, err
+		}
+		row[offset] = &d
+	}
+	return row, nil
+}
+
+// fillInsertedRow fills the columns skipped by the inserted row, they are either stored as the handle
+// or NULL without default values. The virtual generated columns are left absent.
+func (t *binlogTable) fillInsertedRow(row []*types.Datum, handle []types.Datum) error {
+	for i, offset := range t.handleOffsets {
+		if offset < 0 || row[offset] != nil {
+			continue
+		}
+		col := t.cols[offset]
+		d, err := tablecodec.Unflatten(handle[i], &col.FieldType, time.UTC)
+		if err != nil {
+			return err
+		}
+		if d.Kind() == types.KindInt64 && mysql.HasUnsignedFlag(col.Flag) {
+			// the integer handle is always signed.
+			d.SetUint64(uint64(d.GetInt64()))
+		}
+		row[offset] = &d
+	}
+	for offset, col := range t.cols {
+		if row[offset] == nil && (!col.IsGenerated() || col.GeneratedStored) {
+			d := types.NewDatum(nil)
+			row[offset] = &d
+		}
+	}
+	return nil
+}
+
+// binlogRowsWriter merges the consecutive rows of the same table and type into rows events.
+type binlogRowsWriter struct {
+	dumper    *binlogDumper
+	timestamp uint32
+	tbl       *binlogTable
+	tp        byte
+	present   []byte
+	body      []byte
+}
+
+func (w *binlogRowsWriter) writeMutation(tbl *binlogTable, m *binlog.TableMutation) error {
+	var insertIdx, updateIdx, deleteIdx int
+	for _, tp := range m.Sequence {
+		var (
+			before, after []*types.Datum
+			eventTp       byte
+		)
+		switch tp {
+		case binlog.MutationType_Insert:
+			if insertIdx >= len(m.InsertedRows) {
+				return errors.New("invalid binlog mutation")
+			}
+			datums, err := codec.Decode(m.InsertedRows[insertIdx], len(tbl.cols)*2+tbl.handleLen)
+			if err != nil {
+				return err
+			}
+			insertIdx++
+			if len(datums) < tbl.handleLen {
+				return errors.New("invalid binlog mutation")
+			}
+			if after, err = tbl.decodeRow(datums[tbl.handleLen:]); err != nil {
+				return err
+			}
+			if err = tbl.fillInsertedRow(after, datums[:tbl.handleLen]); err != nil {
+				return err
+			}
+			eventTp = binlogWriteRowsEventV2
+		case binlog.MutationType_Update:
+			if updateIdx >= len(m.UpdatedRows) {
+				return errors.New("invalid binlog mutation")
+			}
+			datums, err := codec.Decode(m.UpdatedRows[updateIdx], len(tbl.cols)*4)
+			if err != nil {
+				return err
+			}
+			updateIdx++
+			if before, err = tbl.decodeRow(datums[:len(datums)/2]); err != nil {
+				return err
+			}
+			if after, err = tbl.decodeRow(datums[len(datums)/2:]); err != nil {
+				return err
+			}
+			eventTp = binlogUpdateRowsEventV2
+		case binlog.MutationType_DeleteRow:
+			if deleteIdx >= len(m.DeletedRows) {
+				return errors.New("invalid binlog mutation")
+			}
+			datums, err := codec.Decode(m.DeletedRows[deleteIdx], len(tbl.cols)*2)
+			if err != nil {
+				return err
+			}
+			deleteIdx++
+			if before, err = tbl.decodeRow(datums); err != nil {
+				return err
+			}
+			eventTp = binlogDeleteRowsEventV2
+		default:
+			continue
+		}
+		if err := w.appendRow(tbl, eventTp, before, after); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func presentBitmap(row []*types.Datum) []byte {
+	bitmap := make([]byte, (len(row)+7)/8)
+	for i, d := range row {
+		if d != nil {
+			bitmap[i/8] |= 1 << (i % 8)
+		}
+	}
+	return bitmap
+}
+
+func (w *binlogRowsWriter) appendRow(tbl *binlogTable, tp byte, before, after []*types.Datum) error {
+	var present []byte
+	if before != nil {
+		present = append(present, presentBitmap(before)...)
+	}
+	if after != nil {
+		present = append(present, presentBitmap(after)...)
+	}
+	if w.tbl != tbl || w.tp != tp || !bytes.Equal(w.present, present) || len(w.body) >= binlogRowsEventMaxSize {
+		if err := w.flush(false); err != nil {
+			return err
+		}
+		// table id (6), flags (2), extra data length (2), column count, columns present bitmaps.
+		w.tbl, w.tp, w.present = tbl, tp, present
+		w.body = appendBinlogTableID(w.body[:0], tbl.id)
+		w.body = dumpUint16(w.body, 0)
+		w.body = dumpUint16(w.body, 2)
+		w.body = dumpLengthEncodedInt(w.body, uint64(len(tbl.cols)))
+		w.body = append(w.body, present...)
+	}
+	var err error
+	for _, row := range [][]*types.Datum{before, after} {
+		if row == nil {
+			continue
+		}
+		if w.body, err = w.appendRowImage(w.body, tbl, row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *binlogRowsWriter) appendRowImage(buf []byte, tbl *binlogTable, row []*types.Datum) ([]byte, error) {
+	present := 0
+	for _, d := range row {
+		if d != nil {
+			present++
+		}
+	}
+	nullStart := len(buf)
+	buf = append(buf, make([]byte, (present+7)/8)...)
+	i := 0
+	var err error
+	for offset, d := range row {
+		if d == nil {
+			continue
+		}
+		if d.IsNull() {
+			buf[nullStart+i/8] |= 1 << (i % 8)
+		} else if buf, err = appendBinlogValue(buf, &tbl.cols[offset].FieldType, *d); err != nil {
+			return nil, errors.Annotatef(err, "column %s.%s.%s", tbl.schema, tbl.name, tbl.cols[offset].Name.O)
+		}
+		i++
+	}
+	return buf, nil
+}
+
+// flush writes the pending rows event, the last rows event of the transaction is marked with STMT_END_F.
+func (w *binlogRowsWriter) flush(stmtEnd bool) error {
+	if w.tbl == nil {
+		return nil
+	}
+	if stmtEnd {
+		binary.LittleEndian.PutUint16(w.body[6:], binlogRowsStmtEndFlag)
+	}
+	// the body is copied by writeEvent, so it can be reused.
+	err := w.dumper.writeEvent(w.tp, w.timestamp, 0, w.body)
+	w.tbl = nil
+	return err
+}
+
+func charsetMaxLen(ft *types.FieldType) int {
+	if cs, err := charset.GetCharsetInfo(ft.Charset); err == nil {
+		return cs.Maxlen
+	}
+	return 1
+}
+
+// binlogColumnType returns the type and the metadata of the column in the table map event.
+func binlogColumnType(ft *types.FieldType) (tp byte, meta []byte, err error) {
+	switch ft.Tp {
+	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong,
+		mysql.TypeYear, mysql.TypeDate:
+		return ft.Tp, nil, nil
+	case mysql.TypeFloat:
+		return ft.Tp, []byte{4}, nil
+	case mysql.TypeDouble:
+		return ft.Tp, []byte{8}, nil
+	case mysql.TypeNewDecimal:
+		return ft.Tp, []byte{byte(ft.Flen), byte(ft.Decimal)}, nil
+	case mysql.TypeDatetime:
+		return binlogTypeDatetime2, []byte{byte(binlogFsp(ft))}, nil
+	case mysql.TypeTimestamp:
+		return binlogTypeTimestamp2, []byte{byte(binlogFsp(ft))}, nil
+	case mysql.TypeDuration:
+		return binlogTypeTime2, []byte{byte(binlogFsp(ft))}, nil
+	case mysql.TypeVarchar, mysql.TypeVarString:
+		return mysql.TypeVarchar, dumpUint16(nil, uint16(ft.Flen*charsetMaxLen(ft))), nil
+	case mysql.TypeString:
+		maxLen := ft.Flen * charsetMaxLen(ft)
+		return mysql.TypeString, []byte{mysql.TypeString ^ byte((maxLen&0x300)>>4), byte(maxLen)}, nil
+	case mysql.TypeEnum:
+		return mysql.TypeString, []byte{mysql.TypeEnum, enumPackLen(ft)}, nil
+	case mysql.TypeSet:
+		return mysql.TypeString, []byte{mysql.TypeSet, setPackLen(ft)}, nil
+	case mysql.TypeBit:
+		return ft.Tp, []byte{byte(ft.Flen % 8), byte(ft.Flen / 8)}, nil
+	case mysql.TypeTinyBlob, mysql.TypeBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob:
+		return mysql.TypeBlob, []byte{blobPackLen(ft.Tp)}, nil
+	case mysql.TypeJSON:
+		return ft.Tp, []byte{4}, nil
+	}
+	return 0, nil, errors.Errorf("unsupported column type %d in binlog", ft.Tp)
+}
+
+func binlogFsp(ft *types.FieldType) int {
+	if ft.Decimal < 0 {
+		return 0
+	}
+	return ft.Decimal
+}
+
+func enumPackLen(ft *types.FieldType) byte {
+	if len(ft.Elems) < 256 {
+		return 1
+	}
+	return 2
+}
+
+func setPackLen(ft *types.FieldType) byte {
+	return byte((len(ft.Elems) + 7) / 8)
+}
+
+func blobPackLen(tp byte) byte {
+	switch tp {
+	case mysql.TypeTinyBlob:
+		return 1
+	case mysql.TypeBlob:
+		return 2
+	case mysql.TypeMediumBlob:
+		return 3
+	}
+	return 4
+}
+
+func appendUintLE(buf []byte, v uint64, n int) []byte {
+	for i := 0; i < n; i++ {
+		buf = append(buf, byte(v>>(8*i)))
+	}
+	return buf
+}
+
+func appendUintBE(buf []byte, v uint64, n int) []byte {
+	for i := n - 1; i >= 0; i-- {
+		buf = append(buf, byte(v>>(8*i)))
+	}
+	return buf
+}
+
+// appendBinlogFrac appends the fractional seconds of the temporal types.
+func appendBinlogFrac(buf []byte, microsecond int, fsp int) []byte {
+	switch fsp {
+	case 1, 2:
+		return append(buf, byte(microsecond/10000))
+	case 3, 4:
+		return appendUintBE(buf, uint64(microsecond/100), 2)
+	case 5, 6:
+		return appendUintBE(buf, uint64(microsecond), 3)
+	}
+	return buf
+}
+
+// appendBinlogValue appends the value in the binary format of the rows event,
+// see https://dev.mysql.com/doc/internals/en/binary-protocol-value.html and
+// log_event.cc of MySQL for the temporal types.
+func appendBinlogValue(buf []byte, ft *types.FieldType, d types.Datum) ([]byte, error) {
+	switch ft.Tp {
+	case mysql.TypeTiny:
+		return appendUintLE(buf, uint64(d.GetInt64()), 1), nil
+	case mysql.TypeShort:
+		return appendUintLE(buf, uint64(d.GetInt64()), 2), nil
+	case mysql.TypeInt24:
+		return appendUintLE(buf, uint64(d.GetInt64()), 3), nil
+	case mysql.TypeLong:
+		return appendUintLE(buf, uint64(d.GetInt64()), 4), nil
+	case mysql.TypeLonglong:
+		return appendUintLE(buf, uint64(d.GetInt64()), 8), nil
+	case mysql.TypeYear:
+		year := d.GetInt64()
+		if year > 0 {
+			year -= 1900
+		}
+		return append(buf, byte(year)), nil
+	case mysql.TypeFloat:
+		return appendUintLE(buf, uint64(math.Float32bits(d.GetFloat32())), 4), nil
+	case mysql.TypeDouble:
+		return appendUintLE(buf, math.Float64bits(d.GetFloat64()), 8), nil
+	case mysql.TypeNewDecimal:
+		bin, err := d.GetMysqlDecimal().ToBin(ft.Flen, ft.Decimal)
+		if err != nil {
+			return nil, err
+		}
+		return append(buf, bin...), nil
+	case mysql.TypeDate:
+		t := d.GetMysqlTime()
+		return appendUintLE(buf, uint64(t.Day()+t.Month()*32+t.Year()*16*32), 3), nil
+	case mysql.TypeDatetime:
+		t := d.GetMysqlTime()
+		ymd := uint64((t.Year()*13+t.Month())<<5 | t.Day())
+		hms := uint64(t.Hour()<<12 | t.Minute()<<6 | t.Second())
+		buf = appendUintBE(buf, (ymd<<17|hms)+0x8000000000, 5)
+		return appendBinlogFrac(buf, t.Microsecond(), binlogFsp(ft)), nil
+	case mysql.TypeTimestamp:
+		t := d.GetMysqlTime()
+		var sec int64
+		if !t.IsZero() {
+			goTime, err := t.CoreTime().GoTime(time.UTC)
+			if err != nil {
+				return nil, err
+			}
+			sec = goTime.Unix()
+		}
+		buf = appendUintBE(buf, uint64(sec), 4)
+		return appendBinlogFrac(buf, t.Microsecond(), binlogFsp(ft)), nil
+	case mysql.TypeDuration:
+		return appendBinlogTime2(buf, d.GetMysqlDuration(), binlogFsp(ft)), nil
+	case mysql.TypeVarchar, mysql.TypeVarString:
+		b := d.GetBytes()
+		if ft.Flen*charsetMaxLen(ft) < 256 {
+			buf = append(buf, byte(len(b)))
+		} else {
+			buf = dumpUint16(buf, uint16(len(b)))
+		}
+		return append(buf, b...), nil
+	case mysql.TypeString:
+		b := d.GetBytes()
+		if ft.Flen*charsetMaxLen(ft) < 256 {
+			buf = append(buf, byte(len(b)))
+		} else {
+			buf = dumpUint16(buf, uint16(len(b)))
+		}
+		return append(buf, b...), nil
+	case mysql.TypeEnum:
+		return appendUintLE(buf, d.GetMysqlEnum().Value, int(enumPackLen(ft))), nil
+	case mysql.TypeSet:
+		return appendUintLE(buf, d.GetMysqlSet().Value, int(setPackLen(ft))), nil
+	case mysql.TypeBit:
+		v, err := d.GetBinaryLiteral().ToInt(nil)
+		if err != nil {
+			return nil, err
+		}
+		return appendUintBE(buf, v, (ft.Flen+7)/8), nil
+	case mysql.TypeTinyBlob, mysql.TypeBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob:
+		b := d.GetBytes()
+		buf = appendUintLE(buf, uint64(len(b)), int(blobPackLen(ft.Tp)))
+		return append(buf, b...), nil
+	case mysql.TypeJSON:
+		j := d.GetMysqlJSON()
+		buf = dumpUint32(buf, uint32(len(j.Value)+1))
+		buf = append(buf, byte(j.TypeCode))
+		return append(buf, j.Value...), nil
+	}
+	return nil, errors.Errorf("unsupported column type %d in binlog", ft.Tp)
+}
+
+// appendBinlogTime2 appends the TIME2 value like my_time_packed_to_binary of MySQL.
+func appendBinlogTime2(buf []byte, dur types.Duration, fsp int) []byte {
+	const intOffset, fracOffset = 0x800000, 0x800000000000
+	neg := dur.Duration < 0
+	abs := dur.Duration
+	if neg {
+		abs = -abs
+	}
+	hour := int64(abs / time.Hour)
+	minute := int64(abs % time.Hour / time.Minute)
+	second := int64(abs % time.Minute / time.Second)
+	micro := int64(abs % time.Second / time.Microsecond)
+	packed := (hour<<12|minute<<6|second)<<24 + micro
+	if neg {
+		packed = -packed
+	}
+	intPart, fracPart := packed>>24, packed%(1<<24)
+	switch fsp {
+	case 1, 2:
+		buf = appendUintBE(buf, uint64(intOffset+intPart), 3)
+		return append(buf, byte(int8(fracPart/10000)))
+	case 3, 4:
+		buf = appendUintBE(buf, uint64(intOffset+intPart), 3)
+		return appendUintBE(buf, uint64(uint16(int16(fracPart/100))), 2)
+	case 5, 6:
+		return appendUintBE(buf, uint64(packed+fracOffset), 6)
+	}
+	return appendUintBE(buf, uint64(intOffset+intPart), 3)
+}
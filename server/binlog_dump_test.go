@@ -0,0 +1,379 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"hash/crc32"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb-tools/tidb-binlog/node"
+	pumpcli "github.com/pingcap/tidb-tools/tidb-binlog/pump_client"
+	"github.com/pingcap/tidb/domain"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/sessionctx/binloginfo"
+	"github.com/pingcap/tidb/testkit"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/arena"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tipb/go-binlog"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+type testBinlogEvent struct {
+	tp     byte
+	logPos uint32
+	body   []byte
+}
+
+// parseBinlogEvents parses the events of the non-blocking binlog dump, which ends with an EOF packet.
+func parseBinlogEvents(t *testing.T, data []byte, checksum bool) []testBinlogEvent {
+	var events []testBinlogEvent
+	for len(data) > 0 {
+		length := int(data[0]) | int(data[1])<<8 | int(data[2])<<16
+		payload := data[4 : 4+length]
+		data = data[4+length:]
+		if payload[0] == mysql.EOFHeader && length < 9 {
+			require.Len(t, data, 0)
+			return events
+		}
+		require.Equal(t, byte(mysql.OKHeader), payload[0])
+		event := payload[1:]
+		require.Equal(t, uint32(len(event)), binary.LittleEndian.Uint32(event[9:]))
+		tp := event[4]
+		if checksum || tp == binlogFormatDescriptionEvent {
+			require.Equal(t, crc32.ChecksumIEEE(event[:len(event)-4]), binary.LittleEndian.Uint32(event[len(event)-4:]))
+			event = event[:len(event)-4]
+		}
+		events = append(events, testBinlogEvent{
+			tp:     tp,
+			logPos: binary.LittleEndian.Uint32(event[13:]),
+			body:   event[binlogEventHeaderLen:],
+		})
+	}
+	require.FailNow(t, "no EOF packet")
+	return nil
+}
+
+func binlogEventTypes(events []testBinlogEvent) []byte {
+	tps := make([]byte, 0, len(events))
+	for _, ev := range events {
+		tps = append(tps, ev.tp)
+	}
+	return tps
+}
+
+func binlogDumpData(file string, pos uint32) []byte {
+	data := dumpUint32(nil, pos)
+	data = dumpUint16(data, binlogDumpNonBlock)
+	data = dumpUint32(data, 100)
+	return append(data, file...)
+}
+
+func binlogDumpGTIDData(file string, pos uint64, sid []byte, end uint64) []byte {
+	data := dumpUint16(nil, binlogDumpNonBlock|binlogDumpThroughGTID)
+	data = dumpUint32(data, 100)
+	data = dumpUint32(data, uint32(len(file)))
+	data = append(data, file...)
+	data = dumpUint64(data, pos)
+	gtids := dumpUint64(nil, 1)
+	gtids = append(gtids, sid...)
+	gtids = dumpUint64(gtids, 1)
+	gtids = dumpUint64(gtids, 1)
+	gtids = dumpUint64(gtids, end)
+	data = dumpUint32(data, uint32(len(gtids)))
+	return append(data, gtids...)
+}
+
+// mockBinlogPump keeps the binlogs written by TiDB like a pump, and sends the committed ones after the
+// requested position by PullBinlogs, followed by a fake binlog of the current TSO.
+type mockBinlogPump struct {
+	store kv.Storage
+	mu    struct {
+		sync.Mutex
+		prewrites map[int64]*binlog.Binlog
+		commits   []*binlog.Binlog
+	}
+}
+
+func newMockBinlogPump(store kv.Storage) *mockBinlogPump {
+	p := &mockBinlogPump{store: store}
+	p.mu.prewrites = make(map[int64]*binlog.Binlog)
+	return p
+}
+
+func (p *mockBinlogPump) WriteBinlog(ctx context.Context, req *binlog.WriteBinlogReq) (*binlog.WriteBinlogResp, error) {
+	b := new(binlog.Binlog)
+	if err := b.Unmarshal(req.Payload); err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	switch b.Tp {
+	case binlog.BinlogType_Prewrite:
+		p.mu.prewrites[b.StartTs] = b
+	case binlog.BinlogType_Commit:
+		prewrite := p.mu.prewrites[b.StartTs]
+		delete(p.mu.prewrites, b.StartTs)
+		prewrite.Tp, prewrite.CommitTs = binlog.BinlogType_Commit, b.CommitTs
+		p.add(prewrite)
+	default:
+		delete(p.mu.prewrites, b.StartTs)
+	}
+	return &binlog.WriteBinlogResp{}, nil
+}
+
+// add adds the commit binlog in the commit TS order, it must be called with the lock held.
+func (p *mockBinlogPump) add(b *binlog.Binlog) {
+	idx := sort.Search(len(p.mu.commits), func(i int) bool { return p.mu.commits[i].CommitTs > b.CommitTs })
+	p.mu.commits = append(p.mu.commits, nil)
+	copy(p.mu.commits[idx+1:], p.mu.commits[idx:])
+	p.mu.commits[idx] = b
+}
+
+func (p *mockBinlogPump) PullBinlogs(req *binlog.PullBinlogReq, srv binlog.Pump_PullBinlogsServer) error {
+	ver, err := p.store.CurrentVersion(kv.GlobalTxnScope)
+	if err != nil {
+		return err
+	}
+	binlogs := []*binlog.Binlog{{Tp: binlog.BinlogType_Rollback, StartTs: int64(ver.Ver), CommitTs: int64(ver.Ver)}}
+	p.mu.Lock()
+	for i := len(p.mu.commits) - 1; i >= 0; i-- {
+		if b := p.mu.commits[i]; b.CommitTs > req.StartFrom.Offset && b.CommitTs < int64(ver.Ver) {
+			binlogs = append([]*binlog.Binlog{b}, binlogs...)
+		}
+	}
+	p.mu.Unlock()
+	for _, b := range binlogs {
+		payload, err := b.Marshal()
+		if err != nil {
+			return err
+		}
+		if err := srv.Send(&binlog.PullBinlogResp{Entity: binlog.Entity{Payload: payload}}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// move moves the commit binlogs of the tables to the other pump, like the transactions are committed through
+// another TiDB instance.
+func (p *mockBinlogPump) move(other *mockBinlogPump, commitTS int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	other.mu.Lock()
+	defer other.mu.Unlock()
+	for i, b := range p.mu.commits {
+		if b.CommitTs == commitTS {
+			p.mu.commits = append(p.mu.commits[:i], p.mu.commits[i+1:]...)
+			other.add(b)
+			return
+		}
+	}
+}
+
+func (p *mockBinlogPump) commitCount() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.mu.commits)
+}
+
+func startMockBinlogPump(t *testing.T, store kv.Storage) (*mockBinlogPump, string, func()) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	serv := grpc.NewServer()
+	pump := newMockBinlogPump(store)
+	binlog.RegisterPumpServer(serv, pump)
+	go func() {
+		require.NoError(t, serv.Serve(l))
+	}()
+	return pump, l.Addr().String(), serv.Stop
+}
+
+func TestBinlogDump(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	out := new(bytes.Buffer)
+	cc := &clientConn{
+		alloc:      arena.NewAllocator(1024),
+		chunkAlloc: chunk.NewAllocator(),
+		capability: mysql.ClientProtocol41,
+		pkt: &packetIO{
+			bufWriter: bufio.NewWriter(out),
+		},
+		server: &Server{dom: domain.GetDomain(tk.Session())},
+	}
+	cc.ctx = &TiDBContext{Session: tk.Session(), stmts: make(map[int]*TiDBStatement)}
+	ctx := context.Background()
+	dump := func(data []byte, withGTID bool, checksum bool) []testBinlogEvent {
+		out.Reset()
+		require.NoError(t, cc.handleBinlogDump(ctx, data, withGTID))
+		return parseBinlogEvents(t, out.Bytes(), checksum)
+	}
+
+	// the binlog is read from the pumps
+	err := cc.handleBinlogDump(ctx, binlogDumpData("", 4), false)
+	require.True(t, terror.ErrorEqual(err, errMasterFatalReadingBinlog))
+
+	// TiDB writes the binlogs to pump1, and the binlogs of the other instances are in pump2
+	pump1, addr1, stop1 := startMockBinlogPump(t, store)
+	defer stop1()
+	pump2, addr2, stop2 := startMockBinlogPump(t, store)
+	defer stop2()
+	conn, err := grpc.Dial(addr1, grpc.WithInsecure())
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, conn.Close())
+	}()
+	client := binloginfo.MockPumpsClient(binlog.NewPumpClient(conn))
+	client.Pumps.Pumps["pump-1"].Addr = addr1
+	client.Pumps.Pumps["pump-2"] = pumpcli.NewPumpStatus(&node.Status{NodeID: "pump-2", Addr: addr2, State: node.Online}, nil)
+	binloginfo.SetPumpsClient(client)
+	defer binloginfo.SetPumpsClient(nil)
+	domain.GetDomain(tk.Session()).DDL().SetBinlogClient(client)
+	defer domain.GetDomain(tk.Session()).DDL().SetBinlogClient(nil)
+	tk.Session().GetSessionVars().BinlogClient = client
+
+	events := dump(binlogDumpData("", 4), false, false)
+	require.Equal(t, []byte{binlogRotateEvent, binlogFormatDescriptionEvent}, binlogEventTypes(events))
+	require.True(t, strings.HasPrefix(string(events[0].body[8:]), binlogFilePrefix+"."))
+	require.Equal(t, uint32(0), events[0].logPos)
+	ver, err := store.CurrentVersion(kv.GlobalTxnScope)
+	require.NoError(t, err)
+	startFile := binlogFileName(ver.Ver)
+
+	tk.MustExec("create table t(a int primary key, b varchar(10))")
+	tk.MustExec("begin")
+	tk.MustExec("insert into t values (1, 'x'), (2, 'y')")
+	tk.MustExec("update t set b = 'z' where a = 1")
+	tk.MustExec("delete from t where a = 2")
+	tk.MustExec("commit")
+	tk.MustExec("insert into t values (3, null)")
+	// the commit binlogs are written asynchronously
+	require.Eventually(t, func() bool { return pump1.commitCount() == 3 }, 5*time.Second, 10*time.Millisecond)
+	pump1.mu.Lock()
+	ddlTS, firstTS, secondTS := pump1.mu.commits[0].CommitTs, pump1.mu.commits[1].CommitTs, pump1.mu.commits[2].CommitTs
+	pump1.mu.Unlock()
+	pump1.move(pump2, firstTS)
+
+	txnEvents := []byte{binlogGTIDEvent, binlogQueryEvent, binlogTableMapEvent}
+	events = dump(binlogDumpData(startFile, 4), false, false)
+	expected := []byte{binlogRotateEvent, binlogFormatDescriptionEvent,
+		binlogGTIDEvent, binlogQueryEvent, binlogRotateEvent, binlogFormatDescriptionEvent}
+	expected = append(expected, txnEvents...)
+	expected = append(expected, binlogWriteRowsEventV2, binlogUpdateRowsEventV2, binlogDeleteRowsEventV2,
+		binlogXIDEvent, binlogRotateEvent, binlogFormatDescriptionEvent)
+	expected = append(expected, txnEvents...)
+	expected = append(expected, binlogWriteRowsEventV2, binlogXIDEvent, binlogRotateEvent, binlogFormatDescriptionEvent)
+	require.Equal(t, expected, binlogEventTypes(events))
+	// the DDL job is sent by the query event with its schema
+	require.Equal(t, uint64(ddlTS), binary.LittleEndian.Uint64(events[2].body[17:]))
+	require.Equal(t, byte(4), events[3].body[8])
+	require.Equal(t, "test\x00create table t(a int primary key, b varchar(10))", string(events[3].body[13:]))
+	require.Equal(t, binlogFileName(uint64(ddlTS)), string(events[4].body[8:]))
+	events = events[4:]
+	// the positions in the file of the first transaction
+	pos := uint32(binlogFileStartPos)
+	for _, ev := range events[1:10] {
+		pos += uint32(binlogEventHeaderLen + len(ev.body))
+		if ev.tp == binlogFormatDescriptionEvent {
+			pos += binlogChecksumLen
+		}
+		require.Equal(t, pos, ev.logPos)
+	}
+	require.Equal(t, uint32(binlogEventHeaderLen+len(events[10].body)+binlogChecksumLen+binlogFileStartPos), events[10].logPos)
+	// table id (6), flags (2), extra data (2), column count, columns present, null bitmap, a, b
+	require.Equal(t, []byte{2, 3, 0, 1, 0, 0, 0, 1, 'x', 0, 2, 0, 0, 0, 1, 'y'}, events[5].body[10:])
+	require.Equal(t, []byte{2, 3, 3, 0, 1, 0, 0, 0, 1, 'x', 0, 1, 0, 0, 0, 1, 'z'}, events[6].body[10:])
+	require.Equal(t, []byte{0, 0}, events[6].body[6:8])
+	require.Equal(t, []byte{1, 0}, events[7].body[6:8])
+	require.Equal(t, []byte{2, 3, 2, 3, 0, 0, 0}, events[14].body[10:])
+	// the transaction in pump2 is merged in the commit TS order
+	require.Equal(t, uint64(firstTS), binary.LittleEndian.Uint64(events[2].body[17:]))
+	require.Equal(t, uint64(secondTS), binary.LittleEndian.Uint64(events[11].body[17:]))
+	require.Equal(t, binlogFileName(uint64(firstTS)), string(events[9].body[8:]))
+	require.Equal(t, binlogFileName(uint64(secondTS)), string(events[16].body[8:]))
+
+	// resume from the file of the second transaction, the binlog is kept by the pumps so it can be resumed
+	// through any instance after restarting
+	events = dump(binlogDumpData(binlogFileName(uint64(firstTS)), 4), false, false)
+	require.Equal(t, binlogFileName(uint64(firstTS)), string(events[0].body[8:]))
+	require.Equal(t, uint64(secondTS), binary.LittleEndian.Uint64(events[2].body[17:]))
+	require.Len(t, events, 9)
+	// the second transaction has been read
+	events = dump(binlogDumpData(binlogFileName(uint64(firstTS)), 100), false, false)
+	require.Equal(t, []byte{binlogRotateEvent, binlogFormatDescriptionEvent, binlogRotateEvent, binlogFormatDescriptionEvent},
+		binlogEventTypes(events))
+	require.Equal(t, binlogFileName(uint64(secondTS)), string(events[2].body[8:]))
+	require.Equal(t, uint32(0), events[2].logPos)
+
+	// resume from the GTID with checksum
+	tk.MustExec("set @master_binlog_checksum = 'CRC32'")
+	sid := binlogServerUUID(store.UUID())
+	events = dump(binlogDumpGTIDData("", 4, sid[:], uint64(firstTS)+1), true, true)
+	require.Len(t, events, 9)
+	require.Equal(t, sid[:], events[2].body[1:17])
+	require.Equal(t, uint64(secondTS), binary.LittleEndian.Uint64(events[2].body[17:]))
+	require.Equal(t, binlogChecksumCRC32, events[1].body[len(events[1].body)-1])
+	events = dump(binlogDumpGTIDData(binlogFilePrefix, uint64(ddlTS)-1, make([]byte, 16), 2), true, true)
+	require.Equal(t, []byte{binlogRotateEvent, binlogFormatDescriptionEvent, binlogGTIDEvent, binlogQueryEvent},
+		binlogEventTypes(events)[:4])
+
+	err = cc.handleBinlogDump(ctx, binlogDumpData("mysql-bin.000001", 4), false)
+	require.True(t, terror.ErrorEqual(err, errMasterFatalReadingBinlog))
+}
+
+func TestBinlogValue(t *testing.T) {
+	t.Parallel()
+	datetime := types.NewFieldType(mysql.TypeDatetime)
+	datetime.Decimal = 4
+	tm, err := types.ParseDatetime(nil, "2021-01-02 03:04:05.1234")
+	require.NoError(t, err)
+	buf, err := appendBinlogValue(nil, datetime, types.NewTimeDatum(tm))
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x99, 0xa8, 0x84, 0x31, 0x05, 0x04, 0xd2}, buf)
+
+	duration := types.NewFieldType(mysql.TypeDuration)
+	duration.Decimal = 1
+	buf, err = appendBinlogValue(nil, duration, types.NewDurationDatum(types.Duration{Duration: -time.Hour - 500*time.Millisecond, Fsp: 1}))
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x7f, 0xef, 0xff, 0xce}, buf)
+
+	timestamp := types.NewFieldType(mysql.TypeTimestamp)
+	tm, err = types.ParseDatetime(nil, "1970-01-01 00:01:40")
+	require.NoError(t, err)
+	tm.SetType(mysql.TypeTimestamp)
+	buf, err = appendBinlogValue(nil, timestamp, types.NewTimeDatum(tm))
+	require.NoError(t, err)
+	require.Equal(t, []byte{0, 0, 0, 100}, buf)
+
+	tp, meta, err := binlogColumnType(datetime)
+	require.NoError(t, err)
+	require.Equal(t, binlogTypeDatetime2, tp)
+	require.Equal(t, []byte{4}, meta)
+}
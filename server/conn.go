@@ -72,6 +72,7 @@ import (
 	plannercore "github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/plugin"
 	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/privilege/privileges"
 	"github.com/pingcap/tidb/session"
 	"github.com/pingcap/tidb/sessionctx"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
@@ -95,6 +96,7 @@ const (
 	connStatusReading
 	connStatusShutdown     // Closed by server.
 	connStatusWaitShutdown // Notified by server to close.
+	connStatusDraining     // Notified by the draining server to close while reading.
 )
 
 var (
@@ -181,7 +183,7 @@ type clientConn struct {
 	chunkAlloc    chunk.Allocator
 	lastPacket    []byte            // latest sql query string, currently used for logging error.
 	ctx           *TiDBContext      // an interface to execute sql statements.
-	attrs         map[string]string // attributes parsed from client handshake response, not used for now.
+	attrs         map[string]string // attributes parsed from client handshake response.
 	peerHost      string            // peer host
 	peerPort      string            // peer port
 	status        int32             // dispatching/reading/shutdown/waitshutdown
@@ -192,6 +194,8 @@ type clientConn struct {
 	isUnixSocket  bool              // connection is Unix Socket file
 	rsEncoder     *resultEncoder    // rsEncoder is used to encode the string result to different charsets.
 	socketCredUID uint32            // UID from the other end of the Unix Socket
+	zstdLevel     int               // zstd compression level requested by the client.
+	listener      *serverListener   // the additional listener accepting the connection, nil for the default ones.
 	// mu is used for cancelling the execution of current transaction.
 	mu struct {
 		sync.RWMutex
@@ -248,6 +252,15 @@ func (cc *clientConn) authSwitchRequest(ctx context.Context, plugin string) ([]b
 // during handshake, client and server negotiate compatible features and do authentication.
 // After handshake, client can send sql query to server.
 func (cc *clientConn) handshake(ctx context.Context) error {
+	// A draining server sends the error instead of the initial handshake packet, so that the client can
+	// connect to another server.
+	if cc.server.isDraining() {
+		metrics.ListenerRejectedCounter.WithLabelValues(cc.listener.name(), listenerRejectDraining).Inc()
+		if err := cc.writeError(ctx, errServerDraining); err != nil {
+			logutil.Logger(ctx).Debug("writeError failed", zap.Error(err))
+		}
+		return errServerDraining
+	}
 	if err := cc.writeInitialHandshake(ctx); err != nil {
 		if errors.Cause(err) == io.EOF {
 			logutil.Logger(ctx).Debug("Could not send handshake due to connection has be closed by client-side")
@@ -284,7 +297,7 @@ func (cc *clientConn) handshake(ctx context.Context) error {
 	}
 
 	err := cc.writePacket(data)
-	cc.pkt.sequence = 0
+	cc.pkt.resetSequence()
 	if err != nil {
 		err = errors.SuspendStack(err)
 		logutil.Logger(ctx).Debug("write response to client failed", zap.Error(err))
@@ -297,13 +310,14 @@ func (cc *clientConn) handshake(ctx context.Context) error {
 		logutil.Logger(ctx).Debug("flush response to client failed", zap.Error(err))
 		return err
 	}
-	return err
+
+	// The packets following the OK packet are compressed if the compression is negotiated.
+	return cc.pkt.setCompressionAlgorithm(cc.compressionAlgorithm(), cc.zstdLevel)
 }
 
 func (cc *clientConn) Close() error {
 	cc.server.rwlock.Lock()
-	delete(cc.server.clients, cc.connectionID)
-	connections := len(cc.server.clients)
+	connections := cc.server.removeClientWithoutLock(cc)
 	cc.server.rwlock.Unlock()
 	return closeConn(cc, connections)
 }
@@ -319,8 +333,7 @@ func closeConn(cc *clientConn, connections int) error {
 }
 
 func (cc *clientConn) closeWithoutLock() error {
-	delete(cc.server.clients, cc.connectionID)
-	return closeConn(cc, len(cc.server.clients))
+	return closeConn(cc, cc.server.removeClientWithoutLock(cc))
 }
 
 // writeInitialHandshake sends server version, connection ID, server capability, collation, server status
@@ -340,7 +353,8 @@ func (cc *clientConn) writeInitialHandshake(ctx context.Context) error {
 	// filler [00]
 	data = append(data, 0)
 	// capability flag lower 2 bytes, using default capability here
-	data = append(data, byte(cc.server.capability), byte(cc.server.capability>>8))
+	capability := cc.serverCapability()
+	data = append(data, byte(capability), byte(capability>>8))
 	// charset
 	if cc.collation == 0 {
 		cc.collation = uint8(mysql.DefaultCollationID)
@@ -350,7 +364,7 @@ func (cc *clientConn) writeInitialHandshake(ctx context.Context) error {
 	data = dumpUint16(data, mysql.ServerStatusAutocommit)
 	// below 13 byte may not be used
 	// capability flag upper 2 bytes, using default capability here
-	data = append(data, byte(cc.server.capability>>16), byte(cc.server.capability>>24))
+	data = append(data, byte(capability>>16), byte(capability>>24))
 	// length of auth-plugin-data
 	data = append(data, byte(len(cc.salt)+1))
 	// reserved 10 [00]
@@ -424,6 +438,7 @@ type handshakeResponse41 struct {
 	Auth       []byte
 	AuthPlugin string
 	Attrs      map[string]string
+	ZstdLevel  int
 }
 
 // parseOldHandshakeResponseHeader parses the old version handshake header HandshakeResponse320
@@ -576,9 +591,14 @@ func parseHandshakeResponseBody(ctx context.Context, packet *handshakeResponse41
 				return nil
 			}
 			packet.Attrs = attrs
+			offset += int(num)
 		}
 	}
 
+	if packet.Capability&mysql.ClientZstdCompressionAlgorithm > 0 && offset < len(data) {
+		packet.ZstdLevel = int(data[offset])
+	}
+
 	return nil
 }
 
@@ -661,7 +681,7 @@ func (cc *clientConn) readOptionalSSLRequestAndHandshakeResponse(ctx context.Con
 				return err
 			}
 		}
-	} else if config.GetGlobalConfig().Security.RequireSecureTransport {
+	} else if config.GetGlobalConfig().Security.RequireSecureTransport || cc.listener.requireSecureTransport() {
 		err := errSecureTransportRequired.FastGenByArgs()
 		terror.Log(err)
 		return err
@@ -678,11 +698,18 @@ func (cc *clientConn) readOptionalSSLRequestAndHandshakeResponse(ctx context.Con
 		return err
 	}
 
-	cc.capability = resp.Capability & cc.server.capability
+	cc.capability = resp.Capability & cc.serverCapability()
 	cc.user = resp.User
 	cc.dbname = resp.DBName
 	cc.collation = resp.Collation
 	cc.attrs = resp.Attrs
+	cc.zstdLevel = resp.ZstdLevel
+
+	if cc.compressionAlgorithm() == compressionNone && !isCompressionAlgorithmAllowed(compressionAlgorithmUncompressed) {
+		err := errCompressionAlgorithmNotAllowed.FastGenByArgs(compressionAlgorithmUncompressed)
+		terror.Log(err)
+		return err
+	}
 
 	err = cc.handleAuthPlugin(ctx, &resp)
 	if err != nil {
@@ -698,7 +725,9 @@ func (cc *clientConn) readOptionalSSLRequestAndHandshakeResponse(ctx context.Con
 	case mysql.AuthNativePassword:
 	case mysql.AuthSocket:
 	default:
-		return errors.New("Unknown auth plugin")
+		if plugin.GetAuthenticationPlugin(resp.AuthPlugin) == nil {
+			return errors.New("Unknown auth plugin")
+		}
 	}
 
 	err = cc.openSessionAndDoAuth(resp.Auth, resp.AuthPlugin)
@@ -708,6 +737,39 @@ func (cc *clientConn) readOptionalSSLRequestAndHandshakeResponse(ctx context.Con
 	return err
 }
 
+// serverCapability returns the capability advertised to the client. The protocol compression
+// flags are removed if the algorithms are not permitted by protocol_compression_algorithms.
+func (cc *clientConn) serverCapability() uint32 {
+	capability := cc.server.capability
+	if !isCompressionAlgorithmAllowed(compressionAlgorithmZlib) {
+		capability &^= mysql.ClientCompress
+	}
+	if !isCompressionAlgorithmAllowed(compressionAlgorithmZstd) {
+		capability &^= mysql.ClientZstdCompressionAlgorithm
+	}
+	return capability
+}
+
+// compressionAlgorithm returns the protocol compression algorithm negotiated with the client, zstd is preferred.
+func (cc *clientConn) compressionAlgorithm() int {
+	if cc.capability&mysql.ClientZstdCompressionAlgorithm > 0 {
+		return compressionZstd
+	}
+	if cc.capability&mysql.ClientCompress > 0 {
+		return compressionZlib
+	}
+	return compressionNone
+}
+
+func isCompressionAlgorithmAllowed(algorithm string) bool {
+	for _, allowed := range strings.Split(variable.ProtocolCompressionAlgorithmsValue.Load(), ",") {
+		if allowed == algorithm {
+			return true
+		}
+	}
+	return false
+}
+
 func (cc *clientConn) handleAuthPlugin(ctx context.Context, resp *handshakeResponse41) error {
 	if resp.Capability&mysql.ClientPluginAuth > 0 {
 		newAuth, err := cc.checkAuthPlugin(ctx, &resp.AuthPlugin)
@@ -727,7 +789,9 @@ func (cc *clientConn) handleAuthPlugin(ctx context.Context, resp *handshakeRespo
 		case mysql.AuthNativePassword:
 		case mysql.AuthSocket:
 		default:
-			logutil.Logger(ctx).Warn("Unknown Auth Plugin", zap.String("plugin", resp.AuthPlugin))
+			if plugin.GetAuthenticationPlugin(resp.AuthPlugin) == nil {
+				logutil.Logger(ctx).Warn("Unknown Auth Plugin", zap.String("plugin", resp.AuthPlugin))
+			}
 		}
 	} else {
 		logutil.Logger(ctx).Warn("Client without Auth Plugin support; Please upgrade client")
@@ -788,8 +852,9 @@ func (cc *clientConn) openSession() error {
 	if err != nil {
 		return err
 	}
+	cc.ctx.GetSessionVars().ConnectionAttrs = cc.attrs
 
-	err = cc.server.checkConnectionCount()
+	err = cc.server.checkConnectionCount(cc.listener)
 	if err != nil {
 		return err
 	}
@@ -818,8 +883,23 @@ func (cc *clientConn) openSessionAndDoAuth(authData []byte, authPlugin string) e
 		return errAccessDeniedNoPassword.FastGenByArgs(cc.user, host)
 	}
 
-	if !cc.ctx.Auth(&auth.UserIdentity{Username: cc.user, Hostname: host}, authData, cc.salt) {
-		return errAccessDenied.FastGenByArgs(cc.user, host, hasPassword)
+	if err = cc.ctx.AuthWithError(&auth.UserIdentity{Username: cc.user, Hostname: host}, authData, cc.salt); err != nil {
+		if privileges.ErrAccessDenied.Equal(err) {
+			return errAccessDenied.FastGenByArgs(cc.user, host, hasPassword)
+		}
+		return err
+	}
+	if err = cc.checkListenerAccess(host, hasPassword); err != nil {
+		return err
+	}
+	// The password is expired, the client has to change it before doing anything else. Clients which
+	// can't handle the sandbox mode are disconnected if disconnect_on_expired_password is ON.
+	if cc.ctx.GetSessionVars().InSandBoxMode && cc.capability&mysql.ClientCanHandleExpiredPasswords == 0 &&
+		variable.TiDBOptOn(variable.GetSysVar(variable.DisconnectOnExpiredPassword).Value) {
+		return errMustChangePasswordLogin
+	}
+	if err = cc.ctx.ConsumeConnectionResource(); err != nil {
+		return err
 	}
 	cc.ctx.SetPort(port)
 	if cc.dbname != "" {
@@ -860,6 +940,28 @@ func (cc *clientConn) checkAuthPlugin(ctx context.Context, authPlugin *string) (
 		*authPlugin = mysql.AuthNativePassword
 		return nil, nil
 	}
+	if userplugin != mysql.AuthNativePassword && userplugin != mysql.AuthCachingSha2Password {
+		authPluginImpl := plugin.GetAuthenticationPlugin(userplugin)
+		if authPluginImpl == nil {
+			return nil, errPluginIsNotLoaded.GenWithStackByArgs(userplugin)
+		}
+		// The authentication plugin always asks the client to switch to its client side plugin, so
+		// that the salt provided by the plugin is used.
+		if authPluginImpl.SetSalt != nil {
+			cc.salt = authPluginImpl.SetSalt(cc.salt)
+		}
+		clientPlugin := authPluginImpl.RequiredClientSidePlugin
+		if clientPlugin == "" {
+			clientPlugin = userplugin
+		}
+		authData, err := cc.authSwitchRequest(ctx, clientPlugin)
+		if err != nil {
+			return nil, err
+		}
+		cc.authPlugin = userplugin
+		*authPlugin = userplugin
+		return authData, nil
+	}
 
 	// If the authentication method send by the server (cc.authPlugin) doesn't match
 	// the plugin configured for the user account in the mysql.user.plugin column
@@ -996,6 +1098,17 @@ func (cc *clientConn) Run(ctx context.Context) {
 			// The judge below will not be hit by all means,
 			// But keep it stayed as a reminder and for the code reference for connStatusWaitShutdown.
 			atomic.LoadInt32(&cc.status) == connStatusWaitShutdown {
+			if atomic.LoadInt32(&cc.status) == connStatusWaitShutdown {
+				// The transaction started by the last command is allowed to finish, the server notifies
+				// the connection again after it.
+				if (cc.ctx.Status()&mysql.ServerStatusInTrans) > 0 &&
+					atomic.CompareAndSwapInt32(&cc.status, connStatusWaitShutdown, connStatusDispatching) {
+					continue
+				}
+				if cc.server.isDraining() {
+					terror.Log(cc.writeError(ctx, errServerDraining))
+				}
+			}
 			return
 		}
 
@@ -1005,6 +1118,12 @@ func (cc *clientConn) Run(ctx context.Context) {
 		cc.pkt.setReadTimeout(time.Duration(waitTimeout) * time.Second)
 		start := time.Now()
 		data, err := cc.readPacket()
+		if atomic.LoadInt32(&cc.status) == connStatusDraining {
+			// The read is interrupted by the draining server, or the command arrives after the notification,
+			// see Server.kickIdleConnection.
+			terror.Log(cc.writeError(ctx, errServerDraining))
+			return
+		}
 		if err != nil {
 			if terror.ErrorNotEqual(err, io.EOF) {
 				if netErr, isNetErr := errors.Cause(err).(net.Error); isNetErr && netErr.Timeout() {
@@ -1068,7 +1187,7 @@ func (cc *clientConn) Run(ctx context.Context) {
 			terror.Log(err1)
 		}
 		cc.addMetrics(data[0], startTime, err)
-		cc.pkt.sequence = 0
+		cc.pkt.resetSequence()
 	}
 }
 
@@ -1088,6 +1207,21 @@ func (cc *clientConn) ShutdownOrNotify() bool {
 	return false
 }
 
+// notifyDraining notifies the connection to tell the client that the server is draining and then close itself.
+// It returns true if the connection is idle and reading the next command, the caller should interrupt the read.
+func (cc *clientConn) notifyDraining() bool {
+	if (cc.ctx.Status() & mysql.ServerStatusInTrans) > 0 {
+		return false
+	}
+	if atomic.CompareAndSwapInt32(&cc.status, connStatusReading, connStatusDraining) ||
+		atomic.LoadInt32(&cc.status) == connStatusDraining {
+		return true
+	}
+	// The connection is dispatching, the loop in clientConn.Run will detect it after the command.
+	atomic.CompareAndSwapInt32(&cc.status, connStatusDispatching, connStatusWaitShutdown)
+	return false
+}
+
 func errStrForLog(err error, enableRedactLog bool) string {
 	if enableRedactLog {
 		// currently, only ErrParse is considered when enableRedactLog because it may contain sensitive information like
@@ -1199,6 +1333,18 @@ func (cc *clientConn) dispatch(ctx context.Context, data []byte) error {
 	cc.lastPacket = data
 	cmd := data[0]
 	data = data[1:]
+	vars := cc.ctx.GetSessionVars()
+	vars.QueryAttributes = nil
+	if cmd == mysql.ComQuery && cc.capability&mysql.ClientQueryAttributes > 0 {
+		attrs, query, err := parseQueryAttributes(vars.StmtCtx, data)
+		if err != nil {
+			return err
+		}
+		vars.QueryAttributes = attrs
+		// Keep the last packet as a plain COM_QUERY for the logs and labels.
+		data = query
+		cc.lastPacket = append([]byte{cmd}, query...)
+	}
 	if variable.TopSQLEnabled() {
 		defer pprof.SetGoroutineLabels(ctx)
 	}
@@ -1226,19 +1372,18 @@ func (cc *clientConn) dispatch(ctx context.Context, data []byte) error {
 			pprof.SetGoroutineLabels(ctx)
 		}
 	}
-	token := cc.server.getToken()
+	token := cc.server.getToken(cc.listener)
 	defer func() {
 		// if handleChangeUser failed, cc.ctx may be nil
 		if cc.ctx != nil {
 			cc.ctx.SetProcessInfo("", t, mysql.ComSleep, 0)
 		}
 
-		cc.server.releaseToken(token)
+		cc.server.releaseToken(cc.listener, token)
 		span.Finish()
 		cc.lastActive = time.Now()
 	}()
 
-	vars := cc.ctx.GetSessionVars()
 	// reset killed for each request
 	atomic.StoreUint32(&vars.Killed, 0)
 	if cmd < mysql.ComEnd {
@@ -1248,7 +1393,7 @@ func (cc *clientConn) dispatch(ctx context.Context, data []byte) error {
 	dataStr := string(hack.String(data))
 	switch cmd {
 	case mysql.ComPing, mysql.ComStmtClose, mysql.ComStmtSendLongData, mysql.ComStmtReset,
-		mysql.ComSetOption, mysql.ComChangeUser:
+		mysql.ComSetOption, mysql.ComChangeUser, mysql.ComBinlogDump, mysql.ComBinlogDumpGtid, mysql.ComRegisterSlave:
 		cc.ctx.SetProcessInfo("", t, cmd, 0)
 	case mysql.ComInitDB:
 		cc.ctx.SetProcessInfo("use "+dataStr, t, cmd, 0)
@@ -1295,7 +1440,11 @@ func (cc *clientConn) dispatch(ctx context.Context, data []byte) error {
 	// ComTime, ComDelayedInsert
 	case mysql.ComChangeUser:
 		return cc.handleChangeUser(ctx, data)
-	// ComBinlogDump, ComTableDump, ComConnectOut, ComRegisterSlave
+	case mysql.ComBinlogDump:
+		return cc.handleBinlogDump(ctx, data, false)
+	// ComTableDump, ComConnectOut
+	case mysql.ComRegisterSlave:
+		return cc.handleRegisterSlave(ctx)
 	case mysql.ComStmtPrepare:
 		return cc.handleStmtPrepare(ctx, dataStr)
 	case mysql.ComStmtExecute:
@@ -1310,7 +1459,9 @@ func (cc *clientConn) dispatch(ctx context.Context, data []byte) error {
 		return cc.handleSetOption(ctx, data)
 	case mysql.ComStmtFetch:
 		return cc.handleStmtFetch(ctx, data)
-	// ComDaemon, ComBinlogDumpGtid
+	// ComDaemon
+	case mysql.ComBinlogDumpGtid:
+		return cc.handleBinlogDump(ctx, data, true)
 	case mysql.ComResetConnection:
 		return cc.handleResetConnection(ctx)
 	// ComEnd
@@ -1772,6 +1923,9 @@ func (cc *clientConn) handleQuery(ctx context.Context, sql string) (err error) {
 			// Save the point plan in Session so we don't need to build the point plan again.
 			cc.ctx.SetValue(plannercore.PointPlanKey, plannercore.PointPlanVal{Plan: pointPlans[i]})
 		}
+		if err = cc.ctx.ConsumeStatementResource(stmt); err != nil {
+			break
+		}
 		retryable, err = cc.handleStmt(ctx, stmt, parserWarns, i == len(stmts)-1)
 		if err != nil {
 			if !retryable || !errors.ErrorEqual(err, storeerr.ErrTiFlashServerTimeout) {
@@ -2150,71 +2304,50 @@ func (cc *clientConn) writeChunks(ctx context.Context, rs ResultSet, binary bool
 // serverStatus, a flag bit represents server information.
 // fetchSize, the desired number of rows to be fetched each time when client uses cursor.
 func (cc *clientConn) writeChunksWithFetchSize(ctx context.Context, rs ResultSet, serverStatus uint16, fetchSize int) error {
-	fetchedRows := rs.GetFetchedRows()
-
+	data := cc.alloc.AllocWithLen(4, 1024)
+	var stmtDetail *execdetails.StmtExecDetails
+	stmtDetailRaw := ctx.Value(execdetails.StmtExecDetailKey)
+	if stmtDetailRaw != nil {
+		stmtDetail = stmtDetailRaw.(*execdetails.StmtExecDetails)
+	}
 	req := rs.NewChunk(cc.chunkAlloc)
-	// if fetchedRows is not enough, getting data from recordSet.
-	for len(fetchedRows) < fetchSize {
-		// Here server.tidbResultSet implements Next method.
+	maxChunkSize := cc.ctx.GetSessionVars().MaxChunkSize
+	rowCount := 0
+	for rowCount < fetchSize {
+		req.SetRequiredRows(fetchSize-rowCount, maxChunkSize)
+		// Here server.cursorResultSet implements Next method.
 		err := rs.Next(ctx, req)
 		if err != nil {
 			return err
 		}
-		rowCount := req.NumRows()
-		if rowCount == 0 {
+		if req.NumRows() == 0 {
 			break
 		}
-		// filling fetchedRows with chunk
-		for i := 0; i < rowCount; i++ {
-			fetchedRows = append(fetchedRows, req.GetRow(i))
+		start := time.Now()
+		for i := 0; i < req.NumRows(); i++ {
+			data = data[0:4]
+			data, err = dumpBinaryRow(data, rs.Columns(), req.GetRow(i), cc.rsEncoder)
+			if err != nil {
+				return err
+			}
+			if err = cc.writePacket(data); err != nil {
+				return err
+			}
 		}
-		req = chunk.Renew(req, cc.ctx.GetSessionVars().MaxChunkSize)
+		if stmtDetail != nil {
+			stmtDetail.WriteSQLRespDuration += time.Since(start)
+		}
+		rowCount += req.NumRows()
 	}
 
 	// tell the client COM_STMT_FETCH has finished by setting proper serverStatus,
 	// and close ResultSet.
-	if len(fetchedRows) == 0 {
+	if rowCount == 0 {
 		serverStatus &^= mysql.ServerStatusCursorExists
 		serverStatus |= mysql.ServerStatusLastRowSend
 		terror.Call(rs.Close)
 		return cc.writeEOF(serverStatus)
 	}
-
-	// construct the rows sent to the client according to fetchSize.
-	var curRows []chunk.Row
-	if fetchSize < len(fetchedRows) {
-		curRows = fetchedRows[:fetchSize]
-		fetchedRows = fetchedRows[fetchSize:]
-	} else {
-		curRows = fetchedRows
-		fetchedRows = fetchedRows[:0]
-	}
-	rs.StoreFetchedRows(fetchedRows)
-
-	data := cc.alloc.AllocWithLen(4, 1024)
-	var stmtDetail *execdetails.StmtExecDetails
-	stmtDetailRaw := ctx.Value(execdetails.StmtExecDetailKey)
-	if stmtDetailRaw != nil {
-		stmtDetail = stmtDetailRaw.(*execdetails.StmtExecDetails)
-	}
-	start := time.Now()
-	var err error
-	for _, row := range curRows {
-		data = data[0:4]
-		data, err = dumpBinaryRow(data, rs.Columns(), row, cc.rsEncoder)
-		if err != nil {
-			return err
-		}
-		if err = cc.writePacket(data); err != nil {
-			return err
-		}
-	}
-	if stmtDetail != nil {
-		stmtDetail.WriteSQLRespDuration += time.Since(start)
-	}
-	if cl, ok := rs.(fetchNotifier); ok {
-		cl.OnFetchReturned()
-	}
 	return cc.writeEOF(serverStatus)
 }
 
@@ -2281,6 +2414,7 @@ func (cc *clientConn) handleResetConnection(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	cc.ctx.GetSessionVars().ConnectionAttrs = cc.attrs
 	if !cc.ctx.AuthWithoutVerification(user) {
 		return errors.New("Could not reset connection")
 	}
@@ -118,6 +118,9 @@ func (cc *clientConn) handleStmtPrepare(ctx context.Context, sql string) error {
 	return cc.flush(ctx)
 }
 
+// cursorParameterCountAvailable is the flag of COM_STMT_EXECUTE indicating that the parameter count is sent.
+const cursorParameterCountAvailable = 0x08
+
 func (cc *clientConn) handleStmtExecute(ctx context.Context, data []byte) (err error) {
 	defer trace.StartRegion(ctx, "HandleStmtExecute").End()
 	if len(data) < 9 {
@@ -150,7 +153,7 @@ func (cc *clientConn) handleStmtExecute(ctx context.Context, data []byte) (err e
 	// 0x04 CURSOR_TYPE_SCROLLABLE
 	// Now we only support forward-only, read-only cursor.
 	var useCursor bool
-	switch flag {
+	switch flag &^ cursorParameterCountAvailable {
 	case 0:
 		useCursor = false
 	case 1:
@@ -166,11 +169,27 @@ func (cc *clientConn) handleStmtExecute(ctx context.Context, data []byte) (err e
 		nullBitmaps []byte
 		paramTypes  []byte
 		paramValues []byte
+		paramNames  []string
 	)
 	numParams := stmt.NumParams()
-	args := make([]types.Datum, numParams)
-	if numParams > 0 {
-		nullBitmapLen := (numParams + 7) >> 3
+	// With CLIENT_QUERY_ATTRIBUTES, the query attributes are sent as the parameters after those of the statement,
+	// and the parameter count is sent if the statement has parameters or PARAMETER_COUNT_AVAILABLE is set.
+	paramCount := numParams
+	withAttrs := cc.capability&mysql.ClientQueryAttributes > 0
+	if withAttrs && (numParams > 0 || flag&cursorParameterCountAvailable > 0) {
+		if !isLengthEncodedIntComplete(data[pos:]) {
+			return mysql.ErrMalformPacket
+		}
+		count, _, n := parseLengthEncodedInt(data[pos:])
+		pos += n
+		if count < uint64(numParams) || count > uint64(len(data)) {
+			return mysql.ErrMalformPacket
+		}
+		paramCount = int(count)
+	}
+	args := make([]types.Datum, paramCount)
+	if paramCount > 0 {
+		nullBitmapLen := (paramCount + 7) >> 3
 		if len(data) < (pos + nullBitmapLen + 1) {
 			return mysql.ErrMalformPacket
 		}
@@ -180,25 +199,55 @@ func (cc *clientConn) handleStmtExecute(ctx context.Context, data []byte) (err e
 		// new param bound flag
 		if data[pos] == 1 {
 			pos++
-			if len(data) < (pos + (numParams << 1)) {
-				return mysql.ErrMalformPacket
+			if withAttrs {
+				var n int
+				paramTypes, paramNames, n, err = parseParamTypesAndNames(data[pos:], paramCount)
+				if err != nil {
+					return err
+				}
+				pos += n
+			} else {
+				if len(data) < (pos + (paramCount << 1)) {
+					return mysql.ErrMalformPacket
+				}
+				paramTypes = data[pos : pos+(paramCount<<1)]
+				pos += paramCount << 1
 			}
-
-			paramTypes = data[pos : pos+(numParams<<1)]
-			pos += numParams << 1
 			paramValues = data[pos:]
 			// Just the first StmtExecute packet contain parameters type,
 			// we need save it for further use.
-			stmt.SetParamsType(paramTypes)
+			stmt.SetParamsType(paramTypes[:numParams<<1])
 		} else {
+			if paramCount > numParams {
+				// The query attributes can't be parsed without their types and names.
+				return mysql.ErrMalformPacket
+			}
+			paramTypes = stmt.GetParamsType()
 			paramValues = data[pos+1:]
 		}
 
-		err = parseExecArgs(cc.ctx.GetSessionVars().StmtCtx, args, stmt.BoundParams(), nullBitmaps, stmt.GetParamsType(), paramValues)
+		boundParams := stmt.BoundParams()
+		if paramCount > numParams {
+			boundParams = append(boundParams[:numParams:numParams], make([][]byte, paramCount-numParams)...)
+		}
+		err = parseExecArgs(cc.ctx.GetSessionVars().StmtCtx, args, boundParams, nullBitmaps, paramTypes, paramValues)
 		stmt.Reset()
 		if err != nil {
 			return errors.Annotate(err, cc.preparedStmt2String(stmtID))
 		}
+		if paramCount > numParams {
+			attrs, err := queryAttributes(paramNames[numParams:], args[numParams:])
+			if err != nil {
+				return err
+			}
+			cc.ctx.GetSessionVars().QueryAttributes = attrs
+			args = args[:numParams]
+		}
+	}
+	if prepared, _ := cc.preparedStmtID2CachePreparedStmt(uint32(stmt.ID())); prepared != nil {
+		if err = cc.ctx.ConsumeStatementResource(prepared.PreparedAst.Stmt); err != nil {
+			return err
+		}
 	}
 	ctx = context.WithValue(ctx, execdetails.StmtExecDetailKey, &execdetails.StmtExecDetails{})
 	ctx = context.WithValue(ctx, util.ExecDetailsKey, &util.ExecDetails{})
@@ -234,16 +283,19 @@ func (cc *clientConn) executePreparedStmtAndWriteResult(ctx context.Context, stm
 	// we should hold the ResultSet in PreparedStatement for next stmt_fetch, and only send back ColumnInfo.
 	// Tell the client cursor exists in server by setting proper serverStatus.
 	if useCursor {
+		// The rows are read into the cursor and the statement is finished here, nothing has been
+		// sent to the client yet, so it's retryable if it fails.
+		crs, err := cc.ctx.openCursor(ctx, rs)
+		if err != nil {
+			return true, errors.Annotate(err, cc.preparedStmt2String(uint32(stmt.ID())))
+		}
 		cc.initResultEncoder(ctx)
 		defer cc.rsEncoder.clean()
-		stmt.StoreResultSet(rs)
-		err = cc.writeColumnInfo(rs.Columns(), mysql.ServerStatusCursorExists)
+		stmt.StoreResultSet(crs)
+		err = cc.writeColumnInfo(crs.Columns(), mysql.ServerStatusCursorExists)
 		if err != nil {
 			return false, err
 		}
-		if cl, ok := rs.(fetchNotifier); ok {
-			cl.OnFetchReturned()
-		}
 		// explicitly flush columnInfo to client.
 		return false, cc.flush(ctx)
 	}
@@ -310,8 +362,97 @@ func parseStmtFetchCmd(data []byte) (uint32, uint32, error) {
 	return stmtID, fetchSize, nil
 }
 
-func parseExecArgs(sc *stmtctx.StatementContext, args []types.Datum, boundParams [][]byte, nullBitmap, paramTypes, paramValues []byte) (err error) {
-	pos := 0
+// parseParamTypesAndNames parses the parameter types followed by the names, which are sent with
+// CLIENT_QUERY_ATTRIBUTES. The returned types are in the same layout as those sent without the names.
+func parseParamTypesAndNames(data []byte, paramCount int) (paramTypes []byte, names []string, pos int, err error) {
+	paramTypes = make([]byte, 0, paramCount<<1)
+	names = make([]string, 0, paramCount)
+	for i := 0; i < paramCount; i++ {
+		if len(data) < pos+2 || !isLengthEncodedIntComplete(data[pos+2:]) {
+			return nil, nil, 0, mysql.ErrMalformPacket
+		}
+		paramTypes = append(paramTypes, data[pos], data[pos+1])
+		pos += 2
+		name, _, n, err := parseLengthEncodedBytes(data[pos:])
+		if err != nil {
+			return nil, nil, 0, mysql.ErrMalformPacket
+		}
+		pos += n
+		names = append(names, string(name))
+	}
+	return paramTypes, names, pos, nil
+}
+
+// parseQueryAttributes parses the query attributes at the beginning of the COM_QUERY payload, which are sent with
+// CLIENT_QUERY_ATTRIBUTES, see https://dev.mysql.com/doc/dev/mysql-server/latest/page_protocol_com_query.html.
+// It returns the attributes and the query.
+func parseQueryAttributes(sc *stmtctx.StatementContext, data []byte) (map[string]string, []byte, error) {
+	if !isLengthEncodedIntComplete(data) {
+		return nil, nil, mysql.ErrMalformPacket
+	}
+	paramCount, _, n := parseLengthEncodedInt(data)
+	data = data[n:]
+	// parameter_set_count, always 1
+	if !isLengthEncodedIntComplete(data) {
+		return nil, nil, mysql.ErrMalformPacket
+	}
+	_, _, n = parseLengthEncodedInt(data)
+	data = data[n:]
+	if paramCount == 0 {
+		return nil, data, nil
+	}
+	if paramCount > uint64(len(data)) {
+		return nil, nil, mysql.ErrMalformPacket
+	}
+	count := int(paramCount)
+	nullBitmapLen := (count + 7) >> 3
+	// new_params_bind_flag is always 1 because the types are sent with each query.
+	if len(data) < nullBitmapLen+1 || data[nullBitmapLen] != 1 {
+		return nil, nil, mysql.ErrMalformPacket
+	}
+	nullBitmap := data[:nullBitmapLen]
+	data = data[nullBitmapLen+1:]
+	paramTypes, names, n, err := parseParamTypesAndNames(data, count)
+	if err != nil {
+		return nil, nil, err
+	}
+	data = data[n:]
+	values := make([]types.Datum, count)
+	n, err = parseBinaryArgs(sc, values, make([][]byte, count), nullBitmap, paramTypes, data)
+	if err != nil {
+		return nil, nil, err
+	}
+	attrs, err := queryAttributes(names, values)
+	if err != nil {
+		return nil, nil, err
+	}
+	return attrs, data[n:], nil
+}
+
+// queryAttributes converts the query attributes to strings, the attributes with NULL values are omitted.
+func queryAttributes(names []string, values []types.Datum) (map[string]string, error) {
+	attrs := make(map[string]string, len(names))
+	for i, name := range names {
+		if values[i].IsNull() {
+			continue
+		}
+		v, err := values[i].ToString()
+		if err != nil {
+			return nil, err
+		}
+		// The value may refer to the packet buffer which is reused.
+		attrs[name] = string(hack.Slice(v))
+	}
+	return attrs, nil
+}
+
+func parseExecArgs(sc *stmtctx.StatementContext, args []types.Datum, boundParams [][]byte, nullBitmap, paramTypes, paramValues []byte) error {
+	_, err := parseBinaryArgs(sc, args, boundParams, nullBitmap, paramTypes, paramValues)
+	return err
+}
+
+// parseBinaryArgs parses the arguments in the binary protocol, it returns the length of the parsed values.
+func parseBinaryArgs(sc *stmtctx.StatementContext, args []types.Datum, boundParams [][]byte, nullBitmap, paramTypes, paramValues []byte) (pos int, err error) {
 	var (
 		tmp    interface{}
 		v      []byte
@@ -340,7 +481,7 @@ func parseExecArgs(sc *stmtctx.StatementContext, args []types.Datum, boundParams
 		}
 
 		if (i<<1)+1 >= len(paramTypes) {
-			return mysql.ErrMalformPacket
+			return pos, mysql.ErrMalformPacket
 		}
 
 		tp := paramTypes[i<<1]
@@ -507,7 +648,7 @@ func parseExecArgs(sc *stmtctx.StatementContext, args []types.Datum, boundParams
 				var dec types.MyDecimal
 				err = sc.HandleTruncate(dec.FromString(v))
 				if err != nil {
-					return err
+					return
 				}
 				args[i] = types.NewDecimalDatum(&dec)
 			}
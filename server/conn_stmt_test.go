@@ -225,3 +225,28 @@ func TestParseStmtFetchCmd(t *testing.T) {
 		require.Equal(t, tc.err, err)
 	}
 }
+
+func TestParseQueryAttributes(t *testing.T) {
+	attr := []byte{mysql.TypeVarString, 0, 3, 'r', 'i', 'd'}
+	tests := []struct {
+		data  []byte
+		attrs map[string]string
+		query string
+		err   error
+	}{
+		{append([]byte{0, 1}, "select 1"...), nil, "select 1", nil},
+		{append(append([]byte{1, 1, 0, 1}, attr...), append([]byte{2, 'v', '1'}, "select 1"...)...), map[string]string{"rid": "v1"}, "select 1", nil},
+		// the NULL attribute is omitted
+		{append(append([]byte{1, 1, 1, 1}, attr...), "select 1"...), map[string]string{}, "select 1", nil},
+		// the types and names must be sent
+		{append([]byte{1, 1, 0, 0, 2, 'v', '1'}, "select 1"...), nil, "", mysql.ErrMalformPacket},
+		{append([]byte{1, 1, 0, 1}, attr[:4]...), nil, "", mysql.ErrMalformPacket},
+		{[]byte{0xfc, 1}, nil, "", mysql.ErrMalformPacket},
+	}
+	for _, tt := range tests {
+		attrs, query, err := parseQueryAttributes(&stmtctx.StatementContext{}, tt.data)
+		require.Truef(t, terror.ErrorEqual(err, tt.err), "err %v", err)
+		require.Equal(t, tt.attrs, attrs)
+		require.Equal(t, tt.query, string(query))
+	}
+}
@@ -18,24 +18,33 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/binary"
 	"fmt"
 	"io"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/pingcap/errors"
 	"github.com/pingcap/failpoint"
 	"github.com/pingcap/kvproto/pkg/metapb"
+	"github.com/pingcap/tidb/config"
 	"github.com/pingcap/tidb/domain"
 	"github.com/pingcap/tidb/executor"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/plugin"
 	"github.com/pingcap/tidb/session"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/sessionstates"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/store/mockstore"
 	"github.com/pingcap/tidb/store/mockstore/unistore"
 	"github.com/pingcap/tidb/table"
 	"github.com/pingcap/tidb/testkit"
+	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/arena"
 	"github.com/pingcap/tidb/util/chunk"
 	"github.com/stretchr/testify/require"
@@ -146,6 +155,54 @@ func TestIssue1768(t *testing.T) {
 	require.NotEmpty(t, p.Auth)
 }
 
+func TestParseHandshakeResponseZstdLevel(t *testing.T) {
+	t.Parallel()
+
+	capability := mysql.ClientProtocol41 | mysql.ClientSecureConnection | mysql.ClientPluginAuth |
+		mysql.ClientConnectAtts | mysql.ClientZstdCompressionAlgorithm
+	data := make([]byte, 4+4+1+23)
+	binary.LittleEndian.PutUint32(data, capability)
+	data = append(data, "root\x00"...)
+	// empty auth data
+	data = append(data, 0x00)
+	data = append(data, mysql.AuthNativePassword+"\x00"...)
+	// attributes and zstd compression level
+	data = append(data, 0x04, 0x01, 'a', 0x01, 'b', 0x07)
+
+	var resp handshakeResponse41
+	pos, err := parseHandshakeResponseHeader(context.Background(), &resp, data)
+	require.NoError(t, err)
+	err = parseHandshakeResponseBody(context.Background(), &resp, data, pos)
+	require.NoError(t, err)
+	require.Equal(t, "root", resp.User)
+	require.Equal(t, map[string]string{"a": "b"}, resp.Attrs)
+	require.Equal(t, 7, resp.ZstdLevel)
+}
+
+func TestNegotiateCompression(t *testing.T) {
+	// Not parallel, the test changes protocol_compression_algorithms.
+	defer variable.ProtocolCompressionAlgorithmsValue.Store(variable.DefProtocolCompressionAlgorithms)
+
+	cc := &clientConn{server: &Server{capability: defaultCapability}}
+	compressionCapability := mysql.ClientCompress | mysql.ClientZstdCompressionAlgorithm
+	require.Equal(t, compressionCapability, cc.serverCapability()&compressionCapability)
+
+	cc.capability = mysql.ClientCompress | mysql.ClientZstdCompressionAlgorithm
+	require.Equal(t, compressionZstd, cc.compressionAlgorithm())
+	cc.capability = mysql.ClientCompress
+	require.Equal(t, compressionZlib, cc.compressionAlgorithm())
+	cc.capability = 0
+	require.Equal(t, compressionNone, cc.compressionAlgorithm())
+
+	variable.ProtocolCompressionAlgorithmsValue.Store("zlib,uncompressed")
+	require.Equal(t, mysql.ClientCompress, cc.serverCapability()&compressionCapability)
+	variable.ProtocolCompressionAlgorithmsValue.Store("uncompressed")
+	require.Equal(t, uint32(0), cc.serverCapability()&compressionCapability)
+	require.True(t, isCompressionAlgorithmAllowed(compressionAlgorithmUncompressed))
+	variable.ProtocolCompressionAlgorithmsValue.Store("zstd")
+	require.False(t, isCompressionAlgorithmAllowed(compressionAlgorithmUncompressed))
+}
+
 func TestAuthSwitchRequest(t *testing.T) {
 	t.Parallel()
 	// this data is from a MySQL 8.0 client
@@ -681,6 +738,20 @@ func TestShutdownOrNotify(t *testing.T) {
 	cc.status = connStatusDispatching
 	require.False(t, cc.ShutdownOrNotify())
 	require.Equal(t, connStatusWaitShutdown, cc.status)
+
+	cc.status = connStatusReading
+	require.True(t, cc.notifyDraining())
+	require.Equal(t, connStatusDraining, cc.status)
+	// The read is interrupted again until the connection exits.
+	require.True(t, cc.notifyDraining())
+	require.Equal(t, connStatusDraining, cc.status)
+	cc.status = connStatusDispatching
+	require.False(t, cc.notifyDraining())
+	require.Equal(t, connStatusWaitShutdown, cc.status)
+	// The connection killed by the server is not notified.
+	cc.status = connStatusShutdown
+	require.False(t, cc.notifyDraining())
+	require.Equal(t, connStatusShutdown, cc.status)
 }
 
 type snapshotCache interface {
@@ -810,8 +881,9 @@ func TestTiFlashFallback(t *testing.T) {
 	tk.MustQuery("show warnings").Check(testkit.Rows("Error 9012 TiFlash server timeout"))
 
 	// test COM_STMT_FETCH (cursor mode)
+	// the cursor is materialized on execute, so the fallback happens before any row is fetched
 	require.NoError(t, cc.handleStmtExecute(ctx, []byte{0x1, 0x0, 0x0, 0x0, 0x1, 0x1, 0x0, 0x0, 0x0}))
-	require.Error(t, cc.handleStmtFetch(ctx, []byte{0x1, 0x0, 0x0, 0x0, 0x1, 0x0, 0x0, 0x0}))
+	require.NoError(t, cc.handleStmtFetch(ctx, []byte{0x1, 0x0, 0x0, 0x0, 0x1, 0x0, 0x0, 0x0}))
 	tk.MustExec("set @@tidb_allow_fallback_to_tikv=''")
 	require.Error(t, cc.handleStmtExecute(ctx, []byte{0x1, 0x0, 0x0, 0x0, 0x0, 0x1, 0x0, 0x0, 0x0}))
 	require.NoError(t, failpoint.Disable("github.com/pingcap/tidb/store/mockstore/unistore/BatchCopRpcErrtiflash0"))
@@ -943,3 +1015,331 @@ func TestHandleAuthPlugin(t *testing.T) {
 	err = cc.handleAuthPlugin(ctx, &resp)
 	require.NoError(t, err)
 }
+
+func TestAuthenticationPluginSwitch(t *testing.T) {
+	plugin.SetTestHook(func(_ *plugin.Plugin, _ string, _ plugin.ID) (func() *plugin.Manifest, error) {
+		return func() *plugin.Manifest {
+			m := &plugin.AuthenticationManifest{
+				Manifest: plugin.Manifest{
+					Kind:    plugin.Authentication,
+					Name:    "token_auth",
+					Version: 1,
+					OnInit: func(ctx context.Context, manifest *plugin.Manifest) error {
+						return nil
+					},
+				},
+				RequiredClientSidePlugin: mysql.AuthNativePassword,
+				AuthenticateUser: func(authUser, authHost, storedAuthString string, authResponse, salt []byte, connState *tls.ConnectionState) error {
+					if string(authResponse) != storedAuthString+string(salt) {
+						return errors.New("invalid token")
+					}
+					return nil
+				},
+				GenerateAuthenticationString: func(password string) (string, bool) {
+					return password, true
+				},
+				SetSalt: func(salt []byte) []byte {
+					return []byte("plugin-salt")
+				},
+			}
+			return plugin.ExportManifest(m)
+		}, nil
+	})
+	pluginCfg := plugin.Config{Plugins: []string{"token_auth-1"}}
+	require.NoError(t, plugin.Load(context.Background(), pluginCfg))
+	require.NoError(t, plugin.Init(context.Background(), pluginCfg))
+	defer plugin.Shutdown(context.Background())
+
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("CREATE USER 'u1'@'localhost' IDENTIFIED WITH 'token_auth' BY 'abc'")
+
+	cfg := newTestConfig()
+	cfg.Port = 0
+	cfg.Socket = ""
+	cfg.Status.StatusPort = 0
+	drv := NewTiDBDriver(store)
+	srv, err := NewServer(cfg, drv)
+	require.NoError(t, err)
+	defer srv.Close()
+
+	// The client responds to the switch request with the token signed by the plugin salt.
+	var inBuffer bytes.Buffer
+	authResp := []byte("abcplugin-salt")
+	inBuffer.Write([]byte{byte(len(authResp)), 0x00, 0x00, 0x01})
+	inBuffer.Write(authResp)
+	var outBuffer bytes.Buffer
+	pkt := newPacketIO(newBufferedReadConn(&bytesConn{inBuffer}))
+	pkt.bufWriter = bufio.NewWriter(&outBuffer)
+	cc := &clientConn{
+		connectionID: 1,
+		alloc:        arena.NewAllocator(1024),
+		chunkAlloc:   chunk.NewAllocator(),
+		pkt:          pkt,
+		server:       srv,
+		user:         "u1",
+		peerHost:     "localhost",
+		collation:    mysql.DefaultCollationID,
+		authPlugin:   mysql.AuthNativePassword,
+		salt:         []byte("server-salt"),
+	}
+	authPlugin := mysql.AuthNativePassword
+	authData, err := cc.checkAuthPlugin(context.Background(), &authPlugin)
+	require.NoError(t, err)
+	require.Equal(t, authResp, authData)
+	require.Equal(t, "token_auth", authPlugin)
+	require.Equal(t, []byte("plugin-salt"), cc.salt)
+	// The switch request asks for the client side plugin with the salt from the plugin.
+	expected := append([]byte{mysql.AuthSwitchRequest}, mysql.AuthNativePassword...)
+	expected = append(expected, 0x00)
+	expected = append(expected, "plugin-salt"...)
+	expected = append(expected, 0x00)
+	require.Equal(t, expected, outBuffer.Bytes()[4:])
+
+	require.NoError(t, cc.openSessionAndDoAuth(authData, authPlugin))
+	require.Error(t, cc.openSessionAndDoAuth([]byte("abcserver-salt"), authPlugin))
+
+	// No switch request is sent if the plugin is unloaded.
+	plugin.Shutdown(context.Background())
+	outBuffer.Reset()
+	authPlugin = mysql.AuthNativePassword
+	_, err = cc.checkAuthPlugin(context.Background(), &authPlugin)
+	require.True(t, errPluginIsNotLoaded.Equal(err))
+	require.NoError(t, cc.pkt.flush())
+	require.Zero(t, outBuffer.Len())
+}
+
+func TestExpiredPasswordLogin(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("CREATE USER 'u1'@'localhost' PASSWORD EXPIRE")
+
+	cfg := newTestConfig()
+	cfg.Port = 0
+	cfg.Socket = ""
+	cfg.Status.StatusPort = 0
+	drv := NewTiDBDriver(store)
+	srv, err := NewServer(cfg, drv)
+	require.NoError(t, err)
+	defer srv.Close()
+
+	newConn := func(capability uint32) *clientConn {
+		return &clientConn{
+			connectionID: 1,
+			alloc:        arena.NewAllocator(1024),
+			chunkAlloc:   chunk.NewAllocator(),
+			server:       srv,
+			user:         "u1",
+			peerHost:     "localhost",
+			collation:    mysql.DefaultCollationID,
+			capability:   capability,
+		}
+	}
+	// The client which can't handle the expired password is disconnected.
+	cc := newConn(0)
+	err = cc.openSessionAndDoAuth(nil, mysql.AuthNativePassword)
+	require.True(t, terror.ErrorEqual(err, errMustChangePasswordLogin))
+
+	// Otherwise the session is in the sandbox mode.
+	cc = newConn(mysql.ClientCanHandleExpiredPasswords)
+	require.NoError(t, cc.openSessionAndDoAuth(nil, mysql.AuthNativePassword))
+	require.True(t, cc.ctx.GetSessionVars().InSandBoxMode)
+}
+
+func TestQueryAttributes(t *testing.T) {
+	t.Parallel()
+
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	var outBuffer bytes.Buffer
+	tk := testkit.NewTestKit(t, store)
+	cfg := newTestConfig()
+	cfg.Socket = ""
+	cfg.Port, cfg.Status.StatusPort = 0, 0
+	cfg.Status.ReportStatus = false
+	server, err := NewServer(cfg, NewTiDBDriver(store))
+	require.NoError(t, err)
+	defer server.Close()
+	cc := &clientConn{
+		server:     server,
+		alloc:      arena.NewAllocator(1024),
+		chunkAlloc: chunk.NewAllocator(),
+		capability: mysql.ClientProtocol41 | mysql.ClientQueryAttributes,
+		pkt: &packetIO{
+			bufWriter: bufio.NewWriter(&outBuffer),
+		},
+		attrs: map[string]string{"_client_name": "libmysql"},
+	}
+	cc.ctx = &TiDBContext{Session: tk.Session(), stmts: make(map[int]*TiDBStatement)}
+	cc.ctx.GetSessionVars().ConnectionAttrs = cc.attrs
+	ctx := context.Background()
+	dispatch := func(data []byte) []byte {
+		outBuffer.Reset()
+		require.NoError(t, cc.dispatch(ctx, data))
+		require.NoError(t, cc.flush(ctx))
+		return outBuffer.Bytes()
+	}
+	attr := []byte{mysql.TypeVarString, 0, 3, 'r', 'i', 'd', 2, 'v', '1'}
+
+	// COM_QUERY with an attribute
+	data := append([]byte{mysql.ComQuery, 1, 1, 0, 1}, attr...)
+	out := dispatch(append(data, "select mysql_query_attribute_string('rid')"...))
+	require.True(t, bytes.Contains(out, []byte{2, 'v', '1'}))
+	require.Equal(t, map[string]string{"rid": "v1"}, cc.ctx.GetSessionVars().QueryAttributes)
+	out = dispatch(append([]byte{mysql.ComQuery, 0, 1}, "select mysql_query_attribute_string('rid')"...))
+	require.False(t, bytes.Contains(out, []byte{2, 'v', '1'}))
+	require.Nil(t, cc.ctx.GetSessionVars().QueryAttributes)
+
+	// COM_STMT_EXECUTE with a parameter and an attribute
+	dispatch(append([]byte{mysql.ComStmtPrepare}, "select ?, mysql_query_attribute_string('rid')"...))
+	data = []byte{mysql.ComStmtExecute, 1, 0, 0, 0, cursorParameterCountAvailable, 1, 0, 0, 0, 2, 0, 1}
+	data = append(data, mysql.TypeLonglong, 0, 0)
+	data = append(data, attr[:6]...)
+	data = append(data, 7, 0, 0, 0, 0, 0, 0, 0, 2, 'v', '2')
+	out = dispatch(data)
+	require.True(t, bytes.Contains(out, []byte{2, 'v', '2'}))
+	require.Equal(t, map[string]string{"rid": "v2"}, cc.ctx.GetSessionVars().QueryAttributes)
+
+	cc.ctx.SetProcessInfo("", time.Now(), mysql.ComSleep, 0)
+	require.Equal(t, cc.attrs, cc.ctx.ShowProcess().ConnectAttrs)
+}
+
+func TestUserResourceLimits(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("CREATE USER 'u1'@'localhost' WITH MAX_QUERIES_PER_HOUR 3 MAX_UPDATES_PER_HOUR 1 MAX_CONNECTIONS_PER_HOUR 2 MAX_USER_CONNECTIONS 1")
+	tk.MustExec("CREATE TABLE test.t (a int)")
+	tk.MustExec("GRANT ALL ON test.* TO 'u1'@'localhost'")
+
+	cfg := newTestConfig()
+	cfg.Port = 0
+	cfg.Socket = ""
+	cfg.Status.StatusPort = 0
+	drv := NewTiDBDriver(store)
+	srv, err := NewServer(cfg, drv)
+	require.NoError(t, err)
+	defer srv.Close()
+
+	newConn := func() *clientConn {
+		return &clientConn{
+			connectionID: 1,
+			alloc:        arena.NewAllocator(1024),
+			chunkAlloc:   chunk.NewAllocator(),
+			server:       srv,
+			user:         "u1",
+			peerHost:     "localhost",
+			collation:    mysql.DefaultCollationID,
+			pkt: &packetIO{
+				bufWriter: bufio.NewWriter(bytes.NewBuffer(nil)),
+			},
+		}
+	}
+	ctx := context.Background()
+	cc1 := newConn()
+	require.NoError(t, cc1.openSessionAndDoAuth(nil, mysql.AuthNativePassword))
+	// MAX_USER_CONNECTIONS is reached.
+	err = newConn().openSessionAndDoAuth(nil, mysql.AuthNativePassword)
+	require.True(t, terror.ErrorEqual(err, session.ErrTooManyUserConnections), err)
+
+	// The data modifying statements are limited by MAX_UPDATES_PER_HOUR, all the statements are
+	// limited by MAX_QUERIES_PER_HOUR.
+	require.NoError(t, cc1.handleQuery(ctx, "insert into test.t values (1)"))
+	err = cc1.handleQuery(ctx, "insert into test.t values (2)")
+	require.True(t, terror.ErrorEqual(err, session.ErrUserLimitReached), err)
+	require.NoError(t, cc1.handleQuery(ctx, "select * from test.t"))
+	require.NoError(t, cc1.handleQuery(ctx, "select 1"))
+	err = cc1.handleQuery(ctx, "select 1")
+	require.True(t, terror.ErrorEqual(err, session.ErrUserLimitReached), err)
+	// The statements are counted in memory and flushed periodically.
+	require.Eventually(t, func() bool {
+		rows := tk.MustQuery("SELECT QUESTIONS, UPDATES, CONNECTIONS, USER_CONNECTIONS FROM information_schema.user_resource_usage WHERE USER = 'u1'").Rows()
+		return fmt.Sprint(rows) == "[[3 1 1 1]]"
+	}, 5*time.Second, 100*time.Millisecond)
+
+	// The connection is released when the session is closed, but MAX_CONNECTIONS_PER_HOUR is reached later.
+	require.NoError(t, cc1.ctx.Close())
+	cc2 := newConn()
+	require.NoError(t, cc2.openSessionAndDoAuth(nil, mysql.AuthNativePassword))
+	require.NoError(t, cc2.ctx.Close())
+	err = newConn().openSessionAndDoAuth(nil, mysql.AuthNativePassword)
+	require.True(t, terror.ErrorEqual(err, session.ErrUserLimitReached), err)
+	tk.MustQuery("SELECT USER_CONNECTIONS FROM information_schema.user_resource_usage WHERE USER = 'u1'").Check(testkit.Rows("0"))
+}
+
+func TestSessionStatesMigration(t *testing.T) {
+	defer config.RestoreFunc()()
+	dir := t.TempDir()
+	certPath, keyPath := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	require.NoError(t, util.CreateTLSCertificates(certPath, keyPath, 2048))
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.Security.SessionTokenSigningCert = certPath
+		conf.Security.SessionTokenSigningKey = keyPath
+	})
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+
+	cfg := newTestConfig()
+	cfg.Port = 0
+	cfg.Socket = ""
+	cfg.Status.StatusPort = 0
+	srv, err := NewServer(cfg, NewTiDBDriver(store))
+	require.NoError(t, err)
+	defer srv.Close()
+
+	var outBuffer bytes.Buffer
+	newConn := func() *clientConn {
+		cc := &clientConn{
+			connectionID: 1,
+			alloc:        arena.NewAllocator(1024),
+			chunkAlloc:   chunk.NewAllocator(),
+			server:       srv,
+			user:         "root",
+			peerHost:     "localhost",
+			collation:    mysql.DefaultCollationID,
+			capability:   mysql.ClientProtocol41,
+			pkt: &packetIO{
+				bufWriter: bufio.NewWriter(&outBuffer),
+			},
+		}
+		require.NoError(t, cc.openSessionAndDoAuth(nil, mysql.AuthNativePassword))
+		return cc
+	}
+	ctx := context.Background()
+	dispatch := func(cc *clientConn, data []byte) []byte {
+		outBuffer.Reset()
+		require.NoError(t, cc.dispatch(ctx, data))
+		require.NoError(t, cc.flush(ctx))
+		return outBuffer.Bytes()
+	}
+	showSessionStates := func(cc *clientConn) (string, error) {
+		rs, err := cc.ctx.Execute(ctx, "show session_states")
+		require.NoError(t, err)
+		rows, err := session.ResultSetToStringSlice(ctx, cc.ctx.Session, rs[0])
+		if err != nil {
+			return "", err
+		}
+		return rows[0][0], nil
+	}
+
+	// The parameter types sent by the first COM_STMT_EXECUTE are migrated with the statement.
+	cc1 := newConn()
+	dispatch(cc1, append([]byte{mysql.ComStmtPrepare}, "select ?"...))
+	data := []byte{mysql.ComStmtExecute, 1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, mysql.TypeVarString, 0, 3, 'a', 'b', 'c'}
+	require.True(t, bytes.Contains(dispatch(cc1, data), []byte{3, 'a', 'b', 'c'}))
+	token, err := showSessionStates(cc1)
+	require.NoError(t, err)
+
+	cc2 := newConn()
+	_, err = cc2.ctx.Execute(ctx, "set session_states '"+token+"'")
+	require.NoError(t, err)
+	data = []byte{mysql.ComStmtExecute, 1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 3, 'd', 'e', 'f'}
+	require.True(t, bytes.Contains(dispatch(cc2, data), []byte{3, 'd', 'e', 'f'}))
+
+	// The long data is not migrated.
+	dispatch(cc2, []byte{mysql.ComStmtSendLongData, 1, 0, 0, 0, 0, 0, 'x'})
+	_, err = showSessionStates(cc2)
+	require.True(t, sessionstates.ErrCannotMigrateSession.Equal(err), err)
+}
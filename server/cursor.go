@@ -0,0 +1,154 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/memory"
+)
+
+// cursorResultSet is the result set of a server-side cursor. All the rows are read into a
+// row container when the cursor is opened, so the statement is finished at once and the
+// session can execute other statements or open other cursors while the cursor is open.
+// The row container is tracked by the session memory tracker, it spills to disk when the
+// open cursors of the session use more memory than tidb_mem_quota_query or tidb_mem_quota_session,
+// no matter how oom-use-tmp-storage and oom-action are set.
+type cursorResultSet struct {
+	columns      []*ColumnInfo
+	fieldTypes   []*types.FieldType
+	maxChunkSize int
+	rowContainer *chunk.RowContainer
+	// chk is the chunk being read, and chkIdx is the index of the next chunk in rowContainer.
+	chk     *chunk.Chunk
+	chkIdx  int
+	rowIdx  int
+	closed  bool
+	onClose func()
+}
+
+// openCursor reads all the rows of rs into a cursorResultSet and closes rs.
+func (tc *TiDBContext) openCursor(ctx context.Context, rs ResultSet) (_ *cursorResultSet, err error) {
+	defer func() {
+		if closeErr := rs.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	vars := tc.GetSessionVars()
+	crs := &cursorResultSet{
+		fieldTypes:   rs.FieldTypes(),
+		maxChunkSize: vars.MaxChunkSize,
+		onClose:      tc.resetCursorSpillActions,
+	}
+	crs.rowContainer = chunk.NewRowContainer(crs.fieldTypes, vars.MaxChunkSize)
+	crs.rowContainer.GetMemTracker().AttachTo(vars.MemTracker)
+	crs.rowContainer.GetMemTracker().SetLabel(memory.LabelForCursorFetch)
+	crs.rowContainer.GetDiskTracker().AttachTo(vars.DiskTracker)
+	crs.rowContainer.GetDiskTracker().SetLabel(memory.LabelForCursorFetch)
+	// The session memory tracker only tracks the open cursors, so exceeding the quota spills them
+	// instead of cancelling any statement.
+	vars.ResetMemTrackerBytesLimit()
+	vars.MemTracker.FallbackOldAndSetNewAction(crs.rowContainer.ActionSpill())
+	defer func() {
+		if err != nil {
+			terror.Call(crs.Close)
+		}
+	}()
+
+	for {
+		// The chunk is kept by the row container, so it can't be allocated by the connection allocator.
+		chk := rs.NewChunk(nil)
+		if err = rs.Next(ctx, chk); err != nil {
+			return nil, err
+		}
+		if chk.NumRows() == 0 {
+			break
+		}
+		if err = crs.rowContainer.Add(chk); err != nil {
+			return nil, err
+		}
+	}
+	// The columns are available after calling Next.
+	crs.columns = rs.Columns()
+	return crs, nil
+}
+
+// resetCursorSpillActions rebuilds the actions of the session memory tracker with the open cursors.
+func (tc *TiDBContext) resetCursorSpillActions() {
+	vars := tc.GetSessionVars()
+	vars.MemTracker.SetActionOnExceed(&memory.LogOnExceed{ConnID: vars.ConnectionID})
+	for _, stmt := range tc.stmts {
+		if crs, ok := stmt.rs.(*cursorResultSet); ok && !crs.closed {
+			action := crs.rowContainer.ActionSpill()
+			action.SetFallback(nil)
+			vars.MemTracker.FallbackOldAndSetNewAction(action)
+		}
+	}
+}
+
+func (crs *cursorResultSet) Columns() []*ColumnInfo {
+	return crs.columns
+}
+
+func (crs *cursorResultSet) FieldTypes() []*types.FieldType {
+	return crs.fieldTypes
+}
+
+func (crs *cursorResultSet) NewChunk(alloc chunk.Allocator) *chunk.Chunk {
+	if alloc == nil {
+		return chunk.New(crs.fieldTypes, crs.maxChunkSize, crs.maxChunkSize)
+	}
+	return alloc.Alloc(crs.fieldTypes, crs.maxChunkSize, crs.maxChunkSize)
+}
+
+// Next reads the rows from the row container, at most req.RequiredRows() rows are read.
+func (crs *cursorResultSet) Next(_ context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	for !req.IsFull() {
+		if crs.chk == nil || crs.rowIdx >= crs.chk.NumRows() {
+			if crs.chkIdx >= crs.rowContainer.NumChunks() {
+				break
+			}
+			chk, err := crs.rowContainer.GetChunk(crs.chkIdx)
+			if err != nil {
+				return err
+			}
+			crs.chk, crs.chkIdx, crs.rowIdx = chk, crs.chkIdx+1, 0
+			continue
+		}
+		req.AppendRow(crs.chk.GetRow(crs.rowIdx))
+		crs.rowIdx++
+	}
+	return nil
+}
+
+func (crs *cursorResultSet) Close() error {
+	if crs.closed {
+		return nil
+	}
+	crs.closed = true
+	crs.chk = nil
+	err := crs.rowContainer.Close()
+	crs.rowContainer.GetMemTracker().Detach()
+	crs.rowContainer.GetDiskTracker().Detach()
+	if crs.onClose != nil {
+		crs.onClose()
+	}
+	return err
+}
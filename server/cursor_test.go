@@ -0,0 +1,160 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/testkit"
+	"github.com/pingcap/tidb/util/arena"
+	"github.com/pingcap/tidb/util/chunk"
+	"github.com/stretchr/testify/require"
+)
+
+// parseFetchResult returns the number of rows and the server status of the COM_STMT_FETCH response.
+func parseFetchResult(t *testing.T, data []byte) (rows int, status uint16) {
+	for len(data) > 0 {
+		require.GreaterOrEqual(t, len(data), 4)
+		length := int(data[0]) | int(data[1])<<8 | int(data[2])<<16
+		payload := data[4 : 4+length]
+		data = data[4+length:]
+		if payload[0] == mysql.EOFHeader && length < 9 {
+			require.Len(t, data, 0)
+			return rows, binary.LittleEndian.Uint16(payload[3:])
+		}
+		require.Equal(t, byte(mysql.OKHeader), payload[0])
+		rows++
+	}
+	require.FailNow(t, "no EOF packet")
+	return
+}
+
+func newCursorTestConn(t *testing.T, store kv.Storage) (*clientConn, *bytes.Buffer, *testkit.TestKit) {
+	out := new(bytes.Buffer)
+	cc := &clientConn{
+		alloc:      arena.NewAllocator(1024),
+		chunkAlloc: chunk.NewAllocator(),
+		capability: mysql.ClientProtocol41,
+		pkt: &packetIO{
+			bufWriter: bufio.NewWriter(out),
+		},
+	}
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("use test")
+	cc.ctx = &TiDBContext{Session: tk.Session(), stmts: make(map[int]*TiDBStatement)}
+	tk.MustExec("create table t(a int primary key, b varchar(64))")
+	values := make([]string, 0, 100)
+	for i := 0; i < 100; i++ {
+		values = append(values, fmt.Sprintf("(%d, '%s')", i, strings.Repeat("x", 64)))
+	}
+	tk.MustExec("insert into t values " + strings.Join(values, ","))
+	return cc, out, tk
+}
+
+func fetchCursor(t *testing.T, cc *clientConn, out *bytes.Buffer, stmtID, fetchSize byte) (int, uint16) {
+	out.Reset()
+	require.NoError(t, cc.handleStmtFetch(context.Background(), []byte{stmtID, 0x0, 0x0, 0x0, fetchSize, 0x0, 0x0, 0x0}))
+	return parseFetchResult(t, out.Bytes())
+}
+
+func TestCursorFetch(t *testing.T) {
+	t.Parallel()
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	cc, out, tk := newCursorTestConn(t, store)
+
+	ctx := context.Background()
+	require.NoError(t, cc.handleStmtPrepare(ctx, "select * from t order by a"))
+	require.NoError(t, cc.handleStmtPrepare(ctx, "select a from t where a < 10"))
+	require.NoError(t, cc.handleStmtExecute(ctx, []byte{0x1, 0x0, 0x0, 0x0, 0x1, 0x1, 0x0, 0x0, 0x0}))
+	require.NoError(t, cc.handleStmtExecute(ctx, []byte{0x2, 0x0, 0x0, 0x0, 0x1, 0x1, 0x0, 0x0, 0x0}))
+
+	// other statements can be executed while the cursors are open
+	tk.MustExec("delete from t where a >= 50")
+	tk.MustQuery("select count(*) from t").Check(testkit.Rows("50"))
+
+	rows, status := fetchCursor(t, cc, out, 1, 30)
+	require.Equal(t, 30, rows)
+	require.NotZero(t, status&mysql.ServerStatusCursorExists)
+	rows, status = fetchCursor(t, cc, out, 2, 100)
+	require.Equal(t, 10, rows)
+	require.Zero(t, status&mysql.ServerStatusLastRowSend)
+	rows, status = fetchCursor(t, cc, out, 2, 100)
+	require.Equal(t, 0, rows)
+	require.NotZero(t, status&mysql.ServerStatusLastRowSend)
+
+	// the cursor reads the rows as of the time it was opened
+	rows, _ = fetchCursor(t, cc, out, 1, 100)
+	require.Equal(t, 70, rows)
+	rows, status = fetchCursor(t, cc, out, 1, 100)
+	require.Equal(t, 0, rows)
+	require.NotZero(t, status&mysql.ServerStatusLastRowSend)
+	require.Zero(t, cc.ctx.GetSessionVars().MemTracker.BytesConsumed())
+}
+
+func TestCursorSpill(t *testing.T) {
+	// the statements must not be canceled when the small memory quota is exceeded, and the cursors
+	// spill even if oom-use-tmp-storage is disabled
+	restore := config.RestoreFunc()
+	defer restore()
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.OOMUseTmpStorage = false
+		conf.OOMAction = config.OOMActionLog
+	})
+
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	cc, out, tk := newCursorTestConn(t, store)
+	tk.MustExec("set @@tidb_mem_quota_query = 1024")
+	tk.MustExec("set @@tidb_max_chunk_size = 32")
+
+	ctx := context.Background()
+	require.NoError(t, cc.handleStmtPrepare(ctx, "select * from t order by a"))
+	require.NoError(t, cc.handleStmtExecute(ctx, []byte{0x1, 0x0, 0x0, 0x0, 0x1, 0x1, 0x0, 0x0, 0x0}))
+	crs := cc.ctx.stmts[1].GetResultSet().(*cursorResultSet)
+	require.Eventually(t, crs.rowContainer.AlreadySpilledSafeForTest, 5*time.Second, 10*time.Millisecond)
+	require.Greater(t, cc.ctx.GetSessionVars().DiskTracker.BytesConsumed(), int64(0))
+
+	rows, _ := fetchCursor(t, cc, out, 1, 60)
+	require.Equal(t, 60, rows)
+	rows, _ = fetchCursor(t, cc, out, 1, 60)
+	require.Equal(t, 40, rows)
+	rows, status := fetchCursor(t, cc, out, 1, 60)
+	require.Equal(t, 0, rows)
+	require.NotZero(t, status&mysql.ServerStatusLastRowSend)
+	require.Zero(t, cc.ctx.GetSessionVars().DiskTracker.BytesConsumed())
+
+	// the cursors spill before the session exceeds the smaller tidb_mem_quota_session
+	tk.MustExec("set @@tidb_mem_quota_query = 1073741824")
+	require.Equal(t, int64(1073741824), cc.ctx.GetSessionVars().MemTracker.GetBytesLimit())
+	tk.MustExec("set @@tidb_mem_quota_session = 1024")
+	require.Equal(t, int64(1024), cc.ctx.GetSessionVars().MemTracker.GetBytesLimit())
+	require.NoError(t, cc.handleStmtPrepare(ctx, "select * from t order by a desc"))
+	require.NoError(t, cc.handleStmtExecute(ctx, []byte{0x2, 0x0, 0x0, 0x0, 0x1, 0x1, 0x0, 0x0, 0x0}))
+	crs = cc.ctx.stmts[2].GetResultSet().(*cursorResultSet)
+	require.Eventually(t, crs.rowContainer.AlreadySpilledSafeForTest, 5*time.Second, 10*time.Millisecond)
+	rows, _ = fetchCursor(t, cc, out, 2, 100)
+	require.Equal(t, 100, rows)
+}
@@ -0,0 +1,157 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"sync/atomic"
+	"time"
+	"unsafe"
+
+	"github.com/pingcap/tidb/metrics"
+	"github.com/pingcap/tidb/session/txninfo"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/tikv/client-go/v2/oracle"
+	"go.uber.org/zap"
+)
+
+// drainCheckInterval is how often the draining server closes the idle connections.
+var drainCheckInterval = time.Second
+
+// drainState is the state of a draining server, it's not changed after the draining starts.
+type drainState struct {
+	startTime time.Time
+	// deadline is zero if the server waits until all the transactions finish.
+	deadline time.Time
+	done     chan struct{}
+}
+
+// DrainStatus is the progress of draining the server, which is reported by the status API.
+type DrainStatus struct {
+	Draining             bool       `json:"draining"`
+	Finished             bool       `json:"finished"`
+	StartTime            *time.Time `json:"start_time,omitempty"`
+	Deadline             *time.Time `json:"deadline,omitempty"`
+	RemainingConnections int        `json:"remaining_connections"`
+	InTxnConnections     int        `json:"in_txn_connections"`
+	// OldestTxnConnID and OldestTxnStartTime describe the oldest open transaction.
+	OldestTxnConnID    uint64     `json:"oldest_txn_conn_id,omitempty"`
+	OldestTxnStartTime *time.Time `json:"oldest_txn_start_time,omitempty"`
+}
+
+func (s *Server) getDrainState() *drainState {
+	return (*drainState)(atomic.LoadPointer(&s.drainState))
+}
+
+// isDraining reports whether the server is draining, a draining server rejects the new connections.
+func (s *Server) isDraining() bool {
+	return s.getDrainState() != nil
+}
+
+// StartDrain puts the server into the drain mode. The server reports unhealthy on the status API and rejects the
+// new connections, the existing connections are closed once they are idle between transactions, and the connections
+// still in transactions are killed after the timeout. It returns false if the server is already draining.
+func (s *Server) StartDrain(timeout time.Duration) bool {
+	now := time.Now()
+	state := &drainState{
+		startTime: now,
+		done:      make(chan struct{}),
+	}
+	if timeout > 0 {
+		state.deadline = now.Add(timeout)
+	}
+	if !atomic.CompareAndSwapPointer(&s.drainState, nil, unsafe.Pointer(state)) {
+		return false
+	}
+	logutil.BgLogger().Info("[server] start draining", zap.Duration("timeout", timeout))
+	metrics.ServerEventCounter.WithLabelValues(metrics.EventDrain).Inc()
+	go s.drain(state)
+	return true
+}
+
+// WaitDrained waits until all the connections are closed by the draining.
+func (s *Server) WaitDrained() {
+	if state := s.getDrainState(); state != nil {
+		<-state.done
+	}
+}
+
+func (s *Server) drain(state *drainState) {
+	defer close(state.done)
+	ticker := time.NewTicker(drainCheckInterval)
+	defer ticker.Stop()
+	for i := 0; ; i++ {
+		s.kickIdleConnection()
+		count := s.ConnectionCount()
+		if count == 0 {
+			logutil.BgLogger().Info("[server] drain finished", zap.Duration("duration", time.Since(state.startTime)))
+			return
+		}
+		if !state.deadline.IsZero() && !time.Now().Before(state.deadline) {
+			logutil.BgLogger().Warn("[server] drain timeout, kill the remaining connections", zap.Int("conn count", count))
+			s.KillAllConnections()
+			return
+		}
+		// Print information for every 30 checks.
+		if i%30 == 0 {
+			logutil.BgLogger().Info("[server] draining...", zap.Int("conn count", count))
+		}
+		<-ticker.C
+	}
+}
+
+// DrainStatus returns the progress of draining the server.
+func (s *Server) DrainStatus() DrainStatus {
+	var status DrainStatus
+	state := s.getDrainState()
+	if state == nil {
+		return status
+	}
+	status.Draining = true
+	select {
+	case <-state.done:
+		status.Finished = true
+	default:
+	}
+	startTime := state.startTime
+	status.StartTime = &startTime
+	if !state.deadline.IsZero() {
+		deadline := state.deadline
+		status.Deadline = &deadline
+	}
+
+	var oldest *txninfo.TxnInfo
+	s.rwlock.RLock()
+	status.RemainingConnections = len(s.clients)
+	for _, client := range s.clients {
+		if client.ctx == nil || client.ctx.Session == nil {
+			continue
+		}
+		info := client.ctx.Session.TxnInfo()
+		if info == nil {
+			continue
+		}
+		status.InTxnConnections++
+		if oldest == nil || info.StartTS < oldest.StartTS {
+			oldest = info
+		}
+	}
+	s.rwlock.RUnlock()
+	if oldest != nil {
+		oldestStartTime := oracle.GetTimeFromTS(oldest.StartTS)
+		status.OldestTxnConnID = oldest.ConnectionID
+		status.OldestTxnStartTime = &oldestStartTime
+	}
+	return status
+}
@@ -0,0 +1,163 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func runDrainTestServer(t *testing.T, store kv.Storage) (*Server, *testServerClient) {
+	cfg := newTestConfig()
+	cfg.Port = 0
+	cfg.Socket = ""
+	cfg.Status.StatusPort = 0
+	cfg.Status.ReportStatus = true
+	srv, err := NewServer(cfg, NewTiDBDriver(store))
+	require.NoError(t, err)
+	cli := newTestServerClient()
+	cli.port = getPortFromTCPAddr(srv.listener.Addr())
+	cli.statusPort = getPortFromTCPAddr(srv.statusListener.Addr())
+	go func() {
+		err := srv.Run()
+		require.NoError(t, err)
+	}()
+	return srv, cli
+}
+
+func connectDrainTestServer(t *testing.T, cli *testServerClient) (*sql.Conn, error) {
+	db, err := sql.Open("mysql", cli.getDSN())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+	return db.Conn(context.Background())
+}
+
+func TestDrain(t *testing.T) {
+	origInterval := drainCheckInterval
+	drainCheckInterval = 10 * time.Millisecond
+	defer func() {
+		drainCheckInterval = origInterval
+	}()
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	srv, cli := runDrainTestServer(t, store)
+	defer srv.Close()
+
+	ctx := context.Background()
+	idleConn, err := connectDrainTestServer(t, cli)
+	require.NoError(t, err)
+	txnConn, err := connectDrainTestServer(t, cli)
+	require.NoError(t, err)
+	_, err = txnConn.ExecContext(ctx, "create table t(a int)")
+	require.NoError(t, err)
+	_, err = txnConn.ExecContext(ctx, "begin")
+	require.NoError(t, err)
+	_, err = txnConn.ExecContext(ctx, "insert into t values (1)")
+	require.NoError(t, err)
+
+	resp, err := cli.fetchStatus("/drain")
+	require.NoError(t, err)
+	var drainStatus DrainStatus
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&drainStatus))
+	require.NoError(t, resp.Body.Close())
+	require.False(t, drainStatus.Draining)
+
+	resp, err = cli.formStatus("/drain", url.Values{"timeout": {"60"}})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NoError(t, resp.Body.Close())
+	require.False(t, srv.StartDrain(time.Minute))
+
+	// The idle connection is closed, and the connection in the transaction is kept.
+	require.Eventually(t, func() bool {
+		return srv.ConnectionCount() == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	resp, err = cli.fetchStatus("/status")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	var st status
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&st))
+	require.NoError(t, resp.Body.Close())
+	require.True(t, st.Drain.Draining)
+	require.False(t, st.Drain.Finished)
+	require.Equal(t, 1, st.Drain.RemainingConnections)
+	require.Equal(t, 1, st.Drain.InTxnConnections)
+	require.NotNil(t, st.Drain.OldestTxnStartTime)
+	require.NotNil(t, st.Drain.Deadline)
+	_, err = idleConn.ExecContext(ctx, "select 1")
+	require.Error(t, err)
+
+	// The new connections are rejected.
+	_, err = connectDrainTestServer(t, cli)
+	require.Error(t, err)
+	mysqlErr, ok := err.(*mysql.MySQLError)
+	require.True(t, ok, err.Error())
+	require.Equal(t, uint16(errno.ErrServerDraining), mysqlErr.Number)
+
+	// The transaction can finish, then the connection is closed.
+	_, err = txnConn.ExecContext(ctx, "insert into t values (2)")
+	require.NoError(t, err)
+	_, err = txnConn.ExecContext(ctx, "commit")
+	require.NoError(t, err)
+	srv.WaitDrained()
+	require.Equal(t, 0, srv.ConnectionCount())
+	drainStatus = srv.DrainStatus()
+	require.True(t, drainStatus.Finished)
+	require.Equal(t, 0, drainStatus.InTxnConnections)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustQuery("select * from test.t").Check(testkit.Rows("1", "2"))
+}
+
+func TestDrainTimeout(t *testing.T) {
+	origInterval := drainCheckInterval
+	drainCheckInterval = 10 * time.Millisecond
+	defer func() {
+		drainCheckInterval = origInterval
+	}()
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	srv, cli := runDrainTestServer(t, store)
+	defer srv.Close()
+
+	ctx := context.Background()
+	txnConn, err := connectDrainTestServer(t, cli)
+	require.NoError(t, err)
+	_, err = txnConn.ExecContext(ctx, "begin")
+	require.NoError(t, err)
+
+	// The connection in the transaction is killed after the timeout.
+	start := time.Now()
+	require.True(t, srv.StartDrain(200*time.Millisecond))
+	srv.WaitDrained()
+	require.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+	_, err = txnConn.ExecContext(ctx, "commit")
+	require.Error(t, err)
+	require.Eventually(t, func() bool {
+		return srv.ConnectionCount() == 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
@@ -27,6 +27,8 @@ import (
 	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/planner/core"
 	"github.com/pingcap/tidb/session"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/sessionstates"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
@@ -129,6 +131,7 @@ func (ts *TiDBStatement) StoreResultSet(rs ResultSet) {
 		terror.Call(ts.rs.Close)
 	}
 	ts.rs = rs
+	ts.ctx.resetCursorSpillActions()
 }
 
 // GetResultSet gets ResultSet associated this statement
@@ -196,6 +199,7 @@ func (qd *TiDBDriver) OpenCtx(connID uint64, capability uint32, collation uint8,
 		currentDB: dbname,
 		stmts:     make(map[int]*TiDBStatement),
 	}
+	se.SetSessionStatesHandler(tc)
 	return tc, nil
 }
 
@@ -290,10 +294,54 @@ func (tc *TiDBContext) Prepare(sql string) (statement PreparedStatement, columns
 	return
 }
 
+// EncodeSessionStates implements sessionctx.SessionStatesHandler EncodeSessionStates method.
+func (tc *TiDBContext) EncodeSessionStates(ctx context.Context, sctx sessionctx.Context, sessionStates *sessionstates.SessionStates) error {
+	for id, stmt := range tc.stmts {
+		// The cursor and the long data sent by COM_STMT_SEND_LONG_DATA are not migrated.
+		if stmt.rs != nil {
+			return sessionstates.ErrCannotMigrateSession.GenWithStackByArgs("prepared statement has an open cursor")
+		}
+		for _, param := range stmt.boundParams {
+			if param != nil {
+				return sessionstates.ErrCannotMigrateSession.GenWithStackByArgs("prepared statement has long data")
+			}
+		}
+		if info, ok := sessionStates.PreparedStmts[uint32(id)]; ok {
+			info.ParamTypes = stmt.paramsType
+		}
+	}
+	return nil
+}
+
+// DecodeSessionStates implements sessionctx.SessionStatesHandler DecodeSessionStates method.
+func (tc *TiDBContext) DecodeSessionStates(ctx context.Context, sctx sessionctx.Context, sessionStates *sessionstates.SessionStates) error {
+	// The statements prepared by the binary protocol are restored by the session, and they're referred by the client
+	// with the same IDs.
+	preparedStmts := tc.GetSessionVars().PreparedStmts
+	for id, info := range sessionStates.PreparedStmts {
+		if len(info.Name) > 0 {
+			continue
+		}
+		prepared, ok := preparedStmts[id].(*core.CachedPrepareStmt)
+		if !ok {
+			return errors.Errorf("invalid CachedPrepareStmt type")
+		}
+		paramCount := len(prepared.PreparedAst.Params)
+		tc.stmts[int(id)] = &TiDBStatement{
+			sql:         info.StmtText,
+			id:          id,
+			numParams:   paramCount,
+			boundParams: make([][]byte, paramCount),
+			paramsType:  info.ParamTypes,
+			ctx:         tc,
+		}
+	}
+	return nil
+}
+
 type tidbResultSet struct {
 	recordSet    sqlexec.RecordSet
 	columns      []*ColumnInfo
-	rows         []chunk.Row
 	closed       int32
 	preparedStmt *core.CachedPrepareStmt
 }
@@ -306,15 +354,13 @@ func (trs *tidbResultSet) Next(ctx context.Context, req *chunk.Chunk) error {
 	return trs.recordSet.Next(ctx, req)
 }
 
-func (trs *tidbResultSet) StoreFetchedRows(rows []chunk.Row) {
-	trs.rows = rows
-}
-
-func (trs *tidbResultSet) GetFetchedRows() []chunk.Row {
-	if trs.rows == nil {
-		trs.rows = make([]chunk.Row, 0, 1024)
+func (trs *tidbResultSet) FieldTypes() []*types.FieldType {
+	fields := trs.recordSet.Fields()
+	fieldTypes := make([]*types.FieldType, 0, len(fields))
+	for _, field := range fields {
+		fieldTypes = append(fieldTypes, &field.Column.FieldType)
 	}
-	return trs.rows
+	return fieldTypes
 }
 
 func (trs *tidbResultSet) Close() error {
@@ -326,13 +372,6 @@ func (trs *tidbResultSet) Close() error {
 	return err
 }
 
-// OnFetchReturned implements fetchNotifier#OnFetchReturned
-func (trs *tidbResultSet) OnFetchReturned() {
-	if cl, ok := trs.recordSet.(fetchNotifier); ok {
-		cl.OnFetchReturned()
-	}
-}
-
 func (trs *tidbResultSet) Columns() []*ColumnInfo {
 	if trs.columns != nil {
 		return trs.columns
@@ -43,6 +43,7 @@ import (
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
 	"github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/clustertls"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tidb/util/memory"
 	"github.com/pingcap/tidb/util/printer"
@@ -85,13 +86,12 @@ func (s *Server) listenStatusHTTPServer() error {
 	}
 
 	logutil.BgLogger().Info("for status and metrics report", zap.String("listening on addr", s.statusAddr))
-	clusterSecurity := s.cfg.Security.ClusterSecurity()
-	tlsConfig, err := clusterSecurity.ToTLSConfig()
+	clusterTLS, err := clustertls.GetManager(s.cfg.Security.ClusterSecurity())
 	if err != nil {
 		logutil.BgLogger().Error("invalid TLS config", zap.Error(err))
 		return errors.Trace(err)
 	}
-	tlsConfig = s.setCNChecker(tlsConfig)
+	tlsConfig := s.setCNChecker(clusterTLS.ServerTLSConfig())
 
 	if tlsConfig != nil {
 		// we need to manage TLS here for cmux to distinguish between HTTP and gRPC.
@@ -194,6 +194,7 @@ func (s *Server) startHTTPServer() {
 	router := mux.NewRouter()
 
 	router.HandleFunc("/status", s.handleStatus).Name("Status")
+	router.HandleFunc("/drain", s.handleDrain).Name("Drain")
 	// HTTP path for prometheus.
 	router.Handle("/metrics", promhttp.Handler()).Name("Metrics")
 
@@ -493,9 +494,10 @@ func (s *Server) setCNChecker(tlsConfig *tls.Config) *tls.Config {
 
 // status of TiDB.
 type status struct {
-	Connections int    `json:"connections"`
-	Version     string `json:"version"`
-	GitHash     string `json:"git_hash"`
+	Connections int          `json:"connections"`
+	Version     string       `json:"version"`
+	GitHash     string       `json:"git_hash"`
+	Drain       *DrainStatus `json:"drain,omitempty"`
 }
 
 func (s *Server) handleStatus(w http.ResponseWriter, req *http.Request) {
@@ -512,12 +514,42 @@ func (s *Server) handleStatus(w http.ResponseWriter, req *http.Request) {
 		Version:     mysql.ServerVersion,
 		GitHash:     versioninfo.TiDBGitHash,
 	}
+	// A draining server reports unhealthy, so that the load balancer stops sending new connections to it.
+	if s.isDraining() {
+		drain := s.DrainStatus()
+		st.Drain = &drain
+	}
 	js, err := json.Marshal(st)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		logutil.BgLogger().Error("encode json failed", zap.Error(err))
 		return
 	}
+	if st.Drain != nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
 	_, err = w.Write(js)
 	terror.Log(errors.Trace(err))
 }
+
+// handleDrain starts draining the server by POST, and reports the progress of draining by GET.
+// The timeout of draining is set by the optional "timeout" parameter in seconds, or graceful-drain-timeout.
+func (s *Server) handleDrain(w http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		timeout := s.cfg.GracefulDrainTimeout
+		if str := req.FormValue("timeout"); str != "" {
+			var err error
+			if timeout, err = strconv.Atoi(str); err != nil || timeout < 0 {
+				writeError(w, errors.Errorf("invalid timeout %s", str))
+				return
+			}
+		}
+		s.StartDrain(time.Duration(timeout) * time.Second)
+	default:
+		writeError(w, errors.Errorf("This api only support GET and POST method."))
+		return
+	}
+	writeData(w, s.DrainStatus())
+}
@@ -0,0 +1,165 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/metrics"
+	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+const (
+	listenerRejectTooManyConnections = "too_many_connections"
+	listenerRejectAccessDenied       = "access_denied"
+	listenerRejectDraining           = "draining"
+)
+
+var (
+	defaultListenerConnGauge  = metrics.ListenerConnGauge.WithLabelValues(config.DefListenerName)
+	defaultListenerTokenGauge = metrics.ListenerTokenGauge.WithLabelValues(config.DefListenerName)
+)
+
+// serverListener is an additional MySQL protocol listener configured by config.Listener.
+// The connections of the listener have their own connection and token budgets.
+// A nil *serverListener stands for the listener on host and port and the unix socket,
+// which are limited by max-server-connections and token-limit.
+type serverListener struct {
+	cfg               config.Listener
+	listener          net.Listener
+	concurrentLimiter *TokenLimiter
+	connGauge         prometheus.Gauge
+	tokenGauge        prometheus.Gauge
+	// connCount is the number of connections of the listener, it's protected by Server.rwlock.
+	connCount int
+}
+
+func newServerListener(cfg config.Listener, defaultHost string, tokenLimit uint, tcpProto string) (*serverListener, error) {
+	host := cfg.Host
+	if host == "" {
+		host = defaultHost
+	}
+	if cfg.TokenLimit > 0 {
+		tokenLimit = cfg.TokenLimit
+	}
+	addr := fmt.Sprintf("%s:%d", host, cfg.Port)
+	listener, err := net.Listen(tcpProto, addr)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	logutil.BgLogger().Info("server is running MySQL protocol", zap.String("listener", cfg.Name), zap.String("addr", addr))
+	return &serverListener{
+		cfg:               cfg,
+		listener:          listener,
+		concurrentLimiter: NewTokenLimiter(tokenLimit),
+		connGauge:         metrics.ListenerConnGauge.WithLabelValues(cfg.Name),
+		tokenGauge:        metrics.ListenerTokenGauge.WithLabelValues(cfg.Name),
+	}, nil
+}
+
+func (l *serverListener) name() string {
+	if l == nil {
+		return config.DefListenerName
+	}
+	return l.cfg.Name
+}
+
+func (l *serverListener) requireSecureTransport() bool {
+	return l != nil && l.cfg.RequireSecureTransport
+}
+
+func (l *serverListener) connectionGauge() prometheus.Gauge {
+	if l == nil {
+		return defaultListenerConnGauge
+	}
+	return l.connGauge
+}
+
+func (l *serverListener) concurrencyGauge() prometheus.Gauge {
+	if l == nil {
+		return defaultListenerTokenGauge
+	}
+	return l.tokenGauge
+}
+
+// listenerConnCount returns the number of connections of the listener, s.rwlock must be held.
+func (s *Server) listenerConnCount(l *serverListener) int {
+	if l != nil {
+		return l.connCount
+	}
+	conns := len(s.clients)
+	for _, l := range s.listeners {
+		conns -= l.connCount
+	}
+	return conns
+}
+
+// addClientWithoutLock adds the connection to the clients and returns the number of connections.
+func (s *Server) addClientWithoutLock(cc *clientConn) int {
+	s.clients[cc.connectionID] = cc
+	if cc.listener != nil {
+		cc.listener.connCount++
+	}
+	cc.listener.connectionGauge().Set(float64(s.listenerConnCount(cc.listener)))
+	return len(s.clients)
+}
+
+// removeClientWithoutLock removes the connection from the clients and returns the number of the remaining connections.
+func (s *Server) removeClientWithoutLock(cc *clientConn) int {
+	if _, ok := s.clients[cc.connectionID]; ok {
+		delete(s.clients, cc.connectionID)
+		if cc.listener != nil {
+			cc.listener.connCount--
+		}
+		cc.listener.connectionGauge().Set(float64(s.listenerConnCount(cc.listener)))
+	}
+	return len(s.clients)
+}
+
+// checkListenerAccess checks whether the authenticated user is allowed to connect through the listener.
+func (cc *clientConn) checkListenerAccess(host, hasPassword string) error {
+	l := cc.listener
+	if l == nil {
+		return nil
+	}
+	if len(l.cfg.AllowedUsers) > 0 {
+		allowed := false
+		for _, user := range l.cfg.AllowedUsers {
+			if user == cc.user {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			metrics.ListenerRejectedCounter.WithLabelValues(l.cfg.Name, listenerRejectAccessDenied).Inc()
+			return errAccessDenied.FastGenByArgs(cc.user, host, hasPassword)
+		}
+	}
+	if l.cfg.RequiredPrivilege != "" {
+		checker := privilege.GetPrivilegeManager(cc.ctx.Session)
+		activeRoles := cc.ctx.GetSessionVars().ActiveRoles
+		if checker != nil && !checker.RequestDynamicVerification(activeRoles, l.cfg.RequiredPrivilege, false) {
+			metrics.ListenerRejectedCounter.WithLabelValues(l.cfg.Name, listenerRejectAccessDenied).Inc()
+			return errSpecificAccessDenied.FastGenByArgs(l.cfg.RequiredPrivilege)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/testkit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerListener(t *testing.T) {
+	store, clean := testkit.CreateMockStore(t)
+	defer clean()
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("create user 'u1'@'%'")
+	tk.MustExec("create user 'u2'@'%'")
+	tk.MustExec("grant SERVICE_CONNECTION_ADMIN on *.* to 'u2'@'%'")
+
+	cfg := newTestConfig()
+	cfg.Port = 0
+	cfg.Socket = ""
+	cfg.Status.ReportStatus = false
+	cfg.MaxServerConnections = 1
+	cfg.Listeners = []config.Listener{{
+		Name:              "admin",
+		RequiredPrivilege: "SERVICE_CONNECTION_ADMIN",
+		TokenLimit:        1,
+		MaxConnections:    2,
+	}}
+	srv, err := NewServer(cfg, NewTiDBDriver(store))
+	require.NoError(t, err)
+	require.Len(t, srv.listeners, 1)
+	go func() {
+		err := srv.Run()
+		require.NoError(t, err)
+	}()
+	defer srv.Close()
+
+	ctx := context.Background()
+	connect := func(port uint, user string) (*sql.DB, error) {
+		cli := newTestServerClient()
+		cli.port = port
+		db, err := sql.Open("mysql", cli.getDSN(func(config *mysql.Config) {
+			config.User = user
+			config.DBName = ""
+		}))
+		require.NoError(t, err)
+		db.SetMaxOpenConns(1)
+		if err = db.PingContext(ctx); err != nil {
+			require.NoError(t, db.Close())
+			return nil, err
+		}
+		return db, nil
+	}
+	port := getPortFromTCPAddr(srv.listener.Addr())
+	adminPort := getPortFromTCPAddr(srv.listeners[0].listener.Addr())
+
+	// the connections of the admin listener are not counted in max-server-connections
+	conn1, err := connect(port, "u1")
+	require.NoError(t, err)
+	defer conn1.Close()
+	// the connection is closed before the handshake if there are too many connections
+	_, err = connect(port, "root")
+	require.Error(t, err)
+
+	// the users without the required privilege can't connect to the admin listener
+	_, err = connect(adminPort, "u1")
+	require.Error(t, err)
+	mysqlErr, ok := err.(*mysql.MySQLError)
+	require.True(t, ok, err.Error())
+	require.Equal(t, uint16(errno.ErrSpecificAccessDenied), mysqlErr.Number)
+	conn2, err := connect(adminPort, "u2")
+	require.NoError(t, err)
+	defer conn2.Close()
+	conn3, err := connect(adminPort, "root")
+	require.NoError(t, err)
+	defer conn3.Close()
+	_, err = connect(adminPort, "root")
+	require.Error(t, err)
+
+	srv.rwlock.RLock()
+	require.Equal(t, 1, srv.listenerConnCount(nil))
+	require.Equal(t, 2, srv.listenerConnCount(srv.listeners[0]))
+	srv.rwlock.RUnlock()
+
+	// the connections of the admin listener can be used as usual
+	var user string
+	require.NoError(t, conn3.QueryRowContext(ctx, "select current_user()").Scan(&user))
+	require.Equal(t, "root@%", user)
+	require.NoError(t, conn2.Close())
+	require.Eventually(t, func() bool {
+		srv.rwlock.RLock()
+		defer srv.rwlock.RUnlock()
+		return srv.listenerConnCount(srv.listeners[0]) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	conn4, err := connect(adminPort, "root")
+	require.NoError(t, err)
+	require.NoError(t, conn4.Close())
+}
@@ -37,9 +37,13 @@ package server
 
 import (
 	"bufio"
+	"bytes"
+	"compress/zlib"
 	"io"
+	"sync"
 	"time"
 
+	"github.com/klauspost/compress/zstd"
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tidb/metrics"
 	"github.com/pingcap/tidb/parser/mysql"
@@ -48,9 +52,37 @@ import (
 
 const defaultWriterSize = 16 * 1024
 
+const (
+	compressionNone = iota
+	compressionZlib
+	compressionZstd
+)
+
+// The algorithm names used in protocol_compression_algorithms and status variables.
+const (
+	compressionAlgorithmZlib         = "zlib"
+	compressionAlgorithmZstd         = "zstd"
+	compressionAlgorithmUncompressed = "uncompressed"
+)
+
+const (
+	// minCompressLength is the minimal payload length worth compressing, the same as MySQL.
+	minCompressLength = 50
+	// zlibCompressionLevel is the level of the zlib default compression.
+	zlibCompressionLevel = 6
+	// defaultZstdCompressionLevel is used when the client doesn't specify a valid zstd level.
+	defaultZstdCompressionLevel = 3
+	maxZstdCompressionLevel     = 22
+)
+
 var (
 	readPacketBytes  = metrics.PacketIOHistogram.WithLabelValues("read")
 	writePacketBytes = metrics.PacketIOHistogram.WithLabelValues("write")
+
+	readCompressedBytes    = metrics.PacketIOCompressionCounter.WithLabelValues("read", "compressed")
+	readUncompressedBytes  = metrics.PacketIOCompressionCounter.WithLabelValues("read", "uncompressed")
+	writeCompressedBytes   = metrics.PacketIOCompressionCounter.WithLabelValues("write", "compressed")
+	writeUncompressedBytes = metrics.PacketIOCompressionCounter.WithLabelValues("write", "uncompressed")
 )
 
 // packetIO is a helper to read and write data in packet format.
@@ -59,6 +91,21 @@ type packetIO struct {
 	bufWriter   *bufio.Writer
 	sequence    uint8
 	readTimeout time.Duration
+
+	// The following fields are used only when the protocol compression is enabled.
+	compressionAlgorithm int
+	compressionLevel     int
+	compressedSequence   uint8
+	zlibWriter           *zlib.Writer
+	zstdEncoder          *zstd.Encoder
+	// compressedReadBuf holds the decompressed data which haven't been read.
+	compressedReadBuf bytes.Buffer
+	// compressedWriteBuf holds the packets which haven't been compressed.
+	compressedWriteBuf bytes.Buffer
+	// compressedBytes and uncompressedBytes are the bytes after and before compression,
+	// they are used to calculate the compression ratio of the connection.
+	compressedBytes   uint64
+	uncompressedBytes uint64
 }
 
 func newPacketIO(bufReadConn *bufferedReadConn) *packetIO {
@@ -76,6 +123,129 @@ func (p *packetIO) setReadTimeout(timeout time.Duration) {
 	p.readTimeout = timeout
 }
 
+// setCompressionAlgorithm enables the compressed packet framing. zstdLevel is
+// ignored unless the algorithm is zstd.
+func (p *packetIO) setCompressionAlgorithm(algorithm int, zstdLevel int) error {
+	switch algorithm {
+	case compressionZlib:
+		p.compressionLevel = zlibCompressionLevel
+		p.zlibWriter = zlib.NewWriter(nil)
+	case compressionZstd:
+		if zstdLevel < 1 || zstdLevel > maxZstdCompressionLevel {
+			zstdLevel = defaultZstdCompressionLevel
+		}
+		encoder, err := getZstdEncoder(zstdLevel)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		p.compressionLevel = zstdLevel
+		p.zstdEncoder = encoder
+	}
+	p.compressionAlgorithm = algorithm
+	return nil
+}
+
+// compressionAlgorithmName returns the name of the compression algorithm, or empty if the compression is disabled.
+func (p *packetIO) compressionAlgorithmName() string {
+	switch p.compressionAlgorithm {
+	case compressionZlib:
+		return compressionAlgorithmZlib
+	case compressionZstd:
+		return compressionAlgorithmZstd
+	}
+	return ""
+}
+
+// compressionRatio returns the ratio of the uncompressed bytes to the compressed bytes.
+func (p *packetIO) compressionRatio() float64 {
+	if p.compressedBytes == 0 {
+		return 0
+	}
+	return float64(p.uncompressedBytes) / float64(p.compressedBytes)
+}
+
+func (p *packetIO) resetSequence() {
+	p.sequence = 0
+	p.compressedSequence = 0
+}
+
+// readFull reads exactly len(buf) bytes of the plain packets.
+func (p *packetIO) readFull(buf []byte) error {
+	if p.compressionAlgorithm == compressionNone {
+		_, err := io.ReadFull(p.bufReadConn, buf)
+		return errors.Trace(err)
+	}
+	for p.compressedReadBuf.Len() < len(buf) {
+		if err := p.readCompressedPacket(); err != nil {
+			return err
+		}
+	}
+	_, err := p.compressedReadBuf.Read(buf)
+	return errors.Trace(err)
+}
+
+// readCompressedPacket reads one compressed packet and appends the decompressed payload to compressedReadBuf.
+// A compressed packet has a 7-byte header: 3-byte compressed length, 1-byte sequence and 3-byte uncompressed length.
+// The uncompressed length is 0 if the payload isn't compressed.
+func (p *packetIO) readCompressedPacket() error {
+	var header [7]byte
+	if _, err := io.ReadFull(p.bufReadConn, header[:]); err != nil {
+		return errors.Trace(err)
+	}
+
+	sequence := header[3]
+	if sequence != p.compressedSequence {
+		return errInvalidSequence.GenWithStack("invalid compressed sequence %d != %d", sequence, p.compressedSequence)
+	}
+	p.compressedSequence++
+
+	length := int(uint32(header[0]) | uint32(header[1])<<8 | uint32(header[2])<<16)
+	uncompressedLength := int(uint32(header[4]) | uint32(header[5])<<8 | uint32(header[6])<<16)
+
+	data := make([]byte, length)
+	if _, err := io.ReadFull(p.bufReadConn, data); err != nil {
+		return errors.Trace(err)
+	}
+	if uncompressedLength == 0 {
+		p.compressedReadBuf.Write(data)
+		return nil
+	}
+
+	payload, err := p.decompress(data, uncompressedLength)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(payload) != uncompressedLength {
+		return errors.Trace(mysql.ErrMalformPacket)
+	}
+	p.compressedReadBuf.Write(payload)
+	p.compressedBytes += uint64(length)
+	p.uncompressedBytes += uint64(uncompressedLength)
+	readCompressedBytes.Add(float64(length))
+	readUncompressedBytes.Add(float64(uncompressedLength))
+	return nil
+}
+
+func (p *packetIO) decompress(data []byte, uncompressedLength int) ([]byte, error) {
+	if p.compressionAlgorithm == compressionZstd {
+		decoder, err := getZstdDecoder()
+		if err != nil {
+			return nil, err
+		}
+		return decoder.DecodeAll(data, make([]byte, 0, uncompressedLength))
+	}
+
+	r, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	payload := make([]byte, uncompressedLength)
+	if _, err = io.ReadFull(r, payload); err != nil {
+		return nil, err
+	}
+	return payload, r.Close()
+}
+
 func (p *packetIO) readOnePacket() ([]byte, error) {
 	var header [4]byte
 	if p.readTimeout > 0 {
@@ -83,16 +253,17 @@ func (p *packetIO) readOnePacket() ([]byte, error) {
 			return nil, err
 		}
 	}
-	if _, err := io.ReadFull(p.bufReadConn, header[:]); err != nil {
+	if err := p.readFull(header[:]); err != nil {
 		return nil, errors.Trace(err)
 	}
 
 	sequence := header[3]
-	if sequence != p.sequence {
+	// Like MySQL, the sequence of the packets inside the compressed packets isn't checked.
+	if p.compressionAlgorithm == compressionNone && sequence != p.sequence {
 		return nil, errInvalidSequence.GenWithStack("invalid sequence %d != %d", sequence, p.sequence)
 	}
 
-	p.sequence++
+	p.sequence = sequence + 1
 
 	length := int(uint32(header[0]) | uint32(header[1])<<8 | uint32(header[2])<<16)
 
@@ -102,7 +273,7 @@ func (p *packetIO) readOnePacket() ([]byte, error) {
 			return nil, err
 		}
 	}
-	if _, err := io.ReadFull(p.bufReadConn, data); err != nil {
+	if err := p.readFull(data); err != nil {
 		return nil, errors.Trace(err)
 	}
 	return data, nil
@@ -147,6 +318,11 @@ func (p *packetIO) writePacket(data []byte) error {
 	length := len(data) - 4
 	writePacketBytes.Observe(float64(len(data)))
 
+	var w io.Writer = p.bufWriter
+	if p.compressionAlgorithm != compressionNone {
+		w = &p.compressedWriteBuf
+	}
+
 	for length >= mysql.MaxPayloadLen {
 		data[0] = 0xff
 		data[1] = 0xff
@@ -154,7 +330,7 @@ func (p *packetIO) writePacket(data []byte) error {
 
 		data[3] = p.sequence
 
-		if n, err := p.bufWriter.Write(data[:4+mysql.MaxPayloadLen]); err != nil {
+		if n, err := w.Write(data[:4+mysql.MaxPayloadLen]); err != nil {
 			return errors.Trace(mysql.ErrBadConn)
 		} else if n != (4 + mysql.MaxPayloadLen) {
 			return errors.Trace(mysql.ErrBadConn)
@@ -170,21 +346,133 @@ func (p *packetIO) writePacket(data []byte) error {
 	data[2] = byte(length >> 16)
 	data[3] = p.sequence
 
-	if n, err := p.bufWriter.Write(data); err != nil {
+	if n, err := w.Write(data); err != nil {
 		terror.Log(errors.Trace(err))
 		return errors.Trace(mysql.ErrBadConn)
 	} else if n != len(data) {
 		return errors.Trace(mysql.ErrBadConn)
 	} else {
 		p.sequence++
-		return nil
 	}
+
+	// Compress the pending packets in batches to bound the memory usage of large result sets.
+	if p.compressedWriteBuf.Len() >= defaultWriterSize {
+		return p.writeCompressedPackets()
+	}
+	return nil
+}
+
+// writeCompressedPackets compresses the pending packets and writes them to bufWriter.
+func (p *packetIO) writeCompressedPackets() error {
+	for p.compressedWriteBuf.Len() > 0 {
+		if err := p.writeCompressedPacket(p.compressedWriteBuf.Next(mysql.MaxPayloadLen)); err != nil {
+			return err
+		}
+	}
+	p.compressedWriteBuf.Reset()
+	return nil
+}
+
+func (p *packetIO) writeCompressedPacket(payload []byte) error {
+	data, uncompressedLength := payload, 0
+	// Small or incompressible payload is sent as is, with the uncompressed length 0.
+	if len(payload) >= minCompressLength {
+		compressed, err := p.compress(payload)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if len(compressed) < len(payload) {
+			data, uncompressedLength = compressed, len(payload)
+			p.compressedBytes += uint64(len(compressed))
+			p.uncompressedBytes += uint64(len(payload))
+			writeCompressedBytes.Add(float64(len(compressed)))
+			writeUncompressedBytes.Add(float64(len(payload)))
+		}
+	}
+
+	length := len(data)
+	header := [7]byte{
+		byte(length), byte(length >> 8), byte(length >> 16),
+		p.compressedSequence,
+		byte(uncompressedLength), byte(uncompressedLength >> 8), byte(uncompressedLength >> 16),
+	}
+	if _, err := p.bufWriter.Write(header[:]); err != nil {
+		terror.Log(errors.Trace(err))
+		return errors.Trace(mysql.ErrBadConn)
+	}
+	if _, err := p.bufWriter.Write(data); err != nil {
+		terror.Log(errors.Trace(err))
+		return errors.Trace(mysql.ErrBadConn)
+	}
+	p.compressedSequence++
+	return nil
+}
+
+func (p *packetIO) compress(payload []byte) ([]byte, error) {
+	if p.compressionAlgorithm == compressionZstd {
+		return p.zstdEncoder.EncodeAll(payload, nil), nil
+	}
+
+	var buf bytes.Buffer
+	p.zlibWriter.Reset(&buf)
+	if _, err := p.zlibWriter.Write(payload); err != nil {
+		return nil, err
+	}
+	if err := p.zlibWriter.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
 }
 
 func (p *packetIO) flush() error {
+	if p.compressionAlgorithm != compressionNone {
+		if err := p.writeCompressedPackets(); err != nil {
+			return err
+		}
+		// Like MySQL, the following packets continue with the compressed sequence.
+		p.sequence = p.compressedSequence
+	}
 	err := p.bufWriter.Flush()
 	if err != nil {
 		return errors.Trace(err)
 	}
 	return err
 }
+
+var zstdCodec struct {
+	sync.Mutex
+	encoders map[int]*zstd.Encoder
+	decoder  *zstd.Decoder
+}
+
+// getZstdEncoder returns the encoder of the level, which is shared by all connections.
+func getZstdEncoder(level int) (*zstd.Encoder, error) {
+	zstdCodec.Lock()
+	defer zstdCodec.Unlock()
+	if encoder, ok := zstdCodec.encoders[level]; ok {
+		return encoder, nil
+	}
+	encoder, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(level)))
+	if err != nil {
+		return nil, err
+	}
+	if zstdCodec.encoders == nil {
+		zstdCodec.encoders = make(map[int]*zstd.Encoder)
+	}
+	zstdCodec.encoders[level] = encoder
+	return encoder, nil
+}
+
+// getZstdDecoder returns the decoder shared by all connections.
+func getZstdDecoder() (*zstd.Decoder, error) {
+	zstdCodec.Lock()
+	defer zstdCodec.Unlock()
+	if zstdCodec.decoder == nil {
+		decoder, err := zstd.NewReader(nil, zstd.WithDecoderMaxMemory(mysql.MaxPayloadLen))
+		if err != nil {
+			return nil, err
+		}
+		zstdCodec.decoder = decoder
+	}
+	return zstdCodec.decoder, nil
+}
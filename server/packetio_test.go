@@ -90,6 +90,55 @@ func TestPacketIORead(t *testing.T) {
 	require.Equal(t, byte(0x0a), bytes[mysql.MaxPayloadLen])
 }
 
+func TestPacketIOCompression(t *testing.T) {
+	t.Parallel()
+
+	small := []byte("select 1")
+	large := bytes.Repeat([]byte("compressible payload "), 2*defaultWriterSize)
+	for _, algorithm := range []int{compressionZlib, compressionZstd} {
+		var outBuffer bytes.Buffer
+		writer := &packetIO{bufWriter: bufio.NewWriter(&outBuffer)}
+		require.NoError(t, writer.setCompressionAlgorithm(algorithm, 0))
+		require.NoError(t, writer.writePacket(append(make([]byte, 4), small...)))
+		require.NoError(t, writer.flush())
+		require.NoError(t, writer.writePacket(append(make([]byte, 4), large...)))
+		require.NoError(t, writer.flush())
+		require.Equal(t, uint8(2), writer.sequence)
+		require.Greater(t, writer.compressionRatio(), 1.0)
+
+		// The small packet is sent uncompressed inside the compressed packet.
+		res := outBuffer.Bytes()
+		require.Equal(t, []byte{byte(4 + len(small)), 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, res[:7])
+		require.Equal(t, []byte{byte(len(small)), 0x00, 0x00, 0x00}, res[7:11])
+
+		reader := newPacketIO(newBufferedReadConn(&bytesConn{outBuffer}))
+		require.NoError(t, reader.setCompressionAlgorithm(algorithm, 0))
+		data, err := reader.readPacket()
+		require.NoError(t, err)
+		require.Equal(t, small, data)
+		data, err = reader.readPacket()
+		require.NoError(t, err)
+		require.Equal(t, large, data)
+		require.Equal(t, writer.compressedSequence, reader.compressedSequence)
+		require.Equal(t, uint8(2), reader.sequence)
+		require.Greater(t, reader.compressionRatio(), 1.0)
+
+		// The compressed sequence is checked.
+		reader = newPacketIO(newBufferedReadConn(&bytesConn{*bytes.NewBuffer(res)}))
+		require.NoError(t, reader.setCompressionAlgorithm(algorithm, 0))
+		reader.compressedSequence = 1
+		_, err = reader.readPacket()
+		require.Error(t, err)
+	}
+
+	writer := &packetIO{bufWriter: bufio.NewWriter(&bytes.Buffer{})}
+	require.NoError(t, writer.setCompressionAlgorithm(compressionZstd, 100))
+	require.Equal(t, defaultZstdCompressionLevel, writer.compressionLevel)
+	require.Equal(t, compressionAlgorithmZstd, writer.compressionAlgorithmName())
+	require.NoError(t, writer.setCompressionAlgorithm(compressionZstd, 9))
+	require.Equal(t, 9, writer.compressionLevel)
+}
+
 type bytesConn struct {
 	b bytes.Buffer
 }
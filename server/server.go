@@ -92,16 +92,22 @@ func init() {
 }
 
 var (
-	errUnknownFieldType        = dbterror.ClassServer.NewStd(errno.ErrUnknownFieldType)
-	errInvalidSequence         = dbterror.ClassServer.NewStd(errno.ErrInvalidSequence)
-	errInvalidType             = dbterror.ClassServer.NewStd(errno.ErrInvalidType)
-	errNotAllowedCommand       = dbterror.ClassServer.NewStd(errno.ErrNotAllowedCommand)
-	errAccessDenied            = dbterror.ClassServer.NewStd(errno.ErrAccessDenied)
-	errAccessDeniedNoPassword  = dbterror.ClassServer.NewStd(errno.ErrAccessDeniedNoPassword)
-	errConCount                = dbterror.ClassServer.NewStd(errno.ErrConCount)
-	errSecureTransportRequired = dbterror.ClassServer.NewStd(errno.ErrSecureTransportRequired)
-	errMultiStatementDisabled  = dbterror.ClassServer.NewStd(errno.ErrMultiStatementDisabled)
-	errNewAbortingConnection   = dbterror.ClassServer.NewStd(errno.ErrNewAbortingConnection)
+	errUnknownFieldType               = dbterror.ClassServer.NewStd(errno.ErrUnknownFieldType)
+	errInvalidSequence                = dbterror.ClassServer.NewStd(errno.ErrInvalidSequence)
+	errInvalidType                    = dbterror.ClassServer.NewStd(errno.ErrInvalidType)
+	errNotAllowedCommand              = dbterror.ClassServer.NewStd(errno.ErrNotAllowedCommand)
+	errAccessDenied                   = dbterror.ClassServer.NewStd(errno.ErrAccessDenied)
+	errAccessDeniedNoPassword         = dbterror.ClassServer.NewStd(errno.ErrAccessDeniedNoPassword)
+	errConCount                       = dbterror.ClassServer.NewStd(errno.ErrConCount)
+	errSecureTransportRequired        = dbterror.ClassServer.NewStd(errno.ErrSecureTransportRequired)
+	errMultiStatementDisabled         = dbterror.ClassServer.NewStd(errno.ErrMultiStatementDisabled)
+	errNewAbortingConnection          = dbterror.ClassServer.NewStd(errno.ErrNewAbortingConnection)
+	errMustChangePasswordLogin        = dbterror.ClassServer.NewStd(errno.ErrMustChangePasswordLogin)
+	errCompressionAlgorithmNotAllowed = dbterror.ClassServer.NewStd(errno.ErrCompressionAlgorithmNotAllowed)
+	errSpecificAccessDenied           = dbterror.ClassServer.NewStd(errno.ErrSpecificAccessDenied)
+	errMasterFatalReadingBinlog       = dbterror.ClassServer.NewStd(errno.ErrMasterFatalErrorReadingBinlog)
+	errServerDraining                 = dbterror.ClassServer.NewStd(errno.ErrServerDraining)
+	errPluginIsNotLoaded              = dbterror.ClassServer.NewStd(errno.ErrPluginIsNotLoaded)
 )
 
 // DefaultCapability is the capability of the server when it is created using the default configuration.
@@ -110,7 +116,9 @@ const defaultCapability = mysql.ClientLongPassword | mysql.ClientLongFlag |
 	mysql.ClientConnectWithDB | mysql.ClientProtocol41 |
 	mysql.ClientTransactions | mysql.ClientSecureConnection | mysql.ClientFoundRows |
 	mysql.ClientMultiStatements | mysql.ClientMultiResults | mysql.ClientLocalFiles |
-	mysql.ClientConnectAtts | mysql.ClientPluginAuth | mysql.ClientInteractive
+	mysql.ClientConnectAtts | mysql.ClientPluginAuth | mysql.ClientInteractive |
+	mysql.ClientCanHandleExpiredPasswords | mysql.ClientCompress | mysql.ClientZstdCompressionAlgorithm |
+	mysql.ClientQueryAttributes
 
 // Server is the MySQL protocol server
 type Server struct {
@@ -119,6 +127,7 @@ type Server struct {
 	driver            IDriver
 	listener          net.Listener
 	socket            net.Listener
+	listeners         []*serverListener
 	rwlock            sync.RWMutex
 	concurrentLimiter *TokenLimiter
 	clients           map[uint64]*clientConn
@@ -131,6 +140,7 @@ type Server struct {
 	statusServer   *http.Server
 	grpcServer     *grpc.Server
 	inShutdownMode bool
+	drainState     unsafe.Pointer // *drainState
 }
 
 // ConnectionCount gets current connection count.
@@ -141,18 +151,28 @@ func (s *Server) ConnectionCount() int {
 	return cnt
 }
 
-func (s *Server) getToken() *Token {
+func (s *Server) getToken(l *serverListener) *Token {
 	start := time.Now()
-	tok := s.concurrentLimiter.Get()
+	limiter := s.concurrentLimiter
+	if l != nil {
+		limiter = l.concurrentLimiter
+	}
+	tok := limiter.Get()
 	metrics.TokenGauge.Inc()
+	l.concurrencyGauge().Inc()
 	// Note that data smaller than one microsecond is ignored, because that case can be viewed as non-block.
 	metrics.GetTokenDurationHistogram.Observe(float64(time.Since(start).Nanoseconds() / 1e3))
 	return tok
 }
 
-func (s *Server) releaseToken(token *Token) {
-	s.concurrentLimiter.Put(token)
+func (s *Server) releaseToken(l *serverListener, token *Token) {
+	if l != nil {
+		l.concurrentLimiter.Put(token)
+	} else {
+		s.concurrentLimiter.Put(token)
+	}
 	metrics.TokenGauge.Dec()
+	l.concurrencyGauge().Dec()
 }
 
 // SetDomain use to set the server domain.
@@ -287,6 +307,21 @@ func NewServer(cfg *config.Config, driver IDriver) (*Server, error) {
 		}
 	}
 
+	for _, cfg := range s.cfg.Listeners {
+		if cfg.RequireSecureTransport && s.tlsConfig == nil {
+			return nil, errSecureTransportRequired.FastGenByArgs()
+		}
+		tcpProto := "tcp"
+		if s.cfg.EnableTCP4Only {
+			tcpProto = "tcp4"
+		}
+		l, err := newServerListener(cfg, s.cfg.Host, s.cfg.TokenLimit, tcpProto)
+		if err != nil {
+			return nil, err
+		}
+		s.listeners = append(s.listeners, l)
+	}
+
 	if s.cfg.Status.ReportStatus {
 		err = s.listenStatusHTTPServer()
 		if err != nil {
@@ -364,17 +399,24 @@ func (s *Server) Run() error {
 	}
 	// If error should be reported and exit the server it can be sent on this
 	// channel. Otherwise end with sending a nil error to signal "done"
-	errChan := make(chan error)
-	go s.startNetworkListener(s.listener, false, errChan)
-	go s.startNetworkListener(s.socket, true, errChan)
-	err := <-errChan
-	if err != nil {
-		return err
+	// It's buffered for all the listeners, so the ones still running don't block
+	// after Run returns on the first error.
+	listenerCount := len(s.listeners) + 2
+	errChan := make(chan error, listenerCount)
+	go s.startNetworkListener(s.listener, false, nil, errChan)
+	go s.startNetworkListener(s.socket, true, nil, errChan)
+	for _, l := range s.listeners {
+		go s.startNetworkListener(l.listener, false, l, errChan)
 	}
-	return <-errChan
+	for i := 0; i < listenerCount; i++ {
+		if err := <-errChan; err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-func (s *Server) startNetworkListener(listener net.Listener, isUnixSocket bool, errChan chan error) {
+func (s *Server) startNetworkListener(listener net.Listener, isUnixSocket bool, sl *serverListener, errChan chan error) {
 	if listener == nil {
 		errChan <- nil
 		return
@@ -405,6 +447,7 @@ func (s *Server) startNetworkListener(listener net.Listener, isUnixSocket bool,
 		}
 
 		clientConn := s.newConn(conn)
+		clientConn.listener = sl
 		if isUnixSocket {
 
 			uc, ok := conn.(*net.UnixConn)
@@ -484,6 +527,10 @@ func (s *Server) Close() {
 		terror.Log(errors.Trace(err))
 		s.socket = nil
 	}
+	for _, l := range s.listeners {
+		terror.Log(errors.Trace(l.listener.Close()))
+	}
+	s.listeners = nil
 	if s.statusServer != nil {
 		err := s.statusServer.Close()
 		terror.Log(errors.Trace(err))
@@ -526,8 +573,7 @@ func (s *Server) onConn(conn *clientConn) {
 		logutil.Logger(ctx).Debug("connection closed")
 	}()
 	s.rwlock.Lock()
-	s.clients[conn.connectionID] = conn
-	connections := len(s.clients)
+	connections := s.addClientWithoutLock(conn)
 	s.rwlock.Unlock()
 	metrics.ConnGauge.Set(float64(connections))
 
@@ -583,7 +629,7 @@ func (cc *clientConn) connectInfo() *variable.ConnectionInfo {
 		ClientIP:          cc.peerHost,
 		ClientPort:        cc.peerPort,
 		ServerID:          1,
-		ServerPort:        int(cc.server.cfg.Port),
+		ServerPort:        cc.serverPort(),
 		User:              cc.user,
 		ServerOSLoginUser: osUser,
 		OSVersion:         osVersion,
@@ -591,23 +637,36 @@ func (cc *clientConn) connectInfo() *variable.ConnectionInfo {
 		SSLVersion:        "v1.2.0", // for current go version
 		PID:               serverPID,
 		DB:                cc.dbname,
+		Attributes:        cc.attrs,
 	}
 	return connInfo
 }
 
-func (s *Server) checkConnectionCount() error {
+func (cc *clientConn) serverPort() int {
+	if cc.listener != nil {
+		return int(cc.listener.cfg.Port)
+	}
+	return int(cc.server.cfg.Port)
+}
+
+func (s *Server) checkConnectionCount(l *serverListener) error {
+	maxConns := s.cfg.MaxServerConnections
+	if l != nil {
+		maxConns = l.cfg.MaxConnections
+	}
 	// When the value of MaxServerConnections is 0, the number of connections is unlimited.
-	if int(s.cfg.MaxServerConnections) == 0 {
+	if int(maxConns) == 0 {
 		return nil
 	}
 
 	s.rwlock.RLock()
-	conns := len(s.clients)
+	conns := s.listenerConnCount(l)
 	s.rwlock.RUnlock()
 
-	if conns >= int(s.cfg.MaxServerConnections) {
-		logutil.BgLogger().Error("too many connections",
-			zap.Uint32("max connections", s.cfg.MaxServerConnections), zap.Error(errConCount))
+	if conns >= int(maxConns) {
+		logutil.BgLogger().Error("too many connections", zap.String("listener", l.name()),
+			zap.Uint32("max connections", maxConns), zap.Error(errConCount))
+		metrics.ListenerRejectedCounter.WithLabelValues(l.name(), listenerRejectTooManyConnections).Inc()
 		return errConCount
 	}
 	return nil
@@ -755,8 +814,17 @@ func (s *Server) GracefulDown(ctx context.Context, done chan struct{}) {
 
 func (s *Server) kickIdleConnection() {
 	var conns []*clientConn
+	draining := s.isDraining()
 	s.rwlock.RLock()
 	for _, cc := range s.clients {
+		if draining {
+			// The draining conn tells the client that the server is draining so that it can reconnect to another
+			// server, and then closes itself. Its read is interrupted here instead of writing from this goroutine.
+			if cc.notifyDraining() {
+				conns = append(conns, cc)
+			}
+			continue
+		}
 		if cc.ShutdownOrNotify() {
 			// Shutdowned conn will be closed by us, and notified conn will exist themselves.
 			conns = append(conns, cc)
@@ -765,6 +833,12 @@ func (s *Server) kickIdleConnection() {
 	s.rwlock.RUnlock()
 
 	for _, cc := range conns {
+		if draining {
+			// The conn may reset the read deadline just after being notified, so it's interrupted again on every
+			// call until it exits.
+			terror.Log(cc.bufReadConn.SetReadDeadline(time.Now()))
+			continue
+		}
 		err := cc.Close()
 		if err != nil {
 			logutil.BgLogger().Error("close connection", zap.Error(err))
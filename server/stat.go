@@ -16,6 +16,7 @@ package server
 
 import (
 	"crypto/x509"
+	"strconv"
 
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/util/logutil"
@@ -23,13 +24,21 @@ import (
 )
 
 var (
-	serverNotAfter  = "Ssl_server_not_after"
-	serverNotBefore = "Ssl_server_not_before"
+	serverNotAfter       = "Ssl_server_not_after"
+	serverNotBefore      = "Ssl_server_not_before"
+	compression          = "Compression"
+	compressionAlgorithm = "Compression_algorithm"
+	compressionLevel     = "Compression_level"
+	compressionRatio     = "Compression_ratio"
 )
 
 var defaultStatus = map[string]*variable.StatusVal{
-	serverNotAfter:  {Scope: variable.ScopeGlobal | variable.ScopeSession, Value: ""},
-	serverNotBefore: {Scope: variable.ScopeGlobal | variable.ScopeSession, Value: ""},
+	serverNotAfter:       {Scope: variable.ScopeGlobal | variable.ScopeSession, Value: ""},
+	serverNotBefore:      {Scope: variable.ScopeGlobal | variable.ScopeSession, Value: ""},
+	compression:          {Scope: variable.ScopeSession, Value: variable.Off},
+	compressionAlgorithm: {Scope: variable.ScopeSession, Value: ""},
+	compressionLevel:     {Scope: variable.ScopeSession, Value: "0"},
+	compressionRatio:     {Scope: variable.ScopeSession, Value: "0"},
 }
 
 // GetScope gets the status variables scope.
@@ -57,5 +66,17 @@ func (s *Server) Stats(vars *variable.SessionVars) (map[string]interface{}, erro
 			}
 		}
 	}
+
+	if vars != nil {
+		s.rwlock.RLock()
+		cc, ok := s.clients[vars.ConnectionID]
+		s.rwlock.RUnlock()
+		if ok && cc.pkt != nil && cc.pkt.compressionAlgorithm != compressionNone {
+			m[compression] = variable.On
+			m[compressionAlgorithm] = cc.pkt.compressionAlgorithmName()
+			m[compressionLevel] = strconv.Itoa(cc.pkt.compressionLevel)
+			m[compressionRatio] = strconv.FormatFloat(cc.pkt.compressionRatio(), 'f', 2, 64)
+		}
+	}
 	return m, nil
 }
@@ -100,6 +100,22 @@ func parseLengthEncodedInt(b []byte) (num uint64, isNull bool, n int) {
 	return
 }
 
+// isLengthEncodedIntComplete checks whether b begins with a complete length-encoded integer.
+func isLengthEncodedIntComplete(b []byte) bool {
+	if len(b) == 0 {
+		return false
+	}
+	switch b[0] {
+	case 0xfc:
+		return len(b) >= 3
+	case 0xfd:
+		return len(b) >= 4
+	case 0xfe:
+		return len(b) >= 9
+	}
+	return true
+}
+
 func dumpLengthEncodedInt(buffer []byte, n uint64) []byte {
 	switch {
 	case n <= 250:
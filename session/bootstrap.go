@@ -91,6 +91,17 @@ const (
 		Create_Tablespace_Priv  ENUM('N','Y') NOT NULL DEFAULT 'N',
 		Repl_slave_priv	    	ENUM('N','Y') NOT NULL DEFAULT 'N',
 		Repl_client_priv		ENUM('N','Y') NOT NULL DEFAULT 'N',
+		Password_reuse_history	SMALLINT UNSIGNED DEFAULT NULL,
+		Password_reuse_time		SMALLINT UNSIGNED DEFAULT NULL,
+		User_attributes			JSON,
+		Password_expired		ENUM('N','Y') NOT NULL DEFAULT 'N',
+		Password_last_changed	TIMESTAMP DEFAULT CURRENT_TIMESTAMP(),
+		Password_lifetime		SMALLINT UNSIGNED DEFAULT NULL,
+		max_questions			INT UNSIGNED NOT NULL DEFAULT 0,
+		max_updates				INT UNSIGNED NOT NULL DEFAULT 0,
+		max_connections			INT UNSIGNED NOT NULL DEFAULT 0,
+		max_user_connections	INT UNSIGNED NOT NULL DEFAULT 0,
+		max_memory_quota		BIGINT UNSIGNED NOT NULL DEFAULT 0,
 		PRIMARY KEY (Host, User));`
 	// CreateGlobalPrivTable is the SQL statement creates Global scope privilege table in system db.
 	CreateGlobalPrivTable = "CREATE TABLE IF NOT EXISTS mysql.global_priv (" +
@@ -357,6 +368,28 @@ const (
 		last_analyzed_at TIMESTAMP,
 		PRIMARY KEY (table_id, column_id) CLUSTERED
 	);`
+	// CreatePasswordHistoryTable stores the previous passwords of the users, used by the password reuse policy.
+	CreatePasswordHistoryTable = `CREATE TABLE IF NOT EXISTS mysql.password_history (
+		Host				CHAR(255) NOT NULL DEFAULT '',
+		User				CHAR(32) NOT NULL DEFAULT '',
+		Password_timestamp	TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+		Password			TEXT,
+		PRIMARY KEY (Host, User, Password_timestamp)
+	);`
+	// CreateUserResourceUsageTable stores the resource usage of the accounts which have resource limits, every
+	// TiDB instance keeps its own row of an account, the usage of the account is the sum of all the rows.
+	// The hourly counters are only valid in the hour starting from Window_start, which is a unix time.
+	CreateUserResourceUsageTable = `CREATE TABLE IF NOT EXISTS mysql.user_resource_usage (
+		Host				CHAR(255) NOT NULL DEFAULT '',
+		User				CHAR(32) NOT NULL DEFAULT '',
+		Instance			VARCHAR(64) NOT NULL DEFAULT '',
+		Window_start		BIGINT UNSIGNED NOT NULL DEFAULT 0,
+		Questions			BIGINT UNSIGNED NOT NULL DEFAULT 0,
+		Updates				BIGINT UNSIGNED NOT NULL DEFAULT 0,
+		Connections			BIGINT UNSIGNED NOT NULL DEFAULT 0,
+		User_connections	BIGINT UNSIGNED NOT NULL DEFAULT 0,
+		PRIMARY KEY (Host, User, Instance)
+	);`
 )
 
 // bootstrap initiates system DB for a store.
@@ -528,11 +561,17 @@ const (
 	version77 = 77
 	// version78 updates mysql.stats_buckets.lower_bound, mysql.stats_buckets.upper_bound and mysql.stats_histograms.last_analyze_pos from BLOB to LONGBLOB.
 	version78 = 78
+	// version79 adds the password management columns to mysql.user and adds mysql.password_history table.
+	version79 = 79
+	// version80 adds the resource limit columns to mysql.user and adds mysql.user_resource_usage table.
+	version80 = 80
+	// version81 adds the max_memory_quota column to mysql.user.
+	version81 = 81
 )
 
 // currentBootstrapVersion is defined as a variable, so we can modify its value for testing.
 // please make sure this is the largest version
-var currentBootstrapVersion int64 = version78
+var currentBootstrapVersion int64 = version81
 
 var (
 	bootstrapVersion = []func(Session, int64){
@@ -614,6 +653,9 @@ var (
 		upgradeToVer76,
 		upgradeToVer77,
 		upgradeToVer78,
+		upgradeToVer79,
+		upgradeToVer80,
+		upgradeToVer81,
 	}
 )
 
@@ -1612,6 +1654,37 @@ func upgradeToVer78(s Session, ver int64) {
 	doReentrantDDL(s, "ALTER TABLE mysql.stats_histograms MODIFY last_analyze_pos LONGBLOB DEFAULT NULL")
 }
 
+func upgradeToVer79(s Session, ver int64) {
+	if ver >= version79 {
+		return
+	}
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `Password_reuse_history` SMALLINT UNSIGNED DEFAULT NULL AFTER `Repl_client_priv`", infoschema.ErrColumnExists)
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `Password_reuse_time` SMALLINT UNSIGNED DEFAULT NULL AFTER `Password_reuse_history`", infoschema.ErrColumnExists)
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `User_attributes` JSON AFTER `Password_reuse_time`", infoschema.ErrColumnExists)
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `Password_expired` ENUM('N','Y') NOT NULL DEFAULT 'N' AFTER `User_attributes`", infoschema.ErrColumnExists)
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `Password_last_changed` TIMESTAMP DEFAULT CURRENT_TIMESTAMP() AFTER `Password_expired`", infoschema.ErrColumnExists)
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `Password_lifetime` SMALLINT UNSIGNED DEFAULT NULL AFTER `Password_last_changed`", infoschema.ErrColumnExists)
+	doReentrantDDL(s, CreatePasswordHistoryTable)
+}
+
+func upgradeToVer80(s Session, ver int64) {
+	if ver >= version80 {
+		return
+	}
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `max_questions` INT UNSIGNED NOT NULL DEFAULT 0 AFTER `Password_lifetime`", infoschema.ErrColumnExists)
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `max_updates` INT UNSIGNED NOT NULL DEFAULT 0 AFTER `max_questions`", infoschema.ErrColumnExists)
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `max_connections` INT UNSIGNED NOT NULL DEFAULT 0 AFTER `max_updates`", infoschema.ErrColumnExists)
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `max_user_connections` INT UNSIGNED NOT NULL DEFAULT 0 AFTER `max_connections`", infoschema.ErrColumnExists)
+	doReentrantDDL(s, CreateUserResourceUsageTable)
+}
+
+func upgradeToVer81(s Session, ver int64) {
+	if ver >= version81 {
+		return
+	}
+	doReentrantDDL(s, "ALTER TABLE mysql.user ADD COLUMN `max_memory_quota` BIGINT UNSIGNED NOT NULL DEFAULT 0 AFTER `max_user_connections`", infoschema.ErrColumnExists)
+}
+
 func writeOOMAction(s Session) {
 	comment := "oom-action is `log` by default in v3.0.x, `cancel` by default in v4.0.11+"
 	mustExecute(s, `INSERT HIGH_PRIORITY INTO %n.%n VALUES (%?, %?, %?) ON DUPLICATE KEY UPDATE VARIABLE_VALUE= %?`,
@@ -1694,6 +1767,10 @@ func doDDLWorks(s Session) {
 	mustExecute(s, CreateCapturePlanBaselinesBlacklist)
 	// Create column_stats_usage table
 	mustExecute(s, CreateColumnStatsUsageTable)
+	// Create password_history table.
+	mustExecute(s, CreatePasswordHistoryTable)
+	// Create user_resource_usage table.
+	mustExecute(s, CreateUserResourceUsageTable)
 }
 
 // doDMLWorks executes DML statements in bootstrap stage.
@@ -1710,10 +1787,10 @@ func doDMLWorks(s Session) {
 			logutil.BgLogger().Fatal("failed to read current user. unable to secure bootstrap.", zap.Error(err))
 		}
 		mustExecute(s, `INSERT HIGH_PRIORITY INTO mysql.user VALUES
-		("localhost", "root", %?, "auth_socket", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "N", "Y", "Y", "Y", "Y", "Y", "Y", "Y")`, u.Username)
+		("localhost", "root", %?, "auth_socket", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "N", "Y", "Y", "Y", "Y", "Y", "Y", "Y", NULL, NULL, NULL, "N", CURRENT_TIMESTAMP(), NULL, 0, 0, 0, 0, 0)`, u.Username)
 	} else {
 		mustExecute(s, `INSERT HIGH_PRIORITY INTO mysql.user VALUES
-		("%", "root", "", "mysql_native_password", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "N", "Y", "Y", "Y", "Y", "Y", "Y", "Y")`)
+		("%", "root", "", "mysql_native_password", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "N", "Y", "Y", "Y", "Y", "Y", "Y", "Y", NULL, NULL, NULL, "N", CURRENT_TIMESTAMP(), NULL, 0, 0, 0, 0, 0)`)
 	}
 
 	// Init global system variables table.
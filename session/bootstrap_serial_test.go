@@ -49,7 +49,10 @@ func TestBootstrap(t *testing.T) {
 	require.NotEqual(t, 0, req.NumRows())
 
 	rows := statistics.RowToDatums(req.GetRow(0), r.Fields())
-	match(t, rows, `%`, "root", "", "mysql_native_password", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "N", "Y", "Y", "Y", "Y", "Y", "Y", "Y")
+	// Skip the Password_last_changed and Password_lifetime columns.
+	match(t, rows[:len(rows)-7], `%`, "root", "", "mysql_native_password", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "N", "Y", "Y", "Y", "Y", "Y", "Y", "Y", nil, nil, nil, "N")
+	require.True(t, rows[len(rows)-6].IsNull())
+	match(t, rows[len(rows)-5:], 0, 0, 0, 0, 0)
 
 	ok := se.Auth(&auth.UserIdentity{Username: "root", Hostname: "anyhost"}, []byte(""), []byte(""))
 	require.True(t, ok)
@@ -170,7 +173,10 @@ func TestBootstrapWithError(t *testing.T) {
 
 	row := req.GetRow(0)
 	rows := statistics.RowToDatums(row, r.Fields())
-	match(t, rows, `%`, "root", "", "mysql_native_password", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "N", "Y", "Y", "Y", "Y", "Y", "Y", "Y")
+	// Skip the Password_last_changed and Password_lifetime columns.
+	match(t, rows[:len(rows)-7], `%`, "root", "", "mysql_native_password", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "Y", "N", "Y", "Y", "Y", "Y", "Y", "Y", "Y", nil, nil, nil, "N")
+	require.True(t, rows[len(rows)-6].IsNull())
+	match(t, rows[len(rows)-5:], 0, 0, 0, 0, 0)
 	require.NoError(t, r.Close())
 
 	mustExec(t, se, "USE test")
@@ -827,6 +833,131 @@ func TestUpgradeVersion75(t *testing.T) {
 	require.Equal(t, "char(255)", strings.ToLower(row.GetString(1)))
 }
 
+func TestUpgradeVersion79(t *testing.T) {
+	ctx := context.Background()
+
+	store, _ := createStoreAndBootstrap(t)
+	defer func() { require.NoError(t, store.Close()) }()
+
+	seV78 := createSessionAndSetID(t, store)
+	txn, err := store.Begin()
+	require.NoError(t, err)
+	m := meta.NewMeta(txn)
+	err = m.FinishBootstrap(int64(78))
+	require.NoError(t, err)
+	err = txn.Commit(context.Background())
+	require.NoError(t, err)
+	mustExec(t, seV78, "update mysql.tidb set variable_value='78' where variable_name='tidb_server_version'")
+	mustExec(t, seV78, "commit")
+	for _, column := range []string{"Password_reuse_history", "Password_reuse_time", "User_attributes", "Password_expired", "Password_last_changed", "Password_lifetime"} {
+		mustExec(t, seV78, "ALTER TABLE mysql.user DROP COLUMN "+column)
+	}
+	mustExec(t, seV78, "DROP TABLE mysql.password_history")
+	unsetStoreBootstrapped(store.UUID())
+	ver, err := getBootstrapVersion(seV78)
+	require.NoError(t, err)
+	require.Equal(t, int64(78), ver)
+
+	domV79, err := BootstrapSession(store)
+	require.NoError(t, err)
+	defer domV79.Close()
+	seV79 := createSessionAndSetID(t, store)
+	ver, err = getBootstrapVersion(seV79)
+	require.NoError(t, err)
+	require.Equal(t, currentBootstrapVersion, ver)
+	r := mustExec(t, seV79, "SELECT Password_reuse_history, Password_reuse_time, User_attributes, Password_expired, Password_lifetime, Password_last_changed IS NOT NULL FROM mysql.user WHERE User = 'root'")
+	req := r.NewChunk(nil)
+	require.NoError(t, r.Next(ctx, req))
+	require.Equal(t, 1, req.NumRows())
+	row := req.GetRow(0)
+	require.True(t, row.IsNull(0))
+	require.True(t, row.IsNull(1))
+	require.True(t, row.IsNull(2))
+	require.Equal(t, "N", row.GetEnum(3).String())
+	require.True(t, row.IsNull(4))
+	require.Equal(t, int64(1), row.GetInt64(5))
+	require.NoError(t, r.Close())
+	mustExec(t, seV79, "SELECT * FROM mysql.password_history")
+}
+
+func TestUpgradeVersion80(t *testing.T) {
+	ctx := context.Background()
+
+	store, _ := createStoreAndBootstrap(t)
+	defer func() { require.NoError(t, store.Close()) }()
+
+	seV79 := createSessionAndSetID(t, store)
+	txn, err := store.Begin()
+	require.NoError(t, err)
+	m := meta.NewMeta(txn)
+	err = m.FinishBootstrap(int64(79))
+	require.NoError(t, err)
+	err = txn.Commit(context.Background())
+	require.NoError(t, err)
+	mustExec(t, seV79, "update mysql.tidb set variable_value='79' where variable_name='tidb_server_version'")
+	mustExec(t, seV79, "commit")
+	for _, column := range []string{"max_questions", "max_updates", "max_connections", "max_user_connections"} {
+		mustExec(t, seV79, "ALTER TABLE mysql.user DROP COLUMN "+column)
+	}
+	mustExec(t, seV79, "DROP TABLE mysql.user_resource_usage")
+	unsetStoreBootstrapped(store.UUID())
+	ver, err := getBootstrapVersion(seV79)
+	require.NoError(t, err)
+	require.Equal(t, int64(79), ver)
+
+	domV80, err := BootstrapSession(store)
+	require.NoError(t, err)
+	defer domV80.Close()
+	seV80 := createSessionAndSetID(t, store)
+	ver, err = getBootstrapVersion(seV80)
+	require.NoError(t, err)
+	require.Equal(t, currentBootstrapVersion, ver)
+	r := mustExec(t, seV80, "SELECT max_questions, max_updates, max_connections, max_user_connections FROM mysql.user WHERE User = 'root'")
+	req := r.NewChunk(nil)
+	require.NoError(t, r.Next(ctx, req))
+	require.Equal(t, 1, req.NumRows())
+	match(t, statistics.RowToDatums(req.GetRow(0), r.Fields()), 0, 0, 0, 0)
+	require.NoError(t, r.Close())
+	mustExec(t, seV80, "SELECT * FROM mysql.user_resource_usage")
+}
+
+func TestUpgradeVersion81(t *testing.T) {
+	ctx := context.Background()
+
+	store, _ := createStoreAndBootstrap(t)
+	defer func() { require.NoError(t, store.Close()) }()
+
+	seV80 := createSessionAndSetID(t, store)
+	txn, err := store.Begin()
+	require.NoError(t, err)
+	m := meta.NewMeta(txn)
+	err = m.FinishBootstrap(int64(80))
+	require.NoError(t, err)
+	err = txn.Commit(context.Background())
+	require.NoError(t, err)
+	mustExec(t, seV80, "update mysql.tidb set variable_value='80' where variable_name='tidb_server_version'")
+	mustExec(t, seV80, "commit")
+	mustExec(t, seV80, "ALTER TABLE mysql.user DROP COLUMN max_memory_quota")
+	unsetStoreBootstrapped(store.UUID())
+	ver, err := getBootstrapVersion(seV80)
+	require.NoError(t, err)
+	require.Equal(t, int64(80), ver)
+
+	domV81, err := BootstrapSession(store)
+	require.NoError(t, err)
+	defer domV81.Close()
+	seV81 := createSessionAndSetID(t, store)
+	ver, err = getBootstrapVersion(seV81)
+	require.NoError(t, err)
+	require.Equal(t, currentBootstrapVersion, ver)
+	r := mustExec(t, seV81, "SELECT max_memory_quota FROM mysql.user WHERE User = 'root'")
+	req := r.NewChunk(nil)
+	require.NoError(t, r.Next(ctx, req))
+	require.Equal(t, 1, req.NumRows())
+	match(t, statistics.RowToDatums(req.GetRow(0), r.Fields()), 0)
+	require.NoError(t, r.Close())
+}
+
 func TestForIssue23387(t *testing.T) {
 	// For issue https://github.com/pingcap/tidb/issues/23387
 	saveCurrentBootstrapVersion := currentBootstrapVersion
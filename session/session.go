@@ -85,6 +85,7 @@ import (
 	"github.com/pingcap/tidb/util/execdetails"
 	"github.com/pingcap/tidb/util/kvcache"
 	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/memory"
 	"github.com/pingcap/tidb/util/sli"
 	"github.com/pingcap/tidb/util/sqlexec"
 	"github.com/pingcap/tidb/util/tableutil"
@@ -144,8 +145,16 @@ type Session interface {
 	SetSessionManager(util.SessionManager)
 	Close()
 	Auth(user *auth.UserIdentity, auth []byte, salt []byte) bool
+	AuthWithError(user *auth.UserIdentity, auth []byte, salt []byte) error
 	AuthWithoutVerification(user *auth.UserIdentity) bool
 	AuthPluginForUser(user *auth.UserIdentity) (string, error)
+	// ConsumeConnectionResource counts the connection in the resource limits of the logged-in account.
+	ConsumeConnectionResource() error
+	// ConsumeStatementResource counts the statement in the resource limits of the logged-in account.
+	ConsumeStatementResource(stmt ast.StmtNode) error
+	// SetSessionStatesHandler sets the handler of the session states kept out of the session, such as
+	// the prepared statements of the binary protocol.
+	SetSessionStatesHandler(handler sessionctx.SessionStatesHandler)
 	ShowProcess() *util.ProcessInfo
 	// Return the information of the txn current running
 	TxnInfo() *txninfo.TxnInfo
@@ -228,6 +237,11 @@ type session struct {
 	builtinFunctionUsage telemetry.BuiltinFunctionsUsage
 	// allowed when tikv disk full happened.
 	diskFullOpt kvrpcpb.DiskFullOpt
+
+	// userConnectionCounted indicates the session is counted in the MAX_USER_CONNECTIONS limit of the user.
+	userConnectionCounted bool
+
+	sessionStatesHandler sessionctx.SessionStatesHandler
 }
 
 var parserPool = &sync.Pool{New: func() interface{} { return parser.New() }}
@@ -1226,6 +1240,7 @@ func (s *session) SetProcessInfo(sql string, t time.Time, command byte, maxExecu
 		StatsInfo:        plannercore.GetStatsInfo,
 		MaxExecutionTime: maxExecutionTime,
 		RedactSQL:        s.sessionVars.EnableRedactLog,
+		ConnectAttrs:     s.sessionVars.ConnectionAttrs,
 	}
 	oldPi := s.ShowProcess()
 	if p == nil {
@@ -1248,9 +1263,15 @@ func (s *session) SetProcessInfo(sql string, t time.Time, command byte, maxExecu
 	if command == mysql.ComSleep {
 		s.currentPlan = nil
 	}
+	pi.SessionMemTracker = s.sessionVars.MemTracker
+	pi.MemQuotaSession = s.sessionVars.MemQuotaSession
 	if s.sessionVars.User != nil {
 		pi.User = s.sessionVars.User.Username
 		pi.Host = s.sessionVars.User.Hostname
+		pi.Account = s.sessionVars.User.AuthIdentityString()
+		if pm := privilege.GetPrivilegeManager(s); pm != nil && command != mysql.ComSleep {
+			pi.MemQuotaUser = pm.GetUserResources(s.sessionVars.User.AuthUsername, s.sessionVars.User.AuthHostname).MaxMemoryQuota
+		}
 	}
 	s.processInfo.Store(&pi)
 }
@@ -1541,6 +1562,9 @@ func (s *session) ExecuteStmt(ctx context.Context, stmtNode ast.StmtNode) (sqlex
 	if err := s.validateStatementReadOnlyInStaleness(stmtNode); err != nil {
 		return nil, err
 	}
+	if err := s.validateStatementInSandBoxMode(stmtNode); err != nil {
+		return nil, err
+	}
 
 	// Uncorrelated subqueries will execute once when building plan, so we reset process info before building plan.
 	cmd32 := atomic.LoadUint32(&s.GetSessionVars().CommandValue)
@@ -1635,6 +1659,19 @@ func (s *session) validateStatementReadOnlyInStaleness(stmtNode ast.StmtNode) er
 	return nil
 }
 
+// validateStatementInSandBoxMode only allows the statements to reset the password when the password
+// of the user has expired.
+func (s *session) validateStatementInSandBoxMode(stmtNode ast.StmtNode) error {
+	if !s.sessionVars.InSandBoxMode || s.isInternal() {
+		return nil
+	}
+	switch stmtNode.(type) {
+	case *ast.SetPwdStmt, *ast.AlterUserStmt, *ast.SetStmt, *ast.UseStmt:
+		return nil
+	}
+	return ErrMustChangePassword.GenWithStackByArgs()
+}
+
 // querySpecialKeys contains the keys of special query, the special query will handled by handleQuerySpecial method.
 var querySpecialKeys = []fmt.Stringer{
 	executor.LoadDataVarKey,
@@ -1786,6 +1823,9 @@ func (s *session) rollbackOnError(ctx context.Context) {
 
 // PrepareStmt is used for executing prepare statement in binary protocol
 func (s *session) PrepareStmt(sql string) (stmtID uint32, paramCount int, fields []*ast.ResultField, err error) {
+	if s.sessionVars.InSandBoxMode {
+		return 0, 0, nil, ErrMustChangePassword.GenWithStackByArgs()
+	}
 	if s.sessionVars.TxnCtx.InfoSchema == nil {
 		// We don't need to create a transaction for prepare statement, just get information schema will do.
 		s.sessionVars.TxnCtx.InfoSchema = domain.GetDomain(s).InfoSchema()
@@ -2200,10 +2240,12 @@ func (s *session) Close() {
 	if bindValue != nil {
 		bindValue.(*bindinfo.SessionHandle).Close()
 	}
+	s.releaseUserConnection()
 	ctx := context.WithValue(context.TODO(), inCloseSession{}, struct{}{})
 	s.RollbackTxn(ctx)
 	if s.sessionVars != nil {
 		s.sessionVars.WithdrawAllPreparedStmt()
+		s.sessionVars.MemTracker.DetachFromGlobalTracker()
 	}
 	s.ClearDiskFullOpt()
 }
@@ -2223,34 +2265,136 @@ func (s *session) AuthPluginForUser(user *auth.UserIdentity) (string, error) {
 }
 
 func (s *session) Auth(user *auth.UserIdentity, authentication []byte, salt []byte) bool {
+	return s.AuthWithError(user, authentication, salt) == nil
+}
+
+// AuthWithError is like Auth, but returns the reason if the authentication fails.
+func (s *session) AuthWithError(user *auth.UserIdentity, authentication []byte, salt []byte) error {
 	pm := privilege.GetPrivilegeManager(s)
 
 	// Check IP or localhost.
-	var success bool
-	user.AuthUsername, user.AuthHostname, success = pm.ConnectionVerification(user.Username, user.Hostname, authentication, salt, s.sessionVars.TLSConnectionState)
-	if success {
-		s.sessionVars.User = user
-		s.sessionVars.ActiveRoles = pm.GetDefaultRoles(user.AuthUsername, user.AuthHostname)
-		return true
-	} else if user.Hostname == variable.DefHostname {
-		return false
+	info, err := pm.ConnectionVerification(user.Username, user.Hostname, authentication, salt, s.sessionVars)
+	if err == nil {
+		user.AuthUsername, user.AuthHostname = info.AuthUser, info.AuthHost
+		return s.onAuthSucceeded(user, info)
+	}
+	if user.Hostname != variable.DefHostname {
+		// Check Hostname.
+		for _, addr := range s.getHostByIP(user.Hostname) {
+			hostInfo, hostErr := pm.ConnectionVerification(user.Username, addr, authentication, salt, s.sessionVars)
+			if hostErr == nil {
+				return s.onAuthSucceeded(&auth.UserIdentity{
+					Username:     user.Username,
+					Hostname:     addr,
+					AuthUsername: hostInfo.AuthUser,
+					AuthHostname: hostInfo.AuthHost,
+				}, hostInfo)
+			}
+			if !info.FailedDueToWrongPassword && hostInfo.FailedDueToWrongPassword {
+				info, err = hostInfo, hostErr
+			}
+		}
+	}
+	if info.FailedDueToWrongPassword && info.FailedLoginTracking {
+		lockedErr, trackErr := s.trackFailedLogin(info.AuthUser, info.AuthHost, true)
+		if trackErr != nil {
+			logutil.BgLogger().Warn("track failed login failed", zap.String("user", info.AuthUser),
+				zap.String("host", info.AuthHost), zap.Error(trackErr))
+		}
+		if lockedErr != nil {
+			return lockedErr
+		}
 	}
+	return err
+}
 
-	// Check Hostname.
-	for _, addr := range s.getHostByIP(user.Hostname) {
-		u, h, success := pm.ConnectionVerification(user.Username, addr, authentication, salt, s.sessionVars.TLSConnectionState)
-		if success {
-			s.sessionVars.User = &auth.UserIdentity{
-				Username:     user.Username,
-				Hostname:     addr,
-				AuthUsername: u,
-				AuthHostname: h,
-			}
-			s.sessionVars.ActiveRoles = pm.GetDefaultRoles(u, h)
-			return true
+func (s *session) onAuthSucceeded(user *auth.UserIdentity, info privilege.VerificationInfo) error {
+	pm := privilege.GetPrivilegeManager(s)
+	if info.FailedLoginCount > 0 {
+		if _, err := s.trackFailedLogin(info.AuthUser, info.AuthHost, false); err != nil {
+			logutil.BgLogger().Warn("reset failed login count failed", zap.String("user", info.AuthUser),
+				zap.String("host", info.AuthHost), zap.Error(err))
 		}
 	}
-	return false
+	s.sessionVars.User = user
+	s.sessionVars.ActiveRoles = pm.GetDefaultRoles(user.AuthUsername, user.AuthHostname)
+	s.sessionVars.InSandBoxMode = info.InSandBoxMode
+	return nil
+}
+
+// trackFailedLogin records the consecutive failed logins of the account in mysql.user, the count is
+// reset if the login succeeds. When the count reaches FAILED_LOGIN_ATTEMPTS, the account is locked for
+// PASSWORD_LOCK_TIME days and lockedErr tells that. The count and the lock are shared by all the TiDB
+// instances, but the privileges are only reloaded when the account starts counting, gets locked or is
+// reset, since the other instances only need to know whether there is a count to reset.
+func (s *session) trackFailedLogin(user, host string, failed bool) (lockedErr error, err error) {
+	ctx := context.Background()
+	tmp, err := s.sysSessionPool().Get()
+	if err != nil {
+		return nil, err
+	}
+	defer s.sysSessionPool().Put(tmp)
+	se := tmp.(*session)
+
+	if _, err = se.ExecuteInternal(ctx, "BEGIN PESSIMISTIC"); err != nil {
+		return nil, err
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			_, rollbackErr := se.ExecuteInternal(ctx, "ROLLBACK")
+			terror.Log(rollbackErr)
+		}
+	}()
+	rs, err := se.ExecuteInternal(ctx, "SELECT User_attributes FROM %n.%n WHERE User=%? AND Host=%? FOR UPDATE",
+		mysql.SystemDB, mysql.UserTable, user, host)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := drainRecordSet(ctx, se, rs, nil)
+	terror.Log(rs.Close())
+	if err != nil || len(rows) == 0 || rows[0].IsNull(0) {
+		return nil, err
+	}
+	locking, err := privileges.ParsePasswordLocking(rows[0].GetJSON(0).String())
+	if err != nil || !locking.Enabled() {
+		return nil, err
+	}
+
+	now := time.Now()
+	old := locking
+	if failed {
+		if locking.LockedDaysRemaining(now) != 0 {
+			// The account has been locked by another TiDB instance.
+			return privileges.PasswordLockedError(user, host, &locking, now), nil
+		}
+		if locking.AutoLockedAt != 0 {
+			// The account is unlocked automatically, count from the beginning.
+			locking.FailedLoginCount, locking.AutoLockedAt = 0, 0
+		}
+		locking.FailedLoginCount++
+		if locking.FailedLoginCount >= locking.FailedLoginAttempts {
+			locking.AutoLockedAt = now.Unix()
+			lockedErr = privileges.PasswordLockedError(user, host, &locking, now)
+		}
+	} else {
+		locking.FailedLoginCount, locking.AutoLockedAt = 0, 0
+	}
+	if locking == old {
+		return nil, nil
+	}
+	if _, err = se.ExecuteInternal(ctx, "UPDATE %n.%n SET User_attributes=%? WHERE User=%? AND Host=%?",
+		mysql.SystemDB, mysql.UserTable, locking.JSON(), user, host); err != nil {
+		return nil, err
+	}
+	if _, err = se.ExecuteInternal(ctx, "COMMIT"); err != nil {
+		return nil, err
+	}
+	committed = true
+	if (old.FailedLoginCount == 0) == (locking.FailedLoginCount == 0) && (old.AutoLockedAt == 0) == (locking.AutoLockedAt == 0) {
+		return lockedErr, nil
+	}
+	return lockedErr, domain.GetDomain(s).NotifyUpdatePrivilege()
 }
 
 // AuthWithoutVerification is required by the ResetConnection RPC
@@ -2495,6 +2639,11 @@ func BootstrapSession(store kv.Storage) (*domain.Domain, error) {
 		})
 	}
 
+	// The user connections counted by the previous run of this instance are all closed.
+	if err = resetUserConnections(se); err != nil {
+		return nil, err
+	}
+
 	dom := domain.GetDomain(se)
 
 	se2, err := createSession(store)
@@ -2568,6 +2717,13 @@ func BootstrapSession(store kv.Storage) (*domain.Domain, error) {
 
 	dom.PlanReplayerLoop()
 
+	se8, err := createSession(store)
+	if err != nil {
+		return nil, err
+	}
+	tracker := getUserResourceTracker(store)
+	dom.UserResourceUsageLoop(userResourceFlushInterval, func() { tracker.flush(se8) })
+
 	if raw, ok := store.(kv.EtcdBackend); ok {
 		err = raw.StartGCWorker()
 		if err != nil {
@@ -2635,6 +2791,7 @@ func createSessionWithOpt(store kv.Storage, opt *Opt) (*session, error) {
 	// session implements variable.GlobalVarAccessor. Bind it to ctx.
 	s.sessionVars.GlobalVarsAccessor = s
 	s.sessionVars.BinlogClient = binloginfo.GetPumpsClient()
+	s.sessionVars.MemTracker.AttachToGlobalTracker(memory.GlobalSessionMemoryUsageTracker)
 	s.txn.init()
 
 	sessionBindHandle := bindinfo.NewSessionBindHandle(parser.New())
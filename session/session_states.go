@@ -0,0 +1,208 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"context"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/executor"
+	"github.com/pingcap/tidb/infoschema"
+	"github.com/pingcap/tidb/meta/autoid"
+	"github.com/pingcap/tidb/parser/auth"
+	"github.com/pingcap/tidb/parser/model"
+	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/sessionstates"
+	"github.com/pingcap/tidb/table"
+	"github.com/pingcap/tidb/table/temptable"
+)
+
+// SetSessionStatesHandler implements the Session interface.
+func (s *session) SetSessionStatesHandler(handler sessionctx.SessionStatesHandler) {
+	s.sessionStatesHandler = handler
+}
+
+// checkSessionMigratable checks whether the session has the states which cannot be migrated.
+func (s *session) checkSessionMigratable() error {
+	if s.sessionVars.InTxn() {
+		return sessionstates.ErrCannotMigrateSession.GenWithStackByArgs("session has an active transaction")
+	}
+	if s.HasLockedTables() {
+		return sessionstates.ErrCannotMigrateSession.GenWithStackByArgs("session has locked tables")
+	}
+	return nil
+}
+
+// EncodeSessionStates implements the sessionctx.SessionStatesHandler interface.
+func (s *session) EncodeSessionStates(ctx context.Context, sctx sessionctx.Context, sessionStates *sessionstates.SessionStates) error {
+	if err := s.checkSessionMigratable(); err != nil {
+		return err
+	}
+	sessionStates.User = s.sessionVars.User
+	sessionStates.ActiveRoles = s.sessionVars.ActiveRoles
+	if err := s.sessionVars.EncodeSessionStates(sessionStates); err != nil {
+		return err
+	}
+	if err := s.encodeLocalTemporaryTables(sessionStates); err != nil {
+		return err
+	}
+
+	stmtNames := make(map[uint32]string, len(s.sessionVars.PreparedStmtNameToID))
+	for name, id := range s.sessionVars.PreparedStmtNameToID {
+		stmtNames[id] = name
+	}
+	sessionStates.PreparedStmts = make(map[uint32]*sessionstates.PreparedStmtInfo, len(s.sessionVars.PreparedStmts))
+	for id, stmt := range s.sessionVars.PreparedStmts {
+		prepared, ok := stmt.(*plannercore.CachedPrepareStmt)
+		if !ok {
+			return errors.Errorf("invalid CachedPrepareStmt type")
+		}
+		sessionStates.PreparedStmts[id] = &sessionstates.PreparedStmtInfo{
+			Name:     stmtNames[id],
+			StmtText: prepared.StmtText,
+			StmtDB:   prepared.StmtDB,
+		}
+	}
+
+	if s.sessionStatesHandler != nil {
+		return s.sessionStatesHandler.EncodeSessionStates(ctx, s, sessionStates)
+	}
+	return nil
+}
+
+// encodeLocalTemporaryTables saves the metadata of the local temporary tables. The data of the tables
+// is not migrated, so the session cannot be migrated if any of the tables has data.
+func (s *session) encodeLocalTemporaryTables(sessionStates *sessionstates.SessionStates) error {
+	localTempTables, ok := s.sessionVars.LocalTemporaryTables.(*infoschema.LocalTemporaryTables)
+	if !ok {
+		return nil
+	}
+	tempTableData := s.sessionVars.TemporaryTableData
+	return localTempTables.ForEachTable(func(db *model.DBInfo, tbl table.Table) error {
+		tblInfo := tbl.Meta()
+		if tempTableData != nil && tempTableData.GetTableSize(tblInfo.ID) > 0 {
+			return sessionstates.ErrCannotMigrateSession.GenWithStackByArgs(
+				"local temporary table " + db.Name.O + "." + tblInfo.Name.O + " has data")
+		}
+		info := &sessionstates.TemporaryTableInfo{
+			DB:    &model.DBInfo{ID: db.ID, Name: db.Name, Charset: db.Charset, Collate: db.Collate},
+			Table: tblInfo.Clone(),
+		}
+		if alloc := tbl.Allocators(s).Get(autoid.RowIDAllocType); alloc != nil {
+			info.AutoIDBase = alloc.Base()
+		}
+		sessionStates.LocalTemporaryTables = append(sessionStates.LocalTemporaryTables, info)
+		return nil
+	})
+}
+
+// DecodeSessionStates implements the sessionctx.SessionStatesHandler interface.
+func (s *session) DecodeSessionStates(ctx context.Context, sctx sessionctx.Context, sessionStates *sessionstates.SessionStates) error {
+	if err := s.checkSessionMigratable(); err != nil {
+		return err
+	}
+	for id := range sessionStates.PreparedStmts {
+		if _, ok := s.sessionVars.PreparedStmts[id]; ok {
+			return sessionstates.ErrCannotMigrateSession.GenWithStackByArgs("session has prepared statements")
+		}
+	}
+	if err := s.restoreUser(sessionStates.User, sessionStates.ActiveRoles); err != nil {
+		return err
+	}
+	if err := s.sessionVars.DecodeSessionStates(sessionStates); err != nil {
+		return err
+	}
+	if err := s.decodeLocalTemporaryTables(ctx, sessionStates); err != nil {
+		return err
+	}
+
+	// The statements are prepared in the databases where they were prepared before.
+	currentDB := s.sessionVars.CurrentDB
+	defer func() {
+		s.sessionVars.CurrentDB = currentDB
+	}()
+	for id, info := range sessionStates.PreparedStmts {
+		s.sessionVars.CurrentDB = info.StmtDB
+		if err := executor.NewPrepareExecWithID(s, info.StmtText, info.Name, id).Next(ctx, nil); err != nil {
+			return err
+		}
+	}
+
+	if s.sessionStatesHandler != nil {
+		return s.sessionStatesHandler.DecodeSessionStates(ctx, s, sessionStates)
+	}
+	return nil
+}
+
+// restoreUser restores the identity of the migrated session, whose privileges are checked again. The session must
+// have logged in as the same account, otherwise anyone who gets a token could act as the account of the token.
+func (s *session) restoreUser(user *auth.UserIdentity, roles []*auth.RoleIdentity) error {
+	if user == nil {
+		return nil
+	}
+	current := s.sessionVars.User
+	if current == nil || current.AuthUsername != user.AuthUsername || current.AuthHostname != user.AuthHostname {
+		return sessionstates.ErrInvalidSessionStates.GenWithStackByArgs("the session states of user " + user.String() + " can't be restored by the current user")
+	}
+	pm := privilege.GetPrivilegeManager(s)
+	if pm == nil {
+		return nil
+	}
+	if u, h, ok := pm.GetAuthWithoutVerification(user.Username, user.Hostname); !ok || u != user.AuthUsername || h != user.AuthHostname {
+		return sessionstates.ErrInvalidSessionStates.GenWithStackByArgs("user " + user.String() + " does not exist")
+	}
+	s.sessionVars.User = user
+	// The roles may be revoked after the session states are shown, so they are checked again like SET ROLE.
+	account := &auth.UserIdentity{Username: user.AuthUsername, Hostname: user.AuthHostname}
+	activeRoles := make([]*auth.RoleIdentity, 0, len(roles))
+	for _, role := range roles {
+		if pm.FindEdge(s, role, account) {
+			activeRoles = append(activeRoles, role)
+		}
+	}
+	s.sessionVars.ActiveRoles = activeRoles
+	return nil
+}
+
+// decodeLocalTemporaryTables creates the migrated local temporary tables, which are empty.
+func (s *session) decodeLocalTemporaryTables(ctx context.Context, sessionStates *sessionstates.SessionStates) error {
+	is := s.GetInfoSchema().(infoschema.InfoSchema)
+	tempTableDDL := temptable.GetTemporaryTableDDL(s)
+	for _, info := range sessionStates.LocalTemporaryTables {
+		db := info.DB
+		if dbInfo, ok := is.SchemaByName(db.Name); ok {
+			db = dbInfo
+		}
+		if err := tempTableDDL.CreateLocalTemporaryTable(db, info.Table); err != nil {
+			return err
+		}
+		if info.AutoIDBase == 0 {
+			continue
+		}
+		// The table ID is allocated again by CreateLocalTemporaryTable.
+		tbl, ok := s.sessionVars.LocalTemporaryTables.(*infoschema.LocalTemporaryTables).TableByID(info.Table.ID)
+		if !ok {
+			continue
+		}
+		if alloc := tbl.Allocators(s).Get(autoid.RowIDAllocType); alloc != nil {
+			if err := alloc.Rebase(ctx, info.AutoIDBase, false); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -371,4 +371,9 @@ func ResultSetToStringSlice(ctx context.Context, s Session, rs sqlexec.RecordSet
 // Session errors.
 var (
 	ErrForUpdateCantRetry = dbterror.ClassSession.NewStd(errno.ErrForUpdateCantRetry)
+	ErrMustChangePassword = dbterror.ClassSession.NewStd(errno.ErrMustChangePassword)
+	// ErrUserLimitReached is returned when an hourly resource limit of the account is reached.
+	ErrUserLimitReached = dbterror.ClassSession.NewStd(errno.ErrUserLimitReached)
+	// ErrTooManyUserConnections is returned when the MAX_USER_CONNECTIONS limit of the account is reached.
+	ErrTooManyUserConnections = dbterror.ClassSession.NewStd(errno.ErrTooManyUserConnections)
 )
@@ -0,0 +1,434 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/domain/infosync"
+	"github.com/pingcap/tidb/kv"
+	"github.com/pingcap/tidb/parser/ast"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/parser/terror"
+	plannercore "github.com/pingcap/tidb/planner/core"
+	"github.com/pingcap/tidb/privilege"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+)
+
+// userResourceUsage is the usage of the resources limited by MAX_QUERIES_PER_HOUR, MAX_UPDATES_PER_HOUR,
+// MAX_CONNECTIONS_PER_HOUR and MAX_USER_CONNECTIONS.
+type userResourceUsage struct {
+	questions       int64
+	updates         int64
+	connections     int64
+	userConnections int64
+}
+
+var (
+	// store.UUID()-> the statements counted by this instance
+	userResourceTrackers     = make(map[string]*userResourceTracker)
+	userResourceTrackersLock sync.Mutex
+
+	// userResourceFlushInterval is the interval to flush the statements counted by this instance to
+	// mysql.user_resource_usage, the instances see the statements of each other after the flush.
+	userResourceFlushInterval = time.Second
+	// userResourceReapInterval is the interval to clean up the usage counted by the instances which have left the cluster.
+	userResourceReapInterval = time.Minute
+)
+
+// ResourceUsageInstance returns the name of this TiDB instance in mysql.user_resource_usage.
+func ResourceUsageInstance() string {
+	cfg := config.GetGlobalConfig()
+	return net.JoinHostPort(cfg.AdvertiseAddress, strconv.Itoa(int(cfg.Port)))
+}
+
+// resourceUsageWindow returns the start of the hour in which the hourly resources are counted.
+func resourceUsageWindow(now time.Time) int64 {
+	return now.Truncate(time.Hour).Unix()
+}
+
+// ConsumeConnectionResource counts a new connection of the logged-in account, it fails if the
+// MAX_CONNECTIONS_PER_HOUR or MAX_USER_CONNECTIONS limit of the account is reached.
+func (s *session) ConsumeConnectionResource() error {
+	user := s.sessionVars.User
+	if user == nil || s.userConnectionCounted {
+		return nil
+	}
+	limits := privilege.GetPrivilegeManager(s).GetUserResources(user.AuthUsername, user.AuthHostname)
+	if limits.MaxConnections == 0 && limits.MaxUserConnections == 0 {
+		return nil
+	}
+	err := s.consumeUserResource(user.AuthUsername, user.AuthHostname, limits, userResourceUsage{connections: 1, userConnections: 1})
+	if err != nil {
+		return err
+	}
+	s.userConnectionCounted = true
+	return nil
+}
+
+// ConsumeStatementResource counts a statement sent by the client of the logged-in account, it fails if the
+// MAX_QUERIES_PER_HOUR limit, or the MAX_UPDATES_PER_HOUR limit for the statements modifying data, is reached.
+func (s *session) ConsumeStatementResource(stmt ast.StmtNode) error {
+	user := s.sessionVars.User
+	if user == nil {
+		return nil
+	}
+	limits := privilege.GetPrivilegeManager(s).GetUserResources(user.AuthUsername, user.AuthHostname)
+	usage := userResourceUsage{questions: 1}
+	if s.isUpdateStmt(stmt) {
+		usage.updates = 1
+	}
+	if limits.MaxQuestions == 0 && (limits.MaxUpdates == 0 || usage.updates == 0) {
+		return nil
+	}
+	return getUserResourceTracker(s.store).consume(s, user.AuthUsername, user.AuthHostname, limits, usage)
+}
+
+// isUpdateStmt reports whether the statement is counted by MAX_UPDATES_PER_HOUR, which limits
+// the statements modifying tables, databases and accounts.
+func (s *session) isUpdateStmt(stmt ast.StmtNode) bool {
+	switch x := stmt.(type) {
+	case ast.DDLNode, *ast.InsertStmt, *ast.UpdateStmt, *ast.DeleteStmt, *ast.LoadDataStmt,
+		*ast.CreateUserStmt, *ast.AlterUserStmt, *ast.DropUserStmt, *ast.RenameUserStmt, *ast.SetPwdStmt,
+		*ast.GrantStmt, *ast.GrantRoleStmt, *ast.RevokeStmt, *ast.RevokeRoleStmt:
+		return true
+	case *ast.ExecuteStmt:
+		// EXECUTE is counted as the prepared statement.
+		id, ok := s.sessionVars.PreparedStmtNameToID[x.Name]
+		if !ok {
+			return false
+		}
+		if prepared, ok := s.sessionVars.PreparedStmts[id].(*plannercore.CachedPrepareStmt); ok {
+			return s.isUpdateStmt(prepared.PreparedAst.Stmt)
+		}
+	}
+	return false
+}
+
+// consumeUserResource counts the connections of the account in the row of this instance in mysql.user_resource_usage,
+// it fails without counting anything if a resource would exceed its limit. The usage of an account is the sum of
+// all the instances, the rows of the account are locked so that the instances check and count the usage one by one.
+func (s *session) consumeUserResource(user, host string, limits privilege.UserResources, usage userResourceUsage) error {
+	ctx := context.Background()
+	tmp, err := s.sysSessionPool().Get()
+	if err != nil {
+		return err
+	}
+	defer s.sysSessionPool().Put(tmp)
+	se := tmp.(*session)
+
+	if _, err = se.ExecuteInternal(ctx, "BEGIN PESSIMISTIC"); err != nil {
+		return err
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			_, rollbackErr := se.ExecuteInternal(ctx, "ROLLBACK")
+			terror.Log(rollbackErr)
+		}
+	}()
+	rs, err := se.ExecuteInternal(ctx, "SELECT Window_start, Questions, Updates, Connections, User_connections FROM %n.%n WHERE Host=%? AND User=%? FOR UPDATE",
+		mysql.SystemDB, mysql.UserResourceUsageTable, host, user)
+	if err != nil {
+		return err
+	}
+	rows, err := drainRecordSet(ctx, se, rs, nil)
+	terror.Log(rs.Close())
+	if err != nil {
+		return err
+	}
+	window := resourceUsageWindow(time.Now())
+	var used userResourceUsage
+	for _, row := range rows {
+		if int64(row.GetUint64(0)) == window {
+			used.questions += int64(row.GetUint64(1))
+			used.updates += int64(row.GetUint64(2))
+			used.connections += int64(row.GetUint64(3))
+		}
+		used.userConnections += int64(row.GetUint64(4))
+	}
+	switch {
+	case exceedsLimit(used.userConnections, usage.userConnections, limits.MaxUserConnections):
+		return ErrTooManyUserConnections.GenWithStackByArgs(user)
+	case exceedsLimit(used.connections, usage.connections, limits.MaxConnections):
+		return ErrUserLimitReached.GenWithStackByArgs(user, "max_connections_per_hour", limits.MaxConnections)
+	case exceedsLimit(used.questions, usage.questions, limits.MaxQuestions):
+		return ErrUserLimitReached.GenWithStackByArgs(user, "max_questions", limits.MaxQuestions)
+	case exceedsLimit(used.updates, usage.updates, limits.MaxUpdates):
+		return ErrUserLimitReached.GenWithStackByArgs(user, "max_updates", limits.MaxUpdates)
+	}
+
+	// The hourly counters of the row are restarted if they were counted in a previous hour, so Window_start
+	// has to be assigned after them.
+	if _, err = se.ExecuteInternal(ctx, `INSERT INTO %n.%n (Host, User, Instance, Window_start, Questions, Updates, Connections, User_connections)
+		VALUES (%?, %?, %?, %?, %?, %?, %?, %?) ON DUPLICATE KEY UPDATE
+		Questions = IF(Window_start = VALUES(Window_start), Questions, 0) + VALUES(Questions),
+		Updates = IF(Window_start = VALUES(Window_start), Updates, 0) + VALUES(Updates),
+		Connections = IF(Window_start = VALUES(Window_start), Connections, 0) + VALUES(Connections),
+		User_connections = User_connections + VALUES(User_connections),
+		Window_start = VALUES(Window_start)`,
+		mysql.SystemDB, mysql.UserResourceUsageTable, host, user, ResourceUsageInstance(), window,
+		usage.questions, usage.updates, usage.connections, usage.userConnections); err != nil {
+		return err
+	}
+	if _, err = se.ExecuteInternal(ctx, "COMMIT"); err != nil {
+		return err
+	}
+	committed = true
+	return nil
+}
+
+// exceedsLimit reports whether counting the delta would exceed the limit, 0 means no limit.
+func exceedsLimit(used, delta, limit int64) bool {
+	return delta > 0 && limit > 0 && used+delta > limit
+}
+
+// releaseUserConnection stops counting the connection of the session in MAX_USER_CONNECTIONS.
+func (s *session) releaseUserConnection() {
+	if !s.userConnectionCounted {
+		return
+	}
+	s.userConnectionCounted = false
+	user := s.sessionVars.User
+	tmp, err := s.sysSessionPool().Get()
+	if err == nil {
+		_, err = tmp.(*session).ExecuteInternal(context.Background(), "UPDATE %n.%n SET User_connections = User_connections - 1 WHERE Host=%? AND User=%? AND Instance=%? AND User_connections > 0",
+			mysql.SystemDB, mysql.UserResourceUsageTable, user.AuthHostname, user.AuthUsername, ResourceUsageInstance())
+		s.sysSessionPool().Put(tmp)
+	}
+	if err != nil {
+		logutil.BgLogger().Warn("release user connection failed", zap.String("user", user.AuthUsername),
+			zap.String("host", user.AuthHostname), zap.Error(err))
+	}
+}
+
+// resetUserConnections clears MAX_USER_CONNECTIONS counting of this instance, the connections counted by
+// the previous run of the instance are all gone.
+func resetUserConnections(se *session) error {
+	_, err := se.ExecuteInternal(context.Background(), "UPDATE %n.%n SET User_connections = 0 WHERE Instance=%?",
+		mysql.SystemDB, mysql.UserResourceUsageTable, ResourceUsageInstance())
+	return err
+}
+
+// reapUserResourceUsage cleans up the usage counted by the instances which are not in the cluster anymore. The
+// connections of such instances are all gone, and their rows are deleted once the hourly counters expire.
+func reapUserResourceUsage(se *session) error {
+	ctx := context.Background()
+	servers, err := infosync.GetAllServerInfo(ctx)
+	if err != nil || len(servers) == 0 {
+		return err
+	}
+	instances := make([]string, 0, len(servers))
+	for _, info := range servers {
+		instances = append(instances, net.JoinHostPort(info.IP, strconv.Itoa(int(info.Port))))
+	}
+	if _, err = se.ExecuteInternal(ctx, "DELETE FROM %n.%n WHERE Instance NOT IN (%?) AND Window_start < %?",
+		mysql.SystemDB, mysql.UserResourceUsageTable, instances, resourceUsageWindow(time.Now())); err != nil {
+		return err
+	}
+	_, err = se.ExecuteInternal(ctx, "UPDATE %n.%n SET User_connections = 0 WHERE Instance NOT IN (%?) AND User_connections > 0",
+		mysql.SystemDB, mysql.UserResourceUsageTable, instances)
+	return err
+}
+
+// userResourceTracker counts the statements of the accounts on this instance. Locking the rows of an account for
+// every statement is too expensive, so the statements are counted in memory and flushed to mysql.user_resource_usage
+// periodically. Like MySQL, the limits are approximate, the instances may exceed a limit together before they see
+// the statements of each other.
+type userResourceTracker struct {
+	sync.Mutex
+	accounts map[string]*accountResourceUsage
+	lastReap time.Time
+}
+
+// accountResourceUsage is the hourly usage of an account in a window.
+type accountResourceUsage struct {
+	user   string
+	host   string
+	window int64
+	// flushed is the usage of all the instances loaded from mysql.user_resource_usage, pending is the usage
+	// counted by this instance and not flushed yet.
+	flushed userResourceUsage
+	pending userResourceUsage
+}
+
+// getUserResourceTracker returns the tracker counting the statements of the store on this instance.
+func getUserResourceTracker(store kv.Storage) *userResourceTracker {
+	userResourceTrackersLock.Lock()
+	defer userResourceTrackersLock.Unlock()
+	t, ok := userResourceTrackers[store.UUID()]
+	if !ok {
+		t = &userResourceTracker{accounts: make(map[string]*accountResourceUsage), lastReap: time.Now()}
+		userResourceTrackers[store.UUID()] = t
+	}
+	return t
+}
+
+// get returns the usage of the account in the current window, it is nil if the usage is not loaded yet. The usage
+// is kept when the limits of the account are changed, the statements counted in the window are checked against
+// the new limits.
+func (t *userResourceTracker) get(user, host string, window int64) *accountResourceUsage {
+	a, ok := t.accounts[user+"@"+host]
+	if !ok || a.window != window {
+		return nil
+	}
+	return a
+}
+
+// consume counts the statement in memory, it fails without counting anything if a resource would exceed its limit.
+func (t *userResourceTracker) consume(s *session, user, host string, limits privilege.UserResources, usage userResourceUsage) error {
+	window := resourceUsageWindow(time.Now())
+	t.Lock()
+	a := t.get(user, host, window)
+	if a == nil {
+		t.Unlock()
+		flushed, err := loadUserResourceUsage(s, user, host, window)
+		if err != nil {
+			return err
+		}
+		t.Lock()
+		if a = t.get(user, host, window); a == nil {
+			a = &accountResourceUsage{user: user, host: host, window: window, flushed: flushed}
+			t.accounts[user+"@"+host] = a
+		}
+	}
+	defer t.Unlock()
+
+	switch {
+	case exceedsLimit(a.flushed.questions+a.pending.questions, usage.questions, limits.MaxQuestions):
+		return ErrUserLimitReached.GenWithStackByArgs(user, "max_questions", limits.MaxQuestions)
+	case exceedsLimit(a.flushed.updates+a.pending.updates, usage.updates, limits.MaxUpdates):
+		return ErrUserLimitReached.GenWithStackByArgs(user, "max_updates", limits.MaxUpdates)
+	}
+	a.pending.questions += usage.questions
+	a.pending.updates += usage.updates
+	return nil
+}
+
+// loadUserResourceUsage loads the statements of the account counted by all the instances in the window.
+func loadUserResourceUsage(s *session, user, host string, window int64) (userResourceUsage, error) {
+	var usage userResourceUsage
+	ctx := context.Background()
+	tmp, err := s.sysSessionPool().Get()
+	if err != nil {
+		return usage, err
+	}
+	defer s.sysSessionPool().Put(tmp)
+	se := tmp.(*session)
+	rs, err := se.ExecuteInternal(ctx, "SELECT Questions, Updates FROM %n.%n WHERE Host=%? AND User=%? AND Window_start=%?",
+		mysql.SystemDB, mysql.UserResourceUsageTable, host, user, window)
+	if err != nil {
+		return usage, err
+	}
+	rows, err := drainRecordSet(ctx, se, rs, nil)
+	terror.Log(rs.Close())
+	if err != nil {
+		return usage, err
+	}
+	for _, row := range rows {
+		usage.questions += int64(row.GetUint64(0))
+		usage.updates += int64(row.GetUint64(1))
+	}
+	return usage, nil
+}
+
+// flush writes the statements counted by this instance to mysql.user_resource_usage, and reloads the statements
+// counted by all the instances. It is called by the loop of the domain.
+func (t *userResourceTracker) flush(se *session) {
+	window := resourceUsageWindow(time.Now())
+	t.Lock()
+	accounts := make([]accountResourceUsage, 0, len(t.accounts))
+	for key, a := range t.accounts {
+		// The statements counted in the previous hours are not limited anymore.
+		if a.window != window {
+			delete(t.accounts, key)
+			continue
+		}
+		accounts = append(accounts, *a)
+	}
+	reap := time.Since(t.lastReap) >= userResourceReapInterval
+	if reap {
+		t.lastReap = time.Now()
+	}
+	t.Unlock()
+
+	if err := t.flushAccounts(se, accounts, window); err != nil {
+		logutil.BgLogger().Warn("flush user resource usage failed", zap.Error(err))
+	}
+	if reap {
+		if err := reapUserResourceUsage(se); err != nil {
+			logutil.BgLogger().Warn("reap user resource usage failed", zap.Error(err))
+		}
+	}
+}
+
+func (t *userResourceTracker) flushAccounts(se *session, accounts []accountResourceUsage, window int64) error {
+	if len(accounts) == 0 {
+		return nil
+	}
+	ctx := context.Background()
+	for i := range accounts {
+		a := &accounts[i]
+		if a.pending.questions == 0 && a.pending.updates == 0 {
+			continue
+		}
+		// The row is only written for an existing account, the usage of a dropped account is not kept. The hourly
+		// counters of the row are restarted if they were counted in a previous hour, see consumeUserResource.
+		if _, err := se.ExecuteInternal(ctx, `INSERT INTO %n.%n (Host, User, Instance, Window_start, Questions, Updates, Connections, User_connections)
+			SELECT Host, User, %?, %?, %?, %?, 0, 0 FROM %n.%n WHERE Host=%? AND User=%? ON DUPLICATE KEY UPDATE
+			Questions = IF(Window_start = VALUES(Window_start), Questions, 0) + VALUES(Questions),
+			Updates = IF(Window_start = VALUES(Window_start), Updates, 0) + VALUES(Updates),
+			Connections = IF(Window_start = VALUES(Window_start), Connections, 0),
+			Window_start = VALUES(Window_start)`,
+			mysql.SystemDB, mysql.UserResourceUsageTable, ResourceUsageInstance(), window, a.pending.questions, a.pending.updates,
+			mysql.SystemDB, mysql.UserTable, a.host, a.user); err != nil {
+			return err
+		}
+		t.Lock()
+		if cur := t.get(a.user, a.host, window); cur != nil {
+			cur.pending.questions -= a.pending.questions
+			cur.pending.updates -= a.pending.updates
+			cur.flushed.questions += a.pending.questions
+			cur.flushed.updates += a.pending.updates
+		}
+		t.Unlock()
+	}
+
+	// Reload the statements counted by the other instances.
+	rs, err := se.ExecuteInternal(ctx, "SELECT User, Host, CAST(SUM(Questions) AS UNSIGNED), CAST(SUM(Updates) AS UNSIGNED) FROM %n.%n WHERE Window_start=%? GROUP BY User, Host",
+		mysql.SystemDB, mysql.UserResourceUsageTable, window)
+	if err != nil {
+		return err
+	}
+	rows, err := drainRecordSet(ctx, se, rs, nil)
+	terror.Log(rs.Close())
+	if err != nil {
+		return err
+	}
+	t.Lock()
+	defer t.Unlock()
+	for _, row := range rows {
+		if a, ok := t.accounts[row.GetString(0)+"@"+row.GetString(1)]; ok && a.window == window {
+			a.flushed = userResourceUsage{questions: int64(row.GetUint64(2)), updates: int64(row.GetUint64(3))}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/privilege"
+	"github.com/stretchr/testify/require"
+)
+
+func queryStrings(t *testing.T, se Session, sql string, args ...interface{}) [][]string {
+	rows, err := ResultSetToStringSlice(context.Background(), se, mustExec(t, se, sql, args...))
+	require.NoError(t, err)
+	return rows
+}
+
+func TestUserResourceTracker(t *testing.T) {
+	store, dom := createStoreAndBootstrap(t)
+	defer func() { require.NoError(t, store.Close()) }()
+	defer dom.Close()
+
+	se := createSessionAndSetID(t, store)
+	mustExec(t, se, "CREATE USER u1 WITH MAX_QUERIES_PER_HOUR 3")
+	window := resourceUsageWindow(time.Now())
+	mustExec(t, se, "INSERT INTO mysql.user_resource_usage VALUES ('%', 'u1', 'tidb-1:4000', ?, 1, 0, 0, 0)", window)
+
+	// The statements counted by the other instances are loaded, the statements of this instance are counted in memory.
+	tracker := getUserResourceTracker(store)
+	limits := privilege.UserResources{MaxQuestions: 3}
+	for i := 0; i < 2; i++ {
+		require.NoError(t, tracker.consume(se.(*session), "u1", "%", limits, userResourceUsage{questions: 1}))
+	}
+	err := tracker.consume(se.(*session), "u1", "%", limits, userResourceUsage{questions: 1})
+	require.True(t, terror.ErrorEqual(err, ErrUserLimitReached), err)
+
+	// The statements are flushed to the row of this instance.
+	require.Eventually(t, func() bool {
+		rows := queryStrings(t, se, "SELECT Questions FROM mysql.user_resource_usage WHERE User = 'u1' AND Instance = ?", ResourceUsageInstance())
+		return len(rows) == 1 && rows[0][0] == "2"
+	}, 5*time.Second, 100*time.Millisecond)
+
+	// The other instances see the statements after the flush.
+	mustExec(t, se, "UPDATE mysql.user_resource_usage SET Questions = 0 WHERE User = 'u1' AND Instance = 'tidb-1:4000'")
+	require.Eventually(t, func() bool {
+		return tracker.consume(se.(*session), "u1", "%", limits, userResourceUsage{questions: 1}) == nil
+	}, 5*time.Second, 100*time.Millisecond)
+
+	// The statements counted in the window are checked against the changed limits.
+	limits = privilege.UserResources{MaxQuestions: 4}
+	require.NoError(t, tracker.consume(se.(*session), "u1", "%", limits, userResourceUsage{questions: 1}))
+	err = tracker.consume(se.(*session), "u1", "%", limits, userResourceUsage{questions: 1})
+	require.True(t, terror.ErrorEqual(err, ErrUserLimitReached), err)
+	err = tracker.consume(se.(*session), "u1", "%", privilege.UserResources{MaxQuestions: 3}, userResourceUsage{questions: 1})
+	require.True(t, terror.ErrorEqual(err, ErrUserLimitReached), err)
+}
+
+func TestReapUserResourceUsage(t *testing.T) {
+	store, dom := createStoreAndBootstrap(t)
+	defer func() { require.NoError(t, store.Close()) }()
+	defer dom.Close()
+
+	se := createSessionAndSetID(t, store)
+	window := resourceUsageWindow(time.Now())
+	mustExec(t, se, "INSERT INTO mysql.user_resource_usage VALUES ('%', 'u1', ?, ?, 1, 1, 1, 1), ('%', 'u1', 'tidb-1:4000', ?, 1, 1, 1, 1), ('%', 'u1', 'tidb-2:4000', ?, 1, 1, 1, 1)",
+		ResourceUsageInstance(), window, window, window-3600)
+	require.NoError(t, reapUserResourceUsage(se.(*session)))
+
+	// The connections of the instances which have left the cluster are gone, and their rows are deleted after
+	// the hourly counters expire.
+	rows := queryStrings(t, se, "SELECT Instance, Questions, User_connections FROM mysql.user_resource_usage ORDER BY Instance")
+	require.Equal(t, [][]string{{ResourceUsageInstance(), "1", "1"}, {"tidb-1:4000", "1", "0"}}, rows)
+}
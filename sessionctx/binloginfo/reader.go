@@ -0,0 +1,352 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package binloginfo
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb-tools/tidb-binlog/node"
+	pumpcli "github.com/pingcap/tidb-tools/tidb-binlog/pump_client"
+	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tipb/go-binlog"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+var (
+	// PumpRefreshInterval is the interval to refresh the pumps read by the ChangeReader.
+	PumpRefreshInterval = 10 * time.Second
+	// PumpRetryInterval is the interval to pull the binlogs again after the connection to a pump fails.
+	PumpRetryInterval = time.Second
+)
+
+// pumpBufferSize is the max number of the binlogs pulled from a pump but not merged yet.
+const pumpBufferSize = 1024
+
+// ChangeEvent is a transaction or a DDL job read from the binlog of the cluster.
+type ChangeEvent struct {
+	StartTS   uint64
+	CommitTS  uint64
+	Mutations []binlog.TableMutation
+	// DDLJobID is not 0 if the event is the binlog of a DDL job, which is written with the query and the
+	// schema state of the job.
+	DDLJobID       int64
+	DDLSchemaState int32
+	DDLQuery       string
+}
+
+// isEmpty returns whether the event only tells the position of the pump, e.g. the fake binlogs written
+// by the pumps periodically and the rollback binlogs.
+func (ev *ChangeEvent) isEmpty() bool {
+	return ev.DDLJobID == 0 && len(ev.Mutations) == 0
+}
+
+// pumpSource is a pump from which the ChangeReader pulls the binlogs.
+type pumpSource struct {
+	status node.Status
+	cancel context.CancelFunc
+	// events are pulled from the pump but not merged yet, sorted by the commit TS.
+	events []*ChangeEvent
+	// pulledTS is the commit TS of the last binlog pulled from the pump.
+	pulledTS uint64
+}
+
+// exhausted returns whether all the binlogs of the pump have been pulled. A pump which isn't online
+// doesn't write binlogs after its max commit TS any more.
+func (src *pumpSource) exhausted() bool {
+	return src.status.State != node.Online && uint64(src.status.MaxCommitTS) <= src.pulledTS
+}
+
+// ChangeReader reads the binlogs of the cluster from all the pumps and merges them in the commit TS order,
+// like drainer does. Every pump keeps the binlogs of the transactions it receives, and writes a fake binlog
+// periodically if it is idle, so a binlog is merged once every pump has sent a binlog after it.
+type ChangeReader struct {
+	client *pumpcli.PumpsClient
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu      sync.Mutex
+	sources map[string]*pumpSource
+	// resolvedTS is the commit TS before which all the binlogs have been merged.
+	resolvedTS uint64
+	// notify is closed and replaced when the binlogs or the pumps are changed.
+	notify chan struct{}
+}
+
+// NewChangeReader creates a ChangeReader which reads the binlogs committed after ts from the pumps of the client.
+func NewChangeReader(client *pumpcli.PumpsClient, ts uint64) *ChangeReader {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &ChangeReader{
+		client:     client,
+		ctx:        ctx,
+		cancel:     cancel,
+		sources:    make(map[string]*pumpSource),
+		resolvedTS: ts,
+		notify:     make(chan struct{}),
+	}
+	r.wg.Add(1)
+	go r.refreshLoop()
+	return r
+}
+
+// Close stops pulling the binlogs.
+func (r *ChangeReader) Close() {
+	r.cancel()
+	r.wg.Wait()
+}
+
+// ResolvedTS returns the commit TS before which all the binlogs have been read.
+func (r *ChangeReader) ResolvedTS() uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.resolvedTS
+}
+
+// TryNext returns the next transaction or DDL job, it's nil if the next one can't be determined yet.
+func (r *ChangeReader) TryNext() *ChangeEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for {
+		var next *pumpSource
+		for _, src := range r.sources {
+			if len(src.events) == 0 {
+				if src.exhausted() {
+					continue
+				}
+				return nil
+			}
+			if next == nil || src.events[0].CommitTS < next.events[0].CommitTS {
+				next = src
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		ev := next.events[0]
+		next.events[0] = nil
+		next.events = next.events[1:]
+		r.resolvedTS = ev.CommitTS
+		r.broadcast()
+		if !ev.isEmpty() {
+			return ev
+		}
+	}
+}
+
+// Wait waits until the binlogs or the pumps are changed, or the context is done.
+func (r *ChangeReader) Wait(ctx context.Context) error {
+	r.mu.Lock()
+	notify := r.notify
+	r.mu.Unlock()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-notify:
+		return nil
+	}
+}
+
+// broadcast wakes up the waiters, it must be called with the lock held.
+func (r *ChangeReader) broadcast() {
+	close(r.notify)
+	r.notify = make(chan struct{})
+}
+
+func (r *ChangeReader) refreshLoop() {
+	defer r.wg.Done()
+	ticker := time.NewTicker(PumpRefreshInterval)
+	defer ticker.Stop()
+	for {
+		if err := r.refresh(); err != nil {
+			logutil.BgLogger().Warn("refresh the pumps of the binlog reader failed", zap.Error(err))
+		}
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// listPumps returns the pumps registered in etcd, or the pumps of the client if it has no registry.
+func (r *ChangeReader) listPumps() ([]*node.Status, error) {
+	if r.client.EtcdRegistry != nil {
+		pumps, _, err := r.client.EtcdRegistry.Nodes(r.ctx, node.NodePrefix[node.PumpNode])
+		return pumps, errors.Trace(err)
+	}
+	r.client.RLock()
+	defer r.client.RUnlock()
+	pumps := make([]*node.Status, 0, len(r.client.Pumps.Pumps))
+	for _, pump := range r.client.Pumps.Pumps {
+		status := pump.Status
+		pumps = append(pumps, &status)
+	}
+	return pumps, nil
+}
+
+// refresh starts pulling the binlogs from the new pumps, and stops pulling from the pumps which are exhausted.
+func (r *ChangeReader) refresh() error {
+	pumps, err := r.listPumps()
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	listed := make(map[string]struct{}, len(pumps))
+	for _, status := range pumps {
+		listed[status.NodeID] = struct{}{}
+		src, ok := r.sources[status.NodeID]
+		if !ok {
+			if status.State != node.Online && uint64(status.MaxCommitTS) <= r.resolvedTS {
+				continue
+			}
+			ctx, cancel := context.WithCancel(r.ctx)
+			src = &pumpSource{cancel: cancel, pulledTS: r.resolvedTS}
+			r.sources[status.NodeID] = src
+			r.wg.Add(1)
+			go r.pull(ctx, src, status.NodeID, status.Addr)
+		}
+		src.status = *status
+	}
+	for id, src := range r.sources {
+		if _, ok := listed[id]; !ok {
+			// the pump has been removed from the registry, so it won't write binlogs any more.
+			src.status.State = node.Offline
+		}
+		if src.exhausted() && len(src.events) == 0 {
+			src.cancel()
+			delete(r.sources, id)
+		}
+	}
+	r.broadcast()
+	return nil
+}
+
+// pull pulls the binlogs from the pump until the context is done, it reconnects to the pump on error.
+func (r *ChangeReader) pull(ctx context.Context, src *pumpSource, nodeID, addr string) {
+	defer r.wg.Done()
+	for {
+		err := r.pullOnce(ctx, src, addr)
+		if ctx.Err() != nil {
+			return
+		}
+		logutil.BgLogger().Warn("pull binlogs from pump failed", zap.String("pump", nodeID),
+			zap.String("addr", addr), zap.Error(err))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(PumpRetryInterval):
+		}
+	}
+}
+
+func (r *ChangeReader) pullOnce(ctx context.Context, src *pumpSource, addr string) error {
+	opts := []grpc.DialOption{grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(math.MaxInt32))}
+	if r.client.Security != nil {
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(r.client.Security)))
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+	}
+	conn, err := grpc.DialContext(ctx, addr, opts...)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() {
+		terror.Log(conn.Close())
+	}()
+
+	r.mu.Lock()
+	from := src.pulledTS
+	r.mu.Unlock()
+	stream, err := binlog.NewPumpClient(conn).PullBinlogs(ctx, &binlog.PullBinlogReq{
+		ClusterID: r.client.ClusterID,
+		StartFrom: binlog.Pos{Offset: int64(from)},
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		ev, err := decodeChangeEvent(resp.Entity.Payload)
+		if err != nil {
+			return err
+		}
+		if err := r.push(ctx, src, ev); err != nil {
+			return err
+		}
+	}
+}
+
+// decodeChangeEvent decodes the binlog pulled from a pump. The commit binlogs are sent by the pumps with the
+// prewrite value of the transaction, the other binlogs only tell the position.
+func decodeChangeEvent(payload []byte) (*ChangeEvent, error) {
+	var b binlog.Binlog
+	if err := b.Unmarshal(payload); err != nil {
+		return nil, errors.Trace(err)
+	}
+	ev := &ChangeEvent{StartTS: uint64(b.StartTs), CommitTS: uint64(b.CommitTs)}
+	if b.Tp != binlog.BinlogType_Commit {
+		return ev, nil
+	}
+	if b.DdlJobId > 0 {
+		ev.DDLJobID = b.DdlJobId
+		ev.DDLSchemaState = b.DdlSchemaState
+		ev.DDLQuery = string(b.DdlQuery)
+		return ev, nil
+	}
+	if len(b.PrewriteValue) > 0 {
+		var value binlog.PrewriteValue
+		if err := value.Unmarshal(b.PrewriteValue); err != nil {
+			return nil, errors.Trace(err)
+		}
+		ev.Mutations = value.Mutations
+	}
+	return ev, nil
+}
+
+// push adds the event pulled from the pump, it waits if too many events of the pump are not merged.
+func (r *ChangeReader) push(ctx context.Context, src *pumpSource, ev *ChangeEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for len(src.events) >= pumpBufferSize {
+		notify := r.notify
+		r.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			r.mu.Lock()
+			return ctx.Err()
+		case <-notify:
+		}
+		r.mu.Lock()
+	}
+	// the binlogs may be sent again after reconnecting.
+	if ev.CommitTS <= src.pulledTS {
+		return nil
+	}
+	src.pulledTS = ev.CommitTS
+	src.events = append(src.events, ev)
+	r.broadcast()
+	return nil
+}
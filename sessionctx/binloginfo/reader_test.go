@@ -0,0 +1,168 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package binloginfo_test
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb-tools/tidb-binlog/node"
+	pumpcli "github.com/pingcap/tidb-tools/tidb-binlog/pump_client"
+	"github.com/pingcap/tidb/sessionctx/binloginfo"
+	binlog "github.com/pingcap/tipb/go-binlog"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// mockPullPump sends the binlogs after the requested position, and closes the stream once they are sent.
+type mockPullPump struct {
+	mu      sync.Mutex
+	binlogs []*binlog.Binlog
+}
+
+func (p *mockPullPump) WriteBinlog(ctx context.Context, req *binlog.WriteBinlogReq) (*binlog.WriteBinlogResp, error) {
+	return &binlog.WriteBinlogResp{}, nil
+}
+
+func (p *mockPullPump) PullBinlogs(req *binlog.PullBinlogReq, srv binlog.Pump_PullBinlogsServer) error {
+	p.mu.Lock()
+	binlogs := append([]*binlog.Binlog(nil), p.binlogs...)
+	p.mu.Unlock()
+	for _, b := range binlogs {
+		if b.CommitTs <= req.StartFrom.Offset {
+			continue
+		}
+		payload, err := b.Marshal()
+		if err != nil {
+			return err
+		}
+		if err := srv.Send(&binlog.PullBinlogResp{Entity: binlog.Entity{Payload: payload}}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *mockPullPump) add(binlogs ...*binlog.Binlog) {
+	p.mu.Lock()
+	p.binlogs = append(p.binlogs, binlogs...)
+	p.mu.Unlock()
+}
+
+func startMockPullPump(t *testing.T) (*mockPullPump, string, func()) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	serv := grpc.NewServer()
+	pump := new(mockPullPump)
+	binlog.RegisterPumpServer(serv, pump)
+	go func() {
+		require.NoError(t, serv.Serve(l))
+	}()
+	return pump, l.Addr().String(), serv.Stop
+}
+
+func commitBinlog(t *testing.T, startTS, commitTS int64, tableID int64) *binlog.Binlog {
+	value := binlog.PrewriteValue{Mutations: []binlog.TableMutation{{TableId: tableID, InsertedRows: [][]byte{{1}}}}}
+	data, err := value.Marshal()
+	require.NoError(t, err)
+	return &binlog.Binlog{Tp: binlog.BinlogType_Commit, StartTs: startTS, CommitTs: commitTS, PrewriteValue: data}
+}
+
+func fakeBinlog(ts int64) *binlog.Binlog {
+	return &binlog.Binlog{Tp: binlog.BinlogType_Rollback, StartTs: ts, CommitTs: ts}
+}
+
+func nextChangeEvent(t *testing.T, r *binloginfo.ChangeReader) *binloginfo.ChangeEvent {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for {
+		if ev := r.TryNext(); ev != nil {
+			return ev
+		}
+		require.NoError(t, r.Wait(ctx))
+	}
+}
+
+func TestChangeReader(t *testing.T) {
+	binloginfo.PumpRetryInterval = 10 * time.Millisecond
+	binloginfo.PumpRefreshInterval = 10 * time.Millisecond
+	defer func() {
+		binloginfo.PumpRetryInterval = time.Second
+		binloginfo.PumpRefreshInterval = 10 * time.Second
+	}()
+
+	pump1, addr1, stop1 := startMockPullPump(t)
+	defer stop1()
+	pump2, addr2, stop2 := startMockPullPump(t)
+	defer stop2()
+	pump1.add(commitBinlog(t, 5, 10, 1),
+		&binlog.Binlog{Tp: binlog.BinlogType_Commit, StartTs: 25, CommitTs: 30, DdlJobId: 2, DdlSchemaState: 5, DdlQuery: []byte("create table t(a int)")},
+		fakeBinlog(50))
+	pump2.add(commitBinlog(t, 15, 20, 1), &binlog.Binlog{Tp: binlog.BinlogType_Rollback, StartTs: 22, CommitTs: 25}, fakeBinlog(40))
+
+	client := &pumpcli.PumpsClient{ClusterID: 1, Pumps: pumpcli.NewPumpInfos()}
+	client.Pumps.Pumps["pump1"] = pumpcli.NewPumpStatus(&node.Status{NodeID: "pump1", Addr: addr1, State: node.Online}, nil)
+	client.Pumps.Pumps["pump2"] = pumpcli.NewPumpStatus(&node.Status{NodeID: "pump2", Addr: addr2, State: node.Online}, nil)
+
+	// the binlogs of the pumps are merged in the commit TS order
+	r := binloginfo.NewChangeReader(client, 0)
+	ev := nextChangeEvent(t, r)
+	require.Equal(t, uint64(5), ev.StartTS)
+	require.Equal(t, uint64(10), ev.CommitTS)
+	require.Equal(t, int64(1), ev.Mutations[0].TableId)
+	ev = nextChangeEvent(t, r)
+	require.Equal(t, uint64(20), ev.CommitTS)
+	ev = nextChangeEvent(t, r)
+	require.Equal(t, uint64(30), ev.CommitTS)
+	require.Equal(t, int64(2), ev.DDLJobID)
+	require.Equal(t, int32(5), ev.DDLSchemaState)
+	require.Equal(t, "create table t(a int)", ev.DDLQuery)
+	require.Empty(t, ev.Mutations)
+	// the binlog at 50 waits for the next binlog of pump2
+	require.Eventually(t, func() bool {
+		return r.TryNext() == nil && r.ResolvedTS() == 40
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// the binlogs written after the stream is closed are pulled again from the last position
+	pump2.add(commitBinlog(t, 55, 60, 3), fakeBinlog(70))
+	pump1.add(fakeBinlog(80))
+	ev = nextChangeEvent(t, r)
+	require.Equal(t, uint64(60), ev.CommitTS)
+	require.Equal(t, int64(3), ev.Mutations[0].TableId)
+	require.Eventually(t, func() bool {
+		return r.TryNext() == nil && r.ResolvedTS() == 70
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// the paused pump doesn't block the reader once its binlogs are read
+	client.Lock()
+	client.Pumps.Pumps["pump2"].State = node.Paused
+	client.Pumps.Pumps["pump2"].MaxCommitTS = 70
+	client.Unlock()
+	require.Eventually(t, func() bool {
+		return r.TryNext() == nil && r.ResolvedTS() == 80
+	}, 5*time.Second, 10*time.Millisecond)
+	r.Close()
+
+	// resume from a position
+	r = binloginfo.NewChangeReader(client, 20)
+	defer r.Close()
+	ev = nextChangeEvent(t, r)
+	require.Equal(t, uint64(30), ev.CommitTS)
+	ev = nextChangeEvent(t, r)
+	require.Equal(t, uint64(60), ev.CommitTS)
+}
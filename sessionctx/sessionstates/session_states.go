@@ -0,0 +1,64 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sessionstates
+
+import (
+	"github.com/pingcap/tidb/errno"
+	"github.com/pingcap/tidb/parser/auth"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/dbterror"
+)
+
+var (
+	// ErrCannotMigrateSession is returned when the session has states that cannot be migrated, such as a transaction.
+	ErrCannotMigrateSession = dbterror.ClassSession.NewStd(errno.ErrCannotMigrateSession)
+	// ErrInvalidSessionStates is returned when the session states cannot be decoded or verified.
+	ErrInvalidSessionStates = dbterror.ClassSession.NewStd(errno.ErrInvalidSessionStates)
+)
+
+// SessionStates contains the states of a session that are migrated to another TiDB instance,
+// they are exported by SHOW SESSION_STATES and restored by SET SESSION_STATES.
+type SessionStates struct {
+	User        *auth.UserIdentity   `json:"user,omitempty"`
+	ActiveRoles []*auth.RoleIdentity `json:"active-roles,omitempty"`
+	CurrentDB   string               `json:"current-db,omitempty"`
+	// SystemVars only contains the session variables different from the global ones.
+	SystemVars   map[string]string           `json:"sys-vars,omitempty"`
+	UserVars     map[string]*types.Datum     `json:"user-vars,omitempty"`
+	UserVarTypes map[string]*types.FieldType `json:"user-var-types,omitempty"`
+	// PreparedStmts are indexed by the statement IDs, which are kept after the migration because the clients
+	// of the binary protocol refer to the statements by the IDs.
+	PreparedStmts        map[uint32]*PreparedStmtInfo `json:"prepared-stmts,omitempty"`
+	PreparedStmtID       uint32                       `json:"prepared-stmt-id,omitempty"`
+	LocalTemporaryTables []*TemporaryTableInfo        `json:"local-temp-tables,omitempty"`
+}
+
+// PreparedStmtInfo contains the information to prepare a statement again.
+type PreparedStmtInfo struct {
+	// Name is empty for the statements prepared by the binary protocol.
+	Name     string `json:"name,omitempty"`
+	StmtText string `json:"text"`
+	StmtDB   string `json:"db,omitempty"`
+	// ParamTypes are the parameter types sent by the first COM_STMT_EXECUTE of the binary protocol.
+	ParamTypes []byte `json:"param-types,omitempty"`
+}
+
+// TemporaryTableInfo contains the metadata of a local temporary table. Only the empty tables are migrated.
+type TemporaryTableInfo struct {
+	DB         *model.DBInfo    `json:"db"`
+	Table      *model.TableInfo `json:"table"`
+	AutoIDBase int64            `json:"auto-id-base,omitempty"`
+}
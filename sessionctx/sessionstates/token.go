@@ -0,0 +1,242 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sessionstates
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/util/logutil"
+	"go.uber.org/zap"
+)
+
+// TokenLifetime is how long a token exported by SHOW SESSION_STATES can be restored.
+const TokenLifetime = 15 * time.Minute
+
+// now is replaced in tests.
+var now = time.Now
+
+// token is the signed session states, it's encoded by base64 in SHOW SESSION_STATES.
+type token struct {
+	States     json.RawMessage `json:"states"`
+	ExpireTime time.Time       `json:"expire-time"`
+	Signature  []byte          `json:"signature"`
+}
+
+func (t *token) signedContent() []byte {
+	content := make([]byte, 0, len(t.States)+len(time.RFC3339Nano))
+	content = append(content, t.States...)
+	return append(content, t.ExpireTime.UTC().Format(time.RFC3339Nano)...)
+}
+
+// EncodeToken encodes the session states into a token signed by session-token-signing-key.
+func EncodeToken(states *SessionStates) (string, error) {
+	data, err := json.Marshal(states)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	t := &token{
+		States:     data,
+		ExpireTime: now().Add(TokenLifetime),
+	}
+	if t.Signature, err = globalSigningCert.sign(t.signedContent()); err != nil {
+		return "", err
+	}
+	data, err = json.Marshal(t)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+// DecodeToken verifies the token by session-token-signing-cert and decodes the session states from it.
+func DecodeToken(tokenStr string) (*SessionStates, error) {
+	data, err := base64.StdEncoding.DecodeString(tokenStr)
+	if err != nil {
+		return nil, ErrInvalidSessionStates.GenWithStackByArgs("malformed token")
+	}
+	t := &token{}
+	if err = json.Unmarshal(data, t); err != nil {
+		return nil, ErrInvalidSessionStates.GenWithStackByArgs("malformed token")
+	}
+	if now().After(t.ExpireTime) {
+		return nil, ErrInvalidSessionStates.GenWithStackByArgs("token expired")
+	}
+	if err = globalSigningCert.verify(t.signedContent(), t.Signature); err != nil {
+		return nil, err
+	}
+	states := &SessionStates{}
+	if err = json.Unmarshal(t.States, states); err != nil {
+		return nil, ErrInvalidSessionStates.GenWithStackByArgs(err.Error())
+	}
+	return states, nil
+}
+
+// expiringCert is a replaced certificate, it still verifies the tokens signed before the replacement.
+type expiringCert struct {
+	cert     *x509.Certificate
+	expireAt time.Time
+}
+
+// signingCert holds the certificate and key in session-token-signing-cert and session-token-signing-key.
+// The files are reloaded when they are modified, so that the certificate can be rotated without restarting.
+type signingCert struct {
+	sync.Mutex
+	certPath    string
+	keyPath     string
+	certModTime time.Time
+	keyModTime  time.Time
+	cert        *x509.Certificate
+	key         crypto.Signer
+	oldCerts    []expiringCert
+}
+
+var globalSigningCert signingCert
+
+// reload loads the certificate and key if the files are modified. A pair that fails to be loaded, which may be
+// caused by replacing the files one by one, is ignored as long as the previous pair is still available.
+func (sc *signingCert) reload() error {
+	security := config.GetGlobalConfig().Security
+	certPath, keyPath := security.SessionTokenSigningCert, security.SessionTokenSigningKey
+	if len(certPath) == 0 || len(keyPath) == 0 {
+		return ErrCannotMigrateSession.GenWithStackByArgs("session-token-signing-cert or session-token-signing-key is not set")
+	}
+	if certPath != sc.certPath || keyPath != sc.keyPath {
+		sc.certPath, sc.keyPath = certPath, keyPath
+		sc.cert, sc.key, sc.oldCerts = nil, nil, nil
+	}
+	certStat, err := os.Stat(certPath)
+	if err != nil {
+		return sc.keepOrFail(err)
+	}
+	keyStat, err := os.Stat(keyPath)
+	if err != nil {
+		return sc.keepOrFail(err)
+	}
+	if sc.cert != nil && certStat.ModTime().Equal(sc.certModTime) && keyStat.ModTime().Equal(sc.keyModTime) {
+		return nil
+	}
+	cert, key, err := loadSigningCert(certPath, keyPath)
+	if err != nil {
+		return sc.keepOrFail(err)
+	}
+
+	nowTime := now()
+	oldCerts := sc.oldCerts[:0]
+	for _, old := range sc.oldCerts {
+		if old.expireAt.After(nowTime) {
+			oldCerts = append(oldCerts, old)
+		}
+	}
+	if sc.cert != nil && !bytes.Equal(sc.cert.Raw, cert.Raw) {
+		oldCerts = append(oldCerts, expiringCert{cert: sc.cert, expireAt: nowTime.Add(TokenLifetime)})
+		logutil.BgLogger().Info("session token signing cert is rotated", zap.String("cert", certPath),
+			zap.Time("not-after", cert.NotAfter))
+	}
+	sc.oldCerts = oldCerts
+	sc.cert, sc.key = cert, key
+	sc.certModTime, sc.keyModTime = certStat.ModTime(), keyStat.ModTime()
+	return nil
+}
+
+func (sc *signingCert) keepOrFail(err error) error {
+	if sc.cert == nil {
+		return errors.Trace(err)
+	}
+	logutil.BgLogger().Warn("reload session token signing cert failed, keep the previous one",
+		zap.String("cert", sc.certPath), zap.String("key", sc.keyPath), zap.Error(err))
+	return nil
+}
+
+func loadSigningCert(certPath, keyPath string) (*x509.Certificate, crypto.Signer, error) {
+	certPEM, err := os.ReadFile(certPath)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	keyPEM, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	pair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	key, ok := pair.PrivateKey.(crypto.Signer)
+	if !ok {
+		return nil, nil, errors.Errorf("unsupported private key type %T", pair.PrivateKey)
+	}
+	return cert, key, nil
+}
+
+func (sc *signingCert) sign(content []byte) ([]byte, error) {
+	sc.Lock()
+	defer sc.Unlock()
+	if err := sc.reload(); err != nil {
+		return nil, err
+	}
+	if sc.cert.PublicKeyAlgorithm == x509.Ed25519 {
+		return sc.key.Sign(rand.Reader, content, crypto.Hash(0))
+	}
+	digest := sha256.Sum256(content)
+	return sc.key.Sign(rand.Reader, digest[:], crypto.SHA256)
+}
+
+func (sc *signingCert) verify(content, signature []byte) error {
+	sc.Lock()
+	defer sc.Unlock()
+	if err := sc.reload(); err != nil {
+		return err
+	}
+	if checkSignature(sc.cert, content, signature) == nil {
+		return nil
+	}
+	nowTime := now()
+	for _, old := range sc.oldCerts {
+		if old.expireAt.After(nowTime) && checkSignature(old.cert, content, signature) == nil {
+			return nil
+		}
+	}
+	return ErrInvalidSessionStates.GenWithStackByArgs("signature verification failed")
+}
+
+func checkSignature(cert *x509.Certificate, content, signature []byte) error {
+	var algo x509.SignatureAlgorithm
+	switch cert.PublicKeyAlgorithm {
+	case x509.RSA:
+		algo = x509.SHA256WithRSA
+	case x509.ECDSA:
+		algo = x509.ECDSAWithSHA256
+	case x509.Ed25519:
+		algo = x509.PureEd25519
+	default:
+		return errors.Errorf("unsupported public key algorithm %s", cert.PublicKeyAlgorithm)
+	}
+	return cert.CheckSignature(algo, content, signature)
+}
@@ -0,0 +1,139 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sessionstates
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/parser/auth"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util"
+	"github.com/stretchr/testify/require"
+)
+
+func setSigningCert(t *testing.T, dir string) (certPath, keyPath string) {
+	certPath, keyPath = filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	require.NoError(t, util.CreateTLSCertificates(certPath, keyPath, 2048))
+	config.UpdateGlobal(func(conf *config.Config) {
+		conf.Security.SessionTokenSigningCert = certPath
+		conf.Security.SessionTokenSigningKey = keyPath
+	})
+	return
+}
+
+func TestTokenRoundTrip(t *testing.T) {
+	defer config.RestoreFunc()()
+	_, err := EncodeToken(&SessionStates{})
+	require.True(t, ErrCannotMigrateSession.Equal(err))
+
+	setSigningCert(t, t.TempDir())
+	intDatum := types.NewIntDatum(10)
+	states := &SessionStates{
+		User:       &auth.UserIdentity{Username: "u1", Hostname: "%", AuthUsername: "u1", AuthHostname: "%"},
+		CurrentDB:  "test",
+		SystemVars: map[string]string{"sql_mode": "ANSI_QUOTES"},
+		UserVars:   map[string]*types.Datum{"a": &intDatum},
+		UserVarTypes: map[string]*types.FieldType{
+			"a": types.NewFieldType(mysql.TypeLonglong),
+		},
+		PreparedStmts: map[uint32]*PreparedStmtInfo{
+			3: {Name: "s", StmtText: "select ?", StmtDB: "test"},
+		},
+		PreparedStmtID: 3,
+	}
+	tokenStr, err := EncodeToken(states)
+	require.NoError(t, err)
+	decoded, err := DecodeToken(tokenStr)
+	require.NoError(t, err)
+	require.Equal(t, states.User, decoded.User)
+	require.Equal(t, states.SystemVars, decoded.SystemVars)
+	require.Equal(t, int64(10), decoded.UserVars["a"].GetInt64())
+	require.Equal(t, mysql.TypeLonglong, decoded.UserVarTypes["a"].Tp)
+	require.Equal(t, states.PreparedStmts, decoded.PreparedStmts)
+	require.Equal(t, uint32(3), decoded.PreparedStmtID)
+
+	// A modified token is rejected.
+	data, err := base64.StdEncoding.DecodeString(tokenStr)
+	require.NoError(t, err)
+	tk := &token{}
+	require.NoError(t, json.Unmarshal(data, tk))
+	tk.States = []byte(`{"user":{"Username":"root","Hostname":"%"}}`)
+	data, err = json.Marshal(tk)
+	require.NoError(t, err)
+	_, err = DecodeToken(base64.StdEncoding.EncodeToString(data))
+	require.True(t, ErrInvalidSessionStates.Equal(err))
+	_, err = DecodeToken("not a token")
+	require.True(t, ErrInvalidSessionStates.Equal(err))
+
+	// An expired token is rejected.
+	now = func() time.Time { return time.Now().Add(TokenLifetime + time.Minute) }
+	defer func() { now = time.Now }()
+	_, err = DecodeToken(tokenStr)
+	require.True(t, ErrInvalidSessionStates.Equal(err))
+}
+
+func TestTokenCertRotation(t *testing.T) {
+	defer config.RestoreFunc()()
+	certPath, keyPath := setSigningCert(t, t.TempDir())
+	oldToken, err := EncodeToken(&SessionStates{CurrentDB: "old"})
+	require.NoError(t, err)
+
+	// Rotate the certificate and key.
+	newDir := t.TempDir()
+	newCert, newKey := filepath.Join(newDir, "cert.pem"), filepath.Join(newDir, "key.pem")
+	require.NoError(t, util.CreateTLSCertificates(newCert, newKey, 2048))
+	require.NoError(t, os.Rename(newCert, certPath))
+	require.NoError(t, os.Rename(newKey, keyPath))
+	modTime := time.Now().Add(time.Second)
+	require.NoError(t, os.Chtimes(certPath, modTime, modTime))
+	require.NoError(t, os.Chtimes(keyPath, modTime, modTime))
+
+	// The rotated certificate still verifies the old token, and the new token is signed by the new key.
+	states, err := DecodeToken(oldToken)
+	require.NoError(t, err)
+	require.Equal(t, "old", states.CurrentDB)
+	newToken, err := EncodeToken(&SessionStates{CurrentDB: "new"})
+	require.NoError(t, err)
+	states, err = DecodeToken(newToken)
+	require.NoError(t, err)
+	require.Equal(t, "new", states.CurrentDB)
+
+	// The old certificate is dropped once the tokens signed by it expire.
+	now = func() time.Time { return time.Now().Add(TokenLifetime + time.Minute) }
+	defer func() { now = time.Now }()
+	require.NoError(t, globalSigningCert.verify([]byte("content"), mustSign(t, []byte("content"))))
+	require.NoError(t, os.Chtimes(certPath, modTime.Add(time.Second), modTime.Add(time.Second)))
+	require.NoError(t, globalSigningCert.reload())
+	require.Len(t, globalSigningCert.oldCerts, 0)
+
+	// A broken pair is ignored while the previous one is loaded.
+	require.NoError(t, os.WriteFile(keyPath, []byte("broken"), 0600))
+	require.NoError(t, os.Chtimes(keyPath, modTime.Add(2*time.Second), modTime.Add(2*time.Second)))
+	_, err = globalSigningCert.sign([]byte("content"))
+	require.NoError(t, err)
+}
+
+func mustSign(t *testing.T, content []byte) []byte {
+	signature, err := globalSigningCert.sign(content)
+	require.NoError(t, err)
+	return signature
+}
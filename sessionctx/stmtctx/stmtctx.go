@@ -86,11 +86,16 @@ type StatementContext struct {
 	AllowInvalidDate             bool
 	IgnoreNoPartition            bool
 	MaybeOverOptimized4PlanCache bool
-	IgnoreExplainIDSuffix        bool
+	// SkipPlanCacheReason is the reason why the plan of the cacheable statement is not cached.
+	SkipPlanCacheReason   string
+	IgnoreExplainIDSuffix bool
 	// If the select statement was like 'select * from t as of timestamp ...' or in a stale read transaction
 	// or is affected by the tidb_read_staleness session variable, then the statement will be makred as isStaleness
 	// in stmtCtx
 	IsStaleness bool
+	// MultiSchemaInfo is used in the multi-schema change of an ALTER TABLE statement,
+	// the DDL jobs of the specs are collected as its sub-jobs instead of being run one by one.
+	MultiSchemaInfo *model.MultiSchemaInfo
 	// mu struct holds variables that change during execution.
 	mu struct {
 		sync.Mutex
@@ -172,6 +177,8 @@ type StatementContext struct {
 	stmtCache map[StmtCacheKey]interface{}
 	// resourceGroupTag cache for the current statement resource group tag.
 	resourceGroupTag atomic.Value
+	// MemoryKilled is the memory quota that the statement is killed for exceeding, it's set by the memory killer.
+	MemoryKilled atomic2.String
 	// Map to store all CTE storages of current SQL.
 	// Will clean up at the end of the execution.
 	CTEStorageMap interface{}
@@ -291,6 +298,14 @@ func (sc *StatementContext) GetResourceGroupTag() []byte {
 	return tag
 }
 
+// SetSkipPlanCache marks the plan of the statement un-cacheable and records the reason.
+func (sc *StatementContext) SetSkipPlanCache(reason string) {
+	sc.MaybeOverOptimized4PlanCache = true
+	if sc.SkipPlanCacheReason == "" {
+		sc.SkipPlanCacheReason = reason
+	}
+}
+
 // SetPlanDigest sets the normalized plan and plan digest.
 func (sc *StatementContext) SetPlanDigest(normalized string, planDigest *parser.Digest) {
 	if planDigest != nil {
@@ -445,6 +460,43 @@ func (sc *StatementContext) AddTouchedRows(rows uint64) {
 	sc.mu.touched += rows
 }
 
+// RowCounters is a snapshot of the row counters of a statement.
+type RowCounters struct {
+	AffectedRows uint64
+	FoundRows    uint64
+	Records      uint64
+	Updated      uint64
+	Copied       uint64
+	Touched      uint64
+}
+
+// GetRowCounters returns a snapshot of the row counters.
+func (sc *StatementContext) GetRowCounters() RowCounters {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	return RowCounters{
+		AffectedRows: sc.mu.affectedRows,
+		FoundRows:    sc.mu.foundRows,
+		Records:      sc.mu.records,
+		Updated:      sc.mu.updated,
+		Copied:       sc.mu.copied,
+		Touched:      sc.mu.touched,
+	}
+}
+
+// SetRowCounters restores the row counters from a snapshot, it is used by the
+// foreign key cascades which must not change the counters of the outer statement.
+func (sc *StatementContext) SetRowCounters(c RowCounters) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.mu.affectedRows = c.AffectedRows
+	sc.mu.foundRows = c.FoundRows
+	sc.mu.records = c.Records
+	sc.mu.updated = c.Updated
+	sc.mu.copied = c.Copied
+	sc.mu.touched = c.Touched
+}
+
 // GetMessage returns the extra message of the last executed command, if there is no message, it returns empty string
 func (sc *StatementContext) GetMessage() string {
 	sc.mu.Lock()
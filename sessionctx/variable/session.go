@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"crypto/tls"
 	"encoding/binary"
+	"encoding/json"
 	"fmt"
 	"math"
 	"math/rand"
@@ -41,10 +42,15 @@ import (
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/sessionctx/sessionstates"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/types"
 	"github.com/pingcap/tidb/util/chunk"
+	"github.com/pingcap/tidb/util/disk"
 	"github.com/pingcap/tidb/util/execdetails"
+	"github.com/pingcap/tidb/util/hack"
+	"github.com/pingcap/tidb/util/kvcache"
+	"github.com/pingcap/tidb/util/memory"
 	"github.com/pingcap/tidb/util/rowcodec"
 	"github.com/pingcap/tidb/util/stringutil"
 	"github.com/pingcap/tidb/util/tableutil"
@@ -505,6 +511,10 @@ type SessionVars struct {
 	// User is the user identity with which the session login.
 	User *auth.UserIdentity
 
+	// InSandBoxMode indicates the password of the user has expired, only the statements to reset
+	// the password are allowed in the session.
+	InSandBoxMode bool
+
 	// Port is the port of the connected socket
 	Port string
 
@@ -546,6 +556,15 @@ type SessionVars struct {
 	// StmtCtx holds variables for current executing statement.
 	StmtCtx *stmtctx.StatementContext
 
+	// MemTracker tracks the memory usage which lives across statements in the session, such as the
+	// result sets of server-side cursors.
+	MemTracker *memory.Tracker
+	// DiskTracker tracks the disk usage which lives across statements in the session.
+	DiskTracker *disk.Tracker
+	// MemQuotaSession is the memory quota of the session, which covers the running statement and the memory
+	// tracked by MemTracker. 0 means no limit.
+	MemQuotaSession int64
+
 	// AllowAggPushDown can be set to false to forbid aggregation push down.
 	AllowAggPushDown bool
 
@@ -715,6 +734,10 @@ type SessionVars struct {
 	// EnablePlacementChecks indicates whether a user can check validation of placement.
 	EnablePlacementChecks bool
 
+	// ForeignKeyChecks indicates whether the foreign key constraints are checked and the referential actions
+	// are performed when writing rows.
+	ForeignKeyChecks bool
+
 	// WaitSplitRegionFinish defines the split region behaviour is sync or async.
 	WaitSplitRegionFinish bool
 
@@ -763,6 +786,13 @@ type SessionVars struct {
 	// ConnectionInfo indicates current connection info used by current session, only be lazy assigned by plugin.
 	ConnectionInfo *ConnectionInfo
 
+	// ConnectionAttrs are the connection attributes sent by the client in the handshake.
+	ConnectionAttrs map[string]string
+
+	// QueryAttributes are the attributes sent by the client with the current COM_QUERY or COM_STMT_EXECUTE,
+	// the attributes with NULL values are not included.
+	QueryAttributes map[string]string
+
 	// NoopFuncsMode allows OFF/ON/WARN values as 0/1/2.
 	NoopFuncsMode int
 
@@ -933,6 +963,16 @@ type SessionVars struct {
 	// EnablePseudoForOutdatedStats if using pseudo for outdated stats
 	EnablePseudoForOutdatedStats bool
 
+	// EnableNonPreparedPlanCache indicates whether to cache the plans of the statements sent by the text protocol.
+	EnableNonPreparedPlanCache bool
+
+	// NonPreparedPlanCacheSize is the number of the parameterized statements cached by the non-prepared plan cache.
+	NonPreparedPlanCacheSize int
+
+	// nonPreparedPlanCacheStmts stores the parameterized statements of the non-prepared plan cache, the values are
+	// *core.CachedPrepareStmt, use interface to avoid circle dependency.
+	nonPreparedPlanCacheStmts *kvcache.SimpleLRUCache
+
 	// LocalTemporaryTables is *infoschema.LocalTemporaryTables, use interface to avoid circle dependency.
 	// It's nil if there is no local temporary table.
 	LocalTemporaryTables interface{}
@@ -1095,6 +1135,8 @@ type ConnectionInfo struct {
 	SSLVersion        string
 	PID               int
 	DB                string
+	// Attributes are the connection attributes sent by the client in the handshake.
+	Attributes map[string]string
 }
 
 // NewSessionVars creates a session vars object.
@@ -1115,6 +1157,8 @@ func NewSessionVars() *SessionVars {
 		AutoIncrementOffset:         DefAutoIncrementOffset,
 		Status:                      mysql.ServerStatusAutocommit,
 		StmtCtx:                     new(stmtctx.StatementContext),
+		MemTracker:                  memory.NewTracker(memory.LabelForSession, -1),
+		DiskTracker:                 disk.NewTracker(memory.LabelForSession, -1),
 		AllowAggPushDown:            false,
 		AllowBCJ:                    false,
 		AllowCartesianBCJ:           DefOptCartesianBCJ,
@@ -1184,9 +1228,11 @@ func NewSessionVars() *SessionVars {
 		AllowFallbackToTiKV:         make(map[kv.StoreType]struct{}),
 		CTEMaxRecursionDepth:        DefCTEMaxRecursionDepth,
 		TMPTableSize:                DefTiDBTmpTableMaxSize,
+		NonPreparedPlanCacheSize:    DefTiDBNonPreparedPlanCacheSize,
 		MPPStoreLastFailTime:        make(map[string]time.Time),
 		MPPStoreFailTTL:             DefTiDBMPPStoreFailTTL,
 		EnablePlacementChecks:       DefEnablePlacementCheck,
+		ForeignKeyChecks:            DefForeignKeyChecks,
 	}
 	vars.KVVars = tikvstore.NewVariables(&vars.Killed)
 	vars.Concurrency = Concurrency{
@@ -1544,6 +1590,33 @@ func (s *SessionVars) RemovePreparedStmt(stmtID uint32) {
 	metrics.PreparedStmtGauge.Set(float64(afterMinus))
 }
 
+// nonPreparedPlanCacheStmtKey is the key of the statements cached by the non-prepared plan cache.
+type nonPreparedPlanCacheStmtKey string
+
+// Hash implements kvcache.Key interface.
+func (k nonPreparedPlanCacheStmtKey) Hash() []byte {
+	return hack.Slice(string(k))
+}
+
+// GetNonPreparedPlanCacheStmt returns the statement cached by the non-prepared plan cache, it returns nil if the
+// statement isn't cached.
+func (s *SessionVars) GetNonPreparedPlanCacheStmt(sql string) interface{} {
+	if s.nonPreparedPlanCacheStmts == nil {
+		return nil
+	}
+	stmt, _ := s.nonPreparedPlanCacheStmts.Get(nonPreparedPlanCacheStmtKey(sql))
+	return stmt
+}
+
+// AddNonPreparedPlanCacheStmt adds the statement to the non-prepared plan cache, the least recently used statement
+// is evicted if there are more than NonPreparedPlanCacheSize statements.
+func (s *SessionVars) AddNonPreparedPlanCacheStmt(sql string, stmt interface{}) {
+	if s.nonPreparedPlanCacheStmts == nil {
+		s.nonPreparedPlanCacheStmts = kvcache.NewSimpleLRUCache(uint(s.NonPreparedPlanCacheSize), 0, 0)
+	}
+	s.nonPreparedPlanCacheStmts.Put(nonPreparedPlanCacheStmtKey(sql), stmt)
+}
+
 // WithdrawAllPreparedStmt remove all preparedStmt in current session and decrease count in global.
 func (s *SessionVars) WithdrawAllPreparedStmt() {
 	psCount := len(s.PreparedStmts)
@@ -1554,6 +1627,79 @@ func (s *SessionVars) WithdrawAllPreparedStmt() {
 	metrics.PreparedStmtGauge.Set(float64(afterMinus))
 }
 
+// EncodeSessionStates saves the system variables, the user variables, the current database and the prepared
+// statement ID of the session into the session states.
+func (s *SessionVars) EncodeSessionStates(sessionStates *sessionstates.SessionStates) error {
+	sessionStates.CurrentDB = s.CurrentDB
+	sessionStates.PreparedStmtID = s.preparedStmtID
+
+	s.UsersLock.RLock()
+	sessionStates.UserVars = make(map[string]*types.Datum, len(s.Users))
+	for name, d := range s.Users {
+		sessionStates.UserVars[name] = d.Clone()
+	}
+	sessionStates.UserVarTypes = make(map[string]*types.FieldType, len(s.UserVarTypes))
+	for name, ft := range s.UserVarTypes {
+		sessionStates.UserVarTypes[name] = ft.Clone()
+	}
+	s.UsersLock.RUnlock()
+
+	// Only the variables different from the global ones are saved. The session-only variables with getters
+	// reflect the TiDB instance or the last statement rather than the session, so they are not saved.
+	sessionStates.SystemVars = make(map[string]string)
+	for name, sv := range GetSysVars() {
+		if !sv.HasSessionScope() || sv.ReadOnly || (!sv.HasGlobalScope() && sv.GetSession != nil) {
+			continue
+		}
+		val, err := GetSessionOrGlobalSystemVar(s, name)
+		if err != nil {
+			return err
+		}
+		defVal := sv.Value
+		if sv.HasGlobalScope() {
+			if defVal, err = GetGlobalSystemVar(s, name); err != nil {
+				return err
+			}
+		}
+		if val != defVal {
+			sessionStates.SystemVars[name] = val
+		}
+	}
+	return nil
+}
+
+// DecodeSessionStates restores the states saved by EncodeSessionStates into the session.
+func (s *SessionVars) DecodeSessionStates(sessionStates *sessionstates.SessionStates) error {
+	names := make([]string, 0, len(sessionStates.SystemVars))
+	for name := range sessionStates.SystemVars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := SetSessionSystemVar(s, name, sessionStates.SystemVars[name]); err != nil {
+			return err
+		}
+	}
+
+	s.UsersLock.Lock()
+	for name, d := range sessionStates.UserVars {
+		s.Users[name] = *d
+	}
+	for name, ft := range sessionStates.UserVarTypes {
+		s.UserVarTypes[name] = ft
+	}
+	s.UsersLock.Unlock()
+
+	if s.CurrentDB != sessionStates.CurrentDB {
+		s.CurrentDB = sessionStates.CurrentDB
+		s.CurrentDBChanged = true
+	}
+	if s.preparedStmtID < sessionStates.PreparedStmtID {
+		s.preparedStmtID = sessionStates.PreparedStmtID
+	}
+	return nil
+}
+
 // SetStmtVar sets the value of a system variable temporarily
 func (s *SessionVars) SetStmtVar(name string, val string) error {
 	s.stmtVars[name] = val
@@ -1565,6 +1711,17 @@ func (s *SessionVars) ClearStmtVars() {
 	s.stmtVars = make(map[string]string)
 }
 
+// ResetMemTrackerBytesLimit sets the bytes limit of MemTracker, the open cursors spill to disk once they exceed it.
+// It is the smaller one of tidb_mem_quota_query and tidb_mem_quota_session, so the cursors spill before the
+// session is killed for exceeding tidb_mem_quota_session.
+func (s *SessionVars) ResetMemTrackerBytesLimit() {
+	limit := s.MemQuotaQuery
+	if s.MemQuotaSession > 0 && (limit <= 0 || s.MemQuotaSession < limit) {
+		limit = s.MemQuotaSession
+	}
+	s.MemTracker.SetBytesLimit(limit)
+}
+
 // SetSystemVar sets the value of a system variable for session scope.
 // Validation is expected to be performed before calling this function,
 // and the values should been normalized.
@@ -1963,6 +2120,8 @@ const (
 	SlowLogMemMax = "Mem_max"
 	// SlowLogDiskMax is the nax number bytes of disk used in this statement.
 	SlowLogDiskMax = "Disk_max"
+	// SlowLogMemQuotaKilled is the memory quota that the statement is killed for exceeding.
+	SlowLogMemQuotaKilled = "Mem_quota_killed"
 	// SlowLogPrepared is used to indicate whether this sql execute in prepare.
 	SlowLogPrepared = "Prepared"
 	// SlowLogPlanFromCache is used to indicate whether this plan is from plan cache.
@@ -2003,6 +2162,10 @@ const (
 	SlowLogResultRows = "Result_rows"
 	// SlowLogIsExplicitTxn is used to indicate whether this sql execute in explicit transaction or not.
 	SlowLogIsExplicitTxn = "IsExplicitTxn"
+	// SlowLogQueryAttributes is the query attributes sent with the statement, in JSON.
+	SlowLogQueryAttributes = "Query_attributes"
+	// SlowLogConnAttributes is the connection attributes sent in the handshake, in JSON.
+	SlowLogConnAttributes = "Conn_attributes"
 )
 
 // SlowQueryLogItems is a collection of items that should be included in the
@@ -2022,6 +2185,7 @@ type SlowQueryLogItems struct {
 	ExecDetail        execdetails.ExecDetails
 	MemMax            int64
 	DiskMax           int64
+	MemoryKilled      string
 	Succ              bool
 	Prepared          bool
 	PlanFromCache     bool
@@ -2192,6 +2356,9 @@ func (s *SessionVars) SlowLogFormat(logItems *SlowQueryLogItems) string {
 	if logItems.DiskMax > 0 {
 		writeSlowLogItem(&buf, SlowLogDiskMax, strconv.FormatInt(logItems.DiskMax, 10))
 	}
+	if len(logItems.MemoryKilled) > 0 {
+		writeSlowLogItem(&buf, SlowLogMemQuotaKilled, logItems.MemoryKilled)
+	}
 
 	writeSlowLogItem(&buf, SlowLogPrepared, strconv.FormatBool(logItems.Prepared))
 	writeSlowLogItem(&buf, SlowLogPlanFromCache, strconv.FormatBool(logItems.PlanFromCache))
@@ -2204,6 +2371,12 @@ func (s *SessionVars) SlowLogFormat(logItems *SlowQueryLogItems) string {
 	writeSlowLogItem(&buf, SlowLogResultRows, strconv.FormatInt(logItems.ResultRows, 10))
 	writeSlowLogItem(&buf, SlowLogSucc, strconv.FormatBool(logItems.Succ))
 	writeSlowLogItem(&buf, SlowLogIsExplicitTxn, strconv.FormatBool(logItems.IsExplicitTxn))
+	if len(s.QueryAttributes) > 0 {
+		writeSlowLogAttributes(&buf, SlowLogQueryAttributes, s.QueryAttributes)
+	}
+	if len(s.ConnectionAttrs) > 0 {
+		writeSlowLogAttributes(&buf, SlowLogConnAttributes, s.ConnectionAttrs)
+	}
 	if len(logItems.Plan) != 0 {
 		writeSlowLogItem(&buf, SlowLogPlan, logItems.Plan)
 	}
@@ -2227,6 +2400,15 @@ func (s *SessionVars) SlowLogFormat(logItems *SlowQueryLogItems) string {
 	return buf.String()
 }
 
+// writeSlowLogAttributes writes the attributes as a JSON object, which keeps the item in one line.
+func writeSlowLogAttributes(buf *bytes.Buffer, key string, attrs map[string]string) {
+	value, err := json.Marshal(attrs)
+	if err != nil {
+		return
+	}
+	writeSlowLogItem(buf, key, string(value))
+}
+
 // writeSlowLogItem writes a slow log item in the form of: "# ${key}:${value}"
 func writeSlowLogItem(buf *bytes.Buffer, key, value string) {
 	buf.WriteString(SlowLogRowPrefixStr + key + SlowLogSpaceMarkStr + value + "\n")
@@ -155,6 +155,8 @@ func TestSlowLogFormat(t *testing.T) {
 	seVar.ConnectionID = 1
 	seVar.CurrentDB = "test"
 	seVar.InRestrictedSQL = true
+	seVar.QueryAttributes = map[string]string{"request_id": "r1", "b": ""}
+	seVar.ConnectionAttrs = map[string]string{"_client_name": "libmysql"}
 	txnTS := uint64(406649736972468225)
 	costTime := time.Second
 	execDetail := execdetails.ExecDetails{
@@ -225,6 +227,7 @@ func TestSlowLogFormat(t *testing.T) {
 # Cop_backoff_rpcTiKV_total_times: 200 Cop_backoff_rpcTiKV_total_time: 0.2 Cop_backoff_rpcTiKV_max_time: 0.2 Cop_backoff_rpcTiKV_max_addr: 127.0.0.1 Cop_backoff_rpcTiKV_avg_time: 0.2 Cop_backoff_rpcTiKV_p90_time: 0.2
 # Mem_max: 2333
 # Disk_max: 6666
+# Mem_quota_killed: session
 # Prepared: true
 # Plan_from_cache: true
 # Plan_from_binding: true
@@ -235,7 +238,9 @@ func TestSlowLogFormat(t *testing.T) {
 # Write_sql_response_total: 1
 # Result_rows: 12345
 # Succ: true
-# IsExplicitTxn: true`
+# IsExplicitTxn: true
+# Query_attributes: {"b":"","request_id":"r1"}
+# Conn_attributes: {"_client_name":"libmysql"}`
 	sql := "select * from t;"
 	_, digest := parser.NormalizeDigest(sql)
 	logItems := &variable.SlowQueryLogItems{
@@ -253,6 +258,7 @@ func TestSlowLogFormat(t *testing.T) {
 		ExecDetail:        execDetail,
 		MemMax:            memMax,
 		DiskMax:           diskMax,
+		MemoryKilled:      "session",
 		Prepared:          true,
 		PlanFromCache:     true,
 		PlanFromBinding:   true,
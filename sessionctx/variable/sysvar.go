@@ -644,16 +644,9 @@ var defaultSysVars = []*SysVar{
 		return nil
 	}},
 	{Scope: ScopeNone, Name: SystemTimeZone, Value: "CST"},
-	{Scope: ScopeGlobal | ScopeSession, Name: ForeignKeyChecks, Value: Off, Type: TypeBool, skipInit: true, Validation: func(vars *SessionVars, normalizedValue string, originalValue string, scope ScopeFlag) (string, error) {
-		if TiDBOptOn(normalizedValue) {
-			// TiDB does not yet support foreign keys.
-			// Return the original value in the warning, so that users are not confused.
-			vars.StmtCtx.AppendWarning(ErrUnsupportedValueForVar.GenWithStackByArgs(ForeignKeyChecks, originalValue))
-			return Off, nil
-		} else if !TiDBOptOn(normalizedValue) {
-			return Off, nil
-		}
-		return normalizedValue, ErrWrongValueForVar.GenWithStackByArgs(ForeignKeyChecks, originalValue)
+	{Scope: ScopeGlobal | ScopeSession, Name: ForeignKeyChecks, Value: BoolToOnOff(DefForeignKeyChecks), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.ForeignKeyChecks = TiDBOptOn(val)
+		return nil
 	}},
 	{Scope: ScopeGlobal | ScopeSession, Name: PlacementChecks, Value: On, Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
 		s.EnablePlacementChecks = TiDBOptOn(val)
@@ -1081,6 +1074,7 @@ var defaultSysVars = []*SysVar{
 	}},
 	{Scope: ScopeSession, Name: TiDBMemQuotaQuery, Value: strconv.FormatInt(config.GetGlobalConfig().MemQuotaQuery, 10), skipInit: true, Type: TypeInt, MinValue: -1, MaxValue: math.MaxInt64, SetSession: func(s *SessionVars, val string) error {
 		s.MemQuotaQuery = tidbOptInt64(val, config.GetGlobalConfig().MemQuotaQuery)
+		s.ResetMemTrackerBytesLimit()
 		return nil
 	}},
 	{Scope: ScopeSession, Name: TiDBMemQuotaHashJoin, Value: strconv.FormatInt(DefTiDBMemQuotaHashJoin, 10), skipInit: true, Type: TypeInt, MinValue: -1, MaxValue: math.MaxInt64, SetSession: func(s *SessionVars, val string) error {
@@ -1207,6 +1201,11 @@ var defaultSysVars = []*SysVar{
 		s.MemQuotaApplyCache = tidbOptInt64(val, DefTiDBMemQuotaApplyCache)
 		return nil
 	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBMemQuotaSession, Value: strconv.Itoa(DefTiDBMemQuotaSession), Type: TypeUnsigned, MaxValue: math.MaxInt64, SetSession: func(s *SessionVars, val string) error {
+		s.MemQuotaSession = tidbOptInt64(val, DefTiDBMemQuotaSession)
+		s.ResetMemTrackerBytesLimit()
+		return nil
+	}},
 	{Scope: ScopeGlobal | ScopeSession, Name: TiDBBackoffLockFast, Value: strconv.Itoa(tikvstore.DefBackoffLockFast), Type: TypeUnsigned, MinValue: 1, MaxValue: math.MaxInt32, SetSession: func(s *SessionVars, val string) error {
 		s.KVVars.BackoffLockFast = tidbOptPositiveInt32(val, tikvstore.DefBackoffLockFast)
 		return nil
@@ -1433,6 +1432,12 @@ var defaultSysVars = []*SysVar{
 	}, GetSession: func(s *SessionVars) (string, error) {
 		return fmt.Sprintf("%g", MemoryUsageAlarmRatio.Load()), nil
 	}},
+	{Scope: ScopeGlobal, Name: TiDBServerMemoryLimit, Value: strconv.Itoa(DefTiDBServerMemoryLimit), Type: TypeUnsigned, MaxValue: math.MaxInt64, GetGlobal: func(sv *SessionVars) (string, error) {
+		return strconv.FormatInt(ServerMemoryLimit.Load(), 10), nil
+	}, SetGlobal: func(s *SessionVars, val string) error {
+		ServerMemoryLimit.Store(tidbOptInt64(val, DefTiDBServerMemoryLimit))
+		return nil
+	}},
 	{Scope: ScopeGlobal | ScopeSession, Name: TiDBEnableNoopFuncs, Value: DefTiDBEnableNoopFuncs, Type: TypeEnum, PossibleValues: []string{Off, On, Warn}, Validation: func(vars *SessionVars, normalizedValue string, originalValue string, scope ScopeFlag) (string, error) {
 
 		// The behavior is very weird if someone can turn TiDBEnableNoopFuncs OFF, but keep any of the following on:
@@ -1781,6 +1786,29 @@ var defaultSysVars = []*SysVar{
 		s.TMPTableSize = tidbOptInt64(val, DefTiDBTmpTableMaxSize)
 		return nil
 	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBEnableNonPreparedPlanCache, Value: BoolToOnOff(DefTiDBEnableNonPreparedPlanCache), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
+		s.EnableNonPreparedPlanCache = TiDBOptOn(val)
+		return nil
+	}},
+	{Scope: ScopeGlobal | ScopeSession, Name: TiDBNonPreparedPlanCacheSize, Value: strconv.Itoa(DefTiDBNonPreparedPlanCacheSize), Type: TypeUnsigned, MinValue: 1, MaxValue: 100000, SetSession: func(s *SessionVars, val string) error {
+		s.NonPreparedPlanCacheSize = tidbOptPositiveInt32(val, DefTiDBNonPreparedPlanCacheSize)
+		if s.nonPreparedPlanCacheStmts != nil {
+			return s.nonPreparedPlanCacheStmts.SetCapacity(uint(s.NonPreparedPlanCacheSize))
+		}
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: TiDBEnableInstancePlanCache, Value: BoolToOnOff(DefTiDBEnableInstancePlanCache), Type: TypeBool, GetGlobal: func(s *SessionVars) (string, error) {
+		return BoolToOnOff(EnableInstancePlanCache.Load()), nil
+	}, SetGlobal: func(s *SessionVars, val string) error {
+		EnableInstancePlanCache.Store(TiDBOptOn(val))
+		return nil
+	}},
+	{Scope: ScopeGlobal, Name: TiDBInstancePlanCacheMaxMemSize, Value: strconv.Itoa(DefTiDBInstancePlanCacheMaxMemSize), Type: TypeUnsigned, MinValue: 1, MaxValue: math.MaxInt64, GetGlobal: func(s *SessionVars) (string, error) {
+		return strconv.FormatInt(InstancePlanCacheMaxMemSize.Load(), 10), nil
+	}, SetGlobal: func(s *SessionVars, val string) error {
+		InstancePlanCacheMaxMemSize.Store(tidbOptInt64(val, DefTiDBInstancePlanCacheMaxMemSize))
+		return nil
+	}},
 	// variable for top SQL feature.
 	{Scope: ScopeGlobal, Name: TiDBEnableTopSQL, Value: BoolToOnOff(DefTiDBTopSQLEnable), Type: TypeBool, Hidden: true, AllowEmpty: true, GetGlobal: func(s *SessionVars) (string, error) {
 		return BoolToOnOff(TopSQLVariable.Enable.Load()), nil
@@ -1830,6 +1858,33 @@ var defaultSysVars = []*SysVar{
 	}},
 	{Scope: ScopeGlobal, Name: SkipNameResolve, Value: Off, Type: TypeBool},
 	{Scope: ScopeGlobal, Name: DefaultAuthPlugin, Value: mysql.AuthNativePassword, Type: TypeEnum, PossibleValues: []string{mysql.AuthNativePassword, mysql.AuthCachingSha2Password}},
+	{Scope: ScopeGlobal, Name: DefaultPasswordLifetime, Value: "0", Type: TypeUnsigned, MinValue: 0, MaxValue: math.MaxUint16},
+	{Scope: ScopeNone, Name: DisconnectOnExpiredPassword, Value: On, Type: TypeBool},
+	{Scope: ScopeGlobal, Name: PasswordHistory, Value: "0", Type: TypeUnsigned, MinValue: 0, MaxValue: math.MaxUint32},
+	{Scope: ScopeGlobal, Name: PasswordReuseInterval, Value: "0", Type: TypeUnsigned, MinValue: 0, MaxValue: math.MaxUint32},
+	{Scope: ScopeGlobal, Name: ValidatePasswordEnable, Value: Off, Type: TypeBool},
+	{Scope: ScopeGlobal, Name: ValidatePasswordPolicy, Value: "MEDIUM", Type: TypeEnum, PossibleValues: []string{"LOW", "MEDIUM", "STRONG"}},
+	{Scope: ScopeGlobal, Name: ValidatePasswordCheckUserName, Value: Off, Type: TypeBool},
+	{Scope: ScopeGlobal, Name: ValidatePasswordLength, Value: "8", Type: TypeUnsigned, MinValue: 0, MaxValue: math.MaxInt32},
+	{Scope: ScopeGlobal, Name: ValidatePasswordMixedCaseCount, Value: "1", Type: TypeUnsigned, MinValue: 0, MaxValue: math.MaxInt32},
+	{Scope: ScopeGlobal, Name: ValidatePasswordNumberCount, Value: "1", Type: TypeUnsigned, MinValue: 0, MaxValue: math.MaxInt32},
+	{Scope: ScopeGlobal, Name: ValidatePasswordSpecialCharCount, Value: "1", Type: TypeUnsigned, MinValue: 0, MaxValue: math.MaxInt32},
+	{Scope: ScopeGlobal, Name: ValidatePasswordDictionaryFile, Value: ""},
+	{Scope: ScopeGlobal, Name: ProtocolCompressionAlgorithms, Value: DefProtocolCompressionAlgorithms, Validation: func(vars *SessionVars, normalizedValue string, originalValue string, scope ScopeFlag) (string, error) {
+		algorithms := strings.Split(strings.ToLower(normalizedValue), ",")
+		for i, algorithm := range algorithms {
+			algorithms[i] = strings.TrimSpace(algorithm)
+			switch algorithms[i] {
+			case "zlib", "zstd", "uncompressed":
+			default:
+				return normalizedValue, ErrWrongValueForVar.GenWithStackByArgs(ProtocolCompressionAlgorithms, originalValue)
+			}
+		}
+		return strings.Join(algorithms, ","), nil
+	}, SetGlobal: func(s *SessionVars, val string) error {
+		ProtocolCompressionAlgorithmsValue.Store(val)
+		return nil
+	}},
 	{Scope: ScopeGlobal | ScopeSession, Name: TiDBEnableOrderedResultMode, Value: BoolToOnOff(DefTiDBEnableOrderedResultMode), Type: TypeBool, SetSession: func(s *SessionVars, val string) error {
 		s.EnableStableResultMode = TiDBOptOn(val)
 		return nil
@@ -1978,10 +2033,30 @@ const (
 	BlockEncryptionMode = "block_encryption_mode"
 	// WaitTimeout is the name for 'wait_timeout' system variable.
 	WaitTimeout = "wait_timeout"
+	// ValidatePasswordEnable is the name of 'validate_password_enable' system variable.
+	ValidatePasswordEnable = "validate_password_enable"
+	// ValidatePasswordPolicy is the name of 'validate_password_policy' system variable.
+	ValidatePasswordPolicy = "validate_password_policy"
 	// ValidatePasswordNumberCount is the name of 'validate_password_number_count' system variable.
 	ValidatePasswordNumberCount = "validate_password_number_count"
 	// ValidatePasswordLength is the name of 'validate_password_length' system variable.
 	ValidatePasswordLength = "validate_password_length"
+	// ValidatePasswordMixedCaseCount is the name of 'validate_password_mixed_case_count' system variable.
+	ValidatePasswordMixedCaseCount = "validate_password_mixed_case_count"
+	// ValidatePasswordSpecialCharCount is the name of 'validate_password_special_char_count' system variable.
+	ValidatePasswordSpecialCharCount = "validate_password_special_char_count"
+	// ValidatePasswordDictionaryFile is the name of 'validate_password_dictionary_file' system variable.
+	ValidatePasswordDictionaryFile = "validate_password_dictionary_file"
+	// DefaultPasswordLifetime is the name of 'default_password_lifetime' system variable.
+	DefaultPasswordLifetime = "default_password_lifetime"
+	// DisconnectOnExpiredPassword is the name of 'disconnect_on_expired_password' system variable.
+	DisconnectOnExpiredPassword = "disconnect_on_expired_password"
+	// PasswordHistory is the name of 'password_history' system variable.
+	PasswordHistory = "password_history"
+	// PasswordReuseInterval is the name of 'password_reuse_interval' system variable.
+	PasswordReuseInterval = "password_reuse_interval"
+	// ProtocolCompressionAlgorithms is the name of 'protocol_compression_algorithms' system variable.
+	ProtocolCompressionAlgorithms = "protocol_compression_algorithms"
 	// Version is the name of 'version' system variable.
 	Version = "version"
 	// VersionComment is the name of 'version_comment' system variable.
@@ -110,6 +110,9 @@ const (
 	// "tidb_mem_quota_query":				control the memory quota of a query.
 	TiDBMemQuotaQuery      = "tidb_mem_quota_query" // Bytes.
 	TiDBMemQuotaApplyCache = "tidb_mem_quota_apply_cache"
+	// TiDBMemQuotaSession is the memory quota of a session, the running statement of the session is killed
+	// when the memory used by the session exceeds it.
+	TiDBMemQuotaSession = "tidb_mem_quota_session" // Bytes.
 	// TODO: remove them below sometime, it should have only one Quota(TiDBMemQuotaQuery).
 	TiDBMemQuotaHashJoin          = "tidb_mem_quota_hashjoin"          // Bytes.
 	TiDBMemQuotaMergeJoin         = "tidb_mem_quota_mergejoin"         // Bytes.
@@ -538,6 +541,10 @@ const (
 	// TiDBMemoryUsageAlarmRatio indicates the alarm threshold when memory usage of the tidb-server exceeds.
 	TiDBMemoryUsageAlarmRatio = "tidb_memory_usage_alarm_ratio"
 
+	// TiDBServerMemoryLimit is the memory limit of the tidb-server instance, the statement using the most memory
+	// is killed when the memory tracked in the instance exceeds it.
+	TiDBServerMemoryLimit = "tidb_server_memory_limit"
+
 	// TiDBEnableRateLimitAction indicates whether enabled ratelimit action
 	TiDBEnableRateLimitAction = "tidb_enable_rate_limit_action"
 
@@ -597,11 +604,24 @@ const (
 
 	// TiDBTmpTableMaxSize indicates the max memory size of temporary tables.
 	TiDBTmpTableMaxSize = "tidb_tmp_table_max_size"
+
+	// TiDBEnableNonPreparedPlanCache indicates whether to cache the plans of the statements sent by the text protocol,
+	// the literals of the statements are parameterized to reuse the plans.
+	TiDBEnableNonPreparedPlanCache = "tidb_enable_non_prepared_plan_cache"
+
+	// TiDBNonPreparedPlanCacheSize is the number of the parameterized statements cached by the non-prepared plan cache
+	// in a session.
+	TiDBNonPreparedPlanCacheSize = "tidb_non_prepared_plan_cache_size"
 )
 
 // TiDB vars that have only global scope
 
 const (
+	// TiDBEnableInstancePlanCache indicates whether the plans of the prepared statements are cached in the instance
+	// plan cache, which is shared by all the sessions of the tidb-server instance.
+	TiDBEnableInstancePlanCache = "tidb_enable_instance_plan_cache"
+	// TiDBInstancePlanCacheMaxMemSize is the max memory size of the plans cached by the instance plan cache.
+	TiDBInstancePlanCacheMaxMemSize = "tidb_instance_plan_cache_max_size"
 	// TiDBGCEnable turns garbage collection on or OFF
 	TiDBGCEnable = "tidb_gc_enable"
 	// TiDBGCRunInterval sets the interval that GC runs
@@ -674,6 +694,8 @@ const (
 	DefMaxPreparedStmtCount               = -1
 	DefWaitTimeout                        = 0
 	DefTiDBMemQuotaApplyCache             = 32 << 20 // 32MB.
+	DefTiDBMemQuotaSession                = 0        // No limit.
+	DefTiDBServerMemoryLimit              = 0        // No limit.
 	DefTiDBMemQuotaHashJoin               = 32 << 30 // 32GB.
 	DefTiDBMemQuotaMergeJoin              = 32 << 30 // 32GB.
 	DefTiDBMemQuotaSort                   = 32 << 30 // 32GB.
@@ -769,6 +791,12 @@ const (
 	DefTiDBEnableOrderedResultMode        = false
 	DefTiDBEnablePseudoForOutdatedStats   = true
 	DefEnablePlacementCheck               = true
+	DefForeignKeyChecks                   = false
+	DefProtocolCompressionAlgorithms      = "zlib,zstd,uncompressed"
+	DefTiDBEnableNonPreparedPlanCache     = false
+	DefTiDBNonPreparedPlanCacheSize       = 100
+	DefTiDBEnableInstancePlanCache        = false
+	DefTiDBInstancePlanCacheMaxMemSize    = 100 << 20 // 100MB.
 )
 
 // Process global variables.
@@ -804,6 +832,14 @@ var (
 	MaxTSOBatchWaitInterval = atomic.NewInt64(DefTiDBTSOClientBatchMaxWaitTime)
 	EnableTSOFollowerProxy  = atomic.NewBool(DefTiDBEnableTSOFollowerProxy)
 	RestrictedReadOnly      = atomic.NewBool(DefTiDBRestrictedReadOnly)
+	// ServerMemoryLimit is the value of tidb_server_memory_limit.
+	ServerMemoryLimit = atomic.NewInt64(DefTiDBServerMemoryLimit)
+	// EnableInstancePlanCache is the value of tidb_enable_instance_plan_cache.
+	EnableInstancePlanCache = atomic.NewBool(DefTiDBEnableInstancePlanCache)
+	// InstancePlanCacheMaxMemSize is the value of tidb_instance_plan_cache_max_size.
+	InstancePlanCacheMaxMemSize = atomic.NewInt64(DefTiDBInstancePlanCacheMaxMemSize)
+	// ProtocolCompressionAlgorithmsValue is the comma separated list of the permitted protocol compression algorithms.
+	ProtocolCompressionAlgorithmsValue = atomic.NewString(DefProtocolCompressionAlgorithms)
 )
 
 // TopSQL is the variable for control top sql feature.
@@ -32,6 +32,7 @@ import (
 	derr "github.com/pingcap/tidb/store/driver/error"
 	txn_driver "github.com/pingcap/tidb/store/driver/txn"
 	"github.com/pingcap/tidb/store/gcworker"
+	"github.com/pingcap/tidb/util/clustertls"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/tikv/client-go/v2/config"
 	"github.com/tikv/client-go/v2/tikv"
@@ -122,17 +123,26 @@ func (d TiKVDriver) OpenWithOptions(path string, options ...Option) (kv.Storage,
 		return nil, errors.Trace(err)
 	}
 
-	pdCli, err := pd.NewClient(etcdAddrs, pd.SecurityOption{
-		CAPath:   d.security.ClusterSSLCA,
-		CertPath: d.security.ClusterSSLCert,
-		KeyPath:  d.security.ClusterSSLKey,
-	},
-		pd.WithGRPCDialOptions(
-			grpc.WithKeepaliveParams(keepalive.ClientParameters{
-				Time:    time.Duration(d.tikvConfig.GrpcKeepAliveTime) * time.Second,
-				Timeout: time.Duration(d.tikvConfig.GrpcKeepAliveTimeout) * time.Second,
-			}),
-		),
+	// The TLS configs and the gRPC dialer of the cluster certificates use the latest certificates for new connections.
+	clusterTLS, err := clustertls.GetManager(d.security)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	tlsConfig := clusterTLS.ClientTLSConfig()
+
+	dialOptions := []grpc.DialOption{
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:    time.Duration(d.tikvConfig.GrpcKeepAliveTime) * time.Second,
+			Timeout: time.Duration(d.tikvConfig.GrpcKeepAliveTimeout) * time.Second,
+		}),
+	}
+	// The PD client reads the CA file only once for each member, so the TLS handshake is done by the dialer
+	// and the PD client itself doesn't use TLS.
+	if dialer := clusterTLS.GRPCDialer(); dialer != nil {
+		dialOptions = append(dialOptions, grpc.WithContextDialer(dialer))
+	}
+	pdCli, err := pd.NewClient(etcdAddrs, pd.SecurityOption{},
+		pd.WithGRPCDialOptions(dialOptions...),
 		pd.WithCustomTimeoutOption(time.Duration(d.pdConfig.PDServerTimeout)*time.Second),
 		pd.WithForwardingOption(config.GetGlobalConfig().EnableForwarding))
 	pdCli = util.InterceptedPDClient{Client: pdCli}
@@ -147,18 +157,15 @@ func (d TiKVDriver) OpenWithOptions(path string, options ...Option) (kv.Storage,
 		return store, nil
 	}
 
-	tlsConfig, err := d.security.ToTLSConfig()
-	if err != nil {
-		return nil, errors.Trace(err)
-	}
-
 	spkv, err := tikv.NewEtcdSafePointKV(etcdAddrs, tlsConfig)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
 
 	pdClient := tikv.CodecPDClient{Client: pdCli}
-	s, err := tikv.NewKVStore(uuid, &pdClient, spkv, tikv.NewRPCClient(tikv.WithSecurity(d.security)))
+	s, err := tikv.NewKVStore(uuid, &pdClient, spkv, newReloadableRPCClient(clusterTLS, func() tikv.Client {
+		return tikv.NewRPCClient(tikv.WithSecurity(d.security))
+	}))
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -184,6 +191,87 @@ func (d TiKVDriver) OpenWithOptions(path string, options ...Option) (kv.Storage,
 	return store, nil
 }
 
+// rpcClientCloseDelay is the delay to close the replaced RPC client, so the requests sent by it can be finished.
+const rpcClientCloseDelay = 10 * time.Minute
+
+// reloadableRPCClient sends the requests to TiKV with an RPC client which is re-created when the cluster
+// certificates are reloaded. The connections of the RPC client read the CA file only once.
+type reloadableRPCClient struct {
+	clusterTLS *clustertls.Manager
+	newClient  func() tikv.Client
+
+	mu         sync.RWMutex
+	client     tikv.Client
+	generation uint64
+	// replaced holds the timers to close the replaced clients.
+	replaced map[tikv.Client]*time.Timer
+	closed   bool
+}
+
+func newReloadableRPCClient(clusterTLS *clustertls.Manager, newClient func() tikv.Client) tikv.Client {
+	if !clusterTLS.Enabled() {
+		return newClient()
+	}
+	return &reloadableRPCClient{
+		clusterTLS: clusterTLS,
+		newClient:  newClient,
+		client:     newClient(),
+		generation: clusterTLS.Generation(),
+		replaced:   make(map[tikv.Client]*time.Timer),
+	}
+}
+
+func (c *reloadableRPCClient) getClient() tikv.Client {
+	generation := c.clusterTLS.Generation()
+	c.mu.RLock()
+	client, reloaded := c.client, c.generation < generation
+	c.mu.RUnlock()
+	if !reloaded {
+		return client
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.generation >= generation || c.closed {
+		return c.client
+	}
+	old := c.client
+	c.client = c.newClient()
+	c.generation = generation
+	c.replaced[old] = time.AfterFunc(rpcClientCloseDelay, func() {
+		c.mu.Lock()
+		delete(c.replaced, old)
+		c.mu.Unlock()
+		closeReplacedRPCClient(old)
+	})
+	logutil.BgLogger().Info("the RPC client is re-created with the reloaded cluster certificates")
+	return c.client
+}
+
+// SendRequest implements the tikv.Client interface.
+func (c *reloadableRPCClient) SendRequest(ctx context.Context, addr string, req *tikvrpc.Request, timeout time.Duration) (*tikvrpc.Response, error) {
+	return c.getClient().SendRequest(ctx, addr, req, timeout)
+}
+
+// Close implements the tikv.Client interface.
+func (c *reloadableRPCClient) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	for old, timer := range c.replaced {
+		if timer.Stop() {
+			closeReplacedRPCClient(old)
+		}
+	}
+	c.replaced = nil
+	return c.client.Close()
+}
+
+func closeReplacedRPCClient(client tikv.Client) {
+	if err := client.Close(); err != nil {
+		logutil.BgLogger().Warn("close the replaced RPC client failed", zap.Error(err))
+	}
+}
+
 type tikvStore struct {
 	*tikv.KVStore
 	etcdAddrs []string
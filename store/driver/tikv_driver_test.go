@@ -0,0 +1,91 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package driver
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/clustertls"
+	"github.com/stretchr/testify/require"
+	"github.com/tikv/client-go/v2/config"
+	"github.com/tikv/client-go/v2/tikv"
+	"github.com/tikv/client-go/v2/tikvrpc"
+)
+
+type mockRPCClient struct {
+	sent   int
+	closed bool
+}
+
+func (c *mockRPCClient) SendRequest(context.Context, string, *tikvrpc.Request, time.Duration) (*tikvrpc.Response, error) {
+	c.sent++
+	return &tikvrpc.Response{}, nil
+}
+
+func (c *mockRPCClient) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestReloadableRPCClient(t *testing.T) {
+	dir := t.TempDir()
+	security := config.Security{
+		ClusterSSLCA:   filepath.Join(dir, "cert.pem"),
+		ClusterSSLCert: filepath.Join(dir, "cert.pem"),
+		ClusterSSLKey:  filepath.Join(dir, "key.pem"),
+	}
+	require.NoError(t, util.CreateTLSCertificates(security.ClusterSSLCert, security.ClusterSSLKey, 2048))
+	clusterTLS, err := clustertls.GetManager(security)
+	require.NoError(t, err)
+
+	var clients []*mockRPCClient
+	client := newReloadableRPCClient(clusterTLS, func() tikv.Client {
+		clients = append(clients, &mockRPCClient{})
+		return clients[len(clients)-1]
+	})
+	_, err = client.SendRequest(context.Background(), "store1", &tikvrpc.Request{}, time.Second)
+	require.NoError(t, err)
+	require.Len(t, clients, 1)
+	require.Equal(t, 1, clients[0].sent)
+
+	// the RPC client is re-created after the CA is rotated, so the new connections use the new CA
+	require.NoError(t, util.CreateTLSCertificates(security.ClusterSSLCert, security.ClusterSSLKey, 2048))
+	changed, err := clusterTLS.Reload()
+	require.NoError(t, err)
+	require.True(t, changed)
+	_, err = client.SendRequest(context.Background(), "store1", &tikvrpc.Request{}, time.Second)
+	require.NoError(t, err)
+	require.Len(t, clients, 2)
+	require.Equal(t, 1, clients[0].sent)
+	require.Equal(t, 1, clients[1].sent)
+	require.False(t, clients[0].closed)
+
+	// the replaced RPC client is closed with the reloadable client
+	require.NoError(t, client.Close())
+	require.True(t, clients[0].closed)
+	require.True(t, clients[1].closed)
+
+	// the RPC client is used directly if TLS is disabled
+	disabled, err := clustertls.GetManager(config.Security{})
+	require.NoError(t, err)
+	client = newReloadableRPCClient(disabled, func() tikv.Client {
+		return &mockRPCClient{}
+	})
+	require.IsType(t, &mockRPCClient{}, client)
+}
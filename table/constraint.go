@@ -0,0 +1,95 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package table
+
+import (
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/expression"
+	"github.com/pingcap/tidb/parser/model"
+	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/util/chunk"
+)
+
+// Constraint provides the meta and the evaluable expression of a check constraint.
+type Constraint struct {
+	*model.ConstraintInfo
+	// ConstraintExpr is the expression built from ExprString, whose columns refer to the row by the column offsets.
+	ConstraintExpr expression.Expression
+}
+
+// ToConstraint builds the Constraint of the check constraint in the table.
+func ToConstraint(ctx sessionctx.Context, constraintInfo *model.ConstraintInfo, tblInfo *model.TableInfo) (*Constraint, error) {
+	expr, err := expression.ParseSimpleExprWithTableInfo(ctx, constraintInfo.ExprString, tblInfo)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &Constraint{ConstraintInfo: constraintInfo, ConstraintExpr: expr}, nil
+}
+
+// WritableConstraints returns the check constraints which need to be checked when writing the rows of the table.
+// The enforced constraints in the write only, write reorganization and public states are writable.
+func WritableConstraints(ctx sessionctx.Context, tblInfo *model.TableInfo) ([]*Constraint, error) {
+	if len(tblInfo.Constraints) == 0 {
+		return nil, nil
+	}
+	constraints := make([]*Constraint, 0, len(tblInfo.Constraints))
+	for _, constraintInfo := range tblInfo.Constraints {
+		if !constraintInfo.Enforced {
+			continue
+		}
+		switch constraintInfo.State {
+		case model.StateWriteOnly, model.StateWriteReorganization, model.StatePublic:
+		default:
+			continue
+		}
+		constraint, err := ToConstraint(ctx, constraintInfo, tblInfo)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		constraints = append(constraints, constraint)
+	}
+	return constraints, nil
+}
+
+// CheckRow checks whether the row satisfies the check constraint.
+// The constraint is satisfied when its expression is evaluated to true or NULL.
+func (c *Constraint) CheckRow(ctx sessionctx.Context, row []types.Datum) error {
+	val, err := c.ConstraintExpr.Eval(chunk.MutRowFromDatums(row).ToRow())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if val.IsNull() {
+		return nil
+	}
+	ok, err := val.ToBool(ctx.GetSessionVars().StmtCtx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if ok == 0 {
+		return ErrCheckConstraintViolated.GenWithStackByArgs(c.Name.O)
+	}
+	return nil
+}
+
+// CheckRowConstraints checks whether the row satisfies all the check constraints.
+func CheckRowConstraints(ctx sessionctx.Context, constraints []*Constraint, row []types.Datum) error {
+	for _, constraint := range constraints {
+		if err := constraint.CheckRow(ctx, row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -144,6 +144,22 @@ func (c *index) GenIndexKey(sc *stmtctx.StatementContext, indexedValues []types.
 // If the index is unique and there is an existing entry with the same key,
 // Create will return the existing entry's handle as the first return value, ErrKeyExists as the second return value.
 func (c *index) Create(sctx sessionctx.Context, txn kv.Transaction, indexedValues []types.Datum, h kv.Handle, handleRestoreData []types.Datum, opts ...table.CreateIdxOptFunc) (kv.Handle, error) {
+	if !c.idxInfo.MVIndex {
+		return c.create(sctx, txn, indexedValues, h, handleRestoreData, opts...)
+	}
+	entries, err := tablecodec.SplitMVIndexValues(sctx.GetSessionVars().StmtCtx, c.tblInfo, c.idxInfo, indexedValues)
+	if err != nil {
+		return nil, err
+	}
+	for _, vals := range entries {
+		if dupHandle, err := c.create(sctx, txn, vals, h, handleRestoreData, opts...); err != nil {
+			return dupHandle, err
+		}
+	}
+	return nil, nil
+}
+
+func (c *index) create(sctx sessionctx.Context, txn kv.Transaction, indexedValues []types.Datum, h kv.Handle, handleRestoreData []types.Datum, opts ...table.CreateIdxOptFunc) (kv.Handle, error) {
 	if c.Meta().Unique {
 		txn.CacheTableInfo(c.phyTblID, c.tblInfo)
 	}
@@ -229,6 +245,22 @@ func (c *index) Create(sctx sessionctx.Context, txn kv.Transaction, indexedValue
 
 // Delete removes the entry for handle h and indexedValues from KV index.
 func (c *index) Delete(sc *stmtctx.StatementContext, txn kv.Transaction, indexedValues []types.Datum, h kv.Handle) error {
+	if !c.idxInfo.MVIndex {
+		return c.delete(sc, txn, indexedValues, h)
+	}
+	entries, err := tablecodec.SplitMVIndexValues(sc, c.tblInfo, c.idxInfo, indexedValues)
+	if err != nil {
+		return err
+	}
+	for _, vals := range entries {
+		if err := c.delete(sc, txn, vals, h); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *index) delete(sc *stmtctx.StatementContext, txn kv.Transaction, indexedValues []types.Datum, h kv.Handle) error {
 	key, distinct, err := c.GenIndexKey(sc, indexedValues, h, nil)
 	if err != nil {
 		return err
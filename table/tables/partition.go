@@ -81,6 +81,15 @@ type partitionedTable struct {
 	partitions      map[int64]*partition
 	evalBufferTypes []*types.FieldType
 	evalBufferPool  sync.Pool
+
+	// Only used during ALTER TABLE ... REORGANIZE PARTITION.
+	// reorgPartitionInfo is the partition layout the rows are double written to,
+	// reorgPartitionExpr is the partition expression built on it, and
+	// doubleWritePartitions holds the physical IDs which are not visible but
+	// still need to be kept in sync with the visible ones.
+	reorgPartitionInfo    *model.PartitionInfo
+	reorgPartitionExpr    *PartitionExpr
+	doubleWritePartitions map[int64]struct{}
 }
 
 func newPartitionedTable(tbl *TableCommon, tblInfo *model.TableInfo) (table.Table, error) {
@@ -100,19 +109,96 @@ func newPartitionedTable(tbl *TableCommon, tblInfo *model.TableInfo) (table.Tabl
 		return nil, errors.Trace(err)
 	}
 	pi := tblInfo.GetPartitionInfo()
-	partitions := make(map[int64]*partition, len(pi.Definitions))
-	for _, p := range pi.Definitions {
-		var t partition
-		err := initTableCommonWithIndices(&t.TableCommon, tblInfo, p.ID, tbl.Columns, tbl.allocs)
-		if err != nil {
-			return nil, errors.Trace(err)
+	partitions := make(map[int64]*partition, len(pi.Definitions)+len(pi.AddingDefinitions)+len(pi.DroppingDefinitions))
+	for _, defs := range [][]model.PartitionDefinition{pi.Definitions, pi.AddingDefinitions, pi.DroppingDefinitions} {
+		for _, p := range defs {
+			if _, ok := partitions[p.ID]; ok {
+				continue
+			}
+			var t partition
+			err := initTableCommonWithIndices(&t.TableCommon, tblInfo, p.ID, tbl.Columns, tbl.allocs)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			partitions[p.ID] = &t
 		}
-		partitions[p.ID] = &t
 	}
 	ret.partitions = partitions
+	if err := initReorganizePartition(ret, tblInfo); err != nil {
+		return nil, errors.Trace(err)
+	}
 	return ret, nil
 }
 
+// initReorganizePartition sets up the double write layout of a table in the
+// middle of ALTER TABLE ... REORGANIZE PARTITION, PARTITION BY or REMOVE PARTITIONING.
+// Before the reorganization is done, the new partitions in AddingDefinitions
+// are written to; after it, the old partitions in DroppingDefinitions are.
+func initReorganizePartition(t *partitionedTable, tblInfo *model.TableInfo) error {
+	pi := tblInfo.GetPartitionInfo()
+	switch pi.DDLAction {
+	case model.ActionReorganizePartition, model.ActionAlterTablePartitioning, model.ActionRemovePartitioning:
+	default:
+		return nil
+	}
+	var doubleWrite []model.PartitionDefinition
+	var defs []model.PartitionDefinition
+	switch pi.DDLState {
+	case model.StateDeleteOnly, model.StateWriteOnly, model.StateWriteReorganization:
+		doubleWrite = pi.AddingDefinitions
+		defs = ReplacePartitionDefinitions(pi.Definitions, pi.DroppingDefinitions, pi.AddingDefinitions)
+	case model.StateDeleteReorganization:
+		doubleWrite = pi.DroppingDefinitions
+		defs = ReplacePartitionDefinitions(pi.Definitions, pi.AddingDefinitions, pi.DroppingDefinitions)
+	default:
+		return nil
+	}
+	reorgPi := *pi
+	reorgPi.Type = pi.DDLType
+	reorgPi.Expr = pi.DDLExpr
+	reorgPi.Columns = pi.DDLColumns
+	reorgPi.Definitions = defs
+	reorgPi.Num = uint64(len(defs))
+	reorgTblInfo := *tblInfo
+	reorgTblInfo.Partition = &reorgPi
+	partitionExpr, err := newPartitionExpr(&reorgTblInfo)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	t.reorgPartitionInfo = &reorgPi
+	t.reorgPartitionExpr = partitionExpr
+	t.doubleWritePartitions = make(map[int64]struct{}, len(doubleWrite))
+	for _, def := range doubleWrite {
+		t.doubleWritePartitions[def.ID] = struct{}{}
+	}
+	return nil
+}
+
+// ReplacePartitionDefinitions returns a copy of defs where the partitions in
+// oldDefs are replaced by newDefs, put at the place of the first one of oldDefs.
+func ReplacePartitionDefinitions(defs, oldDefs, newDefs []model.PartitionDefinition) []model.PartitionDefinition {
+	oldIDs := make(map[int64]struct{}, len(oldDefs))
+	for _, def := range oldDefs {
+		oldIDs[def.ID] = struct{}{}
+	}
+	ret := make([]model.PartitionDefinition, 0, len(defs)-len(oldDefs)+len(newDefs))
+	inserted := false
+	for _, def := range defs {
+		if _, ok := oldIDs[def.ID]; !ok {
+			ret = append(ret, def)
+			continue
+		}
+		if !inserted {
+			ret = append(ret, newDefs...)
+			inserted = true
+		}
+	}
+	if !inserted {
+		ret = append(ret, newDefs...)
+	}
+	return ret
+}
+
 func newPartitionExpr(tblInfo *model.TableInfo) (*PartitionExpr, error) {
 	ctx := mock.NewContext()
 	dbName := model.NewCIStr(ctx.GetSessionVars().CurrentDB)
@@ -128,6 +214,9 @@ func newPartitionExpr(tblInfo *model.TableInfo) (*PartitionExpr, error) {
 		return generateHashPartitionExpr(ctx, pi, columns, names)
 	case model.PartitionTypeList:
 		return generateListPartitionExpr(ctx, tblInfo, columns, names)
+	case model.PartitionTypeNone:
+		// All the rows are in the single partition, nothing to evaluate.
+		return &PartitionExpr{}, nil
 	}
 	panic("cannot reach here")
 }
@@ -911,19 +1000,31 @@ func PartitionRecordKey(pid int64, handle int64) kv.Key {
 
 // locatePartition returns the partition ID of the input record.
 func (t *partitionedTable) locatePartition(ctx sessionctx.Context, pi *model.PartitionInfo, r []types.Datum) (int64, error) {
+	return t.locatePartitionWithExpr(ctx, pi, t.partitionExpr, r)
+}
+
+// locateReorgPartition returns the partition ID of the input record in the
+// partition layout used for double writing during partition reorganization.
+func (t *partitionedTable) locateReorgPartition(ctx sessionctx.Context, r []types.Datum) (int64, error) {
+	return t.locatePartitionWithExpr(ctx, t.reorgPartitionInfo, t.reorgPartitionExpr, r)
+}
+
+func (t *partitionedTable) locatePartitionWithExpr(ctx sessionctx.Context, pi *model.PartitionInfo, partExpr *PartitionExpr, r []types.Datum) (int64, error) {
 	var err error
 	var idx int
-	switch t.meta.Partition.Type {
+	switch pi.Type {
 	case model.PartitionTypeRange:
 		if len(pi.Columns) == 0 {
-			idx, err = t.locateRangePartition(ctx, pi, r)
+			idx, err = t.locateRangePartition(ctx, pi, partExpr, r)
 		} else {
-			idx, err = t.locateRangeColumnPartition(ctx, pi, r)
+			idx, err = t.locateRangeColumnPartition(ctx, pi, partExpr, r)
 		}
 	case model.PartitionTypeHash:
-		idx, err = t.locateHashPartition(ctx, pi, r)
+		idx, err = t.locateHashPartition(ctx, pi, partExpr, r)
 	case model.PartitionTypeList:
-		idx, err = t.locateListPartition(ctx, pi, r)
+		idx, err = t.locateListPartition(ctx, pi, partExpr, r)
+	case model.PartitionTypeNone:
+		idx = 0
 	}
 	if err != nil {
 		return 0, errors.Trace(err)
@@ -931,10 +1032,10 @@ func (t *partitionedTable) locatePartition(ctx sessionctx.Context, pi *model.Par
 	return pi.Definitions[idx].ID, nil
 }
 
-func (t *partitionedTable) locateRangeColumnPartition(ctx sessionctx.Context, pi *model.PartitionInfo, r []types.Datum) (int, error) {
+func (t *partitionedTable) locateRangeColumnPartition(ctx sessionctx.Context, pi *model.PartitionInfo, partExpr *PartitionExpr, r []types.Datum) (int, error) {
 	var err error
 	var isNull bool
-	partitionExprs := t.partitionExpr.UpperBounds
+	partitionExprs := partExpr.UpperBounds
 	evalBuffer := t.evalBufferPool.Get().(*chunk.MutRow)
 	defer t.evalBufferPool.Put(evalBuffer)
 	idx := sort.Search(len(partitionExprs), func(i int) bool {
@@ -976,22 +1077,22 @@ func (t *partitionedTable) locateRangeColumnPartition(ctx sessionctx.Context, pi
 	return idx, nil
 }
 
-func (t *partitionedTable) locateListPartition(ctx sessionctx.Context, pi *model.PartitionInfo, r []types.Datum) (int, error) {
-	lp := t.partitionExpr.ForListPruning
+func (t *partitionedTable) locateListPartition(ctx sessionctx.Context, pi *model.PartitionInfo, partExpr *PartitionExpr, r []types.Datum) (int, error) {
+	lp := partExpr.ForListPruning
 	if len(lp.ColPrunes) == 0 {
 		return lp.locateListPartitionByRow(ctx, r)
 	}
 	return lp.locateListColumnsPartitionByRow(ctx, r)
 }
 
-func (t *partitionedTable) locateRangePartition(ctx sessionctx.Context, pi *model.PartitionInfo, r []types.Datum) (int, error) {
+func (t *partitionedTable) locateRangePartition(ctx sessionctx.Context, pi *model.PartitionInfo, partExpr *PartitionExpr, r []types.Datum) (int, error) {
 	var (
 		ret    int64
 		val    int64
 		isNull bool
 		err    error
 	)
-	if col, ok := t.partitionExpr.Expr.(*expression.Column); ok {
+	if col, ok := partExpr.Expr.(*expression.Column); ok {
 		if r[col.Index].IsNull() {
 			isNull = true
 		}
@@ -1000,14 +1101,14 @@ func (t *partitionedTable) locateRangePartition(ctx sessionctx.Context, pi *mode
 		evalBuffer := t.evalBufferPool.Get().(*chunk.MutRow)
 		defer t.evalBufferPool.Put(evalBuffer)
 		evalBuffer.SetDatums(r...)
-		val, isNull, err = t.partitionExpr.Expr.EvalInt(ctx, evalBuffer.ToRow())
+		val, isNull, err = partExpr.Expr.EvalInt(ctx, evalBuffer.ToRow())
 		if err != nil {
 			return 0, err
 		}
 		ret = val
 	}
-	unsigned := mysql.HasUnsignedFlag(t.partitionExpr.Expr.GetType().Flag)
-	ranges := t.partitionExpr.ForRangePruning
+	unsigned := mysql.HasUnsignedFlag(partExpr.Expr.GetType().Flag)
+	ranges := partExpr.ForRangePruning
 	length := len(ranges.LessThan)
 	pos := sort.Search(length, func(i int) bool {
 		if isNull {
@@ -1039,8 +1140,8 @@ func (t *partitionedTable) locateRangePartition(ctx sessionctx.Context, pi *mode
 }
 
 // TODO: supports linear hashing
-func (t *partitionedTable) locateHashPartition(ctx sessionctx.Context, pi *model.PartitionInfo, r []types.Datum) (int, error) {
-	if col, ok := t.partitionExpr.Expr.(*expression.Column); ok {
+func (t *partitionedTable) locateHashPartition(ctx sessionctx.Context, pi *model.PartitionInfo, partExpr *PartitionExpr, r []types.Datum) (int, error) {
+	if col, ok := partExpr.Expr.(*expression.Column); ok {
 		var data types.Datum
 		switch r[col.Index].Kind() {
 		case types.KindInt64, types.KindUint64:
@@ -1053,7 +1154,7 @@ func (t *partitionedTable) locateHashPartition(ctx sessionctx.Context, pi *model
 			}
 		}
 		ret := data.GetInt64()
-		ret = ret % int64(t.meta.Partition.Num)
+		ret = ret % int64(pi.Num)
 		if ret < 0 {
 			ret = -ret
 		}
@@ -1062,14 +1163,14 @@ func (t *partitionedTable) locateHashPartition(ctx sessionctx.Context, pi *model
 	evalBuffer := t.evalBufferPool.Get().(*chunk.MutRow)
 	defer t.evalBufferPool.Put(evalBuffer)
 	evalBuffer.SetDatums(r...)
-	ret, isNull, err := t.partitionExpr.Expr.EvalInt(ctx, evalBuffer.ToRow())
+	ret, isNull, err := partExpr.Expr.EvalInt(ctx, evalBuffer.ToRow())
 	if err != nil {
 		return 0, err
 	}
 	if isNull {
 		return 0, nil
 	}
-	ret = ret % int64(t.meta.Partition.Num)
+	ret = ret % int64(pi.Num)
 	if ret < 0 {
 		ret = -ret
 	}
@@ -1126,7 +1227,35 @@ func partitionedTableAddRecord(ctx sessionctx.Context, t *partitionedTable, r []
 		}
 	}
 	tbl := t.GetPartition(pid)
-	return tbl.AddRecord(ctx, r, opts...)
+	recordID, err = tbl.AddRecord(ctx, r, opts...)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if t.reorgPartitionExpr == nil || t.meta.Partition.DDLState == model.StateDeleteOnly {
+		return recordID, nil
+	}
+	pid, err = t.locateReorgPartition(ctx, r)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if _, ok := t.doubleWritePartitions[pid]; !ok {
+		return recordID, nil
+	}
+	// The double written record must keep the same handle.
+	_, err = t.GetPartition(pid).AddRecord(ctx, t.withRecordHandle(r, recordID), opts...)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return recordID, nil
+}
+
+// withRecordHandle appends the handle to the row if it is an extra handle column
+// (_tidb_rowid) that is not part of the row yet, so that AddRecord reuses it.
+func (t *partitionedTable) withRecordHandle(r []types.Datum, h kv.Handle) []types.Datum {
+	if t.meta.PKIsHandle || t.meta.IsCommonHandle || len(r) > len(t.Cols()) {
+		return r
+	}
+	return append(r[:len(r):len(r)], types.NewIntDatum(h.IntValue()))
 }
 
 // partitionTableWithGivenSets is used for this kind of grammar: partition (p0,p1)
@@ -1170,13 +1299,29 @@ func (t *partitionedTable) RemoveRecord(ctx sessionctx.Context, h kv.Handle, r [
 	}
 
 	tbl := t.GetPartition(pid)
-	return tbl.RemoveRecord(ctx, h, r)
+	err = tbl.RemoveRecord(ctx, h, r)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if t.reorgPartitionExpr == nil {
+		return nil
+	}
+	pid, err = t.locateReorgPartition(ctx, r)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, ok := t.doubleWritePartitions[pid]; !ok {
+		return nil
+	}
+	return t.GetPartition(pid).RemoveRecord(ctx, h, r)
 }
 
+// GetAllPartitionIDs returns the IDs of all the visible partitions.
 func (t *partitionedTable) GetAllPartitionIDs() []int64 {
-	ptIDs := make([]int64, 0, len(t.partitions))
-	for id := range t.partitions {
-		ptIDs = append(ptIDs, id)
+	pi := t.meta.GetPartitionInfo()
+	ptIDs := make([]int64, 0, len(pi.Definitions))
+	for _, def := range pi.Definitions {
+		ptIDs = append(ptIDs, def.ID)
 	}
 	return ptIDs
 }
@@ -1214,8 +1359,9 @@ func partitionedTableUpdateRecord(gctx context.Context, ctx sessionctx.Context,
 
 	// The old and new data locate in different partitions.
 	// Remove record from old partition and add record to new partition.
+	newHandle := h
 	if from != to {
-		_, err = t.GetPartition(to).AddRecord(ctx, newData)
+		newHandle, err = t.GetPartition(to).AddRecord(ctx, newData)
 		if err != nil {
 			return errors.Trace(err)
 		}
@@ -1230,11 +1376,53 @@ func partitionedTableUpdateRecord(gctx context.Context, ctx sessionctx.Context,
 			logutil.BgLogger().Error("update partition record fails", zap.String("message", "new record inserted while old record is not removed"), zap.Error(err))
 			return errors.Trace(err)
 		}
+	} else {
+		tbl := t.GetPartition(to)
+		if err = tbl.UpdateRecord(gctx, ctx, h, currData, newData, touched); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if t.reorgPartitionExpr == nil {
 		return nil
 	}
+	return t.updateReorgPartitionRecord(gctx, ctx, h, newHandle, currData, newData, touched)
+}
 
-	tbl := t.GetPartition(to)
-	return tbl.UpdateRecord(gctx, ctx, h, currData, newData, touched)
+// updateReorgPartitionRecord applies an update to the partitions which are
+// double written during partition reorganization.
+func (t *partitionedTable) updateReorgPartitionRecord(gctx context.Context, ctx sessionctx.Context, h, newHandle kv.Handle, currData, newData []types.Datum, touched []bool) error {
+	from, err := t.locateReorgPartition(ctx, currData)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	_, fromOK := t.doubleWritePartitions[from]
+	if t.meta.Partition.DDLState == model.StateDeleteOnly {
+		if !fromOK {
+			return nil
+		}
+		return t.GetPartition(from).RemoveRecord(ctx, h, currData)
+	}
+	to, err := t.locateReorgPartition(ctx, newData)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	_, toOK := t.doubleWritePartitions[to]
+	if from == to && h.Equal(newHandle) {
+		if !fromOK {
+			return nil
+		}
+		return t.GetPartition(to).UpdateRecord(gctx, ctx, h, currData, newData, touched)
+	}
+	if toOK {
+		_, err = t.GetPartition(to).AddRecord(ctx, t.withRecordHandle(newData, newHandle))
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if fromOK {
+		return t.GetPartition(from).RemoveRecord(ctx, h, currData)
+	}
+	return nil
 }
 
 // FindPartitionByName finds partition in table meta by name.
@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"encoding/binary"
 	"math"
+	"sort"
 	"strings"
 	"time"
 	"unicode/utf8"
@@ -32,6 +33,7 @@ import (
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/structure"
 	"github.com/pingcap/tidb/types"
+	"github.com/pingcap/tidb/types/json"
 	"github.com/pingcap/tidb/util/codec"
 	"github.com/pingcap/tidb/util/collate"
 	"github.com/pingcap/tidb/util/dbterror"
@@ -1279,6 +1281,74 @@ func genIndexValueVersion0(sc *stmtctx.StatementContext, tblInfo *model.TableInf
 	return idxVal, nil
 }
 
+// SplitMVIndexValues splits the indexed values of a multi-valued index into the
+// indexed values of its index entries, there is one entry for every distinct
+// element of the array key part and no entry for an empty array.
+func SplitMVIndexValues(sc *stmtctx.StatementContext, tblInfo *model.TableInfo, idxInfo *model.IndexInfo, indexedValues []types.Datum) ([][]types.Datum, error) {
+	for i, idxCol := range idxInfo.Columns {
+		elemTp := tblInfo.Columns[idxCol.Offset].ArrayElem
+		if elemTp == nil || indexedValues[i].IsNull() {
+			continue
+		}
+		arr := indexedValues[i].GetMysqlJSON()
+		elems := make([]types.Datum, 0, arr.GetElemCount())
+		for j := 0; j < arr.GetElemCount(); j++ {
+			elem, err := MVIndexElemDatum(arr.ArrayGetElem(j), elemTp)
+			if err != nil {
+				return nil, err
+			}
+			elems = append(elems, elem)
+		}
+		var err error
+		sort.Slice(elems, func(a, b int) bool {
+			cmp, err1 := elems[a].CompareDatum(sc, &elems[b])
+			if err1 != nil {
+				err = err1
+			}
+			return cmp < 0
+		})
+		if err != nil {
+			return nil, err
+		}
+		entries := make([][]types.Datum, 0, len(elems))
+		for j := range elems {
+			if j > 0 {
+				cmp, err := elems[j].CompareDatum(sc, &elems[j-1])
+				if err != nil {
+					return nil, err
+				}
+				if cmp == 0 {
+					continue
+				}
+			}
+			vals := make([]types.Datum, len(indexedValues))
+			copy(vals, indexedValues)
+			vals[i] = elems[j]
+			entries = append(entries, vals)
+		}
+		return entries, nil
+	}
+	return [][]types.Datum{indexedValues}, nil
+}
+
+// MVIndexElemDatum converts an element of the array key part of a multi-valued
+// index to the datum stored in the index entry.
+func MVIndexElemDatum(elem json.BinaryJSON, elemTp *types.FieldType) (d types.Datum, err error) {
+	switch elem.TypeCode {
+	case json.TypeCodeInt64:
+		d.SetInt64(elem.GetInt64())
+	case json.TypeCodeUint64:
+		d.SetUint64(elem.GetUint64())
+	case json.TypeCodeFloat64:
+		d.SetFloat64(elem.GetFloat64())
+	case json.TypeCodeString:
+		d.SetString(string(elem.GetString()), elemTp.Collate)
+	default:
+		err = errors.Errorf("invalid element %s of multi-valued index", elem.String())
+	}
+	return d, err
+}
+
 // TruncateIndexValues truncates the index values created using only the leading part of column values.
 func TruncateIndexValues(tblInfo *model.TableInfo, idxInfo *model.IndexInfo, indexedValues []types.Datum) {
 	for i := 0; i < len(indexedValues); i++ {
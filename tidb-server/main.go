@@ -53,6 +53,7 @@ import (
 	"github.com/pingcap/tidb/store/driver"
 	"github.com/pingcap/tidb/store/mockstore"
 	"github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/clustertls"
 	"github.com/pingcap/tidb/util/deadlockhistory"
 	"github.com/pingcap/tidb/util/disk"
 	"github.com/pingcap/tidb/util/domainutil"
@@ -196,6 +197,7 @@ func main() {
 	printInfo()
 	setupBinlogClient()
 	setupMetrics()
+	setupClusterTLS()
 
 	storage, dom := createStoreAndDomain()
 	svr := createServer(storage, dom)
@@ -306,6 +308,15 @@ func createStoreAndDomain() (kv.Storage, *domain.Domain) {
 	return storage, dom
 }
 
+// setupClusterTLS reloads the cluster TLS certificates used by the new connections when the files are rotated.
+func setupClusterTLS() {
+	m, err := clustertls.Global()
+	if err != nil {
+		log.Fatal("could not load cluster ssl", zap.Error(err))
+	}
+	go m.Watch(context.Background(), clustertls.DefaultWatchInterval)
+}
+
 func setupBinlogClient() {
 	cfg := config.GetGlobalConfig()
 	if !cfg.Binlog.Enable {
@@ -630,11 +641,11 @@ func setGlobalVars() {
 	executor.GlobalDiskUsageTracker.SetBytesLimit(cfg.TempStorageQuota)
 	if cfg.Performance.ServerMemoryQuota < 1 {
 		// If MaxMemory equals 0, it means unlimited
-		executor.GlobalMemoryUsageTracker.SetBytesLimit(-1)
+		memory.GlobalMemoryUsageTracker.SetBytesLimit(-1)
 	} else {
-		executor.GlobalMemoryUsageTracker.SetBytesLimit(int64(cfg.Performance.ServerMemoryQuota))
+		memory.GlobalMemoryUsageTracker.SetBytesLimit(int64(cfg.Performance.ServerMemoryQuota))
 	}
-	kvcache.GlobalLRUMemUsageTracker.AttachToGlobalTracker(executor.GlobalMemoryUsageTracker)
+	kvcache.GlobalLRUMemUsageTracker.AttachToGlobalTracker(memory.GlobalMemoryUsageTracker)
 
 	t, err := time.ParseDuration(cfg.TiKVClient.StoreLivenessTimeout)
 	if err != nil || t < 0 {
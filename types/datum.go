@@ -15,6 +15,7 @@
 package types
 
 import (
+	gjson "encoding/json"
 	"fmt"
 	"math"
 	"sort"
@@ -418,6 +419,80 @@ func (d Datum) String() string {
 	return fmt.Sprintf("%v %v", t, v)
 }
 
+// jsonDatum is the JSON representation of Datum, decimals are kept as strings and times as packed uint64.
+type jsonDatum struct {
+	K         byte   `json:"k"`
+	Decimal   uint16 `json:"decimal,omitempty"`
+	Length    uint32 `json:"length,omitempty"`
+	I         int64  `json:"i,omitempty"`
+	Collation string `json:"collation,omitempty"`
+	B         []byte `json:"b,omitempty"`
+	TimeType  uint8  `json:"time_type,omitempty"`
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (d Datum) MarshalJSON() ([]byte, error) {
+	jd := jsonDatum{
+		K:         d.k,
+		Decimal:   d.decimal,
+		Length:    d.length,
+		I:         d.i,
+		Collation: d.collation,
+		B:         d.b,
+	}
+	switch d.k {
+	case KindMysqlDecimal:
+		jd.B = d.GetMysqlDecimal().ToString()
+	case KindMysqlTime:
+		t := d.GetMysqlTime()
+		packed, err := t.ToPackedUint()
+		if err != nil {
+			return nil, err
+		}
+		jd.I = int64(packed)
+		jd.TimeType = t.Type()
+		jd.Decimal = uint16(t.Fsp())
+	case KindInterface:
+		return nil, errors.Errorf("cannot marshal datum of kind %d", d.k)
+	}
+	return gjson.Marshal(jd)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (d *Datum) UnmarshalJSON(data []byte) error {
+	var jd jsonDatum
+	if err := gjson.Unmarshal(data, &jd); err != nil {
+		return err
+	}
+	*d = Datum{
+		k:         jd.K,
+		decimal:   jd.Decimal,
+		length:    jd.Length,
+		i:         jd.I,
+		collation: jd.Collation,
+		b:         jd.B,
+	}
+	switch jd.K {
+	case KindMysqlDecimal:
+		dec := new(MyDecimal)
+		if err := dec.FromString(jd.B); err != nil {
+			return err
+		}
+		d.b = nil
+		d.x = dec
+	case KindMysqlTime:
+		t := NewTime(ZeroCoreTime, jd.TimeType, int8(jd.Decimal))
+		if err := t.FromPackedUint(uint64(jd.I)); err != nil {
+			return err
+		}
+		d.decimal, d.i = 0, 0
+		d.x = t
+	case KindInterface:
+		return errors.Errorf("cannot unmarshal datum of kind %d", jd.K)
+	}
+	return nil
+}
+
 // GetValue gets the value of the datum of any kind.
 func (d *Datum) GetValue() interface{} {
 	switch d.k {
@@ -15,6 +15,7 @@
 package types
 
 import (
+	gjson "encoding/json"
 	"fmt"
 	"math"
 	"reflect"
@@ -356,6 +357,47 @@ func TestCloneDatum(t *testing.T) {
 	}
 }
 
+func TestDatumJSON(t *testing.T) {
+	t.Parallel()
+	dec := newMyDecimal("1234.5678", t)
+	tm, err := ParseDatetime(nil, "2021-10-18 12:34:56.789")
+	require.NoError(t, err)
+	j, err := json.ParseBinaryFromString(`{"a": [1, "b"]}`)
+	require.NoError(t, err)
+	tests := []Datum{
+		{},
+		NewIntDatum(-72),
+		NewUintDatum(math.MaxUint64),
+		NewFloat64Datum(1.25),
+		NewFloat32Datum(3.5),
+		NewCollationStringDatum("abcd", "utf8mb4_general_ci"),
+		NewBytesDatum([]byte{0xff, 0x00, 0x80}),
+		NewDecimalDatum(dec),
+		NewTimeDatum(tm),
+		NewDurationDatum(Duration{Duration: time.Hour + time.Second, Fsp: 2}),
+		NewJSONDatum(j),
+		NewBinaryLiteralDatum(NewBinaryLiteralFromUint(0x41, -1)),
+		NewMysqlEnumDatum(Enum{Name: "a", Value: 1}),
+	}
+
+	sc := new(stmtctx.StatementContext)
+	for _, tt := range tests {
+		data, err := gjson.Marshal(tt)
+		require.NoError(t, err)
+		var decoded Datum
+		require.NoError(t, gjson.Unmarshal(data, &decoded))
+		require.Equal(t, tt.Kind(), decoded.Kind())
+		require.Equal(t, tt.Collation(), decoded.Collation())
+		res, err := tt.CompareDatum(sc, &decoded)
+		require.NoError(t, err)
+		require.Equal(t, 0, res, tt.String())
+		if tt.Kind() == KindMysqlTime {
+			require.Equal(t, tt.GetMysqlTime().Type(), decoded.GetMysqlTime().Type())
+			require.Equal(t, tt.GetMysqlTime().Fsp(), decoded.GetMysqlTime().Fsp())
+		}
+	}
+}
+
 func newTypeWithFlag(tp byte, flag uint) *FieldType {
 	t := NewFieldType(tp)
 	t.Flag |= flag
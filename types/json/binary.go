@@ -207,6 +207,11 @@ func (bj BinaryJSON) GetElemCount() int {
 	return int(endian.Uint32(bj.Value))
 }
 
+// ArrayGetElem gets the element of the array at the index.
+func (bj BinaryJSON) ArrayGetElem(idx int) BinaryJSON {
+	return bj.arrayGetElem(idx)
+}
+
 func (bj BinaryJSON) arrayGetElem(idx int) BinaryJSON {
 	return bj.valEntryGet(headerSize + idx*valEntrySize)
 }
@@ -228,6 +228,12 @@ func (bj BinaryJSON) Extract(pathExprList []PathExpression) (ret BinaryJSON, fou
 	return
 }
 
+// ExtractAll returns all the values matched by the path expression. Unlike Extract,
+// the values are never wrapped as an array.
+func (bj BinaryJSON) ExtractAll(pathExpr PathExpression) []BinaryJSON {
+	return bj.extractTo(nil, pathExpr)
+}
+
 func (bj BinaryJSON) extractTo(buf []BinaryJSON, pathExpr PathExpression) []BinaryJSON {
 	if len(pathExpr.legs) == 0 {
 		return append(buf, bj)
@@ -1021,6 +1027,51 @@ func ContainsBinary(obj, target BinaryJSON) bool {
 	}
 }
 
+// MemberOfBinary checks whether the value is an element of the JSON array, a
+// nonarray is compared with the value as if it is an array with only one element.
+func MemberOfBinary(value, arr BinaryJSON) bool {
+	if arr.TypeCode != TypeCodeArray {
+		return CompareBinary(value, arr) == 0
+	}
+	for i := 0; i < arr.GetElemCount(); i++ {
+		if CompareBinary(value, arr.arrayGetElem(i)) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// OverlapsBinary checks whether two JSON documents have any key-value pairs or
+// array elements in common. Two objects overlap if they have at least one key-value
+// pair in common, an array and another document overlap if they have at least one
+// element in common, where a nonarray document is treated as an array with only one
+// element, and two scalars overlap if they are comparable and are equal.
+func OverlapsBinary(left, right BinaryJSON) bool {
+	if left.TypeCode == TypeCodeObject && right.TypeCode == TypeCodeObject {
+		for i := 0; i < right.GetElemCount(); i++ {
+			if val, exists := left.objectSearchKey(right.objectGetKey(i)); exists && CompareBinary(val, right.objectGetVal(i)) == 0 {
+				return true
+			}
+		}
+		return false
+	}
+	if left.TypeCode != TypeCodeArray {
+		left, right = right, left
+	}
+	if left.TypeCode != TypeCodeArray {
+		return CompareBinary(left, right) == 0
+	}
+	if right.TypeCode != TypeCodeArray {
+		return MemberOfBinary(right, left)
+	}
+	for i := 0; i < right.GetElemCount(); i++ {
+		if MemberOfBinary(right.arrayGetElem(i), left) {
+			return true
+		}
+	}
+	return false
+}
+
 // GetElemDepth for JSON_DEPTH
 // Returns the maximum depth of a JSON document
 // rules referenced by MySQL JSON_DEPTH function
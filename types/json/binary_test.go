@@ -85,6 +85,33 @@ func TestBinaryJSONExtract(t *testing.T) {
 	}
 }
 
+func TestBinaryJSONExtractAll(t *testing.T) {
+	t.Parallel()
+
+	bj := mustParseBinaryFromString(t, `{"a": [1, [2, 3]], "b": {"c": 4}}`)
+	var tests = []struct {
+		pathExprString string
+		expected       []string
+	}{
+		{"$.a", []string{"[1, [2, 3]]"}},
+		{"$.a[*]", []string{"1", "[2, 3]"}},
+		{"$.a[1][*]", []string{"2", "3"}},
+		{"$.b[*]", []string{}},
+		{"$.b.c[*]", []string{}},
+		{"$.*.c", []string{"4"}},
+		{"$.d", []string{}},
+	}
+	for _, test := range tests {
+		pe, err := ParseJSONPathExpr(test.pathExprString)
+		require.NoError(t, err)
+		result := bj.ExtractAll(pe)
+		require.Len(t, result, len(test.expected), test.pathExprString)
+		for i, expected := range test.expected {
+			require.Equal(t, expected, result[i].String(), test.pathExprString)
+		}
+	}
+}
+
 func TestBinaryJSONType(t *testing.T) {
 	t.Parallel()
 
@@ -400,6 +427,38 @@ func TestBinaryJSONContains(t *testing.T) {
 	}
 }
 
+func TestBinaryJSONMemberOfAndOverlaps(t *testing.T) {
+	t.Parallel()
+
+	var tests = []struct {
+		left     string
+		right    string
+		memberOf bool
+		overlaps bool
+	}{
+		{`1`, `[1, 2]`, true, true},
+		{`3`, `[1, 2]`, false, false},
+		{`"1"`, `[1, 2]`, false, false},
+		{`1`, `1`, true, true},
+		{`1`, `[[1], 2]`, false, false},
+		{`[1]`, `[[1], 2]`, true, false},
+		{`[1, 3]`, `[2, 3]`, false, true},
+		{`{"a": 1}`, `[{"a": 1}]`, true, true},
+		{`{"a": 1, "b": 2}`, `{"b": 2}`, false, true},
+		{`{"a": 1}`, `{"a": 2}`, false, false},
+		{`{"a": 1}`, `{"a": 1}`, true, true},
+		{`[]`, `[]`, false, false},
+	}
+
+	for _, test := range tests {
+		left := mustParseBinaryFromString(t, test.left)
+		right := mustParseBinaryFromString(t, test.right)
+		require.Equal(t, test.memberOf, MemberOfBinary(left, right), test)
+		require.Equal(t, test.overlaps, OverlapsBinary(left, right), test)
+		require.Equal(t, test.overlaps, OverlapsBinary(right, left), test)
+	}
+}
+
 func TestBinaryJSONCopy(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,302 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clustertls
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"net"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tidb/config"
+	"github.com/pingcap/tidb/metrics"
+	"github.com/pingcap/tidb/util/logutil"
+	tikvcfg "github.com/tikv/client-go/v2/config"
+	"go.uber.org/zap"
+)
+
+// DefaultWatchInterval is the default interval of checking whether the certificate files are rotated.
+const DefaultWatchInterval = time.Minute
+
+const (
+	certTypeCA   = "ca"
+	certTypeCert = "cert"
+)
+
+// certificates is a snapshot of the cluster certificates.
+type certificates struct {
+	caPool *x509.CertPool
+	// cert is nil if cluster-ssl-cert and cluster-ssl-key are not set.
+	cert *tls.Certificate
+	// caPEM, certPEM and keyPEM are the contents of the files, they are used to detect the rotation.
+	caPEM, certPEM, keyPEM []byte
+	caNotAfter             time.Time
+	certNotAfter           time.Time
+}
+
+func newCertificates(caPEM, certPEM, keyPEM []byte) (*certificates, error) {
+	c := &certificates{
+		caPool:  x509.NewCertPool(),
+		caPEM:   caPEM,
+		certPEM: certPEM,
+		keyPEM:  keyPEM,
+	}
+	for rest := caPEM; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		ca, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, errors.Errorf("could not parse ca certificate: %s", err)
+		}
+		c.caPool.AddCert(ca)
+		if c.caNotAfter.IsZero() || ca.NotAfter.Before(c.caNotAfter) {
+			c.caNotAfter = ca.NotAfter
+		}
+	}
+	if c.caNotAfter.IsZero() {
+		return nil, errors.New("failed to append ca certs")
+	}
+	if len(certPEM) == 0 {
+		return c, nil
+	}
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, errors.Errorf("could not load client key pair: %s", err)
+	}
+	if cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0]); err != nil {
+		return nil, errors.Errorf("could not parse client certificate: %s", err)
+	}
+	c.cert = &cert
+	c.certNotAfter = cert.Leaf.NotAfter
+	return c, nil
+}
+
+func (c *certificates) equal(caPEM, certPEM, keyPEM []byte) bool {
+	return bytes.Equal(c.caPEM, caPEM) && bytes.Equal(c.certPEM, certPEM) && bytes.Equal(c.keyPEM, keyPEM)
+}
+
+// Manager holds the certificates used to connect to the other components of the cluster, i.e.
+// cluster-ssl-ca, cluster-ssl-cert and cluster-ssl-key, and reloads them when the files are rotated.
+// The TLS configs returned by the manager always use the latest certificates for new connections.
+// The clients which only accept the file paths, e.g. the gRPC client of TiKV, re-create their
+// connections when the generation of the certificates changes.
+type Manager struct {
+	security   tikvcfg.Security
+	current    atomic.Value // *certificates
+	generation uint64
+}
+
+var (
+	managersMu sync.Mutex
+	managers   = make(map[[3]string]*Manager)
+)
+
+// GetManager returns the manager of the cluster certificates in the security config. The managers
+// are shared by the configs with the same certificate files, so they are reloaded only once.
+func GetManager(security tikvcfg.Security) (*Manager, error) {
+	key := [3]string{security.ClusterSSLCA, security.ClusterSSLCert, security.ClusterSSLKey}
+	managersMu.Lock()
+	defer managersMu.Unlock()
+	if m, ok := managers[key]; ok {
+		return m, nil
+	}
+	m := &Manager{security: security}
+	if m.Enabled() {
+		if _, err := m.Reload(); err != nil {
+			return nil, err
+		}
+	}
+	managers[key] = m
+	return m, nil
+}
+
+// Global returns the manager of the cluster certificates in the global config.
+func Global() (*Manager, error) {
+	return GetManager(config.GetGlobalConfig().Security.ClusterSecurity())
+}
+
+// Enabled indicates whether TLS is enabled for the connections between the components.
+func (m *Manager) Enabled() bool {
+	return len(m.security.ClusterSSLCA) != 0
+}
+
+func (m *Manager) load() *certificates {
+	c, _ := m.current.Load().(*certificates)
+	return c
+}
+
+// Reload reads the certificate files and swaps in the new certificates if the files are changed.
+// The current certificates are kept if the new ones are invalid, e.g. the certificate has been
+// rotated but the key has not.
+func (m *Manager) Reload() (changed bool, err error) {
+	defer func() {
+		if changed || err != nil {
+			metrics.ClusterTLSReloadCounter.WithLabelValues(metrics.RetLabel(err)).Inc()
+		}
+	}()
+	caPEM, err := os.ReadFile(m.security.ClusterSSLCA)
+	if err != nil {
+		return false, errors.Errorf("could not read ca certificate: %s", err)
+	}
+	var certPEM, keyPEM []byte
+	if len(m.security.ClusterSSLCert) != 0 && len(m.security.ClusterSSLKey) != 0 {
+		if certPEM, err = os.ReadFile(m.security.ClusterSSLCert); err != nil {
+			return false, errors.Errorf("could not read client certificate: %s", err)
+		}
+		if keyPEM, err = os.ReadFile(m.security.ClusterSSLKey); err != nil {
+			return false, errors.Errorf("could not read client key: %s", err)
+		}
+	}
+	if c := m.load(); c != nil && c.equal(caPEM, certPEM, keyPEM) {
+		return false, nil
+	}
+	c, err := newCertificates(caPEM, certPEM, keyPEM)
+	if err != nil {
+		return false, err
+	}
+	m.current.Store(c)
+	atomic.AddUint64(&m.generation, 1)
+	metrics.ClusterTLSCertExpireTime.WithLabelValues(certTypeCA).Set(float64(c.caNotAfter.Unix()))
+	if c.cert != nil {
+		metrics.ClusterTLSCertExpireTime.WithLabelValues(certTypeCert).Set(float64(c.certNotAfter.Unix()))
+	}
+	return true, nil
+}
+
+// Watch reloads the certificates every interval until the context is done.
+func (m *Manager) Watch(ctx context.Context, interval time.Duration) {
+	if !m.Enabled() {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		changed, err := m.Reload()
+		if err != nil {
+			logutil.BgLogger().Warn("reload cluster TLS certificates failed", zap.Error(err))
+		} else if changed {
+			c := m.load()
+			logutil.BgLogger().Info("cluster TLS certificates are reloaded",
+				zap.Time("ca-expire-time", c.caNotAfter), zap.Time("cert-expire-time", c.certNotAfter))
+		}
+	}
+}
+
+// NotAfter returns the expiration time of the CA and the certificate, the latter is zero if it is not set.
+func (m *Manager) NotAfter() (ca time.Time, cert time.Time) {
+	if c := m.load(); c != nil {
+		return c.caNotAfter, c.certNotAfter
+	}
+	return
+}
+
+// Generation returns the number of times the certificates are loaded.
+func (m *Manager) Generation() uint64 {
+	return atomic.LoadUint64(&m.generation)
+}
+
+// ClientTLSConfig returns the TLS config to connect to the other components, it's nil if TLS is disabled.
+func (m *Manager) ClientTLSConfig() *tls.Config {
+	if !m.Enabled() {
+		return nil
+	}
+	return &tls.Config{
+		// RootCAs can't be changed once the config is in use, so the peer certificates are
+		// verified with the latest CA by VerifyConnection instead.
+		InsecureSkipVerify:   true, // #nosec G402
+		VerifyConnection:     m.verifyConnection,
+		GetClientCertificate: m.getClientCertificate,
+	}
+}
+
+// GRPCDialer returns the dialer for the gRPC clients which can't take the TLS config, e.g. the PD client.
+// The dialer does the TLS handshake with the latest certificates, so the clients must be created without
+// transport security. It's nil if TLS is disabled.
+func (m *Manager) GRPCDialer() func(ctx context.Context, addr string) (net.Conn, error) {
+	tlsConfig := m.ClientTLSConfig()
+	if tlsConfig == nil {
+		return nil
+	}
+	// HTTP/2 must be negotiated since the servers may serve HTTP/1.1 on the same port, e.g. PD.
+	tlsConfig.NextProtos = []string{"h2"}
+	dialer := &tls.Dialer{Config: tlsConfig}
+	return func(ctx context.Context, addr string) (net.Conn, error) {
+		return dialer.DialContext(ctx, "tcp", addr)
+	}
+}
+
+// ServerTLSConfig returns the TLS config to accept the connections from the other components, it's nil
+// if TLS is disabled. The fields set by the caller are kept, while the certificate and the CAs are
+// replaced with the latest ones for each connection.
+func (m *Manager) ServerTLSConfig() *tls.Config {
+	if !m.Enabled() {
+		return nil
+	}
+	tlsConfig := &tls.Config{}
+	tlsConfig.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		c := m.load()
+		connConfig := tlsConfig.Clone()
+		connConfig.GetConfigForClient = nil
+		connConfig.RootCAs = c.caPool
+		connConfig.ClientCAs = c.caPool
+		if c.cert != nil {
+			connConfig.Certificates = []tls.Certificate{*c.cert}
+		}
+		return connConfig, nil
+	}
+	return tlsConfig
+}
+
+func (m *Manager) getClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	if c := m.load(); c.cert != nil {
+		return c.cert, nil
+	}
+	// No certificate is sent to the server.
+	return &tls.Certificate{}, nil
+}
+
+func (m *Manager) verifyConnection(cs tls.ConnectionState) error {
+	if len(cs.PeerCertificates) == 0 {
+		return errors.New("no certificate is provided by the server")
+	}
+	opts := x509.VerifyOptions{
+		Roots:         m.load().caPool,
+		DNSName:       cs.ServerName,
+		Intermediates: x509.NewCertPool(),
+	}
+	for _, cert := range cs.PeerCertificates[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	_, err := cs.PeerCertificates[0].Verify(opts)
+	return err
+}
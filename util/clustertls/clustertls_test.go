@@ -0,0 +1,258 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clustertls
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	tikvcfg "github.com/tikv/client-go/v2/config"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+func generateCert(t *testing.T, serial int64, notAfter time.Time, parent *testCert) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "tidb"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	signer, signerKey := template, key
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+	} else {
+		template.IPAddresses = []net.IP{net.ParseIP("127.0.0.1")}
+		template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return &testCert{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+func writeCerts(t *testing.T, security tikvcfg.Security, ca, cert *testCert) {
+	require.NoError(t, os.WriteFile(security.ClusterSSLCA, ca.certPEM, 0600))
+	require.NoError(t, os.WriteFile(security.ClusterSSLCert, cert.certPEM, 0600))
+	require.NoError(t, os.WriteFile(security.ClusterSSLKey, cert.keyPEM, 0600))
+}
+
+// handshake connects to a TLS server with the configs and returns the certificate used by the server.
+func handshake(t *testing.T, serverConfig, clientConfig *tls.Config) (*x509.Certificate, error) {
+	l, err := tls.Listen("tcp", "127.0.0.1:0", serverConfig)
+	require.NoError(t, err)
+	defer l.Close()
+	serverErr := make(chan error, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err == nil {
+			err = conn.(*tls.Conn).Handshake()
+			conn.Close()
+		}
+		serverErr <- err
+	}()
+	conn, err := tls.Dial("tcp", l.Addr().String(), clientConfig)
+	if err != nil {
+		<-serverErr
+		return nil, err
+	}
+	defer conn.Close()
+	if err = <-serverErr; err != nil {
+		return nil, err
+	}
+	return conn.ConnectionState().PeerCertificates[0], nil
+}
+
+func TestManager(t *testing.T) {
+	dir := t.TempDir()
+	security := tikvcfg.Security{
+		ClusterSSLCA:   filepath.Join(dir, "ca.pem"),
+		ClusterSSLCert: filepath.Join(dir, "cert.pem"),
+		ClusterSSLKey:  filepath.Join(dir, "key.pem"),
+	}
+	ca1 := generateCert(t, 1, time.Now().Add(48*time.Hour), nil)
+	cert1 := generateCert(t, 2, time.Now().Add(24*time.Hour), ca1)
+	writeCerts(t, security, ca1, cert1)
+
+	m, err := GetManager(security)
+	require.NoError(t, err)
+	require.True(t, m.Enabled())
+	m2, err := GetManager(security)
+	require.NoError(t, err)
+	require.Same(t, m, m2)
+	caNotAfter, certNotAfter := m.NotAfter()
+	require.True(t, caNotAfter.Equal(ca1.cert.NotAfter))
+	require.True(t, certNotAfter.Equal(cert1.cert.NotAfter))
+
+	serverConfig, clientConfig := m.ServerTLSConfig(), m.ClientTLSConfig()
+	serverConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	peer, err := handshake(t, serverConfig, clientConfig)
+	require.NoError(t, err)
+	require.Equal(t, cert1.cert.SerialNumber, peer.SerialNumber)
+
+	// nothing is changed if the files are not rotated
+	changed, err := m.Reload()
+	require.NoError(t, err)
+	require.False(t, changed)
+
+	// the certificate is rotated but the key is not, the old certificates are kept
+	ca2 := generateCert(t, 3, time.Now().Add(96*time.Hour), nil)
+	cert2 := generateCert(t, 4, time.Now().Add(72*time.Hour), ca2)
+	require.NoError(t, os.WriteFile(security.ClusterSSLCert, cert2.certPEM, 0600))
+	_, err = m.Reload()
+	require.Error(t, err)
+	peer, err = handshake(t, serverConfig, clientConfig)
+	require.NoError(t, err)
+	require.Equal(t, cert1.cert.SerialNumber, peer.SerialNumber)
+
+	// the new connections use the rotated certificates with the configs created before
+	writeCerts(t, security, ca2, cert2)
+	changed, err = m.Reload()
+	require.NoError(t, err)
+	require.True(t, changed)
+	caNotAfter, certNotAfter = m.NotAfter()
+	require.True(t, caNotAfter.Equal(ca2.cert.NotAfter))
+	require.True(t, certNotAfter.Equal(cert2.cert.NotAfter))
+	peer, err = handshake(t, serverConfig, clientConfig)
+	require.NoError(t, err)
+	require.Equal(t, cert2.cert.SerialNumber, peer.SerialNumber)
+
+	// the certificate signed by the old CA is not trusted anymore
+	oldServerConfig := &tls.Config{Certificates: []tls.Certificate{{
+		Certificate: [][]byte{cert1.cert.Raw},
+		PrivateKey:  cert1.key,
+	}}}
+	_, err = handshake(t, oldServerConfig, clientConfig)
+	require.Error(t, err)
+}
+
+// startGRPCServer starts a gRPC server on the address with the certificate, the address is random if it's empty.
+func startGRPCServer(t *testing.T, addr string, cert *testCert) (*grpc.Server, string) {
+	if addr == "" {
+		addr = "127.0.0.1:0"
+	}
+	l, err := net.Listen("tcp", addr)
+	require.NoError(t, err)
+	server := grpc.NewServer(grpc.Creds(credentials.NewTLS(&tls.Config{Certificates: []tls.Certificate{{
+		Certificate: [][]byte{cert.cert.Raw},
+		PrivateKey:  cert.key,
+	}}})))
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go func() {
+		_ = server.Serve(l)
+	}()
+	return server, l.Addr().String()
+}
+
+func checkHealth(conn *grpc.ClientConn) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	_, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	return err
+}
+
+func TestGRPCDialer(t *testing.T) {
+	dir := t.TempDir()
+	security := tikvcfg.Security{
+		ClusterSSLCA:   filepath.Join(dir, "ca.pem"),
+		ClusterSSLCert: filepath.Join(dir, "cert.pem"),
+		ClusterSSLKey:  filepath.Join(dir, "key.pem"),
+	}
+	ca1 := generateCert(t, 1, time.Now().Add(48*time.Hour), nil)
+	cert1 := generateCert(t, 2, time.Now().Add(24*time.Hour), ca1)
+	writeCerts(t, security, ca1, cert1)
+	m, err := GetManager(security)
+	require.NoError(t, err)
+
+	server, addr := startGRPCServer(t, "", cert1)
+	conn, err := grpc.Dial(addr, grpc.WithInsecure(), grpc.WithContextDialer(m.GRPCDialer()))
+	require.NoError(t, err)
+	defer conn.Close()
+	require.NoError(t, checkHealth(conn))
+	// the connection which reads the CA file only once, e.g. the one created by the PD client from the file paths
+	staticConfig, err := security.ToTLSConfig()
+	require.NoError(t, err)
+	staticConn, err := grpc.Dial(addr, grpc.WithTransportCredentials(credentials.NewTLS(staticConfig)))
+	require.NoError(t, err)
+	defer staticConn.Close()
+	require.NoError(t, checkHealth(staticConn))
+
+	// the CA is rotated and the server restarts with the certificate signed by the new CA
+	ca2 := generateCert(t, 3, time.Now().Add(96*time.Hour), nil)
+	cert2 := generateCert(t, 4, time.Now().Add(72*time.Hour), ca2)
+	writeCerts(t, security, ca2, cert2)
+	generation := m.Generation()
+	changed, err := m.Reload()
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.Equal(t, generation+1, m.Generation())
+	server.Stop()
+	server, _ = startGRPCServer(t, addr, cert2)
+	defer server.Stop()
+
+	// the connection reconnects with the new CA
+	require.Eventually(t, func() bool {
+		return checkHealth(conn) == nil
+	}, 10*time.Second, 100*time.Millisecond)
+	require.Error(t, checkHealth(staticConn))
+}
+
+func TestManagerDisabled(t *testing.T) {
+	m, err := GetManager(tikvcfg.Security{})
+	require.NoError(t, err)
+	require.False(t, m.Enabled())
+	require.Nil(t, m.ClientTLSConfig())
+	require.Nil(t, m.ServerTLSConfig())
+	require.Nil(t, m.GRPCDialer())
+	changed, err := m.Reload()
+	require.Error(t, err)
+	require.False(t, changed)
+}
@@ -57,6 +57,7 @@ func (eqh *Handle) Run() {
 	defer ticker.Stop()
 	sm := eqh.sm.Load().(util.SessionManager)
 	record := &memoryUsageAlarm{}
+	killer := newMemoryKiller()
 	for {
 		select {
 		case <-ticker.C:
@@ -81,6 +82,7 @@ func (eqh *Handle) Run() {
 			if record.err == nil {
 				record.alarm4ExcessiveMemUsage(sm)
 			}
+			killer.checkMemoryQuotas(sm, processInfo)
 		case <-eqh.exitCh:
 			return
 		}
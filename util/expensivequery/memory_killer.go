@@ -0,0 +1,214 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expensivequery
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pingcap/tidb/parser"
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/logutil"
+	"github.com/pingcap/tidb/util/memory"
+	"go.uber.org/zap"
+)
+
+// The memory quotas enforced by the memory killer.
+const (
+	// MemoryQuotaServer is tidb_server_memory_limit, which limits the memory tracked in the instance.
+	MemoryQuotaServer = "server"
+	// MemoryQuotaSession is tidb_mem_quota_session, which limits the memory used by a session.
+	MemoryQuotaSession = "session"
+	// MemoryQuotaUser is MAX_MEMORY_QUOTA of an account, which limits the memory used by the sessions of the account.
+	MemoryQuotaUser = "user"
+)
+
+// maxMemoryKillRecords is the number of the latest kill records kept in the instance.
+const maxMemoryKillRecords = 256
+
+// MemoryKillRecord describes a statement killed by the memory killer.
+type MemoryKillRecord struct {
+	Time time.Time
+	// Quota is the kind of the exceeded memory quota, QuotaBytes is its value and UsedBytes is the memory
+	// used by the instance, the session or the account when the statement is killed.
+	Quota      string
+	QuotaBytes int64
+	UsedBytes  int64
+	// StmtBytes is the memory used by the killed statement.
+	StmtBytes int64
+	ConnID    uint64
+	User      string
+	Host      string
+	DB        string
+	Digest    string
+	SQL       string
+}
+
+var memoryKillRecords struct {
+	sync.Mutex
+	records []MemoryKillRecord
+}
+
+func addMemoryKillRecord(record MemoryKillRecord) {
+	memoryKillRecords.Lock()
+	defer memoryKillRecords.Unlock()
+	if len(memoryKillRecords.records) >= maxMemoryKillRecords {
+		memoryKillRecords.records = memoryKillRecords.records[1:]
+	}
+	memoryKillRecords.records = append(memoryKillRecords.records, record)
+}
+
+// MemoryKillRecords returns the latest statements killed by the memory killer in the instance, the oldest first.
+func MemoryKillRecords() []MemoryKillRecord {
+	memoryKillRecords.Lock()
+	defer memoryKillRecords.Unlock()
+	return append([]MemoryKillRecord(nil), memoryKillRecords.records...)
+}
+
+// sessionMemory is the memory usage of a session.
+type sessionMemory struct {
+	info *util.ProcessInfo
+	// stmtBytes is the memory used by the running statement, sessionBytes also includes the memory which
+	// lives across statements in the session.
+	stmtBytes    int64
+	sessionBytes int64
+	running      bool
+}
+
+// memoryKiller kills the running statements to keep the memory usage under the memory quotas. The victim of the
+// server quota or an account quota is the statement using the most memory in the instance or the account.
+type memoryKiller struct {
+	// victims are the killed statements which haven't finished, keyed by the quota they exceeded. No more
+	// statements are killed for a quota until its victim finishes and releases the memory.
+	victims map[string]*stmtctx.StatementContext
+}
+
+func newMemoryKiller() *memoryKiller {
+	return &memoryKiller{victims: make(map[string]*stmtctx.StatementContext)}
+}
+
+func collectSessionMemory(processInfo map[uint64]*util.ProcessInfo) []sessionMemory {
+	sessions := make([]sessionMemory, 0, len(processInfo))
+	for _, info := range processInfo {
+		s := sessionMemory{info: info}
+		if info.SessionMemTracker != nil {
+			s.sessionBytes = info.SessionMemTracker.BytesConsumed()
+		}
+		if info.Command != mysql.ComSleep && info.StmtCtx != nil && info.StmtCtx.MemTracker != nil {
+			s.running = true
+			s.stmtBytes = info.StmtCtx.MemTracker.BytesConsumed()
+			s.sessionBytes += s.stmtBytes
+		}
+		sessions = append(sessions, s)
+	}
+	return sessions
+}
+
+// checkMemoryQuotas kills the statements for the exceeded memory quotas.
+func (k *memoryKiller) checkMemoryQuotas(sm util.SessionManager, processInfo map[uint64]*util.ProcessInfo) {
+	sessions := collectSessionMemory(processInfo)
+	running := make(map[*stmtctx.StatementContext]struct{}, len(sessions))
+	for _, s := range sessions {
+		if s.running {
+			running[s.info.StmtCtx] = struct{}{}
+		}
+	}
+	for key, stmtCtx := range k.victims {
+		if _, ok := running[stmtCtx]; !ok {
+			delete(k.victims, key)
+		}
+	}
+
+	type accountMemory struct {
+		quota  int64
+		used   int64
+		victim *sessionMemory
+	}
+	accounts := make(map[string]*accountMemory)
+	var serverVictim *sessionMemory
+	for i := range sessions {
+		s := &sessions[i]
+		if s.running && s.info.MemQuotaSession > 0 && s.sessionBytes > s.info.MemQuotaSession {
+			k.kill(sm, "session:"+strconv.FormatUint(s.info.ID, 10), MemoryQuotaSession, s.info.MemQuotaSession, s.sessionBytes, s)
+		}
+		if len(s.info.Account) > 0 {
+			account, ok := accounts[s.info.Account]
+			if !ok {
+				account = &accountMemory{}
+				accounts[s.info.Account] = account
+			}
+			account.used += s.sessionBytes
+			// The quota is only looked up for the running statements.
+			if s.info.MemQuotaUser > account.quota {
+				account.quota = s.info.MemQuotaUser
+			}
+			if s.running && (account.victim == nil || s.stmtBytes > account.victim.stmtBytes) {
+				account.victim = s
+			}
+		}
+		if s.running && (serverVictim == nil || s.stmtBytes > serverVictim.stmtBytes) {
+			serverVictim = s
+		}
+	}
+	for name, account := range accounts {
+		if account.quota > 0 && account.used > account.quota && account.victim != nil {
+			k.kill(sm, "user:"+name, MemoryQuotaUser, account.quota, account.used, account.victim)
+		}
+	}
+	limit := variable.ServerMemoryLimit.Load()
+	used := memory.GlobalMemoryUsageTracker.BytesConsumed() + memory.GlobalSessionMemoryUsageTracker.BytesConsumed()
+	if limit > 0 && used > limit && serverVictim != nil {
+		k.kill(sm, MemoryQuotaServer, MemoryQuotaServer, limit, used, serverVictim)
+	}
+}
+
+// kill kills the running statement of the session unless the quota already has a victim which hasn't finished.
+func (k *memoryKiller) kill(sm util.SessionManager, key, quota string, quotaBytes, usedBytes int64, s *sessionMemory) {
+	if _, ok := k.victims[key]; ok {
+		return
+	}
+	info := s.info
+	// The statement may have been killed for another quota.
+	if len(info.StmtCtx.MemoryKilled.Load()) > 0 {
+		return
+	}
+	info.StmtCtx.MemoryKilled.Store(quota)
+	k.victims[key] = info.StmtCtx
+	logutil.BgLogger().Warn("kill the statement for exceeding the memory quota", zap.String("quota", quota),
+		zap.Int64("quota_bytes", quotaBytes), zap.Int64("used_bytes", usedBytes), zap.Int64("stmt_bytes", s.stmtBytes),
+		zap.Uint64("conn_id", info.ID), zap.String("user", info.User), zap.String("digest", info.Digest))
+	sm.Kill(info.ID, true)
+	sql := info.Info
+	if info.RedactSQL {
+		sql = parser.Normalize(sql)
+	}
+	addMemoryKillRecord(MemoryKillRecord{
+		Time:       time.Now(),
+		Quota:      quota,
+		QuotaBytes: quotaBytes,
+		UsedBytes:  usedBytes,
+		StmtBytes:  s.stmtBytes,
+		ConnID:     info.ID,
+		User:       info.User,
+		Host:       info.Host,
+		DB:         info.DB,
+		Digest:     info.Digest,
+		SQL:        sql,
+	})
+}
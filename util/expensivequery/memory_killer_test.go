@@ -0,0 +1,157 @@
+// Copyright 2021 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expensivequery
+
+import (
+	"crypto/tls"
+	"testing"
+
+	"github.com/pingcap/tidb/parser/mysql"
+	"github.com/pingcap/tidb/session/txninfo"
+	"github.com/pingcap/tidb/sessionctx/stmtctx"
+	"github.com/pingcap/tidb/sessionctx/variable"
+	"github.com/pingcap/tidb/util"
+	"github.com/pingcap/tidb/util/memory"
+	"github.com/stretchr/testify/require"
+)
+
+type mockSessionManager struct {
+	processInfo map[uint64]*util.ProcessInfo
+	killed      []uint64
+}
+
+func (sm *mockSessionManager) ShowProcessList() map[uint64]*util.ProcessInfo { return sm.processInfo }
+
+func (sm *mockSessionManager) ShowTxnList() []*txninfo.TxnInfo { return nil }
+
+func (sm *mockSessionManager) GetProcessInfo(id uint64) (*util.ProcessInfo, bool) {
+	info, ok := sm.processInfo[id]
+	return info, ok
+}
+
+func (sm *mockSessionManager) Kill(connectionID uint64, query bool) {
+	sm.killed = append(sm.killed, connectionID)
+}
+
+func (sm *mockSessionManager) KillAllConnections() {}
+
+func (sm *mockSessionManager) UpdateTLSConfig(cfg *tls.Config) {}
+
+func (sm *mockSessionManager) ServerID() uint64 { return 1 }
+
+func (sm *mockSessionManager) addSession(id uint64, account string, stmtBytes, sessionBytes int64) *util.ProcessInfo {
+	stmtTracker := memory.NewTracker(memory.LabelForSQLText, -1)
+	stmtTracker.Consume(stmtBytes)
+	sessionTracker := memory.NewTracker(memory.LabelForSession, -1)
+	sessionTracker.Consume(sessionBytes)
+	info := &util.ProcessInfo{
+		ID:                id,
+		User:              account,
+		Account:           account + "@%",
+		Info:              "select 1",
+		Command:           mysql.ComQuery,
+		StmtCtx:           &stmtctx.StatementContext{MemTracker: stmtTracker},
+		SessionMemTracker: sessionTracker,
+	}
+	sm.processInfo[id] = info
+	return info
+}
+
+func TestMemoryKiller(t *testing.T) {
+	sm := &mockSessionManager{processInfo: make(map[uint64]*util.ProcessInfo)}
+	killer := newMemoryKiller()
+	s1 := sm.addSession(1, "u1", 100, 50)
+	s2 := sm.addSession(2, "u1", 300, 0)
+	s3 := sm.addSession(3, "u2", 200, 0)
+	killer.checkMemoryQuotas(sm, sm.processInfo)
+	require.Empty(t, sm.killed)
+
+	// The session quota counts the memory of the running statement and the memory which lives across statements.
+	s1.MemQuotaSession = 120
+	killer.checkMemoryQuotas(sm, sm.processInfo)
+	require.Equal(t, []uint64{1}, sm.killed)
+	require.Equal(t, MemoryQuotaSession, s1.StmtCtx.MemoryKilled.Load())
+	// The statement is killed only once.
+	killer.checkMemoryQuotas(sm, sm.processInfo)
+	require.Equal(t, []uint64{1}, sm.killed)
+	s1.Command = mysql.ComSleep
+	s1.MemQuotaSession = 0
+
+	// The statement using the most memory of the account is killed, the sleeping sessions still count the memory
+	// which lives across statements.
+	s2.MemQuotaUser = 320
+	killer.checkMemoryQuotas(sm, sm.processInfo)
+	require.Equal(t, []uint64{1, 2}, sm.killed)
+	require.Equal(t, MemoryQuotaUser, s2.StmtCtx.MemoryKilled.Load())
+	// No more statements are killed until the victim finishes.
+	s4 := sm.addSession(4, "u1", 400, 0)
+	killer.checkMemoryQuotas(sm, sm.processInfo)
+	require.Equal(t, []uint64{1, 2}, sm.killed)
+	delete(sm.processInfo, 2)
+	s4.MemQuotaUser = 320
+	killer.checkMemoryQuotas(sm, sm.processInfo)
+	require.Equal(t, []uint64{1, 2, 4}, sm.killed)
+	delete(sm.processInfo, 4)
+
+	// The statement using the most memory in the instance is killed for the server memory limit.
+	origLimit := variable.ServerMemoryLimit.Load()
+	defer variable.ServerMemoryLimit.Store(origLimit)
+	tracker := memory.NewTracker(memory.LabelForSQLText, -1)
+	tracker.AttachToGlobalTracker(memory.GlobalMemoryUsageTracker)
+	defer tracker.DetachFromGlobalTracker()
+	tracker.Consume(1000)
+	variable.ServerMemoryLimit.Store(memory.GlobalMemoryUsageTracker.BytesConsumed() - 1)
+	killer.checkMemoryQuotas(sm, sm.processInfo)
+	require.Equal(t, []uint64{1, 2, 4, 3}, sm.killed)
+	require.Equal(t, MemoryQuotaServer, s3.StmtCtx.MemoryKilled.Load())
+
+	records := MemoryKillRecords()
+	require.Len(t, records, 4)
+	require.Equal(t, MemoryQuotaSession, records[0].Quota)
+	require.Equal(t, int64(120), records[0].QuotaBytes)
+	require.Equal(t, int64(150), records[0].UsedBytes)
+	require.Equal(t, int64(100), records[0].StmtBytes)
+	require.Equal(t, MemoryQuotaUser, records[1].Quota)
+	require.Equal(t, int64(350), records[1].UsedBytes)
+	require.Equal(t, uint64(4), records[2].ConnID)
+	require.Equal(t, MemoryQuotaServer, records[3].Quota)
+	require.Equal(t, "u2", records[3].User)
+	require.Equal(t, "select 1", records[3].SQL)
+}
+
+func TestMemoryKillerSessionMemory(t *testing.T) {
+	sm := &mockSessionManager{processInfo: make(map[uint64]*util.ProcessInfo)}
+	killer := newMemoryKiller()
+	s1 := sm.addSession(1, "u1", 100, 0)
+	s2 := sm.addSession(2, "u2", 10, 0)
+	s2.Command = mysql.ComSleep
+	origLimit := variable.ServerMemoryLimit.Load()
+	defer variable.ServerMemoryLimit.Store(origLimit)
+	variable.ServerMemoryLimit.Store(memory.GlobalMemoryUsageTracker.BytesConsumed() + memory.GlobalSessionMemoryUsageTracker.BytesConsumed() + 1000)
+	killer.checkMemoryQuotas(sm, sm.processInfo)
+	require.Empty(t, sm.killed)
+
+	// The memory which lives across statements in the idle session pushes the server over the limit, it doesn't
+	// count in GlobalMemoryUsageTracker, whose action would fail the statement consuming the memory, but the
+	// memory killer kills the statement using the most memory.
+	globalBytes := memory.GlobalMemoryUsageTracker.BytesConsumed()
+	s2.SessionMemTracker.AttachToGlobalTracker(memory.GlobalSessionMemoryUsageTracker)
+	defer s2.SessionMemTracker.DetachFromGlobalTracker()
+	s2.SessionMemTracker.Consume(2000)
+	require.Equal(t, globalBytes, memory.GlobalMemoryUsageTracker.BytesConsumed())
+	killer.checkMemoryQuotas(sm, sm.processInfo)
+	require.Equal(t, []uint64{1}, sm.killed)
+	require.Equal(t, MemoryQuotaServer, s1.StmtCtx.MemoryKilled.Load())
+}
@@ -125,6 +125,17 @@ func NewGlobalTracker(label int, bytesLimit int64) *Tracker {
 	return t
 }
 
+var (
+	// GlobalMemoryUsageTracker is the root of the memory trackers of the running statements and the plan cache
+	// in a TiDB instance.
+	GlobalMemoryUsageTracker = NewGlobalTracker(LabelForGlobalMemory, -1)
+	// GlobalSessionMemoryUsageTracker is the root of the trackers of the memory which lives across statements
+	// in the sessions. It's not under GlobalMemoryUsageTracker, so the memory of the sessions doesn't trigger the
+	// action of GlobalMemoryUsageTracker in the statement which happens to consume the memory, but only counts
+	// in the server memory limit enforced by the memory killer.
+	GlobalSessionMemoryUsageTracker = NewGlobalTracker(LabelForGlobalSessionMemory, -1)
+)
+
 // CheckBytesLimit check whether the bytes limit of the tracker is equal to a value.
 // Only used in test.
 func (t *Tracker) CheckBytesLimit(val int64) bool {
@@ -552,4 +563,10 @@ const (
 	LabelForSimpleTask int = -18
 	// LabelForCTEStorage represents the label of CTE storage
 	LabelForCTEStorage int = -19
+	// LabelForSession represents the label of a session
+	LabelForSession int = -20
+	// LabelForCursorFetch represents the label of the result set of a server-side cursor
+	LabelForCursorFetch int = -21
+	// LabelForGlobalSessionMemory represents the label of the Global Session Memory
+	LabelForGlobalSessionMemory int = -22
 )
@@ -41,6 +41,7 @@ import (
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/parser/mysql"
 	"github.com/pingcap/tidb/parser/terror"
+	"github.com/pingcap/tidb/util/clustertls"
 	"github.com/pingcap/tidb/util/collate"
 	"github.com/pingcap/tidb/util/logutil"
 	"github.com/pingcap/tipb/go-tipb"
@@ -448,7 +449,7 @@ func LoadTLSCertificates(ca, key, cert string, autoTLS bool, rsaKeySize int) (tl
 		tempStoragePath := config.GetGlobalConfig().TempStoragePath
 		cert = filepath.Join(tempStoragePath, "/cert.pem")
 		key = filepath.Join(tempStoragePath, "/key.pem")
-		err = createTLSCertificates(cert, key, rsaKeySize)
+		err = CreateTLSCertificates(cert, key, rsaKeySize)
 		if err != nil {
 			logutil.BgLogger().Warn("TLS Certificate creation failed", zap.Error(err))
 			return
@@ -565,11 +566,12 @@ func InternalHTTPSchema() string {
 }
 
 func initInternalClient() {
-	clusterSecurity := config.GetGlobalConfig().Security.ClusterSecurity()
-	tlsCfg, err := clusterSecurity.ToTLSConfig()
+	// The TLS config always uses the latest cluster certificates for new connections.
+	m, err := clustertls.Global()
 	if err != nil {
 		logutil.BgLogger().Fatal("could not load cluster ssl", zap.Error(err))
 	}
+	tlsCfg := m.ClientTLSConfig()
 	if tlsCfg == nil {
 		internalHTTPSchema = "http"
 		internalHTTPClient = http.DefaultClient
@@ -612,7 +614,8 @@ func QueryStrForLog(query string) string {
 	return query
 }
 
-func createTLSCertificates(certpath string, keypath string, rsaKeySize int) error {
+// CreateTLSCertificates creates a self-signed certificate and its RSA private key in the files.
+func CreateTLSCertificates(certpath string, keypath string, rsaKeySize int) error {
 	privkey, err := rsa.GenerateKey(rand.Reader, rsaKeySize)
 	if err != nil {
 		return err
@@ -27,6 +27,7 @@ import (
 	"github.com/pingcap/tidb/parser/ast"
 	"github.com/pingcap/tidb/parser/model"
 	"github.com/pingcap/tidb/sessionctx"
+	"github.com/pingcap/tidb/sessionctx/sessionstates"
 	"github.com/pingcap/tidb/sessionctx/variable"
 	"github.com/pingcap/tidb/util"
 	"github.com/pingcap/tidb/util/disk"
@@ -337,6 +338,16 @@ func (c *Context) HasLockedTables() bool {
 func (c *Context) PrepareTSFuture(ctx context.Context) {
 }
 
+// EncodeSessionStates implements the sessionctx.Context interface.
+func (c *Context) EncodeSessionStates(ctx context.Context, sctx sessionctx.Context, sessionStates *sessionstates.SessionStates) error {
+	return errors.Errorf("Not Supported.")
+}
+
+// DecodeSessionStates implements the sessionctx.Context interface.
+func (c *Context) DecodeSessionStates(ctx context.Context, sctx sessionctx.Context, sessionStates *sessionstates.SessionStates) error {
+	return errors.Errorf("Not Supported.")
+}
+
 // Close implements the sessionctx.Context interface.
 func (c *Context) Close() {
 }
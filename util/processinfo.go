@@ -18,6 +18,7 @@ import (
 	"crypto/tls"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"sync/atomic"
 	"time"
@@ -26,6 +27,7 @@ import (
 	"github.com/pingcap/tidb/session/txninfo"
 	"github.com/pingcap/tidb/sessionctx/stmtctx"
 	"github.com/pingcap/tidb/util/execdetails"
+	"github.com/pingcap/tidb/util/memory"
 	"github.com/tikv/client-go/v2/oracle"
 )
 
@@ -48,6 +50,15 @@ type ProcessInfo struct {
 	// MaxExecutionTime is the timeout for select statement, in milliseconds.
 	// If the query takes too long, kill it.
 	MaxExecutionTime uint64
+	// ConnectAttrs is the connection attributes sent by the client in the handshake.
+	ConnectAttrs map[string]string
+	// SessionMemTracker tracks the memory which lives across statements in the session.
+	SessionMemTracker *memory.Tracker
+	// MemQuotaSession is tidb_mem_quota_session of the session, 0 means no limit.
+	MemQuotaSession int64
+	// Account is the logged-in account, whose sessions share the memory quota MemQuotaUser set by MAX_MEMORY_QUOTA.
+	Account      string
+	MemQuotaUser int64
 
 	State                     uint16
 	Command                   byte
@@ -112,6 +123,21 @@ func (pi *ProcessInfo) ToRow(tz *time.Location) []interface{} {
 	return append(pi.ToRowForShow(true), pi.Digest, bytesConsumed, diskConsumed, pi.txnStartTs(tz))
 }
 
+// ToConnectAttrsRows returns the rows of "INFORMATION_SCHEMA.SESSION_CONNECT_ATTRS".
+// The attributes are ordered by name since the client order is not kept.
+func (pi *ProcessInfo) ToConnectAttrsRows() [][]interface{} {
+	names := make([]string, 0, len(pi.ConnectAttrs))
+	for name := range pi.ConnectAttrs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	rows := make([][]interface{}, 0, len(names))
+	for i, name := range names {
+		rows = append(rows, []interface{}{pi.ID, name, pi.ConnectAttrs[name], i + 1})
+	}
+	return rows
+}
+
 // ascServerStatus is a slice of all defined server status in ascending order.
 var ascServerStatus = []uint16{
 	mysql.ServerStatusInTrans,
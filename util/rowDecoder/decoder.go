@@ -165,9 +165,14 @@ func (rd *RowDecoder) DecodeTheExistedColumnMap(ctx sessionctx.Context, handle k
 		if err != nil {
 			return nil, err
 		}
+		rd.mutRow.SetValue(colInfo.Offset, val.GetValue())
+		// The non-public column is being added by another job, don't write its value into the row,
+		// otherwise its origin default value is overwritten.
+		if colInfo.State != model.StatePublic {
+			continue
+		}
 		// Fill the default value into map.
 		row[colInfo.ID] = val
-		rd.mutRow.SetValue(colInfo.Offset, val.GetValue())
 	}
 	// return the existed column map here.
 	return row, nil
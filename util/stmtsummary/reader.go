@@ -311,6 +311,8 @@ const (
 	PlanInBindingStr                = "PLAN_IN_BINDING"
 	QuerySampleTextStr              = "QUERY_SAMPLE_TEXT"
 	PrevSampleTextStr               = "PREV_SAMPLE_TEXT"
+	QuerySampleAttrsStr             = "QUERY_SAMPLE_ATTRIBUTES"
+	ConnSampleAttrsStr              = "CONN_SAMPLE_ATTRIBUTES"
 	PlanDigestStr                   = "PLAN_DIGEST"
 	PlanStr                         = "PLAN"
 )
@@ -590,6 +592,18 @@ var columnValueFactoryMap = map[string]columnValueFactory{
 	PrevSampleTextStr: func(ssElement *stmtSummaryByDigestElement, _ *stmtSummaryByDigest) interface{} {
 		return ssElement.prevSQL
 	},
+	QuerySampleAttrsStr: func(ssElement *stmtSummaryByDigestElement, _ *stmtSummaryByDigest) interface{} {
+		if len(ssElement.sampleQueryAttrs) == 0 {
+			return nil
+		}
+		return ssElement.sampleQueryAttrs
+	},
+	ConnSampleAttrsStr: func(ssElement *stmtSummaryByDigestElement, _ *stmtSummaryByDigest) interface{} {
+		if len(ssElement.sampleConnAttrs) == 0 {
+			return nil
+		}
+		return ssElement.sampleConnAttrs
+	},
 	PlanDigestStr: func(_ *stmtSummaryByDigestElement, ssbd *stmtSummaryByDigest) interface{} {
 		return ssbd.planDigest
 	},
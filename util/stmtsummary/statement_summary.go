@@ -17,6 +17,7 @@ package stmtsummary
 import (
 	"bytes"
 	"container/list"
+	"encoding/json"
 	"fmt"
 	"math"
 	"sort"
@@ -116,6 +117,9 @@ type stmtSummaryByDigestElement struct {
 	execCount   int64
 	sumErrors   int
 	sumWarnings int
+	// the attributes of the sampled statement in JSON
+	sampleQueryAttrs string
+	sampleConnAttrs  string
 	// latency
 	sumLatency        time.Duration
 	maxLatency        time.Duration
@@ -236,6 +240,8 @@ type StmtExecInfo struct {
 	ResultRows      int64
 	TiKVExecDetails util.ExecDetails
 	Prepared        bool
+	QueryAttributes map[string]string
+	ConnAttributes  map[string]string
 }
 
 // newStmtSummaryByDigestMap creates an empty stmtSummaryByDigestMap.
@@ -605,6 +611,8 @@ func newStmtSummaryByDigestElement(sei *StmtExecInfo, beginTime int64, intervalS
 		prepared:      sei.Prepared,
 		minResultRows: math.MaxInt64,
 	}
+	ssElement.sampleQueryAttrs = formatAttributes(sei.QueryAttributes)
+	ssElement.sampleConnAttrs = formatAttributes(sei.ConnAttributes)
 	ssElement.add(sei, intervalSeconds)
 	return ssElement
 }
@@ -835,6 +843,18 @@ func formatSQL(sql string) string {
 	return sql
 }
 
+// formatAttributes formats the query or connection attributes to a JSON string.
+func formatAttributes(attrs map[string]string) string {
+	if len(attrs) == 0 {
+		return ""
+	}
+	value, err := json.Marshal(attrs)
+	if err != nil {
+		return ""
+	}
+	return formatSQL(string(value))
+}
+
 // Format the backoffType map to a string or nil.
 func formatBackoffTypes(backoffMap map[string]int) interface{} {
 	type backoffStat struct {
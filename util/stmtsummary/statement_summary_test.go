@@ -1330,6 +1330,40 @@ func TestPrevSQL(t *testing.T) {
 	require.True(t, ok)
 }
 
+func TestSampleAttributes(t *testing.T) {
+	t.Parallel()
+	ssMap := newStmtSummaryByDigestMap()
+	now := time.Now().Unix()
+	// to disable expiration
+	ssMap.beginTimeForCurInterval = now + 60
+
+	stmtExecInfo1 := generateAnyExecInfo()
+	stmtExecInfo1.QueryAttributes = map[string]string{"request_id": "r1"}
+	stmtExecInfo1.ConnAttributes = map[string]string{"_client_name": "libmysql"}
+	ssMap.AddStatement(stmtExecInfo1)
+	// the attributes of the first statement are kept
+	stmtExecInfo2 := generateAnyExecInfo()
+	stmtExecInfo2.QueryAttributes = map[string]string{"request_id": "r2"}
+	ssMap.AddStatement(stmtExecInfo2)
+
+	cols := []*model.ColumnInfo{
+		{ID: 0, Name: model.NewCIStr(QuerySampleAttrsStr), Offset: 0},
+		{ID: 1, Name: model.NewCIStr(ConnSampleAttrsStr), Offset: 1},
+	}
+	reader := NewStmtSummaryReader(nil, true, cols, "")
+	reader.ssMap = ssMap
+	datums := reader.GetStmtSummaryCurrentRows()
+	require.Equal(t, 1, len(datums))
+	match(t, datums[0], `{"request_id":"r1"}`, `{"_client_name":"libmysql"}`)
+
+	ssMap.Clear()
+	ssMap.AddStatement(generateAnyExecInfo())
+	datums = reader.GetStmtSummaryCurrentRows()
+	require.Equal(t, 1, len(datums))
+	require.True(t, datums[0][0].IsNull())
+	require.True(t, datums[0][1].IsNull())
+}
+
 func TestEndTime(t *testing.T) {
 	t.Parallel()
 	ssMap := newStmtSummaryByDigestMap()